@@ -222,6 +222,9 @@ func beforeFunc(cx *conte.Xt) func(c *cli.Context) error {
 		if c.IsSet("nocheckpoints") {
 			*cx.Config.DisableCheckpoints = c.Bool("nocheckpoints")
 		}
+		if c.IsSet("assumevalid") {
+			*cx.Config.AssumeValid = c.String("assumevalid")
+		}
 		if c.IsSet("dbtype") {
 			*cx.Config.DbType = c.String("dbtype")
 		}
@@ -249,6 +252,12 @@ func beforeFunc(cx *conte.Xt) func(c *cli.Context) error {
 		if c.IsSet("maxorphantx") {
 			*cx.Config.MaxOrphanTxs = c.Int("maxorphantx")
 		}
+		if c.IsSet("maxmempool") {
+			*cx.Config.MaxMempool = c.Int("maxmempool")
+		}
+		if c.IsSet("rejectreplacement") {
+			*cx.Config.RejectReplacement = c.Bool("rejectreplacement")
+		}
 		if c.IsSet("generate") {
 			*cx.Config.Generate = c.Bool("generate")
 		}
@@ -277,12 +286,24 @@ func beforeFunc(cx *conte.Xt) func(c *cli.Context) error {
 		if c.IsSet("controller") {
 			*cx.Config.Controller = c.String("controller")
 		}
+		if c.IsSet("controllerconnect") {
+			*cx.Config.ControllerConnect = c.String("controllerconnect")
+		}
 		if c.IsSet("miningaddrs") {
 			*cx.Config.MiningAddrs = c.StringSlice("miningaddrs")
 		}
+		if c.IsSet("minerlistener") {
+			*cx.Config.MinerListener = c.String("minerlistener")
+		}
 		if c.IsSet("minerpass") {
 			*cx.Config.MinerPass = c.String("minerpass")
 		}
+		if c.IsSet("stratum") {
+			*cx.Config.Stratum = c.String("stratum")
+		}
+		if c.IsSet("stratumpass") {
+			*cx.Config.StratumPass = c.String("stratumpass")
+		}
 		if c.IsSet("blockminsize") {
 			*cx.Config.BlockMinSize = c.Int("blockminsize")
 		}
@@ -327,6 +348,15 @@ func beforeFunc(cx *conte.Xt) func(c *cli.Context) error {
 		if c.IsSet("noaddrindex") {
 			*cx.Config.AddrIndex = c.Bool("noaddrindex")
 		}
+		if c.IsSet("addrutxoindex") {
+			*cx.Config.AddrUtxoIndex = c.Bool("addrutxoindex")
+		}
+		if c.IsSet("algostatsindex") {
+			*cx.Config.AlgoStatsIndex = c.Bool("algostatsindex")
+		}
+		if c.IsSet("prune") {
+			*cx.Config.Prune = c.Int("prune")
+		}
 		if c.IsSet("relaynonstd") {
 			*cx.Config.RelayNonStd = c.Bool("relaynonstd")
 		}
@@ -349,6 +379,15 @@ func beforeFunc(cx *conte.Xt) func(c *cli.Context) error {
 			// as otherwise it would have the hex of the hash of the password here
 			*cx.Config.WalletPass = ""
 		}
+		if c.IsSet("walletwatchonly") {
+			*cx.Config.WalletWatchOnly = c.String("walletwatchonly")
+		}
+		if c.IsSet("walletcoinselection") {
+			*cx.Config.WalletCoinSelection = c.String("walletcoinselection")
+		}
+		if c.IsSet("walletrbf") {
+			*cx.Config.WalletRBF = c.Bool("walletrbf")
+		}
 		if c.IsSet("onetimetlskey") {
 			*cx.Config.OneTimeTLSKey = c.Bool("onetimetlskey")
 		}
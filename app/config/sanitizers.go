@@ -632,6 +632,14 @@ func validatePolicies(cfg *pod.Config, stateConfig *state.Config) {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		// os.Exit(1)
 	}
+	// The mempool size limit may not be negative.
+	Trace("checking max mempool size")
+	if *cfg.MaxMempool < 0 {
+		str := "%s: The maxmempool option may not be less than 0 -- parsed [%d]"
+		err := fmt.Errorf(str, funcName, *cfg.MaxMempool)
+		_, _ = fmt.Fprintln(os.Stderr, err)
+		// os.Exit(1)
+	}
 	// Limit the block priority and minimum block sizes to max block size.
 	Trace("validating block priority and minimum size/weight")
 	*cfg.BlockPrioritySize = int(apputil.MinUint32(
@@ -677,6 +685,19 @@ func validatePolicies(cfg *pod.Config, stateConfig *state.Config) {
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		// os.Exit(1)
 	}
+	// Check the assumed valid block for syntax errors, the empty and zero values are resolved by the node.
+	stateConfig.AssumeValid = nil
+	if av := *cfg.AssumeValid; av != "" && av != "0" {
+		checkpoint, err := node.NewCheckpointFromStr(av)
+		if err != nil {
+			Error(err)
+			str := "%s: Error parsing assumevalid: %v"
+			err := fmt.Errorf(str, funcName, err)
+			_, _ = fmt.Fprintln(os.Stderr, err)
+		} else {
+			stateConfig.AssumeValid = &checkpoint
+		}
+	}
 }
 func validateOnions(cfg *pod.Config) {
 	// --onionproxy and not --onion are contradictory
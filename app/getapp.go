@@ -16,6 +16,7 @@ import (
 	"github.com/p9c/pod/cmd/node/mempool"
 	"github.com/p9c/pod/cmd/walletmain"
 	"github.com/p9c/pod/pkg/coding/base58"
+	"github.com/p9c/pod/pkg/coding/gcs/builder"
 	"github.com/p9c/pod/pkg/db/blockdb"
 	"github.com/p9c/pod/pkg/rpc/legacy"
 	"github.com/p9c/pod/pkg/util/hdkeychain"
@@ -78,6 +79,24 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 						au.SubCommands(),
 						nil,
 					),
+					au.Command("dropaddrutxoindex",
+						"drop the address utxo index",
+						func(c *cli.Context) error {
+							cx.StateCfg.DropAddrUtxoIndex = true
+							return nodeHandle(cx)(c)
+						},
+						au.SubCommands(),
+						nil,
+					),
+					au.Command("dropalgostatsindex",
+						"drop the algo stats index",
+						func(c *cli.Context) error {
+							cx.StateCfg.DropAlgoStatsIndex = true
+							return nodeHandle(cx)(c)
+						},
+						au.SubCommands(),
+						nil,
+					),
 					au.Command("droptxindex",
 						"drop the address search index",
 						func(c *cli.Context) error {
@@ -92,6 +111,8 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 						"drop all of the indexes",
 						func(c *cli.Context) error {
 							cx.StateCfg.DropAddrIndex = true
+							cx.StateCfg.DropAddrUtxoIndex = true
+							cx.StateCfg.DropAlgoStatsIndex = true
 							cx.StateCfg.DropTxIndex = true
 							cx.StateCfg.DropCfIndex = true
 							return nodeHandle(cx)(c)
@@ -110,6 +131,44 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 						au.SubCommands(),
 						nil,
 					),
+					au.Command("buildcfindex",
+						"build the committed filters of a type for the whole chain",
+						func(c *cli.Context) (err error) {
+							if cx.StateCfg.BuildCfIndexType, err = builder.ParseFilterType(c.String("type")); err != nil {
+								return err
+							}
+							cx.StateCfg.BuildCfIndex = true
+							return nodeHandle(cx)(c)
+						},
+						au.SubCommands(),
+						[]cli.Flag{
+							au.String("type", "name or number of the filter type to build", "extended", nil),
+						},
+					),
+					au.Command("checkdb",
+						"check the chain state and indexes in the database against the blocks and optionally repair them",
+						func(c *cli.Context) error {
+							config.Configure(cx, c.Command.Name, true)
+							return node.CheckDB(cx, int32(c.Int("blocks")), c.Bool("repair"))
+						},
+						au.SubCommands(),
+						[]cli.Flag{
+							au.Int("blocks", "number of blocks below the best block to check, 0 checks all of them", 288,
+								nil),
+							au.Bool("repair", "roll back to the last consistent height if there are discrepancies", nil),
+						},
+					),
+					au.Command("migratedb",
+						"copy the block database to a new database of another type",
+						func(c *cli.Context) error {
+							config.Configure(cx, c.Command.Name, true)
+							return node.MigrateDB(cx, c.String("to"))
+						},
+						au.SubCommands(),
+						[]cli.Flag{
+							au.String("to", "database type to copy the block database to", "boltdb", nil),
+						},
+					),
 					au.Command("resetchain",
 						"reset the chain",
 						func(c *cli.Context) (err error) {
@@ -411,6 +470,13 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 				"Disable built-in checkpoints.  Don't do this unless"+
 					" you know what you're doing.",
 				cx.Config.DisableCheckpoints),
+			au.String(
+				"assumevalid",
+				"Skip the script checks of the ancestors of this block. "+
+					"Format: '<height>:<hash>', empty uses the network "+
+					"default and 0 checks all scripts",
+				"",
+				cx.Config.AssumeValid),
 			au.String(
 				"dbtype",
 				"Database backend to use for the Block Chain",
@@ -459,6 +525,17 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 				"Max number of orphan transactions to keep in memory",
 				node.DefaultMaxOrphanTransactions,
 				cx.Config.MaxOrphanTxs),
+			au.Int(
+				"maxmempool",
+				"Keep the transaction memory pool below this many megabytes"+
+					" (0 = unbounded)",
+				mempool.DefaultMaxPoolSizeMB,
+				cx.Config.MaxMempool),
+			au.Bool(
+				"rejectreplacement",
+				"Reject transactions that replace transactions in the"+
+					" mempool even if they signal replaceability (BIP125)",
+				cx.Config.RejectReplacement),
 			au.Bool(
 				"generate, g",
 				"Generate (mine) DUO using the CPU",
@@ -483,6 +560,13 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 					" and other node peers",
 				":0",
 				cx.Config.Controller),
+			au.String(
+				"controllerconnect",
+				"address of a mining controller kopach registers with to be"+
+					" sent work by unicast instead of listening for multicast"+
+					" (empty = multicast)",
+				"",
+				cx.Config.ControllerConnect),
 			au.Bool(
 				"autoports",
 				"uses random automatic ports for p2p, rpc and controller",
@@ -493,11 +577,30 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 					" addresses to use for generated blocks, at least one is "+
 					"required if generate or minerlistener are set",
 				cx.Config.MiningAddrs),
+			au.String(
+				"minerlistener",
+				"address the mining controller accepts kopach registrations"+
+					" on to send them work by unicast, for miners outside the"+
+					" local network (empty = multicast only)",
+				"",
+				cx.Config.MinerListener),
 			au.String(
 				"minerpass",
 				"password to authorise sending work to a miner",
 				genPassword(),
 				cx.Config.MinerPass),
+			au.String(
+				"stratum",
+				"address the Stratum mining server listens on, each"+
+					" algorithm is served on consecutive ports from this one"+
+					" (empty = disabled)",
+				"",
+				cx.Config.Stratum),
+			au.String(
+				"stratumpass",
+				"password Stratum miners must authorize with (empty = any)",
+				"",
+				cx.Config.StratumPass),
 			au.Int(
 				"blockminsize",
 				"Minimum block size in bytes to be used when"+
@@ -560,6 +663,21 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 				"Disable address-based transaction index which makes the searchrawtransactions RPC available",
 				cx.Config.AddrIndex,
 			),
+			au.Bool(
+				"addrutxoindex",
+				"Maintain an index of address balances and unspent outputs which makes the getaddress* RPCs available",
+				cx.Config.AddrUtxoIndex,
+			),
+			au.Bool(
+				"algostatsindex",
+				"Maintain an index of the proof of work of each block which makes the getalgostats RPC available",
+				cx.Config.AlgoStatsIndex,
+			),
+			au.Int(
+				"prune",
+				"Delete the oldest block files to keep them below this many megabytes (0 = disabled, minimum 550)",
+				0,
+				cx.Config.Prune),
 			au.Bool(
 				"relaynonstd",
 				"Relay non-standard transactions regardless of the default settings for the active network.",
@@ -584,6 +702,21 @@ func GetApp(cx *conte.Xt) (a *cli.App) {
 				"The public wallet password -- Only required if the wallet was created with one",
 				"",
 				cx.Config.WalletPass),
+			au.String(
+				"walletwatchonly",
+				"create the wallet watching-only from the extended public key (xpub, ypub or zpub) of an account",
+				"",
+				cx.Config.WalletWatchOnly),
+			au.String(
+				"walletcoinselection",
+				"strategy choosing the outputs a transaction spends: bnb (changeless if possible, else knapsack), "+
+					"knapsack, privacy (spend whole address groups), largest or valueage",
+				"bnb",
+				cx.Config.WalletCoinSelection),
+			au.BoolTrue(
+				"walletrbf",
+				"signal that the transactions the wallet sends may be replaced with a higher fee (BIP125)",
+				cx.Config.WalletRBF),
 			au.Bool(
 				"onetimetlskey",
 				"Generate a new TLS certificate pair at startup, but only write the certificate to disk",
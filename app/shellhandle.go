@@ -73,6 +73,10 @@ func ShellHandle(cx *conte.Xt) func(c *cli.Context) (err error) {
 			Info("node started")
 		}
 		if !*cx.Config.WalletOff {
+			// an unknown coin selection strategy is refused now rather than when the first transaction is created
+			if _, err = wallet.CoinSelectorByName(*cx.Config.WalletCoinSelection); Check(err) {
+				return
+			}
 			go func() {
 				err = walletmain.Main(cx)
 				if err != nil {
@@ -18,6 +18,10 @@ import (
 func WalletHandle(cx *conte.Xt) func(c *cli.Context) (err error) {
 	return func(c *cli.Context) (err error) {
 		config.Configure(cx, c.Command.Name, true)
+		// an unknown coin selection strategy is refused now rather than when the first transaction is created
+		if _, err = wallet.CoinSelectorByName(*cx.Config.WalletCoinSelection); Check(err) {
+			return
+		}
 		*cx.Config.WalletFile = *cx.Config.DataDir + string(os.PathSeparator) +
 			cx.ActiveNet.Name + string(os.PathSeparator) + wallet.WalletDbName
 		// dbFilename := *cx.Config.DataDir + slash + cx.ActiveNet.
@@ -1,6 +1,8 @@
 package gui
 
 import (
+	"fmt"
+	
 	l "gioui.org/layout"
 	
 	"github.com/p9c/pod/pkg/gui/p9"
@@ -98,6 +100,49 @@ func (wg *WalletGUI) HistoryPageView() l.Widget {
 	// ).Fn
 }
 
+// updateHistoryTable generates the rows of the history table from the filtered transactions, with the label of the
+// address and the comment and payee recorded when the transaction was sent
+func (wg *WalletGUI) updateHistoryTable() {
+	if wg.historyTable.Header == nil {
+		wg.historyTable.Header = p9.TextTableHeader{
+			"Amount",
+			"Category",
+			"Address",
+			"Label",
+			"Time",
+			"Conf",
+			"In Block",
+			"Comment",
+			"To",
+		}
+	}
+	wg.State.FilteredMutex.Lock()
+	defer wg.State.FilteredMutex.Unlock()
+	var bd p9.TextTableBody
+	for i, oi := range wg.State.FilteredTxs {
+		// unconfirmed transactions are not in a block yet
+		var blockIndex string
+		if oi.BlockIndex != nil {
+			blockIndex = fmt.Sprintf("%v", *oi.BlockIndex)
+		}
+		bd = append(
+			bd, p9.TextTableRow{
+				fmt.Sprintf("%6.8f", oi.Amount),
+				oi.Category,
+				oi.Address,
+				oi.Label,
+				wg.State.FilteredTimeStrings[i],
+				fmt.Sprintf("%v", oi.Confirmations),
+				blockIndex,
+				oi.Comment,
+				oi.To,
+			},
+		)
+	}
+	wg.historyTable.Body = bd
+	wg.historyTable.Regenerate(false)
+}
+
 func (wg *WalletGUI) HistoryPageStatusFilter() l.Widget {
 	return wg.th.Flex().AlignMiddle().
 		Rigid(
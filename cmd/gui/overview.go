@@ -9,6 +9,7 @@ import (
 	l "gioui.org/layout"
 
 	"github.com/p9c/pod/pkg/gui/p9"
+	"github.com/p9c/pod/pkg/rpc/btcjson"
 )
 
 func (wg *WalletGUI) OverviewPage() l.Widget {
@@ -156,7 +157,7 @@ func (wg *WalletGUI) OverviewPage() l.Widget {
 
 // RecentTransactions generates a display showing recent transactions
 //
-// fields to use: Address, Amount, BlockIndex, BlockTime, Category, Confirmations, Generated
+// fields to use: Address, Amount, BlockIndex, BlockTime, Category, Comment, Confirmations, Generated, Label, To
 func (wg *WalletGUI) RecentTransactions() l.Widget {
 	var out []l.Widget
 	first := true
@@ -184,14 +185,27 @@ func (wg *WalletGUI) RecentTransactions() l.Widget {
 			).Fn,
 		)
 
+		address := txs.Address
+		if txs.Label != "" {
+			address = txs.Label + " " + address
+		}
 		out = append(out,
 			wg.th.Fill("DocBg",
-				wg.th.Caption(txs.Address).
+				wg.th.Caption(address).
 					Font("go regular").
 					Color("PanelText").
 					TextScale(0.66).Fn,
 			).Fn,
 		)
+		if comment := txComment(txs); comment != "" {
+			out = append(out,
+				wg.th.Fill("DocBg",
+					wg.th.Caption(comment).
+						Color("PanelText").
+						TextScale(0.66).Fn,
+				).Fn,
+			)
+		}
 
 		out = append(out,
 			wg.th.Fill("DocBg",
@@ -283,6 +297,17 @@ func (wg *WalletGUI) RecentTransactions() l.Widget {
 	}
 }
 
+// txComment returns the comment recorded for a transaction, along with who it pays when that was recorded too
+func txComment(txs btcjson.ListTransactionsResult) string {
+	switch {
+	case txs.To == "":
+		return txs.Comment
+	case txs.Comment == "":
+		return "to " + txs.To
+	}
+	return txs.Comment + " (to " + txs.To + ")"
+}
+
 func leftPadTo(length, limit int, txt string) string {
 	if len(txt) > limit {
 		return txt[limit-len(txt):]
@@ -80,3 +80,19 @@ func (c *Client) SendPass(pass string) (err error) {
 	}
 	return
 }
+
+// SetController tells the worker to send its solutions and hashrate reports to a controller at the given address by
+// unicast, it must be sent before SendPass
+func (c *Client) SetController(addr string) (err error) {
+	Debug("sending controller address")
+	var reply bool
+	err = c.Call("Worker.SetController", addr, &reply)
+	if err != nil {
+		Error(err)
+		return
+	}
+	if reply != true {
+		err = errors.New("set controller command not acknowledged")
+	}
+	return
+}
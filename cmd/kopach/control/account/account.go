@@ -0,0 +1,109 @@
+// Package account is a message type for Simplebuffers sent by a controller to each kopach miner registered with it for
+// unicast work, reporting the hashrate and share counts the controller has recorded for the miner on each algorithm
+package account
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/p9c/pod/pkg/coding/simplebuffer"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/String"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Time"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Uint64"
+)
+
+// Magic is the marker for packets containing an account report
+var Magic = []byte{'a', 'c', 'c', 't'}
+
+// fieldsPerAlgo is the number of fields each algorithm adds to the message after the time and ID
+const fieldsPerAlgo = 6
+
+type Container struct {
+	simplebuffer.Container
+}
+
+// Algo is the record of the work of a miner on one algorithm
+type Algo struct {
+	Algo         string
+	HashesPerSec float64
+	Hashes       uint64
+	Accepted     uint64
+	Stale        uint64
+	Invalid      uint64
+}
+
+// Get returns an account report for the miner with the given ID
+func Get(id string, algos []Algo) Container {
+	srs := simplebuffer.Serializers{
+		Time.New().Put(time.Now()),
+		String.New().Put(id),
+	}
+	for i := range algos {
+		srs = append(
+			srs,
+			String.New().Put(algos[i].Algo),
+			// the hashrate is sent as the bits of the float as it is often well under one hash per second
+			Uint64.New().Put(math.Float64bits(algos[i].HashesPerSec)),
+			Uint64.New().Put(algos[i].Hashes),
+			Uint64.New().Put(algos[i].Accepted),
+			Uint64.New().Put(algos[i].Stale),
+			Uint64.New().Put(algos[i].Invalid),
+		)
+	}
+	return Container{*srs.CreateContainer(Magic)}
+}
+
+// LoadContainer takes a message byte slice payload and loads it into a container ready to be decoded
+func LoadContainer(b []byte) (out Container) {
+	out.Data = b
+	return
+}
+
+func (j *Container) GetTime() time.Time {
+	return Time.New().DecodeOne(j.Get(0)).Get()
+}
+
+func (j *Container) GetID() string {
+	return String.New().DecodeOne(j.Get(1)).Get()
+}
+
+// GetAlgos decodes the records of each algorithm
+func (j *Container) GetAlgos() (out []Algo) {
+	count := j.Count()
+	if count < 2 {
+		return
+	}
+	for i := uint16(2); i+fieldsPerAlgo <= count; i += fieldsPerAlgo {
+		out = append(
+			out, Algo{
+				Algo:         String.New().DecodeOne(j.Get(i)).Get(),
+				HashesPerSec: math.Float64frombits(Uint64.New().DecodeOne(j.Get(i + 1)).Get()),
+				Hashes:       Uint64.New().DecodeOne(j.Get(i + 2)).Get(),
+				Accepted:     Uint64.New().DecodeOne(j.Get(i + 3)).Get(),
+				Stale:        Uint64.New().DecodeOne(j.Get(i + 4)).Get(),
+				Invalid:      Uint64.New().DecodeOne(j.Get(i + 5)).Get(),
+			},
+		)
+	}
+	return
+}
+
+func (j *Container) String() (s string) {
+	s += fmt.Sprint("\ntype '"+string(Magic)+"' elements:", j.Count())
+	s += "\n"
+	s += "1 Time: "
+	s += fmt.Sprint(j.GetTime())
+	s += "\n"
+	s += "2 ID: "
+	s += fmt.Sprint(j.GetID())
+	s += "\n"
+	algos := j.GetAlgos()
+	for i := range algos {
+		s += fmt.Sprintf(
+			"%d %s: %.2f hash/s %d hashes %d accepted %d stale %d invalid\n", i+3, algos[i].Algo,
+			algos[i].HashesPerSec, algos[i].Hashes, algos[i].Accepted, algos[i].Stale, algos[i].Invalid,
+		)
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package account
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAccount(t *testing.T) {
+	algos := []Algo{
+		{Algo: "blake2b", HashesPerSec: 0.25, Hashes: 1380, Accepted: 12, Stale: 1, Invalid: 0},
+		{Algo: "scrypt", HashesPerSec: 1234.5, Hashes: 69, Accepted: 0, Stale: 0, Invalid: 3},
+	}
+	c := Get("rig-01", algos)
+	dec := LoadContainer(c.Data)
+	if id := dec.GetID(); id != "rig-01" {
+		t.Fatalf("id is %q, want %q", id, "rig-01")
+	}
+	if got := dec.GetAlgos(); !reflect.DeepEqual(got, algos) {
+		t.Fatalf("algos are %+v, want %+v", got, algos)
+	}
+	empty := Get("rig-02", nil)
+	dec = LoadContainer(empty.Data)
+	if got := dec.GetAlgos(); len(got) != 0 {
+		t.Fatalf("algos are %+v, want none", got)
+	}
+}
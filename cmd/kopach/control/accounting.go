@@ -0,0 +1,200 @@
+package control
+
+import (
+	"net"
+	"sort"
+	"time"
+
+	"github.com/VividCortex/ewma"
+
+	"github.com/p9c/pod/cmd/kopach/control/account"
+	"github.com/p9c/pod/cmd/kopach/control/share"
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/comm/transport"
+	"github.com/p9c/pod/pkg/rpc/btcjson"
+)
+
+const (
+	// AccountReportInterval is how often each miner registered for unicast work is sent the record the controller keeps
+	// of its work
+	AccountReportInterval = time.Second * 5
+	// AccountTimeout is how long the record of a miner is kept after it was last heard from
+	AccountTimeout = time.Hour
+)
+
+// workerAccount is the record the controller keeps of the work of a kopach miner, which may run several worker
+// processes that all report under the ID of the miner
+type workerAccount struct {
+	id       string
+	addr     net.Addr
+	lastSeen time.Time
+	algos    map[string]*algoAccount
+}
+
+// algoAccount is the record of the work of a miner on one algorithm
+type algoAccount struct {
+	hashes   uint64
+	sampled  uint64
+	rate     ewma.MovingAverage
+	accepted uint64
+	stale    uint64
+	invalid  uint64
+}
+
+// account returns the record of the miner with the given ID on the named algorithm, creating them as needed and
+// updating when and where the miner was last heard from. This function MUST be called with the accounts lock held.
+func (c *Controller) account(id string, src net.Addr, algo string) *algoAccount {
+	wa, ok := c.accounts[id]
+	if !ok {
+		wa = &workerAccount{id: id, algos: make(map[string]*algoAccount)}
+		c.accounts[id] = wa
+		Debug("accounting for new miner", id, "at", src)
+	}
+	wa.addr = src
+	wa.lastSeen = time.Now()
+	aa, ok := wa.algos[algo]
+	if !ok {
+		aa = &algoAccount{rate: ewma.NewMovingAverage()}
+		wa.algos[algo] = aa
+	}
+	return aa
+}
+
+// accountHashes adds a count of hashes reported by a miner for the given block version and height
+func (c *Controller) accountHashes(id string, src net.Addr, version, height int32, count int) {
+	algo := algoName(version, height)
+	c.accountsMx.Lock()
+	defer c.accountsMx.Unlock()
+	c.account(id, src, algo).hashes += uint64(count)
+}
+
+// accountShare checks a share submitted by a miner and counts it as accepted, stale or invalid. A share is stale if it
+// does not build on the best block and invalid if it does not meet the share target of the current job for its
+// version, or was already submitted.
+func (c *Controller) accountShare(id string, src net.Addr, header *wire.BlockHeader) {
+	best := c.blockTemplateGenerator.BestSnapshot()
+	height := best.Height + 1
+	algo := algoName(header.Version, height)
+	c.accountsMx.Lock()
+	defer c.accountsMx.Unlock()
+	aa := c.account(id, src, algo)
+	if !header.PrevBlock.IsEqual(&best.Hash) {
+		aa.stale++
+		return
+	}
+	if c.sharesPrev != best.Hash {
+		c.sharesPrev = best.Hash
+		c.shares = make(map[chainhash.Hash]struct{})
+	}
+	hash := header.BlockHashWithAlgos(height)
+	_, duplicate := c.shares[hash]
+	bitses, _ := c.bitses.Load().(blockchain.TargetBits)
+	bits, ok := bitses[header.Version]
+	if duplicate || !ok || bits != header.Bits || blockchain.HashToBig(&hash).Cmp(share.Target(bits)) > 0 {
+		Debug("invalid share from miner", id, "at", src)
+		aa.invalid++
+		return
+	}
+	c.shares[hash] = struct{}{}
+	aa.accepted++
+}
+
+// algoName returns the name of the algorithm of a block version, or the version if it is not known at the height
+func algoName(version, height int32) (name string) {
+	if name = fork.GetAlgoName(version, height); name == "" {
+		name = "unknown"
+	}
+	return
+}
+
+// sampleAccounts adds the hashes counted since the last sample to the hashrate averages and forgets miners that have
+// not been heard from within AccountTimeout. It is called every second.
+func (c *Controller) sampleAccounts() {
+	c.accountsMx.Lock()
+	defer c.accountsMx.Unlock()
+	for id, wa := range c.accounts {
+		if time.Since(wa.lastSeen) > AccountTimeout {
+			delete(c.accounts, id)
+			continue
+		}
+		for _, aa := range wa.algos {
+			aa.rate.Add(float64(aa.hashes - aa.sampled))
+			aa.sampled = aa.hashes
+		}
+	}
+}
+
+// algoStats returns the record of each algorithm of a miner sorted by name. This function MUST be called with the
+// accounts lock held.
+func (wa *workerAccount) algoStats() (out []account.Algo) {
+	for algo, aa := range wa.algos {
+		out = append(
+			out, account.Algo{
+				Algo:         algo,
+				HashesPerSec: aa.rate.Value(),
+				Hashes:       aa.hashes,
+				Accepted:     aa.accepted,
+				Stale:        aa.stale,
+				Invalid:      aa.invalid,
+			},
+		)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Algo < out[j].Algo })
+	return
+}
+
+// sendAccounts sends each miner registered for unicast work the record of its own work. Miners that only listen for
+// multicast are not sent one, as every miner would receive the records of all the others.
+func (c *Controller) sendAccounts() {
+	if c.uniConn == nil {
+		return
+	}
+	reports := make(map[string][][]byte)
+	c.accountsMx.Lock()
+	for id, wa := range c.accounts {
+		reports[id] = transport.GetShards(account.Get(id, wa.algoStats()).Data)
+	}
+	c.accountsMx.Unlock()
+	c.workersMx.Lock()
+	defer c.workersMx.Unlock()
+	for _, w := range c.unicastWorkers {
+		if shards, ok := reports[w.id]; ok {
+			if err := c.uniConn.SendManyTo(w.addr, account.Magic, shards); Check(err) {
+			}
+		}
+	}
+}
+
+// WorkerStats returns the record of the work of each miner the controller has heard from, sorted by ID
+func (c *Controller) WorkerStats() (out []btcjson.GetWorkerStatsResult) {
+	c.accountsMx.Lock()
+	defer c.accountsMx.Unlock()
+	out = make([]btcjson.GetWorkerStatsResult, 0, len(c.accounts))
+	for id, wa := range c.accounts {
+		algos := wa.algoStats()
+		res := btcjson.GetWorkerStatsResult{
+			ID:       id,
+			LastSeen: wa.lastSeen.Unix(),
+			Algos:    make([]btcjson.WorkerAlgoStatsResult, len(algos)),
+		}
+		if wa.addr != nil {
+			res.Address = wa.addr.String()
+		}
+		for i := range algos {
+			res.Algos[i] = btcjson.WorkerAlgoStatsResult{
+				Algo:         algos[i].Algo,
+				HashesPerSec: algos[i].HashesPerSec,
+				Hashes:       algos[i].Hashes,
+				Accepted:     algos[i].Accepted,
+				Stale:        algos[i].Stale,
+				Invalid:      algos[i].Invalid,
+			}
+		}
+		out = append(out, res)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return
+}
@@ -0,0 +1,165 @@
+package control
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/p9c/pod/cmd/kopach/control/account"
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/mining"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/comm/transport"
+	database "github.com/p9c/pod/pkg/db"
+	_ "github.com/p9c/pod/pkg/db/ffldb"
+	qu "github.com/p9c/pod/pkg/util/quit"
+)
+
+// testGenerator returns a block template generator on a new regression test chain holding only the genesis block. The
+// returned function removes the chain.
+func testGenerator(t *testing.T) (*mining.BlkTmplGenerator, func()) {
+	dir, err := ioutil.TempDir("", "controltest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	params := &netparams.RegressionTestParams
+	db, err := database.Create("ffldb", dir, params.Net)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unable to create db: %v", err)
+	}
+	teardown := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: params,
+		TimeSource:  blockchain.NewMedianTime(),
+		SigCache:    txscript.NewSigCache(1000),
+	})
+	if err != nil {
+		teardown()
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	return &mining.BlkTmplGenerator{ChainParams: params, Chain: chain}, teardown
+}
+
+// TestAccountShare checks that shares on the best block meeting the share target of the current job are accepted once,
+// and that other shares are counted as stale or invalid against the miner that submitted them.
+func TestAccountShare(t *testing.T) {
+	g, teardown := testGenerator(t)
+	defer teardown()
+	c := &Controller{
+		blockTemplateGenerator: g,
+		accounts:               make(map[string]*workerAccount),
+		shares:                 make(map[chainhash.Hash]struct{}),
+	}
+	best := g.BestSnapshot()
+	version := fork.GetAlgoVer(fork.SHA256d, best.Height+1)
+	const easyBits, hardBits = 0x207fffff, 0x1d00ffff
+	c.bitses.Store(blockchain.TargetBits{version: easyBits})
+	src := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+	header := wire.BlockHeader{Version: version, PrevBlock: best.Hash, Timestamp: time.Unix(time.Now().Unix(), 0),
+		Bits: easyBits}
+	c.accountShare("a", src, &header)
+	// the same share again is not counted twice
+	c.accountShare("a", src, &header)
+	header.Nonce++
+	c.accountShare("b", src, &header)
+	// a share that does not build on the best block
+	stale := header
+	stale.PrevBlock[0] ^= 1
+	c.accountShare("b", src, &stale)
+	// a share with different bits than the job
+	wrongBits := header
+	wrongBits.Nonce++
+	wrongBits.Bits = hardBits
+	c.accountShare("b", src, &wrongBits)
+	// a share that misses the target of the job
+	c.bitses.Store(blockchain.TargetBits{version: hardBits})
+	c.accountShare("b", src, &wrongBits)
+	algo := fork.GetAlgoName(version, best.Height+1)
+	for _, want := range []struct {
+		id                       string
+		accepted, stale, invalid uint64
+	}{
+		{"a", 1, 0, 1},
+		{"b", 1, 1, 2},
+	} {
+		wa, ok := c.accounts[want.id]
+		if !ok {
+			t.Errorf("no account for miner %s", want.id)
+			continue
+		}
+		aa, ok := wa.algos[algo]
+		if !ok {
+			t.Errorf("no %s account for miner %s", algo, want.id)
+			continue
+		}
+		if aa.accepted != want.accepted || aa.stale != want.stale || aa.invalid != want.invalid {
+			t.Errorf("miner %s has %d accepted, %d stale and %d invalid shares, want %d, %d and %d", want.id,
+				aa.accepted, aa.stale, aa.invalid, want.accepted, want.stale, want.invalid)
+		}
+	}
+}
+
+// TestSendAccounts checks that each miner registered for unicast work is sent the record of its own work only.
+func TestSendAccounts(t *testing.T) {
+	quit := qu.T()
+	defer quit.Q()
+	c, teardown := testController(t, quit)
+	defer teardown()
+	c.accounts = make(map[string]*workerAccount)
+	src := &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 1}
+	for _, id := range []string{"a", "b", "unregistered"} {
+		c.account(id, src, fork.SHA256d).hashes = 100
+	}
+	reports := make(map[string]chan string)
+	for _, id := range []string{"a", "b"} {
+		ch := make(chan string, 4)
+		reports[id] = ch
+		miner, err := transport.NewDialChannel(id, nil, testKey, c.uniConn.Receiver.LocalAddr().String(),
+			MaxDatagramSize, transport.Handlers{
+				string(account.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+					a := account.LoadContainer(b)
+					ch <- a.GetID()
+					return
+				},
+			}, quit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer miner.Sender.Close()
+		if err = miner.AddSalt(c.uniConn.Salt()); err != nil {
+			t.Fatal(err)
+		}
+		addr := miner.Sender.LocalAddr().(*net.UDPAddr)
+		c.unicastWorkers[addr.String()] = &unicastWorker{id: id, addr: addr, lastSeen: time.Now()}
+	}
+	c.sendAccounts()
+	for id, ch := range reports {
+		select {
+		case got := <-ch:
+			if got != id {
+				t.Errorf("miner %s was sent the record of %s", id, got)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("miner %s was not sent its record", id)
+		}
+	}
+	time.Sleep(time.Millisecond * 100)
+	for id, ch := range reports {
+		select {
+		case got := <-ch:
+			t.Errorf("miner %s was also sent the record of %s", id, got)
+		default:
+		}
+	}
+}
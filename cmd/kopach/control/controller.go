@@ -8,6 +8,7 @@ import (
 	"math/rand"
 	"net"
 	"strings"
+	"sync"
 	"time"
 	
 	"github.com/VividCortex/ewma"
@@ -18,6 +19,8 @@ import (
 	"github.com/p9c/pod/cmd/kopach/control/job"
 	"github.com/p9c/pod/cmd/kopach/control/p2padvt"
 	"github.com/p9c/pod/cmd/kopach/control/pause"
+	"github.com/p9c/pod/cmd/kopach/control/reg"
+	"github.com/p9c/pod/cmd/kopach/control/share"
 	"github.com/p9c/pod/cmd/kopach/control/sol"
 	blockchain "github.com/p9c/pod/pkg/chain"
 	"github.com/p9c/pod/pkg/chain/fork"
@@ -35,11 +38,16 @@ const (
 	MaxDatagramSize      = 8192
 	UDP4MulticastAddress = "224.0.0.1:11049"
 	BufferSize           = 4096
+	// WorkerTimeout is how long a miner registered for unicast work is sent work after its last registration
+	WorkerTimeout = time.Second * 10
 )
 
 type Controller struct {
 	multiConn *transport.Channel
-	// uniConn                *transport.Channel
+	// uniConn receives registrations from miners that are sent work by unicast, and their solutions
+	uniConn                *transport.Channel
+	unicastWorkers         map[string]*unicastWorker
+	workersMx              sync.Mutex
 	active                 atomic.Bool
 	quit                   qu.C
 	cx                     *conte.Xt
@@ -62,6 +70,21 @@ type Controller struct {
 	hashCount              atomic.Uint64
 	hashSampleBuf          *rav.BufferUint64
 	lastNonce              int32
+	// accounts is the record of the work of each miner by ID, and shares the hashes of the shares accepted on
+	// sharesPrev, the best block they were last accepted on
+	accounts   map[string]*workerAccount
+	accountsMx sync.Mutex
+	shares     map[chainhash.Hash]struct{}
+	sharesPrev chainhash.Hash
+	// bitses holds the block target bits of each version of the current job, which shares are checked against
+	bitses atomic.Value
+}
+
+// unicastWorker is a kopach miner that has registered with the controller to be sent work by unicast
+type unicastWorker struct {
+	id       string
+	addr     *net.UDPAddr
+	lastSeen time.Time
 }
 
 func Run(cx *conte.Xt) (quit qu.C) {
@@ -85,13 +108,16 @@ func Run(cx *conte.Xt) (quit qu.C) {
 		cx:                     cx,
 		sendAddresses:          []*net.UDPAddr{},
 		submitChan:             make(chan []byte),
-		blockTemplateGenerator: getBlkTemplateGenerator(cx),
+		blockTemplateGenerator: GetBlkTemplateGenerator(cx),
 		coinbases:              make(map[int32]*util.Tx),
 		buffer:                 ring.New(BufferSize),
 		began:                  time.Now(),
 		otherNodes:             make(map[string]time.Time),
 		listenPort:             int(Uint16.GetActualPort(*cx.Config.Controller)),
 		hashSampleBuf:          rav.NewBufferUint64(100),
+		unicastWorkers:         make(map[string]*unicastWorker),
+		accounts:               make(map[string]*workerAccount),
+		shares:                 make(map[chainhash.Hash]struct{}),
 	}
 	quit = ctrl.quit
 	ctrl.lastTxUpdate.Store(time.Now().UnixNano())
@@ -110,6 +136,20 @@ func Run(cx *conte.Xt) (quit qu.C) {
 		ctrl.quit.Q()
 		return
 	}
+	if *cx.Config.MinerListener != "" {
+		if ctrl.uniConn, err = transport.NewListenerChannel(
+			"controller", ctrl, *cx.Config.MinerPass, *cx.Config.MinerListener, MaxDatagramSize, handlersUnicast, quit,
+		); Check(err) {
+			ctrl.quit.Q()
+			return
+		}
+		// unicast miners are sent the same jobs as multicast ones, which tell workers the salt to send solutions with
+		if err = ctrl.uniConn.SetSalt(ctrl.multiConn.Salt()); Check(err) {
+			ctrl.quit.Q()
+			return
+		}
+		Info("accepting unicast miner registrations on", *cx.Config.MinerListener)
+	}
 	pM := pause.GetPauseContainer(cx)
 	var pauseShards [][]byte
 	if pauseShards = transport.GetShards(pM.Data); Check(err) {
@@ -121,12 +161,16 @@ func Run(cx *conte.Xt) (quit qu.C) {
 		func() {
 			Debug("miner controller shutting down")
 			ctrl.active.Store(false)
-			err := ctrl.multiConn.SendMany(pause.PauseMagic, pauseShards)
+			err := ctrl.sendWork(pause.PauseMagic, pauseShards)
 			if err != nil {
 				Error(err)
 			}
 			if err = ctrl.multiConn.Close(); Check(err) {
 			}
+			if ctrl.uniConn != nil {
+				if err = ctrl.uniConn.Receiver.Close(); Check(err) {
+				}
+			}
 			ctrl.quit.Q()
 		},
 	)
@@ -143,15 +187,24 @@ func Run(cx *conte.Xt) (quit qu.C) {
 		go submitter(ctrl)
 	}
 	go advertiser(ctrl)
+	for i := range cx.RealNode.RPCServers {
+		cx.RealNode.RPCServers[i].Cfg.WorkerStats.Store(ctrl.WorkerStats)
+	}
 	factor := 1
 	ticker := time.NewTicker(time.Second * time.Duration(factor))
 	go func() {
+		var ticks int
 	out:
 		for {
 			select {
 			case <-ticker.C:
 				// qu.PrintChanState()
 				Debug("controller ticker")
+				ctrl.reapWorkers()
+				ctrl.sampleAccounts()
+				if ticks++; ticks%int(AccountReportInterval/time.Second) == 0 {
+					ctrl.sendAccounts()
+				}
 				if !ctrl.Ready.Load() {
 					if cx.IsCurrent() {
 						Info("ready to send out jobs!")
@@ -180,6 +233,35 @@ func Run(cx *conte.Xt) (quit qu.C) {
 	return
 }
 
+// sendWork sends a job or pause message to the miners listening for multicast and to each miner registered for unicast
+func (c *Controller) sendWork(magic []byte, shards [][]byte) (err error) {
+	if err = c.multiConn.SendMany(magic, shards); Check(err) {
+	}
+	if c.uniConn == nil {
+		return
+	}
+	c.workersMx.Lock()
+	defer c.workersMx.Unlock()
+	for _, w := range c.unicastWorkers {
+		if e := c.uniConn.SendManyTo(w.addr, magic, shards); Check(e) {
+			err = e
+		}
+	}
+	return
+}
+
+// reapWorkers stops sending work to unicast miners that have not registered again within WorkerTimeout
+func (c *Controller) reapWorkers() {
+	c.workersMx.Lock()
+	defer c.workersMx.Unlock()
+	for addr, w := range c.unicastWorkers {
+		if time.Since(w.lastSeen) > WorkerTimeout {
+			Info("unicast miner", w.id, "at", addr, "timed out")
+			delete(c.unicastWorkers, addr)
+		}
+	}
+}
+
 func (c *Controller) HashReport() float64 {
 	c.hashSampleBuf.Add(c.hashCount.Load())
 	av := ewma.NewMovingAverage()
@@ -241,7 +323,7 @@ var handlersMulticast = transport.Handlers{
 			msgBlock.Transactions = append(msgBlock.Transactions, txs[i].MsgTx())
 		}
 		// set old blocks to pause and send pause directly as block is probably a solution
-		err = c.multiConn.SendMany(pause.PauseMagic, c.pauseShards)
+		err = c.sendWork(pause.PauseMagic, c.pauseShards)
 		if err != nil {
 			Error(err)
 			return
@@ -349,12 +431,62 @@ var handlersMulticast = transport.Handlers{
 		c.lastNonce = nonce
 		// add to total hash counts
 		c.hashCount.Store(c.hashCount.Load() + uint64(count))
+		c.accountHashes(hp.GetID(), src, hp.GetVersion(), hp.GetHeight(), count)
+		return
+	},
+	// shares from workers
+	string(share.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+		c := ctx.(*Controller)
+		if !c.active.Load() {
+			Debug("not active")
+			return
+		}
+		s := share.LoadContainer(b)
+		c.accountShare(s.GetID(), src, s.GetHeader())
+		return
+	},
+}
+
+// handlersUnicast are the handlers for messages from miners registered for unicast work, which send their hashrate
+// reports, shares and solutions straight to the controller
+var handlersUnicast = transport.Handlers{
+	string(reg.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+		c := ctx.(*Controller)
+		addr, ok := src.(*net.UDPAddr)
+		if !ok {
+			return
+		}
+		r := reg.LoadContainer(b)
+		c.workersMx.Lock()
+		w, known := c.unicastWorkers[addr.String()]
+		if !known {
+			w = &unicastWorker{id: r.GetID(), addr: addr}
+			c.unicastWorkers[addr.String()] = w
+			Info("unicast miner", w.id, "registered from", addr)
+		}
+		w.lastSeen = time.Now()
+		c.workersMx.Unlock()
+		// the miner learns the salt of the key work is sent with from the advertisment, it is sent on every
+		// registration as the miner may have restarted since it was last sent
+		ad := transport.GetShards(p2padvt.GetAdvertisment(c.cx, c.uniConn.Salt()).CreateContainer(p2padvt.Magic).Data)
+		if err = c.uniConn.SendManyTo(addr, p2padvt.Magic, ad); Check(err) {
+		}
+		// a new miner is sent the current job straight away rather than at the next rebroadcast
+		if !known && c.active.Load() {
+			if oB, ok := c.oldBlocks.Load().([][]byte); ok && len(oB) > 0 {
+				if err = c.uniConn.SendManyTo(addr, job.Magic, oB); Check(err) {
+				}
+			}
+		}
 		return
 	},
+	string(sol.SolutionMagic):      handlersMulticast[string(sol.SolutionMagic)],
+	string(hashrate.HashrateMagic): handlersMulticast[string(hashrate.HashrateMagic)],
+	string(share.Magic):            handlersMulticast[string(share.Magic)],
 }
 
 func (c *Controller) sendNewBlockTemplate() (err error) {
-	template := getNewBlockTemplate(c.cx, c.blockTemplateGenerator)
+	template := GetNewBlockTemplate(c.cx, c.blockTemplateGenerator, fork.SHA256d)
 	if template == nil {
 		err = errors.New("could not get template")
 		Error(err)
@@ -365,14 +497,15 @@ func (c *Controller) sendNewBlockTemplate() (err error) {
 	var fMC job.Container
 	adv := p2padvt.Get(c.cx)
 	// Traces(adv)
-	fMC, c.transactions = job.Get(c.cx, util.NewBlock(msgB), adv, &c.coinbases)
+	fMC, c.transactions = job.Get(c.cx, util.NewBlock(msgB), adv, c.multiConn.Salt(), &c.coinbases)
 	jobShards := transport.GetShards(fMC.Data)
 	shardsLen := len(jobShards)
 	if shardsLen < 1 {
 		Warn("jobShards", shardsLen)
 		return fmt.Errorf("jobShards len %d", shardsLen)
 	}
-	err = c.multiConn.SendMany(job.Magic, jobShards)
+	c.bitses.Store(fMC.GetBitses())
+	err = c.sendWork(job.Magic, jobShards)
 	if err != nil {
 		Error(err)
 	}
@@ -383,8 +516,10 @@ func (c *Controller) sendNewBlockTemplate() (err error) {
 	return
 }
 
-func getNewBlockTemplate(
-	cx *conte.Xt, bTG *mining.BlkTmplGenerator,
+// GetNewBlockTemplate returns a new block template for the passed algorithm paying to one of the configured mining
+// addresses, or nil if there are none or the template could not be created.
+func GetNewBlockTemplate(
+	cx *conte.Xt, bTG *mining.BlkTmplGenerator, algo string,
 ) (template *mining.BlockTemplate) {
 	Trace("getting new block template")
 	if len(*cx.Config.MiningAddrs) < 1 {
@@ -402,7 +537,7 @@ func getNewBlockTemplate(
 	Trace("calling new block template")
 	template, err := bTG.NewBlockTemplate(
 		0, payToAddr,
-		fork.SHA256d,
+		algo,
 	)
 	if err != nil {
 		Error(err)
@@ -412,7 +547,9 @@ func getNewBlockTemplate(
 	return
 }
 
-func getBlkTemplateGenerator(cx *conte.Xt) *mining.BlkTmplGenerator {
+// GetBlkTemplateGenerator returns a block template generator using the mining policy of the configuration and the
+// chain state of the node.
+func GetBlkTemplateGenerator(cx *conte.Xt) *mining.BlkTmplGenerator {
 	policy := mining.Policy{
 		BlockMinWeight:    uint32(*cx.Config.BlockMinWeight),
 		BlockMaxWeight:    uint32(*cx.Config.BlockMaxWeight),
@@ -431,7 +568,7 @@ func getBlkTemplateGenerator(cx *conte.Xt) *mining.BlkTmplGenerator {
 
 func advertiser(c *Controller) {
 	advertismentTicker := time.NewTicker(time.Second)
-	advt := p2padvt.Get(c.cx)
+	advt := p2padvt.GetAdvertisment(c.cx, c.multiConn.Salt())
 	ad := transport.GetShards(advt.CreateContainer(p2padvt.Magic).Data)
 out:
 	for {
@@ -494,7 +631,7 @@ out:
 				continue
 			}
 			Debug("sending out job")
-			err := c.multiConn.SendMany(job.Magic, oB)
+			err := c.sendWork(job.Magic, oB)
 			if err != nil {
 				Error(err)
 			}
@@ -552,7 +689,7 @@ func (c *Controller) getNotifier() func(n *blockchain.Notification) {
 
 func (c *Controller) UpdateAndSendTemplate() {
 	c.coinbases = make(map[int32]*util.Tx)
-	template := getNewBlockTemplate(c.cx, c.blockTemplateGenerator)
+	template := GetNewBlockTemplate(c.cx, c.blockTemplateGenerator, fork.SHA256d)
 	if template != nil {
 		c.transactions = []*util.Tx{}
 		for _, v := range template.Block.Transactions[1:] {
@@ -562,7 +699,7 @@ func (c *Controller) UpdateAndSendTemplate() {
 		var mC job.Container
 		mC, c.transactions = job.Get(
 			c.cx, util.NewBlock(msgB),
-			p2padvt.Get(c.cx), &c.coinbases,
+			p2padvt.Get(c.cx), c.multiConn.Salt(), &c.coinbases,
 		)
 		nH := mC.GetNewHeight()
 		if c.height.Load() < uint64(nH) {
@@ -570,8 +707,9 @@ func (c *Controller) UpdateAndSendTemplate() {
 			c.height.Store(uint64(nH))
 		}
 		shards := transport.GetShards(mC.Data)
+		c.bitses.Store(mC.GetBitses())
 		c.oldBlocks.Store(shards)
-		if err := c.multiConn.SendMany(job.Magic, shards); Check(err) {
+		if err := c.sendWork(job.Magic, shards); Check(err) {
 		}
 		c.prevHash.Store(&template.Block.Header.PrevBlock)
 		c.lastGenerated.Store(time.Now().UnixNano())
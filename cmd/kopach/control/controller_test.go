@@ -0,0 +1,150 @@
+package control
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/urfave/cli"
+
+	"github.com/p9c/pod/app/conte"
+	"github.com/p9c/pod/cmd/kopach/control/job"
+	"github.com/p9c/pod/cmd/kopach/control/p2padvt"
+	"github.com/p9c/pod/cmd/kopach/control/reg"
+	"github.com/p9c/pod/pkg/comm/transport"
+	"github.com/p9c/pod/pkg/pod"
+	qu "github.com/p9c/pod/pkg/util/quit"
+)
+
+const testKey = "controller test"
+
+// testController returns an active controller accepting unicast registrations on a loopback port. Its multicast
+// channel is stood in for by one sending to a local socket nothing reads. The returned function closes the sockets.
+func testController(t *testing.T, quit qu.C) (*Controller, func()) {
+	cfg, _ := pod.EmptyConfig()
+	*cfg.Listeners = cli.StringSlice{"127.0.0.1:11047"}
+	*cfg.RPCListeners = cli.StringSlice{"127.0.0.1:11048"}
+	*cfg.Controller = "127.0.0.1:11049"
+	c := &Controller{
+		quit:           quit,
+		cx:             &conte.Xt{Config: cfg},
+		unicastWorkers: make(map[string]*unicastWorker),
+	}
+	c.active.Store(true)
+	sink, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.multiConn, err = transport.NewDialChannel("controller", c, testKey, sink.LocalAddr().String(),
+		MaxDatagramSize, transport.Handlers{}, quit); err != nil {
+		sink.Close()
+		t.Fatal(err)
+	}
+	if c.uniConn, err = transport.NewListenerChannel("controller", c, testKey, "127.0.0.1:0", MaxDatagramSize,
+		handlersUnicast, quit); err != nil {
+		sink.Close()
+		c.multiConn.Sender.Close()
+		t.Fatal(err)
+	}
+	teardown := func() {
+		c.uniConn.Receiver.Close()
+		c.multiConn.Sender.Close()
+		sink.Close()
+	}
+	if err = c.uniConn.SetSalt(c.multiConn.Salt()); err != nil {
+		teardown()
+		t.Fatal(err)
+	}
+	return c, teardown
+}
+
+// receive returns the next message sent to the channel, failing the test if none arrives in time.
+func receive(t *testing.T, ch chan []byte, what string) []byte {
+	select {
+	case b := <-ch:
+		return b
+	case <-time.After(time.Second * 5):
+		t.Fatalf("%s was not received", what)
+	}
+	return nil
+}
+
+// TestUnicastRegistration checks that a miner registering with the controller is sent its advertisment, from which it
+// learns the salt jobs are sent with, and the current job, that it is then sent the work the controller sends out
+// until it times out, and that registering again as a heartbeat does not add it twice.
+func TestUnicastRegistration(t *testing.T) {
+	quit := qu.T()
+	defer quit.Q()
+	c, teardown := testController(t, quit)
+	defer teardown()
+	current := []byte("current job")
+	c.oldBlocks.Store(transport.GetShards(current))
+	adverts := make(chan []byte, 4)
+	jobs := make(chan []byte, 4)
+	var miner *transport.Channel
+	miner, err := transport.NewDialChannel("kopach", nil, testKey, c.uniConn.Receiver.LocalAddr().String(),
+		MaxDatagramSize, transport.Handlers{
+			string(p2padvt.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+				a := p2padvt.LoadContainer(b)
+				if err = miner.AddSalt(a.GetSalt()); err != nil {
+					return
+				}
+				adverts <- b
+				return
+			},
+			string(job.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+				jobs <- b
+				return
+			},
+		}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer miner.Sender.Close()
+	registration := transport.GetShards(reg.Get("miner").Data)
+	if err = miner.SendMany(reg.Magic, registration); err != nil {
+		t.Fatal(err)
+	}
+	a := p2padvt.LoadContainer(receive(t, adverts, "advertisment"))
+	if !bytes.Equal(a.GetSalt(), c.uniConn.Salt()) {
+		t.Fatalf("advertised salt %x, want %x", a.GetSalt(), c.uniConn.Salt())
+	}
+	if b := receive(t, jobs, "current job"); !bytes.Equal(b, current) {
+		t.Fatalf("job is %q, want %q", b, current)
+	}
+	c.workersMx.Lock()
+	if len(c.unicastWorkers) != 1 {
+		t.Errorf("%d miners registered, want 1", len(c.unicastWorkers))
+	}
+	for _, w := range c.unicastWorkers {
+		if w.id != "miner" {
+			t.Errorf("registered miner has ID %q, want %q", w.id, "miner")
+		}
+	}
+	c.workersMx.Unlock()
+	// a heartbeat is answered with the advertisment only
+	if err = miner.SendMany(reg.Magic, registration); err != nil {
+		t.Fatal(err)
+	}
+	receive(t, adverts, "advertisment")
+	next := []byte("next job")
+	if err = c.sendWork(job.Magic, transport.GetShards(next)); err != nil {
+		t.Fatal(err)
+	}
+	if b := receive(t, jobs, "next job"); !bytes.Equal(b, next) {
+		t.Fatalf("job is %q, want %q (the current job was sent again)", b, next)
+	}
+	c.workersMx.Lock()
+	if len(c.unicastWorkers) != 1 {
+		t.Errorf("%d miners registered after a heartbeat, want 1", len(c.unicastWorkers))
+	}
+	for _, w := range c.unicastWorkers {
+		w.lastSeen = time.Now().Add(-WorkerTimeout * 2)
+	}
+	c.workersMx.Unlock()
+	c.reapWorkers()
+	if len(c.unicastWorkers) != 0 {
+		t.Errorf("a miner that timed out is still registered")
+	}
+}
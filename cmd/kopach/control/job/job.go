@@ -13,6 +13,7 @@ import (
 	"github.com/p9c/pod/pkg/chain/wire"
 	"github.com/p9c/pod/pkg/coding/simplebuffer"
 	"github.com/p9c/pod/pkg/coding/simplebuffer/Bitses"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Bytes"
 	"github.com/p9c/pod/pkg/coding/simplebuffer/Hash"
 	"github.com/p9c/pod/pkg/coding/simplebuffer/Hashes"
 	"github.com/p9c/pod/pkg/coding/simplebuffer/IPs"
@@ -37,6 +38,7 @@ type Job struct {
 	Bitses          blockchain.TargetBits
 	Hashes          map[int32]*chainhash.Hash
 	CoinBases       map[int32]*util.Tx
+	Salt            []byte
 }
 
 // Get returns a message broadcast by a node and each field is decoded where possible avoiding memory allocation
@@ -44,8 +46,10 @@ type Job struct {
 // in FlatBuffers, we define a message type that instead of using a reflect based encoder, there is a creation function,
 // and a set of methods that extracts the individual requested field without copying memory, or deserialize their
 // contents which will be concurrent safe The varying coinbase payment values are in transaction 0 last output, the
-// individual varying transactions are stored separately and will be reassembled at the end
-func Get(cx *conte.Xt, mB *util.Block, msg simplebuffer.Serializers, cbs *map[int32]*util.Tx) (out Container, txr []*util.Tx) {
+// individual varying transactions are stored separately and will be reassembled at the end. The salt is that of the key
+// the controller sends with, which workers send their solutions with.
+func Get(cx *conte.Xt, mB *util.Block, msg simplebuffer.Serializers, salt []byte,
+	cbs *map[int32]*util.Tx) (out Container, txr []*util.Tx) {
 	// msg := append(Serializers{}, GetMessageBase(cx)...)
 	if txr == nil {
 		txr = []*util.Tx{}
@@ -118,6 +122,7 @@ func Get(cx *conte.Xt, mB *util.Block, msg simplebuffer.Serializers, cbs *map[in
 	mHashes := Hashes.NewHashes()
 	mHashes.Put(mTS)
 	msg = append(msg, mHashes)
+	msg = append(msg, Bytes.New().Put(salt))
 	// previously were sending blocks, no need for that really miner only needs
 	// valid block headers
 	// txs := mB.MsgBlock().Transactions
@@ -168,6 +173,11 @@ func (j *Container) GetHashes() (out map[int32]*chainhash.Hash) {
 	return Hashes.NewHashes().DecodeOne(j.Get(7)).Get()
 }
 
+// GetSalt returns the salt of the key the controller sends with
+func (j *Container) GetSalt() []byte {
+	return Bytes.New().DecodeOne(j.Get(8)).Get()
+}
+
 func (j *Container) String() (s string) {
 	s += fmt.Sprint("\ntype '"+string(Magic)+"' elements:", j.Count())
 	s += "\n"
@@ -231,6 +241,7 @@ func (j *Container) Struct() (out Job) {
 		PrevBlockHash:   j.GetPrevBlockHash(),
 		Bitses:          j.GetBitses(),
 		Hashes:          j.GetHashes(),
+		Salt:            j.GetSalt(),
 	}
 	return
 }
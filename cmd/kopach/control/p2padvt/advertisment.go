@@ -5,6 +5,7 @@ import (
 
 	"github.com/p9c/pod/app/conte"
 	"github.com/p9c/pod/pkg/coding/simplebuffer"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Bytes"
 	"github.com/p9c/pod/pkg/coding/simplebuffer/IPs"
 	"github.com/p9c/pod/pkg/coding/simplebuffer/Uint16"
 )
@@ -30,6 +31,12 @@ func Get(cx *conte.Xt) simplebuffer.Serializers {
 	}
 }
 
+// GetAdvertisment returns an advertisment that also carries the salt of the key the controller sends messages with, so
+// miners that receive it can read them
+func GetAdvertisment(cx *conte.Xt, salt []byte) simplebuffer.Serializers {
+	return append(Get(cx), Bytes.New().Put(salt))
+}
+
 func (j *Container) GetIPs() []*net.IP {
 	return IPs.New().DecodeOne(j.Get(0)).Get()
 }
@@ -45,3 +52,8 @@ func (j *Container) GetRPCListenersPort() uint16 {
 func (j *Container) GetControllerListenerPort() uint16 {
 	return Uint16.New().DecodeOne(j.Get(3)).Get()
 }
+
+// GetSalt returns the salt of the key the advertising controller sends messages with
+func (j *Container) GetSalt() []byte {
+	return Bytes.New().DecodeOne(j.Get(4)).Get()
+}
@@ -0,0 +1,54 @@
+// Package reg is a message type for Simplebuffers sent by kopach miners to a controller to register to be sent work by
+// unicast, for miners that can't receive the controller's multicast. Registrations are repeated as a heartbeat and a
+// controller stops sending work to a miner it has not heard from for a while.
+package reg
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/p9c/pod/pkg/coding/simplebuffer"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/String"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Time"
+)
+
+// Magic is the marker for packets containing a registration
+var Magic = []byte{'r', 'e', 'g', 'i'}
+
+type Container struct {
+	simplebuffer.Container
+}
+
+// Get returns a registration for the miner with the given ID
+func Get(id string) Container {
+	return Container{*simplebuffer.Serializers{
+		Time.New().Put(time.Now()),
+		String.New().Put(id),
+	}.CreateContainer(Magic)}
+}
+
+// LoadContainer takes a message byte slice payload and loads it into a container ready to be decoded
+func LoadContainer(b []byte) (out Container) {
+	out.Data = b
+	return
+}
+
+func (j *Container) GetTime() time.Time {
+	return Time.New().DecodeOne(j.Get(0)).Get()
+}
+
+func (j *Container) GetID() string {
+	return String.New().DecodeOne(j.Get(1)).Get()
+}
+
+func (j *Container) String() (s string) {
+	s += fmt.Sprint("\ntype '"+string(Magic)+"' elements:", j.Count())
+	s += "\n"
+	s += "1 Time: "
+	s += fmt.Sprint(j.GetTime())
+	s += "\n"
+	s += "2 ID: "
+	s += fmt.Sprint(j.GetID())
+	s += "\n"
+	return
+}
@@ -0,0 +1,77 @@
+// Package share is a message type for Simplebuffers sent by kopach miners to a controller for each hash they find that
+// meets the share target of its algorithm, a target ShareTargetShift bits easier than the block target. Shares let the
+// controller account for the work of each miner and algorithm far more often than solutions are found.
+package share
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/fork"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/coding/simplebuffer"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Block"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/String"
+	"github.com/p9c/pod/pkg/coding/simplebuffer/Time"
+)
+
+// Magic is the marker for packets containing a share
+var Magic = []byte{'s', 'h', 'a', 'r'}
+
+// ShareTargetShift is how many bits easier than the block target the share target is, so a miner finds about 256
+// shares for each block it would find
+const ShareTargetShift = 8
+
+type Container struct {
+	simplebuffer.Container
+}
+
+// Get returns a share found by the miner with the given ID. Only the header of the block is sent.
+func Get(id string, header *wire.BlockHeader) Container {
+	return Container{*simplebuffer.Serializers{
+		Time.New().Put(time.Now()),
+		String.New().Put(id),
+		Block.New().Put(&wire.MsgBlock{Header: *header}),
+	}.CreateContainer(Magic)}
+}
+
+// LoadContainer takes a message byte slice payload and loads it into a container ready to be decoded
+func LoadContainer(b []byte) (out Container) {
+	out.Data = b
+	return
+}
+
+func (j *Container) GetTime() time.Time {
+	return Time.New().DecodeOne(j.Get(0)).Get()
+}
+
+func (j *Container) GetID() string {
+	return String.New().DecodeOne(j.Get(1)).Get()
+}
+
+func (j *Container) GetHeader() *wire.BlockHeader {
+	return &Block.New().DecodeOne(j.Get(2)).Get().Header
+}
+
+func (j *Container) String() (s string) {
+	s += fmt.Sprint("\ntype '"+string(Magic)+"' elements:", j.Count())
+	s += "\n"
+	s += "1 Time: "
+	s += fmt.Sprint(j.GetTime())
+	s += "\n"
+	s += "2 ID: "
+	s += fmt.Sprint(j.GetID())
+	s += "\n"
+	h := j.GetHeader()
+	s += "3 Header: "
+	s += fmt.Sprintf("version %d prev %v merkle %v time %v bits %08x nonce %d", h.Version, h.PrevBlock, h.MerkleRoot,
+		h.Timestamp, h.Bits, h.Nonce)
+	s += "\n"
+	return
+}
+
+// Target returns the share target for the passed block target bits
+func Target(bits uint32) *big.Int {
+	return new(big.Int).Lsh(fork.CompactToBig(bits), ShareTargetShift)
+}
@@ -11,6 +11,7 @@ import (
 	
 	"github.com/p9c/pod/app/conte"
 	"github.com/p9c/pod/app/save"
+	"github.com/p9c/pod/cmd/kopach/control/account"
 	"github.com/p9c/pod/pkg/gui/f"
 	"github.com/p9c/pod/pkg/gui/fonts/p9fonts"
 	icons "github.com/p9c/pod/pkg/gui/ico/svg"
@@ -137,6 +138,9 @@ func (m *MinerModel) Widget(gtx l.Context) l.Dimensions {
 							Rigid(m.SetThreads).
 							Rigid(m.PreSharedKey).
 							Rigid(m.VSpacer).
+							Rigid(m.H5("algorithms").Fn).
+							Rigid(m.AlgoStats).
+							Rigid(m.VSpacer).
 							Rigid(m.H5("found blocks").Fn).
 							Rigid(
 								m.Fill(
@@ -427,6 +431,43 @@ func (m *MinerModel) BlockDetails(gtx l.Context) l.Dimensions {
 	).Fn(gtx)
 }
 
+// AlgoStats shows the hashrate and share counts the controller has recorded for this miner on each algorithm
+func (m *MinerModel) AlgoStats(gtx l.Context) l.Dimensions {
+	stats, _ := m.worker.algoStats.Load().([]account.Algo)
+	if len(stats) < 1 {
+		// the controller only reports to miners registered with it for unicast work
+		if *m.worker.cx.Config.ControllerConnect == "" {
+			return m.Inset(0.25, m.Body1("set a controller to connect to for reports").Fn).Fn(gtx)
+		}
+		return m.Inset(0.25, m.Body1("waiting for a report from the controller").Fn).Fn(gtx)
+	}
+	rows := m.VFlex()
+	for x := range stats {
+		s := stats[x]
+		rows = rows.Rigid(
+			m.Inset(
+				0.25,
+				m.Flex().
+					Rigid(
+						m.Body1(s.Algo).Font("plan9").Fn,
+					).
+					Flexed(
+						1,
+						m.Body1(
+							fmt.Sprintf(
+								"%.2f hash/s  %d accepted  %d stale  %d invalid",
+								s.HashesPerSec, s.Accepted, s.Stale, s.Invalid,
+							),
+						).
+							Alignment(text.End).
+							Fn,
+					).Fn,
+			).Fn,
+		)
+	}
+	return rows.Fn(gtx)
+}
+
 func (m *MinerModel) FoundBlocks(gtx l.Context) l.Dimensions {
 	var widgets []l.Widget
 	for x := range m.worker.solutions {
@@ -21,9 +21,12 @@ import (
 	"github.com/p9c/pod/app/conte"
 	"github.com/p9c/pod/cmd/kopach/client"
 	"github.com/p9c/pod/cmd/kopach/control"
+	"github.com/p9c/pod/cmd/kopach/control/account"
 	"github.com/p9c/pod/cmd/kopach/control/hashrate"
 	"github.com/p9c/pod/cmd/kopach/control/job"
+	"github.com/p9c/pod/cmd/kopach/control/p2padvt"
 	"github.com/p9c/pod/cmd/kopach/control/pause"
+	"github.com/p9c/pod/cmd/kopach/control/reg"
 	"github.com/p9c/pod/cmd/kopach/control/sol"
 	"github.com/p9c/pod/pkg/chain/fork"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
@@ -78,6 +81,8 @@ type Worker struct {
 	hashSampleBuf       *rav.BufferUint64
 	hashrate            float64
 	lastNonce           int32
+	// algoStats is the record of the work of this miner on each algorithm last reported by the controller
+	algoStats atomic.Value // []account.Algo
 }
 
 func (w *Worker) Start() {
@@ -95,6 +100,11 @@ func (w *Worker) Start() {
 		w.clients = append(w.clients, client.New(cmd.StdConn))
 	}
 	for i := range w.clients {
+		if *w.cx.Config.ControllerConnect != "" {
+			Debug("sending controller address to worker", i)
+			if err := w.clients[i].SetController(*w.cx.Config.ControllerConnect); Check(err) {
+			}
+		}
 		Debug("sending pass to worker", i)
 		err := w.clients[i].SendPass(*w.cx.Config.MinerPass)
 		if err != nil {
@@ -131,6 +141,24 @@ func (w *Worker) Stop() {
 	w.active.Store(false)
 }
 
+// register sends a registration to the controller every second for as long as kopach runs, so the controller sends
+// work to it by unicast and knows it is still alive. The controller learns the salt registrations are sent with from the
+// first one that reaches it.
+func (w *Worker) register() {
+	shards := transport.GetShards(reg.Get(w.id).Data)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		if err := w.conn.SendMany(reg.Magic, shards); Check(err) {
+		}
+		select {
+		case <-ticker.C:
+		case <-w.quit:
+			return
+		}
+	}
+}
+
 func Handle(cx *conte.Xt) func(c *cli.Context) error {
 	return func(c *cli.Context) (err error) {
 		Debug("miner controller starting")
@@ -138,8 +166,13 @@ func Handle(cx *conte.Xt) func(c *cli.Context) error {
 		randomBytes := make([]byte, 4)
 		if _, err = rand.Read(randomBytes); Check(err) {
 		}
+		// the host name lets the controller's accounting tell which machine a miner is running on
+		hostname, err := os.Hostname()
+		if Check(err) {
+			hostname = "kopach"
+		}
 		w := &Worker{
-			id: fmt.Sprintf("%x", randomBytes),
+			id: fmt.Sprintf("%s-%x", hostname, randomBytes),
 			cx: cx,
 			// ctx:           ctx,
 			quit:          cx.KillAll,
@@ -158,16 +191,30 @@ func Handle(cx *conte.Xt) func(c *cli.Context) error {
 		}
 		w.lastSent.Store(time.Now().UnixNano())
 		w.active.Store(false)
-		Debug("opening broadcast channel listener")
-		w.conn, err = transport.NewBroadcastChannel(
-			"kopachmain", w, *cx.Config.MinerPass,
-			transport.DefaultPort, control.MaxDatagramSize, handlers,
-			w.quit,
-		)
-		if err != nil {
-			Error(err)
-			// cancel()
-			return
+		if *cx.Config.ControllerConnect != "" {
+			Debug("opening unicast channel to controller", *cx.Config.ControllerConnect)
+			w.conn, err = transport.NewDialChannel(
+				"kopachmain", w, *cx.Config.MinerPass,
+				*cx.Config.ControllerConnect, control.MaxDatagramSize, handlers,
+				w.quit,
+			)
+			if err != nil {
+				Error(err)
+				return
+			}
+			go w.register()
+		} else {
+			Debug("opening broadcast channel listener")
+			w.conn, err = transport.NewBroadcastChannel(
+				"kopachmain", w, *cx.Config.MinerPass,
+				transport.DefaultPort, control.MaxDatagramSize, handlers,
+				w.quit,
+			)
+			if err != nil {
+				Error(err)
+				// cancel()
+				return
+			}
 		}
 		// start up the workers
 		if *cx.Config.Generate {
@@ -257,6 +304,22 @@ func Handle(cx *conte.Xt) func(c *cli.Context) error {
 
 // these are the handlers for specific message types.
 var handlers = transport.Handlers{
+	string(account.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+		w := ctx.(*Worker)
+		a := account.LoadContainer(b)
+		// reports are sent by unicast to the address the miner registered from, so one for another miner is stale
+		if a.GetID() != w.id {
+			return
+		}
+		w.algoStats.Store(a.GetAlgos())
+		if *w.cx.Config.KopachGUI {
+			select {
+			case w.Update <- struct{}{}:
+			default:
+			}
+		}
+		return
+	},
 	string(hashrate.HashrateMagic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
 		c := ctx.(*Worker)
 		if !c.active.Load() {
@@ -273,6 +336,16 @@ var handlers = transport.Handlers{
 		c.hashCount.Store(c.hashCount.Load() + uint64(count))
 		return
 	},
+	string(p2padvt.Magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+		w := ctx.(*Worker)
+		// jobs and the reports of workers are sent with the salt the controller advertises
+		a := p2padvt.LoadContainer(b)
+		if salt := a.GetSalt(); len(salt) > 0 {
+			if err = w.conn.AddSalt(salt); Check(err) {
+			}
+		}
+		return
+	},
 	string(job.Magic): func(
 		ctx interface{}, src net.Addr, dst string,
 		b []byte,
@@ -3,6 +3,7 @@ package worker
 import (
 	"crypto/cipher"
 	"errors"
+	"math/big"
 	qu "github.com/p9c/pod/pkg/util/quit"
 	"math/rand"
 	"net"
@@ -11,6 +12,7 @@ import (
 	"time"
 	
 	"github.com/p9c/pod/cmd/kopach/control/hashrate"
+	"github.com/p9c/pod/cmd/kopach/control/share"
 	"github.com/p9c/pod/cmd/kopach/control/sol"
 	blockchain "github.com/p9c/pod/pkg/chain"
 	"github.com/p9c/pod/pkg/chain/fork"
@@ -37,12 +39,14 @@ type Worker struct {
 	pipeConn      *stdconn.StdConn
 	dispatchConn  *transport.Channel
 	dispatchReady atomic.Bool
+	controller    atomic.String
 	ciph          cipher.AEAD
 	quit          qu.C
 	block         atomic.Value
 	senderPort    atomic.Uint32
 	msgBlock      atomic.Value // *wire.MsgBlock
 	bitses        atomic.Value
+	shareTargets  atomic.Value // map[int32]*big.Int
 	hashes        atomic.Value
 	lastMerkle    *chainhash.Hash
 	roller        *Counter
@@ -232,7 +236,8 @@ out:
 						// send out broadcast containing worker nonce and algorithm and count of blocks
 						w.hashCount.Store(w.hashCount.Load() + uint64(w.roller.RoundsPerAlgo.Load()))
 						nextAlgo = w.roller.C.Load() + 1
-						hashReport := hashrate.Get(w.roller.RoundsPerAlgo.Load(), nextAlgo, nH, w.id)
+						// the rounds just finished were all of the version of this one
+						hashReport := hashrate.Get(w.roller.RoundsPerAlgo.Load(), hv, nH, w.id)
 						err := w.dispatchConn.SendMany(
 							hashrate.HashrateMagic,
 							transport.GetShards(hashReport.Data),
@@ -243,6 +248,13 @@ out:
 					}
 					hash := mb.Header.BlockHashWithAlgos(nH)
 					bigHash := blockchain.HashToBig(&hash)
+					// hashes meeting the share target are reported so the controller can account for the work done
+					st, _ := w.shareTargets.Load().(map[int32]*big.Int)
+					if t, ok := st[hv]; ok && bigHash.Cmp(t) <= 0 {
+						shr := share.Get(w.id, &mb.Header)
+						if err := w.dispatchConn.SendMany(share.Magic, transport.GetShards(shr.Data)); Check(err) {
+						}
+					}
 					if bigHash.Cmp(fork.CompactToBig(mb.Header.Bits)) <= 0 {
 						Debug("found solution")
 						srs := sol.GetSolContainer(w.senderPort.Load(), mb)
@@ -287,7 +299,17 @@ func (w *Worker) NewJob(job *job.Container, reply *bool) (err error) {
 		return
 	}
 	j := job.Struct()
+	// reports, shares and solutions are sent with the key of the controller the job came from so it can read them
+	if len(j.Salt) > 0 {
+		if err = w.dispatchConn.SetSalt(j.Salt); Check(err) {
+		}
+	}
 	w.bitses.Store(j.Bitses)
+	shareTargets := make(map[int32]*big.Int, len(j.Bitses))
+	for v, bits := range j.Bitses {
+		shareTargets[v] = share.Target(bits)
+	}
+	w.shareTargets.Store(shareTargets)
 	w.hashes.Store(j.Hashes)
 	if j.Hashes[5].IsEqual(w.lastMerkle) {
 		Debug("not a new job")
@@ -366,6 +388,15 @@ func (w *Worker) Stop(_ int, reply *bool) (err error) {
 	return
 }
 
+// SetController sets the address of a controller to send solutions and hashrate reports to by unicast instead of
+// multicast, it takes effect when the dispatch password is next received
+func (w *Worker) SetController(addr string, reply *bool) (err error) {
+	Debug("receiving controller address", addr)
+	w.controller.Store(addr)
+	*reply = true
+	return
+}
+
 // SendPass gives the encryption key configured in the kopach controller ( pod) configuration to allow workers to
 // dispatch their solutions
 func (w *Worker) SendPass(pass string, reply *bool) (err error) {
@@ -374,15 +405,27 @@ func (w *Worker) SendPass(pass string, reply *bool) (err error) {
 	// sp := fmt.Sprint(rand.Intn(32767) + 1025)
 	// rp := fmt.Sprint(rand.Intn(32767) + 1025)
 	var conn *transport.Channel
-	conn, err = transport.NewBroadcastChannel(
-		"kopachworker",
-		w,
-		pass,
-		transport.DefaultPort,
-		control.MaxDatagramSize,
-		transport.Handlers{},
-		w.quit,
-	)
+	if controller := w.controller.Load(); controller != "" {
+		conn, err = transport.NewDialChannel(
+			"kopachworker",
+			w,
+			pass,
+			controller,
+			control.MaxDatagramSize,
+			transport.Handlers{},
+			w.quit,
+		)
+	} else {
+		conn, err = transport.NewBroadcastChannel(
+			"kopachworker",
+			w,
+			pass,
+			transport.DefaultPort,
+			control.MaxDatagramSize,
+			transport.Handlers{},
+			w.quit,
+		)
+	}
 	if err != nil {
 		Error(err)
 	}
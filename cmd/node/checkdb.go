@@ -0,0 +1,82 @@
+package node
+
+import (
+	"errors"
+
+	"github.com/p9c/pod/app/conte"
+	blockchain "github.com/p9c/pod/pkg/chain"
+	indexers "github.com/p9c/pod/pkg/chain/index"
+	"github.com/p9c/pod/pkg/util/interrupt"
+)
+
+// CheckDB checks that the chain state, utxo set, spend journal and index tips in the block database agree with the
+// blocks of the main chain and logs every discrepancy found. The utxo set is checked against the last checkBlocks
+// blocks, or the whole chain when it is not positive. When repair is set and the chain state is inconsistent, the
+// chain is rolled back to the last consistent height and its stored blocks are connected again, and indexes left with
+// a tip outside the main chain are dropped.
+func CheckDB(cx *conte.Xt, checkBlocks int32, repair bool) (err error) {
+	db, err := loadBlockDB(cx)
+	if err != nil {
+		Error(err)
+		return
+	}
+	defer func() {
+		if e := db.Close(); e != nil {
+			Error(e)
+		}
+	}()
+	// The chain is loaded without the indexes so they are neither caught up nor rolled back before being checked.
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		Interrupt:   interrupt.ShutdownRequestChan,
+		ChainParams: cx.ActiveNet,
+		TimeSource:  blockchain.NewMedianTime(),
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	best := chain.BestSnapshot()
+	Infof("checking the chain state at block %v (height %d)", best.Hash, best.Height)
+	result, err := chain.CheckDB(checkBlocks, interrupt.ShutdownRequestChan)
+	if err != nil {
+		Error(err)
+		return
+	}
+	for _, d := range result.Discrepancies {
+		Warnf("height %d block %v: %s", d.Height, d.Hash, d.Description)
+	}
+	Infof("checked the utxos of blocks %d to %d and found %d discrepancies", result.StartHeight, result.EndHeight,
+		len(result.Discrepancies))
+	tips, err := indexers.CheckTips(db, chain)
+	if err != nil {
+		Error(err)
+		return
+	}
+	for _, d := range tips {
+		Warnf("height %d block %v: %s", d.Height, d.Hash, d.Description)
+	}
+	if len(result.Discrepancies) == 0 && len(tips) == 0 {
+		Info("the chain state is consistent")
+		return
+	}
+	if !repair {
+		if len(result.Discrepancies) > 0 {
+			Warnf("the chain state is consistent up to height %d, run checkdb with --repair to roll back to it",
+				result.ConsistentHeight)
+		}
+		return
+	}
+	if len(result.Discrepancies) > 0 {
+		if result.ConsistentHeight < 0 {
+			return errors.New("no consistent height was found, the chain has to be reset with resetchain")
+		}
+		if err = chain.RollbackTo(result.ConsistentHeight); err != nil {
+			Error("failed to repair the chain state, it can be reset with resetchain:", err)
+			return
+		}
+		best = chain.BestSnapshot()
+		Infof("repaired the chain state, the best block is %v (height %d)", best.Hash, best.Height)
+	}
+	return indexers.DropOrphanedIndexes(db, chain, interrupt.ShutdownRequestChan)
+}
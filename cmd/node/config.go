@@ -14,6 +14,7 @@ import (
 
 	"github.com/p9c/pod/pkg/comm/peer"
 	// This ensures the database drivers get registered
+	_ "github.com/p9c/pod/pkg/db/boltdb"
 	_ "github.com/p9c/pod/pkg/db/ffldb"
 )
 
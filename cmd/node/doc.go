@@ -19,9 +19,14 @@ VERSION:
    v0.0.1
 
 COMMANDS:
-     dropaddrindex  drop the address search index
-     droptxindex    drop the address search index
-     dropcfindex    drop the address search index
+     dropaddrindex       drop the address search index
+     dropaddrutxoindex   drop the address utxo index
+     dropalgostatsindex  drop the algo stats index
+     droptxindex         drop the address search index
+     dropcfindex         drop the address search index
+     buildcfindex        build the committed filters of a type for the whole chain
+     checkdb             check the chain state and indexes in the database against the blocks
+     migratedb           copy the block database to a new database of another type
 
 GLOBAL OPTIONS:
    --help, -h  show help
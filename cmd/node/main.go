@@ -1,6 +1,7 @@
 package node
 
 import (
+	"errors"
 	"github.com/p9c/pod/pkg/util/logi"
 	qu "github.com/p9c/pod/pkg/util/quit"
 	"net"
@@ -15,11 +16,15 @@ import (
 	"github.com/p9c/pod/app/conte"
 	"github.com/p9c/pod/cmd/kopach/control"
 	"github.com/p9c/pod/cmd/node/path"
+	"github.com/p9c/pod/cmd/node/stratum"
 	"github.com/p9c/pod/cmd/node/version"
+	blockchain "github.com/p9c/pod/pkg/chain"
 	indexers "github.com/p9c/pod/pkg/chain/index"
+	"github.com/p9c/pod/pkg/chain/mining"
 	database "github.com/p9c/pod/pkg/db"
 	"github.com/p9c/pod/pkg/db/blockdb"
 	"github.com/p9c/pod/pkg/rpc/chainrpc"
+	"github.com/p9c/pod/pkg/util"
 	"github.com/p9c/pod/pkg/util/interrupt"
 )
 
@@ -108,6 +113,18 @@ func Main(cx *conte.Xt) (err error) {
 			return
 		}
 	}
+	if cx.StateCfg.DropAddrUtxoIndex {
+		Warn("dropping address utxo index")
+		if err = indexers.DropAddrUtxoIndex(db, interrupt.ShutdownRequestChan); Check(err) {
+			return
+		}
+	}
+	if cx.StateCfg.DropAlgoStatsIndex {
+		Warn("dropping algo stats index")
+		if err = indexers.DropAlgoStatsIndex(db, interrupt.ShutdownRequestChan); Check(err) {
+			return
+		}
+	}
 	if cx.StateCfg.DropTxIndex {
 		Warn("dropping transaction index")
 		if err = indexers.DropTxIndex(db, interrupt.ShutdownRequestChan); Check(err) {
@@ -148,6 +165,12 @@ func Main(cx *conte.Xt) (err error) {
 	}
 	// Debug("controller started")
 	// cx.Controller.Store(true)
+	var stratumServer *stratum.Server
+	if *cx.Config.Stratum != "" {
+		if stratumServer, err = startStratum(cx, server); Check(err) {
+			return
+		}
+	}
 	once := true
 	gracefulShutdown := func() {
 		if !once {
@@ -164,6 +187,10 @@ func Main(cx *conte.Xt) (err error) {
 		// }
 		Debug("stopping controller")
 		controlQuit.Q()
+		if stratumServer != nil {
+			Debug("stopping stratum server")
+			stratumServer.Stop()
+		}
 		Debug("stopping server")
 		e := server.Stop()
 		if e != nil {
@@ -211,6 +238,41 @@ func Main(cx *conte.Xt) (err error) {
 	return nil
 }
 
+// startStratum starts the Stratum mining server serving jobs built from block templates of the node, which are updated
+// whenever a block is connected to the main chain.
+func startStratum(cx *conte.Xt, server *chainrpc.Node) (s *stratum.Server, err error) {
+	var listeners map[string]string
+	if listeners, err = stratum.AlgoListeners(*cx.Config.Stratum); Check(err) {
+		return
+	}
+	bTG := control.GetBlkTemplateGenerator(cx)
+	s = stratum.New(&stratum.Config{
+		Listeners:   listeners,
+		ChainParams: cx.ActiveNet,
+		NewBlockTemplate: func(algo string) (*mining.BlockTemplate, error) {
+			template := control.GetNewBlockTemplate(cx, bTG, algo)
+			if template == nil {
+				return nil, errors.New("could not get block template for " + algo)
+			}
+			return template, nil
+		},
+		ProcessBlock: func(block *util.Block) (bool, error) {
+			return server.SyncManager.ProcessBlock(block, blockchain.BFNone)
+		},
+		IsCurrent: server.SyncManager.IsCurrent,
+		Password:  *cx.Config.StratumPass,
+	})
+	if err = s.Start(); Check(err) {
+		return
+	}
+	server.Chain.Subscribe(func(n *blockchain.Notification) {
+		if n.Type == blockchain.NTBlockConnected {
+			s.BlockConnected()
+		}
+	})
+	return
+}
+
 // loadBlockDB loads (or creates when needed) the block database taking into account the selected database backend and
 // returns a handle to it. It also additional logic such warning the user if there are multiple databases which consume
 // space on the file system and ensuring the regression test database is clean when in regression test mode.
@@ -288,7 +350,7 @@ func removeRegressionDB(cx *conte.Xt, dbPath string) error {
 func warnMultipleDBs(cx *conte.Xt) {
 	// This is intentionally not using the known db types which depend on the database types compiled into the binary
 	// since we want to detect legacy db types as well.
-	dbTypes := []string{"ffldb", "leveldb", "sqlite"}
+	dbTypes := []string{"ffldb", "boltdb", "leveldb", "sqlite"}
 	duplicateDbPaths := make([]string, 0, len(dbTypes)-1)
 	for _, dbType := range dbTypes {
 		if dbType == *cx.Config.DbType {
@@ -0,0 +1,45 @@
+package mempool
+
+import (
+	"fmt"
+
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// DefaultMaxAncestorCount is the default maximum number of transactions in the pool, including the transaction
+	// itself, that a transaction may depend on.
+	DefaultMaxAncestorCount = 25
+	// DefaultMaxAncestorSize is the default maximum total virtual size in bytes of a transaction together with the
+	// transactions in the pool that it depends on.
+	DefaultMaxAncestorSize = 101000
+)
+
+// checkAncestorLimits returns an error if the passed transaction together with the transactions in the pool that it
+// depends on exceeds the ancestor count or size limits of the policy. Bounding the ancestors keeps the cost of
+// assembling transaction packages for block templates and of evicting or replacing them in check. This function MUST
+// be called with the mempool lock held (for reads).
+func (mp *TxPool) checkAncestorLimits(tx *util.Tx) error {
+	maxCount, maxSize := mp.cfg.Policy.MaxAncestorCount, mp.cfg.Policy.MaxAncestorSize
+	if maxCount <= 0 && maxSize <= 0 {
+		return nil
+	}
+	ancestors := mp.txAncestors(tx)
+	count := len(ancestors) + 1
+	if maxCount > 0 && count > maxCount {
+		str := fmt.Sprintf("transaction %v has too many unconfirmed ancestors: max is %d, has %d",
+			tx.Hash(), maxCount, count)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+	size := GetTxVirtualSize(tx)
+	for _, ancestor := range ancestors {
+		size += GetTxVirtualSize(ancestor.Tx)
+	}
+	if maxSize > 0 && size > maxSize {
+		str := fmt.Sprintf("transaction %v has too large unconfirmed ancestors: max is %d bytes, has %d",
+			tx.Hash(), maxSize, size)
+		return txRuleError(wire.RejectNonstandard, str)
+	}
+	return nil
+}
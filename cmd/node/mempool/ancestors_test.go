@@ -0,0 +1,59 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+)
+
+// TestAncestorLimits ensures transactions are rejected when they would extend a chain of unconfirmed transactions in
+// the pool beyond the ancestor count or size limits.
+func TestAncestorLimits(t *testing.T) {
+	t.Parallel()
+	harness, outputs, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	const maxAncestors = 3
+	chainedTxns, err := harness.CreateTxChain(outputs[0], maxAncestors+1)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	var chainSize int64
+	for _, tx := range chainedTxns[:maxAncestors] {
+		chainSize += GetTxVirtualSize(tx)
+	}
+	// The last transaction in the chain has one ancestor too many.
+	harness.txPool.cfg.Policy.MaxAncestorCount = maxAncestors
+	for _, tx := range chainedTxns[:maxAncestors] {
+		_, err = harness.txPool.ProcessTransaction(nil, tx, false, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+	}
+	last := chainedTxns[maxAncestors]
+	_, err = harness.txPool.ProcessTransaction(nil, last, false, false, 0)
+	if err == nil {
+		t.Fatalf("ProcessTransaction: accepted tx exceeding the ancestor " +
+			"count limit")
+	}
+	testPoolMembership(tc, last, false, false)
+	// The chain is just within the count limit once it is raised, but the last transaction takes it over the size
+	// limit.
+	harness.txPool.cfg.Policy.MaxAncestorCount = maxAncestors + 1
+	harness.txPool.cfg.Policy.MaxAncestorSize = chainSize
+	_, err = harness.txPool.ProcessTransaction(nil, last, false, false, 0)
+	if err == nil {
+		t.Fatalf("ProcessTransaction: accepted tx exceeding the ancestor " +
+			"size limit")
+	}
+	testPoolMembership(tc, last, false, false)
+	harness.txPool.cfg.Policy.MaxAncestorSize = chainSize + GetTxVirtualSize(last)
+	_, err = harness.txPool.ProcessTransaction(nil, last, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx within the "+
+			"ancestor limits: %v", err)
+	}
+	testPoolMembership(tc, last, false, true)
+}
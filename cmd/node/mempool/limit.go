@@ -0,0 +1,180 @@
+package mempool
+
+import (
+	"container/heap"
+	"math"
+	"time"
+
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// DefaultMaxPoolSizeMB is the default maximum size of the main pool in megabytes.
+	DefaultMaxPoolSizeMB = 300
+	// rollingFeeHalfLife is the time it takes the dynamic minimum fee to halve while the pool is at least half full.
+	// It decays faster when the pool has more room.
+	rollingFeeHalfLife = time.Hour * 12
+)
+
+// MaxSize returns the maximum total serialized size in bytes of the transactions in the main pool. Zero means the
+// pool is unbounded. This is part of the mining.TxSource interface implementation and is safe for concurrent access.
+func (mp *TxPool) MaxSize() int64 {
+	return mp.cfg.Policy.MaxPoolSize
+}
+
+// MinFee returns the minimum fee in Satoshi/kB a new transaction must pay to be accepted into the pool. It is the
+// greater of the configured minimum relay fee and the dynamic fee floor that rises when transactions are evicted to
+// keep the pool within its maximum size. This is part of the mining.TxSource interface implementation and is safe for
+// concurrent access.
+func (mp *TxPool) MinFee() util.Amount {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+	rollingMinFee := util.Amount(mp.rollingMinFee(time.Now()))
+	if rollingMinFee > mp.cfg.Policy.MinRelayTxFee {
+		return rollingMinFee
+	}
+	return mp.cfg.Policy.MinRelayTxFee
+}
+
+// Size returns the total serialized size in bytes of the transactions in the main pool. It does not include the orphan
+// pool. This function is safe for concurrent access.
+func (mp *TxPool) Size() int64 {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+	return mp.totalSize
+}
+
+// bumpRollingMinFee raises the dynamic minimum fee above the passed fee rate in Satoshi/kB of an evicted package. This
+// function MUST be called with the mempool lock held (for writes).
+func (mp *TxPool) bumpRollingMinFee(evictedFeeRate float64, now time.Time) {
+	minFee := evictedFeeRate + float64(mp.cfg.Policy.MinRelayTxFee)
+	if current := mp.rollingMinFee(now); current > minFee {
+		minFee = current
+	}
+	mp.lastRollingMinFee = minFee
+	mp.lastRollingFeeUpdate = now
+}
+
+// rollingMinFee returns the dynamic minimum fee in Satoshi/kB at the passed time. The fee set by the last eviction
+// halves every rollingFeeHalfLife, or more quickly if the pool is less than half full, and drops to zero once it falls
+// below half the minimum relay fee. This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) rollingMinFee(now time.Time) float64 {
+	if mp.lastRollingMinFee == 0 {
+		return 0
+	}
+	halfLife := rollingFeeHalfLife
+	switch maxSize := mp.cfg.Policy.MaxPoolSize; {
+	case mp.totalSize < maxSize/4:
+		halfLife /= 4
+	case mp.totalSize < maxSize/2:
+		halfLife /= 2
+	}
+	elapsed := now.Sub(mp.lastRollingFeeUpdate)
+	minFee := mp.lastRollingMinFee * math.Pow(0.5, elapsed.Seconds()/halfLife.Seconds())
+	if minFee < float64(mp.cfg.Policy.MinRelayTxFee)/2 {
+		return 0
+	}
+	return minFee
+}
+
+// evictionEntry is a transaction of the main pool in the eviction queue, along with the fee rate in Satoshi/kB of its
+// package.
+type evictionEntry struct {
+	tx      *util.Tx
+	feeRate float64
+	index   int
+}
+
+// evictionQueue is a heap of the transactions of the main pool ordered by the fee rate of their packages, lowest first,
+// which lets the pool be trimmed without computing the package of every transaction in it. It implements
+// heap.Interface.
+type evictionQueue []*evictionEntry
+
+func (q evictionQueue) Len() int           { return len(q) }
+func (q evictionQueue) Less(i, j int) bool { return q[i].feeRate < q[j].feeRate }
+func (q evictionQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+func (q *evictionQueue) Push(x interface{}) {
+	entry := x.(*evictionEntry)
+	entry.index = len(*q)
+	*q = append(*q, entry)
+}
+func (q *evictionQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	entry := old[n-1]
+	entry.index = -1
+	old[n-1] = nil
+	*q = old[:n-1]
+	return entry
+}
+
+// packageFeeRate returns the fee rate in Satoshi/kB of the package of the passed transaction in the pool, which is the
+// transaction together with all of its descendants in the pool. This function MUST be called with the mempool lock
+// held (for reads).
+func (mp *TxPool) packageFeeRate(desc *TxDesc) float64 {
+	fees, size := desc.Fee, GetTxVirtualSize(desc.Tx)
+	for _, descendant := range mp.txDescendants(desc.Tx) {
+		fees += descendant.Fee
+		size += GetTxVirtualSize(descendant.Tx)
+	}
+	return float64(fees) * 1000 / float64(size)
+}
+
+// updateEvictionQueue brings the eviction queue up to date after the passed transaction was added to or removed from
+// the main pool. The transaction is queued or dropped from the queue, and the packages of its ancestors in the pool,
+// which it joined or left, are given their new fee rate. This function MUST be called with the mempool lock held (for
+// writes).
+func (mp *TxPool) updateEvictionQueue(tx *util.Tx) {
+	txHash := *tx.Hash()
+	entry, queued := mp.evictionEntries[txHash]
+	desc, inPool := mp.pool[txHash]
+	switch {
+	case inPool && !queued:
+		entry = &evictionEntry{tx: tx, feeRate: mp.packageFeeRate(desc)}
+		heap.Push(&mp.evictionQueue, entry)
+		mp.evictionEntries[txHash] = entry
+	case inPool:
+		entry.feeRate = mp.packageFeeRate(desc)
+		heap.Fix(&mp.evictionQueue, entry.index)
+	case queued:
+		heap.Remove(&mp.evictionQueue, entry.index)
+		delete(mp.evictionEntries, txHash)
+	}
+	for hash, ancestor := range mp.txAncestors(tx) {
+		if entry, ok := mp.evictionEntries[hash]; ok {
+			entry.feeRate = mp.packageFeeRate(ancestor)
+			heap.Fix(&mp.evictionQueue, entry.index)
+		}
+	}
+}
+
+// trimToSize evicts the transaction packages with the lowest fee rate until the main pool is no larger than its
+// maximum size. A package is a transaction together with all of its descendants in the pool, so evicting one never
+// leaves a transaction in the pool without its parents. The dynamic minimum fee is raised above the fee rate of the
+// evicted packages so they are not immediately replaced by transactions paying the same. This function MUST be called
+// with the mempool lock held (for writes).
+func (mp *TxPool) trimToSize() {
+	maxSize := mp.cfg.Policy.MaxPoolSize
+	if maxSize <= 0 || mp.totalSize <= maxSize {
+		return
+	}
+	var evicted int
+	var maxEvictedFeeRate float64
+	for mp.totalSize > maxSize && len(mp.evictionQueue) > 0 {
+		// Removing the package drops it from the queue and updates the packages of its ancestors.
+		lowest := mp.evictionQueue[0]
+		poolCount := len(mp.pool)
+		mp.removeTransaction(lowest.tx, true)
+		evicted += poolCount - len(mp.pool)
+		maxEvictedFeeRate = math.Max(maxEvictedFeeRate, lowest.feeRate)
+	}
+	mp.bumpRollingMinFee(maxEvictedFeeRate, time.Now())
+	Debugf(
+		"evicted %d transactions to keep the mempool within %d bytes, minimum fee is now %v",
+		evicted, maxSize, util.Amount(mp.lastRollingMinFee),
+	)
+}
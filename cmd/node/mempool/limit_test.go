@@ -0,0 +1,114 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// TestTrimToSize ensures that when the pool grows beyond its maximum size the package with the lowest fee rate is
+// evicted and the minimum fee rises so that the evicted transaction is not accepted again.
+func TestTrimToSize(t *testing.T) {
+	t.Parallel()
+	harness, outputs, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	// Split the spendable output so there are several independent outputs to spend.
+	const numChildren = 4
+	parent, err := harness.CreateSignedTx(outputs[:1], numChildren)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(nil, parent, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+	// Each child pays a higher fee than the one before it.
+	children := make([]*util.Tx, numChildren)
+	for i := range children {
+		input := txOutToSpendableOut(parent, uint32(i))
+		input.amount -= util.Amount(int64(i+1) * 10000)
+		children[i], err = harness.CreateSignedTx([]spendableOutput{input}, 1)
+		if err != nil {
+			t.Fatalf("unable to create transaction: %v", err)
+		}
+		_, err = harness.txPool.ProcessTransaction(nil, children[i], false,
+			false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+	}
+	// Shrink the pool just enough that one transaction has to go.
+	harness.txPool.mtx.Lock()
+	harness.txPool.cfg.Policy.MaxPoolSize = harness.txPool.totalSize - 1
+	harness.txPool.trimToSize()
+	harness.txPool.mtx.Unlock()
+	testPoolMembership(tc, children[0], false, false)
+	testPoolMembership(tc, parent, false, true)
+	for _, child := range children[1:] {
+		testPoolMembership(tc, child, false, true)
+	}
+	minRelayTxFee := harness.txPool.cfg.Policy.MinRelayTxFee
+	if minFee := harness.txPool.MinFee(); minFee <= minRelayTxFee {
+		t.Fatalf("minimum fee %v was not raised above the relay fee %v",
+			minFee, minRelayTxFee)
+	}
+	_, err = harness.txPool.ProcessTransaction(nil, children[0], false, false, 0)
+	if err == nil {
+		t.Fatalf("ProcessTransaction: accepted evicted tx %v",
+			children[0].Hash())
+	}
+}
+
+// checkEvictionQueue ensures the eviction queue holds every transaction of the pool once, with the fee rate of its
+// current package, in heap order.
+func checkEvictionQueue(t *testing.T, mp *TxPool) {
+	t.Helper()
+	if len(mp.evictionQueue) != len(mp.pool) || len(mp.evictionEntries) != len(mp.pool) {
+		t.Fatalf("eviction queue has %d entries and %d indexed for a pool of %d transactions",
+			len(mp.evictionQueue), len(mp.evictionEntries), len(mp.pool))
+	}
+	for i, entry := range mp.evictionQueue {
+		if entry.index != i || mp.evictionEntries[*entry.tx.Hash()] != entry {
+			t.Fatalf("eviction queue entry %d of %v is not indexed", i, entry.tx.Hash())
+		}
+		if want := mp.packageFeeRate(mp.pool[*entry.tx.Hash()]); entry.feeRate != want {
+			t.Fatalf("eviction queue has fee rate %v for %v, want %v", entry.feeRate, entry.tx.Hash(), want)
+		}
+		if i > 0 && mp.evictionQueue[(i-1)/2].feeRate > entry.feeRate {
+			t.Fatalf("eviction queue entry %d is out of heap order", i)
+		}
+	}
+}
+
+// TestEvictionQueue ensures the fee rates of the packages in the eviction queue follow transactions being added to and
+// removed from the pool.
+func TestEvictionQueue(t *testing.T) {
+	t.Parallel()
+	harness, outputs, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	const txChainLength = 5
+	chainedTxns, err := harness.CreateTxChain(outputs[0], txChainLength)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(nil, tx, true, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+		checkEvictionQueue(t, harness.txPool)
+	}
+	harness.txPool.RemoveTransaction(chainedTxns[txChainLength-1], false)
+	checkEvictionQueue(t, harness.txPool)
+	harness.txPool.RemoveTransaction(chainedTxns[1], true)
+	checkEvictionQueue(t, harness.txPool)
+	if len(harness.txPool.pool) != 1 {
+		t.Fatalf("pool has %d transactions, want 1", len(harness.txPool.pool))
+	}
+}
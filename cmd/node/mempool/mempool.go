@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -51,6 +52,9 @@ type Config struct {
 	// AddrIndex defines the optional address index instance to use for indexing the unconfirmed transactions in the
 	// memory pool. This can be nil if the address index is not enabled.
 	AddrIndex *indexers.AddrIndex
+	// AddrUtxoIndex defines the optional address utxo index instance to use for tracking the changes the unconfirmed
+	// transactions in the memory pool make to address balances. This can be nil if the index is not enabled.
+	AddrUtxoIndex *indexers.AddrUtxoIndex
 	// FeeEstimatator provides a feeEstimator. If it is not nil, the mempool records all new transactions it
 	// observes into the feeEstimator.
 	FeeEstimator *FeeEstimator
@@ -80,6 +84,22 @@ type Policy struct {
 	MaxSigOpCostPerTx int
 	// MinRelayTxFee defines the minimum transaction fee in DUO/kB to be considered a non-zero fee.
 	MinRelayTxFee util.Amount
+	// MaxPoolSize is the maximum total serialized size in bytes of the transactions in the main pool. When it is
+	// exceeded the packages with the lowest fee rate are evicted. Zero disables the limit.
+	MaxPoolSize int64
+	// RejectReplacement defines whether to reject transactions that spend the same outputs as transactions in the pool
+	// even when those transactions signal that they can be replaced.
+	RejectReplacement bool
+	// MaxAncestorCount is the maximum number of transactions in the pool, including the transaction itself, that a new
+	// transaction may depend on. Zero disables the limit.
+	MaxAncestorCount int
+	// MaxAncestorSize is the maximum total virtual size in bytes of a new transaction together with the transactions in
+	// the pool it depends on. Zero disables the limit.
+	MaxAncestorSize int64
+	// BlockPrioritySize is the size in bytes of the area of the block templates the node creates that is filled with
+	// high-priority transactions regardless of their fee. It is used to estimate the priority a transaction needs to be
+	// mined. Zero means blocks have no such area.
+	BlockPrioritySize int64
 }
 
 // Tag represents an identifier to use for tagging orphan transactions. The caller may choose any scheme it desires
@@ -106,6 +126,16 @@ type TxPool struct {
 	outpoints     map[wire.OutPoint]*util.Tx
 	pennyTotal    float64 // exponentially decaying total for penny spends.
 	lastPennyUnix int64   // unix time of last ``penny spend''
+	// totalSize is the total serialized size of the transactions in the main pool.
+	totalSize int64
+	// lastRollingMinFee is the dynamic minimum fee in Satoshi/kB as of lastRollingFeeUpdate. It is raised when
+	// transactions are evicted to keep the pool within its maximum size and decays over time.
+	lastRollingMinFee    float64
+	lastRollingFeeUpdate time.Time
+	// evictionQueue holds the transactions of the main pool ordered by the fee rate of their packages, and
+	// evictionEntries indexes its entries by transaction hash.
+	evictionQueue   evictionQueue
+	evictionEntries map[chainhash.Hash]*evictionEntry
 	// nextExpireScan is the time after which the orphan pool will be scanned in order to evict orphans. This is NOT
 	// a hard deadline as the scan will only run when an orphan is added to the pool as opposed to on an
 	// unconditional timer.
@@ -154,6 +184,43 @@ func (mp *TxPool) Count() int {
 	return count
 }
 
+// EstimatePriority returns the priority a transaction needs to be included in one of the next numBlocks blocks based
+// on the current contents of the pool. Transactions are ordered by their current priority and the priority of the
+// transaction that would overflow the high-priority area of numBlocks blocks is returned. When the pool does not hold
+// enough transactions to fill that area the minimum high priority is returned, and when blocks have no high-priority
+// area -1 is. This function is safe for concurrent access.
+func (mp *TxPool) EstimatePriority(numBlocks int64) float64 {
+	if mp.cfg.Policy.BlockPrioritySize <= 0 {
+		return -1.0
+	}
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+	type prioItem struct {
+		priority float64
+		size     int64
+	}
+	nextHeight := mp.cfg.BestHeight() + 1
+	items := make([]prioItem, 0, len(mp.pool))
+	for _, desc := range mp.pool {
+		items = append(items, prioItem{
+			priority: mp.currentPriority(desc.Tx, nextHeight),
+			size:     int64(desc.Tx.MsgTx().SerializeSize()),
+		})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].priority > items[j].priority
+	})
+	prioritySpace := numBlocks * mp.cfg.Policy.BlockPrioritySize
+	var used int64
+	for _, item := range items {
+		used += item.size
+		if used > prioritySpace {
+			return math.Max(item.priority, mining.MinHighPriority.ToDUO())
+		}
+	}
+	return mining.MinHighPriority.ToDUO()
+}
+
 // FetchTransaction returns the requested transaction from the transaction pool. This only fetches from the main
 // transaction pool and does not include orphans. This function is safe for concurrent access.
 func (mp *TxPool) FetchTransaction(txHash *chainhash.Hash) (*util.Tx, error) {
@@ -218,6 +285,57 @@ func (mp *TxPool) MaybeAcceptTransaction(b *blockchain.BlockChain,
 	return hashes, txD, err
 }
 
+// MempoolEntry returns the details of the transaction with the passed hash in the form used by the getmempoolentry RPC,
+// including the counts, sizes and fees of its in-pool ancestors and descendants. An error is returned if the
+// transaction is not in the main pool. This function is safe for concurrent access.
+func (mp *TxPool) MempoolEntry(txHash *chainhash.Hash) (*btcjson.GetMempoolEntryResult, error) {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+	desc, exists := mp.pool[*txHash]
+	if !exists {
+		return nil, fmt.Errorf("transaction is not in the pool")
+	}
+	tx := desc.Tx
+	size := int64(tx.MsgTx().SerializeSize())
+	entry := &btcjson.GetMempoolEntryResult{
+		Size:              int32(size),
+		Fee:               util.Amount(desc.Fee).ToDUO(),
+		ModifiedFee:       util.Amount(desc.Fee).ToDUO(),
+		Time:              desc.Added.Unix(),
+		Height:            int64(desc.Height),
+		StartingPriority:  desc.StartingPriority,
+		CurrentPriority:   mp.currentPriority(tx, mp.cfg.BestHeight()+1),
+		Depends:           make([]string, 0),
+		BIP125Replaceable: mp.signalsReplacement(tx, nil),
+	}
+	// The ancestor and descendant figures include the transaction itself.
+	ancestorCount, ancestorSize, ancestorFees := int64(1), size, desc.Fee
+	for _, ancestor := range mp.txAncestors(tx) {
+		ancestorCount++
+		ancestorSize += int64(ancestor.Tx.MsgTx().SerializeSize())
+		ancestorFees += ancestor.Fee
+	}
+	descendantCount, descendantSize, descendantFees := int64(1), size, desc.Fee
+	for _, descendant := range mp.txDescendants(tx) {
+		descendantCount++
+		descendantSize += int64(descendant.Tx.MsgTx().SerializeSize())
+		descendantFees += descendant.Fee
+	}
+	entry.AncestorCount = ancestorCount
+	entry.AncestorSize = ancestorSize
+	entry.AncestorFees = util.Amount(ancestorFees).ToDUO()
+	entry.DescendantCount = descendantCount
+	entry.DescendantSize = descendantSize
+	entry.DescendantFees = util.Amount(descendantFees).ToDUO()
+	for _, txIn := range tx.MsgTx().TxIn {
+		hash := &txIn.PreviousOutPoint.Hash
+		if mp.haveTransaction(hash) {
+			entry.Depends = append(entry.Depends, hash.String())
+		}
+	}
+	return entry, nil
+}
+
 // MiningDescs returns a slice of mining descriptors for all the transactions in the pool. This is part of the mining.
 // TxSource interface implementation and is safe for concurrent access as required by the interface contract.
 func (mp *TxPool) MiningDescs() []*mining.TxDesc {
@@ -300,12 +418,6 @@ func (mp *TxPool) RawMempoolVerbose() map[string]*btcjson.GetRawMempoolVerboseRe
 		// Calculate the current priority based on the inputs to the transaction. Use zero if one or more of the input
 		// transactions can't be found for some reason.
 		tx := desc.Tx
-		var currentPriority float64
-		utxos, err := mp.fetchInputUtxos(tx)
-		if err == nil {
-			currentPriority = mining.CalcPriority(tx.MsgTx(), utxos,
-				bestHeight+1)
-		}
 		mpd := &btcjson.GetRawMempoolVerboseResult{
 			Size:             int32(tx.MsgTx().SerializeSize()),
 			VSize:            int32(GetTxVirtualSize(tx)),
@@ -313,7 +425,7 @@ func (mp *TxPool) RawMempoolVerbose() map[string]*btcjson.GetRawMempoolVerboseRe
 			Time:             desc.Added.Unix(),
 			Height:           int64(desc.Height),
 			StartingPriority: desc.StartingPriority,
-			CurrentPriority:  currentPriority,
+			CurrentPriority:  mp.currentPriority(tx, bestHeight+1),
 			Depends:          make([]string, 0),
 		}
 		for _, txIn := range tx.MsgTx().TxIn {
@@ -451,14 +563,19 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, tx *util.Tx
 		StartingPriority: mining.CalcPriority(tx.MsgTx(), utxoView, height),
 	}
 	mp.pool[*tx.Hash()] = txD
+	mp.totalSize += int64(tx.MsgTx().SerializeSize())
 	for _, txIn := range tx.MsgTx().TxIn {
 		mp.outpoints[txIn.PreviousOutPoint] = tx
 	}
+	mp.updateEvictionQueue(tx)
 	atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
 	// Add unconfirmed address index entries associated with the transaction if enabled.
 	if mp.cfg.AddrIndex != nil {
 		mp.cfg.AddrIndex.AddUnconfirmedTx(tx, utxoView)
 	}
+	if mp.cfg.AddrUtxoIndex != nil {
+		mp.cfg.AddrUtxoIndex.AddUnconfirmedTx(tx, utxoView)
+	}
 	// Record this tx for fee estimation if enabled.
 	if mp.cfg.FeeEstimator != nil {
 		mp.cfg.FeeEstimator.ObserveTransaction(txD)
@@ -467,18 +584,36 @@ func (mp *TxPool) addTransaction(utxoView *blockchain.UtxoViewpoint, tx *util.Tx
 }
 
 // checkPoolDoubleSpend checks whether or not the passed transaction is attempting to spend coins already spent by other
-// transactions in the pool. Note it does not check for double spends against transactions already in the main chain.
-// This function MUST be called with the mempool lock held (for reads).
-func (mp *TxPool) checkPoolDoubleSpend(tx *util.Tx) error {
+// transactions in the pool. Spending the same coins is only allowed if every conflicting transaction signals that it
+// can be replaced and replacement is not disabled by policy, in which case true is returned to indicate the transaction
+// is a replacement. Note it does not check for double spends against transactions already in the main chain. This
+// function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) checkPoolDoubleSpend(tx *util.Tx) (isReplacement bool, err error) {
 	for _, txIn := range tx.MsgTx().TxIn {
-		if txR, exists := mp.outpoints[txIn.PreviousOutPoint]; exists {
+		txR, exists := mp.outpoints[txIn.PreviousOutPoint]
+		if !exists {
+			continue
+		}
+		if mp.cfg.Policy.RejectReplacement || !mp.signalsReplacement(txR, nil) {
 			str := fmt.Sprintf("output %v already spent by "+
 				"transaction %v in the memory pool",
 				txIn.PreviousOutPoint, txR.Hash())
-			return txRuleError(wire.RejectDuplicate, str)
+			return false, txRuleError(wire.RejectDuplicate, str)
 		}
+		isReplacement = true
 	}
-	return nil
+	return isReplacement, nil
+}
+
+// currentPriority returns the priority of the passed transaction if it were to be included in a block at the passed
+// height. Zero is returned if one or more of the input transactions can't be found for some reason. This function MUST
+// be called with the mempool lock held (for reads).
+func (mp *TxPool) currentPriority(tx *util.Tx, nextBlockHeight int32) float64 {
+	utxos, err := mp.fetchInputUtxos(tx)
+	if err != nil {
+		return 0
+	}
+	return mining.CalcPriority(tx.MsgTx(), utxos, nextBlockHeight)
 }
 
 // fetchInputUtxos loads utxo details about the input transactions referenced by the passed transaction. First it loads
@@ -646,11 +781,12 @@ func (mp *TxPool) maybeAcceptTransaction(b *blockchain.BlockChain, tx *util.Tx,
 		}
 	}
 	// The transaction may not use any of the same outputs as other transactions already in the pool as that would
-	// ultimately result in a double spend. This check is intended to be quick and therefore only detects double spends
-	// within the transaction pool itself. The transaction could still be double spending coins from the main chain at
-	// this point. There is a more in-depth check that happens later after fetching the referenced transaction inputs
-	// from the main chain which examines the actual spend data and prevents double spends.
-	err = mp.checkPoolDoubleSpend(tx)
+	// ultimately result in a double spend, unless it is a valid replacement for them. This check is intended to be
+	// quick and therefore only detects double spends within the transaction pool itself. The transaction could still
+	// be double spending coins from the main chain at this point. There is a more in-depth check that happens later
+	// after fetching the referenced transaction inputs from the main chain which examines the actual spend data and
+	// prevents double spends.
+	isReplacement, err := mp.checkPoolDoubleSpend(tx)
 	if err != nil {
 		Error(err)
 		return nil, nil, err
@@ -767,6 +903,17 @@ func (mp *TxPool) maybeAcceptTransaction(b *blockchain.BlockChain, tx *util.Tx,
 			txHash, txFee, minFee)
 		return nil, nil, txRuleError(wire.RejectInsufficientFee, str)
 	}
+	// While the pool is full, or recovering from having been full, new transactions must also pay the dynamic minimum
+	// fee. Transactions which are being added back to the memory pool from blocks that have been disconnected during a
+	// reorg are exempted.
+	if rollingMinFee := mp.rollingMinFee(time.Now()); isNew && rollingMinFee > 0 {
+		poolMinFee := calcMinRequiredTxRelayFee(serializedSize, util.Amount(rollingMinFee))
+		if txFee < poolMinFee {
+			str := fmt.Sprintf("transaction %v has %d fees which is under the mempool minimum fee of %d",
+				txHash, txFee, poolMinFee)
+			return nil, nil, txRuleError(wire.RejectInsufficientFee, str)
+		}
+	}
 	// Require that free transactions have sufficient priority to be mined in the next block. Transactions which are
 	// being added back to the memory pool from blocks that have been disconnected during a reorg are exempted.
 	if isNew && !mp.cfg.Policy.DisableRelayPriority && txFee < minFee {
@@ -802,6 +949,24 @@ func (mp *TxPool) maybeAcceptTransaction(b *blockchain.BlockChain, tx *util.Tx,
 			mp.cfg.Policy.FreeTxRelayLimit*10*1000,
 		)
 	}
+	// Don't allow chains of unconfirmed transactions to grow beyond the ancestor limits. Transactions which are being
+	// added back to the memory pool from blocks that have been disconnected during a reorg are exempted.
+	if isNew {
+		if err = mp.checkAncestorLimits(tx); err != nil {
+			Error(err)
+			return nil, nil, err
+		}
+	}
+	// A transaction replacing others in the pool must satisfy the replacement rules before it is worth the cost of
+	// verifying its signatures.
+	var replacedTxs map[chainhash.Hash]*TxDesc
+	if isReplacement {
+		replacedTxs, err = mp.validateReplacement(tx, txFee)
+		if err != nil {
+			Error(err)
+			return nil, nil, err
+		}
+	}
 	// Verify crypto signatures for each input and reject the transaction if any don't verify.
 	err = blockchain.ValidateTransactionScripts(b, tx, utxoView,
 		txscript.StandardVerifyFlags, mp.cfg.SigCache,
@@ -813,8 +978,20 @@ func (mp *TxPool) maybeAcceptTransaction(b *blockchain.BlockChain, tx *util.Tx,
 		}
 		return nil, nil, err
 	}
+	// Evict the transactions being replaced along with their descendants.
+	for _, replaced := range replacedTxs {
+		Debugf("replacing transaction %v with %v", replaced.Tx.Hash(), txHash)
+		mp.removeTransaction(replaced.Tx, true)
+	}
 	// Add to transaction pool.
 	txD := mp.addTransaction(utxoView, tx, bestHeight, txFee)
+	// Keep the pool within its maximum size. The new transaction itself may be among those evicted, in which case it
+	// is rejected.
+	mp.trimToSize()
+	if !mp.isTransactionInPool(txHash) {
+		str := fmt.Sprintf("transaction %v has too low a fee rate to enter the full mempool", txHash)
+		return nil, nil, txRuleError(wire.RejectInsufficientFee, str)
+	}
 	Debugf(
 		"accepted transaction %v (pool size: %v) %s",
 		txHash,
@@ -969,23 +1146,79 @@ func (mp *TxPool) removeTransaction(tx *util.Tx, removeRedeemers bool) {
 		if mp.cfg.AddrIndex != nil {
 			mp.cfg.AddrIndex.RemoveUnconfirmedTx(txHash)
 		}
+		if mp.cfg.AddrUtxoIndex != nil {
+			mp.cfg.AddrUtxoIndex.RemoveUnconfirmedTx(txHash)
+		}
 		// Mark the referenced outpoints as unspent by the pool.
 		for _, txIn := range txDesc.Tx.MsgTx().TxIn {
 			delete(mp.outpoints, txIn.PreviousOutPoint)
 		}
 		delete(mp.pool, *txHash)
+		mp.totalSize -= int64(txDesc.Tx.MsgTx().SerializeSize())
+		mp.updateEvictionQueue(txDesc.Tx)
 		atomic.StoreInt64(&mp.lastUpdated, time.Now().Unix())
 	}
 }
 
+// txAncestors returns the descriptors of all transactions in the pool that the passed transaction depends on, either
+// directly or through other transactions in the pool. This function MUST be called with the mempool lock held (for
+// reads).
+func (mp *TxPool) txAncestors(tx *util.Tx) map[chainhash.Hash]*TxDesc {
+	ancestors := make(map[chainhash.Hash]*TxDesc)
+	pending := []*util.Tx{tx}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for _, txIn := range next.MsgTx().TxIn {
+			parentHash := txIn.PreviousOutPoint.Hash
+			if _, seen := ancestors[parentHash]; seen {
+				continue
+			}
+			if parent, exists := mp.pool[parentHash]; exists {
+				ancestors[parentHash] = parent
+				pending = append(pending, parent.Tx)
+			}
+		}
+	}
+	return ancestors
+}
+
+// txDescendants returns the descriptors of all transactions in the pool that depend on the passed transaction, either
+// directly or through other transactions in the pool. This function MUST be called with the mempool lock held (for
+// reads).
+func (mp *TxPool) txDescendants(tx *util.Tx) map[chainhash.Hash]*TxDesc {
+	descendants := make(map[chainhash.Hash]*TxDesc)
+	pending := []*util.Tx{tx}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for i := range next.MsgTx().TxOut {
+			prevOut := wire.OutPoint{Hash: *next.Hash(), Index: uint32(i)}
+			redeemer, exists := mp.outpoints[prevOut]
+			if !exists {
+				continue
+			}
+			if _, seen := descendants[*redeemer.Hash()]; seen {
+				continue
+			}
+			if child, exists := mp.pool[*redeemer.Hash()]; exists {
+				descendants[*redeemer.Hash()] = child
+				pending = append(pending, redeemer)
+			}
+		}
+	}
+	return descendants
+}
+
 // New returns a new memory pool for validating and storing standalone transactions until they are mined into a block.
 func New(cfg *Config) *TxPool {
 	return &TxPool{
-		cfg:            *cfg,
-		pool:           make(map[chainhash.Hash]*TxDesc),
-		orphans:        make(map[chainhash.Hash]*orphanTx),
-		orphansByPrev:  make(map[wire.OutPoint]map[chainhash.Hash]*util.Tx),
-		nextExpireScan: time.Now().Add(orphanExpireScanInterval),
-		outpoints:      make(map[wire.OutPoint]*util.Tx),
+		cfg:             *cfg,
+		pool:            make(map[chainhash.Hash]*TxDesc),
+		orphans:         make(map[chainhash.Hash]*orphanTx),
+		orphansByPrev:   make(map[wire.OutPoint]map[chainhash.Hash]*util.Tx),
+		nextExpireScan:  time.Now().Add(orphanExpireScanInterval),
+		outpoints:       make(map[wire.OutPoint]*util.Tx),
+		evictionEntries: make(map[chainhash.Hash]*evictionEntry),
 	}
 }
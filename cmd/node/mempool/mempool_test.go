@@ -11,6 +11,7 @@ import (
 	blockchain "github.com/p9c/pod/pkg/chain"
 	"github.com/p9c/pod/pkg/chain/config/netparams"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/mining"
 	txscript "github.com/p9c/pod/pkg/chain/tx/script"
 	"github.com/p9c/pod/pkg/chain/wire"
 	ec "github.com/p9c/pod/pkg/coding/elliptic"
@@ -735,3 +736,68 @@ func TestCheckSpend(t *testing.T) {
 		t.Fatalf("Unexpeced spend found in pool: %v", spend)
 	}
 }
+
+// TestMempoolEntry ensures the ancestor and descendant figures reported for a transaction in the middle of an
+// unconfirmed chain are correct and that unknown transactions are rejected.
+func TestMempoolEntry(t *testing.T) {
+	t.Parallel()
+	harness, outputs, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	const txChainLength = 5
+	chainedTxns, err := harness.CreateTxChain(outputs[0], txChainLength)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(nil, tx, true,
+			false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept "+
+				"tx: %v", err)
+		}
+	}
+	// The third transaction has two ancestors and two descendants in the pool, and the counts include itself.
+	const mid = 2
+	entry, err := harness.txPool.MempoolEntry(chainedTxns[mid].Hash())
+	if err != nil {
+		t.Fatalf("MempoolEntry: unexpected error: %v", err)
+	}
+	if entry.AncestorCount != mid+1 {
+		t.Fatalf("unexpected ancestor count -- got %d, want %d",
+			entry.AncestorCount, mid+1)
+	}
+	if entry.DescendantCount != txChainLength-mid {
+		t.Fatalf("unexpected descendant count -- got %d, want %d",
+			entry.DescendantCount, txChainLength-mid)
+	}
+	if len(entry.Depends) != 1 ||
+		entry.Depends[0] != chainedTxns[mid-1].Hash().String() {
+		t.Fatalf("unexpected depends -- got %v, want [%v]",
+			entry.Depends, chainedTxns[mid-1].Hash())
+	}
+	// A transaction that is not in the pool must be rejected.
+	harness.txPool.RemoveTransaction(chainedTxns[txChainLength-1], false)
+	_, err = harness.txPool.MempoolEntry(chainedTxns[txChainLength-1].Hash())
+	if err == nil {
+		t.Fatalf("MempoolEntry: expected error for transaction not in pool")
+	}
+}
+
+// TestEstimatePriority ensures the priority estimate is taken from the configured high-priority area of blocks, and that
+// there is none when blocks have no such area.
+func TestEstimatePriority(t *testing.T) {
+	t.Parallel()
+	harness, _, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	if got := harness.txPool.EstimatePriority(1); got != -1.0 {
+		t.Fatalf("EstimatePriority without a high-priority area -- got %v, want -1", got)
+	}
+	harness.txPool.cfg.Policy.BlockPrioritySize = DefaultBlockPrioritySize
+	if got, want := harness.txPool.EstimatePriority(1), mining.MinHighPriority.ToDUO(); got != want {
+		t.Fatalf("EstimatePriority of an empty pool -- got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,242 @@
+package mempool
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// DumpFileName is the name of the file in the network data directory the memory pool is saved to on shutdown.
+	DumpFileName = "mempool.dat"
+	// dumpVersion is the version of the memory pool dump format.
+	dumpVersion = 1
+	// maxDumpEntries is the maximum number of transactions that will be read from a dump. It guards against allocating
+	// huge amounts of memory for a corrupt file.
+	maxDumpEntries = 1 << 20
+	// maxFeeEstimatorStateSize is the maximum size in bytes of the fee estimator state that will be read from a dump,
+	// which leaves room for the estimator to have observed a pool of maxDumpEntries transactions. It guards against
+	// allocating huge amounts of memory for a corrupt file.
+	maxFeeEstimatorStateSize = maxDumpEntries * 64
+)
+
+// DumpEntry is a transaction in a memory pool dump along with the metadata it had in the pool.
+type DumpEntry struct {
+	// Tx is the transaction.
+	Tx *util.Tx
+	// Added is the time the transaction was originally added to the pool.
+	Added time.Time
+	// Height is the block height when the transaction was originally added to the pool.
+	Height int32
+	// Fee is the total fee the transaction paid.
+	Fee int64
+}
+
+// Dump is a snapshot of the memory pool and the fee estimator that can be written to disk and restored after a restart.
+type Dump struct {
+	// FeeEstimator is the saved state of the fee estimator. It is nil if the pool has no fee estimator.
+	FeeEstimator FeeEstimatorState
+	// Entries are the transactions in the pool, ordered so that every transaction comes after the transactions in the
+	// pool it depends on.
+	Entries []DumpEntry
+}
+
+// Dump returns a snapshot of the transactions in the pool and the state of the fee estimator. This function is safe for
+// concurrent access.
+func (mp *TxPool) Dump() *Dump {
+	mp.mtx.RLock()
+	type dumpItem struct {
+		desc      *TxDesc
+		ancestors int
+	}
+	items := make([]dumpItem, 0, len(mp.pool))
+	for _, desc := range mp.pool {
+		items = append(items, dumpItem{desc: desc, ancestors: len(mp.txAncestors(desc.Tx))})
+	}
+	mp.mtx.RUnlock()
+	// A transaction always has more in-pool ancestors than any of its in-pool parents, so sorting by the number of
+	// ancestors places parents before their children. Ties are broken by the time they were added to keep the order
+	// stable.
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ancestors != items[j].ancestors {
+			return items[i].ancestors < items[j].ancestors
+		}
+		return items[i].desc.Added.Before(items[j].desc.Added)
+	})
+	dump := &Dump{Entries: make([]DumpEntry, len(items))}
+	for i, item := range items {
+		dump.Entries[i] = DumpEntry{
+			Tx:     item.desc.Tx,
+			Added:  item.desc.Added,
+			Height: item.desc.Height,
+			Fee:    item.desc.Fee,
+		}
+	}
+	if mp.cfg.FeeEstimator != nil {
+		dump.FeeEstimator = mp.cfg.FeeEstimator.Save()
+	}
+	return dump
+}
+
+// Restore re-validates every transaction in the dump and adds those that are still acceptable to the pool, keeping the
+// time and height at which they were originally added. Transactions that have since been mined, conflict with the
+// chain or are no longer standard are dropped. The number of transactions added to the pool is returned. This function
+// is safe for concurrent access.
+func (mp *TxPool) Restore(b *blockchain.BlockChain, dump *Dump) (restored int) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+	for _, entry := range dump.Entries {
+		missingParents, txD, err := mp.maybeAcceptTransaction(b, entry.Tx, false, false, true)
+		if err != nil {
+			Debug("dropping saved transaction", entry.Tx.Hash(), err)
+			continue
+		}
+		if len(missingParents) > 0 {
+			Debug("dropping saved transaction", entry.Tx.Hash(), "with missing parents")
+			continue
+		}
+		txD.Added = entry.Added
+		txD.Height = entry.Height
+		restored++
+	}
+	return
+}
+
+// SaveFile writes a dump of the pool and the fee estimator to the file at the passed path. The file is first written
+// to a temporary file that replaces the target once complete, so an interrupted save does not destroy an earlier dump.
+// This function is safe for concurrent access.
+func (mp *TxPool) SaveFile(path string) (err error) {
+	dump := mp.Dump()
+	tmpPath := path + ".new"
+	var f *os.File
+	if f, err = os.Create(tmpPath); err != nil {
+		Error(err)
+		return
+	}
+	w := bufio.NewWriter(f)
+	if err = dump.Serialize(w); err == nil {
+		err = w.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		Error(err)
+		_ = os.Remove(tmpPath)
+		return
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		Error(err)
+		return
+	}
+	Infof("saved %d mempool transactions to %s", len(dump.Entries), path)
+	return
+}
+
+// ReadDumpFile reads a memory pool dump from the file at the passed path.
+func ReadDumpFile(path string) (*Dump, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			Error(err)
+		}
+	}()
+	return DeserializeDump(bufio.NewReader(f))
+}
+
+// Serialize writes the dump to w in the versioned mempool dump format.
+func (d *Dump) Serialize(w io.Writer) (err error) {
+	if err = binary.Write(w, binary.BigEndian, uint32(dumpVersion)); err != nil {
+		return
+	}
+	if err = binary.Write(w, binary.BigEndian, uint32(len(d.FeeEstimator))); err != nil {
+		return
+	}
+	if _, err = w.Write(d.FeeEstimator); err != nil {
+		return
+	}
+	if err = binary.Write(w, binary.BigEndian, uint32(len(d.Entries))); err != nil {
+		return
+	}
+	for i := range d.Entries {
+		entry := &d.Entries[i]
+		if err = binary.Write(w, binary.BigEndian, entry.Added.Unix()); err != nil {
+			return
+		}
+		if err = binary.Write(w, binary.BigEndian, entry.Height); err != nil {
+			return
+		}
+		if err = binary.Write(w, binary.BigEndian, entry.Fee); err != nil {
+			return
+		}
+		if err = entry.Tx.MsgTx().Serialize(w); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// DeserializeDump reads a memory pool dump in the format written by Serialize from r.
+func DeserializeDump(r io.Reader) (*Dump, error) {
+	var version uint32
+	if err := binary.Read(r, binary.BigEndian, &version); err != nil {
+		return nil, err
+	}
+	if version != dumpVersion {
+		return nil, fmt.Errorf("incorrect mempool dump version: expected %d found %d", dumpVersion, version)
+	}
+	var stateLen uint32
+	if err := binary.Read(r, binary.BigEndian, &stateLen); err != nil {
+		return nil, err
+	}
+	if stateLen > maxFeeEstimatorStateSize {
+		return nil, fmt.Errorf("mempool dump has a fee estimator state of %d bytes, more than the maximum of %d",
+			stateLen, maxFeeEstimatorStateSize)
+	}
+	d := &Dump{}
+	if stateLen > 0 {
+		d.FeeEstimator = make(FeeEstimatorState, stateLen)
+		if _, err := io.ReadFull(r, d.FeeEstimator); err != nil {
+			return nil, err
+		}
+	}
+	var count uint32
+	if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+		return nil, err
+	}
+	if count > maxDumpEntries {
+		return nil, fmt.Errorf("mempool dump has too many entries: %d", count)
+	}
+	d.Entries = make([]DumpEntry, count)
+	for i := range d.Entries {
+		var added int64
+		entry := &d.Entries[i]
+		if err := binary.Read(r, binary.BigEndian, &added); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.BigEndian, &entry.Height); err != nil {
+			return nil, err
+		}
+		if err := binary.Read(r, binary.BigEndian, &entry.Fee); err != nil {
+			return nil, err
+		}
+		var msgTx wire.MsgTx
+		if err := msgTx.Deserialize(r); err != nil {
+			return nil, err
+		}
+		entry.Added = time.Unix(added, 0)
+		entry.Tx = util.NewTx(&msgTx)
+	}
+	return d, nil
+}
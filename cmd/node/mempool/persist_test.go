@@ -0,0 +1,95 @@
+package mempool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+)
+
+// TestDumpRoundTrip ensures a dump of the pool survives serialization and that restoring it re-adds every transaction
+// with the time it was originally added to the pool.
+func TestDumpRoundTrip(t *testing.T) {
+	t.Parallel()
+	harness, outputs, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	const txChainLength = 5
+	chainedTxns, err := harness.CreateTxChain(outputs[0], txChainLength)
+	if err != nil {
+		t.Fatalf("unable to create transaction chain: %v", err)
+	}
+	for _, tx := range chainedTxns {
+		_, err := harness.txPool.ProcessTransaction(nil, tx, true,
+			false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept "+
+				"tx: %v", err)
+		}
+	}
+	var buf bytes.Buffer
+	if err := harness.txPool.Dump().Serialize(&buf); err != nil {
+		t.Fatalf("Serialize: unexpected error: %v", err)
+	}
+	dump, err := DeserializeDump(&buf)
+	if err != nil {
+		t.Fatalf("DeserializeDump: unexpected error: %v", err)
+	}
+	if len(dump.Entries) != txChainLength {
+		t.Fatalf("unexpected number of entries -- got %d, want %d",
+			len(dump.Entries), txChainLength)
+	}
+	// Parents must be written before the transactions spending them.
+	for i, entry := range dump.Entries {
+		if *entry.Tx.Hash() != *chainedTxns[i].Hash() {
+			t.Fatalf("entry %d is %v, want %v", i, entry.Tx.Hash(),
+				chainedTxns[i].Hash())
+		}
+	}
+	// Empty the pool and restore it from the dump.
+	harness.txPool.RemoveTransaction(chainedTxns[0], true)
+	for _, tx := range chainedTxns {
+		testPoolMembership(tc, tx, false, false)
+	}
+	restored := harness.txPool.Restore(nil, dump)
+	if restored != txChainLength {
+		t.Fatalf("unexpected number of restored transactions -- got %d, "+
+			"want %d", restored, txChainLength)
+	}
+	for i, tx := range chainedTxns {
+		testPoolMembership(tc, tx, false, true)
+		desc := harness.txPool.pool[*tx.Hash()]
+		if !desc.Added.Equal(dump.Entries[i].Added) {
+			t.Fatalf("restored tx %v added at %v, want %v", tx.Hash(),
+				desc.Added, dump.Entries[i].Added)
+		}
+	}
+}
+
+// TestDeserializeDumpLimits ensures a dump claiming more fee estimator state or transactions than the limits is
+// rejected before anything is allocated for it.
+func TestDeserializeDumpLimits(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		stateLen uint32
+		count    uint32
+	}{
+		{"fee estimator state", maxFeeEstimatorStateSize + 1, 0},
+		{"entries", 0, maxDumpEntries + 1},
+	}
+	for _, test := range tests {
+		var buf bytes.Buffer
+		for _, v := range []uint32{dumpVersion, test.stateLen, test.count} {
+			if err := binary.Write(&buf, binary.BigEndian, v); err != nil {
+				t.Fatalf("unable to write dump: %v", err)
+			}
+		}
+		if _, err := DeserializeDump(&buf); err == nil {
+			t.Errorf("%s: DeserializeDump of a dump above the limit did not fail", test.name)
+		}
+	}
+}
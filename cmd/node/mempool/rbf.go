@@ -0,0 +1,119 @@
+package mempool
+
+import (
+	"fmt"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// MaxReplacementEvictions is the maximum number of transactions, including descendants, that a single replacement
+// transaction may evict from the pool.
+const MaxReplacementEvictions = 100
+
+// signalsReplacement returns whether the passed transaction can be replaced under the BIP125 policy. A transaction
+// signals replaceability explicitly if any of its inputs has a sequence number no greater than
+// txrules.MaxRBFSequence, and inherits it from any unconfirmed ancestor that signals it. The cache holds transactions
+// already found not to signal replacement and may be nil.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *TxPool) signalsReplacement(tx *util.Tx, cache map[chainhash.Hash]struct{}) bool {
+	if cache == nil {
+		cache = make(map[chainhash.Hash]struct{})
+	}
+	for _, txIn := range tx.MsgTx().TxIn {
+		if txIn.Sequence <= txrules.MaxRBFSequence {
+			return true
+		}
+		hash := txIn.PreviousOutPoint.Hash
+		parent, ok := mp.pool[hash]
+		if !ok {
+			continue
+		}
+		if _, ok := cache[hash]; ok {
+			continue
+		}
+		if mp.signalsReplacement(parent.Tx, cache) {
+			return true
+		}
+		cache[hash] = struct{}{}
+	}
+	return false
+}
+
+// txConflicts returns the transactions in the pool that spend the same outputs as the passed transaction along with
+// all of their descendants, which are the transactions that would be evicted if it replaced them. This function MUST
+// be called with the mempool lock held (for reads).
+func (mp *TxPool) txConflicts(tx *util.Tx) map[chainhash.Hash]*TxDesc {
+	conflicts := make(map[chainhash.Hash]*TxDesc)
+	for _, txIn := range tx.MsgTx().TxIn {
+		conflict, ok := mp.outpoints[txIn.PreviousOutPoint]
+		if !ok {
+			continue
+		}
+		conflicts[*conflict.Hash()] = mp.pool[*conflict.Hash()]
+		for hash, descendant := range mp.txDescendants(conflict) {
+			conflicts[hash] = descendant
+		}
+	}
+	return conflicts
+}
+
+// validateReplacement checks that the passed transaction, which pays the passed fee, is an acceptable replacement for
+// every transaction it conflicts with according to the BIP125 rules, and returns the transactions it replaces. The
+// replacement must not evict more than MaxReplacementEvictions transactions, must pay a higher fee rate than each of
+// them, must pay at least their total fee plus the minimum relay fee for its own size, and must not spend any
+// unconfirmed outputs the replaced transactions did not already spend. This function MUST be called with the mempool
+// lock held (for reads).
+func (mp *TxPool) validateReplacement(tx *util.Tx, txFee int64) (map[chainhash.Hash]*TxDesc, error) {
+	txHash := tx.Hash()
+	conflicts := mp.txConflicts(tx)
+	if len(conflicts) > MaxReplacementEvictions {
+		str := fmt.Sprintf("replacement transaction %v evicts more transactions than permitted: max is %d, evicts %d",
+			txHash, MaxReplacementEvictions, len(conflicts))
+		return nil, txRuleError(wire.RejectNonstandard, str)
+	}
+	// A replacement spending one of the transactions it replaces would be spending an output that no longer exists.
+	for ancestorHash := range mp.txAncestors(tx) {
+		if _, ok := conflicts[ancestorHash]; ok {
+			str := fmt.Sprintf("replacement transaction %v spends parent transaction %v", txHash, ancestorHash)
+			return nil, txRuleError(wire.RejectInvalid, str)
+		}
+	}
+	txSize := GetTxVirtualSize(tx)
+	txFeePerKB := txFee * 1000 / txSize
+	var conflictsFee int64
+	conflictsParents := make(map[chainhash.Hash]struct{})
+	for _, conflict := range conflicts {
+		if txFeePerKB <= conflict.FeePerKB {
+			str := fmt.Sprintf("replacement transaction %v has an insufficient fee rate: needs more than %d, has %d",
+				txHash, conflict.FeePerKB, txFeePerKB)
+			return nil, txRuleError(wire.RejectInsufficientFee, str)
+		}
+		conflictsFee += conflict.Fee
+		for _, txIn := range conflict.Tx.MsgTx().TxIn {
+			conflictsParents[txIn.PreviousOutPoint.Hash] = struct{}{}
+		}
+	}
+	// The replacement also has to pay for the bandwidth used to relay it.
+	minFee := conflictsFee + calcMinRequiredTxRelayFee(txSize, mp.cfg.Policy.MinRelayTxFee)
+	if txFee < minFee {
+		str := fmt.Sprintf("replacement transaction %v has an insufficient absolute fee: needs %d, has %d",
+			txHash, minFee, txFee)
+		return nil, txRuleError(wire.RejectInsufficientFee, str)
+	}
+	for _, txIn := range tx.MsgTx().TxIn {
+		parentHash := txIn.PreviousOutPoint.Hash
+		if _, ok := conflictsParents[parentHash]; ok {
+			continue
+		}
+		if _, ok := mp.pool[parentHash]; ok {
+			str := fmt.Sprintf("replacement transaction %v spends new unconfirmed input %v not found in conflicting "+
+				"transactions", txHash, txIn.PreviousOutPoint)
+			return nil, txRuleError(wire.RejectNonstandard, str)
+		}
+	}
+	return conflicts, nil
+}
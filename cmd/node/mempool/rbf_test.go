@@ -0,0 +1,111 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// createTxWithSequence creates a signed transaction spending the provided output to a single output to the payment
+// script associated with the harness, paying the provided fee and using the provided input sequence number.
+func (p *poolHarness) createTxWithSequence(input spendableOutput, fee util.Amount, sequence uint32) (*util.Tx,
+	error) {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: input.outPoint,
+		Sequence:         sequence,
+	})
+	tx.AddTxOut(&wire.TxOut{
+		PkScript: p.payScript,
+		Value:    int64(input.amount - fee),
+	})
+	sigScript, err := txscript.SignatureScript(tx, 0, p.payScript,
+		txscript.SigHashAll, p.signKey, true)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	tx.TxIn[0].SignatureScript = sigScript
+	return util.NewTx(tx), nil
+}
+
+// TestReplaceByFee ensures transactions spending the same outputs as transactions in the pool are only accepted when
+// the transactions they conflict with signal replaceability and the replacement pays enough, and that accepting a
+// replacement evicts the transactions it conflicts with along with their descendants.
+func TestReplaceByFee(t *testing.T) {
+	t.Parallel()
+	harness, outputs, err := newPoolHarness(&netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create test pool: %v", err)
+	}
+	tc := &testContext{t, harness}
+	// A transaction that does not signal replaceability can't be replaced.
+	final, err := harness.createTxWithSequence(outputs[0], 1000, wire.MaxTxInSequenceNum)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(nil, final, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+	}
+	replacement, err := harness.createTxWithSequence(outputs[0], 5000, wire.MaxTxInSequenceNum)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(nil, replacement, false, false, 0)
+	if err == nil {
+		t.Fatalf("ProcessTransaction: accepted replacement of a " +
+			"transaction that does not signal replaceability")
+	}
+	testPoolMembership(tc, final, false, true)
+	testPoolMembership(tc, replacement, false, false)
+	harness.txPool.RemoveTransaction(final, true)
+	// Add a replaceable transaction along with a child spending it.
+	original, err := harness.createTxWithSequence(outputs[0], 1000, txrules.MaxRBFSequence)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	child, err := harness.CreateSignedTx(
+		[]spendableOutput{txOutToSpendableOut(original, 0)}, 1)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	for _, tx := range []*util.Tx{original, child} {
+		_, err = harness.txPool.ProcessTransaction(nil, tx, false, false, 0)
+		if err != nil {
+			t.Fatalf("ProcessTransaction: failed to accept tx: %v", err)
+		}
+	}
+	// A replacement that does not pay for its own relay on top of the fees of the transactions it replaces is
+	// rejected.
+	cheap, err := harness.createTxWithSequence(outputs[0], 1100, txrules.MaxRBFSequence)
+	if err != nil {
+		t.Fatalf("unable to create transaction: %v", err)
+	}
+	_, err = harness.txPool.ProcessTransaction(nil, cheap, false, false, 0)
+	if err == nil {
+		t.Fatalf("ProcessTransaction: accepted replacement with an " +
+			"insufficient fee")
+	}
+	testPoolMembership(tc, cheap, false, false)
+	// Replacement is refused entirely when disabled by policy.
+	harness.txPool.cfg.Policy.RejectReplacement = true
+	_, err = harness.txPool.ProcessTransaction(nil, replacement, false, false, 0)
+	if err == nil {
+		t.Fatalf("ProcessTransaction: accepted replacement while " +
+			"replacement is disabled")
+	}
+	harness.txPool.cfg.Policy.RejectReplacement = false
+	// A replacement paying enough evicts both the original and its child.
+	_, err = harness.txPool.ProcessTransaction(nil, replacement, false, false, 0)
+	if err != nil {
+		t.Fatalf("ProcessTransaction: failed to accept replacement: %v", err)
+	}
+	testPoolMembership(tc, replacement, false, true)
+	testPoolMembership(tc, original, false, false)
+	testPoolMembership(tc, child, false, false)
+}
@@ -0,0 +1,234 @@
+package node
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/p9c/pod/app/conte"
+	"github.com/p9c/pod/cmd/node/path"
+	blockchain "github.com/p9c/pod/pkg/chain"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/db/blockdb"
+	"github.com/p9c/pod/pkg/util"
+	"github.com/p9c/pod/pkg/util/interrupt"
+)
+
+const (
+	// migrateKeyBatch is how many metadata keys are written to the target database in each transaction.
+	migrateKeyBatch = 10000
+	// migrateBlockBatch is how many blocks are written to the target database in each transaction.
+	migrateBlockBatch = 100
+)
+
+// errMigrateInterrupted is returned when a migration is stopped by an interrupt.
+var errMigrateInterrupted = errors.New("block database migration interrupted")
+
+// MigrateDB copies the block database of the configured database type to a new block database of the passed type, which
+// the node uses once it is started with that database type. The metadata, which holds the chain state and the indexes,
+// is copied key by key, and the blocks whose data is stored are copied in ascending order of height. A database that
+// blocks were pruned from can not be migrated, as the target could not tell which blocks are missing. The target
+// database is removed again if the migration fails.
+func MigrateDB(cx *conte.Xt, toType string) (err error) {
+	fromType := *cx.Config.DbType
+	if toType == fromType {
+		return fmt.Errorf("the block database is already of type %s", toType)
+	}
+	if toType == "memdb" || !ValidDbType(toType) {
+		return fmt.Errorf("cannot migrate to database type %q, the supported types are %v", toType, KnownDbTypes)
+	}
+	srcPath := path.BlockDb(cx, fromType, blockdb.NamePrefix)
+	src, err := database.Open(fromType, srcPath, cx.ActiveNet.Net)
+	if err != nil {
+		Error(err)
+		return
+	}
+	defer func() {
+		if e := src.Close(); e != nil {
+			Error(e)
+		}
+	}()
+	pruned, err := src.BeenPruned()
+	if err != nil {
+		Error(err)
+		return
+	}
+	if pruned {
+		return errors.New("blocks were pruned from the block database, so it cannot be migrated")
+	}
+	// The chain is loaded without the indexes so only its block index is read to find the stored blocks.
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          src,
+		Interrupt:   interrupt.ShutdownRequestChan,
+		ChainParams: cx.ActiveNet,
+		TimeSource:  blockchain.NewMedianTime(),
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	dstPath := path.BlockDb(cx, toType, blockdb.NamePrefix)
+	dst, err := database.Create(toType, dstPath, cx.ActiveNet.Net)
+	if err != nil {
+		Error(err)
+		return
+	}
+	defer func() {
+		if e := dst.Close(); e != nil {
+			Error(e)
+		}
+		if err != nil {
+			Warn("removing the incomplete block database", dstPath)
+			if e := os.RemoveAll(dstPath); e != nil {
+				Error(e)
+			}
+		}
+	}()
+	Infof("migrating the block database '%s' to '%s'", srcPath, dstPath)
+	// Drivers keep their own entries in the metadata under names prefixed by their type, as ffldb does with its block
+	// index and write cursor, and these are not copied.
+	var keys uint64
+	err = src.View(func(tx database.Tx) error {
+		return copyBucket(dst, tx.Metadata(), nil, []byte(fromType+"-"), &keys)
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	Infof("copied %d metadata keys", keys)
+	hashes := chain.Index.StoredBlocks()
+	for start := 0; start < len(hashes); start += migrateBlockBatch {
+		if interrupt.Requested() {
+			return errMigrateInterrupted
+		}
+		end := start + migrateBlockBatch
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+		var blocks []*util.Block
+		err = src.View(func(tx database.Tx) error {
+			for i := start; i < end; i++ {
+				blockBytes, err := tx.FetchBlock(&hashes[i])
+				if err != nil {
+					return err
+				}
+				block, err := util.NewBlockFromBytes(blockBytes)
+				if err != nil {
+					return err
+				}
+				blocks = append(blocks, block)
+			}
+			return nil
+		})
+		if err != nil {
+			Error(err)
+			return
+		}
+		err = dst.Update(func(tx database.Tx) error {
+			for _, block := range blocks {
+				if err := tx.StoreBlock(block); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			Error(err)
+			return
+		}
+		if end%(migrateBlockBatch*100) == 0 {
+			Infof("copied %d of %d blocks", end, len(hashes))
+		}
+	}
+	Infof("migrated %d metadata keys and %d blocks, start the node with --dbtype=%s to use the new block database",
+		keys, len(hashes), toType)
+	return nil
+}
+
+// bucketAt returns the bucket at the passed path of nested bucket names under the metadata bucket of a transaction.
+func bucketAt(tx database.Tx, path [][]byte) database.Bucket {
+	bucket := tx.Metadata()
+	for _, name := range path {
+		bucket = bucket.Bucket(name)
+	}
+	return bucket
+}
+
+// copyBucket copies the keys and nested buckets of a source bucket to the bucket at the passed path in the target
+// database, writing the keys in batches so the transactions of the target stay small. The keys and buckets of the
+// metadata bucket itself whose names start with skipPrefix are not copied. The number of keys copied is added to
+// copied.
+func copyBucket(dst database.DB, src database.Bucket, path [][]byte, skipPrefix []byte, copied *uint64) error {
+	skip := func(name []byte) bool {
+		return len(path) == 0 && bytes.HasPrefix(name, skipPrefix)
+	}
+	var keys, values [][]byte
+	flush := func() error {
+		if interrupt.Requested() {
+			return errMigrateInterrupted
+		}
+		err := dst.Update(func(tx database.Tx) error {
+			bucket := bucketAt(tx, path)
+			for i := range keys {
+				if err := bucket.Put(keys[i], values[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		*copied += uint64(len(keys))
+		if *copied%(migrateKeyBatch*100) == 0 {
+			Infof("copied %d metadata keys", *copied)
+		}
+		keys, values = keys[:0], values[:0]
+		return nil
+	}
+	// The keys and values stay valid while the source transaction is open, so they need not be copied.
+	err := src.ForEach(func(k, v []byte) error {
+		if skip(k) {
+			return nil
+		}
+		keys = append(keys, k)
+		values = append(values, v)
+		if len(keys) == migrateKeyBatch {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(keys) > 0 {
+		if err = flush(); err != nil {
+			return err
+		}
+	}
+	var nested [][]byte
+	err = src.ForEachBucket(func(k []byte) error {
+		if !skip(k) {
+			nested = append(nested, k)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, name := range nested {
+		err = dst.Update(func(tx database.Tx) error {
+			_, err := bucketAt(tx, path).CreateBucket(name)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		childPath := append(append([][]byte{}, path...), name)
+		if err = copyBucket(dst, src.Bucket(name), childPath, skipPrefix, copied); err != nil {
+			return err
+		}
+	}
+	return nil
+}
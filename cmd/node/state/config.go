@@ -5,6 +5,7 @@ import (
 	"time"
 
 	chaincfg "github.com/p9c/pod/pkg/chain/config"
+	"github.com/p9c/pod/pkg/chain/wire"
 	"github.com/p9c/pod/pkg/util"
 )
 
@@ -14,11 +15,16 @@ type Config struct {
 	Oniondial           func(string, string, time.Duration) (net.Conn, error)
 	Dial                func(string, string, time.Duration) (net.Conn, error)
 	AddedCheckpoints    []chaincfg.Checkpoint
+	AssumeValid         *chaincfg.Checkpoint
 	ActiveMiningAddrs   []util.Address
 	ActiveMinerKey      []byte
 	ActiveMinRelayTxFee util.Amount
 	ActiveWhitelists    []*net.IPNet
+	BuildCfIndex        bool
+	BuildCfIndexType    wire.FilterType
 	DropAddrIndex       bool
+	DropAddrUtxoIndex   bool
+	DropAlgoStatsIndex  bool
 	DropTxIndex         bool
 	DropCfIndex         bool
 	Save                bool
@@ -0,0 +1,301 @@
+package stratum
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"net"
+	"sync"
+	"time"
+)
+
+const (
+	// maxMessageSize is the largest message a miner may send.
+	maxMessageSize = 4096
+	// idleTimeout is how long a connection may go without sending anything before it is dropped.
+	idleTimeout = time.Minute * 10
+	// maxRetargetFactor bounds how much a single vardiff retarget may change the share difficulty.
+	maxRetargetFactor = 4
+)
+
+// The error codes used in responses, as used by other Stratum servers.
+const (
+	errOther          = 20
+	errJobNotFound    = 21
+	errDuplicateShare = 22
+	errLowDifficulty  = 23
+	errUnauthorized   = 24
+	errNotSubscribed  = 25
+)
+
+// stratumError is an error returned to a miner in a response, which is encoded as [code, message, null].
+type stratumError struct {
+	code    int
+	message string
+}
+
+// newError returns a stratumError with the passed code and message.
+func newError(code int, message string) *stratumError {
+	return &stratumError{code: code, message: message}
+}
+
+// Error returns the message of the error. It is part of the error interface implementation.
+func (e *stratumError) Error() string {
+	return e.message
+}
+
+// MarshalJSON encodes the error in the form Stratum uses.
+func (e *stratumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.code, e.message, nil})
+}
+
+// request is a message sent by a miner.
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// response is the reply to a request.
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  *stratumError   `json:"error"`
+}
+
+// notification is a message sent to a miner that is not a reply to a request.
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// client is a connection from a miner.
+type client struct {
+	server         *Server
+	conn           net.Conn
+	algo           string
+	extraNonce1    []byte
+	subscriptionID string
+	writeMtx       sync.Mutex
+	// mtx guards the following fields, as jobs are sent to the miner from other goroutines than the one serving it.
+	mtx        sync.Mutex
+	subscribed bool
+	authorized bool
+	worker     string
+	// difficulty is the current share difficulty and prevDiff the one in force before the last job was sent, which
+	// shares are also accepted at so that work in flight when the difficulty rises is not lost.
+	difficulty   float64
+	prevDiff     float64
+	lastRetarget time.Time
+	shares       int
+}
+
+// serve reads and handles the requests of the miner until the connection is closed.
+func (c *client) serve() {
+	defer c.close()
+	Debug("stratum", c.algo, "miner connected from", c.conn.RemoteAddr())
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, maxMessageSize), maxMessageSize)
+	for {
+		if err := c.conn.SetReadDeadline(time.Now().Add(idleTimeout)); Check(err) {
+			return
+		}
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				Debug("stratum miner", c.conn.RemoteAddr(), "disconnected:", err)
+			}
+			return
+		}
+		var req request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			Debug("malformed stratum request from", c.conn.RemoteAddr(), err)
+			return
+		}
+		result, err := c.handle(&req)
+		resp := &response{ID: req.ID, Result: result, Error: err}
+		if err != nil {
+			resp.Result = nil
+		}
+		if e := c.send(resp); e != nil {
+			return
+		}
+		if req.Method == "mining.authorize" && err == nil {
+			if j := c.server.currentJob(c.algo); j != nil {
+				c.sendJob(j, true)
+			}
+		}
+	}
+}
+
+// close closes the connection.
+func (c *client) close() {
+	if err := c.conn.Close(); err != nil {
+		Trace(err)
+	}
+}
+
+// send writes a message to the miner.
+func (c *client) send(msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	c.writeMtx.Lock()
+	defer c.writeMtx.Unlock()
+	if _, err = c.conn.Write(append(b, '\n')); err != nil {
+		Debug("failed to write to stratum miner", c.conn.RemoteAddr(), err)
+	}
+	return err
+}
+
+// sendJob sends the passed job to the miner, preceded by its share difficulty if vardiff changed it, provided the
+// miner has authorized.
+func (c *client) sendJob(j *job, clean bool) {
+	c.mtx.Lock()
+	if !c.authorized {
+		c.mtx.Unlock()
+		return
+	}
+	// Shares at the difficulty in force before this job are still accepted until the next one.
+	c.prevDiff = c.difficulty
+	c.retarget(time.Now())
+	difficulty := c.difficulty
+	changed := difficulty != c.prevDiff
+	c.mtx.Unlock()
+	if changed || clean {
+		if err := c.send(&notification{Method: "mining.set_difficulty", Params: []interface{}{difficulty}}); err != nil {
+			return
+		}
+	}
+	if err := c.send(&notification{Method: "mining.notify", Params: j.notifyParams(clean)}); Check(err) {
+	}
+}
+
+// retarget adjusts the share difficulty so the miner finds shares about once every target share interval, once the
+// retarget interval has passed since the last adjustment. This function MUST be called with the client lock held.
+func (c *client) retarget(now time.Time) {
+	elapsed := now.Sub(c.lastRetarget)
+	if elapsed < c.server.cfg.RetargetInterval {
+		return
+	}
+	// With no shares at all the miner is assumed to have found one just now, which lowers the difficulty as far as a
+	// single retarget allows the longer it has been.
+	shares := math.Max(float64(c.shares), 1)
+	factor := c.server.cfg.TargetShareInterval.Seconds() * shares / elapsed.Seconds()
+	factor = math.Max(math.Min(factor, maxRetargetFactor), 1.0/maxRetargetFactor)
+	difficulty := math.Max(c.difficulty*factor, c.server.cfg.MinDifficulty)
+	// Small changes are not worth making the miner switch.
+	if math.Abs(difficulty-c.difficulty) > c.difficulty/10 {
+		Debugf("stratum vardiff for %s changed from %v to %v", c.worker, c.difficulty, difficulty)
+		c.difficulty = difficulty
+	}
+	c.lastRetarget = now
+	c.shares = 0
+}
+
+// handle carries out a request and returns its result.
+func (c *client) handle(req *request) (interface{}, *stratumError) {
+	switch req.Method {
+	case "mining.subscribe":
+		c.mtx.Lock()
+		c.subscribed = true
+		c.mtx.Unlock()
+		return []interface{}{
+			[][]string{
+				{"mining.set_difficulty", c.subscriptionID},
+				{"mining.notify", c.subscriptionID},
+			},
+			hex.EncodeToString(c.extraNonce1),
+			ExtraNonce2Size,
+		}, nil
+	case "mining.authorize":
+		var worker, password string
+		if len(req.Params) < 1 || json.Unmarshal(req.Params[0], &worker) != nil || worker == "" {
+			return nil, newError(errOther, "worker name required")
+		}
+		if len(req.Params) > 1 {
+			if err := json.Unmarshal(req.Params[1], &password); err != nil {
+				return nil, newError(errOther, "invalid password")
+			}
+		}
+		if c.server.cfg.Password != "" && password != c.server.cfg.Password {
+			return false, nil
+		}
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+		if !c.subscribed {
+			return nil, newError(errNotSubscribed, "not subscribed")
+		}
+		c.authorized = true
+		c.worker = worker
+		Info("stratum worker", worker, "authorized for", c.algo, "from", c.conn.RemoteAddr())
+		return true, nil
+	case "mining.submit":
+		return c.submit(req.Params)
+	case "mining.extranonce.subscribe":
+		return true, nil
+	default:
+		return nil, newError(errOther, "unknown method "+req.Method)
+	}
+}
+
+// submit handles a share submitted by the miner. The params are the worker name, job id, extra nonce 2, time and
+// nonce, the latter three in hex.
+func (c *client) submit(params []json.RawMessage) (interface{}, *stratumError) {
+	c.mtx.Lock()
+	authorized, worker := c.authorized, c.worker
+	difficulty := math.Min(c.difficulty, c.prevDiff)
+	c.mtx.Unlock()
+	if !authorized {
+		return nil, newError(errUnauthorized, "unauthorized worker")
+	}
+	var args [5]string
+	if len(params) < len(args) {
+		return nil, newError(errOther, "missing parameters")
+	}
+	for i := range args {
+		if err := json.Unmarshal(params[i], &args[i]); err != nil {
+			return nil, newError(errOther, "invalid parameters")
+		}
+	}
+	j := c.server.findJob(c.algo, args[1])
+	if j == nil {
+		return nil, newError(errJobNotFound, "job not found")
+	}
+	extraNonce2, err := hex.DecodeString(args[2])
+	if err != nil || len(extraNonce2) != ExtraNonce2Size {
+		return nil, newError(errOther, "invalid extranonce2")
+	}
+	timestamp, err := hex.DecodeString(args[3])
+	if err != nil || len(timestamp) != 4 {
+		return nil, newError(errOther, "invalid ntime")
+	}
+	nonce, err := hex.DecodeString(args[4])
+	if err != nil || len(nonce) != 4 {
+		return nil, newError(errOther, "invalid nonce")
+	}
+	s := &share{
+		extraNonce1: c.extraNonce1,
+		extraNonce2: extraNonce2,
+		timestamp:   binary.BigEndian.Uint32(timestamp),
+		nonce:       binary.BigEndian.Uint32(nonce),
+	}
+	block, shareErr := j.checkShare(s, c.server.shareTarget(difficulty))
+	if shareErr != nil {
+		Debug("rejected share from stratum worker", worker, shareErr)
+		return nil, shareErr
+	}
+	c.mtx.Lock()
+	c.shares++
+	c.mtx.Unlock()
+	if block != nil {
+		if err = c.server.submitBlock(block, worker); err != nil {
+			return nil, newError(errOther, err.Error())
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,220 @@
+package stratum
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/mining"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// ExtraNonce1Size is the size in bytes of the extra nonce the server assigns to each connection.
+	ExtraNonce1Size = 4
+	// ExtraNonce2Size is the size in bytes of the extra nonce miners roll in the coinbase.
+	ExtraNonce2Size = 4
+	// outPointSize is the serialized size of a previous outpoint, a transaction hash and an output index.
+	outPointSize = chainhash.HashSize + 4
+	// maxTimeOffset is how far into the future the timestamp of a share may be.
+	maxTimeOffset = time.Hour * 2
+)
+
+// job is a unit of work sent to the miners of one algorithm. It is built from a block template whose coinbase signature
+// script has room for the extra nonces, split around them so miners can assemble the coinbase themselves.
+type job struct {
+	id       string
+	algo     string
+	template *mining.BlockTemplate
+	// coinbase1 and coinbase2 are the serialized coinbase transaction without witness data before and after the extra
+	// nonces.
+	coinbase1 []byte
+	coinbase2 []byte
+	// witness is the witness of the template coinbase, which is restored when assembling a block as it does not take
+	// part in the transaction hash.
+	witness wire.TxWitness
+	// merkleBranch holds the hashes needed to compute the merkle root from the coinbase hash.
+	merkleBranch []chainhash.Hash
+	// submitted records the shares already received for the job so they are not counted twice.
+	submitted map[string]struct{}
+	mtx       sync.Mutex
+}
+
+// newJob returns a job with the passed id for the passed block template, which must be for the named algorithm.
+func newJob(id, algo string, template *mining.BlockTemplate) (*job, error) {
+	msgBlock := template.Block
+	coinbase := msgBlock.Transactions[0].Copy()
+	// The extra nonces take the place of the extra nonce the template generator puts after the height.
+	heightScript, err := txscript.NewScriptBuilder().AddInt64(int64(template.Height)).Script()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	script, err := txscript.NewScriptBuilder().AddInt64(int64(template.Height)).
+		AddData(make([]byte, ExtraNonce1Size+ExtraNonce2Size)).
+		AddData([]byte(mining.CoinbaseFlags)).Script()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if len(script) > blockchain.MaxCoinbaseScriptLen {
+		return nil, fmt.Errorf("coinbase transaction script length of %d is out of range (max: %d)",
+			len(script), blockchain.MaxCoinbaseScriptLen)
+	}
+	coinbase.TxIn[0].SignatureScript = script
+	var buf bytes.Buffer
+	if err = coinbase.SerializeNoWitness(&buf); err != nil {
+		Error(err)
+		return nil, err
+	}
+	// The extra nonces follow the version, input count, previous outpoint and script length, then the height and the
+	// push opcode of the extra nonce data in the script.
+	offset := 4 + wire.VarIntSerializeSize(uint64(len(coinbase.TxIn))) + outPointSize +
+		wire.VarIntSerializeSize(uint64(len(script))) + len(heightScript) + 1
+	raw := buf.Bytes()
+	j := &job{
+		id:        id,
+		algo:      algo,
+		template:  template,
+		coinbase1: raw[:offset],
+		coinbase2: raw[offset+ExtraNonce1Size+ExtraNonce2Size:],
+		witness:   coinbase.TxIn[0].Witness,
+		submitted: make(map[string]struct{}),
+	}
+	hashes := make([]*chainhash.Hash, len(msgBlock.Transactions))
+	for i, tx := range msgBlock.Transactions[1:] {
+		hash := tx.TxHash()
+		hashes[i+1] = &hash
+	}
+	j.merkleBranch = merkleBranch(hashes)
+	return j, nil
+}
+
+// merkleBranch returns the hashes along the path from the first of the passed transaction hashes to the merkle root.
+// The first hash is not used, as it is the coinbase hash which is not known until the miner fills in the extra nonces.
+func merkleBranch(hashes []*chainhash.Hash) (branch []chainhash.Hash) {
+	for len(hashes) > 1 {
+		branch = append(branch, *hashes[1])
+		// Odd levels pair the last hash with itself.
+		if len(hashes)%2 != 0 {
+			hashes = append(hashes, hashes[len(hashes)-1])
+		}
+		next := make([]*chainhash.Hash, 1, len(hashes)/2)
+		for i := 2; i < len(hashes); i += 2 {
+			next = append(next, blockchain.HashMerkleBranches(hashes[i], hashes[i+1]))
+		}
+		hashes = next
+	}
+	return
+}
+
+// notifyParams returns the parameters of the mining.notify message announcing the job.
+func (j *job) notifyParams(clean bool) []interface{} {
+	header := &j.template.Block.Header
+	branch := make([]string, len(j.merkleBranch))
+	for i := range j.merkleBranch {
+		branch[i] = hex.EncodeToString(j.merkleBranch[i][:])
+	}
+	return []interface{}{
+		j.id,
+		hex.EncodeToString(swapWords(header.PrevBlock[:])),
+		hex.EncodeToString(j.coinbase1),
+		hex.EncodeToString(j.coinbase2),
+		branch,
+		fmt.Sprintf("%08x", uint32(header.Version)),
+		fmt.Sprintf("%08x", header.Bits),
+		fmt.Sprintf("%08x", uint32(header.Timestamp.Unix())),
+		clean,
+	}
+}
+
+// swapWords returns a copy of the passed bytes with the byte order of every 4 byte word reversed, which is how Stratum
+// encodes the previous block hash.
+func swapWords(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := 0; i+4 <= len(b); i += 4 {
+		binary.LittleEndian.PutUint32(out[i:], binary.BigEndian.Uint32(b[i:]))
+	}
+	return out
+}
+
+// share is a solution for a job submitted by a miner.
+type share struct {
+	extraNonce1 []byte
+	extraNonce2 []byte
+	timestamp   uint32
+	nonce       uint32
+}
+
+// block assembles the block the passed share solves, returning it along with its proof of work hash.
+func (j *job) block(s *share) (*wire.MsgBlock, chainhash.Hash, error) {
+	raw := make([]byte, 0, len(j.coinbase1)+ExtraNonce1Size+ExtraNonce2Size+len(j.coinbase2))
+	raw = append(raw, j.coinbase1...)
+	raw = append(raw, s.extraNonce1...)
+	raw = append(raw, s.extraNonce2...)
+	raw = append(raw, j.coinbase2...)
+	var coinbase wire.MsgTx
+	if err := coinbase.DeserializeNoWitness(bytes.NewReader(raw)); err != nil {
+		Error(err)
+		return nil, chainhash.Hash{}, err
+	}
+	coinbase.TxIn[0].Witness = j.witness
+	merkleRoot := coinbase.TxHash()
+	for i := range j.merkleBranch {
+		merkleRoot = *blockchain.HashMerkleBranches(&merkleRoot, &j.merkleBranch[i])
+	}
+	template := j.template.Block
+	msgBlock := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:    template.Header.Version,
+			PrevBlock:  template.Header.PrevBlock,
+			MerkleRoot: merkleRoot,
+			Timestamp:  time.Unix(int64(s.timestamp), 0),
+			Bits:       template.Header.Bits,
+			Nonce:      s.nonce,
+		},
+		Transactions: make([]*wire.MsgTx, 0, len(template.Transactions)),
+	}
+	msgBlock.Transactions = append(msgBlock.Transactions, &coinbase)
+	msgBlock.Transactions = append(msgBlock.Transactions, template.Transactions[1:]...)
+	return msgBlock, msgBlock.BlockHashWithAlgos(j.template.Height), nil
+}
+
+// checkShare validates the passed share against the job and the passed share target. It returns the solved block if
+// the share also meets the network target, and nil if it is only a share.
+func (j *job) checkShare(s *share, shareTarget *big.Int) (*util.Block, *stratumError) {
+	minTime := j.template.Block.Header.Timestamp.Unix()
+	if int64(s.timestamp) < minTime || time.Unix(int64(s.timestamp), 0).After(time.Now().Add(maxTimeOffset)) {
+		return nil, newError(errOther, "ntime out of range")
+	}
+	key := fmt.Sprintf("%x%x%08x%08x", s.extraNonce1, s.extraNonce2, s.timestamp, s.nonce)
+	j.mtx.Lock()
+	_, duplicate := j.submitted[key]
+	j.submitted[key] = struct{}{}
+	j.mtx.Unlock()
+	if duplicate {
+		return nil, newError(errDuplicateShare, "duplicate share")
+	}
+	msgBlock, hash, err := j.block(s)
+	if err != nil {
+		return nil, newError(errOther, err.Error())
+	}
+	hashNum := blockchain.HashToBig(&hash)
+	if hashNum.Cmp(shareTarget) > 0 {
+		return nil, newError(errLowDifficulty, "low difficulty share")
+	}
+	if hashNum.Cmp(blockchain.CompactToBig(msgBlock.Header.Bits)) > 0 {
+		return nil, nil
+	}
+	block := util.NewBlock(msgBlock)
+	block.SetHeight(j.template.Height)
+	return block, nil
+}
@@ -0,0 +1,358 @@
+// Package stratum implements a Stratum v1 mining server so external miners can mine on the node. Each algorithm is
+// served on its own port, jobs are built from the block templates of the node and solved blocks are submitted to the
+// chain. The share difficulty of each connection is adjusted to keep its share rate near a target.
+package stratum
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/mining"
+	"github.com/p9c/pod/pkg/util"
+	qu "github.com/p9c/pod/pkg/util/quit"
+)
+
+const (
+	// DefaultInitialDifficulty is the share difficulty new connections start at.
+	DefaultInitialDifficulty = 1.0
+	// DefaultMinDifficulty is the lowest share difficulty vardiff will set.
+	DefaultMinDifficulty = 1.0 / 1024
+	// DefaultTargetShareInterval is the time vardiff aims for between the shares of each connection.
+	DefaultTargetShareInterval = time.Second * 10
+	// DefaultRetargetInterval is how often vardiff reconsiders the share difficulty of each connection.
+	DefaultRetargetInterval = time.Second * 90
+	// jobRefreshInterval is how often jobs are rebuilt to pick up new transactions when the best block does not
+	// change.
+	jobRefreshInterval = time.Minute
+	// maxJobsPerAlgo is the number of the latest jobs of each algorithm that shares are accepted for while the best
+	// block does not change, so the jobs of the periodic refreshes do not pile up.
+	maxJobsPerAlgo = 4
+)
+
+// Config holds the parameters of the Stratum server and the functions it uses to get work from the node and hand
+// solved blocks back to it.
+type Config struct {
+	// Listeners maps the name of each algorithm to serve to the address its port listens on.
+	Listeners map[string]string
+	// ChainParams identifies the network being mined. A share difficulty of 1 corresponds to its proof of work limit.
+	ChainParams *netparams.Params
+	// NewBlockTemplate returns a new block template to mine with the named algorithm.
+	NewBlockTemplate func(algo string) (*mining.BlockTemplate, error)
+	// ProcessBlock submits a solved block to the chain and returns whether it is an orphan.
+	ProcessBlock func(block *util.Block) (bool, error)
+	// IsCurrent returns whether the chain is synced, jobs are not updated until it is. It may be nil.
+	IsCurrent func() bool
+	// Password, if not empty, must be given by miners when authorizing.
+	Password string
+	// InitialDifficulty, MinDifficulty, TargetShareInterval and RetargetInterval control vardiff. Zero values are
+	// replaced by their defaults.
+	InitialDifficulty   float64
+	MinDifficulty       float64
+	TargetShareInterval time.Duration
+	RetargetInterval    time.Duration
+}
+
+// Server is a Stratum v1 mining server.
+type Server struct {
+	cfg       Config
+	listeners map[string]net.Listener
+	mtx       sync.Mutex
+	// jobs holds the jobs of each algorithm that shares are still accepted for, oldest first, and current the latest
+	// one.
+	jobs    map[string][]*job
+	current map[string]*job
+	clients map[*client]struct{}
+	// prevHash is the previous block of the current jobs, used to tell whether miners must drop old work.
+	prevHash        chainhash.Hash
+	nextJobID       uint64
+	nextExtraNonce1 uint32
+	// blockConnected signals the goroutine refreshing the jobs that the best block has changed.
+	blockConnected chan struct{}
+	quit           qu.C
+	wg             sync.WaitGroup
+}
+
+// AlgoListeners returns the listener addresses for every Plan 9 algorithm, ordered by block version, starting at the
+// passed address and using consecutive ports.
+func AlgoListeners(addr string) (map[string]string, error) {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	algos := fork.AlgoSlices[len(fork.AlgoSlices)-1]
+	if port == 0 || port+len(algos) > 65536 {
+		return nil, fmt.Errorf("stratum port %d leaves no room for the %d algorithm ports", port, len(algos))
+	}
+	listeners := make(map[string]string, len(algos))
+	for i := range algos {
+		listeners[algos[i].Name] = net.JoinHostPort(host, strconv.Itoa(port+i))
+	}
+	return listeners, nil
+}
+
+// New returns a new Stratum server with the passed configuration. It does not listen until Start is called.
+func New(cfg *Config) *Server {
+	s := &Server{
+		cfg:            *cfg,
+		listeners:      make(map[string]net.Listener),
+		jobs:           make(map[string][]*job),
+		current:        make(map[string]*job),
+		clients:        make(map[*client]struct{}),
+		blockConnected: make(chan struct{}, 1),
+		quit:           qu.T(),
+	}
+	if s.cfg.InitialDifficulty <= 0 {
+		s.cfg.InitialDifficulty = DefaultInitialDifficulty
+	}
+	if s.cfg.MinDifficulty <= 0 {
+		s.cfg.MinDifficulty = DefaultMinDifficulty
+	}
+	if s.cfg.TargetShareInterval <= 0 {
+		s.cfg.TargetShareInterval = DefaultTargetShareInterval
+	}
+	if s.cfg.RetargetInterval <= 0 {
+		s.cfg.RetargetInterval = DefaultRetargetInterval
+	}
+	return s
+}
+
+// Start opens the listener of every algorithm, builds the first jobs and starts serving miners.
+func (s *Server) Start() (err error) {
+	for algo, addr := range s.cfg.Listeners {
+		var listener net.Listener
+		if listener, err = net.Listen("tcp", addr); err != nil {
+			Error(err)
+			for _, l := range s.listeners {
+				if e := l.Close(); Check(e) {
+				}
+			}
+			return
+		}
+		Infof("stratum server listening for %s miners on %s", algo, listener.Addr())
+		s.listeners[algo] = listener
+	}
+	s.UpdateJobs()
+	for algo, listener := range s.listeners {
+		s.wg.Add(1)
+		go s.acceptConnections(algo, listener)
+	}
+	s.wg.Add(1)
+	go s.refreshJobs()
+	return
+}
+
+// Stop closes the listeners and all miner connections and waits for them to finish.
+func (s *Server) Stop() {
+	s.quit.Q()
+	for _, listener := range s.listeners {
+		if err := listener.Close(); Check(err) {
+		}
+	}
+	s.mtx.Lock()
+	for c := range s.clients {
+		c.close()
+	}
+	s.mtx.Unlock()
+	s.wg.Wait()
+}
+
+// Addrs returns the address each algorithm is being served on.
+func (s *Server) Addrs() map[string]net.Addr {
+	addrs := make(map[string]net.Addr, len(s.listeners))
+	for algo, listener := range s.listeners {
+		addrs[algo] = listener.Addr()
+	}
+	return addrs
+}
+
+// UpdateJobs builds new jobs for every algorithm from fresh block templates and sends them to the miners. If the
+// previous block has changed the miners are told to drop their old work, and shares for the old jobs are no longer
+// accepted. Otherwise shares are still accepted for the last few jobs. This function is safe for concurrent access.
+func (s *Server) UpdateJobs() {
+	if s.cfg.IsCurrent != nil && !s.cfg.IsCurrent() {
+		Debug("chain is not current, not updating stratum jobs")
+		return
+	}
+	jobs := make(map[string]*job, len(s.listeners))
+	for algo := range s.listeners {
+		template, err := s.cfg.NewBlockTemplate(algo)
+		if err != nil {
+			Error(err)
+			continue
+		}
+		s.mtx.Lock()
+		s.nextJobID++
+		id := strconv.FormatUint(s.nextJobID, 16)
+		s.mtx.Unlock()
+		if jobs[algo], err = newJob(id, algo, template); err != nil {
+			Error(err)
+			delete(jobs, algo)
+		}
+	}
+	type update struct {
+		j     *job
+		clean bool
+	}
+	updates := make(map[string]update, len(jobs))
+	var clients []*client
+	s.mtx.Lock()
+	for algo, j := range jobs {
+		clean := s.current[algo] == nil || j.template.Block.Header.PrevBlock != s.prevHash
+		if clean {
+			s.jobs[algo] = nil
+		}
+		s.jobs[algo] = append(s.jobs[algo], j)
+		if n := len(s.jobs[algo]); n > maxJobsPerAlgo {
+			s.jobs[algo] = append([]*job(nil), s.jobs[algo][n-maxJobsPerAlgo:]...)
+		}
+		s.current[algo] = j
+		updates[algo] = update{j, clean}
+	}
+	for _, j := range jobs {
+		s.prevHash = j.template.Block.Header.PrevBlock
+		break
+	}
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.mtx.Unlock()
+	// Jobs are sent without holding the server lock so a slow miner does not hold up the others.
+	for _, c := range clients {
+		if u, ok := updates[c.algo]; ok {
+			c.sendJob(u.j, u.clean)
+		}
+	}
+}
+
+// BlockConnected tells the server a block has been connected to the main chain, so it sends miners new jobs building on
+// it. It does not block, the jobs are updated by another goroutine.
+func (s *Server) BlockConnected() {
+	select {
+	case s.blockConnected <- struct{}{}:
+	default:
+	}
+}
+
+// refreshJobs rebuilds the jobs when a block is connected, and periodically so the blocks being mined include new
+// transactions.
+func (s *Server) refreshJobs() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(jobRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.UpdateJobs()
+		case <-s.blockConnected:
+			s.UpdateJobs()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// acceptConnections serves each miner connecting to the listener of the passed algorithm until the server stops.
+func (s *Server) acceptConnections(algo string, listener net.Listener) {
+	defer s.wg.Done()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			select {
+			case <-s.quit:
+			default:
+				Error(err)
+			}
+			return
+		}
+		c := s.newClient(conn, algo)
+		s.mtx.Lock()
+		s.clients[c] = struct{}{}
+		s.mtx.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			c.serve()
+			s.mtx.Lock()
+			delete(s.clients, c)
+			s.mtx.Unlock()
+		}()
+	}
+}
+
+// newClient returns a client for a miner connected to the port of the passed algorithm, with its own extra nonce.
+func (s *Server) newClient(conn net.Conn, algo string) *client {
+	s.mtx.Lock()
+	s.nextExtraNonce1++
+	extraNonce1 := make([]byte, ExtraNonce1Size)
+	binary.BigEndian.PutUint32(extraNonce1, s.nextExtraNonce1)
+	s.mtx.Unlock()
+	return &client{
+		server:         s,
+		conn:           conn,
+		algo:           algo,
+		extraNonce1:    extraNonce1,
+		difficulty:     s.cfg.InitialDifficulty,
+		prevDiff:       s.cfg.InitialDifficulty,
+		lastRetarget:   time.Now(),
+		subscriptionID: hex.EncodeToString(extraNonce1),
+	}
+}
+
+// currentJob returns the latest job of the passed algorithm, or nil if there is none yet.
+func (s *Server) currentJob(algo string) *job {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.current[algo]
+}
+
+// findJob returns the job of the passed algorithm with the passed id if shares are still accepted for it.
+func (s *Server) findJob(algo, id string) *job {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	for _, j := range s.jobs[algo] {
+		if j.id == id {
+			return j
+		}
+	}
+	return nil
+}
+
+// shareTarget returns the target a hash must not exceed to be a share of the passed difficulty. Difficulty 1 is the
+// target encoded by the compact proof of work limit, which blocks at the minimum difficulty carry, rather than the
+// limit itself, which can be higher.
+func (s *Server) shareTarget(difficulty float64) *big.Int {
+	target, _ := new(big.Float).Quo(new(big.Float).SetInt(fork.CompactToBig(s.cfg.ChainParams.PowLimitBits)),
+		big.NewFloat(difficulty)).Int(nil)
+	return target
+}
+
+// submitBlock hands a block solved by a miner to the chain.
+func (s *Server) submitBlock(block *util.Block, worker string) error {
+	isOrphan, err := s.cfg.ProcessBlock(block)
+	if err != nil {
+		Warn("block submitted via stratum by", worker, "rejected:", err)
+		return err
+	}
+	if isOrphan {
+		Warn("block submitted via stratum by", worker, "is an orphan")
+		return errors.New("block is an orphan")
+	}
+	Infof("block %v at height %d found by stratum worker %s", block.Hash(), block.Height(), worker)
+	return nil
+}
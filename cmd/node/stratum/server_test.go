@@ -0,0 +1,279 @@
+package stratum
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/mining"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// testMiner is an in-process Stratum client.
+type testMiner struct {
+	t      *testing.T
+	conn   net.Conn
+	reader *bufio.Reader
+	nextID int
+}
+
+// message is any message received from the server.
+type message struct {
+	ID     *int              `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+	Result json.RawMessage   `json:"result"`
+	Error  []interface{}     `json:"error"`
+}
+
+// call sends a request and returns the response to it.
+func (m *testMiner) call(method string, params ...interface{}) *message {
+	m.nextID++
+	b, err := json.Marshal(map[string]interface{}{"id": m.nextID, "method": method, "params": params})
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	if _, err = m.conn.Write(append(b, '\n')); err != nil {
+		m.t.Fatal(err)
+	}
+	msg := m.read()
+	if msg.ID == nil || *msg.ID != m.nextID {
+		m.t.Fatalf("%s: unexpected reply %+v", method, msg)
+	}
+	return msg
+}
+
+// read returns the next message from the server.
+func (m *testMiner) read() *message {
+	if err := m.conn.SetReadDeadline(time.Now().Add(time.Second * 10)); err != nil {
+		m.t.Fatal(err)
+	}
+	line, err := m.reader.ReadBytes('\n')
+	if err != nil {
+		m.t.Fatal(err)
+	}
+	var msg message
+	if err = json.Unmarshal(line, &msg); err != nil {
+		m.t.Fatalf("malformed message %s: %v", line, err)
+	}
+	return &msg
+}
+
+// testTemplate returns a block template at height 1 with a coinbase and one other transaction.
+func testTemplate(params *netparams.Params) *mining.BlockTemplate {
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex),
+		SignatureScript:  []byte{0x51, 0x51},
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(50*1e8, []byte{0x51}))
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(1e8, []byte{0x51}))
+	return &mining.BlockTemplate{
+		Block: &wire.MsgBlock{
+			Header: wire.BlockHeader{
+				Version:   fork.GetAlgoVer(fork.SHA256d, 1),
+				PrevBlock: *params.GenesisHash,
+				Timestamp: time.Unix(time.Now().Unix(), 0),
+				Bits:      params.PowLimitBits,
+			},
+			Transactions: []*wire.MsgTx{coinbase, tx},
+		},
+		Height: 1,
+	}
+}
+
+// TestServer drives the server with an in-process miner that solves a block, and checks that the block reaches the
+// chain and that duplicate and low difficulty shares are rejected.
+func TestServer(t *testing.T) {
+	params := &netparams.RegressionTestParams
+	template := testTemplate(params)
+	blocks := make(chan *util.Block, 1)
+	s := New(&Config{
+		Listeners:   map[string]string{fork.SHA256d: "127.0.0.1:0"},
+		ChainParams: params,
+		NewBlockTemplate: func(algo string) (*mining.BlockTemplate, error) {
+			return template, nil
+		},
+		ProcessBlock: func(block *util.Block) (bool, error) {
+			blocks <- block
+			return false, nil
+		},
+	})
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+	conn, err := net.Dial("tcp", s.Addrs()[fork.SHA256d].String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	m := &testMiner{t: t, conn: conn, reader: bufio.NewReader(conn)}
+	// A miner must subscribe before authorizing.
+	var subscription []json.RawMessage
+	if err = json.Unmarshal(m.call("mining.subscribe", "test/1.0").Result, &subscription); err != nil {
+		t.Fatal(err)
+	}
+	if len(subscription) != 3 {
+		t.Fatalf("unexpected subscription %v", subscription)
+	}
+	var extraNonce1Hex string
+	if err = json.Unmarshal(subscription[1], &extraNonce1Hex); err != nil {
+		t.Fatal(err)
+	}
+	extraNonce1, err := hex.DecodeString(extraNonce1Hex)
+	if err != nil || len(extraNonce1) != ExtraNonce1Size {
+		t.Fatalf("invalid extranonce1 %q", extraNonce1Hex)
+	}
+	if resp := m.call("mining.authorize", "worker", "x"); string(resp.Result) != "true" {
+		t.Fatalf("authorize failed: %+v", resp)
+	}
+	if msg := m.read(); msg.Method != "mining.set_difficulty" {
+		t.Fatalf("expected mining.set_difficulty, got %+v", msg)
+	}
+	notify := m.read()
+	if notify.Method != "mining.notify" || len(notify.Params) != 9 {
+		t.Fatalf("expected mining.notify, got %+v", notify)
+	}
+	var jobID, prevHashHex, coinbase1Hex, coinbase2Hex, versionHex, bitsHex, timeHex string
+	var branchHex []string
+	for i, v := range []interface{}{
+		&jobID, &prevHashHex, &coinbase1Hex, &coinbase2Hex, &branchHex, &versionHex, &bitsHex, &timeHex,
+	} {
+		if err = json.Unmarshal(notify.Params[i], v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Assemble the block the way a miner does.
+	extraNonce2 := []byte{1, 2, 3, 4}
+	coinbase1, _ := hex.DecodeString(coinbase1Hex)
+	coinbase2, _ := hex.DecodeString(coinbase2Hex)
+	var coinbase wire.MsgTx
+	raw := append(append(append(coinbase1, extraNonce1...), extraNonce2...), coinbase2...)
+	if err = coinbase.DeserializeNoWitness(bytes.NewReader(raw)); err != nil {
+		t.Fatal(err)
+	}
+	merkleRoot := coinbase.TxHash()
+	for _, h := range branchHex {
+		var hash chainhash.Hash
+		b, _ := hex.DecodeString(h)
+		copy(hash[:], b)
+		merkleRoot = *blockchain.HashMerkleBranches(&merkleRoot, &hash)
+	}
+	if want := blockchain.BuildMerkleTreeStore(util.NewBlock(&wire.MsgBlock{
+		Transactions: []*wire.MsgTx{&coinbase, template.Block.Transactions[1]},
+	}).Transactions(), false); merkleRoot != *want[len(want)-1] {
+		t.Fatalf("merkle root %v, want %v", merkleRoot, want[len(want)-1])
+	}
+	prevHash, _ := hex.DecodeString(prevHashHex)
+	version, _ := strconv.ParseUint(versionHex, 16, 32)
+	bits, _ := strconv.ParseUint(bitsHex, 16, 32)
+	timestamp, _ := strconv.ParseUint(timeHex, 16, 32)
+	header := wire.BlockHeader{
+		Version:    int32(version),
+		MerkleRoot: merkleRoot,
+		Timestamp:  time.Unix(int64(timestamp), 0),
+		Bits:       uint32(bits),
+	}
+	copy(header.PrevBlock[:], swapWords(prevHash))
+	if header.PrevBlock != template.Block.Header.PrevBlock {
+		t.Fatalf("previous block %v, want %v", header.PrevBlock, template.Block.Header.PrevBlock)
+	}
+	// Find a nonce that solves the block and one that does not. As the share difficulty is 1 the share target is the
+	// block target at the proof of work limit, so a hash that misses the block target is also too high for a share.
+	target := blockchain.CompactToBig(header.Bits)
+	solved, unsolved := -1, -1
+	for nonce := 0; solved < 0 || unsolved < 0; nonce++ {
+		header.Nonce = uint32(nonce)
+		hash := header.BlockHashWithAlgos(1)
+		if blockchain.HashToBig(&hash).Cmp(target) <= 0 {
+			if solved < 0 {
+				solved = nonce
+			}
+		} else if unsolved < 0 {
+			unsolved = nonce
+		}
+	}
+	submit := func(nonce int) *message {
+		return m.call("mining.submit", "worker", jobID, hex.EncodeToString(extraNonce2), timeHex,
+			hex.EncodeToString([]byte{byte(nonce >> 24), byte(nonce >> 16), byte(nonce >> 8), byte(nonce)}))
+	}
+	if resp := submit(unsolved); len(resp.Error) == 0 || resp.Error[0] != float64(errLowDifficulty) {
+		t.Fatalf("expected low difficulty error, got %+v", resp)
+	}
+	if resp := submit(solved); string(resp.Result) != "true" {
+		t.Fatalf("solution rejected: %+v", resp)
+	}
+	select {
+	case block := <-blocks:
+		header.Nonce = uint32(solved)
+		if block.MsgBlock().BlockHashWithAlgos(1) != header.BlockHashWithAlgos(1) {
+			t.Fatalf("submitted block header %+v, want %+v", block.MsgBlock().Header, header)
+		}
+		if block.Height() != 1 {
+			t.Fatalf("submitted block height %d, want 1", block.Height())
+		}
+		script := block.MsgBlock().Transactions[0].TxIn[0].SignatureScript
+		if !bytes.Contains(script, append(extraNonce1, extraNonce2...)) {
+			t.Fatalf("coinbase script %x does not contain the extra nonces", script)
+		}
+	default:
+		t.Fatal("solved block was not submitted")
+	}
+	if resp := submit(solved); len(resp.Error) == 0 || resp.Error[0] != float64(errDuplicateShare) {
+		t.Fatalf("expected duplicate share error, got %+v", resp)
+	}
+	if resp := m.call("mining.submit", "worker", "unknown", "00000000", timeHex, "00000000"); len(resp.Error) == 0 ||
+		resp.Error[0] != float64(errJobNotFound) {
+		t.Fatalf("expected job not found error, got %+v", resp)
+	}
+}
+
+// TestUpdateJobsLimit ensures only the last few jobs of an algorithm are kept while the best block does not change, and
+// that none of the jobs on the previous block are once it does.
+func TestUpdateJobsLimit(t *testing.T) {
+	params := &netparams.RegressionTestParams
+	template := testTemplate(params)
+	s := New(&Config{
+		Listeners:   map[string]string{fork.SHA256d: "127.0.0.1:0"},
+		ChainParams: params,
+		NewBlockTemplate: func(algo string) (*mining.BlockTemplate, error) {
+			return template, nil
+		},
+	})
+	// The jobs are built for the algorithms being listened for.
+	s.listeners[fork.SHA256d] = nil
+	for i := 0; i < maxJobsPerAlgo*2; i++ {
+		s.UpdateJobs()
+	}
+	jobs := s.jobs[fork.SHA256d]
+	if len(jobs) != maxJobsPerAlgo {
+		t.Fatalf("%d jobs kept, want %d", len(jobs), maxJobsPerAlgo)
+	}
+	if latest := s.currentJob(fork.SHA256d); jobs[len(jobs)-1] != latest || s.findJob(fork.SHA256d, latest.id) != latest {
+		t.Fatalf("latest job is not kept")
+	}
+	if s.findJob(fork.SHA256d, "1") != nil {
+		t.Fatalf("shares are accepted for the first job")
+	}
+	next := testTemplate(params)
+	next.Block.Header.PrevBlock = chainhash.Hash{1}
+	template = next
+	s.UpdateJobs()
+	if jobs = s.jobs[fork.SHA256d]; len(jobs) != 1 || jobs[0] != s.currentJob(fork.SHA256d) {
+		t.Fatalf("jobs on the previous block are kept after it changed")
+	}
+}
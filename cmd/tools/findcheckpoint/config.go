@@ -11,6 +11,7 @@ import (
 	"github.com/p9c/pod/pkg/chain/config/netparams"
 	"github.com/p9c/pod/pkg/chain/wire"
 	database "github.com/p9c/pod/pkg/db"
+	_ "github.com/p9c/pod/pkg/db/boltdb"
 	_ "github.com/p9c/pod/pkg/db/ffldb"
 )
 
@@ -57,6 +57,11 @@ func CreateWallet(activenet *netparams.Params, config *pod.Config) error {
 	dbDir := *config.WalletFile
 	loader := wallet.NewLoader(activenet, dbDir, 250)
 	Debug("WalletPage", loader.ChainParams.Name)
+	// A watching-only wallet is created from the extended public key of an account and holds no private keys, so there
+	// is neither a private passphrase nor a seed to ask for.
+	if *config.WalletWatchOnly != "" {
+		return createWatchingOnlyWallet(loader, config)
+	}
 	// When there is a legacy keystore, open it now to ensure any errors don't end up exiting the process after the user
 	// has spent time entering a bunch of information.
 	netDir := NetworkDir(*config.DataDir, activenet)
@@ -156,6 +161,30 @@ func CreateWallet(activenet *netparams.Params, config *pod.Config) error {
 	return nil
 }
 
+// createWatchingOnlyWallet prompts for the public passphrase and creates a watching-only wallet from the configured
+// extended public key. As nothing is known of the account's history, the wallet's birthday is the genesis block, so the
+// initial sync scans the whole chain for it.
+func createWatchingOnlyWallet(loader *wallet.Loader, config *pod.Config) error {
+	reader := bufio.NewReader(os.Stdin)
+	pubPass, err := prompt.PublicPass(reader, nil, []byte(""), []byte(*config.WalletPass))
+	if err != nil {
+		Error(err)
+		time.Sleep(time.Second * 5)
+		return err
+	}
+	Debug("Creating the watching-only wallet")
+	bday := loader.ChainParams.GenesisBlock.Header.Timestamp
+	w, err := loader.CreateNewWatchingOnlyWallet(pubPass, *config.WalletWatchOnly, bday, false, config, nil)
+	if err != nil {
+		Error(err)
+		time.Sleep(time.Second * 5)
+		return err
+	}
+	w.Manager.Close()
+	Debug("The watching-only wallet has been created successfully.")
+	return nil
+}
+
 // NetworkDir returns the directory name of a network directory to hold wallet files.
 func NetworkDir(dataDir string, chainParams *netparams.Params) string {
 	netname := chainParams.Name
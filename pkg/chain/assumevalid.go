@@ -0,0 +1,123 @@
+package blockchain
+
+import (
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// isAssumedValid returns whether the passed block is an ancestor of the assumed valid block, so its scripts are not
+// checked. Blocks are only known to be its ancestors once the assumed valid block is in the block index. This function
+// MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) isAssumedValid(node *BlockNode) bool {
+	av := b.assumeValid
+	if av == nil || node.height >= av.Height {
+		return false
+	}
+	if b.assumeValidChain == nil {
+		avNode := b.Index.LookupNode(av.Hash)
+		if avNode == nil {
+			return false
+		}
+		b.assumeValidChain = newChainView(avNode)
+	}
+	// This is avNode.Ancestor(node.height) == node without walking back from the assumed valid block every time.
+	return b.assumeValidChain.Contains(node)
+}
+
+// checkAssumeValid confirms the assumed valid block once the best chain reaches its height, after which the scripts of
+// every block are checked. If the best chain has a different block at that height, the ancestors of the assumed valid
+// block it shares with the best chain had their scripts skipped for a block that turned out not to be in it, so a
+// warning is logged and their scripts are checked now. The first of them that fails is marked invalid and the chain is
+// reorganized away from it. Errors are only logged as the block being processed is not affected by them. This function
+// MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) checkAssumeValid() {
+	av := b.assumeValid
+	if av == nil || b.BestChain.Height() < av.Height {
+		return
+	}
+	b.assumeValid = nil
+	b.assumeValidChain = nil
+	node := b.BestChain.NodeByHeight(av.Height)
+	if node.hash.IsEqual(av.Hash) {
+		Infof("reached assumed valid block %v (height %d), checking the scripts of all further blocks", av.Hash,
+			av.Height)
+		return
+	}
+	Warnf("ASSUMEVALID: the assumed valid block %v is not in the best chain, which has block %v at height %d. "+
+		"The scripts of the blocks below it were not checked, checking them now and fully validating from here on",
+		av.Hash, node.hash, av.Height)
+	from := int32(1)
+	if checkpoint := b.LatestCheckpoint(); checkpoint != nil && checkpoint.Height >= from {
+		from = checkpoint.Height + 1
+	}
+	for height := from; height < av.Height; height++ {
+		n := b.BestChain.NodeByHeight(height)
+		err := b.verifyBlockScripts(n)
+		if err == nil {
+			continue
+		}
+		if _, ok := err.(RuleError); !ok {
+			Error("unable to check the scripts of block", n.hash, "at height", height, ":", err)
+			return
+		}
+		Warnf("ASSUMEVALID: block %v (height %d) has invalid scripts: %v", n.hash, n.height, err)
+		b.Index.SetStatusFlags(n, statusValidateFailed)
+		for _, d := range b.descendants(n) {
+			b.Index.SetStatusFlags(d, statusInvalidAncestor)
+		}
+		if err = b.Index.flushToDB(); err != nil {
+			Error(err)
+			return
+		}
+		if err = b.activateBestValidChain(n.parent); err != nil {
+			Error(err)
+		}
+		return
+	}
+	Infof("the scripts of all blocks below height %d are valid", av.Height)
+}
+
+// verifyBlockScripts checks the scripts of the passed block in the main chain against the outputs it spent, which are
+// read back from the spend journal. This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) verifyBlockScripts(node *BlockNode) error {
+	var block *util.Block
+	var stxos []SpentTxOut
+	err := b.db.View(func(dbTx database.Tx) error {
+		var err error
+		if block, err = dbFetchBlockByNode(dbTx, node); err != nil {
+			return err
+		}
+		stxos, err = dbFetchSpendJournalEntry(dbTx, block)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	// The spent outputs are journaled in the order the inputs of the transactions after the coinbase spend them.
+	view := NewUtxoViewpoint()
+	var i int
+	for _, tx := range block.Transactions()[1:] {
+		for _, txIn := range tx.MsgTx().TxIn {
+			if i >= len(stxos) {
+				return AssertError("spend journal of block " + node.hash.String() + " is missing entries")
+			}
+			stxo := &stxos[i]
+			var flags txoFlags
+			if stxo.IsCoinBase {
+				flags |= tfCoinBase
+			}
+			view.entries[txIn.PreviousOutPoint] = &UtxoEntry{
+				amount:      stxo.Amount,
+				pkScript:    stxo.PkScript,
+				blockHeight: stxo.Height,
+				packedFlags: flags,
+			}
+			i++
+		}
+	}
+	scriptFlags, err := b.blockScriptFlags(node, block)
+	if err != nil {
+		return err
+	}
+	return checkBlockScripts(block, view, scriptFlags, b.sigCache, b.hashCache)
+}
@@ -0,0 +1,139 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	chaincfg "github.com/p9c/pod/pkg/chain/config"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+)
+
+// TestIsAssumedValid ensures only the ancestors of the assumed valid block have their scripts skipped, and none do
+// while it is not in the block index.
+func TestIsAssumedValid(t *testing.T) {
+	chain, teardown, err := chainSetup("isassumedvalid", &netparams.RegressionTestParams)
+	if err != nil {
+		t.Fatalf("failed to setup chain instance: %v", err)
+	}
+	defer teardown()
+	main, err := addTestBlocks(chain, chain.BestChain.Tip(), 4, 0)
+	if err != nil {
+		t.Fatalf("unable to add blocks: %v", err)
+	}
+	side, err := addTestBlocks(chain, main[1], 3, 1)
+	if err != nil {
+		t.Fatalf("unable to add side chain blocks: %v", err)
+	}
+	// The side branch forks from the ancestors of the assumed valid block below it.
+	chain.chainLock.Lock()
+	defer chain.chainLock.Unlock()
+	chain.assumeValid = &chaincfg.Checkpoint{Height: main[3].height, Hash: &main[3].hash}
+	tests := []struct {
+		name string
+		node *BlockNode
+		want bool
+	}{
+		{"ancestor", main[0], true},
+		{"ancestor below fork", main[1], true},
+		{"ancestor above fork", main[2], true},
+		{"assumed valid block", main[3], false},
+		{"other branch", side[0], false},
+		{"other branch above", side[2], false},
+	}
+	for _, test := range tests {
+		if got := chain.isAssumedValid(test.node); got != test.want {
+			t.Errorf("%s: isAssumedValid(%d %v) = %v, want %v", test.name, test.node.height, test.node.hash, got,
+				test.want)
+		}
+	}
+	chain.assumeValid = &chaincfg.Checkpoint{Height: 10, Hash: &chainhash.Hash{1}}
+	chain.assumeValidChain = nil
+	for _, test := range tests {
+		if chain.isAssumedValid(test.node) {
+			t.Errorf("%s: block %d %v is assumed valid for a block that is not in the block index", test.name,
+				test.node.height, test.node.hash)
+		}
+	}
+}
+
+// TestAssumeValidSkipsScripts ensures a block with an invalid script is connected in a reorganize to a branch ending
+// in the assumed valid block, and rejected if the assumed valid block is not its descendant.
+func TestAssumeValidSkipsScripts(t *testing.T) {
+	tests := []struct {
+		name string
+		// assumeValid returns the assumed valid block given the tip of the branch with the invalid script.
+		assumeValid func(tip *BlockNode) *chaincfg.Checkpoint
+		connected   bool
+	}{
+		{
+			name: "descendant",
+			assumeValid: func(tip *BlockNode) *chaincfg.Checkpoint {
+				return &chaincfg.Checkpoint{Height: tip.height, Hash: &tip.hash}
+			},
+			connected: true,
+		},
+		{
+			name: "unknown",
+			assumeValid: func(tip *BlockNode) *chaincfg.Checkpoint {
+				return &chaincfg.Checkpoint{Height: 10, Hash: &chainhash.Hash{1}}
+			},
+		},
+	}
+	for _, test := range tests {
+		func() {
+			chain, teardown, err := chainSetup("assumevalidscripts", &netparams.RegressionTestParams)
+			if err != nil {
+				t.Fatalf("failed to setup chain instance: %v", err)
+			}
+			defer teardown()
+			chain.TstSetCoinbaseMaturity(1)
+			mainNodes, err := addTestBlocks(chain, chain.BestChain.Tip(), 4, 0)
+			if err != nil {
+				t.Fatalf("%s: unable to add blocks: %v", test.name, err)
+			}
+			// The side branch from height 3 spends the coinbase of the first block with a script that fails.
+			spent, err := chain.BlockByHash(&mainNodes[0].hash)
+			if err != nil {
+				t.Fatalf("%s: unable to fetch block: %v", test.name, err)
+			}
+			coinbase := spent.Transactions()[0].MsgTx()
+			tx := wire.NewMsgTx(wire.TxVersion)
+			tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: coinbase.TxHash()}, []byte{txscript.OP_RETURN}, nil))
+			tx.AddTxOut(wire.NewTxOut(coinbase.TxOut[0].Value, []byte{txscript.OP_TRUE}))
+			bad, err := newTestBlock(chain, mainNodes[1], 1, tx)
+			if err != nil {
+				t.Fatalf("%s: unable to create block: %v", test.name, err)
+			}
+			if _, _, err = chain.ProcessBlock(0, bad, BFNoPoWCheck, bad.Height()); err != nil {
+				t.Fatalf("%s: side chain block rejected: %v", test.name, err)
+			}
+			badNode := chain.Index.LookupNode(bad.Hash())
+			sideNodes, err := addTestBlocks(chain, badNode, 2, 1)
+			if err != nil {
+				t.Fatalf("%s: unable to add side chain blocks: %v", test.name, err)
+			}
+			// Side chain blocks are accepted with the work of their own block only, so give the side branch more work
+			// than the main branch and reorganize to it by invalidating the main branch above the fork.
+			sideTip := tstTip(sideNodes)
+			sideTip.workSum = new(big.Int).Add(tstTip(mainNodes).workSum, big.NewInt(1))
+			chain.assumeValid = test.assumeValid(sideTip)
+			if err = chain.InvalidateBlock(&mainNodes[2].hash); err != nil {
+				t.Fatalf("%s: InvalidateBlock: unexpected err %v", test.name, err)
+			}
+			tip := chain.BestChain.Tip()
+			switch {
+			case test.connected && tip != sideTip:
+				t.Errorf("%s: best chain tip is %d %v, want the assumed valid block %d %v", test.name, tip.height,
+					tip.hash, sideTip.height, sideTip.hash)
+			case !test.connected && tip != mainNodes[1]:
+				t.Errorf("%s: best chain tip is %d %v, want %d %v", test.name, tip.height, tip.hash,
+					mainNodes[1].height, mainNodes[1].hash)
+			case !test.connected && chain.Index.NodeStatus(badNode)&statusValidateFailed == 0:
+				t.Errorf("%s: block with an invalid script is not marked invalid", test.name)
+			}
+		}()
+	}
+}
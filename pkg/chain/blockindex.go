@@ -259,6 +259,27 @@ func (bi *blockIndex) UnsetStatusFlags(node *BlockNode, flags blockStatus) {
 	bi.Unlock()
 }
 
+// StoredBlocks returns the hashes of the blocks in the index whose data is stored in the database, in ascending order
+// of height. This function is safe for concurrent access.
+func (bi *blockIndex) StoredBlocks() []chainhash.Hash {
+	bi.RLock()
+	nodes := make([]*BlockNode, 0, len(bi.index))
+	for _, node := range bi.index {
+		if node.status.HaveData() {
+			nodes = append(nodes, node)
+		}
+	}
+	bi.RUnlock()
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].height < nodes[j].height
+	})
+	hashes := make([]chainhash.Hash, len(nodes))
+	for i := range nodes {
+		hashes[i] = nodes[i].hash
+	}
+	return hashes
+}
+
 // flushToDB writes all dirty block nodes to the database. If all writes succeed, this clears the dirty set.
 func (bi *blockIndex) flushToDB() error {
 	bi.Lock()
@@ -89,6 +89,7 @@ type BlockChain struct {
 	sigCache            *txscript.SigCache
 	indexManager        IndexManager
 	hashCache           *txscript.HashCache
+	pruneTarget         uint64
 	// The following fields are calculated based upon the provided chain parameters. They are also set when the instance
 	// is created and can't be changed afterwards, so there is no need to protect them with a separate mutex.
 	minRetargetTimespan int64 // target timespan / adjustment factor
@@ -111,6 +112,14 @@ type BlockChain struct {
 	// These fields are related to checkpoint handling. They are protected by the chain lock.
 	nextCheckpoint *chaincfg.Checkpoint
 	checkpointNode *BlockNode
+	// assumeValid is the block whose ancestors are not script checked, until the best chain reaches its height and it
+	// is confirmed or found not to be in it. assumeValidChain is the chain ending in it, once it is in the block index.
+	// They are protected by the chain lock.
+	assumeValid      *chaincfg.Checkpoint
+	assumeValidChain *chainView
+	// history is the state of the validation of the blocks below the utxo set snapshot the chain state was loaded from,
+	// nil if it was not or they are validated. It is protected by the chain lock.
+	history *snapshotHistory
 	// The state is used as a fairly efficient way to cache information about the current best chain state that is
 	// returned to callers when requested. It operates on the principle of MVCC such that any time a new block becomes
 	// the best block, the state pointer is replaced with a new struct and the old state is left untouched. In this way,
@@ -525,6 +534,7 @@ func (b *BlockChain) connectBlock(node *BlockNode, block *util.Block,
 	// Prune fully spent entries and mark all entries in the view unmodified now that the modifications have been
 	// committed to the database.
 	view.commit()
+	b.pruneBlocks(node)
 
 	// This node is now the end of the best chain.
 	b.BestChain.SetTip(node)
@@ -1413,6 +1423,13 @@ type Config struct {
 	// O(N^2) validation complexity due to the SigHashAll flag. This field can be nil if the caller is not interested in
 	// using a signature cache.
 	HashCache *txscript.HashCache
+	// Prune is the size in bytes the block files are kept below by deleting the oldest of them once they are deeper
+	// than PruneDepth. Zero disables pruning.
+	Prune uint64
+	// AssumeValid is a block whose ancestors are assumed to have valid scripts, so their scripts are not checked once
+	// it is in the block index. If it turns out not to be in the best chain the skipped scripts are checked once the
+	// chain reaches its height. This field can be nil to check the scripts of every block.
+	AssumeValid *chaincfg.Checkpoint
 }
 
 // New returns a BlockChain instance using the provided configuration details.
@@ -1460,6 +1477,8 @@ func New(config *Config) (*BlockChain, error) {
 		blocksPerRetarget:     int32(targetTimespan / targetTimePerBlock),
 		Index:                 newBlockIndex(config.DB, params),
 		hashCache:             config.HashCache,
+		pruneTarget:           config.Prune,
+		assumeValid:           config.AssumeValid,
 		BestChain:             newChainView(nil),
 		orphans:               make(map[chainhash.Hash]*orphanBlock),
 		prevOrphans:           make(map[chainhash.Hash][]*orphanBlock),
@@ -1473,6 +1492,9 @@ func New(config *Config) (*BlockChain, error) {
 	if err := b.initChainState(); err != nil {
 		return nil, err
 	}
+	if err := b.initSnapshotHistory(); err != nil {
+		return nil, err
+	}
 	// Perform any upgrades to the various chain-specific buckets as needed.
 	if err := b.maybeUpgradeDbBuckets(config.Interrupt); err != nil {
 		return nil, err
@@ -1502,5 +1524,10 @@ func New(config *Config) (*BlockChain, error) {
 	Infof("chain state (height %d, hash %v, totaltx %d, work %v)",
 		bestNode.height, bestNode.hash, b.stateSnapshot.TotalTxns,
 		bestNode.workSum)
+	// A chain that is already past the assumed valid block must be checked against it now, as it may not reach the
+	// height again.
+	b.chainLock.Lock()
+	b.checkAssumeValid()
+	b.chainLock.Unlock()
 	return &b, nil
 }
@@ -1,6 +1,7 @@
 package blockchain
 
 import (
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
@@ -8,7 +9,9 @@ import (
 	chaincfg "github.com/p9c/pod/pkg/chain/config"
 	"github.com/p9c/pod/pkg/chain/config/netparams"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
 	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
 	"github.com/p9c/pod/pkg/util"
 )
 
@@ -861,3 +864,345 @@ func TestIntervalBlockHashes(t *testing.T) {
 		}
 	}
 }
+
+// TestChainTips ensures ChainTips reports the best chain tip and every side chain tip with the correct branch length and
+// status.
+func TestChainTips(t *testing.T) {
+	// Construct a synthetic block chain with a block index consisting of the following structure.
+	//
+	// 	genesis -> 1 -> 2 -> 3 -> 4 -> 5
+	// 	                \-> 3a -> 4a
+	// 	                     \-> 4b
+	tip := tstTip
+	chain := newFakeChain(&netparams.MainNetParams)
+	branch0Nodes := chainedNodes(chain.BestChain.Genesis(), 5)
+	branch1Nodes := chainedNodes(branch0Nodes[1], 2)
+	branch2Nodes := chainedNodes(branch1Nodes[0], 1)
+	for _, node := range branch0Nodes {
+		chain.Index.AddNode(node)
+	}
+	for _, node := range branch1Nodes {
+		node.status = statusDataStored | statusValid
+		chain.Index.AddNode(node)
+	}
+	for _, node := range branch2Nodes {
+		node.status = statusDataStored | statusValidateFailed
+		chain.Index.AddNode(node)
+	}
+	chain.BestChain.SetTip(tip(branch0Nodes))
+	expected := map[chainhash.Hash]ChainTip{
+		tip(branch0Nodes).hash: {Height: 5, Hash: tip(branch0Nodes).hash, BranchLen: 0, Status: ChainTipActive},
+		tip(branch1Nodes).hash: {Height: 4, Hash: tip(branch1Nodes).hash, BranchLen: 2, Status: ChainTipValidFork},
+		tip(branch2Nodes).hash: {Height: 4, Hash: tip(branch2Nodes).hash, BranchLen: 2, Status: ChainTipInvalid},
+	}
+	tips := chain.ChainTips()
+	if len(tips) != len(expected) {
+		t.Fatalf("unexpected number of tips -- got %d, want %d", len(tips), len(expected))
+	}
+	if tips[0].Status != ChainTipActive {
+		t.Errorf("first tip is not the active tip -- got %v", tips[0].Status)
+	}
+	for _, got := range tips {
+		want, ok := expected[got.Hash]
+		if !ok {
+			t.Errorf("unexpected tip %v", got.Hash)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("unexpected tip -- got %+v, want %+v", got, want)
+		}
+	}
+}
+
+// TestInvalidateReconsiderBlock ensures invalidating a block in the best chain reorganizes to the best branch without
+// it, and that reconsidering it makes the branch valid again.
+func TestInvalidateReconsiderBlock(t *testing.T) {
+	// Construct the following chain, where the main branch was seen first and is the best chain.
+	//
+	// 	genesis -> 1 -> 2 -> 3
+	// 	            \-> 2b -> 3b
+	chain, teardownFunc, err := chainSetup("invalidateblock", &netparams.RegressionTestParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+	mainNodes, err := addTestBlocks(chain, chain.BestChain.Genesis(), 3, 0)
+	if err != nil {
+		t.Fatalf("unable to add blocks: %v", err)
+	}
+	sideNodes, err := addTestBlocks(chain, mainNodes[0], 2, 1)
+	if err != nil {
+		t.Fatalf("unable to add side chain blocks: %v", err)
+	}
+	// Side chain blocks are accepted with the work of their own block only, so give the side branch the work of the
+	// main branch of the same length for the branches to compete.
+	tstTip(sideNodes).workSum = new(big.Int).Set(tstTip(mainNodes).workSum)
+	if chain.BestChain.Tip() != tstTip(mainNodes) {
+		t.Fatalf("best chain tip is %v, want %v", chain.BestChain.Tip().hash, tstTip(mainNodes).hash)
+	}
+	genesisHash := chain.BestChain.Genesis().hash
+	if err := chain.InvalidateBlock(&genesisHash); err == nil {
+		t.Errorf("InvalidateBlock: expected an error invalidating the genesis block")
+	}
+	if err := chain.InvalidateBlock(&chainhash.Hash{0x01}); err == nil {
+		t.Errorf("InvalidateBlock: expected an error for an unknown block")
+	}
+	// Invalidating 2 must mark it and its descendant invalid and reorganize to the side branch.
+	if err := chain.InvalidateBlock(&mainNodes[1].hash); err != nil {
+		t.Fatalf("InvalidateBlock: unexpected err %v", err)
+	}
+	if chain.BestChain.Tip() != tstTip(sideNodes) {
+		t.Fatalf("best chain tip after invalidate is %v, want %v", chain.BestChain.Tip().hash,
+			tstTip(sideNodes).hash)
+	}
+	if chain.Index.NodeStatus(mainNodes[1])&statusValidateFailed == 0 {
+		t.Errorf("invalidated block is not marked invalid")
+	}
+	if chain.Index.NodeStatus(mainNodes[2])&statusInvalidAncestor == 0 {
+		t.Errorf("descendant of the invalidated block is not marked invalid")
+	}
+	tips := chain.ChainTips()
+	if len(tips) != 2 || tips[1].Hash != tstTip(mainNodes).hash || tips[1].Status != ChainTipInvalid {
+		t.Errorf("unexpected tips after invalidate %+v", tips)
+	}
+	// Blocks building on the invalidated branch are rejected.
+	if _, err := addTestBlocks(chain, tstTip(mainNodes), 1, 0); err == nil {
+		t.Errorf("expected an error adding a block to the invalidated branch")
+	}
+	if chain.BestChain.Tip() != tstTip(sideNodes) {
+		t.Errorf("best chain moved to the invalidated branch")
+	}
+	// Reconsidering the descendant clears its ancestor too. The branch has no more work than the best chain, so the
+	// best chain is left alone.
+	if err := chain.ReconsiderBlock(&mainNodes[2].hash); err != nil {
+		t.Fatalf("ReconsiderBlock: unexpected err %v", err)
+	}
+	for _, node := range mainNodes {
+		if chain.Index.NodeStatus(node).KnownInvalid() {
+			t.Errorf("reconsidered block %v is still marked invalid", node.hash)
+		}
+	}
+	if chain.BestChain.Tip() != tstTip(sideNodes) {
+		t.Errorf("best chain tip after reconsider is %v, want %v", chain.BestChain.Tip().hash,
+			tstTip(sideNodes).hash)
+	}
+	tips = chain.ChainTips()
+	if len(tips) != 2 || tips[1].Hash != tstTip(mainNodes).hash || tips[1].Status != ChainTipValidFork {
+		t.Errorf("unexpected tips after reconsider %+v", tips)
+	}
+	if err := chain.ReconsiderBlock(&chainhash.Hash{0x01}); err == nil {
+		t.Errorf("ReconsiderBlock: expected an error for an unknown block")
+	}
+}
+
+// TestPreciousBlock ensures PreciousBlock reorganizes to a branch with as much work as the best chain, and leaves the
+// chain alone for blocks with less work.
+func TestPreciousBlock(t *testing.T) {
+	// Construct the following chain, where the main branch was seen first and is the best chain.
+	//
+	// 	genesis -> 1 -> 2 -> 3
+	// 	            \-> 2b -> 3b
+	chain, teardownFunc, err := chainSetup("preciousblock", &netparams.RegressionTestParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+	mainNodes, err := addTestBlocks(chain, chain.BestChain.Genesis(), 3, 0)
+	if err != nil {
+		t.Fatalf("unable to add blocks: %v", err)
+	}
+	sideNodes, err := addTestBlocks(chain, mainNodes[0], 2, 1)
+	if err != nil {
+		t.Fatalf("unable to add side chain blocks: %v", err)
+	}
+	// Side chain blocks are accepted with the work of their own block only, so give the side branch the work of the
+	// main branch of the same length for the branches to compete.
+	tstTip(sideNodes).workSum = new(big.Int).Set(tstTip(mainNodes).workSum)
+	if chain.BestChain.Tip() != tstTip(mainNodes) {
+		t.Fatalf("best chain tip is %v, want %v", chain.BestChain.Tip().hash, tstTip(mainNodes).hash)
+	}
+	// A block with less work than the tip is left alone.
+	if err := chain.PreciousBlock(&sideNodes[0].hash); err != nil {
+		t.Fatalf("PreciousBlock: unexpected err %v", err)
+	}
+	if chain.BestChain.Tip() != tstTip(mainNodes) {
+		t.Errorf("PreciousBlock reorganized to a block with less work")
+	}
+	// A block with the same work as the tip becomes the tip, and the tip can be switched back.
+	if err := chain.PreciousBlock(&tstTip(sideNodes).hash); err != nil {
+		t.Fatalf("PreciousBlock: unexpected err %v", err)
+	}
+	if chain.BestChain.Tip() != tstTip(sideNodes) {
+		t.Errorf("best chain tip is %v, want %v", chain.BestChain.Tip().hash, tstTip(sideNodes).hash)
+	}
+	if err := chain.PreciousBlock(&tstTip(mainNodes).hash); err != nil {
+		t.Fatalf("PreciousBlock: unexpected err %v", err)
+	}
+	if chain.BestChain.Tip() != tstTip(mainNodes) {
+		t.Errorf("best chain tip is %v, want %v", chain.BestChain.Tip().hash, tstTip(mainNodes).hash)
+	}
+	// An invalid block is refused.
+	if err := chain.InvalidateBlock(&tstTip(sideNodes).hash); err != nil {
+		t.Fatalf("InvalidateBlock: unexpected err %v", err)
+	}
+	if err := chain.PreciousBlock(&tstTip(sideNodes).hash); err == nil {
+		t.Errorf("PreciousBlock: expected an error for an invalid block")
+	}
+	if err := chain.PreciousBlock(&chainhash.Hash{0x01}); err == nil {
+		t.Errorf("PreciousBlock: expected an error for an unknown block")
+	}
+}
+
+// TestCheckDB ensures the chain state of a new chain is found consistent and that an output in the utxo set that no
+// block created is reported.
+func TestCheckDB(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("checkdb", &netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+	result, err := chain.CheckDB(0, nil)
+	if err != nil {
+		t.Fatalf("CheckDB: unexpected err %v", err)
+	}
+	if len(result.Discrepancies) != 0 || result.StartHeight != 0 || result.ConsistentHeight != 0 {
+		t.Fatalf("CheckDB: got %+v for a new chain", *result)
+	}
+	// Add an output to the utxo set that claims to have been created by the genesis block.
+	view := NewUtxoViewpoint()
+	view.addTxOut(wire.OutPoint{Index: 1}, wire.NewTxOut(1, []byte{txscript.OP_TRUE}), false, 0)
+	err = chain.db.Update(func(dbTx database.Tx) error {
+		return dbPutUtxoView(dbTx, view)
+	})
+	if err != nil {
+		t.Fatalf("dbPutUtxoView: unexpected err %v", err)
+	}
+	result, err = chain.CheckDB(0, nil)
+	if err != nil {
+		t.Fatalf("CheckDB: unexpected err %v", err)
+	}
+	if len(result.Discrepancies) != 1 || result.Discrepancies[0].Height != 0 || result.ConsistentHeight != -1 {
+		t.Errorf("CheckDB: got %+v with an output no block created", *result)
+	}
+}
+
+// TestRollbackTo ensures rolling back removes an unreadable utxo set entry from above the height and leaves the chain
+// consistent, and that nothing is rolled back when an unreadable entry is from the height or below, as it would not
+// be repaired.
+func TestRollbackTo(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("rollbackto", &netparams.RegressionTestParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+	nodes, err := addTestBlocks(chain, chain.BestChain.Tip(), 4, 0)
+	if err != nil {
+		t.Fatalf("unable to add blocks: %v", err)
+	}
+	// putCorruptUtxo stores an entry for the outpoint whose header claims a height but whose output can't be read.
+	putCorruptUtxo := func(outpoint wire.OutPoint, height int32) {
+		value := make([]byte, serializeSizeVLQ(uint64(height)<<1)+1)
+		offset := putVLQ(value, uint64(height)<<1)
+		value[offset] = 0x80
+		err := chain.db.Update(func(dbTx database.Tx) error {
+			return dbTx.Metadata().Bucket(utxoSetBucketName).Put(*outpointKey(outpoint), value)
+		})
+		if err != nil {
+			t.Fatalf("unable to store utxo: %v", err)
+		}
+	}
+	hasUtxo := func(outpoint wire.OutPoint) (found bool) {
+		err := chain.db.View(func(dbTx database.Tx) error {
+			found = dbTx.Metadata().Bucket(utxoSetBucketName).Get(*outpointKey(outpoint)) != nil
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unable to fetch utxo: %v", err)
+		}
+		return
+	}
+	below := wire.OutPoint{Hash: chainhash.Hash{0x01}}
+	putCorruptUtxo(below, 2)
+	if err = chain.RollbackTo(2); err == nil {
+		t.Errorf("RollbackTo: expected an error with an unreadable utxo from the height")
+	}
+	if tip := chain.BestChain.Tip(); tip != tstTip(nodes) {
+		t.Errorf("RollbackTo: best chain tip is %d %v after refusing to roll back", tip.height, tip.hash)
+	}
+	if !hasUtxo(below) {
+		t.Errorf("RollbackTo: unreadable utxo from the height was removed")
+	}
+	err = chain.db.Update(func(dbTx database.Tx) error {
+		return dbTx.Metadata().Bucket(utxoSetBucketName).Delete(*outpointKey(below))
+	})
+	if err != nil {
+		t.Fatalf("unable to remove utxo: %v", err)
+	}
+	above := wire.OutPoint{Hash: chainhash.Hash{0x02}}
+	putCorruptUtxo(above, 3)
+	if err = chain.RollbackTo(2); err != nil {
+		t.Fatalf("RollbackTo: unexpected err %v", err)
+	}
+	if hasUtxo(above) {
+		t.Errorf("RollbackTo: unreadable utxo from above the height was not removed")
+	}
+	if tip := chain.BestChain.Tip(); tip != tstTip(nodes) {
+		t.Errorf("RollbackTo: best chain tip is %d %v, want the blocks connected again", tip.height, tip.hash)
+	}
+	result, err := chain.CheckDB(0, nil)
+	if err != nil {
+		t.Fatalf("CheckDB: unexpected err %v", err)
+	}
+	if len(result.Discrepancies) != 0 {
+		t.Errorf("CheckDB: got %+v after rolling back", *result)
+	}
+}
+
+// TestRollbackToCorruptSpendJournal ensures rolling back repairs a chain whose spend journal entries above the height
+// cannot be read, restoring the outputs the disconnected blocks spent from the blocks that created them.
+func TestRollbackToCorruptSpendJournal(t *testing.T) {
+	chain, teardownFunc, err := chainSetup("rollbacktojournal", &netparams.RegressionTestParams)
+	if err != nil {
+		t.Fatalf("Failed to setup chain instance: %v", err)
+	}
+	defer teardownFunc()
+	if _, err = testTxOutSetChain(chain, 6, false); err != nil {
+		t.Fatalf("unable to add blocks: %v", err)
+	}
+	tip := chain.BestChain.Tip()
+	// The blocks from height 3 on spend an output, so the entries of those above the height cannot be read once
+	// overwritten.
+	err = chain.db.Update(func(dbTx database.Tx) error {
+		journal := dbTx.Metadata().Bucket(spendJournalBucketName)
+		for height := int32(4); height <= tip.height; height++ {
+			hash := chain.BestChain.NodeByHeight(height).hash
+			if err := journal.Put(hash[:], []byte{0xff}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to store spend journal: %v", err)
+	}
+	result, err := chain.CheckDB(0, nil)
+	if err != nil {
+		t.Fatalf("CheckDB: unexpected err %v", err)
+	}
+	if len(result.Discrepancies) == 0 {
+		t.Fatalf("CheckDB: found no discrepancy with corrupt spend journal entries")
+	}
+	if err = chain.RollbackTo(3); err != nil {
+		t.Fatalf("RollbackTo: unexpected err %v", err)
+	}
+	if chain.BestChain.Tip() != tip {
+		t.Errorf("RollbackTo: best chain tip is %d %v, want the blocks connected again", chain.BestChain.Tip().height,
+			chain.BestChain.Tip().hash)
+	}
+	if result, err = chain.CheckDB(0, nil); err != nil {
+		t.Fatalf("CheckDB: unexpected err %v", err)
+	}
+	if len(result.Discrepancies) != 0 {
+		t.Errorf("CheckDB: got %+v after rolling back", *result)
+	}
+}
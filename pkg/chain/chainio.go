@@ -653,7 +653,12 @@ func dbFetchUtxoEntry(dbTx database.Tx, outpoint wire.OutPoint) (*UtxoEntry, err
 //
 // In particular, only the entries that have been marked as modified are written to the database.
 func dbPutUtxoView(dbTx database.Tx, view *UtxoViewpoint) error {
-	utxoBucket := dbTx.Metadata().Bucket(utxoSetBucketName)
+	return dbPutUtxoViewBucket(dbTx.Metadata().Bucket(utxoSetBucketName), view)
+}
+
+// dbPutUtxoViewBucket updates the utxo set held by the provided bucket based on the provided utxo view contents and
+// state, in the same way as dbPutUtxoView does for the utxo set of the main chain.
+func dbPutUtxoViewBucket(utxoBucket database.Bucket, view *UtxoViewpoint) error {
 	for outpoint, entry := range view.entries {
 		// No need to update the database if the entry was not modified.
 		if entry == nil || !entry.isModified() {
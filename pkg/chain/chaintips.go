@@ -0,0 +1,269 @@
+package blockchain
+
+import (
+	"container/list"
+	"fmt"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+)
+
+// Chain tip status strings as returned by ChainTips. These match the values used by the reference client for the
+// getchaintips RPC.
+const (
+	// ChainTipActive is the tip of the current best chain.
+	ChainTipActive = "active"
+	// ChainTipInvalid is a branch that contains at least one invalid block.
+	ChainTipInvalid = "invalid"
+	// ChainTipValidFork is a branch that has been fully validated but is not part of the best chain.
+	ChainTipValidFork = "valid-fork"
+	// ChainTipValidHeaders is a branch for which all block data is available but which was never fully validated.
+	ChainTipValidHeaders = "valid-headers"
+	// ChainTipHeadersOnly is a branch for which not all block data is available.
+	ChainTipHeadersOnly = "headers-only"
+)
+
+// ChainTip describes the tip of a branch in the block index.
+type ChainTip struct {
+	// Height is the height of the tip block.
+	Height int32
+	// Hash is the hash of the tip block.
+	Hash chainhash.Hash
+	// BranchLen is the number of blocks between the tip and the fork point with the best chain. It is zero for the
+	// active tip.
+	BranchLen int32
+	// Status is one of the ChainTip* status strings.
+	Status string
+}
+
+// ChainTips returns information about every branch tip in the block index, including the tip of the best chain. The
+// best chain tip is always returned first. This function is safe for concurrent access.
+func (b *BlockChain) ChainTips() []ChainTip {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+	// A tip is any node in the index that is not the parent of any other node.
+	b.Index.RLock()
+	parents := make(map[*BlockNode]struct{}, len(b.Index.index))
+	for _, node := range b.Index.index {
+		if node.parent != nil {
+			parents[node.parent] = struct{}{}
+		}
+	}
+	var tips []*BlockNode
+	for _, node := range b.Index.index {
+		if _, ok := parents[node]; !ok {
+			tips = append(tips, node)
+		}
+	}
+	b.Index.RUnlock()
+	bestTip := b.BestChain.Tip()
+	results := []ChainTip{{
+		Height: bestTip.height,
+		Hash:   bestTip.hash,
+		Status: ChainTipActive,
+	}}
+	for _, tip := range tips {
+		if tip == bestTip {
+			continue
+		}
+		forkNode := b.BestChain.FindFork(tip)
+		var branchLen int32
+		if forkNode != nil {
+			branchLen = tip.height - forkNode.height
+		}
+		results = append(results, ChainTip{
+			Height:    tip.height,
+			Hash:      tip.hash,
+			BranchLen: branchLen,
+			Status:    b.branchStatus(tip, forkNode),
+		})
+	}
+	return results
+}
+
+// branchStatus returns the ChainTip* status of the side chain branch from tip back to (but not including) forkNode.
+// This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) branchStatus(tip, forkNode *BlockNode) string {
+	status := ChainTipValidFork
+	for n := tip; n != nil && n != forkNode; n = n.parent {
+		nodeStatus := b.Index.NodeStatus(n)
+		switch {
+		case nodeStatus.KnownInvalid():
+			return ChainTipInvalid
+		case !nodeStatus.HaveData():
+			status = ChainTipHeadersOnly
+		case !nodeStatus.KnownValid() && status == ChainTipValidFork:
+			status = ChainTipValidHeaders
+		}
+	}
+	return status
+}
+
+// InvalidateBlock permanently marks the block with the given hash, and all of its descendants, as invalid. If the block
+// is part of the best chain, the chain is reorganized to the most-work branch that does not contain it. The new status
+// is persisted to the block index in the database. This function is safe for concurrent access.
+func (b *BlockChain) InvalidateBlock(hash *chainhash.Hash) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+	node := b.Index.LookupNode(hash)
+	if node == nil {
+		return fmt.Errorf("block %v is not known", hash)
+	}
+	if node.parent == nil {
+		return fmt.Errorf("cannot invalidate the genesis block")
+	}
+	b.Index.SetStatusFlags(node, statusValidateFailed)
+	for _, n := range b.descendants(node) {
+		b.Index.SetStatusFlags(n, statusInvalidAncestor)
+	}
+	if err := b.Index.flushToDB(); err != nil {
+		Error(err)
+		return err
+	}
+	if !b.BestChain.Contains(node) {
+		return nil
+	}
+	Warnf("INVALIDATE: block %v (height %d) is in the best chain, reorganizing", node.hash, node.height)
+	return b.activateBestValidChain(node.parent)
+}
+
+// ReconsiderBlock removes invalidity status from the block with the given hash as well as its ancestors and
+// descendants, reverting the effect of InvalidateBlock. Blocks that genuinely break the consensus rules will be marked
+// invalid again when they are next validated. If the reconsidered branch has more work than the current best chain the
+// chain is reorganized onto it. This function is safe for concurrent access.
+func (b *BlockChain) ReconsiderBlock(hash *chainhash.Hash) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+	node := b.Index.LookupNode(hash)
+	if node == nil {
+		return fmt.Errorf("block %v is not known", hash)
+	}
+	const invalidFlags = statusValidateFailed | statusInvalidAncestor
+	for n := node; n != nil; n = n.parent {
+		if b.Index.NodeStatus(n).KnownInvalid() {
+			b.Index.UnsetStatusFlags(n, invalidFlags)
+		}
+	}
+	for _, n := range b.descendants(node) {
+		if b.Index.NodeStatus(n).KnownInvalid() {
+			b.Index.UnsetStatusFlags(n, invalidFlags)
+		}
+	}
+	if err := b.Index.flushToDB(); err != nil {
+		Error(err)
+		return err
+	}
+	return b.activateBestValidChain(b.BestChain.Tip())
+}
+
+// PreciousBlock treats the block with the given hash as if it was received before any competing block with the same
+// amount of work, reorganizing the chain onto it if required. Blocks with less work than the current best chain tip
+// are left alone. This function is safe for concurrent access.
+func (b *BlockChain) PreciousBlock(hash *chainhash.Hash) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+	node := b.Index.LookupNode(hash)
+	if node == nil {
+		return fmt.Errorf("block %v is not known", hash)
+	}
+	if b.BestChain.Contains(node) || node.workSum.Cmp(b.BestChain.Tip().workSum) < 0 {
+		return nil
+	}
+	if b.Index.NodeStatus(node).KnownInvalid() {
+		return fmt.Errorf("block %v is known to be invalid", hash)
+	}
+	if !b.branchHasData(node) {
+		return fmt.Errorf("block data for the branch ending at %v is not available", hash)
+	}
+	err := b.reorganizeTo(node)
+	if writeErr := b.Index.flushToDB(); writeErr != nil {
+		Error(writeErr)
+	}
+	return err
+}
+
+// descendants returns every node in the block index that has the passed node as an ancestor. This function MUST be
+// called with the chain state lock held (for reads).
+func (b *BlockChain) descendants(node *BlockNode) (nodes []*BlockNode) {
+	b.Index.RLock()
+	defer b.Index.RUnlock()
+	for _, n := range b.Index.index {
+		if n.height > node.height && n.Ancestor(node.height) == node {
+			nodes = append(nodes, n)
+		}
+	}
+	return
+}
+
+// branchHasData returns whether the block data for every block between the passed node and the best chain is stored in
+// the database. This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) branchHasData(node *BlockNode) bool {
+	forkNode := b.BestChain.FindFork(node)
+	for n := node; n != nil && n != forkNode; n = n.parent {
+		if !b.Index.NodeStatus(n).HaveData() {
+			return false
+		}
+	}
+	return true
+}
+
+// findBestValidTip returns the node with the most cumulative work that is not known to be invalid and for which all
+// block data back to the best chain is available. The passed node is returned if no candidate has more work than it.
+// This function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) findBestValidTip(best *BlockNode) *BlockNode {
+	b.Index.RLock()
+	candidates := make([]*BlockNode, 0, len(b.Index.index))
+	for _, n := range b.Index.index {
+		if n.workSum != nil && n.workSum.Cmp(best.workSum) > 0 {
+			candidates = append(candidates, n)
+		}
+	}
+	b.Index.RUnlock()
+	for _, n := range candidates {
+		if n.workSum.Cmp(best.workSum) <= 0 || b.Index.NodeStatus(n).KnownInvalid() || !b.branchHasData(n) {
+			continue
+		}
+		best = n
+	}
+	return best
+}
+
+// activateBestValidChain reorganizes the chain to the valid branch with the most work, which will be at least the passed
+// fallback node. Branches that fail validation during the reorganization are marked invalid and the next best branch
+// is tried. This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) activateBestValidChain(fallback *BlockNode) error {
+	for {
+		target := b.findBestValidTip(fallback)
+		if target == b.BestChain.Tip() {
+			return nil
+		}
+		err := b.reorganizeTo(target)
+		if writeErr := b.Index.flushToDB(); writeErr != nil {
+			Error(writeErr)
+		}
+		if err == nil {
+			return nil
+		}
+		// A rule violation marks the offending block invalid so the next iteration will pick a different branch.
+		if _, ok := err.(RuleError); !ok {
+			Error(err)
+			return err
+		}
+		Warn("reorganization to", target.hash, "failed, trying next best branch:", err)
+	}
+}
+
+// reorganizeTo reorganizes the best chain so that the passed node becomes its tip. The node may be an ancestor of the
+// current tip, in which case blocks are only disconnected. This function MUST be called with the chain state lock held
+// (for writes).
+func (b *BlockChain) reorganizeTo(node *BlockNode) error {
+	attachNodes := list.New()
+	detachNodes := list.New()
+	forkNode := b.BestChain.FindFork(node)
+	for n := node; n != nil && n != forkNode; n = n.parent {
+		attachNodes.PushFront(n)
+	}
+	for n := b.BestChain.Tip(); n != nil && n != forkNode; n = n.parent {
+		detachNodes.PushBack(n)
+	}
+	return b.reorganizeChain(detachNodes, attachNodes)
+}
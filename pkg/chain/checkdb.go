@@ -0,0 +1,483 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// DBDiscrepancy describes an inconsistency between the chain state stored in the database and the blocks of the main
+// chain.
+type DBDiscrepancy struct {
+	// Height is the height of the block the inconsistency was found at.
+	Height int32
+	// Hash is the hash of the block the inconsistency was found at.
+	Hash chainhash.Hash
+	// Description tells what is inconsistent.
+	Description string
+}
+
+// DBCheckResult is the outcome of CheckDB.
+type DBCheckResult struct {
+	// StartHeight and EndHeight are the heights of the first and last block whose utxos were checked against the
+	// spend journal.
+	StartHeight int32
+	EndHeight   int32
+	// Discrepancies holds the inconsistencies found, from the highest block down.
+	Discrepancies []DBDiscrepancy
+	// ConsistentHeight is the height of the highest block up to which no inconsistency was found. It is the height of
+	// the best block when there are no discrepancies, and -1 when even the genesis block is affected.
+	ConsistentHeight int32
+}
+
+// report adds a discrepancy found at a block to the result and lowers the consistent height below it.
+func (r *DBCheckResult) report(node *BlockNode, format string, args ...interface{}) {
+	r.Discrepancies = append(r.Discrepancies, DBDiscrepancy{
+		Height:      node.height,
+		Hash:        node.hash,
+		Description: fmt.Sprintf(format, args...),
+	})
+	if node.height-1 < r.ConsistentHeight {
+		r.ConsistentHeight = node.height - 1
+	}
+}
+
+// utxoEntryMatches returns whether a utxo entry holds the passed output created at a height.
+func utxoEntryMatches(entry *UtxoEntry, txOut *wire.TxOut, height int32, isCoinBase bool) bool {
+	return entry.Amount() == txOut.Value && bytes.Equal(entry.PkScript(), txOut.PkScript) &&
+		entry.BlockHeight() == height && entry.IsCoinBase() == isCoinBase
+}
+
+// CheckDB verifies that the chain state in the database agrees with the blocks of the main chain. The height and hash
+// indexes, the block index and the best chain state are checked for the last checkBlocks blocks, or every block when
+// checkBlocks is not positive, and the utxo set is checked against a replay of the spend journal of those blocks from
+// the best block down. Every unspent output created by them must be in the utxo set, none that they spend may be, and
+// the utxo set may hold no other outputs from that range of heights.
+//
+// Blocks that were removed by pruning or lie below a utxo set snapshot the chain was loaded from end the range early.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) CheckDB(checkBlocks int32, interrupt <-chan struct{}) (*DBCheckResult, error) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+	tip := b.BestChain.Tip()
+	startHeight := int32(0)
+	if checkBlocks > 0 && tip.height-checkBlocks+1 > 0 {
+		startHeight = tip.height - checkBlocks + 1
+	}
+	base, err := SnapshotBase(b.db)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if base != nil {
+		if node := b.Index.LookupNode(base); node != nil && node.height >= startHeight {
+			startHeight = node.height + 1
+		}
+	}
+	// The start height is lowered as blocks are checked, so it ends at the lowest block that was.
+	result := &DBCheckResult{StartHeight: tip.height + 1, EndHeight: tip.height, ConsistentHeight: tip.height}
+	err = b.db.View(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		state, err := deserializeBestChainState(meta.Get(chainStateKeyName))
+		if err != nil {
+			result.report(tip, "best chain state cannot be read: %v", err)
+		} else if state.hash != tip.hash || int32(state.height) != tip.height {
+			result.report(tip, "best chain state is block %v at height %d", state.hash, state.height)
+		}
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Disconnect the blocks in a view from the best block down, checking each block against the utxo set as it was
+	// before the blocks above it were connected. The number of outputs of each block found in the utxo set is recorded
+	// so any others from the checked heights can be found afterwards.
+	view := NewUtxoViewpoint()
+	view.SetBestHash(&tip.hash)
+	found := make(map[int32]int)
+	for node := tip; node != nil && node.height >= startHeight; node = node.parent {
+		if interruptRequested(interrupt) {
+			return nil, errInterruptRequested
+		}
+		var block *util.Block
+		var stxos []SpentTxOut
+		var journalErr error
+		err = b.db.View(func(dbTx database.Tx) error {
+			meta := dbTx.Metadata()
+			if hash, err := dbFetchHashByHeight(dbTx, node.height); err != nil || *hash != node.hash {
+				result.report(node, "height index does not hold the block")
+			}
+			if height, err := dbFetchHeightByHash(dbTx, &node.hash); err != nil || height != node.height {
+				result.report(node, "hash index does not hold the block")
+			}
+			if meta.Bucket(blockIndexBucketName).Get(blockIndexKey(&node.hash, uint32(node.height))) == nil {
+				result.report(node, "block index does not hold the block")
+			}
+			blockBytes, err := dbTx.FetchBlock(&node.hash)
+			if err != nil {
+				return nil
+			}
+			if block, err = util.NewBlockFromBytes(blockBytes); err != nil {
+				result.report(node, "stored block cannot be read: %v", err)
+				block = nil
+				return nil
+			}
+			block.SetHeight(node.height)
+			stxos, journalErr = dbFetchSpendJournalEntry(dbTx, block)
+			return nil
+		})
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		if block == nil {
+			if !b.IsPruned() {
+				result.report(node, "block data is missing")
+			}
+			break
+		}
+		if journalErr != nil {
+			result.report(node, "spend journal cannot be read: %v", journalErr)
+			break
+		}
+		if len(stxos) != countSpentOutputs(block) {
+			result.report(node, "spend journal holds %d spent outputs for %d inputs", len(stxos),
+				countSpentOutputs(block))
+			break
+		}
+		err = b.checkBlockUtxos(view, block, node, result, found)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		err = view.disconnectTransactions(b.db, block, stxos)
+		if err != nil {
+			result.report(node, "spend journal cannot be replayed: %v", err)
+			break
+		}
+		result.StartHeight = node.height
+	}
+	// Any other output in the utxo set from the checked heights was not created by the main chain, and none can be
+	// from above the best block. The outputs held for each checked height are counted first, and only the heights
+	// holding more than were found are searched for the outputs their block did not create.
+	held := make(map[int32]int)
+	err = b.db.View(func(dbTx database.Tx) error {
+		return forEachUtxo(dbTx, func(outpoint wire.OutPoint, entry *UtxoEntry, err error) {
+			switch {
+			case err != nil:
+				result.report(tip, "%v", err)
+			case entry.BlockHeight() > tip.height:
+				result.report(tip, "utxo %v is from height %d above the best block", outpoint, entry.BlockHeight())
+			case entry.BlockHeight() >= result.StartHeight:
+				held[entry.BlockHeight()]++
+			}
+		})
+	})
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	stray := make(map[int32]*util.Block)
+	for height, n := range held {
+		if n > found[height] {
+			stray[height] = nil
+		}
+	}
+	if len(stray) == 0 {
+		return result, nil
+	}
+	err = b.db.View(func(dbTx database.Tx) error {
+		for height := range stray {
+			block, err := dbFetchBlockByNode(dbTx, b.BestChain.NodeByHeight(height))
+			if err != nil {
+				return err
+			}
+			stray[height] = block
+		}
+		return forEachUtxo(dbTx, func(outpoint wire.OutPoint, entry *UtxoEntry, err error) {
+			if err != nil {
+				return
+			}
+			block, ok := stray[entry.BlockHeight()]
+			if ok && !blockCreatesOutput(block, outpoint) {
+				result.report(b.BestChain.NodeByHeight(entry.BlockHeight()), "utxo %v was not created by the block",
+					outpoint)
+			}
+		})
+	})
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// forEachUtxo calls fn with every entry of the utxo set, or with an error describing an entry that cannot be read.
+func forEachUtxo(dbTx database.Tx, fn func(outpoint wire.OutPoint, entry *UtxoEntry, err error)) error {
+	cursor := dbTx.Metadata().Bucket(utxoSetBucketName).Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key := cursor.Key()
+		var outpoint wire.OutPoint
+		if len(key) <= chainhash.HashSize {
+			fn(outpoint, nil, fmt.Errorf("utxo key of %d bytes is too short", len(key)))
+			continue
+		}
+		copy(outpoint.Hash[:], key[:chainhash.HashSize])
+		index, _ := deserializeVLQ(key[chainhash.HashSize:])
+		outpoint.Index = uint32(index)
+		entry, err := deserializeUtxoEntry(cursor.Value())
+		if err != nil {
+			fn(outpoint, nil, fmt.Errorf("utxo %v cannot be read: %v", outpoint, err))
+			continue
+		}
+		fn(outpoint, entry, nil)
+	}
+	return nil
+}
+
+// blockCreatesOutput returns whether the passed outpoint is a spendable output of a transaction of the block.
+func blockCreatesOutput(block *util.Block, outpoint wire.OutPoint) bool {
+	for _, tx := range block.Transactions() {
+		if *tx.Hash() != outpoint.Hash {
+			continue
+		}
+		txOuts := tx.MsgTx().TxOut
+		return outpoint.Index < uint32(len(txOuts)) && !txscript.IsUnspendable(txOuts[outpoint.Index].PkScript)
+	}
+	return false
+}
+
+// checkBlockUtxos checks the outputs a block creates and spends against the utxo set as viewed after the blocks above
+// it were disconnected. The outputs of the block found in the utxo set are counted in found at its height.
+func (b *BlockChain) checkBlockUtxos(view *UtxoViewpoint, block *util.Block, node *BlockNode,
+	result *DBCheckResult, found map[int32]int) error {
+	// Outputs spent within the block never reach the utxo set.
+	spentInBlock := make(map[wire.OutPoint]struct{})
+	for _, tx := range block.Transactions()[1:] {
+		for _, txIn := range tx.MsgTx().TxIn {
+			spentInBlock[txIn.PreviousOutPoint] = struct{}{}
+		}
+	}
+	return b.db.View(func(dbTx database.Tx) error {
+		for txIdx, tx := range block.Transactions() {
+			isCoinBase := txIdx == 0
+			// The outputs of the genesis block are never added to the utxo set.
+			if node.height == 0 {
+				break
+			}
+			outpoint := wire.OutPoint{Hash: *tx.Hash()}
+			for txOutIdx, txOut := range tx.MsgTx().TxOut {
+				outpoint.Index = uint32(txOutIdx)
+				if _, ok := spentInBlock[outpoint]; ok || txscript.IsUnspendable(txOut.PkScript) {
+					continue
+				}
+				// An output in the view was spent by a block above and restored from its spend journal entry.
+				if entry := view.LookupEntry(outpoint); entry != nil {
+					if entry.IsSpent() || !utxoEntryMatches(entry, txOut, node.height, isCoinBase) {
+						result.report(node, "spend journal of a later block does not match output %v", outpoint)
+					}
+					continue
+				}
+				entry, err := dbFetchUtxoEntry(dbTx, outpoint)
+				if err != nil {
+					result.report(node, "utxo %v cannot be read: %v", outpoint, err)
+					continue
+				}
+				switch {
+				case entry == nil:
+					result.report(node, "output %v is missing from the utxo set", outpoint)
+				case !utxoEntryMatches(entry, txOut, node.height, isCoinBase):
+					result.report(node, "utxo %v does not match the output", outpoint)
+					found[node.height]++
+				default:
+					found[node.height]++
+				}
+			}
+		}
+		for _, tx := range block.Transactions()[1:] {
+			for _, txIn := range tx.MsgTx().TxIn {
+				prevOut := txIn.PreviousOutPoint
+				if entry := view.LookupEntry(prevOut); entry != nil {
+					if !entry.IsSpent() {
+						result.report(node, "output %v is spent again by a later block", prevOut)
+					}
+					continue
+				}
+				entry, err := dbFetchUtxoEntry(dbTx, prevOut)
+				if err == nil && entry != nil {
+					result.report(node, "output %v spent by the block is still in the utxo set", prevOut)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// RollbackTo disconnects the blocks of the main chain above the passed height and removes any output from above the
+// height that is left in the utxo set. The spend journal entries of those blocks may be what drifted, so they are first
+// rebuilt from the block data and the outputs the blocks spent are restored from the blocks that created them. The
+// stored blocks are then connected again, which rebuilds their utxos and spend journal entries from the block data, so
+// a chain state that drifted from the blocks above a consistent height is repaired. A utxo set entry that cannot be
+// read is only removed if it is from above the height, otherwise nothing is rolled back as it would not be repaired.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) RollbackTo(height int32) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+	tip := b.BestChain.Tip()
+	if height < 0 || height > tip.height {
+		return fmt.Errorf("height %d is not in the main chain", height)
+	}
+	if b.IsPruned() {
+		return fmt.Errorf("cannot roll back a pruned chain")
+	}
+	err := b.db.View(func(dbTx database.Tx) error {
+		_, err := strayUtxos(dbTx.Metadata().Bucket(utxoSetBucketName), height)
+		return err
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	Warnf("rolling back the chain from height %d to %d", tip.height, height)
+	if err = b.rebuildSpendJournals(height); err != nil {
+		Error(err)
+		return err
+	}
+	if err = b.reorganizeTo(b.BestChain.NodeByHeight(height)); err != nil {
+		Error(err)
+		return err
+	}
+	err = b.db.Update(func(dbTx database.Tx) error {
+		utxos := dbTx.Metadata().Bucket(utxoSetBucketName)
+		stray, err := strayUtxos(utxos, height)
+		if err != nil {
+			return err
+		}
+		for _, key := range stray {
+			if err := utxos.Delete(key); err != nil {
+				return err
+			}
+		}
+		if len(stray) > 0 {
+			Warnf("removed %d outputs from above height %d from the utxo set", len(stray), height)
+		}
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	return b.activateBestValidChain(b.BestChain.Tip())
+}
+
+// strayUtxos returns the keys of the entries of the utxo set from above the passed height. The height of an entry is
+// serialized ahead of its output, so it can still be known for an entry whose output cannot be read. An error is
+// returned for an entry that cannot be read and is not known to be from above the height.
+func strayUtxos(utxos database.Bucket, height int32) ([][]byte, error) {
+	var stray [][]byte
+	cursor := utxos.Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		value := cursor.Value()
+		entryHeight := int32(-1)
+		entry, err := deserializeUtxoEntry(value)
+		if err == nil {
+			entryHeight = entry.BlockHeight()
+		} else if code, offset := deserializeVLQ(value); offset > 0 && offset < len(value) {
+			entryHeight = int32(code >> 1)
+		}
+		if entryHeight > height {
+			stray = append(stray, append([]byte{}, cursor.Key()...))
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("utxo %x cannot be read and is not from above height %d, so rolling back "+
+				"would not repair it: %v", cursor.Key(), height, err)
+		}
+	}
+	return stray, nil
+}
+
+// rebuildSpendJournals rewrites the spend journal entries of the blocks of the main chain above the passed height from
+// the block data. The outputs spent by these blocks are read from the blocks that created them, searching down from the
+// height for those created below it, so neither the old spend journal entries nor the utxo set are relied on. This
+// function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) rebuildSpendJournals(height int32) error {
+	tip := b.BestChain.Tip()
+	blocks := make([]*util.Block, 0, tip.height-height)
+	// spent maps the outputs spent by the blocks to their details, which are nil until the creating block is found.
+	spent := make(map[wire.OutPoint]*SpentTxOut)
+	// fill records the details of the outputs of a block that are spent, returning how many were found.
+	fill := func(block *util.Block, blockHeight int32) (n int) {
+		for txIdx, tx := range block.Transactions() {
+			outpoint := wire.OutPoint{Hash: *tx.Hash()}
+			for txOutIdx, txOut := range tx.MsgTx().TxOut {
+				outpoint.Index = uint32(txOutIdx)
+				if stxo, ok := spent[outpoint]; ok && stxo == nil {
+					spent[outpoint] = &SpentTxOut{
+						Amount:     txOut.Value,
+						PkScript:   txOut.PkScript,
+						Height:     blockHeight,
+						IsCoinBase: txIdx == 0,
+					}
+					n++
+				}
+			}
+		}
+		return
+	}
+	err := b.db.View(func(dbTx database.Tx) error {
+		for h := height + 1; h <= tip.height; h++ {
+			block, err := dbFetchBlockByNode(dbTx, b.BestChain.NodeByHeight(h))
+			if err != nil {
+				return err
+			}
+			for _, tx := range block.Transactions()[1:] {
+				for _, txIn := range tx.MsgTx().TxIn {
+					spent[txIn.PreviousOutPoint] = nil
+				}
+			}
+			blocks = append(blocks, block)
+		}
+		missing := len(spent)
+		for i, block := range blocks {
+			missing -= fill(block, height+1+int32(i))
+		}
+		for h := height; h >= 0 && missing > 0; h-- {
+			block, err := dbFetchBlockByNode(dbTx, b.BestChain.NodeByHeight(h))
+			if err != nil {
+				return err
+			}
+			missing -= fill(block, h)
+		}
+		if missing > 0 {
+			return AssertError(fmt.Sprintf("%d outputs spent above height %d were not created by the main chain",
+				missing, height))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return b.db.Update(func(dbTx database.Tx) error {
+		for _, block := range blocks {
+			stxos := make([]SpentTxOut, 0, countSpentOutputs(block))
+			for _, tx := range block.Transactions()[1:] {
+				for _, txIn := range tx.MsgTx().TxIn {
+					stxos = append(stxos, *spent[txIn.PreviousOutPoint])
+				}
+			}
+			if err := dbPutSpendJournalEntry(dbTx, block.Hash(), stxos); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
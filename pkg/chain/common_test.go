@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/fork"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
 	txscript "github.com/p9c/pod/pkg/chain/tx/script"
 	"github.com/p9c/pod/pkg/util"
@@ -339,3 +340,64 @@ func newFakeNode(parent *BlockNode, blockVersion int32, bits uint32, timestamp t
 	}
 	return NewBlockNode(header, parent)
 }
+
+// newTestBlock returns a block building on the passed parent node that is valid apart from its proof of work, so it
+// must be processed with BFNoPoWCheck. The coinbase pays the block subsidy to an anyone-can-spend script and commits to
+// extraNonce, which tells apart blocks at the same height on competing branches. The passed transactions are included
+// after the coinbase.
+func newTestBlock(b *BlockChain, parent *BlockNode, extraNonce int64, txs ...*wire.MsgTx) (*util.Block, error) {
+	const version = 2
+	height := parent.height + 1
+	timestamp := time.Unix(parent.timestamp+fork.GetTargetTimePerBlock(height), 0)
+	bits, err := b.calcNextRequiredDifficulty(0, parent, timestamp, fork.GetAlgoName(version, height), false)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseScript, err := txscript.NewScriptBuilder().AddInt64(int64(height)).AddInt64(extraNonce).Script()
+	if err != nil {
+		return nil, err
+	}
+	coinbase := wire.NewMsgTx(wire.TxVersion)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex),
+		SignatureScript:  coinbaseScript,
+		Sequence:         wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(wire.NewTxOut(CalcBlockSubsidy(height, b.params, version), []byte{txscript.OP_TRUE}))
+	msgBlock := &wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   version,
+			PrevBlock: parent.hash,
+			Timestamp: timestamp,
+			Bits:      bits,
+		},
+	}
+	msgBlock.AddTransaction(coinbase)
+	for _, tx := range txs {
+		msgBlock.AddTransaction(tx)
+	}
+	block := util.NewBlock(msgBlock)
+	merkles := BuildMerkleTreeStore(block.Transactions(), false)
+	msgBlock.Header.MerkleRoot = *merkles[len(merkles)-1]
+	block = util.NewBlock(msgBlock)
+	block.SetHeight(height)
+	return block, nil
+}
+
+// addTestBlocks builds a branch of numBlocks blocks on the passed parent node with newTestBlock and processes them,
+// returning the block nodes of the branch.
+func addTestBlocks(b *BlockChain, parent *BlockNode, numBlocks int, extraNonce int64) ([]*BlockNode, error) {
+	nodes := make([]*BlockNode, 0, numBlocks)
+	for i := 0; i < numBlocks; i++ {
+		block, err := newTestBlock(b, parent, extraNonce)
+		if err != nil {
+			return nil, err
+		}
+		if _, _, err = b.ProcessBlock(0, block, BFNoPoWCheck, block.Height()); err != nil {
+			return nil, err
+		}
+		parent = b.Index.LookupNode(block.Hash())
+		nodes = append(nodes, parent)
+	}
+	return nodes, nil
+}
@@ -176,6 +176,9 @@ type Params struct {
 	GenerateSupported bool
 	// Checkpoints ordered from oldest to newest.
 	Checkpoints []Checkpoint
+	// AssumeValid is a block in the main chain whose ancestors are assumed to have valid scripts, so their scripts are
+	// not checked while syncing. All other rules are still enforced. Nil checks the scripts of every block.
+	AssumeValid *Checkpoint
 	// These fields are related to voting on consensus rule changes as defined by BIP0009.
 	//
 	// RuleChangeActivationThreshold is the number of blocks in a threshold state retarget window for which a positive
@@ -35,6 +35,8 @@ var MainNetParams = Params{
 		// {, newHashFromStr("")},
 		// {200069, newHashFromStr("000000000000044e641986c8ee672460e853a11b352869cb8a4a8ba0b3f3e6dc")},
 	},
+	// AssumeValid is the block whose ancestors are assumed to have valid scripts.
+	AssumeValid: nil,
 	// Consensus rule change deployments.
 	//
 	// The miner confirmation window is defined as:
@@ -31,6 +31,8 @@ var RegressionTestParams = Params{
 	GenerateSupported:        true,
 	// Checkpoints ordered from oldest to newest.
 	Checkpoints: nil,
+	// AssumeValid is the block whose ancestors are assumed to have valid scripts.
+	AssumeValid: nil,
 	// Consensus rule change deployments.
 	//
 	// The miner confirmation window is defined as:
@@ -35,6 +35,8 @@ var SimNetParams = Params{
 	GenerateSupported:        true,
 	// Checkpoints ordered from oldest to newest.
 	Checkpoints: nil,
+	// AssumeValid is the block whose ancestors are assumed to have valid scripts.
+	AssumeValid: nil,
 	// Consensus rule change deployments.
 	//
 	// The miner confirmation window is defined as:
@@ -36,6 +36,8 @@ var TestNet3Params = Params{
 	Checkpoints: []Checkpoint{
 		// {546, newHashFromStr("000000002a936ca763904c3c35fce2f3556c559c0214345d31b1bcebf76acb70")},
 	},
+	// AssumeValid is the block whose ancestors are assumed to have valid scripts.
+	AssumeValid: nil,
 	// Consensus rule change deployments.
 	//
 	// The miner confirmation window is defined as:
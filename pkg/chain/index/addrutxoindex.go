@@ -0,0 +1,605 @@
+package indexers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	qu "github.com/p9c/pod/pkg/util/quit"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// addrUtxoIndexName is the human-readable name for the index.
+	addrUtxoIndexName = "address utxo index"
+	// addrUtxoKeySize is the size of a key of the unspent outputs bucket. It consists of the address key + 32 bytes
+	// transaction hash + 4 bytes output index.
+	addrUtxoKeySize = addrKeySize + chainhash.HashSize + 4
+	// addrDeltaKeySize is the size of a key of the deltas bucket. It consists of the address key + 4 bytes block height
+	// + 4 bytes transaction index in the block + 1 byte spending flag + 4 bytes input or output index.
+	addrDeltaKeySize = addrKeySize + 4 + 4 + 1 + 4
+	// addrBalanceSize is the size of a value of the balances bucket. It consists of 8 bytes balance + 8 bytes total
+	// received.
+	addrBalanceSize = 8 + 8
+)
+
+var (
+	// addrUtxoIndexKey is the key of the address utxo index and the db bucket used to house it.
+	addrUtxoIndexKey = []byte("utxobyaddridx")
+	// addrUtxoBucketName is the name of the bucket holding the unspent outputs of each address.
+	addrUtxoBucketName = []byte("utxos")
+	// addrDeltaBucketName is the name of the bucket holding the changes transactions made to the balance of each
+	// address.
+	addrDeltaBucketName = []byte("deltas")
+	// addrBalanceBucketName is the name of the bucket holding the balance of each address.
+	addrBalanceBucketName = []byte("balances")
+	// keyOrder is the byte order used for the numeric fields of keys, which must sort in numeric order.
+	keyOrder = binary.BigEndian
+)
+
+// The address utxo index maps addresses to their unspent outputs, their balance and the changes each transaction in the
+// main chain made to it. Unlike the address index it is updated as outputs are spent, so the balance and unspent
+// outputs of an address can be queried directly. Blocks are only ever added to and removed from the tip of the index,
+// and removing a block restores the outputs it spent from the spend journal, so it stays correct across
+// reorganizations. Only outputs paying to a single address are indexed, as the amount of a bare multisig output can't
+// be attributed to one of its keys.
+//
+// The index consists of three buckets, whose keys all start with the address key described with the address index.
+//
+// The unspent outputs bucket:
+//   <addr key><tx hash><output index> => <height><amount><pk script>
+//   Field           Type      Size
+//   addr key        [21]byte  21 bytes
+//   tx hash         hash      32 bytes
+//   output index    uint32    4 bytes (big endian)
+//   height          uint32    4 bytes
+//   amount          int64     8 bytes
+//   pk script       []byte    variable
+//
+// The deltas bucket, ordered by height and position in the block:
+//   <addr key><height><tx index><spending><index> => <tx hash><amount>
+//   Field           Type      Size
+//   addr key        [21]byte  21 bytes
+//   height          uint32    4 bytes (big endian)
+//   tx index        uint32    4 bytes (big endian)
+//   spending        uint8     1 byte, 1 for an input and 0 for an output
+//   index           uint32    4 bytes (big endian)
+//   tx hash         hash      32 bytes
+//   amount          int64     8 bytes, negative for an input
+//
+// The balances bucket, without entries for addresses that never received anything:
+//   <addr key> => <balance><received>
+//   Field           Type      Size
+//   addr key        [21]byte  21 bytes
+//   balance         int64     8 bytes
+//   received        int64     8 bytes
+
+// AddrUtxo is an unspent output paying to an address.
+type AddrUtxo struct {
+	OutPoint wire.OutPoint
+	Height   int32
+	Amount   int64
+	PkScript []byte
+}
+
+// AddrDelta is the change a transaction in the main chain made to the balance of an address, by spending an output
+// paying to it or creating one.
+type AddrDelta struct {
+	TxHash     chainhash.Hash
+	Height     int32
+	BlockIndex uint32
+	Spending   bool
+	Index      uint32
+	Amount     int64
+}
+
+// AddrBalance is the balance of an address along with the total it ever received.
+type AddrBalance struct {
+	Balance  int64
+	Received int64
+}
+
+// AddrMempoolDelta is the change an unconfirmed transaction makes to the balance of an address. PrevOut is the output
+// spent by the input if the transaction spends from the address.
+type AddrMempoolDelta struct {
+	TxHash  chainhash.Hash
+	Index   uint32
+	Amount  int64
+	Time    time.Time
+	PrevOut *wire.OutPoint
+}
+
+// addrUtxoBuckets are the buckets of the address utxo index. They are abstracted to allow the tests to use mocks.
+type addrUtxoBuckets struct {
+	utxos    internalBucket
+	deltas   internalBucket
+	balances internalBucket
+}
+
+// addrUtxoKey returns the key of the passed output in the unspent outputs bucket.
+func addrUtxoKey(addrKey [addrKeySize]byte, op *wire.OutPoint) []byte {
+	key := make([]byte, addrUtxoKeySize)
+	copy(key, addrKey[:])
+	copy(key[addrKeySize:], op.Hash[:])
+	keyOrder.PutUint32(key[addrKeySize+chainhash.HashSize:], op.Index)
+	return key
+}
+
+// addrDeltaKey returns the key of the change made by the passed input or output in the deltas bucket.
+func addrDeltaKey(addrKey [addrKeySize]byte, height int32, blockIndex uint32, spending bool, index uint32) []byte {
+	key := make([]byte, addrDeltaKeySize)
+	copy(key, addrKey[:])
+	keyOrder.PutUint32(key[addrKeySize:], uint32(height))
+	keyOrder.PutUint32(key[addrKeySize+4:], blockIndex)
+	if spending {
+		key[addrKeySize+8] = 1
+	}
+	keyOrder.PutUint32(key[addrKeySize+9:], index)
+	return key
+}
+
+// serializeAddrUtxo serializes the value of an unspent output according to the format described above.
+func serializeAddrUtxo(height int32, amount int64, pkScript []byte) []byte {
+	serialized := make([]byte, 12+len(pkScript))
+	byteOrder.PutUint32(serialized, uint32(height))
+	byteOrder.PutUint64(serialized[4:], uint64(amount))
+	copy(serialized[12:], pkScript)
+	return serialized
+}
+
+// serializeAddrDelta serializes the value of a delta according to the format described above.
+func serializeAddrDelta(txHash *chainhash.Hash, amount int64) []byte {
+	serialized := make([]byte, chainhash.HashSize+8)
+	copy(serialized, txHash[:])
+	byteOrder.PutUint64(serialized[chainhash.HashSize:], uint64(amount))
+	return serialized
+}
+
+// deserializeAddrBalance decodes a value of the balances bucket, which is empty for an unknown address.
+func deserializeAddrBalance(serialized []byte) (*AddrBalance, error) {
+	if len(serialized) == 0 {
+		return &AddrBalance{}, nil
+	}
+	if len(serialized) < addrBalanceSize {
+		return nil, errDeserialize("unexpected end of data")
+	}
+	return &AddrBalance{
+		Balance:  int64(byteOrder.Uint64(serialized)),
+		Received: int64(byteOrder.Uint64(serialized[8:])),
+	}, nil
+}
+
+// applyAddrBalanceChanges adds the passed changes to the balances of the addresses, removing the entries of addresses
+// left with nothing.
+func applyAddrBalanceChanges(bucket internalBucket, changes map[[addrKeySize]byte]*AddrBalance) error {
+	for addrKey, change := range changes {
+		balance, err := deserializeAddrBalance(bucket.Get(addrKey[:]))
+		if err != nil {
+			return err
+		}
+		balance.Balance += change.Balance
+		balance.Received += change.Received
+		if balance.Balance == 0 && balance.Received == 0 {
+			if err = bucket.Delete(addrKey[:]); err != nil {
+				return err
+			}
+			continue
+		}
+		serialized := make([]byte, addrBalanceSize)
+		byteOrder.PutUint64(serialized, uint64(balance.Balance))
+		byteOrder.PutUint64(serialized[8:], uint64(balance.Received))
+		if err = bucket.Put(addrKey[:], serialized); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddrUtxoIndex implements an index of the unspent outputs and balances of addresses, along with the changes each
+// transaction made to them. In addition, the changes unconfirmed transactions in the memory pool make are kept in
+// memory.
+type AddrUtxoIndex struct {
+	// The following fields are set when the instance is created and can't be changed afterwards, so there is no need to
+	// protect them with a separate mutex.
+	db          database.DB
+	chainParams *netparams.Params
+	// The following fields keep the changes unconfirmed transactions make to the balances of addresses, and the
+	// addresses each transaction involves so they can be removed when it leaves the memory pool. They are protected by
+	// the unconfirmedLock field.
+	unconfirmedLock sync.RWMutex
+	deltasByAddr    map[[addrKeySize]byte]map[chainhash.Hash][]AddrMempoolDelta
+	addrsByTx       map[chainhash.Hash]map[[addrKeySize]byte]struct{}
+}
+
+// Ensure the AddrUtxoIndex type implements the Indexer interface.
+var _ Indexer = (*AddrUtxoIndex)(nil)
+
+// Ensure the AddrUtxoIndex type implements the NeedsInputser interface.
+var _ NeedsInputser = (*AddrUtxoIndex)(nil)
+
+// NeedsInputs signals that the index requires the referenced inputs in order to properly create the index. This
+// implements the NeedsInputser interface.
+func (idx *AddrUtxoIndex) NeedsInputs() bool {
+	return true
+}
+
+// Init is only provided to satisfy the Indexer interface as there is nothing to initialize for this index. This is part
+// of the Indexer interface.
+func (idx *AddrUtxoIndex) Init() error {
+	// Nothing to do.
+	return nil
+}
+
+// Key returns the database key to use for the index as a byte slice. This is part of the Indexer interface.
+func (idx *AddrUtxoIndex) Key() []byte {
+	return addrUtxoIndexKey
+}
+
+// Name returns the human-readable name of the index. This is part of the Indexer interface.
+func (idx *AddrUtxoIndex) Name() string {
+	return addrUtxoIndexName
+}
+
+// Create is invoked when the indexer manager determines the index needs to be created for the first time. It creates
+// the bucket for the address utxo index and its sub-buckets. This is part of the Indexer interface.
+func (idx *AddrUtxoIndex) Create(dbTx database.Tx) error {
+	bucket, err := dbTx.Metadata().CreateBucket(addrUtxoIndexKey)
+	if err != nil {
+		return err
+	}
+	for _, name := range [][]byte{addrUtxoBucketName, addrDeltaBucketName, addrBalanceBucketName} {
+		if _, err = bucket.CreateBucket(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buckets returns the buckets of the index within the passed database transaction.
+func (idx *AddrUtxoIndex) buckets(dbTx database.Tx) *addrUtxoBuckets {
+	bucket := dbTx.Metadata().Bucket(addrUtxoIndexKey)
+	return &addrUtxoBuckets{
+		utxos:    bucket.Bucket(addrUtxoBucketName),
+		deltas:   bucket.Bucket(addrDeltaBucketName),
+		balances: bucket.Bucket(addrBalanceBucketName),
+	}
+}
+
+// scriptAddrKey returns the address key of the single address the passed public key script pays to, or false if it
+// does not pay to exactly one address of a supported type.
+func (idx *AddrUtxoIndex) scriptAddrKey(pkScript []byte) ([addrKeySize]byte, bool) {
+	class, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, idx.chainParams)
+	if err != nil || class == txscript.MultiSigTy || len(addrs) != 1 {
+		return [addrKeySize]byte{}, false
+	}
+	addrKey, err := addrToKey(addrs[0])
+	if err != nil {
+		return [addrKeySize]byte{}, false
+	}
+	return addrKey, true
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been connected to the main chain. This indexer
+// adds the outputs of the block to the unspent outputs of the addresses they pay to and removes the outputs the block
+// spends, recording the change of each to the balance of its address. This is part of the Indexer interface.
+func (idx *AddrUtxoIndex) ConnectBlock(dbTx database.Tx, block *util.Block, stxos []blockchain.SpentTxOut) error {
+	return idx.connectBlock(idx.buckets(dbTx), block, stxos)
+}
+
+// connectBlock adds the passed block to the passed buckets of the index.
+func (idx *AddrUtxoIndex) connectBlock(b *addrUtxoBuckets, block *util.Block, stxos []blockchain.SpentTxOut) error {
+	height := block.Height()
+	changes := make(map[[addrKeySize]byte]*AddrBalance)
+	change := func(addrKey [addrKeySize]byte) *AddrBalance {
+		if changes[addrKey] == nil {
+			changes[addrKey] = &AddrBalance{}
+		}
+		return changes[addrKey]
+	}
+	var stxoIndex int
+	for txIdx, tx := range block.Transactions() {
+		blockIndex := uint32(txIdx)
+		// Coinbases do not reference any inputs.
+		if txIdx != 0 {
+			for i, txIn := range tx.MsgTx().TxIn {
+				if stxoIndex >= len(stxos) {
+					return AssertError("spent outputs of block " + block.Hash().String() + " are missing entries")
+				}
+				stxo := &stxos[stxoIndex]
+				stxoIndex++
+				addrKey, ok := idx.scriptAddrKey(stxo.PkScript)
+				if !ok {
+					continue
+				}
+				if err := b.utxos.Delete(addrUtxoKey(addrKey, &txIn.PreviousOutPoint)); err != nil {
+					return err
+				}
+				err := b.deltas.Put(addrDeltaKey(addrKey, height, blockIndex, true, uint32(i)),
+					serializeAddrDelta(tx.Hash(), -stxo.Amount))
+				if err != nil {
+					return err
+				}
+				change(addrKey).Balance -= stxo.Amount
+			}
+		}
+		for i, txOut := range tx.MsgTx().TxOut {
+			addrKey, ok := idx.scriptAddrKey(txOut.PkScript)
+			if !ok {
+				continue
+			}
+			op := wire.OutPoint{Hash: *tx.Hash(), Index: uint32(i)}
+			err := b.utxos.Put(addrUtxoKey(addrKey, &op), serializeAddrUtxo(height, txOut.Value, txOut.PkScript))
+			if err != nil {
+				return err
+			}
+			err = b.deltas.Put(addrDeltaKey(addrKey, height, blockIndex, false, uint32(i)),
+				serializeAddrDelta(tx.Hash(), txOut.Value))
+			if err != nil {
+				return err
+			}
+			c := change(addrKey)
+			c.Balance += txOut.Value
+			c.Received += txOut.Value
+		}
+	}
+	return applyAddrBalanceChanges(b.balances, changes)
+}
+
+// DisconnectBlock is invoked by the index manager when a block has been disconnected from the main chain. This indexer
+// undoes the changes the block made, restoring the outputs it spent from the passed spent outputs. This is part of the
+// Indexer interface.
+func (idx *AddrUtxoIndex) DisconnectBlock(dbTx database.Tx, block *util.Block, stxos []blockchain.SpentTxOut) error {
+	return idx.disconnectBlock(idx.buckets(dbTx), block, stxos)
+}
+
+// disconnectBlock removes the passed block from the passed buckets of the index. The transactions are undone in
+// reverse order, so an output created and spent within the block ends up removed.
+func (idx *AddrUtxoIndex) disconnectBlock(b *addrUtxoBuckets, block *util.Block, stxos []blockchain.SpentTxOut) error {
+	height := block.Height()
+	changes := make(map[[addrKeySize]byte]*AddrBalance)
+	change := func(addrKey [addrKeySize]byte) *AddrBalance {
+		if changes[addrKey] == nil {
+			changes[addrKey] = &AddrBalance{}
+		}
+		return changes[addrKey]
+	}
+	txns := block.Transactions()
+	stxoIndex := len(stxos)
+	for txIdx := len(txns) - 1; txIdx >= 0; txIdx-- {
+		tx := txns[txIdx]
+		blockIndex := uint32(txIdx)
+		for i, txOut := range tx.MsgTx().TxOut {
+			addrKey, ok := idx.scriptAddrKey(txOut.PkScript)
+			if !ok {
+				continue
+			}
+			op := wire.OutPoint{Hash: *tx.Hash(), Index: uint32(i)}
+			if err := b.utxos.Delete(addrUtxoKey(addrKey, &op)); err != nil {
+				return err
+			}
+			if err := b.deltas.Delete(addrDeltaKey(addrKey, height, blockIndex, false, uint32(i))); err != nil {
+				return err
+			}
+			c := change(addrKey)
+			c.Balance -= txOut.Value
+			c.Received -= txOut.Value
+		}
+		if txIdx == 0 {
+			break
+		}
+		txIns := tx.MsgTx().TxIn
+		for i := len(txIns) - 1; i >= 0; i-- {
+			stxoIndex--
+			if stxoIndex < 0 {
+				return AssertError("spent outputs of block " + block.Hash().String() + " are missing entries")
+			}
+			stxo := &stxos[stxoIndex]
+			addrKey, ok := idx.scriptAddrKey(stxo.PkScript)
+			if !ok {
+				continue
+			}
+			err := b.utxos.Put(addrUtxoKey(addrKey, &txIns[i].PreviousOutPoint),
+				serializeAddrUtxo(stxo.Height, stxo.Amount, stxo.PkScript))
+			if err != nil {
+				return err
+			}
+			if err = b.deltas.Delete(addrDeltaKey(addrKey, height, blockIndex, true, uint32(i))); err != nil {
+				return err
+			}
+			change(addrKey).Balance += stxo.Amount
+		}
+	}
+	return applyAddrBalanceChanges(b.balances, changes)
+}
+
+// Balance returns the balance of the passed address and the total it ever received in the main chain. This function
+// is safe for concurrent access.
+func (idx *AddrUtxoIndex) Balance(addr util.Address) (*AddrBalance, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, err
+	}
+	var balance *AddrBalance
+	err = idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		balance, err = deserializeAddrBalance(idx.buckets(dbTx).balances.Get(addrKey[:]))
+		return err
+	})
+	return balance, err
+}
+
+// Utxos returns the unspent outputs paying to the passed address in the main chain, ordered by transaction hash. This
+// function is safe for concurrent access.
+func (idx *AddrUtxoIndex) Utxos(addr util.Address) ([]AddrUtxo, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, err
+	}
+	var utxos []AddrUtxo
+	err = idx.db.View(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(addrUtxoIndexKey).Bucket(addrUtxoBucketName)
+		cursor := bucket.Cursor()
+		for ok := cursor.Seek(addrKey[:]); ok && bytes.HasPrefix(cursor.Key(), addrKey[:]); ok = cursor.Next() {
+			key, value := cursor.Key(), cursor.Value()
+			if len(key) != addrUtxoKeySize || len(value) < 12 {
+				return errDeserialize("unexpected end of data")
+			}
+			utxo := AddrUtxo{
+				Height:   int32(byteOrder.Uint32(value)),
+				Amount:   int64(byteOrder.Uint64(value[4:])),
+				PkScript: append([]byte(nil), value[12:]...),
+			}
+			copy(utxo.OutPoint.Hash[:], key[addrKeySize:])
+			utxo.OutPoint.Index = keyOrder.Uint32(key[addrKeySize+chainhash.HashSize:])
+			utxos = append(utxos, utxo)
+		}
+		return nil
+	})
+	return utxos, err
+}
+
+// Deltas returns the changes transactions in the main chain made to the balance of the passed address in the blocks
+// from start to end height, in the order they were made. An end of zero means up to the best block. This function is
+// safe for concurrent access.
+func (idx *AddrUtxoIndex) Deltas(addr util.Address, start, end int32) ([]AddrDelta, error) {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil, err
+	}
+	var deltas []AddrDelta
+	err = idx.db.View(func(dbTx database.Tx) error {
+		bucket := dbTx.Metadata().Bucket(addrUtxoIndexKey).Bucket(addrDeltaBucketName)
+		cursor := bucket.Cursor()
+		seek := addrDeltaKey(addrKey, start, 0, false, 0)
+		for ok := cursor.Seek(seek); ok && bytes.HasPrefix(cursor.Key(), addrKey[:]); ok = cursor.Next() {
+			key, value := cursor.Key(), cursor.Value()
+			if len(key) != addrDeltaKeySize || len(value) < chainhash.HashSize+8 {
+				return errDeserialize("unexpected end of data")
+			}
+			delta := AddrDelta{
+				Height:     int32(keyOrder.Uint32(key[addrKeySize:])),
+				BlockIndex: keyOrder.Uint32(key[addrKeySize+4:]),
+				Spending:   key[addrKeySize+8] != 0,
+				Index:      keyOrder.Uint32(key[addrKeySize+9:]),
+				Amount:     int64(byteOrder.Uint64(value[chainhash.HashSize:])),
+			}
+			if end > 0 && delta.Height > end {
+				break
+			}
+			copy(delta.TxHash[:], value)
+			deltas = append(deltas, delta)
+		}
+		return nil
+	})
+	return deltas, err
+}
+
+// AddUnconfirmedTx adds the changes the passed transaction makes to the balances of addresses to the unconfirmed
+// (memory-only) part of the index. NOTE: This transaction MUST have already been validated by the memory pool before
+// calling this function with it and have all of the inputs available in the provided utxo view. This function is safe
+// for concurrent access.
+func (idx *AddrUtxoIndex) AddUnconfirmedTx(tx *util.Tx, utxoView *blockchain.UtxoViewpoint) {
+	now := time.Now()
+	deltas := make(map[[addrKeySize]byte][]AddrMempoolDelta)
+	for i, txIn := range tx.MsgTx().TxIn {
+		entry := utxoView.LookupEntry(txIn.PreviousOutPoint)
+		if entry == nil {
+			// Ignore missing entries. This should never happen in practice since the function comments specifically
+			// call out all inputs must be available.
+			continue
+		}
+		addrKey, ok := idx.scriptAddrKey(entry.PkScript())
+		if !ok {
+			continue
+		}
+		prevOut := txIn.PreviousOutPoint
+		deltas[addrKey] = append(deltas[addrKey], AddrMempoolDelta{
+			TxHash:  *tx.Hash(),
+			Index:   uint32(i),
+			Amount:  -entry.Amount(),
+			Time:    now,
+			PrevOut: &prevOut,
+		})
+	}
+	for i, txOut := range tx.MsgTx().TxOut {
+		addrKey, ok := idx.scriptAddrKey(txOut.PkScript)
+		if !ok {
+			continue
+		}
+		deltas[addrKey] = append(deltas[addrKey], AddrMempoolDelta{
+			TxHash: *tx.Hash(),
+			Index:  uint32(i),
+			Amount: txOut.Value,
+			Time:   now,
+		})
+	}
+	if len(deltas) == 0 {
+		return
+	}
+	idx.unconfirmedLock.Lock()
+	defer idx.unconfirmedLock.Unlock()
+	addrs := make(map[[addrKeySize]byte]struct{}, len(deltas))
+	for addrKey, addrDeltas := range deltas {
+		if idx.deltasByAddr[addrKey] == nil {
+			idx.deltasByAddr[addrKey] = make(map[chainhash.Hash][]AddrMempoolDelta)
+		}
+		idx.deltasByAddr[addrKey][*tx.Hash()] = addrDeltas
+		addrs[addrKey] = struct{}{}
+	}
+	idx.addrsByTx[*tx.Hash()] = addrs
+}
+
+// RemoveUnconfirmedTx removes the passed transaction from the unconfirmed (memory-only) part of the index. This
+// function is safe for concurrent access.
+func (idx *AddrUtxoIndex) RemoveUnconfirmedTx(hash *chainhash.Hash) {
+	idx.unconfirmedLock.Lock()
+	defer idx.unconfirmedLock.Unlock()
+	for addrKey := range idx.addrsByTx[*hash] {
+		delete(idx.deltasByAddr[addrKey], *hash)
+		if len(idx.deltasByAddr[addrKey]) == 0 {
+			delete(idx.deltasByAddr, addrKey)
+		}
+	}
+	delete(idx.addrsByTx, *hash)
+}
+
+// UnconfirmedDeltas returns the changes the transactions in the memory pool make to the balance of the passed address.
+// Unsupported address types result in no changes. This function is safe for concurrent access.
+func (idx *AddrUtxoIndex) UnconfirmedDeltas(addr util.Address) []AddrMempoolDelta {
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		return nil
+	}
+	idx.unconfirmedLock.RLock()
+	defer idx.unconfirmedLock.RUnlock()
+	var deltas []AddrMempoolDelta
+	for _, txDeltas := range idx.deltasByAddr[addrKey] {
+		deltas = append(deltas, txDeltas...)
+	}
+	return deltas
+}
+
+// NewAddrUtxoIndex returns a new instance of an indexer that is used to keep the unspent outputs and balances of all
+// addresses in the blockchain. It implements the Indexer interface which plugs into the IndexManager that in turn is
+// used by the blockchain package. This allows the index to be seamlessly maintained along with the chain.
+func NewAddrUtxoIndex(db database.DB, chainParams *netparams.Params) *AddrUtxoIndex {
+	return &AddrUtxoIndex{
+		db:           db,
+		chainParams:  chainParams,
+		deltasByAddr: make(map[[addrKeySize]byte]map[chainhash.Hash][]AddrMempoolDelta),
+		addrsByTx:    make(map[chainhash.Hash]map[[addrKeySize]byte]struct{}),
+	}
+}
+
+// DropAddrUtxoIndex drops the address utxo index from the provided database if it exists.
+func DropAddrUtxoIndex(db database.DB, interrupt qu.C) error {
+	return dropIndex(db, addrUtxoIndexKey, addrUtxoIndexName, interrupt)
+}
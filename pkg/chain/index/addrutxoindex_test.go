@@ -0,0 +1,138 @@
+package indexers
+
+import (
+	"testing"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// addrUtxoBucket provides a mock database bucket for the address utxo index by implementing the internalBucket
+// interface.
+type addrUtxoBucket struct {
+	entries map[string][]byte
+}
+
+// Get returns the value associated with the key from the mock bucket.
+//
+// This is part of the internalBucket interface.
+func (b *addrUtxoBucket) Get(key []byte) []byte {
+	return b.entries[string(key)]
+}
+
+// Put stores the provided key/value pair to the mock bucket.
+//
+// This is part of the internalBucket interface.
+func (b *addrUtxoBucket) Put(key []byte, value []byte) error {
+	b.entries[string(key)] = value
+	return nil
+}
+
+// Delete removes the provided key from the mock bucket.
+//
+// This is part of the internalBucket interface.
+func (b *addrUtxoBucket) Delete(key []byte) error {
+	delete(b.entries, string(key))
+	return nil
+}
+
+// testAddrScript returns a pay to pubkey hash address made from the passed byte and its script.
+func testAddrScript(t *testing.T, b byte) ([addrKeySize]byte, []byte) {
+	pkHash := make([]byte, 20)
+	pkHash[0] = b
+	addr, err := util.NewAddressPubKeyHash(pkHash, &netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewAddressPubKeyHash: unexpected err %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("PayToAddrScript: unexpected err %v", err)
+	}
+	addrKey, err := addrToKey(addr)
+	if err != nil {
+		t.Fatalf("addrToKey: unexpected err %v", err)
+	}
+	return addrKey, pkScript
+}
+
+// TestAddrUtxoIndexConnectDisconnect ensures connecting a block moves the outputs it spends and creates between the
+// unspent outputs and balances of their addresses, and that disconnecting it again leaves the index as it was.
+func TestAddrUtxoIndexConnectDisconnect(t *testing.T) {
+	keyA, scriptA := testAddrScript(t, 1)
+	keyB, scriptB := testAddrScript(t, 2)
+	idx := NewAddrUtxoIndex(nil, &netparams.MainNetParams)
+	b := &addrUtxoBuckets{
+		utxos:    &addrUtxoBucket{entries: make(map[string][]byte)},
+		deltas:   &addrUtxoBucket{entries: make(map[string][]byte)},
+		balances: &addrUtxoBucket{entries: make(map[string][]byte)},
+	}
+	// A is paid 100 by an earlier block, which is spent below.
+	prevOut := wire.OutPoint{Index: 3}
+	prevOut.Hash[0] = 0xff
+	err := b.utxos.Put(addrUtxoKey(keyA, &prevOut), serializeAddrUtxo(5, 100, scriptA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = applyAddrBalanceChanges(b.balances, map[[addrKeySize]byte]*AddrBalance{keyA: {Balance: 100, Received: 100}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The coinbase pays 50 to B, and the next transaction spends the output of A paying 60 to B and 40 back to A.
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: wire.MaxPrevOutIndex}, nil, nil))
+	coinbase.AddTxOut(wire.NewTxOut(50, scriptB))
+	spend := wire.NewMsgTx(1)
+	spend.AddTxIn(wire.NewTxIn(&prevOut, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(60, scriptB))
+	spend.AddTxOut(wire.NewTxOut(40, scriptA))
+	msgBlock := wire.NewMsgBlock(&wire.BlockHeader{})
+	_ = msgBlock.AddTransaction(coinbase)
+	_ = msgBlock.AddTransaction(spend)
+	block := util.NewBlock(msgBlock)
+	block.SetHeight(10)
+	stxos := []blockchain.SpentTxOut{{Amount: 100, PkScript: scriptA, Height: 5}}
+	if err = idx.connectBlock(b, block, stxos); err != nil {
+		t.Fatalf("connectBlock: unexpected err %v", err)
+	}
+	wantBalances := map[[addrKeySize]byte]AddrBalance{
+		keyA: {Balance: 40, Received: 140},
+		keyB: {Balance: 110, Received: 110},
+	}
+	for addrKey, want := range wantBalances {
+		balance, err := deserializeAddrBalance(b.balances.Get(addrKey[:]))
+		if err != nil {
+			t.Fatalf("deserializeAddrBalance: unexpected err %v", err)
+		}
+		if *balance != want {
+			t.Errorf("connectBlock: got balance %+v, want %+v", *balance, want)
+		}
+	}
+	if b.utxos.Get(addrUtxoKey(keyA, &prevOut)) != nil {
+		t.Errorf("connectBlock: spent output still unspent")
+	}
+	if n := len(b.utxos.(*addrUtxoBucket).entries); n != 3 {
+		t.Errorf("connectBlock: got %d unspent outputs, want 3", n)
+	}
+	if n := len(b.deltas.(*addrUtxoBucket).entries); n != 4 {
+		t.Errorf("connectBlock: got %d deltas, want 4", n)
+	}
+	if err = idx.disconnectBlock(b, block, stxos); err != nil {
+		t.Fatalf("disconnectBlock: unexpected err %v", err)
+	}
+	if len(b.utxos.(*addrUtxoBucket).entries) != 1 || b.utxos.Get(addrUtxoKey(keyA, &prevOut)) == nil {
+		t.Errorf("disconnectBlock: spent output not restored")
+	}
+	if n := len(b.deltas.(*addrUtxoBucket).entries); n != 0 {
+		t.Errorf("disconnectBlock: %d deltas left", n)
+	}
+	if b.balances.Get(keyB[:]) != nil {
+		t.Errorf("disconnectBlock: balance of an address the block paid left behind")
+	}
+	balance, _ := deserializeAddrBalance(b.balances.Get(keyA[:]))
+	if *balance != (AddrBalance{Balance: 100, Received: 100}) {
+		t.Errorf("disconnectBlock: got balance %+v, want the balance before the block", *balance)
+	}
+}
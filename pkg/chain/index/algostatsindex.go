@@ -0,0 +1,323 @@
+package indexers
+
+import (
+	"math/big"
+	"sort"
+
+	qu "github.com/p9c/pod/pkg/util/quit"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/fork"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// algoStatsIndexName is the human-readable name for the index.
+	algoStatsIndexName = "algo stats index"
+	// algoStatsRecordSize is the size of a block record without its cumulative work. It consists of 4 bytes version +
+	// 4 bytes bits + 8 bytes timestamp + 4 bytes previous height + 8 bytes interval.
+	algoStatsRecordSize = 4 + 4 + 8 + 4 + 8
+)
+
+var (
+	// algoStatsIndexKey is the key of the algo stats index and the db bucket used to house it.
+	algoStatsIndexKey = []byte("algostatsidx")
+	// algoStatsBlockBucketName is the name of the bucket holding the record of each block by height.
+	algoStatsBlockBucketName = []byte("blocks")
+	// algoStatsTipBucketName is the name of the bucket holding the height of the last block of each algorithm.
+	algoStatsTipBucketName = []byte("tips")
+)
+
+// The algo stats index keeps a record of the proof of work of each block in the main chain, so the share of blocks,
+// the block intervals and the hashrate of each algorithm can be computed over any range of blocks. As every algorithm
+// has its own difficulty, each record refers to the previous block of the same algorithm.
+//
+// The blocks bucket:
+//   <height> => <version><bits><timestamp><prev height><interval><cumulative work>
+//   Field           Type      Size
+//   height          uint32    4 bytes (big endian)
+//   version         int32     4 bytes
+//   bits            uint32    4 bytes
+//   timestamp       int64     8 bytes
+//   prev height     int32     4 bytes, -1 for the first block of the algorithm
+//   interval        int64     8 bytes, seconds since the previous block of the algorithm
+//   cumulative work big.Int   variable, the work of all blocks of the algorithm up to this one
+//
+// The tips bucket:
+//   <version> => <height>
+//   Field           Type      Size
+//   version         int32     4 bytes (big endian)
+//   height          int32     4 bytes
+
+// algoStatsRecord is the record of a block in the algo stats index.
+type algoStatsRecord struct {
+	version    int32
+	bits       uint32
+	timestamp  int64
+	prevHeight int32
+	interval   int64
+	work       *big.Int
+}
+
+// serialize encodes the record according to the format described above.
+func (r *algoStatsRecord) serialize() []byte {
+	work := r.work.Bytes()
+	serialized := make([]byte, algoStatsRecordSize+len(work))
+	byteOrder.PutUint32(serialized, uint32(r.version))
+	byteOrder.PutUint32(serialized[4:], r.bits)
+	byteOrder.PutUint64(serialized[8:], uint64(r.timestamp))
+	byteOrder.PutUint32(serialized[16:], uint32(r.prevHeight))
+	byteOrder.PutUint64(serialized[20:], uint64(r.interval))
+	copy(serialized[algoStatsRecordSize:], work)
+	return serialized
+}
+
+// deserializeAlgoStatsRecord decodes a record encoded according to the format described above.
+func deserializeAlgoStatsRecord(serialized []byte) (*algoStatsRecord, error) {
+	if len(serialized) < algoStatsRecordSize {
+		return nil, errDeserialize("unexpected end of data")
+	}
+	return &algoStatsRecord{
+		version:    int32(byteOrder.Uint32(serialized)),
+		bits:       byteOrder.Uint32(serialized[4:]),
+		timestamp:  int64(byteOrder.Uint64(serialized[8:])),
+		prevHeight: int32(byteOrder.Uint32(serialized[16:])),
+		interval:   int64(byteOrder.Uint64(serialized[20:])),
+		work:       new(big.Int).SetBytes(serialized[algoStatsRecordSize:]),
+	}, nil
+}
+
+// algoStatsKey returns the key of a height or version in the buckets of the index.
+func algoStatsKey(n int32) []byte {
+	key := make([]byte, 4)
+	keyOrder.PutUint32(key, uint32(n))
+	return key
+}
+
+// fetchAlgoStatsRecord returns the record of the block at the passed height, or an error if there is none.
+func fetchAlgoStatsRecord(blocks internalBucket, height int32) (*algoStatsRecord, error) {
+	serialized := blocks.Get(algoStatsKey(height))
+	if serialized == nil {
+		return nil, AssertError("no algo stats record for the block at the given height")
+	}
+	return deserializeAlgoStatsRecord(serialized)
+}
+
+// connectAlgoStats adds the record of the passed block to the buckets and makes it the tip of its algorithm.
+func connectAlgoStats(blocks, tips internalBucket, block *util.Block) error {
+	height := block.Height()
+	header := &block.MsgBlock().Header
+	r := &algoStatsRecord{
+		version:    header.Version,
+		bits:       header.Bits,
+		timestamp:  header.Timestamp.Unix(),
+		prevHeight: -1,
+		work:       blockchain.CalcWork(header.Bits, height, header.Version),
+	}
+	tipKey := algoStatsKey(header.Version)
+	if tip := tips.Get(tipKey); tip != nil {
+		r.prevHeight = int32(byteOrder.Uint32(tip))
+		prev, err := fetchAlgoStatsRecord(blocks, r.prevHeight)
+		if err != nil {
+			return err
+		}
+		r.interval = r.timestamp - prev.timestamp
+		r.work.Add(r.work, prev.work)
+	}
+	if err := blocks.Put(algoStatsKey(height), r.serialize()); err != nil {
+		return err
+	}
+	serializedHeight := make([]byte, 4)
+	byteOrder.PutUint32(serializedHeight, uint32(height))
+	return tips.Put(tipKey, serializedHeight)
+}
+
+// disconnectAlgoStats removes the record of the passed block from the buckets and makes the previous block of its
+// algorithm the tip again.
+func disconnectAlgoStats(blocks, tips internalBucket, block *util.Block) error {
+	r, err := fetchAlgoStatsRecord(blocks, block.Height())
+	if err != nil {
+		return err
+	}
+	if err = blocks.Delete(algoStatsKey(block.Height())); err != nil {
+		return err
+	}
+	tipKey := algoStatsKey(r.version)
+	if r.prevHeight < 0 {
+		return tips.Delete(tipKey)
+	}
+	serializedHeight := make([]byte, 4)
+	byteOrder.PutUint32(serializedHeight, uint32(r.prevHeight))
+	return tips.Put(tipKey, serializedHeight)
+}
+
+// AlgoStats are the statistics of an algorithm over a range of blocks.
+type AlgoStats struct {
+	// Algo and Version identify the algorithm. Blocks of the same algorithm before and after a hard fork are counted
+	// separately, as they have different versions.
+	Algo    string
+	Version int32
+	// Blocks is the number of blocks of the algorithm in the range.
+	Blocks int
+	// Intervals is the number of blocks that had a previous block of the algorithm, and IntervalSum the total seconds
+	// between them and their previous block.
+	Intervals   int
+	IntervalSum int64
+	// TargetInterval is the number of seconds between blocks of the algorithm the difficulty adjustment aims for.
+	TargetInterval int64
+	// Bits is the difficulty of the last block of the algorithm in the range.
+	Bits uint32
+	// Work is the work of the blocks of the algorithm in the range and CumulativeWork that of all blocks of the
+	// algorithm up to the last one in the range.
+	Work           *big.Int
+	CumulativeWork *big.Int
+}
+
+// AlgoStatsRange are the statistics of each algorithm over a range of blocks, ordered by version.
+type AlgoStatsRange struct {
+	From, To int32
+	// Span is the number of seconds from the block before the range, or the first block if the range starts at the
+	// genesis block, to the last block of the range.
+	Span  int64
+	Algos []*AlgoStats
+}
+
+// algoTargetInterval returns the number of seconds between blocks of the passed algorithm that the difficulty
+// adjustment at the passed height aims for.
+func algoTargetInterval(algo string, height int32) int64 {
+	hf := fork.GetCurrent(height)
+	if hf == 0 {
+		return int64(fork.List[hf].TargetTimePerBlock)
+	}
+	return int64(fork.List[hf].Algos[algo].VersionInterval)
+}
+
+// algoStatsRange computes the statistics of each algorithm over the blocks from the passed heights from the records in
+// the passed bucket.
+func algoStatsRange(blocks internalBucket, from, to int32) (*AlgoStatsRange, error) {
+	stats := &AlgoStatsRange{From: from, To: to}
+	byVersion := make(map[int32]*AlgoStats)
+	var first, last int64
+	for height := from; height <= to; height++ {
+		r, err := fetchAlgoStatsRecord(blocks, height)
+		if err != nil {
+			return nil, err
+		}
+		if height == from {
+			first = r.timestamp
+		}
+		last = r.timestamp
+		s, ok := byVersion[r.version]
+		if !ok {
+			algo := fork.GetAlgoName(r.version, height)
+			s = &AlgoStats{
+				Algo:           algo,
+				Version:        r.version,
+				TargetInterval: algoTargetInterval(algo, height),
+				Work:           new(big.Int),
+			}
+			byVersion[r.version] = s
+			stats.Algos = append(stats.Algos, s)
+		}
+		s.Blocks++
+		if r.prevHeight >= 0 {
+			s.Intervals++
+			s.IntervalSum += r.interval
+		}
+		s.Bits = r.bits
+		s.Work.Add(s.Work, blockchain.CalcWork(r.bits, height, r.version))
+		s.CumulativeWork = r.work
+	}
+	if from > 0 {
+		r, err := fetchAlgoStatsRecord(blocks, from-1)
+		if err != nil {
+			return nil, err
+		}
+		first = r.timestamp
+	}
+	stats.Span = last - first
+	sort.Slice(stats.Algos, func(i, j int) bool {
+		return stats.Algos[i].Version < stats.Algos[j].Version
+	})
+	return stats, nil
+}
+
+// AlgoStatsIndex implements an index of the proof of work of each block in the main chain, from which the statistics
+// of each algorithm are computed.
+type AlgoStatsIndex struct {
+	db database.DB
+}
+
+// Ensure the AlgoStatsIndex type implements the Indexer interface.
+var _ Indexer = (*AlgoStatsIndex)(nil)
+
+// Init is only provided to satisfy the Indexer interface as there is nothing to initialize for this index. This is part
+// of the Indexer interface.
+func (idx *AlgoStatsIndex) Init() error {
+	// Nothing to do.
+	return nil
+}
+
+// Key returns the database key to use for the index as a byte slice. This is part of the Indexer interface.
+func (idx *AlgoStatsIndex) Key() []byte {
+	return algoStatsIndexKey
+}
+
+// Name returns the human-readable name of the index. This is part of the Indexer interface.
+func (idx *AlgoStatsIndex) Name() string {
+	return algoStatsIndexName
+}
+
+// Create is invoked when the indexer manager determines the index needs to be created for the first time. It creates
+// the bucket for the algo stats index and its sub-buckets. This is part of the Indexer interface.
+func (idx *AlgoStatsIndex) Create(dbTx database.Tx) error {
+	bucket, err := dbTx.Metadata().CreateBucket(algoStatsIndexKey)
+	if err != nil {
+		return err
+	}
+	if _, err = bucket.CreateBucket(algoStatsBlockBucketName); err != nil {
+		return err
+	}
+	_, err = bucket.CreateBucket(algoStatsTipBucketName)
+	return err
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been connected to the main chain. This indexer
+// adds the record of the block. This is part of the Indexer interface.
+func (idx *AlgoStatsIndex) ConnectBlock(dbTx database.Tx, block *util.Block, stxos []blockchain.SpentTxOut) error {
+	bucket := dbTx.Metadata().Bucket(algoStatsIndexKey)
+	return connectAlgoStats(bucket.Bucket(algoStatsBlockBucketName), bucket.Bucket(algoStatsTipBucketName), block)
+}
+
+// DisconnectBlock is invoked by the index manager when a block has been disconnected from the main chain. This indexer
+// removes the record of the block. This is part of the Indexer interface.
+func (idx *AlgoStatsIndex) DisconnectBlock(dbTx database.Tx, block *util.Block, stxos []blockchain.SpentTxOut) error {
+	bucket := dbTx.Metadata().Bucket(algoStatsIndexKey)
+	return disconnectAlgoStats(bucket.Bucket(algoStatsBlockBucketName), bucket.Bucket(algoStatsTipBucketName), block)
+}
+
+// Stats returns the statistics of each algorithm over the main chain blocks from the passed heights, which must have
+// been indexed. This function is safe for concurrent access.
+func (idx *AlgoStatsIndex) Stats(from, to int32) (*AlgoStatsRange, error) {
+	var stats *AlgoStatsRange
+	err := idx.db.View(func(dbTx database.Tx) error {
+		var err error
+		blocks := dbTx.Metadata().Bucket(algoStatsIndexKey).Bucket(algoStatsBlockBucketName)
+		stats, err = algoStatsRange(blocks, from, to)
+		return err
+	})
+	return stats, err
+}
+
+// NewAlgoStatsIndex returns a new instance of an indexer that is used to keep a record of the proof of work of each
+// block in the main chain. It implements the Indexer interface which plugs into the IndexManager that in turn is used by
+// the blockchain package. This allows the index to be seamlessly maintained along with the chain.
+func NewAlgoStatsIndex(db database.DB) *AlgoStatsIndex {
+	return &AlgoStatsIndex{db: db}
+}
+
+// DropAlgoStatsIndex drops the algo stats index from the provided database if it exists.
+func DropAlgoStatsIndex(db database.DB, interrupt qu.C) error {
+	return dropIndex(db, algoStatsIndexKey, algoStatsIndexName, interrupt)
+}
@@ -0,0 +1,75 @@
+package indexers
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/fork"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// TestAlgoStatsIndex ensures the records of connected blocks refer to the previous block of the same algorithm, that
+// the statistics over a range are computed from them, and that disconnecting a block restores the previous tip.
+func TestAlgoStatsIndex(t *testing.T) {
+	blocks := &addrUtxoBucket{entries: make(map[string][]byte)}
+	tips := &addrUtxoBucket{entries: make(map[string][]byte)}
+	// Blocks alternate between sha256d and scrypt except for the last, which is another scrypt block.
+	const bits = 0x1d00ffff
+	versions := []int32{2, 514, 2, 514, 514}
+	times := []int64{1000, 1100, 1400, 1500, 1800}
+	var chain []*util.Block
+	for i, version := range versions {
+		block := util.NewBlock(wire.NewMsgBlock(&wire.BlockHeader{
+			Version:   version,
+			Bits:      bits,
+			Timestamp: time.Unix(times[i], 0),
+		}))
+		block.SetHeight(int32(i))
+		if err := connectAlgoStats(blocks, tips, block); err != nil {
+			t.Fatalf("connectAlgoStats: unexpected err %v", err)
+		}
+		chain = append(chain, block)
+	}
+	stats, err := algoStatsRange(blocks, 1, 4)
+	if err != nil {
+		t.Fatalf("algoStatsRange: unexpected err %v", err)
+	}
+	if stats.Span != 800 || len(stats.Algos) != 2 {
+		t.Fatalf("algoStatsRange: got span %d over %d algos, want 800 over 2", stats.Span, len(stats.Algos))
+	}
+	work := blockchain.CalcWork(bits, 0, 2)
+	sha, scrypt := stats.Algos[0], stats.Algos[1]
+	if sha.Algo != fork.SHA256d || sha.Blocks != 1 || sha.Intervals != 1 || sha.IntervalSum != 400 {
+		t.Errorf("algoStatsRange: got sha256d stats %+v", *sha)
+	}
+	if sha.Work.Cmp(work) != 0 || sha.CumulativeWork.Cmp(new(big.Int).Lsh(work, 1)) != 0 {
+		t.Errorf("algoStatsRange: got sha256d work %v cumulative %v", sha.Work, sha.CumulativeWork)
+	}
+	if scrypt.Algo != fork.Scrypt || scrypt.Blocks != 3 || scrypt.Intervals != 2 || scrypt.IntervalSum != 700 {
+		t.Errorf("algoStatsRange: got scrypt stats %+v", *scrypt)
+	}
+	if scrypt.TargetInterval != int64(fork.List[0].TargetTimePerBlock) {
+		t.Errorf("algoStatsRange: got target interval %d", scrypt.TargetInterval)
+	}
+	if _, err = algoStatsRange(blocks, 0, 5); err == nil {
+		t.Errorf("algoStatsRange: accepted a range past the last record")
+	}
+	// Removing the last block makes the scrypt block before it the tip again.
+	if err = disconnectAlgoStats(blocks, tips, chain[4]); err != nil {
+		t.Fatalf("disconnectAlgoStats: unexpected err %v", err)
+	}
+	if tip := tips.Get(algoStatsKey(514)); tip == nil || byteOrder.Uint32(tip) != 3 {
+		t.Errorf("disconnectAlgoStats: scrypt tip not restored")
+	}
+	for i := 3; i >= 0; i-- {
+		if err = disconnectAlgoStats(blocks, tips, chain[i]); err != nil {
+			t.Fatalf("disconnectAlgoStats: unexpected err %v", err)
+		}
+	}
+	if len(blocks.entries) != 0 || len(tips.entries) != 0 {
+		t.Errorf("disconnectAlgoStats: %d records and %d tips left", len(blocks.entries), len(tips.entries))
+	}
+}
@@ -2,6 +2,9 @@ package indexers
 
 import (
 	"errors"
+	"fmt"
+	"sync"
+
 	qu "github.com/p9c/pod/pkg/util/quit"
 	
 	blockchain "github.com/p9c/pod/pkg/chain"
@@ -12,6 +15,7 @@ import (
 	"github.com/p9c/pod/pkg/coding/gcs/builder"
 	database "github.com/p9c/pod/pkg/db"
 	"github.com/p9c/pod/pkg/util"
+	log "github.com/p9c/pod/pkg/util/logi"
 )
 
 const (
@@ -19,33 +23,61 @@ const (
 	cfIndexName = "committed filter index"
 )
 
-// Committed filters come in one flavor currently: basic. They are generated and dropped in pairs, and both are indexed
-// by a block's hash. Besides holding different content, they also live in different buckets.
+// cfBuildBatchSize is the number of blocks whose filters are stored in one database transaction while building the
+// filters of a type for the existing chain.
+const cfBuildBatchSize = 500
+
+// Committed filters come in any of the types registered with the builder package. The basic filter is created with
+// the index and kept for every block, while other types are only kept once they have been built for the existing chain
+// by BuildFilterType. The filters, filter hashes and filter headers of each type are indexed by a block's hash and live
+// in their own buckets.
 var (
 	// cfIndexParentBucketKey is the name of the parent bucket used to house the index. The rest of the buckets live
 	// below this bucket.
 	cfIndexParentBucketKey = []byte("cfindexparentbucket")
-	// cfIndexKeys is an array of db bucket names used to house indexes of block hashes to cfilters.
-	cfIndexKeys = [][]byte{
-		[]byte("cf0byhashidx"),
-	}
-	// cfHeaderKeys is an array of db bucket names used to house indexes of block hashes to cf headers.
-	cfHeaderKeys = [][]byte{
-		[]byte("cf0headerbyhashidx"),
-	}
-	// cfHashKeys is an array of db bucket names used to house indexes of block hashes to cf hashes.
-	cfHashKeys = [][]byte{
-		[]byte("cf0hashbyhashidx"),
-	}
-	maxFilterType = uint8(len(cfHeaderKeys) - 1)
 	// zeroHash is the chainhash.Hash value of all zero bytes, defined here for convenience.
 	zeroHash chainhash.Hash
 )
 
+// cfIndexKey returns the name of the db bucket used to house the index of block hashes to cfilters of a type.
+func cfIndexKey(filterType wire.FilterType) []byte {
+	return []byte(fmt.Sprintf("cf%dbyhashidx", filterType))
+}
+
+// cfHeaderKey returns the name of the db bucket used to house the index of block hashes to cf headers of a type.
+func cfHeaderKey(filterType wire.FilterType) []byte {
+	return []byte(fmt.Sprintf("cf%dheaderbyhashidx", filterType))
+}
+
+// cfHashKey returns the name of the db bucket used to house the index of block hashes to cf hashes of a type.
+func cfHashKey(filterType wire.FilterType) []byte {
+	return []byte(fmt.Sprintf("cf%dhashbyhashidx", filterType))
+}
+
+// cfBuildKey returns the key of the parent bucket entry holding the hash of the last block whose filter of a type was
+// stored while the filters of the type are being built for the existing chain. The type is only kept up to date with
+// the chain once the entry is removed.
+func cfBuildKey(filterType wire.FilterType) []byte {
+	return []byte(fmt.Sprintf("cf%dbuildtip", filterType))
+}
+
+// cfTypeKeys returns the names of all the db buckets of a filter type.
+func cfTypeKeys(filterType wire.FilterType) [][]byte {
+	return [][]byte{cfIndexKey(filterType), cfHeaderKey(filterType), cfHashKey(filterType)}
+}
+
+// dbFilterTypeExists returns whether the buckets of a filter type have been created.
+func dbFilterTypeExists(dbTx database.Tx, filterType wire.FilterType) bool {
+	return dbTx.Metadata().Bucket(cfIndexParentBucketKey).Bucket(cfIndexKey(filterType)) != nil
+}
+
 // dbFetchFilterIdxEntry retrieves a data blob from the filter index database. An entry's absence is not considered an
 // error.
 func dbFetchFilterIdxEntry(dbTx database.Tx, key []byte, h *chainhash.Hash) ([]byte, error) {
 	idx := dbTx.Metadata().Bucket(cfIndexParentBucketKey).Bucket(key)
+	if idx == nil {
+		return nil, nil
+	}
 	return idx.Get(h[:]), nil
 }
 
@@ -65,6 +97,9 @@ func dbDeleteFilterIdxEntry(dbTx database.Tx, key []byte, h *chainhash.Hash) err
 type CFIndex struct {
 	db          database.DB
 	chainParams *netparams.Params
+	// filterTypes holds the filter types kept up to date with the chain.
+	filterTypes    map[wire.FilterType]struct{}
+	filterTypesMtx sync.RWMutex
 }
 
 // Ensure the CfIndex type implements the Indexer interface.
@@ -79,9 +114,20 @@ func (idx *CFIndex) NeedsInputs() bool {
 	return true
 }
 
-// Init initializes the hash-based cf index. This is part of the Indexer interface.
+// Init initializes the hash-based cf index by loading the filter types that have been built for the chain. This is
+// part of the Indexer interface.
 func (idx *CFIndex) Init() error {
-	return nil // Nothing to do.
+	return idx.db.View(func(dbTx database.Tx) error {
+		parent := dbTx.Metadata().Bucket(cfIndexParentBucketKey)
+		idx.filterTypesMtx.Lock()
+		defer idx.filterTypesMtx.Unlock()
+		for _, filterType := range builder.FilterTypes() {
+			if dbFilterTypeExists(dbTx, filterType) && parent.Get(cfBuildKey(filterType)) == nil {
+				idx.filterTypes[filterType] = struct{}{}
+			}
+		}
+		return nil
+	})
 }
 
 // Key returns the database key to use for the index as a byte slice. This is part of the Indexer interface.
@@ -95,7 +141,7 @@ func (idx *CFIndex) Name() string {
 }
 
 // Create is invoked when the indexer manager determines the index needs to be created for the first time. It creates
-// buckets for the two hash-based cf indexes (regular only currently).
+// buckets for the basic filter type, which is the only type kept from the start.
 func (idx *CFIndex) Create(dbTx database.Tx) error {
 	meta := dbTx.Metadata()
 	cfIndexParentBucket, err := meta.CreateBucket(cfIndexParentBucketKey)
@@ -103,40 +149,29 @@ func (idx *CFIndex) Create(dbTx database.Tx) error {
 		Error(err)
 		return err
 	}
-	for _, bucketName := range cfIndexKeys {
-		_, err = cfIndexParentBucket.CreateBucket(bucketName)
-		if err != nil {
-			Error(err)
-			return err
-		}
-	}
-	for _, bucketName := range cfHeaderKeys {
-		_, err = cfIndexParentBucket.CreateBucket(bucketName)
-		if err != nil {
-			Error(err)
-			return err
-		}
-	}
-	for _, bucketName := range cfHashKeys {
+	for _, bucketName := range cfTypeKeys(wire.GCSFilterRegular) {
 		_, err = cfIndexParentBucket.CreateBucket(bucketName)
 		if err != nil {
 			Error(err)
 			return err
 		}
 	}
+	idx.filterTypesMtx.Lock()
+	idx.filterTypes[wire.GCSFilterRegular] = struct{}{}
+	idx.filterTypesMtx.Unlock()
 	return nil
 }
 
 // storeFilter stores a given filter, and performs the steps needed to generate the filter's header.
 func storeFilter(dbTx database.Tx, block *util.Block, f *gcs.Filter,
 	filterType wire.FilterType) error {
-	if uint8(filterType) > maxFilterType {
+	if !dbFilterTypeExists(dbTx, filterType) {
 		return errors.New("unsupported filter type")
 	}
 	// Figure out which buckets to use.
-	fkey := cfIndexKeys[filterType]
-	hkey := cfHeaderKeys[filterType]
-	hashkey := cfHashKeys[filterType]
+	fkey := cfIndexKey(filterType)
+	hkey := cfHeaderKey(filterType)
+	hashkey := cfHashKey(filterType)
 	// Start by storing the filter.
 	h := block.Hash()
 	filterBytes, err := f.NBytes()
@@ -186,58 +221,208 @@ func storeFilter(dbTx database.Tx, block *util.Block, f *gcs.Filter,
 	return dbStoreFilterIdxEntry(dbTx, hkey, h, fh[:])
 }
 
-// ConnectBlock is invoked by the index manager when a new block has been connected to the main chain. This indexer adds
-// a hash-to-cf mapping for every passed block. This is part of the Indexer interface.
-func (idx *CFIndex) ConnectBlock(dbTx database.Tx, block *util.Block,
-	stxos []blockchain.SpentTxOut) error {
+// storeFilters builds and stores the filters of the passed types for a block.
+func storeFilters(dbTx database.Tx, block *util.Block, stxos []blockchain.SpentTxOut,
+	filterTypes []wire.FilterType) error {
 	prevScripts := make([][]byte, len(stxos))
 	for i, stxo := range stxos {
 		prevScripts[i] = stxo.PkScript
 	}
-	f, err := builder.BuildBasicFilter(block.MsgBlock(), prevScripts)
-	if err != nil {
-		Error(err)
-		return err
+	for _, filterType := range filterTypes {
+		f, err := builder.BuildFilter(filterType, block.MsgBlock(), prevScripts)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		if err = storeFilter(dbTx, block, f, filterType); err != nil {
+			return err
+		}
 	}
-	return storeFilter(dbTx, block, f, wire.GCSFilterRegular)
+	return nil
+}
+
+// ConnectBlock is invoked by the index manager when a new block has been connected to the main chain. This indexer adds
+// a hash-to-cf mapping of every filter type kept up to date for every passed block. This is part of the Indexer
+// interface.
+func (idx *CFIndex) ConnectBlock(dbTx database.Tx, block *util.Block,
+	stxos []blockchain.SpentTxOut) error {
+	return storeFilters(dbTx, block, stxos, idx.activeFilterTypes())
 }
 
 // DisconnectBlock is invoked by the index manager when a block has been disconnected from the main chain. This indexer
 // removes the hash-to-cf mapping for every passed block. This is part of the Indexer interface.
 func (idx *CFIndex) DisconnectBlock(dbTx database.Tx, block *util.Block,
 	_ []blockchain.SpentTxOut) error {
-	for _, key := range cfIndexKeys {
-		err := dbDeleteFilterIdxEntry(dbTx, key, block.Hash())
+	// Filters of types still being built are removed as well, so none are left for blocks no longer in the main chain.
+	for _, filterType := range builder.FilterTypes() {
+		if !dbFilterTypeExists(dbTx, filterType) {
+			continue
+		}
+		for _, key := range cfTypeKeys(filterType) {
+			err := dbDeleteFilterIdxEntry(dbTx, key, block.Hash())
+			if err != nil {
+				Error(err)
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// activeFilterTypes returns the filter types kept up to date with the chain in ascending order.
+func (idx *CFIndex) activeFilterTypes() []wire.FilterType {
+	idx.filterTypesMtx.RLock()
+	defer idx.filterTypesMtx.RUnlock()
+	var filterTypes []wire.FilterType
+	for _, filterType := range builder.FilterTypes() {
+		if _, ok := idx.filterTypes[filterType]; ok {
+			filterTypes = append(filterTypes, filterType)
+		}
+	}
+	return filterTypes
+}
+
+// HasFilterType returns whether the filters of a type are kept for every block in the main chain, and so can be served
+// to peers.
+func (idx *CFIndex) HasFilterType(filterType wire.FilterType) bool {
+	idx.filterTypesMtx.RLock()
+	defer idx.filterTypesMtx.RUnlock()
+	_, ok := idx.filterTypes[filterType]
+	return ok
+}
+
+// BuildFilterType builds the filters of a registered type for every block in the main chain and then keeps them up to
+// date as blocks are connected. It must be called before the chain is being extended. Progress is stored as it goes,
+// so a build that is interrupted resumes where it stopped when it is called again.
+func (idx *CFIndex) BuildFilterType(chain *blockchain.BlockChain, filterType wire.FilterType,
+	interrupt qu.C) error {
+	if idx.HasFilterType(filterType) {
+		Infof("%s filters are already built", builder.FilterTypeName(filterType))
+		return nil
+	}
+	// Building a filter needs the outputs spent by a block, which a pruned node no longer has for most of the chain.
+	if chain.IsPruned() {
+		return errors.New("filters cannot be built on a pruned node")
+	}
+	// Create the buckets of the type and find the first block whose filter has not been stored yet.
+	var startHeight int32
+	err := idx.db.Update(func(dbTx database.Tx) error {
+		parent := dbTx.Metadata().Bucket(cfIndexParentBucketKey)
+		if !dbFilterTypeExists(dbTx, filterType) {
+			for _, bucketName := range cfTypeKeys(filterType) {
+				if _, err := parent.CreateBucket(bucketName); err != nil {
+					return err
+				}
+			}
+			return parent.Put(cfBuildKey(filterType), []byte{})
+		}
+		buildTip := parent.Get(cfBuildKey(filterType))
+		if len(buildTip) != chainhash.HashSize {
+			return nil
+		}
+		// The last block stored may have been disconnected since, in which case the build resumes after the last
+		// block it has in common with the main chain.
+		hash, err := chainhash.NewHash(buildTip)
 		if err != nil {
-			Error(err)
 			return err
 		}
+		for _, h := range chain.BlockLocatorFromHash(hash) {
+			if chain.MainChainHasBlock(h) {
+				height, err := chain.BlockHeightByHash(h)
+				if err != nil {
+					return err
+				}
+				startHeight = height + 1
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return err
 	}
-	for _, key := range cfHeaderKeys {
-		err := dbDeleteFilterIdxEntry(dbTx, key, block.Hash())
+	best := chain.BestSnapshot()
+	name := builder.FilterTypeName(filterType)
+	Infof("building %s filters from height %d to %d", name, startHeight, best.Height)
+	progressLogger := newBlockProgressLogger("Built "+name+" filters for", log.L)
+	for height := startHeight; height <= best.Height; height += cfBuildBatchSize {
+		if interruptRequested(interrupt) {
+			return errInterruptRequested
+		}
+		last := height + cfBuildBatchSize - 1
+		if last > best.Height {
+			last = best.Height
+		}
+		blocks, err := idx.buildFilterBatch(chain, filterType, height, last)
 		if err != nil {
-			Error(err)
 			return err
 		}
+		for _, block := range blocks {
+			progressLogger.LogBlockHeight(block)
+		}
 	}
-	for _, key := range cfHashKeys {
-		err := dbDeleteFilterIdxEntry(dbTx, key, block.Hash())
+	err = idx.db.Update(func(dbTx database.Tx) error {
+		return dbTx.Metadata().Bucket(cfIndexParentBucketKey).Delete(cfBuildKey(filterType))
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	idx.filterTypesMtx.Lock()
+	idx.filterTypes[filterType] = struct{}{}
+	idx.filterTypesMtx.Unlock()
+	Infof("built %s filters up to height %d", name, best.Height)
+	return nil
+}
+
+// buildFilterBatch stores the filters of a type for the blocks of the main chain from height first to last together
+// with the hash of the last of them, from which the build resumes if it is interrupted after this batch. The blocks
+// are returned.
+func (idx *CFIndex) buildFilterBatch(chain *blockchain.BlockChain, filterType wire.FilterType,
+	first, last int32) ([]*util.Block, error) {
+	// Fetch the blocks of the batch and the outputs they spend before storing their filters together.
+	var blocks []*util.Block
+	var spent [][]blockchain.SpentTxOut
+	for h := first; h <= last; h++ {
+		block, err := chain.BlockByHeight(h)
 		if err != nil {
 			Error(err)
-			return err
+			return nil, err
+		}
+		stxos, err := chain.FetchSpendJournal(block)
+		if err != nil {
+			Error(err)
+			return nil, err
 		}
+		blocks = append(blocks, block)
+		spent = append(spent, stxos)
 	}
-	return nil
+	err := idx.db.Update(func(dbTx database.Tx) error {
+		for i, block := range blocks {
+			err := storeFilters(dbTx, block, spent[i], []wire.FilterType{filterType})
+			if err != nil {
+				return err
+			}
+		}
+		tip := blocks[len(blocks)-1].Hash()
+		return dbTx.Metadata().Bucket(cfIndexParentBucketKey).Put(cfBuildKey(filterType), tip[:])
+	})
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return blocks, nil
 }
 
 // entryByBlockHash fetches a filter index entry of a particular type (eg. filter, filter header, etc) for a filter type
 // and block hash.
-func (idx *CFIndex) entryByBlockHash(filterTypeKeys [][]byte,
+func (idx *CFIndex) entryByBlockHash(filterTypeKey func(wire.FilterType) []byte,
 	filterType wire.FilterType, h *chainhash.Hash) ([]byte, error) {
-	if uint8(filterType) > maxFilterType {
+	if !idx.HasFilterType(filterType) {
 		return nil, errors.New("unsupported filter type")
 	}
-	key := filterTypeKeys[filterType]
+	key := filterTypeKey(filterType)
 	var entry []byte
 	err := idx.db.View(func(dbTx database.Tx) error {
 		var err error
@@ -249,12 +434,12 @@ func (idx *CFIndex) entryByBlockHash(filterTypeKeys [][]byte,
 
 // entriesByBlockHashes batch fetches a filter index entry of a particular type (eg. filter, filter header, etc) for a
 // filter type and slice of block hashes.
-func (idx *CFIndex) entriesByBlockHashes(filterTypeKeys [][]byte,
+func (idx *CFIndex) entriesByBlockHashes(filterTypeKey func(wire.FilterType) []byte,
 	filterType wire.FilterType, blockHashes []*chainhash.Hash) ([][]byte, error) {
-	if uint8(filterType) > maxFilterType {
+	if !idx.HasFilterType(filterType) {
 		return nil, errors.New("unsupported filter type")
 	}
-	key := filterTypeKeys[filterType]
+	key := filterTypeKey(filterType)
 	entries := make([][]byte, 0, len(blockHashes))
 	err := idx.db.View(func(dbTx database.Tx) error {
 		for _, blockHash := range blockHashes {
@@ -273,40 +458,40 @@ func (idx *CFIndex) entriesByBlockHashes(filterTypeKeys [][]byte,
 // FilterByBlockHash returns the serialized contents of a block's basic or committed filter.
 func (idx *CFIndex) FilterByBlockHash(h *chainhash.Hash,
 	filterType wire.FilterType) ([]byte, error) {
-	return idx.entryByBlockHash(cfIndexKeys, filterType, h)
+	return idx.entryByBlockHash(cfIndexKey, filterType, h)
 }
 
 // FiltersByBlockHashes returns the serialized contents of a block's basic or committed filter for a set of blocks by
 // hash.
 func (idx *CFIndex) FiltersByBlockHashes(blockHashes []*chainhash.Hash,
 	filterType wire.FilterType) ([][]byte, error) {
-	return idx.entriesByBlockHashes(cfIndexKeys, filterType, blockHashes)
+	return idx.entriesByBlockHashes(cfIndexKey, filterType, blockHashes)
 }
 
 // FilterHeaderByBlockHash returns the serialized contents of a block's basic committed filter header.
 func (idx *CFIndex) FilterHeaderByBlockHash(h *chainhash.Hash,
 	filterType wire.FilterType) ([]byte, error) {
-	return idx.entryByBlockHash(cfHeaderKeys, filterType, h)
+	return idx.entryByBlockHash(cfHeaderKey, filterType, h)
 }
 
 // FilterHeadersByBlockHashes returns the serialized contents of a block's basic committed filter header for a set of
 // blocks by hash.
 func (idx *CFIndex) FilterHeadersByBlockHashes(blockHashes []*chainhash.Hash,
 	filterType wire.FilterType) ([][]byte, error) {
-	return idx.entriesByBlockHashes(cfHeaderKeys, filterType, blockHashes)
+	return idx.entriesByBlockHashes(cfHeaderKey, filterType, blockHashes)
 }
 
 // FilterHashByBlockHash returns the serialized contents of a block's basic committed filter hash.
 func (idx *CFIndex) FilterHashByBlockHash(h *chainhash.Hash,
 	filterType wire.FilterType) ([]byte, error) {
-	return idx.entryByBlockHash(cfHashKeys, filterType, h)
+	return idx.entryByBlockHash(cfHashKey, filterType, h)
 }
 
 // FilterHashesByBlockHashes returns the serialized contents of a block's basic committed filter hash for a set of
 // blocks by hash.
 func (idx *CFIndex) FilterHashesByBlockHashes(blockHashes []*chainhash.Hash,
 	filterType wire.FilterType) ([][]byte, error) {
-	return idx.entriesByBlockHashes(cfHashKeys, filterType, blockHashes)
+	return idx.entriesByBlockHashes(cfHashKey, filterType, blockHashes)
 }
 
 // NewCfIndex returns a new instance of an indexer that is used to create a mapping of the hashes of all blocks in the
@@ -314,7 +499,7 @@ func (idx *CFIndex) FilterHashesByBlockHashes(blockHashes []*chainhash.Hash,
 // IndexManager that in turn is used by the blockchain package. This allows the index to be seamlessly maintained along
 // with the chain.
 func NewCfIndex(db database.DB, chainParams *netparams.Params) *CFIndex {
-	return &CFIndex{db: db, chainParams: chainParams}
+	return &CFIndex{db: db, chainParams: chainParams, filterTypes: make(map[wire.FilterType]struct{})}
 }
 
 // DropCfIndex drops the CF index from the provided database if exists.
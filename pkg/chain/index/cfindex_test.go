@@ -0,0 +1,200 @@
+package indexers
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/coding/gcs/builder"
+	database "github.com/p9c/pod/pkg/db"
+	_ "github.com/p9c/pod/pkg/db/ffldb"
+	"github.com/p9c/pod/pkg/util"
+	qu "github.com/p9c/pod/pkg/util/quit"
+)
+
+// testCfIndexChain returns a new regression test chain holding only the genesis block, with the committed filter
+// index enabled. The returned function removes the chain.
+func testCfIndexChain(t *testing.T) (*blockchain.BlockChain, *CFIndex, func()) {
+	dir, err := ioutil.TempDir("", "cfindextest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	params := &netparams.RegressionTestParams
+	db, err := database.Create("ffldb", dir, params.Net)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unable to create db: %v", err)
+	}
+	teardown := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+	idx := NewCfIndex(db, params)
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:           db,
+		ChainParams:  params,
+		TimeSource:   blockchain.NewMedianTime(),
+		SigCache:     txscript.NewSigCache(1000),
+		IndexManager: NewManager(db, []Indexer{idx}),
+	})
+	if err != nil {
+		teardown()
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	return chain, idx, teardown
+}
+
+// addCfIndexTestBlocks extends the best chain by numBlocks blocks holding only a coinbase, which commits to extraNonce
+// to tell apart blocks at the same height on competing branches. The proof of work of the blocks is not solved.
+func addCfIndexTestBlocks(t *testing.T, chain *blockchain.BlockChain, numBlocks int, extraNonce int64) {
+	const version = 2
+	for i := 0; i < numBlocks; i++ {
+		best := chain.BestSnapshot()
+		parent, err := chain.HeaderByHash(&best.Hash)
+		if err != nil {
+			t.Fatalf("unable to fetch best header: %v", err)
+		}
+		height := best.Height + 1
+		timestamp := time.Unix(parent.Timestamp.Unix()+fork.GetTargetTimePerBlock(height), 0)
+		bits, err := chain.CalcNextRequiredDifficulty(0, timestamp, fork.GetAlgoName(version, height))
+		if err != nil {
+			t.Fatalf("unable to calculate difficulty: %v", err)
+		}
+		coinbaseScript, err := txscript.NewScriptBuilder().AddInt64(int64(height)).AddInt64(extraNonce).Script()
+		if err != nil {
+			t.Fatalf("unable to create coinbase script: %v", err)
+		}
+		coinbase := wire.NewMsgTx(wire.TxVersion)
+		coinbase.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: *wire.NewOutPoint(&chainhash.Hash{}, wire.MaxPrevOutIndex),
+			SignatureScript:  coinbaseScript,
+			Sequence:         wire.MaxTxInSequenceNum,
+		})
+		coinbase.AddTxOut(wire.NewTxOut(blockchain.CalcBlockSubsidy(height, &netparams.RegressionTestParams, version),
+			[]byte{txscript.OP_TRUE}))
+		msgBlock := wire.NewMsgBlock(&wire.BlockHeader{
+			Version:   version,
+			PrevBlock: best.Hash,
+			Timestamp: timestamp,
+			Bits:      bits,
+		})
+		if err = msgBlock.AddTransaction(coinbase); err != nil {
+			t.Fatalf("unable to add coinbase: %v", err)
+		}
+		merkles := blockchain.BuildMerkleTreeStore(util.NewBlock(msgBlock).Transactions(), false)
+		msgBlock.Header.MerkleRoot = *merkles[len(merkles)-1]
+		block := util.NewBlock(msgBlock)
+		block.SetHeight(height)
+		if _, _, err = chain.ProcessBlock(0, block, blockchain.BFNoPoWCheck, height); err != nil {
+			t.Fatalf("unable to process block %d: %v", height, err)
+		}
+	}
+}
+
+// TestBuildFilterTypeResume ensures a build of the filters of a type that was interrupted resumes after the last block
+// it stored, or after the last block it has in common with the main chain if that block was disconnected since, and
+// ends with the filters and filter headers of every block in the main chain.
+func TestBuildFilterTypeResume(t *testing.T) {
+	chain, idx, teardown := testCfIndexChain(t)
+	defer teardown()
+	addCfIndexTestBlocks(t, chain, 8, 0)
+	const filterType = wire.GCSFilterExtended
+	if idx.HasFilterType(filterType) {
+		t.Fatalf("extended filters are kept before they are built")
+	}
+	// A build interrupted right away only creates the buckets of the type.
+	interrupted := qu.T()
+	interrupted.Q()
+	if err := idx.BuildFilterType(chain, filterType, interrupted); err != errInterruptRequested {
+		t.Fatalf("BuildFilterType: got err %v, want %v", err, errInterruptRequested)
+	}
+	// Stop the build after the batch up to height 5 as an interrupt would, then disconnect that block and extend the
+	// chain on its parent. The build must resume from height 5 on the new branch.
+	if _, err := idx.buildFilterBatch(chain, filterType, 0, 5); err != nil {
+		t.Fatalf("buildFilterBatch: unexpected err %v", err)
+	}
+	if idx.HasFilterType(filterType) {
+		t.Fatalf("extended filters are kept before their build is done")
+	}
+	stale, err := chain.BlockHashByHeight(5)
+	if err != nil {
+		t.Fatalf("BlockHashByHeight: unexpected err %v", err)
+	}
+	if err = chain.InvalidateBlock(stale); err != nil {
+		t.Fatalf("InvalidateBlock: unexpected err %v", err)
+	}
+	addCfIndexTestBlocks(t, chain, 4, 1)
+	if err = idx.BuildFilterType(chain, filterType, qu.T()); err != nil {
+		t.Fatalf("BuildFilterType: unexpected err %v", err)
+	}
+	if !idx.HasFilterType(filterType) {
+		t.Fatalf("extended filters are not kept after their build is done")
+	}
+	err = idx.db.View(func(dbTx database.Tx) error {
+		if dbTx.Metadata().Bucket(cfIndexParentBucketKey).Get(cfBuildKey(filterType)) != nil {
+			t.Errorf("build tip is still stored after the build is done")
+		}
+		if entry, _ := dbFetchFilterIdxEntry(dbTx, cfHeaderKey(filterType), stale); entry != nil {
+			t.Errorf("filter header of the disconnected block is still stored")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unable to read build tip: %v", err)
+	}
+	// The filter headers must chain through the filters of the blocks of the main chain.
+	var prevHeader chainhash.Hash
+	best := chain.BestSnapshot()
+	if best.Height != 8 {
+		t.Fatalf("best chain height is %d, want 8", best.Height)
+	}
+	for height := int32(0); height <= best.Height; height++ {
+		block, err := chain.BlockByHeight(height)
+		if err != nil {
+			t.Fatalf("BlockByHeight: unexpected err %v", err)
+		}
+		stxos, err := chain.FetchSpendJournal(block)
+		if err != nil {
+			t.Fatalf("FetchSpendJournal: unexpected err %v", err)
+		}
+		prevScripts := make([][]byte, len(stxos))
+		for i, stxo := range stxos {
+			prevScripts[i] = stxo.PkScript
+		}
+		f, err := builder.BuildFilter(filterType, block.MsgBlock(), prevScripts)
+		if err != nil {
+			t.Fatalf("BuildFilter: unexpected err %v", err)
+		}
+		wantFilter, err := f.NBytes()
+		if err != nil {
+			t.Fatalf("NBytes: unexpected err %v", err)
+		}
+		wantHeader, err := builder.MakeHeaderForFilter(f, prevHeader)
+		if err != nil {
+			t.Fatalf("MakeHeaderForFilter: unexpected err %v", err)
+		}
+		prevHeader = wantHeader
+		gotFilter, err := idx.FilterByBlockHash(block.Hash(), filterType)
+		if err != nil {
+			t.Fatalf("FilterByBlockHash: unexpected err %v", err)
+		}
+		if !bytes.Equal(gotFilter, wantFilter) {
+			t.Errorf("filter of block %d is %x, want %x", height, gotFilter, wantFilter)
+		}
+		gotHeader, err := idx.FilterHeaderByBlockHash(block.Hash(), filterType)
+		if err != nil {
+			t.Fatalf("FilterHeaderByBlockHash: unexpected err %v", err)
+		}
+		if !bytes.Equal(gotHeader, wantHeader[:]) {
+			t.Errorf("filter header of block %d is %x, want %x", height, gotHeader, wantHeader)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	database "github.com/p9c/pod/pkg/db"
 	"github.com/p9c/pod/pkg/util"
 	log "github.com/p9c/pod/pkg/util/logi"
+	qu "github.com/p9c/pod/pkg/util/quit"
 )
 
 var (
@@ -623,3 +624,98 @@ func dropIndex(db database.DB, idxKey []byte, idxName string, interrupt <-chan s
 	Info("dropped", idxName)
 	return nil
 }
+
+// knownIndex is the name of an index and the function that drops it from the database.
+type knownIndex struct {
+	name string
+	drop func(db database.DB, interrupt qu.C) error
+}
+
+// knownIndexes holds every index that can be kept in the database by its key.
+var knownIndexes = map[string]knownIndex{
+	string(txIndexKey):             {txIndexName, DropTxIndex},
+	string(addrIndexKey):           {addrIndexName, DropAddrIndex},
+	string(addrUtxoIndexKey):       {addrUtxoIndexName, DropAddrUtxoIndex},
+	string(algoStatsIndexKey):      {algoStatsIndexName, DropAlgoStatsIndex},
+	string(cfIndexParentBucketKey): {cfIndexName, DropCfIndex},
+}
+
+// CheckTips compares the tip of every index in the database with the best chain and returns a discrepancy for each
+// index whose tip is not the best block. The height and hash of a discrepancy are those of the index tip.
+//
+// An index that is behind the best block is caught up when it is next enabled, but one whose tip is not in the main
+// chain can only be rolled back while the spend journal of its blocks is still stored.
+func CheckTips(db database.DB, chain *blockchain.BlockChain) ([]blockchain.DBDiscrepancy, error) {
+	best := chain.BestSnapshot()
+	var discrepancies []blockchain.DBDiscrepancy
+	err := db.View(func(dbTx database.Tx) error {
+		tips := dbTx.Metadata().Bucket(indexTipsBucketName)
+		if tips == nil {
+			return nil
+		}
+		for key, idx := range knownIndexes {
+			if tips.Get([]byte(key)) == nil {
+				continue
+			}
+			hash, height, err := dbFetchIndexerTip(dbTx, []byte(key))
+			if err != nil {
+				discrepancies = append(discrepancies, blockchain.DBDiscrepancy{
+					Description: fmt.Sprintf("%s tip cannot be read: %v", idx.name, err),
+				})
+				continue
+			}
+			var description string
+			switch {
+			case height == -1:
+				description = fmt.Sprintf("%s has no blocks yet", idx.name)
+			case !chain.MainChainHasBlock(hash):
+				description = fmt.Sprintf("%s tip is not in the main chain", idx.name)
+			case height < best.Height:
+				description = fmt.Sprintf("%s is %d blocks behind the best block", idx.name, best.Height-height)
+			default:
+				continue
+			}
+			discrepancies = append(discrepancies, blockchain.DBDiscrepancy{
+				Height:      height,
+				Hash:        *hash,
+				Description: description,
+			})
+		}
+		return nil
+	})
+	return discrepancies, err
+}
+
+// DropOrphanedIndexes drops every index in the database whose tip is not in the main chain, so that it is built again
+// from the start when it is next enabled.
+func DropOrphanedIndexes(db database.DB, chain *blockchain.BlockChain, interrupt qu.C) error {
+	var orphaned []knownIndex
+	err := db.View(func(dbTx database.Tx) error {
+		tips := dbTx.Metadata().Bucket(indexTipsBucketName)
+		if tips == nil {
+			return nil
+		}
+		for key, idx := range knownIndexes {
+			if tips.Get([]byte(key)) == nil {
+				continue
+			}
+			hash, height, err := dbFetchIndexerTip(dbTx, []byte(key))
+			if err != nil || (height != -1 && !chain.MainChainHasBlock(hash)) {
+				orphaned = append(orphaned, idx)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	for _, idx := range orphaned {
+		Warnf("dropping %s as its tip is not in the main chain", idx.name)
+		if err = idx.drop(db, interrupt); err != nil {
+			Error(err)
+			return err
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"container/heap"
 	"fmt"
+	"sort"
 	"time"
 
 	blockchain "github.com/p9c/pod/pkg/chain"
@@ -51,6 +52,11 @@ type (
 		MiningDescs() []*TxDesc
 		// HaveTransaction returns whether or not the passed transaction hash exists in the source pool.
 		HaveTransaction(hash *chainhash.Hash) bool
+		// MaxSize returns the maximum total size in bytes of the transactions in the source pool, or zero if it is
+		// unbounded.
+		MaxSize() int64
+		// MinFee returns the minimum fee in Satoshi/kB the source pool currently requires of new transactions.
+		MinFee() util.Amount
 	}
 	// txPrioItem houses a transaction along with extra information that allows the transaction to be prioritized and
 	// track dependencies on other transactions which have not been mined into a block yet.
@@ -59,11 +65,22 @@ type (
 		fee      int64
 		priority float64
 		feePerKB int64
+		// size is the virtual size of the transaction.
+		size int64
 		// dependsOn holds a map of transaction hashes which this one depends on.
 		//
 		// It will only be set when the transaction references other transactions in the source pool and hence must come
 		// after them in a block.
 		dependsOn map[chainhash.Hash]struct{}
+		// ancestors holds the transactions in the source pool which this one depends on, either directly or through
+		// other transactions, and which have not been included in the block yet. The transaction can only be included
+		// together with them, and they form its package.
+		ancestors map[chainhash.Hash]*txPrioItem
+		// packageFee and packageSize are the total fee and virtual size of the transaction and its ancestors.
+		packageFee  int64
+		packageSize int64
+		// index is the position of the item in the priority queue, or -1 when it is not in the queue.
+		index int
 	}
 	// txPriorityQueueLessFunc describes a function that can be used as a compare function for a transaction priority
 	// queue (txPriorityQueue).
@@ -131,13 +148,17 @@ func (pq *txPriorityQueue) Less(i, j int) bool {
 // It is part of the heap.Interface implementation.
 func (pq *txPriorityQueue) Swap(i, j int) {
 	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
 }
 
 // Push pushes the passed item onto the priority queue.
 //
 // It is part of the heap.Interface implementation.
 func (pq *txPriorityQueue) Push(x interface{}) {
-	pq.items = append(pq.items, x.(*txPrioItem))
+	item := x.(*txPrioItem)
+	item.index = len(pq.items)
+	pq.items = append(pq.items, item)
 }
 
 // Pop removes the highest priority item (according to Less) from the priority queue and returns it.
@@ -146,6 +167,7 @@ func (pq *txPriorityQueue) Push(x interface{}) {
 func (pq *txPriorityQueue) Pop() interface{} {
 	n := len(pq.items)
 	item := pq.items[n-1]
+	item.index = -1
 	pq.items[n-1] = nil
 	pq.items = pq.items[0 : n-1]
 	return item
@@ -170,13 +192,14 @@ func txPQByPriority(pq *txPriorityQueue, i, j int) bool {
 	return pq.items[i].priority > pq.items[j].priority
 }
 
-// txPQByFee sorts a txPriorityQueue by fees per kilobyte and then transaction priority.
+// txPQByFee sorts a txPriorityQueue by the fees per kilobyte of the transaction packages and then transaction priority.
 func txPQByFee(pq *txPriorityQueue, i, j int) bool {
 	// Using > here so that pop gives the highest fee item as opposed to the lowest. Sort by fee first, then priority.
-	if pq.items[i].feePerKB == pq.items[j].feePerKB {
+	feeI, feeJ := pq.items[i].packageFeePerKB(), pq.items[j].packageFeePerKB()
+	if feeI == feeJ {
 		return pq.items[i].priority > pq.items[j].priority
 	}
-	return pq.items[i].feePerKB > pq.items[j].feePerKB
+	return feeI > feeJ
 }
 
 // newTxPriorityQueue returns a new transaction priority queue that reserves the passed amount of space for the
@@ -195,6 +218,89 @@ func newTxPriorityQueue(reserve int, sortByFee bool) *txPriorityQueue {
 	return pq
 }
 
+// packageFeePerKB returns the fee in Satoshi/kB paid by the transaction together with its ancestors which have not been
+// included in the block yet. A high fee child can therefore raise the rate of its low fee parents.
+func (item *txPrioItem) packageFeePerKB() int64 {
+	return item.packageFee * 1000 / item.packageSize
+}
+
+// packageItems returns the transactions which make up the package of the item, which are its ancestors that have not
+// been included in the block yet followed by the item itself, ordered so that every transaction comes after those it
+// depends on.
+func (item *txPrioItem) packageItems() []*txPrioItem {
+	pkg := make([]*txPrioItem, 0, len(item.ancestors)+1)
+	for _, ancestor := range item.ancestors {
+		pkg = append(pkg, ancestor)
+	}
+	// Every transaction has more ancestors left to include than any of its own ancestors, so sorting by that count puts
+	// parents before their children.
+	sort.Slice(pkg, func(i, j int) bool {
+		return len(pkg[i].ancestors) < len(pkg[j].ancestors)
+	})
+	return append(pkg, item)
+}
+
+// findAncestors sets the ancestors of the passed item to all of the transactions in items it depends on, either directly
+// or through other transactions. It returns false if any of them is not available for inclusion in the block, in which
+// case neither is the item. The results are recorded in done so each transaction is only visited once. The recursion
+// is bounded by the ancestor limits the source pool enforces.
+func findAncestors(item *txPrioItem, items map[chainhash.Hash]*txPrioItem, done map[chainhash.Hash]bool) bool {
+	hash := *item.tx.Hash()
+	if ok, seen := done[hash]; seen {
+		return ok
+	}
+	item.ancestors = make(map[chainhash.Hash]*txPrioItem)
+	for parentHash := range item.dependsOn {
+		parent, exists := items[parentHash]
+		if !exists || !findAncestors(parent, items, done) {
+			done[hash] = false
+			return false
+		}
+		item.ancestors[parentHash] = parent
+		for ancestorHash, ancestor := range parent.ancestors {
+			item.ancestors[ancestorHash] = ancestor
+		}
+	}
+	done[hash] = true
+	return true
+}
+
+// forEachDescendant calls fn with every item which depends on the transaction with the passed hash, either directly or
+// through other transactions, using the dependers map built while generating a block template.
+func forEachDescendant(hash chainhash.Hash, dependers map[chainhash.Hash]map[chainhash.Hash]*txPrioItem,
+	fn func(*txPrioItem)) {
+	seen := make(map[chainhash.Hash]struct{})
+	pending := []chainhash.Hash{hash}
+	for len(pending) > 0 {
+		next := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		for childHash, child := range dependers[next] {
+			if _, ok := seen[childHash]; ok {
+				continue
+			}
+			seen[childHash] = struct{}{}
+			fn(child)
+			pending = append(pending, childHash)
+		}
+	}
+}
+
+// skipTxn removes the passed item, if it is still queued, along with every transaction which depends on it from the
+// priority queue, since none of them can be included in the block without it. The dependent transactions are logged at
+// the trace level.
+func skipTxn(pq *txPriorityQueue, item *txPrioItem, dependers map[chainhash.Hash]map[chainhash.Hash]*txPrioItem) {
+	if item.index >= 0 {
+		heap.Remove(pq, item.index)
+	}
+	forEachDescendant(*item.tx.Hash(), dependers, func(descendant *txPrioItem) {
+		if descendant.index < 0 {
+			return
+		}
+		Tracef("skipping tx %s since it depends on %s", descendant.tx.Hash(), item.tx.Hash())
+		heap.Remove(pq, descendant.index)
+	})
+}
+
 // mergeUtxoView adds all of the entries in viewB to viewA. The result is that viewA will contain all of its original
 // entries plus all of the entries in viewB. It will replace any entries in viewB which also exist in viewA if the entry
 // in viewA is spent.
@@ -277,18 +383,6 @@ func spendTransaction(utxoView *blockchain.UtxoViewpoint, tx *util.Tx, height in
 	return nil
 }
 
-// logSkippedDeps logs any dependencies which are also skipped as a result of skipping a transaction while generating a
-// block template at the trace level.
-func logSkippedDeps(tx *util.Tx, deps map[chainhash.Hash]*txPrioItem) {
-	if deps == nil {
-		return
-	}
-	for _, item := range deps {
-		Tracef("skipping tx %s since it depends on %s", item.tx.Hash(),
-			tx.Hash())
-	}
-}
-
 // MinimumMedianTime returns the minimum allowed timestamp for a block building on the end of the provided best chain.
 // In particular, it is one second after the median timestamp of the last several blocks per the chain consensus rules.
 func MinimumMedianTime(chainState *blockchain.BestState) time.Time {
@@ -342,21 +436,24 @@ func NewBlkTmplGenerator(policy *Policy, params *netparams.Params,
 // per kilobyte is calculated for each transaction. Transactions with a higher fee per kilobyte are preferred. Finally,
 // the block generation related policy settings are all taken into account.
 //
-// Transactions which only spend outputs from other transactions already in the block chain are immediately added to a
-// priority queue which either prioritizes based on the priority (then fee per kilobyte) or the fee per kilobyte (then
-// priority) depending on whether or not the BlockPrioritySize policy setting allots space for high-priority
-// transactions.
+// Transactions which spend outputs from other transactions in the source pool can only be included after those
+// transactions, so each transaction is considered together with its ancestors in the source pool that are not in the
+// block yet as a package. The fee per kilobyte of a package is the total fee of its transactions divided by their total
+// size, which lets a child paying a high fee pull its low-fee parents into the block.
 //
-// Transactions which spend outputs from other transactions in the source pool are added to a dependency map so they can
-// be added to the priority queue once the transactions they depend on have been included. Once the high-priority area
-// (if configured) has been filled with transactions, or the priority falls below what is considered high-priority, the
-// priority queue is updated to prioritize by fees per kilobyte (then priority).
+// Every transaction is added to a priority queue which either prioritizes based on the priority (then fee per kilobyte)
+// or the package fee per kilobyte (then priority) depending on whether or not the BlockPrioritySize policy setting
+// allots space for high-priority transactions. When a transaction is selected its whole package is added to the block,
+// parents first, and the packages of the transactions depending on them are updated. Once the high-priority area (if
+// configured) has been filled with transactions, or the priority falls below what is considered high-priority, the
+// priority queue is updated to prioritize by package fees per kilobyte (then priority).
 //
-// When the fees per kilobyte drop below the TxMinFreeFee policy setting, the transaction will be skipped unless the
-// BlockMinSize policy setting is nonzero, in which case the block will be filled with the low-fee/free transactions
-// until the block size reaches that minimum size. Any transactions which would cause the block to exceed the
-// BlockMaxSize policy setting, exceed the maximum allowed signature operations per block, or otherwise cause the block
-// to be invalid are skipped.
+// When the package fees per kilobyte drop below the TxMinFreeFee policy setting, or the minimum fee the source pool
+// currently requires if that is higher, the transaction will be skipped unless the BlockMinSize policy setting is
+// nonzero, in which case the block will be filled with the low-fee/free transactions until the block size reaches that
+// minimum size. Any transactions which would cause the block to exceed the BlockMaxSize policy setting, exceed the
+// maximum allowed signature operations per block, or otherwise cause the block to be invalid are skipped along with the
+// transactions that depend on them.
 //
 // Given the above, a block generated by this function is of the following form:
 //
@@ -415,6 +512,12 @@ Address, algo string) (*BlockTemplate, error) {
 	// or not there is an area allocated for high-priority transactions.
 	sourceTxns := g.TxSource.MiningDescs()
 	sortedByFee := g.Policy.BlockPrioritySize == 0
+	// Transactions paying less than the minimum fee the source pool currently requires are treated as free, as the pool
+	// may have raised it above the policy minimum to evict them.
+	minFreeFee := g.Policy.TxMinFreeFee
+	if poolMinFee := g.TxSource.MinFee(); poolMinFee > minFreeFee {
+		minFreeFee = poolMinFee
+	}
 	priorityQueue := newTxPriorityQueue(len(sourceTxns), sortedByFee)
 	// Create a slice to hold the transactions to be included in the generated block with reserved space. Also create a
 	// utxo view to house all of the input transactions so multiple lookups can be avoided.
@@ -425,6 +528,8 @@ Address, algo string) (*BlockTemplate, error) {
 	// conjunction with the dependsOn map kept with each dependent transaction helps quickly determine which dependent
 	// transactions are now eligible for inclusion in the block once each transaction has been included.
 	dependers := make(map[chainhash.Hash]map[chainhash.Hash]*txPrioItem)
+	// items holds every transaction which may be included in the block, provided the transactions it depends on are.
+	items := make(map[chainhash.Hash]*txPrioItem, len(sourceTxns))
 	// Create slices to hold the fees and number of signature operations for each of the selected transactions and add
 	// an entry for the coinbase. This allows the code below to simply append details about a transaction as it is
 	// selected for inclusion in the final block. However, since the total fees aren't known yet, use a dummy value for
@@ -464,7 +569,7 @@ mempoolLoop:
 		}
 		// Setup dependencies for any transactions which reference other transactions in the mempool so they can be
 		// properly ordered below.
-		prioItem := &txPrioItem{tx: tx}
+		prioItem := &txPrioItem{tx: tx, index: -1}
 		for _, txIn := range tx.MsgTx().TxIn {
 			originHash := &txIn.PreviousOutPoint.Hash
 			entry := utxos.LookupEntry(txIn.PreviousOutPoint)
@@ -501,15 +606,28 @@ mempoolLoop:
 		// Calculate the fee in Satoshi/kB.
 		prioItem.feePerKB = txDesc.FeePerKB
 		prioItem.fee = txDesc.Fee
-		// Add the transaction to the priority queue to mark it ready for inclusion in the block unless it has
-		// dependencies.
-		if prioItem.dependsOn == nil {
-			heap.Push(priorityQueue, prioItem)
-		}
+		prioItem.size = (blockchain.GetTransactionWeight(tx) + blockchain.WitnessScaleFactor - 1) /
+			blockchain.WitnessScaleFactor
+		items[*tx.Hash()] = prioItem
 		// Merge the referenced outputs from the input transactions to this transaction into the block utxo view. This
 		// allows the code below to avoid a second lookup.
 		mergeUtxoView(blockUtxos, utxos)
 	}
+	// Add every transaction whose ancestors in the source pool are all available to the priority queue along with the
+	// fee and size of its package, so a child paying a high fee can pull its parents into the block.
+	done := make(map[chainhash.Hash]bool, len(items))
+	for _, prioItem := range items {
+		if !findAncestors(prioItem, items, done) {
+			Tracef("skipping tx %s because one of its ancestors is not available", prioItem.tx.Hash())
+			continue
+		}
+		prioItem.packageFee, prioItem.packageSize = prioItem.fee, prioItem.size
+		for _, ancestor := range prioItem.ancestors {
+			prioItem.packageFee += ancestor.fee
+			prioItem.packageSize += ancestor.size
+		}
+		heap.Push(priorityQueue, prioItem)
+	}
 	// Tracec(func() string {
 	//	return fmt.Sprintf(
 	//		"priority queue len %d, dependers len %d",
@@ -536,17 +654,27 @@ mempoolLoop:
 	witnessIncluded := false
 	// Choose which transactions make it into the block.
 	for priorityQueue.Len() > 0 {
-		// Grab the highest priority (or highest fee per kilobyte depending on the sort order) transaction.
+		// Grab the highest priority (or highest package fee per kilobyte depending on the sort order) transaction.
 		prioItem := heap.Pop(priorityQueue).(*txPrioItem)
 		tx := prioItem.tx
+		// The transaction can only be included together with its ancestors which are not in the block yet, so the
+		// checks below apply to the whole package.
+		pkg := prioItem.packageItems()
+		pkgHasWitness := false
+		var pkgWeight uint32
+		for _, item := range pkg {
+			pkgHasWitness = pkgHasWitness || item.tx.HasWitness()
+			pkgWeight += uint32(blockchain.GetTransactionWeight(item.tx))
+		}
 		switch {
 		// If segregated witness has not been activated yet, then we shouldn't include any witness transactions in the
 		// block.
-		case !segwitActive && tx.HasWitness():
+		case !segwitActive && pkgHasWitness:
+			skipTxn(priorityQueue, prioItem, dependers)
 			continue
 			// Otherwise, Keep track of if we've included a transaction with witness data or not. If so, then we'll need
 			// to include the witness commitment as the last output in the coinbase transaction.
-		case segwitActive && !witnessIncluded && tx.HasWitness():
+		case segwitActive && !witnessIncluded && pkgHasWitness:
 			// If we're about to include a transaction bearing witness data, then we'll also need to include a witness
 			// commitment in the coinbase transaction. Therefore, we account for the additional weight within the block
 			// with a model coinbase tx with a witness commitment.
@@ -566,52 +694,29 @@ mempoolLoop:
 			blockWeight += uint32(weightDiff)
 			witnessIncluded = true
 		}
-		// Grab any transactions which depend on this one.
-		deps := dependers[*tx.Hash()]
 		// Enforce maximum block size.  Also check for overflow.
-		txWeight := uint32(blockchain.GetTransactionWeight(tx))
-		blockPlusTxWeight := blockWeight + txWeight
+		blockPlusTxWeight := blockWeight + pkgWeight
 		if blockPlusTxWeight < blockWeight ||
 			blockPlusTxWeight >= g.Policy.BlockMaxWeight {
 			Tracef("skipping tx %s because it would exceed the max block"+
 				" weight", tx.Hash())
-			logSkippedDeps(tx, deps)
-			continue
-		}
-		// Enforce maximum signature operation cost per block. Also check for overflow.
-		sigOpCost, err := blockchain.GetSigOpCost(tx, false,
-			blockUtxos, true, segwitActive)
-		if err != nil {
-			Tracec(func() string {
-				return "skipping tx " + tx.Hash().String() +
-					"due to error in GetSigOpCost: " + err.Error()
-			})
-			logSkippedDeps(tx, deps)
-			continue
-		}
-		if blockSigOpCost+int64(sigOpCost) < blockSigOpCost ||
-			blockSigOpCost+int64(sigOpCost) > blockchain.MaxBlockSigOpsCost {
-			Tracec(func() string {
-				return "skipping tx " + tx.Hash().String() +
-					" because it would exceed the maximum sigops per block"
-			})
-			logSkippedDeps(tx, deps)
+			skipTxn(priorityQueue, prioItem, dependers)
 			continue
 		}
 		// Skip free transactions once the block is larger than the minimum block size.
 		if sortedByFee &&
-			prioItem.feePerKB < int64(g.Policy.TxMinFreeFee) &&
+			prioItem.packageFeePerKB() < int64(minFreeFee) &&
 			blockPlusTxWeight >= g.Policy.BlockMinWeight {
 			Tracec(func() string {
 				return fmt.Sprint(
 					"skipping tx ", tx.Hash(),
-					" with feePerKB ", prioItem.feePerKB,
-					" < TxMinFreeFee ", g.Policy.TxMinFreeFee,
+					" with package feePerKB ", prioItem.packageFeePerKB(),
+					" < TxMinFreeFee ", minFreeFee,
 					" and block weight ", blockPlusTxWeight,
 					" >= minBlockWeight ", g.Policy.BlockMinWeight,
 				)
 			})
-			logSkippedDeps(tx, deps)
+			skipTxn(priorityQueue, prioItem, dependers)
 			continue
 		}
 		// Prioritize by fee per kilobyte once the block is larger than the priority size or there are no more
@@ -626,65 +731,96 @@ mempoolLoop:
 				MinHighPriority)
 			sortedByFee = true
 			priorityQueue.SetLessFunc(txPQByFee)
+			// Put the transaction back into the priority queue and skip it so it is re-prioritized by fees if it won't
+			// fit into the high-priority section or the priority is too low. Otherwise this transaction will be the
+			// final one in the high-priority section, so just fall though to the code below so it is added now.
+			if blockPlusTxWeight > g.Policy.BlockPrioritySize ||
+				prioItem.priority < MinHighPriority.ToDUO() {
+				heap.Push(priorityQueue, prioItem)
+				continue
+			}
 		}
-		// Put the transaction back into the priority queue and skip it so it is re-prioritized by fees if it won't fit
-		// into the high-priority section or the priority is too low. Otherwise this transaction will be the final one
-		// in the high-priority section, so just fall though to the code below so it is added now.
-		if blockPlusTxWeight > g.Policy.BlockPrioritySize ||
-			prioItem.priority < MinHighPriority.ToDUO() {
-			heap.Push(priorityQueue, prioItem)
-			continue
-		}
-
-		// Ensure the transaction inputs pass all of the necessary preconditions before allowing it to be added to the
-		// block.
-		_, err = blockchain.CheckTransactionInputs(tx, nextBlockHeight,
-			blockUtxos, g.ChainParams)
-		if err != nil {
-			Tracef("skipping tx %s due to error in CheckTransactionInputs"+
-				": %v",
-				tx.Hash(), err)
-			logSkippedDeps(tx, deps)
-			continue
-		}
-		err = blockchain.ValidateTransactionScripts(g.Chain, tx, blockUtxos,
-			txscript.StandardVerifyFlags, g.SigCache,
-			g.HashCache)
-		if err != nil {
-			Tracef("skipping tx %s due to error in"+
-				" ValidateTransactionScripts: %v",
-				tx.Hash(), err)
-			logSkippedDeps(tx, deps)
-			continue
-		}
-
-		// Spend the transaction inputs in the block utxo view and add an entry for it to ensure any transactions which
-		// reference this one have it available as an input and can ensure they aren't double spending.
-		err = spendTransaction(blockUtxos, tx, nextBlockHeight)
-		if err != nil {
-			Error(err)
-		}
-		// Add the transaction to the block, increment counters, and save the fees and signature operation counts to the
-		// block template.
-		blockTxns = append(blockTxns, tx)
-		blockWeight += txWeight
-		blockSigOpCost += int64(sigOpCost)
-		totalFees += prioItem.fee
-		txFees = append(txFees, prioItem.fee)
-		txSigOpCosts = append(txSigOpCosts, int64(sigOpCost))
-		Tracef("adding tx %s (priority %.2f, feePerKB %.2f)",
-			prioItem.tx.Hash(),
-			prioItem.priority,
-			prioItem.feePerKB)
-		// Add transactions which depend on this one (and also do not have any other unsatisfied dependencies) to the
-		// priority queue.
-		for _, item := range deps {
-			// Add the transaction to the priority queue if there are no more
-			// dependencies after this one.
-			delete(item.dependsOn, *tx.Hash())
-			if len(item.dependsOn) == 0 {
-				heap.Push(priorityQueue, item)
+		// Add the package to the block one transaction at a time, parents first, so each one can spend the outputs of
+		// those before it. A transaction that fails is skipped along with everything depending on it, which includes
+		// the rest of the package.
+		for _, item := range pkg {
+			tx := item.tx
+			// Enforce maximum signature operation cost per block. Also check for overflow.
+			sigOpCost, err := blockchain.GetSigOpCost(tx, false,
+				blockUtxos, true, segwitActive)
+			if err != nil {
+				Tracec(func() string {
+					return "skipping tx " + tx.Hash().String() +
+						"due to error in GetSigOpCost: " + err.Error()
+				})
+				skipTxn(priorityQueue, item, dependers)
+				break
 			}
+			if blockSigOpCost+int64(sigOpCost) < blockSigOpCost ||
+				blockSigOpCost+int64(sigOpCost) > blockchain.MaxBlockSigOpsCost {
+				Tracec(func() string {
+					return "skipping tx " + tx.Hash().String() +
+						" because it would exceed the maximum sigops per block"
+				})
+				skipTxn(priorityQueue, item, dependers)
+				break
+			}
+			// Ensure the transaction inputs pass all of the necessary preconditions before allowing it to be added to
+			// the block.
+			_, err = blockchain.CheckTransactionInputs(tx, nextBlockHeight,
+				blockUtxos, g.ChainParams)
+			if err != nil {
+				Tracef("skipping tx %s due to error in CheckTransactionInputs"+
+					": %v",
+					tx.Hash(), err)
+				skipTxn(priorityQueue, item, dependers)
+				break
+			}
+			err = blockchain.ValidateTransactionScripts(g.Chain, tx, blockUtxos,
+				txscript.StandardVerifyFlags, g.SigCache,
+				g.HashCache)
+			if err != nil {
+				Tracef("skipping tx %s due to error in"+
+					" ValidateTransactionScripts: %v",
+					tx.Hash(), err)
+				skipTxn(priorityQueue, item, dependers)
+				break
+			}
+			// Spend the transaction inputs in the block utxo view and add an entry for it to ensure any transactions
+			// which reference this one have it available as an input and can ensure they aren't double spending.
+			err = spendTransaction(blockUtxos, tx, nextBlockHeight)
+			if err != nil {
+				Error(err)
+			}
+			// Add the transaction to the block, increment counters, and save the fees and signature operation counts
+			// to the block template.
+			blockTxns = append(blockTxns, tx)
+			blockWeight += uint32(blockchain.GetTransactionWeight(tx))
+			blockSigOpCost += int64(sigOpCost)
+			totalFees += item.fee
+			txFees = append(txFees, item.fee)
+			txSigOpCosts = append(txSigOpCosts, int64(sigOpCost))
+			Tracef("adding tx %s (priority %.2f, feePerKB %d, package feePerKB %d)",
+				tx.Hash(),
+				item.priority,
+				item.feePerKB,
+				item.packageFeePerKB())
+			// An ancestor included as part of the package is no longer waiting in the priority queue, and the packages
+			// of the transactions which depend on it no longer include it.
+			if item.index >= 0 {
+				heap.Remove(priorityQueue, item.index)
+			}
+			forEachDescendant(*tx.Hash(), dependers, func(descendant *txPrioItem) {
+				if _, ok := descendant.ancestors[*tx.Hash()]; !ok {
+					return
+				}
+				delete(descendant.ancestors, *tx.Hash())
+				descendant.packageFee -= item.fee
+				descendant.packageSize -= item.size
+				if descendant.index >= 0 {
+					heap.Fix(priorityQueue, descendant.index)
+				}
+			})
 		}
 	}
 	// Now that the actual transactions have been selected, update the block weight for the real transaction count and
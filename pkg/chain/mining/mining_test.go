@@ -0,0 +1,209 @@
+package mining
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	_ "github.com/p9c/pod/pkg/db/ffldb"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// testTxSource is a TxSource holding a fixed set of transactions.
+type testTxSource struct {
+	descs []*TxDesc
+}
+
+func (s *testTxSource) LastUpdated() time.Time {
+	return time.Time{}
+}
+func (s *testTxSource) MiningDescs() []*TxDesc {
+	return s.descs
+}
+func (s *testTxSource) HaveTransaction(hash *chainhash.Hash) bool {
+	for _, desc := range s.descs {
+		if *desc.Tx.Hash() == *hash {
+			return true
+		}
+	}
+	return false
+}
+func (s *testTxSource) MaxSize() int64 {
+	return 0
+}
+func (s *testTxSource) MinFee() util.Amount {
+	return 0
+}
+
+// add adds the transaction paying the passed fee to the source.
+func (s *testTxSource) add(tx *wire.MsgTx, fee int64) {
+	utilTx := util.NewTx(tx)
+	s.descs = append(s.descs, &TxDesc{
+		Tx:       utilTx,
+		Added:    time.Now(),
+		Fee:      fee,
+		FeePerKB: fee * 1000 / int64(tx.SerializeSize()),
+	})
+}
+
+// newTestGenerator returns a block template generator on a new regression test chain, which selects transactions by
+// package fee and skips packages paying less than 1000 Satoshi/kB, along with the transaction source it draws from.
+// The chain has one more block than the coinbase maturity, so the coinbase of block 1 can be spent in the next block.
+// Every coinbase pays to an OP_TRUE script. The returned function removes the chain.
+func newTestGenerator(t *testing.T) (*BlkTmplGenerator, *testTxSource, func()) {
+	dir, err := ioutil.TempDir("", "miningtest")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	params := &netparams.RegressionTestParams
+	db, err := database.Create("ffldb", dir, params.Net)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unable to create db: %v", err)
+	}
+	teardown := func() {
+		db.Close()
+		os.RemoveAll(dir)
+	}
+	timeSource := blockchain.NewMedianTime()
+	sigCache := txscript.NewSigCache(1000)
+	chain, err := blockchain.New(&blockchain.Config{
+		DB:          db,
+		ChainParams: params,
+		TimeSource:  timeSource,
+		SigCache:    sigCache,
+	})
+	if err != nil {
+		teardown()
+		t.Fatalf("unable to create chain: %v", err)
+	}
+	policy := &Policy{
+		BlockMaxWeight: blockchain.MaxBlockWeight - 4000,
+		TxMinFreeFee:   1000,
+	}
+	txSource := &testTxSource{}
+	g := NewBlkTmplGenerator(policy, params, txSource, chain, timeSource, sigCache, txscript.NewHashCache(1000))
+	for i := 0; i <= int(params.CoinbaseMaturity); i++ {
+		mineTestBlock(t, g)
+	}
+	return g, txSource, teardown
+}
+
+// mineTestBlock generates a block template and adds it to the chain without solving it.
+func mineTestBlock(t *testing.T, g *BlkTmplGenerator) *wire.MsgBlock {
+	template, err := g.NewBlockTemplate(0, nil, "sha256d")
+	if err != nil {
+		t.Fatalf("NewBlockTemplate: unexpected error: %v", err)
+	}
+	block := util.NewBlock(template.Block)
+	block.SetHeight(template.Height)
+	if _, _, err := g.Chain.ProcessBlock(0, block, blockchain.BFNoPoWCheck, template.Height); err != nil {
+		t.Fatalf("ProcessBlock: unexpected error: %v", err)
+	}
+	return template.Block
+}
+
+// spendTestOutput returns a transaction spending the passed OP_TRUE output of the passed value to an OP_TRUE script,
+// paying fee.
+func spendTestOutput(prevOut wire.OutPoint, value, fee int64) *wire.MsgTx {
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&prevOut, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(value-fee, []byte{txscript.OP_TRUE}))
+	return tx
+}
+
+// coinbaseOutPoint returns the output of the coinbase of the block at the passed height of the main chain.
+func coinbaseOutPoint(t *testing.T, g *BlkTmplGenerator, height int32) (wire.OutPoint, int64) {
+	block, err := g.Chain.BlockByHeight(height)
+	if err != nil {
+		t.Fatalf("unable to fetch block %d: %v", height, err)
+	}
+	coinbase := block.Transactions()[0]
+	return wire.OutPoint{Hash: *coinbase.Hash()}, coinbase.MsgTx().TxOut[0].Value
+}
+
+// templateTxns returns the positions of the transactions in the template, excluding the coinbase.
+func templateTxns(block *wire.MsgBlock) map[chainhash.Hash]int {
+	txns := make(map[chainhash.Hash]int)
+	for i, tx := range block.Transactions[1:] {
+		txns[tx.TxHash()] = i
+	}
+	return txns
+}
+
+// TestNewBlockTemplateCPFP ensures a child paying a high fee pulls its parent, which pays less than the minimum fee
+// alone, into the block ahead of it, while a transaction paying the same low fee without such a child is left out.
+func TestNewBlockTemplateCPFP(t *testing.T) {
+	g, txSource, teardown := newTestGenerator(t)
+	defer teardown()
+	prevOut, value := coinbaseOutPoint(t, g, 1)
+	parent := spendTestOutput(prevOut, value, 0)
+	child := spendTestOutput(wire.OutPoint{Hash: parent.TxHash()}, parent.TxOut[0].Value, 100000)
+	prevOut, value = coinbaseOutPoint(t, g, 2)
+	single := spendTestOutput(prevOut, value, 0)
+	// The child is added ahead of its parent so the order of the source does not put the parent first.
+	txSource.add(child, 100000)
+	txSource.add(parent, 0)
+	txSource.add(single, 0)
+	template, err := g.NewBlockTemplate(0, nil, "sha256d")
+	if err != nil {
+		t.Fatalf("NewBlockTemplate: unexpected error: %v", err)
+	}
+	txns := templateTxns(template.Block)
+	parentPos, ok := txns[parent.TxHash()]
+	if !ok {
+		t.Fatalf("the parent of the high fee child is not in the block")
+	}
+	childPos, ok := txns[child.TxHash()]
+	if !ok {
+		t.Fatalf("the high fee child is not in the block")
+	}
+	if parentPos > childPos {
+		t.Errorf("the child is at %d in the block, before its parent at %d", childPos, parentPos)
+	}
+	if _, ok := txns[single.TxHash()]; ok {
+		t.Errorf("a transaction paying less than the minimum fee is in the block")
+	}
+	if fees := template.Fees[0]; fees != -100000 {
+		t.Errorf("the block collects %d in fees, want 100000", -fees)
+	}
+}
+
+// TestNewBlockTemplateSkipDescendants ensures that when a transaction can not be included in the block, neither can
+// the transactions depending on it, however high the fees they pay, while other transactions still are.
+func TestNewBlockTemplateSkipDescendants(t *testing.T) {
+	g, txSource, teardown := newTestGenerator(t)
+	defer teardown()
+	// The coinbase of the tip is immature, so the parent spending it is invalid.
+	tip := g.Chain.BestSnapshot().Height
+	prevOut, value := coinbaseOutPoint(t, g, tip)
+	parent := spendTestOutput(prevOut, value, 1000)
+	child := spendTestOutput(wire.OutPoint{Hash: parent.TxHash()}, parent.TxOut[0].Value, 100000)
+	grandchild := spendTestOutput(wire.OutPoint{Hash: child.TxHash()}, child.TxOut[0].Value, 100000)
+	prevOut, value = coinbaseOutPoint(t, g, 1)
+	other := spendTestOutput(prevOut, value, 10000)
+	txSource.add(parent, 1000)
+	txSource.add(child, 100000)
+	txSource.add(grandchild, 100000)
+	txSource.add(other, 10000)
+	template, err := g.NewBlockTemplate(0, nil, "sha256d")
+	if err != nil {
+		t.Fatalf("NewBlockTemplate: unexpected error: %v", err)
+	}
+	txns := templateTxns(template.Block)
+	for name, tx := range map[string]*wire.MsgTx{"parent": parent, "child": child, "grandchild": grandchild} {
+		if _, ok := txns[tx.TxHash()]; ok {
+			t.Errorf("the %s of the invalid package is in the block", name)
+		}
+	}
+	if _, ok := txns[other.TxHash()]; !ok || len(txns) != 1 {
+		t.Errorf("the block holds %d transactions, want only the one independent of the invalid package", len(txns))
+	}
+}
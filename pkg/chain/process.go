@@ -172,6 +172,7 @@ func (b *BlockChain) ProcessBlock(workerNumber uint32, block *util.Block,
 		Error(err)
 		return false, false, err
 	}
+	b.checkAssumeValid()
 	Tracef("accepted block %d %v %s",
 		blockHeight, bhwa(blockHeight).String(), fork.GetAlgoName(block.MsgBlock().
 			Header.Version, blockHeight))
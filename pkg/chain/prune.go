@@ -0,0 +1,54 @@
+package blockchain
+
+const (
+	// MinPruneTarget is the smallest size in bytes the block files may be pruned to, which leaves room for more than
+	// PruneDepth blocks of the largest size allowed.
+	MinPruneTarget = 550 * 1024 * 1024
+	// PruneDepth is how many blocks below the best block are always kept when pruning, so that a reorganization of the
+	// chain that deep can still be made and peers that are nearly caught up can fetch the blocks they lack.
+	PruneDepth = 288
+)
+
+// pruneBlocks deletes the oldest stored blocks if pruning is enabled and the block files are larger than the prune
+// target, keeping the blocks within PruneDepth of the given new best block. The spend journal is not touched, so the
+// kept blocks can still be disconnected. Errors are only logged as the block that was connected is not affected by
+// them. This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) pruneBlocks(node *BlockNode) {
+	if b.pruneTarget == 0 || node.height <= PruneDepth {
+		return
+	}
+	keep := node.Ancestor(node.height - PruneDepth)
+	pruned, err := b.db.PruneBlocks(b.pruneTarget, &keep.hash)
+	if err != nil {
+		Error("failed to prune blocks:", err)
+		return
+	}
+	if len(pruned) > 0 {
+		Debugf("pruned %d blocks, keeping blocks from height %d", len(pruned), keep.height)
+	}
+}
+
+// IsPruned returns whether pruning is enabled, blocks were deleted from the database by pruning before or the chain
+// state was loaded from a utxo set snapshot whose blocks below it are not all validated yet, in which case blocks in
+// the main chain may not be available to fetch.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) IsPruned() bool {
+	if b.pruneTarget > 0 {
+		return true
+	}
+	pruned, err := b.db.BeenPruned()
+	if err != nil {
+		Error(err)
+		return false
+	}
+	if pruned {
+		return true
+	}
+	base, err := SnapshotBase(b.db)
+	if err != nil {
+		Error(err)
+		return false
+	}
+	return base != nil
+}
@@ -0,0 +1,100 @@
+package netsync
+
+import (
+	"errors"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/wire"
+	peerpkg "github.com/p9c/pod/pkg/comm/peer"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// partialBlock is a block being rebuilt from a cmpctblock message. The transactions that were neither sent in full nor
+// found in the memory pool are missing and have to be fetched from the peer with a getblocktxn message.
+type partialBlock struct {
+	peer    *peerpkg.Peer
+	header  wire.BlockHeader
+	txns    []*wire.MsgTx
+	missing []uint32
+}
+
+// newPartialBlock places the transactions sent in full within the passed compact block and looks up the rest in the
+// passed memory pool transactions by their short IDs. A transaction whose short ID matches more than one memory pool
+// transaction is left missing. An error is returned if the compact block lists a short ID twice, in which case it
+// can't be rebuilt.
+func newPartialBlock(peer *peerpkg.Peer, msg *wire.MsgCmpctBlock, pool []*util.Tx) (*partialBlock, error) {
+	pb := &partialBlock{
+		peer:   peer,
+		header: msg.Header,
+		txns:   make([]*wire.MsgTx, msg.TxCount()),
+	}
+	for _, ptx := range msg.PrefilledTxns {
+		pb.txns[ptx.Index] = ptx.Tx
+	}
+	// The short IDs are for the transactions that were not prefilled, in the order they appear in the block.
+	slots := make(map[uint64]int, len(msg.ShortIDs))
+	var next int
+	for _, id := range msg.ShortIDs {
+		for pb.txns[next] != nil {
+			next++
+		}
+		if _, ok := slots[id]; ok {
+			return nil, errors.New("duplicate short transaction id")
+		}
+		slots[id] = next
+		next++
+	}
+	key := msg.ShortIDKey()
+	collided := make(map[int]struct{})
+	for _, tx := range pool {
+		slot, ok := slots[wire.ShortTxID(&key, tx.Hash())]
+		if !ok {
+			continue
+		}
+		if pb.txns[slot] != nil {
+			collided[slot] = struct{}{}
+			continue
+		}
+		pb.txns[slot] = tx.MsgTx()
+	}
+	for slot := range collided {
+		pb.txns[slot] = nil
+	}
+	for i, tx := range pb.txns {
+		if tx == nil {
+			pb.missing = append(pb.missing, uint32(i))
+		}
+	}
+	return pb, nil
+}
+
+// fill places the transactions of a blocktxn message, which must be the missing transactions in the order they were
+// requested.
+func (pb *partialBlock) fill(txns []*wire.MsgTx) error {
+	if len(txns) != len(pb.missing) {
+		return errors.New("wrong number of transactions to complete the block")
+	}
+	for i, index := range pb.missing {
+		pb.txns[index] = txns[i]
+	}
+	pb.missing = nil
+	return nil
+}
+
+// block returns the rebuilt block, or false if its transactions don't match the merkle root of the header, which can
+// happen when a memory pool transaction has the short ID of a different transaction of the block.
+func (pb *partialBlock) block() (*util.Block, bool) {
+	msgBlock := wire.NewMsgBlock(&pb.header)
+	for _, tx := range pb.txns {
+		if err := msgBlock.AddTransaction(tx); err != nil {
+			Error(err)
+			return nil, false
+		}
+	}
+	block := util.NewBlock(msgBlock)
+	merkles := blockchain.BuildMerkleTreeStore(block.Transactions(), false)
+	if !merkles[len(merkles)-1].IsEqual(&pb.header.MerkleRoot) {
+		return nil, false
+	}
+	return block, true
+}
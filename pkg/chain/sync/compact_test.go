@@ -0,0 +1,74 @@
+package netsync
+
+import (
+	"testing"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// testCompactBlock returns a block of n distinct transactions with a valid merkle root, and its compact block.
+func testCompactBlock(n int) (*util.Block, *wire.MsgCmpctBlock) {
+	msgBlock := wire.NewMsgBlock(&wire.BlockHeader{})
+	for i := 0; i < n; i++ {
+		tx := wire.NewMsgTx(1)
+		tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: uint32(i)}, nil, nil))
+		tx.AddTxOut(wire.NewTxOut(int64(i), nil))
+		_ = msgBlock.AddTransaction(tx)
+	}
+	block := util.NewBlock(msgBlock)
+	merkles := blockchain.BuildMerkleTreeStore(block.Transactions(), false)
+	msgBlock.Header.MerkleRoot = *merkles[len(merkles)-1]
+	block = util.NewBlock(msgBlock)
+	return block, wire.NewMsgCmpctBlock(msgBlock, 42)
+}
+
+// TestPartialBlock ensures a block is rebuilt from its compact block and the memory pool, that the transactions not in
+// the memory pool are reported missing, and that a block completed with the wrong transactions is refused.
+func TestPartialBlock(t *testing.T) {
+	block, msg := testCompactBlock(4)
+	txns := block.Transactions()
+	// Everything but the coinbase is found in the memory pool.
+	pb, err := newPartialBlock(nil, msg, txns[1:])
+	if err != nil {
+		t.Fatalf("newPartialBlock: unexpected err %v", err)
+	}
+	if len(pb.missing) != 0 {
+		t.Fatalf("newPartialBlock: %d transactions missing, want none", len(pb.missing))
+	}
+	rebuilt, ok := pb.block()
+	if !ok || *rebuilt.Hash() != *block.Hash() {
+		t.Fatalf("block: rebuilt block does not match the original")
+	}
+	// Transactions that are not in the memory pool have to be fetched.
+	pb, err = newPartialBlock(nil, msg, []*util.Tx{txns[2]})
+	if err != nil {
+		t.Fatalf("newPartialBlock: unexpected err %v", err)
+	}
+	if len(pb.missing) != 2 || pb.missing[0] != 1 || pb.missing[1] != 3 {
+		t.Fatalf("newPartialBlock: got missing transactions %v, want [1 3]", pb.missing)
+	}
+	if err = pb.fill([]*wire.MsgTx{txns[1].MsgTx()}); err == nil {
+		t.Fatalf("fill: accepted the wrong number of transactions")
+	}
+	if err = pb.fill([]*wire.MsgTx{txns[1].MsgTx(), txns[3].MsgTx()}); err != nil {
+		t.Fatalf("fill: unexpected err %v", err)
+	}
+	if rebuilt, ok = pb.block(); !ok || *rebuilt.Hash() != *block.Hash() {
+		t.Fatalf("block: block completed from blocktxn does not match the original")
+	}
+	// A block completed with a different transaction does not match its merkle root.
+	pb, _ = newPartialBlock(nil, msg, txns[1:3])
+	if err = pb.fill([]*wire.MsgTx{txns[1].MsgTx()}); err != nil {
+		t.Fatalf("fill: unexpected err %v", err)
+	}
+	if _, ok = pb.block(); ok {
+		t.Errorf("block: accepted a block with the wrong transactions")
+	}
+	// Short IDs listed twice can't be told apart.
+	msg.ShortIDs[1] = msg.ShortIDs[0]
+	if _, err = newPartialBlock(nil, msg, txns); err == nil {
+		t.Errorf("newPartialBlock: accepted duplicate short IDs")
+	}
+}
@@ -0,0 +1,206 @@
+package netsync
+
+import (
+	"time"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/wire"
+	peerpkg "github.com/p9c/pod/pkg/comm/peer"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// downloadWindowSize is the maximum number of blocks past the next block to be processed that are requested or held
+	// waiting for the blocks before them at once.
+	downloadWindowSize = 1024
+	// maxBlocksPerPeer is the maximum number of blocks of the download window that are requested from one peer at once.
+	maxBlocksPerPeer = 16
+	// blockDownloadTimeout is how long a peer has to deliver a block of the download window before the request is
+	// given to another peer and the peer is dropped for stalling the download.
+	blockDownloadTimeout = time.Second * 30
+	// stallCheckInterval is how often requests of the download window are checked for timeouts.
+	stallCheckInterval = time.Second * 5
+)
+
+// windowBlock is a block of the download window. Until it is requested peer is nil, and until it arrives block is nil.
+type windowBlock struct {
+	hash      chainhash.Hash
+	height    int32
+	peer      *peerpkg.Peer
+	requested time.Time
+	block     *util.Block
+	from      *peerpkg.Peer
+}
+
+// downloadWindow fetches a sequence of blocks in chain order from several peers at once. Blocks are requested from the
+// peers with the fewest outstanding requests and are handed back in order once all the blocks before them have
+// arrived, so they can be processed without becoming orphans. It is only accessed from the blockHandler thread.
+type downloadWindow struct {
+	size     int
+	perPeer  int
+	timeout  time.Duration
+	blocks   []*windowBlock
+	index    map[chainhash.Hash]*windowBlock
+	inFlight map[*peerpkg.Peer]int
+}
+
+// newDownloadWindow returns an empty download window that requests up to size blocks past the next one to be processed,
+// at most perPeer of them from each peer, and times requests out after timeout.
+func newDownloadWindow(size, perPeer int, timeout time.Duration) *downloadWindow {
+	return &downloadWindow{
+		size:     size,
+		perPeer:  perPeer,
+		timeout:  timeout,
+		index:    make(map[chainhash.Hash]*windowBlock),
+		inFlight: make(map[*peerpkg.Peer]int),
+	}
+}
+
+// add appends a block to the end of the sequence to be downloaded. The height is used to only request the block from
+// peers that claim to have it, zero if it is not known. It returns false if the block is already in the window.
+func (w *downloadWindow) add(hash *chainhash.Hash, height int32) bool {
+	if _, ok := w.index[*hash]; ok {
+		return false
+	}
+	wb := &windowBlock{hash: *hash, height: height}
+	w.blocks = append(w.blocks, wb)
+	w.index[*hash] = wb
+	return true
+}
+
+// has returns whether the block is waiting to be downloaded or processed.
+func (w *downloadWindow) has(hash *chainhash.Hash) bool {
+	_, ok := w.index[*hash]
+	return ok
+}
+
+// len returns the number of blocks waiting to be downloaded or processed.
+func (w *downloadWindow) len() int {
+	return len(w.blocks)
+}
+
+// fetch requests the blocks within the window that are not yet requested from the passed peers, sending each peer one
+// getdata message. Blocks go to the eligible peer with the fewest requests outstanding so they are spread evenly.
+func (w *downloadWindow) fetch(peers []*peerpkg.Peer, now time.Time) {
+	if len(peers) == 0 {
+		return
+	}
+	requests := make(map[*peerpkg.Peer]*wire.MsgGetData)
+	end := len(w.blocks)
+	if end > w.size {
+		end = w.size
+	}
+	for _, wb := range w.blocks[:end] {
+		if wb.peer != nil || wb.block != nil {
+			continue
+		}
+		var best *peerpkg.Peer
+		for _, p := range peers {
+			if w.inFlight[p] >= w.perPeer || (wb.height > 0 && p.LastBlock() < wb.height) {
+				continue
+			}
+			if best == nil || w.inFlight[p] < w.inFlight[best] {
+				best = p
+			}
+		}
+		if best == nil {
+			continue
+		}
+		gdmsg, ok := requests[best]
+		if !ok {
+			gdmsg = wire.NewMsgGetData()
+			requests[best] = gdmsg
+		}
+		iv := wire.NewInvVect(wire.InvTypeBlock, &wb.hash)
+		if best.IsWitnessEnabled() {
+			iv.Type = wire.InvTypeWitnessBlock
+		}
+		if err := gdmsg.AddInvVect(iv); err != nil {
+			Error(err)
+			continue
+		}
+		wb.peer = best
+		wb.requested = now
+		w.inFlight[best]++
+	}
+	for p, gdmsg := range requests {
+		p.QueueMessage(gdmsg, nil)
+	}
+}
+
+// received stores a block that arrived from a peer. It returns false if the block is not part of the window, in which
+// case it is not handled by it. Blocks are accepted from any peer, as a request that timed out may still be answered.
+func (w *downloadWindow) received(peer *peerpkg.Peer, block *util.Block) bool {
+	wb, ok := w.index[*block.Hash()]
+	if !ok {
+		return false
+	}
+	if wb.block != nil {
+		return true
+	}
+	w.unassign(wb)
+	wb.block = block
+	wb.from = peer
+	return true
+}
+
+// ready removes and returns the blocks at the start of the window that have arrived, in chain order.
+func (w *downloadWindow) ready() (blocks []*windowBlock) {
+	var n int
+	for n < len(w.blocks) && w.blocks[n].block != nil {
+		blocks = append(blocks, w.blocks[n])
+		delete(w.index, w.blocks[n].hash)
+		w.blocks[n] = nil
+		n++
+	}
+	w.blocks = w.blocks[n:]
+	return
+}
+
+// timedOut returns the peers that did not deliver a block within the timeout of requesting it. Their outstanding
+// requests are taken back so that the blocks are requested from other peers by the next fetch.
+func (w *downloadWindow) timedOut(now time.Time) (stalled []*peerpkg.Peer) {
+	seen := make(map[*peerpkg.Peer]struct{})
+	for _, wb := range w.blocks {
+		if wb.peer == nil || wb.block != nil || now.Sub(wb.requested) < w.timeout {
+			continue
+		}
+		if _, ok := seen[wb.peer]; !ok {
+			seen[wb.peer] = struct{}{}
+			stalled = append(stalled, wb.peer)
+		}
+	}
+	for _, p := range stalled {
+		w.removePeer(p)
+	}
+	return
+}
+
+// removePeer takes back the outstanding requests made to a peer so the blocks are requested from other peers by the
+// next fetch.
+func (w *downloadWindow) removePeer(peer *peerpkg.Peer) {
+	for _, wb := range w.blocks {
+		if wb.peer == peer {
+			w.unassign(wb)
+		}
+	}
+	delete(w.inFlight, peer)
+}
+
+// reset forgets every block of the window, including those that arrived but were not yet processed.
+func (w *downloadWindow) reset() {
+	w.blocks = nil
+	w.index = make(map[chainhash.Hash]*windowBlock)
+	w.inFlight = make(map[*peerpkg.Peer]int)
+}
+
+// unassign marks a block as not requested from any peer.
+func (w *downloadWindow) unassign(wb *windowBlock) {
+	if wb.peer == nil {
+		return
+	}
+	if w.inFlight[wb.peer]--; w.inFlight[wb.peer] <= 0 {
+		delete(w.inFlight, wb.peer)
+	}
+	wb.peer = nil
+}
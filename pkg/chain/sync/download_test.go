@@ -0,0 +1,270 @@
+package netsync
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/wire"
+	peerpkg "github.com/p9c/pod/pkg/comm/peer"
+	"github.com/p9c/pod/pkg/util"
+	qu "github.com/p9c/pod/pkg/util/quit"
+)
+
+// conn mocks a network connection by implementing the net.Conn interface so peers can be connected without opening a
+// network connection.
+type conn struct {
+	io.Reader
+	io.Writer
+	io.Closer
+	laddr, raddr string
+}
+
+func (c conn) LocalAddr() net.Addr                { return &addr{"tcp", c.laddr} }
+func (c conn) RemoteAddr() net.Addr               { return &addr{"tcp", c.raddr} }
+func (c conn) SetDeadline(t time.Time) error      { return nil }
+func (c conn) SetReadDeadline(t time.Time) error  { return nil }
+func (c conn) SetWriteDeadline(t time.Time) error { return nil }
+
+// Close handles closing the connection.
+func (c conn) Close() error {
+	if c.Closer == nil {
+		return nil
+	}
+	return c.Closer.Close()
+}
+
+// addr mocks a network address
+type addr struct {
+	net, address string
+}
+
+func (m addr) Network() string { return m.net }
+func (m addr) String() string  { return m.address }
+
+// pipe turns two mock connections into a full-duplex connection similar to net.Pipe.
+func pipe(c1, c2 *conn) (*conn, *conn) {
+	r1, w1 := io.Pipe()
+	r2, w2 := io.Pipe()
+	c1.Writer = w1
+	c1.Closer = w1
+	c2.Reader = r1
+	c1.Reader = r2
+	c2.Writer = w2
+	c2.Closer = w2
+	return c1, c2
+}
+
+func init() {
+	// Both ends of the test connections are in this process, so the nonce of the version message sent by one end is
+	// known to the other, which would take it for a connection to itself.
+	peerpkg.AllowSelfConns = true
+}
+
+// newTestPeer returns a peer at the given height that is connected through an in-memory pipe to a remote peer, which
+// passes the getdata messages it receives to getData.
+func newTestPeer(t *testing.T, address string, height int32, getData chan<- *wire.MsgGetData) *peerpkg.Peer {
+	verack := qu.Ts(2)
+	remoteCfg := &peerpkg.Config{
+		Listeners: peerpkg.MessageListeners{
+			OnGetData: func(p *peerpkg.Peer, msg *wire.MsgGetData) {
+				getData <- msg
+			},
+			OnVerAck: func(p *peerpkg.Peer, msg *wire.MsgVerAck) {
+				verack <- struct{}{}
+			},
+		},
+		UserAgentName:    "peer",
+		UserAgentVersion: "1.0",
+		ChainParams:      &netparams.MainNetParams,
+		Services:         wire.SFNodeNetwork,
+		TrickleInterval:  time.Second * 10,
+	}
+	localCfg := *remoteCfg
+	localCfg.Listeners = peerpkg.MessageListeners{
+		OnVerAck: func(p *peerpkg.Peer, msg *wire.MsgVerAck) {
+			verack <- struct{}{}
+		},
+	}
+	inConn, outConn := pipe(
+		&conn{laddr: address, raddr: "10.0.0.1:11047"},
+		&conn{laddr: "10.0.0.1:11047", raddr: address},
+	)
+	remote := peerpkg.NewInboundPeer(remoteCfg)
+	remote.AssociateConnection(inConn)
+	local, err := peerpkg.NewOutboundPeer(&localCfg, address)
+	if err != nil {
+		t.Fatalf("NewOutboundPeer: unexpected err %v", err)
+	}
+	local.AssociateConnection(outConn)
+	for i := 0; i < 2; i++ {
+		select {
+		case <-verack:
+		case <-time.After(time.Second):
+			t.Fatalf("verack timeout")
+		}
+	}
+	local.UpdateLastBlockHeight(height)
+	return local
+}
+
+// testBlocks returns n distinct blocks, which only need distinct hashes as the download window does not validate them.
+func testBlocks(n int) []*util.Block {
+	blocks := make([]*util.Block, n)
+	for i := range blocks {
+		blocks[i] = util.NewBlock(wire.NewMsgBlock(&wire.BlockHeader{
+			Timestamp: time.Unix(1231006505, 0),
+			Nonce:     uint32(i),
+		}))
+	}
+	return blocks
+}
+
+// receiveGetData returns the hashes of the getdata message a remote peer receives next.
+func receiveGetData(t *testing.T, getData <-chan *wire.MsgGetData) (hashes []chainhash.Hash) {
+	select {
+	case msg := <-getData:
+		for _, iv := range msg.InvList {
+			hashes = append(hashes, iv.Hash)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("getdata timeout")
+	}
+	return
+}
+
+// TestDownloadWindowFetch ensures the blocks of the window are spread over the peers without exceeding the per peer
+// limit, and that only peers at the height of a block are asked for it.
+func TestDownloadWindowFetch(t *testing.T) {
+	const perPeer = 4
+	var getData [3]chan *wire.MsgGetData
+	var peers []*peerpkg.Peer
+	for i := range getData {
+		getData[i] = make(chan *wire.MsgGetData, 1)
+		peers = append(peers, newTestPeer(t, fmt.Sprintf("10.0.1.%d:11047", i+1), 100, getData[i]))
+	}
+	defer func() {
+		for _, p := range peers {
+			p.Disconnect()
+		}
+	}()
+	blocks := testBlocks(16)
+	w := newDownloadWindow(len(blocks), perPeer, time.Minute)
+	for i, block := range blocks {
+		if !w.add(block.Hash(), int32(i+1)) {
+			t.Fatalf("add: block %d not added", i)
+		}
+	}
+	if w.add(blocks[0].Hash(), 1) {
+		t.Fatalf("add: duplicate block added")
+	}
+	w.fetch(peers, time.Now())
+	requested := make(map[chainhash.Hash]int)
+	for i := range getData {
+		hashes := receiveGetData(t, getData[i])
+		if len(hashes) != perPeer {
+			t.Errorf("peer %d asked for %d blocks, want %d", i, len(hashes), perPeer)
+		}
+		for _, hash := range hashes {
+			requested[hash]++
+		}
+	}
+	for i, block := range blocks {
+		n := requested[*block.Hash()]
+		switch {
+		case i < len(peers)*perPeer && n != 1:
+			t.Errorf("block %d requested %d times, want once", i, n)
+		case i >= len(peers)*perPeer && n != 0:
+			t.Errorf("block %d requested although every peer is busy", i)
+		}
+	}
+	// A block above the height of every peer must not be requested.
+	w = newDownloadWindow(1, perPeer, time.Minute)
+	w.add(blocks[0].Hash(), 101)
+	w.fetch(peers, time.Now())
+	if w.blocks[0].peer != nil {
+		t.Errorf("block above the height of every peer was requested")
+	}
+}
+
+// TestDownloadWindowOrder ensures blocks are handed back in chain order regardless of the order they arrive in.
+func TestDownloadWindowOrder(t *testing.T) {
+	blocks := testBlocks(4)
+	w := newDownloadWindow(len(blocks), maxBlocksPerPeer, time.Minute)
+	for _, block := range blocks {
+		w.add(block.Hash(), 0)
+	}
+	if w.received(nil, testBlocks(5)[4]) {
+		t.Fatalf("received: block that is not in the window accepted")
+	}
+	for _, i := range []int{2, 1} {
+		w.received(nil, blocks[i])
+		if ready := w.ready(); len(ready) != 0 {
+			t.Fatalf("ready: got %d blocks before the first block arrived", len(ready))
+		}
+	}
+	w.received(nil, blocks[0])
+	ready := w.ready()
+	if len(ready) != 3 {
+		t.Fatalf("ready: got %d blocks, want 3", len(ready))
+	}
+	for i, wb := range ready {
+		if wb.block != blocks[i] {
+			t.Errorf("ready: block %d out of order", i)
+		}
+	}
+	if w.len() != 1 || w.has(blocks[0].Hash()) || !w.has(blocks[3].Hash()) {
+		t.Errorf("window holds the wrong blocks after handing back the ready ones")
+	}
+}
+
+// TestDownloadWindowTimeout ensures a peer that does not deliver its blocks in time is reported as stalling and that
+// its blocks are then requested from the other peers.
+func TestDownloadWindowTimeout(t *testing.T) {
+	slowData := make(chan *wire.MsgGetData, 1)
+	fastData := make(chan *wire.MsgGetData, 2)
+	slow := newTestPeer(t, "10.0.2.1:11047", 100, slowData)
+	fast := newTestPeer(t, "10.0.2.2:11047", 100, fastData)
+	defer slow.Disconnect()
+	defer fast.Disconnect()
+	blocks := testBlocks(4)
+	w := newDownloadWindow(len(blocks), 2, time.Minute)
+	for _, block := range blocks {
+		w.add(block.Hash(), 0)
+	}
+	now := time.Now()
+	w.fetch([]*peerpkg.Peer{slow, fast}, now)
+	slowHashes := receiveGetData(t, slowData)
+	fastHashes := receiveGetData(t, fastData)
+	if len(slowHashes) != 2 || len(fastHashes) != 2 {
+		t.Fatalf("got %d and %d blocks requested, want 2 from each peer", len(slowHashes), len(fastHashes))
+	}
+	for _, block := range blocks {
+		for _, hash := range fastHashes {
+			if hash == *block.Hash() {
+				w.received(fast, block)
+			}
+		}
+	}
+	if stalled := w.timedOut(now.Add(time.Second)); len(stalled) != 0 {
+		t.Fatalf("timedOut: %d peers stalled before the timeout", len(stalled))
+	}
+	stalled := w.timedOut(now.Add(time.Minute))
+	if len(stalled) != 1 || stalled[0] != slow {
+		t.Fatalf("timedOut: got %v, want only the slow peer", stalled)
+	}
+	w.fetch([]*peerpkg.Peer{fast}, now.Add(time.Minute))
+	retried := receiveGetData(t, fastData)
+	if len(retried) != len(slowHashes) {
+		t.Fatalf("got %d blocks requested again, want %d", len(retried), len(slowHashes))
+	}
+	for i := range retried {
+		if retried[i] != slowHashes[i] {
+			t.Errorf("block %v requested again instead of %v", retried[i], slowHashes[i])
+		}
+	}
+}
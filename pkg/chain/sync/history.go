@@ -0,0 +1,63 @@
+package netsync
+
+import (
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	peerpkg "github.com/p9c/pod/pkg/comm/peer"
+)
+
+// historyWindowSize is the maximum number of blocks below the utxo set snapshot the chain state was loaded from that
+// are requested or held waiting for the blocks before them at once.
+const historyWindowSize = 128
+
+// fetchHistoryBlocks adds the next blocks below the utxo set snapshot the chain state was loaded from to the history
+// window and requests them from the sync candidates. The blocks are only fetched once the chain is current, so their
+// validation does not hold up the download of new blocks.
+func (sm *SyncManager) fetchHistoryBlocks() {
+	if !sm.current() {
+		return
+	}
+	next, base := sm.chain.SnapshotHistory()
+	if next == 0 {
+		return
+	}
+	if sm.historyHeight < next-1 {
+		sm.historyHeight = next - 1
+	}
+	// The snapshot block itself was stored when the snapshot was loaded.
+	for sm.history.len() < historyWindowSize && sm.historyHeight < base-1 {
+		hash, err := sm.chain.BlockHashByHeight(sm.historyHeight + 1)
+		if err != nil {
+			Error(err)
+			return
+		}
+		sm.history.add(hash, sm.historyHeight+1)
+		sm.historyHeight++
+	}
+	peers := make([]*peerpkg.Peer, 0, len(sm.peerStates))
+	for peer, state := range sm.peerStates {
+		if state.syncCandidate {
+			peers = append(peers, peer)
+		}
+	}
+	sm.history.fetch(peers, time.Now())
+}
+
+// processHistoryBlocks passes the blocks of the history window that arrived to the chain in order. A block the chain
+// rejects is requested again from another peer, and the peer that sent it is disconnected.
+func (sm *SyncManager) processHistoryBlocks() {
+	for _, wb := range sm.history.ready() {
+		if err := sm.chain.ProcessHistoryBlock(wb.block); err != nil {
+			Errorf("failed to process block %v below the utxo set snapshot from %s: %v", wb.hash, wb.from, err)
+			if _, ok := err.(blockchain.RuleError); ok {
+				wb.from.Disconnect()
+			}
+			// The blocks after it can only be processed once it is, so they are fetched again with it.
+			sm.history.reset()
+			sm.historyHeight = 0
+			break
+		}
+	}
+	sm.fetchHistoryBlocks()
+}
@@ -42,6 +42,11 @@ type (
 		requestedBlocks map[chainhash.Hash]struct{}
 		syncPeer        *peerpkg.Peer
 		peerStates      map[*peerpkg.Peer]*peerSyncState
+		window          *downloadWindow
+		compactBlocks   map[chainhash.Hash]*partialBlock
+		// history fetches the blocks below the utxo set snapshot the chain state was loaded from, up to historyHeight.
+		history       *downloadWindow
+		historyHeight int32
 		// The following fields are used for headers-first mode.
 		headersFirstMode bool
 		headerList       *list.List
@@ -57,6 +62,20 @@ type (
 		peer  *peerpkg.Peer
 		reply qu.C
 	}
+	// blockTxnMsg packages a bitcoin blocktxn message and the peer it came from together so the block handler has access
+	// to that information.
+	blockTxnMsg struct {
+		blockTxn *wire.MsgBlockTxn
+		peer     *peerpkg.Peer
+		reply    qu.C
+	}
+	// cmpctBlockMsg packages a bitcoin cmpctblock message and the peer it came from together so the block handler has
+	// access to that information.
+	cmpctBlockMsg struct {
+		cmpctBlock *wire.MsgCmpctBlock
+		peer       *peerpkg.Peer
+		reply      qu.C
+	}
 	// donePeerMsg signifies a newly disconnected peer to the block handler.
 	donePeerMsg struct {
 		peer *peerpkg.Peer
@@ -127,9 +146,6 @@ type (
 )
 
 const (
-	// minInFlightBlocks is the minimum number of blocks that should be in the request queue for headers-first mode
-	// before requesting more.
-	minInFlightBlocks = 10
 	// maxRejectedTxns is the maximum number of rejected transactions hashes to store in memory.
 	maxRejectedTxns = 1000
 	// maxRequestedBlocks is the maximum number of requested block hashes to store in memory.
@@ -199,6 +215,28 @@ func (sm *SyncManager) QueueBlock(block *util.Block, peer *peerpkg.Peer, done qu
 	sm.msgChan <- &blockMsg{block: block, peer: peer, reply: done}
 }
 
+// QueueBlockTxn adds the passed blocktxn message and peer to the block handling queue. Responds to the done channel
+// argument after the message is processed, which includes processing the block it completes.
+func (sm *SyncManager) QueueBlockTxn(blockTxn *wire.MsgBlockTxn, peer *peerpkg.Peer, done qu.C) {
+	// Don't accept more blocks if we're shutting down.
+	if atomic.LoadInt32(&sm.shutdown) != 0 {
+		done <- struct{}{}
+		return
+	}
+	sm.msgChan <- &blockTxnMsg{blockTxn: blockTxn, peer: peer, reply: done}
+}
+
+// QueueCmpctBlock adds the passed cmpctblock message and peer to the block handling queue. Responds to the done
+// channel argument after the message is processed, which includes processing the block if it could be rebuilt.
+func (sm *SyncManager) QueueCmpctBlock(cmpctBlock *wire.MsgCmpctBlock, peer *peerpkg.Peer, done qu.C) {
+	// Don't accept more blocks if we're shutting down.
+	if atomic.LoadInt32(&sm.shutdown) != 0 {
+		done <- struct{}{}
+		return
+	}
+	sm.msgChan <- &cmpctBlockMsg{cmpctBlock: cmpctBlock, peer: peer, reply: done}
+}
+
 // QueueHeaders adds the passed headers message and peer to the block handling queue.
 func (sm *SyncManager) QueueHeaders(headers *wire.MsgHeaders, peer *peerpkg.Peer) {
 	// No channel handling here because peers do not need to block on headers messages.
@@ -263,9 +301,13 @@ func (sm *SyncManager) SyncPeerID() int32 {
 // thread without needing to lock memory data structures. This is important because the sync manager controls which
 // blocks are needed and how the fetching should proceed.
 func (sm *SyncManager) blockHandler(workerNumber uint32) {
+	stallTicker := time.NewTicker(stallCheckInterval)
+	defer stallTicker.Stop()
 out:
 	for {
 		select {
+		case <-stallTicker.C:
+			sm.handleStallSample()
 		case m := <-sm.msgChan:
 			switch msg := m.(type) {
 			case *newPeerMsg:
@@ -276,6 +318,12 @@ out:
 			case *blockMsg:
 				sm.handleBlockMsg(0, msg)
 				msg.reply <- struct{}{}
+			case *cmpctBlockMsg:
+				sm.handleCmpctBlockMsg(0, msg)
+				msg.reply <- struct{}{}
+			case *blockTxnMsg:
+				sm.handleBlockTxnMsg(0, msg)
+				msg.reply <- struct{}{}
 			case *invMsg:
 				sm.handleInvMsg(msg)
 			case *headersMsg:
@@ -349,18 +397,14 @@ func (sm *SyncManager) current() bool {
 	return true
 }
 
-// fetchHeaderBlocks creates and sends a request to the syncPeer for the next list of blocks to be downloaded based on
-// the current list of headers.
+// fetchHeaderBlocks adds the blocks of the current list of headers that are not yet known to the download window and
+// requests them from the sync candidates.
 func (sm *SyncManager) fetchHeaderBlocks() {
 	// Nothing to do if there is no start header.
 	if sm.startHeader == nil {
 		Warn("fetchHeaderBlocks called with no start header")
 		return
 	}
-	// Build up a getdata request for the list of blocks the headers describe. The size hint will be limited to
-	// wire.MaxInvPerMsg by the function, so no need to double check it here.
-	gdmsg := wire.NewMsgGetDataSizeHint(uint(sm.headerList.Len()))
-	numRequested := 0
 	for e := sm.startHeader; e != nil; e = e.Next() {
 		node, ok := e.Value.(*headerNode)
 		if !ok {
@@ -377,29 +421,26 @@ func (sm *SyncManager) fetchHeaderBlocks() {
 			)
 		}
 		if !haveInv {
-			syncPeerState := sm.peerStates[sm.syncPeer]
-			sm.requestedBlocks[*node.hash] = struct{}{}
-			syncPeerState.requestedBlocks[*node.hash] = struct{}{}
-			// If we're fetching from a witness enabled peer post-fork, then ensure that we receive all the witness data
-			// in the blocks.
-			if sm.syncPeer.IsWitnessEnabled() {
-				iv.Type = wire.InvTypeWitnessBlock
-			}
-			err := gdmsg.AddInvVect(iv)
-			if err != nil {
-				Error(err)
-				Debug(err)
-			}
-			numRequested++
-		}
-		sm.startHeader = e.Next()
-		if numRequested >= wire.MaxInvPerMsg {
-			break
+			sm.window.add(node.hash, node.height)
 		}
 	}
-	if len(gdmsg.InvList) > 0 {
-		sm.syncPeer.QueueMessage(gdmsg, nil)
+	sm.startHeader = nil
+	sm.fillWindow()
+}
+
+// fillWindow requests the blocks of the download window that are not yet requested from the peers that can be synced
+// from.
+func (sm *SyncManager) fillWindow() {
+	if sm.window.len() == 0 {
+		return
 	}
+	peers := make([]*peerpkg.Peer, 0, len(sm.peerStates))
+	for peer, state := range sm.peerStates {
+		if state.syncCandidate || peer == sm.syncPeer {
+			peers = append(peers, peer)
+		}
+	}
+	sm.window.fetch(peers, time.Now())
 }
 
 // findNextHeaderCheckpoint returns the next checkpoint after the passed height. It returns nil when there is not one
@@ -436,6 +477,30 @@ func (sm *SyncManager) handleBlockMsg(workerNumber uint32, bmsg *blockMsg) {
 		)
 		return
 	}
+	// Blocks of the download window are processed in chain order once all the blocks before them have arrived.
+	if sm.window.received(pp, bmsg.block) {
+		blocks := sm.window.ready()
+		for _, wb := range blocks {
+			sm.processBlock(workerNumber, wb.block, wb.from)
+		}
+		// The sync peer only announces more blocks once it is asked for the last block it announced, which may have
+		// been fetched from another peer, so ask it for more when the window runs empty while still syncing.
+		if len(blocks) > 0 && sm.window.len() == 0 && !sm.headersFirstMode && sm.syncPeer != nil &&
+			!sm.current() {
+			locator, err := sm.chain.LatestBlockLocator()
+			if err != nil {
+				Error("failed to get block locator for the latest block:", err)
+			} else if err = sm.syncPeer.PushGetBlocksMsg(locator, &zeroHash); err != nil {
+				Error(err)
+			}
+		}
+		sm.fillWindow()
+		return
+	}
+	if sm.history.received(pp, bmsg.block) {
+		sm.processHistoryBlocks()
+		return
+	}
 	// If we didn't ask for this block then the peer is misbehaving.
 	blockHash := bmsg.block.Hash()
 	if _, exists = state.requestedBlocks[*blockHash]; !exists {
@@ -454,175 +519,34 @@ func (sm *SyncManager) handleBlockMsg(workerNumber uint32, bmsg *blockMsg) {
 			return
 		}
 	}
-	// When in headers-first mode, if the block matches the hash of the first header in the list of headers that are
-	// being fetched, it's eligible for less validation since the headers have already been verified to link together
-	// and are valid up to the next checkpoint. Also, remove the list entry for all blocks except the checkpoint since
-	// it is needed to verify the next round of headers links properly.
-	isCheckpointBlock := false
-	behaviorFlags := blockchain.BFNone
-	if sm.headersFirstMode {
-		firstNodeEl := sm.headerList.Front()
-		if firstNodeEl != nil {
-			firstNode := firstNodeEl.Value.(*headerNode)
-			if blockHash.IsEqual(firstNode.hash) {
-				behaviorFlags |= blockchain.BFFastAdd
-				if firstNode.hash.IsEqual(sm.nextCheckpoint.Hash) {
-					isCheckpointBlock = true
-				} else {
-					sm.headerList.Remove(firstNodeEl)
-				}
-			}
-		}
-	}
 	// Remove block from request maps. Either chain will know about it and so we shouldn't have any more instances of
 	// trying to fetch it, or we will fail the insert and thus we'll retry next time we get an inv.
 	delete(state.requestedBlocks, *blockHash)
 	delete(sm.requestedBlocks, *blockHash)
-	var heightUpdate int32
-	var blkHashUpdate *chainhash.Hash
-	header := &bmsg.block.MsgBlock().Header
-	if blockchain.ShouldHaveSerializedBlockHeight(header) {
-		coinbaseTx := bmsg.block.Transactions()[0]
-		cbHeight, err := blockchain.ExtractCoinbaseHeight(coinbaseTx)
-		if err != nil {
-			Tracef(
-				"unable to extract height from coinbase tx: %v",
-				err,
-			)
-		} else {
-			heightUpdate = cbHeight
-			blkHashUpdate = blockHash
-		}
-	}
-	// Process the block to include validation, best chain selection, orphan handling, etc.
-	_, isOrphan, err := sm.chain.ProcessBlock(workerNumber, bmsg.block,
-		behaviorFlags, heightUpdate)
-	if err != nil {
-		Error(err)
-		// When the error is a rule error, it means the block was simply rejected as opposed to something actually going
-		// wrong, so log it as such. Otherwise, something really did go wrong, so log it as an actual error.
-		if _, ok := err.(blockchain.RuleError); ok {
-			Errorf(
-				"rejected block %v from %s: %v",
-				blockHash, pp, err,
-			)
-			// Infof("height %d", bmsg.block.Height())
-		} else {
-			Errorf("failed to process block %v: %v", blockHash, err)
-		}
-		if dbErr, ok := err.(database.DBError); ok && dbErr.ErrorCode ==
-			database.ErrCorruption {
-			panic(dbErr)
-		}
-		// Convert the error into an appropriate reject message and send it.
-		code, reason := mempool.ErrToRejectErr(err)
-		pp.PushRejectMsg(wire.CmdBlock, code, reason, blockHash, false)
-		return
-	}
-	// Meta-data about the new block this peer is reporting. We use this below to update this peer's lastest block
-	// height and the heights of other peers based on their last announced block hash. This allows us to dynamically
-	// update the block heights of peers, avoiding stale heights when looking for a new sync peer. Upon acceptance of a
-	// block or recognition of an orphan, we also use this information to update the block heights over other peers
-	// who's invs may have been ignored if we are actively syncing while the chain is not yet current or who may have
-	// lost the lock announcment race. Request the parents for the orphan block from the peer that sent it.
-	if isOrphan {
-		// We've just received an orphan block from a peer. In order to update the height of the peer, we try to extract
-		// the block height from the scriptSig of the coinbase transaction. Extraction is only attempted if the block's
-		// version is high enough (ver 2+).
-		header := &bmsg.block.MsgBlock().Header
-		if blockchain.ShouldHaveSerializedBlockHeight(header) {
-			coinbaseTx := bmsg.block.Transactions()[0]
-			cbHeight, err := blockchain.ExtractCoinbaseHeight(coinbaseTx)
-			if err != nil {
-				Errorf("unable to extract height from coinbase tx: %v", err)
-			} else {
-				Debugf("extracted height of %v from orphan block",
-					cbHeight)
-				heightUpdate = cbHeight
-				blkHashUpdate = blockHash
-			}
-		}
-		orphanRoot := sm.chain.GetOrphanRoot(blockHash)
-		locator, err := sm.chain.LatestBlockLocator()
-		if err != nil {
-			Errorf("failed to get block locator for the latest block: %v",
-				err)
-		} else {
-			err := pp.PushGetBlocksMsg(locator, orphanRoot)
-			if err != nil {
-				Error(err)
-			}
-		}
-	} else {
-		// When the block is not an orphan, log information about it and update the chain state.
-		sm.progressLogger.LogBlockHeight(bmsg.block)
-		// Update this peer's latest block height, for future potential sync node candidacy.
-		best := sm.chain.BestSnapshot()
-		heightUpdate = best.Height
-		blkHashUpdate = &best.Hash
-		// Clear the rejected transactions.
-		sm.rejectedTxns = make(map[chainhash.Hash]struct{})
-	}
-	// Update the block height for this peer. But only send a message to the server for updating peer heights if this is
-	// an orphan or our chain is "current". This avoids sending a spammy amount of messages if we're syncing the chain
-	// from scratch.
-	if blkHashUpdate != nil && heightUpdate != 0 {
-		pp.UpdateLastBlockHeight(heightUpdate)
-		if isOrphan || sm.current() {
-			go sm.peerNotifier.UpdatePeerHeights(blkHashUpdate, heightUpdate,
-				pp)
-		}
-	}
-	// Nothing more to do if we aren't in headers-first mode.
-	if !sm.headersFirstMode {
-		return
-	}
-	// This is headers-first mode, so if the block is not a checkpoint request more blocks using the header list when
-	// the request queue is getting short.
-	if !isCheckpointBlock {
-		if sm.startHeader != nil &&
-			len(state.requestedBlocks) < minInFlightBlocks {
-			sm.fetchHeaderBlocks()
-		}
+	sm.processBlock(workerNumber, bmsg.block, pp)
+}
+
+// handleBlockTxnMsg handles blocktxn messages, which complete a block being rebuilt from a compact block sent by the
+// same peer.
+func (sm *SyncManager) handleBlockTxnMsg(workerNumber uint32, bmsg *blockTxnMsg) {
+	pp := bmsg.peer
+	if _, exists := sm.peerStates[pp]; !exists {
+		Trace("received blocktxn message from unknown peer", pp)
 		return
 	}
-	// This is headers-first mode and the block is a checkpoint. When there is a next checkpoint, get the next round of
-	// headers by asking for headers starting from the block after this one up to the next checkpoint.
-	prevHeight := sm.nextCheckpoint.Height
-	prevHash := sm.nextCheckpoint.Hash
-	sm.nextCheckpoint = sm.findNextHeaderCheckpoint(prevHeight)
-	if sm.nextCheckpoint != nil {
-		locator := blockchain.BlockLocator([]*chainhash.Hash{prevHash})
-		err := pp.PushGetHeadersMsg(locator, sm.nextCheckpoint.Hash)
-		if err != nil {
-			Error(err)
-			Errorf(
-				"failed to send getheaders message to peer %s: %v",
-				pp.Addr(), err,
-			)
-			return
-		}
-		Infof(
-			"downloading headers for blocks %d to %d from peer %s",
-			prevHeight+1, sm.nextCheckpoint.Height, sm.syncPeer.Addr(),
-		)
+	blockHash := bmsg.blockTxn.BlockHash
+	pb, exists := sm.compactBlocks[blockHash]
+	if !exists || pb.peer != pp {
+		Tracef("got unrequested transactions of block %v from %s", blockHash, pp)
 		return
 	}
-	// This is headers-first mode, the block is a checkpoint, and there are no more checkpoints, so switch to normal
-	// mode by requesting blocks from the block after this one up to the end of the chain (zero hash).
-	sm.headersFirstMode = false
-	sm.headerList.Init()
-	Info(
-		"reached the final checkpoint -- switching to normal mode",
-	)
-	locator := blockchain.BlockLocator([]*chainhash.Hash{blockHash})
-	err = pp.PushGetBlocksMsg(locator, &zeroHash)
-	if err != nil {
-		Error(
-			"failed to send getblocks message to peer", pp, ":", err,
-		)
+	if err := pb.fill(bmsg.blockTxn.Transactions); err != nil {
+		Debugf("unable to complete compact block %v from %s: %v", blockHash, pp, err)
+		delete(sm.compactBlocks, blockHash)
+		sm.requestFullBlock(pp, &blockHash)
 		return
 	}
+	sm.processCompactBlock(workerNumber, &blockHash, pb)
 }
 
 // handleBlockchainNotification handles notifications from blockchain. It does things such as request orphan block
@@ -702,6 +626,86 @@ func (sm *SyncManager) handleBlockchainNotification(notification *blockchain.Not
 	}
 }
 
+// handleCmpctBlockMsg handles cmpctblock messages from all peers. Once the chain is current, new blocks are rebuilt
+// from their compact block and the transactions of the memory pool, and the transactions that are not in the memory
+// pool are requested from the peer. Compact blocks received while syncing are ignored, as the blocks are then
+// downloaded in full.
+func (sm *SyncManager) handleCmpctBlockMsg(workerNumber uint32, cmsg *cmpctBlockMsg) {
+	pp := cmsg.peer
+	state, exists := sm.peerStates[pp]
+	if !exists {
+		Trace("received cmpctblock message from unknown peer", pp)
+		return
+	}
+	msg := cmsg.cmpctBlock
+	blockHash := msg.BlockHash()
+	pp.AddKnownInventory(wire.NewInvVect(wire.InvTypeBlock, &blockHash))
+	if msg.TxCount() == 0 {
+		Warnf("got compact block %v without transactions from %s -- disconnecting", blockHash, pp)
+		pp.Disconnect()
+		return
+	}
+	if _, exists = sm.compactBlocks[blockHash]; exists {
+		return
+	}
+	// The block is requested again by the next inv announcing it if it is not rebuilt here.
+	delete(state.requestedBlocks, blockHash)
+	delete(sm.requestedBlocks, blockHash)
+	if !sm.current() || sm.headersFirstMode {
+		return
+	}
+	haveBlock, err := sm.chain.HaveBlock(&blockHash)
+	if err != nil {
+		Error(err)
+		return
+	}
+	if haveBlock {
+		return
+	}
+	// The proof of work of the header is checked before any of the transactions of the block are looked up or
+	// requested, so a peer can't make the block be rebuilt without mining it. A block building on an unknown block is
+	// requested in full to be processed as an orphan.
+	if err = sm.chain.CheckHeaderProofOfWork(&msg.Header); err != nil {
+		if rErr, ok := err.(blockchain.RuleError); ok && rErr.ErrorCode == blockchain.ErrPreviousBlockUnknown {
+			sm.requestFullBlock(pp, &blockHash)
+			return
+		}
+		if _, ok := err.(blockchain.RuleError); ok {
+			Warnf("got compact block %v with an invalid header from %s: %v -- disconnecting", blockHash, pp, err)
+			pp.Disconnect()
+			return
+		}
+		Error(err)
+		return
+	}
+	txDescs := sm.txMemPool.TxDescs()
+	pool := make([]*util.Tx, len(txDescs))
+	for i, txD := range txDescs {
+		pool[i] = txD.Tx
+	}
+	pb, err := newPartialBlock(pp, msg, pool)
+	if err != nil {
+		Debugf("unable to rebuild compact block %v from %s: %v", blockHash, pp, err)
+		sm.requestFullBlock(pp, &blockHash)
+		return
+	}
+	if len(pb.missing) == 0 {
+		sm.processCompactBlock(workerNumber, &blockHash, pb)
+		return
+	}
+	// Each peer has at most one block being rebuilt, so a peer that does not answer can't make the partial blocks
+	// pile up.
+	for hash, other := range sm.compactBlocks {
+		if other.peer == pp {
+			delete(sm.compactBlocks, hash)
+		}
+	}
+	sm.compactBlocks[blockHash] = pb
+	Tracef("requesting %d of %d transactions of compact block %v from %s", len(pb.missing), msg.TxCount(),
+		blockHash, pp)
+	pp.QueueMessage(wire.NewMsgGetBlockTxn(&blockHash, pb.missing), nil)
+}
+
 // handleDonePeerMsg deals with peers that have signalled they are done. It removes the peer as a candidate for syncing
 // and in the case where it was the current sync peer, attempts to select a new best peer to sync from. It is invoked
 // from the syncHandler goroutine.
@@ -725,16 +729,27 @@ func (sm *SyncManager) handleDonePeerMsg(peer *peerpkg.Peer) {
 	for blockHash := range state.requestedBlocks {
 		delete(sm.requestedBlocks, blockHash)
 	}
+	sm.window.removePeer(peer)
+	sm.history.removePeer(peer)
+	for blockHash, pb := range sm.compactBlocks {
+		if pb.peer == peer {
+			delete(sm.compactBlocks, blockHash)
+		}
+	}
 	// Attempt to find a new peer to sync from if the quitting peer is the sync peer. Also, reset the headers-first
-	// state if in headers-first mode so
+	// state if in headers-first mode so the headers are fetched again from the new sync peer.
 	if sm.syncPeer == peer {
 		sm.syncPeer = nil
 		if sm.headersFirstMode {
 			best := sm.chain.BestSnapshot()
 			sm.resetHeaderState(&best.Hash, best.Height)
+			sm.window.reset()
 		}
 		sm.startSync()
 	}
+	// Request the blocks the peer did not deliver from the remaining peers.
+	sm.fillWindow()
+	sm.fetchHistoryBlocks()
 }
 
 // handleHeadersMsg handles block header messages from all peers. Headers are requested when performing a headers-first
@@ -881,6 +896,7 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 	// Request the advertised inventory if we don't already have it. Also, request parent blocks of orphans if we
 	// receive one we already have. Finally, attempt to detect potential stalls due to long side chains we already have
 	// and request more blocks to prevent them.
+	var windowed bool
 	for i, iv := range invVects {
 		// Ignore unsupported inventory types.
 		switch iv.Type {
@@ -915,6 +931,14 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 			// if !peer.IsWitnessEnabled() && iv.Type == wire.InvTypeBlock {
 			// 	continue
 			// }
+			// While syncing, the blocks announced by the sync peer are downloaded from all the sync candidates
+			// through the download window.
+			if iv.Type == wire.InvTypeBlock && peer == sm.syncPeer && !sm.current() {
+				if sm.window.add(&iv.Hash, 0) {
+					windowed = true
+				}
+				continue
+			}
 			// Add it to the request queue.
 			state.requestQueue = append(state.requestQueue, iv)
 			continue
@@ -951,6 +975,9 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 			}
 		}
 	}
+	if windowed {
+		sm.fillWindow()
+	}
 	// Request as much as possible at once. Anything that won't fit into request will be requested on the next inv
 	// message.
 	numRequested := 0
@@ -969,7 +996,11 @@ func (sm *SyncManager) handleInvMsg(imsg *invMsg) {
 				sm.requestedBlocks[iv.Hash] = struct{}{}
 				sm.limitMap(sm.requestedBlocks, maxRequestedBlocks)
 				state.requestedBlocks[iv.Hash] = struct{}{}
-				if peer.IsWitnessEnabled() {
+				// Once current, new blocks are fetched as compact blocks from peers that support them.
+				switch {
+				case sm.current() && peer.WantsCompactBlocks():
+					iv.Type = wire.InvTypeCmpctBlock
+				case peer.IsWitnessEnabled():
 					iv.Type = wire.InvTypeWitnessBlock
 				}
 				err := gdmsg.AddInvVect(iv)
@@ -1023,10 +1054,35 @@ func (sm *SyncManager) handleNewPeerMsg(peer *peerpkg.Peer) {
 		requestedTxns:   make(map[chainhash.Hash]struct{}),
 		requestedBlocks: make(map[chainhash.Hash]struct{}),
 	}
-	// Start syncing by choosing the best candidate if needed.
+	// Start syncing by choosing the best candidate if needed, otherwise let the new candidate help download the blocks
+	// of the download window.
 	if isSyncCandidate && sm.syncPeer == nil {
 		sm.startSync()
 	}
+	if isSyncCandidate {
+		sm.fillWindow()
+	}
+}
+
+// handleStallSample drops the peers that did not deliver the blocks of the download window they were asked for in
+// time, and requests those blocks from the remaining peers, along with the blocks below a utxo set snapshot the chain
+// state was loaded from. It is invoked from the syncHandler goroutine.
+func (sm *SyncManager) handleStallSample() {
+	if atomic.LoadInt32(&sm.shutdown) != 0 {
+		return
+	}
+	for _, peer := range sm.window.timedOut(time.Now()) {
+		Warnf("peer %s stalled the block download -- disconnecting", peer)
+		if state, exists := sm.peerStates[peer]; exists {
+			state.syncCandidate = false
+		}
+		peer.Disconnect()
+	}
+	sm.fillWindow()
+	// The blocks below a utxo set snapshot are not urgent, so a peer that is slow to deliver them is only not waited
+	// for. The download of them starts here once the chain is current.
+	sm.history.timedOut(time.Now())
+	sm.fetchHistoryBlocks()
 }
 
 // handleTxMsg handles transaction messages from all peers.
@@ -1180,6 +1236,207 @@ func (sm *SyncManager) limitMap(m map[chainhash.Hash]struct{}, limit int) {
 	}
 }
 
+// processBlock passes a block received from a peer to the chain and, in headers-first mode, moves on to the next round
+// of headers once a checkpoint block is connected.
+func (sm *SyncManager) processBlock(workerNumber uint32, block *util.Block, pp *peerpkg.Peer) {
+	blockHash := block.Hash()
+	// When in headers-first mode, if the block matches the hash of the first header in the list of headers that are
+	// being fetched, it's eligible for less validation since the headers have already been verified to link together
+	// and are valid up to the next checkpoint. Also, remove the list entry for all blocks except the checkpoint since
+	// it is needed to verify the next round of headers links properly.
+	isCheckpointBlock := false
+	behaviorFlags := blockchain.BFNone
+	if sm.headersFirstMode {
+		firstNodeEl := sm.headerList.Front()
+		if firstNodeEl != nil {
+			firstNode := firstNodeEl.Value.(*headerNode)
+			if blockHash.IsEqual(firstNode.hash) {
+				behaviorFlags |= blockchain.BFFastAdd
+				if firstNode.hash.IsEqual(sm.nextCheckpoint.Hash) {
+					isCheckpointBlock = true
+				} else {
+					sm.headerList.Remove(firstNodeEl)
+				}
+			}
+		}
+	}
+	var heightUpdate int32
+	var blkHashUpdate *chainhash.Hash
+	header := &block.MsgBlock().Header
+	if blockchain.ShouldHaveSerializedBlockHeight(header) {
+		coinbaseTx := block.Transactions()[0]
+		cbHeight, err := blockchain.ExtractCoinbaseHeight(coinbaseTx)
+		if err != nil {
+			Tracef(
+				"unable to extract height from coinbase tx: %v",
+				err,
+			)
+		} else {
+			heightUpdate = cbHeight
+			blkHashUpdate = blockHash
+		}
+	}
+	// Process the block to include validation, best chain selection, orphan handling, etc.
+	_, isOrphan, err := sm.chain.ProcessBlock(workerNumber, block,
+		behaviorFlags, heightUpdate)
+	if err != nil {
+		Error(err)
+		// When the error is a rule error, it means the block was simply rejected as opposed to something actually going
+		// wrong, so log it as such. Otherwise, something really did go wrong, so log it as an actual error.
+		if _, ok := err.(blockchain.RuleError); ok {
+			Errorf(
+				"rejected block %v from %s: %v",
+				blockHash, pp, err,
+			)
+			// Infof("height %d", block.Height())
+		} else {
+			Errorf("failed to process block %v: %v", blockHash, err)
+		}
+		if dbErr, ok := err.(database.DBError); ok && dbErr.ErrorCode ==
+			database.ErrCorruption {
+			panic(dbErr)
+		}
+		// Convert the error into an appropriate reject message and send it.
+		code, reason := mempool.ErrToRejectErr(err)
+		pp.PushRejectMsg(wire.CmdBlock, code, reason, blockHash, false)
+		return
+	}
+	// Meta-data about the new block this peer is reporting. We use this below to update this peer's lastest block
+	// height and the heights of other peers based on their last announced block hash. This allows us to dynamically
+	// update the block heights of peers, avoiding stale heights when looking for a new sync peer. Upon acceptance of a
+	// block or recognition of an orphan, we also use this information to update the block heights over other peers
+	// who's invs may have been ignored if we are actively syncing while the chain is not yet current or who may have
+	// lost the lock announcment race. Request the parents for the orphan block from the peer that sent it.
+	if isOrphan {
+		// We've just received an orphan block from a peer. In order to update the height of the peer, we try to extract
+		// the block height from the scriptSig of the coinbase transaction. Extraction is only attempted if the block's
+		// version is high enough (ver 2+).
+		header := &block.MsgBlock().Header
+		if blockchain.ShouldHaveSerializedBlockHeight(header) {
+			coinbaseTx := block.Transactions()[0]
+			cbHeight, err := blockchain.ExtractCoinbaseHeight(coinbaseTx)
+			if err != nil {
+				Errorf("unable to extract height from coinbase tx: %v", err)
+			} else {
+				Debugf("extracted height of %v from orphan block",
+					cbHeight)
+				heightUpdate = cbHeight
+				blkHashUpdate = blockHash
+			}
+		}
+		orphanRoot := sm.chain.GetOrphanRoot(blockHash)
+		locator, err := sm.chain.LatestBlockLocator()
+		if err != nil {
+			Errorf("failed to get block locator for the latest block: %v",
+				err)
+		} else {
+			err := pp.PushGetBlocksMsg(locator, orphanRoot)
+			if err != nil {
+				Error(err)
+			}
+		}
+	} else {
+		// When the block is not an orphan, log information about it and update the chain state.
+		sm.progressLogger.LogBlockHeight(block)
+		// Update this peer's latest block height, for future potential sync node candidacy.
+		best := sm.chain.BestSnapshot()
+		heightUpdate = best.Height
+		blkHashUpdate = &best.Hash
+		// Clear the rejected transactions.
+		sm.rejectedTxns = make(map[chainhash.Hash]struct{})
+	}
+	// Update the block height for this peer. But only send a message to the server for updating peer heights if this is
+	// an orphan or our chain is "current". This avoids sending a spammy amount of messages if we're syncing the chain
+	// from scratch.
+	if blkHashUpdate != nil && heightUpdate != 0 {
+		pp.UpdateLastBlockHeight(heightUpdate)
+		if isOrphan || sm.current() {
+			go sm.peerNotifier.UpdatePeerHeights(blkHashUpdate, heightUpdate,
+				pp)
+		}
+	}
+	// Nothing more to do if we aren't in headers-first mode.
+	if !sm.headersFirstMode {
+		return
+	}
+	// This is headers-first mode, so if the block is not a checkpoint the rest of the blocks of the header list are
+	// already in the download window. The next round of headers and blocks is fetched through the sync peer.
+	if !isCheckpointBlock || sm.syncPeer == nil {
+		return
+	}
+	// This is headers-first mode and the block is a checkpoint. When there is a next checkpoint, get the next round of
+	// headers by asking for headers starting from the block after this one up to the next checkpoint.
+	prevHeight := sm.nextCheckpoint.Height
+	prevHash := sm.nextCheckpoint.Hash
+	sm.nextCheckpoint = sm.findNextHeaderCheckpoint(prevHeight)
+	if sm.nextCheckpoint != nil {
+		locator := blockchain.BlockLocator([]*chainhash.Hash{prevHash})
+		err := sm.syncPeer.PushGetHeadersMsg(locator, sm.nextCheckpoint.Hash)
+		if err != nil {
+			Error(err)
+			Errorf(
+				"failed to send getheaders message to peer %s: %v",
+				sm.syncPeer.Addr(), err,
+			)
+			return
+		}
+		Infof(
+			"downloading headers for blocks %d to %d from peer %s",
+			prevHeight+1, sm.nextCheckpoint.Height, sm.syncPeer.Addr(),
+		)
+		return
+	}
+	// This is headers-first mode, the block is a checkpoint, and there are no more checkpoints, so switch to normal
+	// mode by requesting blocks from the block after this one up to the end of the chain (zero hash).
+	sm.headersFirstMode = false
+	sm.headerList.Init()
+	Info(
+		"reached the final checkpoint -- switching to normal mode",
+	)
+	locator := blockchain.BlockLocator([]*chainhash.Hash{blockHash})
+	err = sm.syncPeer.PushGetBlocksMsg(locator, &zeroHash)
+	if err != nil {
+		Error(
+			"failed to send getblocks message to peer", sm.syncPeer, ":", err,
+		)
+		return
+	}
+}
+
+// processCompactBlock processes a block that was rebuilt from a compact block. If the rebuilt transactions don't
+// match the header the block is requested in full instead.
+func (sm *SyncManager) processCompactBlock(workerNumber uint32, blockHash *chainhash.Hash, pb *partialBlock) {
+	delete(sm.compactBlocks, *blockHash)
+	block, ok := pb.block()
+	if !ok {
+		Debugf("compact block %v from %s does not match its merkle root", blockHash, pb.peer)
+		sm.requestFullBlock(pb.peer, blockHash)
+		return
+	}
+	sm.processBlock(workerNumber, block, pb.peer)
+}
+
+// requestFullBlock requests a block in full from a peer, after it could not be rebuilt from its compact block.
+func (sm *SyncManager) requestFullBlock(peer *peerpkg.Peer, blockHash *chainhash.Hash) {
+	state, exists := sm.peerStates[peer]
+	if !exists {
+		return
+	}
+	sm.requestedBlocks[*blockHash] = struct{}{}
+	sm.limitMap(sm.requestedBlocks, maxRequestedBlocks)
+	state.requestedBlocks[*blockHash] = struct{}{}
+	iv := wire.NewInvVect(wire.InvTypeBlock, blockHash)
+	if peer.IsWitnessEnabled() {
+		iv.Type = wire.InvTypeWitnessBlock
+	}
+	gdmsg := wire.NewMsgGetData()
+	if err := gdmsg.AddInvVect(iv); err != nil {
+		Error(err)
+		return
+	}
+	peer.QueueMessage(gdmsg, nil)
+}
+
 // resetHeaderState sets the headers-first mode state to values appropriate for syncing from a new peer.
 func (sm *SyncManager) resetHeaderState(newestHash *chainhash.Hash, newestHeight int32) {
 	sm.headersFirstMode = false
@@ -1290,6 +1547,9 @@ func New(config *Config) (*SyncManager, error) {
 		requestedTxns:   make(map[chainhash.Hash]struct{}),
 		requestedBlocks: make(map[chainhash.Hash]struct{}),
 		peerStates:      make(map[*peerpkg.Peer]*peerSyncState),
+		window:          newDownloadWindow(downloadWindowSize, maxBlocksPerPeer, blockDownloadTimeout),
+		history:         newDownloadWindow(historyWindowSize, maxBlocksPerPeer, blockDownloadTimeout),
+		compactBlocks:   make(map[chainhash.Hash]*partialBlock),
 		progressLogger:  newBlockProgressLogger("processed"),
 		msgChan:         make(chan interface{}, config.MaxPeers*3),
 		headerList:      list.New(),
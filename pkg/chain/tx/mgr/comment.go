@@ -0,0 +1,34 @@
+package wtxmgr
+
+import (
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/db/walletdb"
+)
+
+// TxComment holds the memo fields a user attaches to a transaction: a free-form comment and the name of the person or
+// organization the transaction is for.
+type TxComment struct {
+	Comment   string
+	CommentTo string
+}
+
+// IsEmpty returns whether neither of the comment fields is set.
+func (c *TxComment) IsEmpty() bool {
+	return c.Comment == "" && c.CommentTo == ""
+}
+
+// PutTxComment records the comment for the transaction with the passed hash, replacing any earlier one. An empty
+// comment removes the recorded one. The transaction does not need to be in the store yet, so the comment can be saved
+// before a transaction created by the wallet is published.
+func (s *Store) PutTxComment(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash, comment *TxComment) error {
+	if comment.IsEmpty() {
+		return deleteTxComment(ns, txHash)
+	}
+	return putTxComment(ns, txHash, comment)
+}
+
+// TxComment returns the comment recorded for the transaction with the passed hash. Not having a comment is not an
+// error, an empty comment is returned instead.
+func (s *Store) TxComment(ns walletdb.ReadBucket, txHash *chainhash.Hash) (TxComment, error) {
+	return fetchTxComment(ns, txHash)
+}
@@ -62,6 +62,7 @@ var (
 	bucketUnmined        = []byte("m")
 	bucketUnminedCredits = []byte("mc")
 	bucketUnminedInputs  = []byte("mi")
+	bucketTxComments     = []byte("tc")
 	// Root (namespace) bucket keys
 	rootCreateDate   = []byte("date")
 	rootVersion      = []byte("vers")
@@ -1182,6 +1183,91 @@ func deleteRawUnminedInput(ns walletdb.ReadWriteBucket, k []byte) error {
 	return nil
 }
 
+// Transaction comments are the memos a user attaches to a transaction, usually when sending it. They are saved in
+// their own bucket keyed by transaction hash, apart from the transaction records, so they are kept when a transaction
+// moves between the unmined and mined buckets. The value is:
+//
+//   [0:4]       Comment length (4 bytes)
+//   [4:4+n]     Comment (n bytes)
+//   [4+n:8+n]   Comment to length (4 bytes)
+//   [8+n:8+n+m] Comment to (m bytes)
+//
+// The bucket was added after stores of the latest version were already in use, so it is created with the first comment
+// saved to a store that lacks it, and a missing bucket reads as no comments.
+func valueTxComment(c *TxComment) []byte {
+	v := make([]byte, 8+len(c.Comment)+len(c.CommentTo))
+	byteOrder.PutUint32(v, uint32(len(c.Comment)))
+	copy(v[4:], c.Comment)
+	off := 4 + len(c.Comment)
+	byteOrder.PutUint32(v[off:], uint32(len(c.CommentTo)))
+	copy(v[off+4:], c.CommentTo)
+	return v
+}
+
+func readRawTxComment(v []byte, c *TxComment) error {
+	if len(v) < 4 {
+		str := "short tx comment value"
+		return storeError(ErrData, str, nil)
+	}
+	n := int(byteOrder.Uint32(v))
+	if len(v) < 8+n {
+		str := "short tx comment value"
+		return storeError(ErrData, str, nil)
+	}
+	c.Comment = string(v[4 : 4+n])
+	m := int(byteOrder.Uint32(v[4+n:]))
+	if len(v) != 8+n+m {
+		str := "tx comment value has the wrong length"
+		return storeError(ErrData, str, nil)
+	}
+	c.CommentTo = string(v[8+n:])
+	return nil
+}
+
+func putTxComment(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash, c *TxComment) error {
+	b, err := ns.CreateBucketIfNotExists(bucketTxComments)
+	if err != nil {
+		Error(err)
+		str := "failed to create tx comments bucket"
+		return storeError(ErrDatabase, str, err)
+	}
+	err = b.Put(txHash[:], valueTxComment(c))
+	if err != nil {
+		Error(err)
+		str := fmt.Sprintf("failed to put comment for tx %v", txHash)
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
+func fetchTxComment(ns walletdb.ReadBucket, txHash *chainhash.Hash) (TxComment, error) {
+	var c TxComment
+	b := ns.NestedReadBucket(bucketTxComments)
+	if b == nil {
+		return c, nil
+	}
+	v := b.Get(txHash[:])
+	if v == nil {
+		return c, nil
+	}
+	err := readRawTxComment(v, &c)
+	return c, err
+}
+
+func deleteTxComment(ns walletdb.ReadWriteBucket, txHash *chainhash.Hash) error {
+	b := ns.NestedReadWriteBucket(bucketTxComments)
+	if b == nil {
+		return nil
+	}
+	err := b.Delete(txHash[:])
+	if err != nil {
+		Error(err)
+		str := fmt.Sprintf("failed to delete comment for tx %v", txHash)
+		return storeError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // openStore opens an existing transaction store from the passed namespace.
 func openStore(ns walletdb.ReadBucket) error {
 	v := ns.Get(rootVersion)
@@ -1299,6 +1385,12 @@ func createStore(ns walletdb.ReadWriteBucket) error {
 		str := "failed to create unmined inputs bucket"
 		return storeError(ErrDatabase, str, err)
 	}
+	_, err = ns.CreateBucket(bucketTxComments)
+	if err != nil {
+		Error(err)
+		str := "failed to create tx comments bucket"
+		return storeError(ErrDatabase, str, err)
+	}
 	return nil
 }
 
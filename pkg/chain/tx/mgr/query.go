@@ -31,6 +31,8 @@ type TxDetails struct {
 	Block   BlockMeta
 	Credits []CreditRecord
 	Debits  []DebitRecord
+	// Comment is the memo recorded for the transaction, which is empty if there is none.
+	Comment TxComment
 }
 
 // minedTxDetails fetches the TxDetails for the mined transaction with hash txHash and the passed tx record key and
@@ -49,6 +51,11 @@ func (s *Store) minedTxDetails(ns walletdb.ReadBucket, txHash *chainhash.Hash, r
 		Error(err)
 		return nil, err
 	}
+	details.Comment, err = fetchTxComment(ns, txHash)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
 	details.Block.Time, err = fetchBlockTime(ns, details.Block.Height)
 	if err != nil {
 		Error(err)
@@ -94,6 +101,11 @@ func (s *Store) unminedTxDetails(ns walletdb.ReadBucket, txHash *chainhash.Hash,
 		Error(err)
 		return nil, err
 	}
+	details.Comment, err = fetchTxComment(ns, txHash)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
 	it := makeReadUnminedCreditIterator(ns, txHash)
 	for it.next() {
 		if int(it.elem.Index) >= len(details.MsgTx.TxOut) {
@@ -280,6 +292,11 @@ func (s *Store) rangeBlockTransactions(ns walletdb.ReadBucket, begin, end int32,
 					Error(err)
 					return false, err
 				}
+				detail.Comment, err = fetchTxComment(ns, &txHash)
+				if err != nil {
+					Error(err)
+					return false, err
+				}
 				credIter := makeReadCreditIterator(ns, k)
 				for credIter.next() {
 					if int(credIter.elem.Index) >= len(detail.MsgTx.TxOut) {
@@ -1825,3 +1825,103 @@ func TestAddDuplicateCreditAfterConfirm(t *testing.T) {
 		}
 	})
 }
+
+// TestTxComments ensures that transaction comments are kept apart from the transaction records: they can be saved
+// before the transaction is known, are returned with its details while it is unmined and after it is mined, and an
+// empty comment removes them.
+func TestTxComments(t *testing.T) {
+	t.Parallel()
+	store, db, teardown, err := testStore()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer teardown()
+	b100 := &BlockMeta{
+		Block: Block{Height: 100},
+		Time:  time.Now(),
+	}
+	cb := newCoinBase(1e8)
+	cbRec, err := NewTxRecordFromMsgTx(cb, b100.Time)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spendTx := spendOutput(&cbRec.Hash, 0, 5e7, 4e7)
+	spendTxRec, err := NewTxRecordFromMsgTx(spendTx, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	comment := TxComment{Comment: "invoice 1042", CommentTo: "Acme Ltd"}
+	checkComment := func(want TxComment) {
+		t.Helper()
+		commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+			t.Helper()
+			got, err := store.TxComment(ns, &spendTxRec.Hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != want {
+				t.Fatalf("expected comment %v, got %v", want, got)
+			}
+			details, err := store.TxDetails(ns, &spendTxRec.Hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if details != nil && details.Comment != want {
+				t.Fatalf("expected details comment %v, got %v", want, details.Comment)
+			}
+		})
+	}
+	// The comment of a transaction the wallet creates is saved before the transaction reaches the store.
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.PutTxComment(ns, &spendTxRec.Hash, &comment); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkComment(comment)
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.InsertTx(ns, cbRec, b100); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.AddCredit(ns, cbRec, b100, 0, false); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.InsertTx(ns, spendTxRec, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkComment(comment)
+	b101 := &BlockMeta{
+		Block: Block{Height: 101},
+		Time:  time.Now(),
+	}
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.InsertTx(ns, spendTxRec, b101); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkComment(comment)
+	// The comment is also returned when ranging over the mined transactions.
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		var found bool
+		err := store.RangeTransactions(ns, 101, 101, func(details []TxDetails) (bool, error) {
+			for i := range details {
+				if details[i].Hash == spendTxRec.Hash {
+					found = details[i].Comment == comment
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Fatal("mined transaction not found with its comment")
+		}
+	})
+	commitDBTx(t, store, db, func(ns walletdb.ReadWriteBucket) {
+		if err := store.PutTxComment(ns, &spendTxRec.Hash, &TxComment{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	checkComment(TxComment{})
+}
@@ -0,0 +1,117 @@
+package psbt
+
+import (
+	"bytes"
+)
+
+// Combine merges packets for the same unsigned transaction, such as the copies a multisig address's cosigners each
+// signed, into a new packet holding the union of their key-value pairs. Where the packets hold different values for a
+// key the value of the earliest packet is kept. ErrUnsignedTxMismatch is returned if the packets are not all for the
+// same transaction.
+func Combine(packets ...*Packet) (*Packet, error) {
+	if len(packets) == 0 {
+		return nil, ErrNoUnsignedTx
+	}
+	txHash := packets[0].UnsignedTx.TxHash()
+	for _, p := range packets[1:] {
+		if p.UnsignedTx.TxHash() != txHash {
+			return nil, ErrUnsignedTxMismatch
+		}
+	}
+	combined, err := NewFromUnsignedTx(packets[0].UnsignedTx.Copy())
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range packets {
+		combined.Unknowns = mergeUnknowns(combined.Unknowns, p.Unknowns)
+		for i := range p.Inputs {
+			combined.Inputs[i].merge(&p.Inputs[i])
+		}
+		for i := range p.Outputs {
+			combined.Outputs[i].merge(&p.Outputs[i])
+		}
+	}
+	return combined, nil
+}
+
+// merge adds the key-value pairs of another map of the same input that this map does not have.
+func (pi *PInput) merge(other *PInput) {
+	if pi.NonWitnessUtxo == nil {
+		pi.NonWitnessUtxo = other.NonWitnessUtxo
+	}
+	if pi.WitnessUtxo == nil {
+		pi.WitnessUtxo = other.WitnessUtxo
+	}
+	for _, ps := range other.PartialSigs {
+		if !hasPartialSig(pi.PartialSigs, ps.PubKey) {
+			pi.PartialSigs = append(pi.PartialSigs, ps)
+		}
+	}
+	if pi.SighashType == 0 {
+		pi.SighashType = other.SighashType
+	}
+	if pi.RedeemScript == nil {
+		pi.RedeemScript = other.RedeemScript
+	}
+	if pi.WitnessScript == nil {
+		pi.WitnessScript = other.WitnessScript
+	}
+	pi.Bip32Derivation = mergeDerivations(pi.Bip32Derivation, other.Bip32Derivation)
+	if pi.FinalScriptSig == nil {
+		pi.FinalScriptSig = other.FinalScriptSig
+	}
+	if pi.FinalScriptWitness == nil {
+		pi.FinalScriptWitness = other.FinalScriptWitness
+	}
+	pi.Unknowns = mergeUnknowns(pi.Unknowns, other.Unknowns)
+}
+
+// merge adds the key-value pairs of another map of the same output that this map does not have.
+func (po *POutput) merge(other *POutput) {
+	if po.RedeemScript == nil {
+		po.RedeemScript = other.RedeemScript
+	}
+	if po.WitnessScript == nil {
+		po.WitnessScript = other.WitnessScript
+	}
+	po.Bip32Derivation = mergeDerivations(po.Bip32Derivation, other.Bip32Derivation)
+	po.Unknowns = mergeUnknowns(po.Unknowns, other.Unknowns)
+}
+
+// hasPartialSig returns whether the signatures include one for the public key.
+func hasPartialSig(sigs []*PartialSig, pubKey []byte) bool {
+	for _, ps := range sigs {
+		if bytes.Equal(ps.PubKey, pubKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeDerivations appends the derivations of other whose public keys are not in derivations.
+func mergeDerivations(derivations, other []*Bip32Derivation) []*Bip32Derivation {
+next:
+	for _, d := range other {
+		for _, have := range derivations {
+			if bytes.Equal(have.PubKey, d.PubKey) {
+				continue next
+			}
+		}
+		derivations = append(derivations, d)
+	}
+	return derivations
+}
+
+// mergeUnknowns appends the unknown pairs of other whose keys are not in unknowns.
+func mergeUnknowns(unknowns, other []*Unknown) []*Unknown {
+next:
+	for _, u := range other {
+		for _, have := range unknowns {
+			if bytes.Equal(have.Key, u.Key) {
+				continue next
+			}
+		}
+		unknowns = append(unknowns, u)
+	}
+	return unknowns
+}
@@ -0,0 +1,197 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	"github.com/p9c/pod/pkg/chain/wire"
+	ec "github.com/p9c/pod/pkg/coding/elliptic"
+)
+
+const (
+	// MaxPsbtKeyLength is the largest key a key-value pair of a packet may have.
+	MaxPsbtKeyLength = 10000
+	// MaxPsbtValueLength is the largest value a key-value pair of a packet may have, which is enough for a transaction
+	// of the largest block size.
+	MaxPsbtValueLength = 4000000
+)
+
+type (
+	// Unknown is a key-value pair of a type this package does not know. These are kept so a packet can be passed
+	// through a party that does not understand them without losing them.
+	Unknown struct {
+		Key   []byte
+		Value []byte
+	}
+	// PartialSig is the signature of one public key for an input that is not yet finalized.
+	PartialSig struct {
+		PubKey    []byte
+		Signature []byte
+	}
+	// Bip32Derivation records the master key fingerprint and BIP32 derivation path of a public key, so a signer
+	// holding the master key can find the private key for it.
+	Bip32Derivation struct {
+		PubKey               []byte
+		MasterKeyFingerprint uint32
+		Bip32Path            []uint32
+	}
+)
+
+// readKVPair reads a key-value pair of a map. The key includes the leading key type byte. The separator that ends a
+// map is returned as a nil key.
+func readKVPair(r io.Reader) (key, value []byte, err error) {
+	keyLen, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, nil, ErrInvalidPsbtFormat
+	}
+	if keyLen == 0 {
+		return nil, nil, nil
+	}
+	if keyLen > MaxPsbtKeyLength {
+		return nil, nil, ErrInvalidPsbtFormat
+	}
+	key = make([]byte, keyLen)
+	if _, err = io.ReadFull(r, key); err != nil {
+		return nil, nil, ErrInvalidPsbtFormat
+	}
+	value, err = wire.ReadVarBytes(r, 0, MaxPsbtValueLength, "PSBT value")
+	if err != nil {
+		return nil, nil, ErrInvalidPsbtFormat
+	}
+	return key, value, nil
+}
+
+// writeKVPair writes a key-value pair whose key is made of the key type and the key data.
+func writeKVPair(w io.Writer, keyType uint8, keyData, value []byte) error {
+	if err := wire.WriteVarInt(w, 0, uint64(len(keyData)+1)); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{keyType}); err != nil {
+		return err
+	}
+	if _, err := w.Write(keyData); err != nil {
+		return err
+	}
+	return wire.WriteVarBytes(w, 0, value)
+}
+
+// writeUnknowns writes the unknown key-value pairs of a map.
+func writeUnknowns(w io.Writer, unknowns []*Unknown) error {
+	for _, u := range unknowns {
+		if err := wire.WriteVarBytes(w, 0, u.Key); err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, u.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSeparator writes the separator that ends a map.
+func writeSeparator(w io.Writer) error {
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+// keySet tracks the keys already read from a map, as a key may not appear in a map twice.
+type keySet map[string]struct{}
+
+// add records a key, returning ErrDuplicateKey if it was seen before.
+func (s keySet) add(key []byte) error {
+	if _, ok := s[string(key)]; ok {
+		return ErrDuplicateKey
+	}
+	s[string(key)] = struct{}{}
+	return nil
+}
+
+// validPubKey returns whether the key data of a pair holds a valid public key.
+func validPubKey(pubKey []byte) bool {
+	_, err := ec.ParsePubKey(pubKey, ec.S256())
+	return err == nil
+}
+
+// readTxOut decodes a transaction output serialized as in a transaction.
+func readTxOut(value []byte) (*wire.TxOut, error) {
+	if len(value) < 9 {
+		return nil, ErrInvalidPsbtFormat
+	}
+	r := bytes.NewReader(value[8:])
+	pkScript, err := wire.ReadVarBytes(r, 0, MaxPsbtValueLength, "pkScript")
+	if err != nil || r.Len() != 0 {
+		return nil, ErrInvalidPsbtFormat
+	}
+	return wire.NewTxOut(int64(binary.LittleEndian.Uint64(value[:8])), pkScript), nil
+}
+
+// serializeTxOut encodes a transaction output as it is serialized in a transaction.
+func serializeTxOut(txOut *wire.TxOut) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := wire.WriteTxOut(&buf, 0, 0, txOut); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// readBip32Derivation decodes a BIP32 derivation value, which is the master key fingerprint followed by the path, all
+// as little endian 32 bit integers.
+func readBip32Derivation(pubKey, value []byte) (*Bip32Derivation, error) {
+	if len(value) < 4 || len(value)%4 != 0 {
+		return nil, ErrInvalidPsbtFormat
+	}
+	d := &Bip32Derivation{
+		PubKey:               pubKey,
+		MasterKeyFingerprint: binary.LittleEndian.Uint32(value[:4]),
+	}
+	for i := 4; i < len(value); i += 4 {
+		d.Bip32Path = append(d.Bip32Path, binary.LittleEndian.Uint32(value[i:i+4]))
+	}
+	return d, nil
+}
+
+// serializeBip32Derivation encodes the value of a BIP32 derivation.
+func serializeBip32Derivation(d *Bip32Derivation) []byte {
+	value := make([]byte, 4*(len(d.Bip32Path)+1))
+	binary.LittleEndian.PutUint32(value[:4], d.MasterKeyFingerprint)
+	for i, index := range d.Bip32Path {
+		binary.LittleEndian.PutUint32(value[4*(i+1):], index)
+	}
+	return value
+}
+
+// readWitness decodes a witness stack serialized as in a transaction.
+func readWitness(value []byte) (wire.TxWitness, error) {
+	r := bytes.NewReader(value)
+	count, err := wire.ReadVarInt(r, 0)
+	// Every item takes at least one byte, which bounds the count before the stack is allocated.
+	if err != nil || count > uint64(len(value)) {
+		return nil, ErrInvalidPsbtFormat
+	}
+	witness := make(wire.TxWitness, count)
+	for i := range witness {
+		witness[i], err = wire.ReadVarBytes(r, 0, MaxPsbtValueLength, "witness item")
+		if err != nil {
+			return nil, ErrInvalidPsbtFormat
+		}
+	}
+	if r.Len() != 0 {
+		return nil, ErrInvalidPsbtFormat
+	}
+	return witness, nil
+}
+
+// serializeWitness encodes a witness stack as it is serialized in a transaction.
+func serializeWitness(witness wire.TxWitness) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := wire.WriteVarInt(&buf, 0, uint64(len(witness))); err != nil {
+		return nil, err
+	}
+	for _, item := range witness {
+		if err := wire.WriteVarBytes(&buf, 0, item); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,152 @@
+package psbt
+
+import (
+	"bytes"
+	"errors"
+
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+var (
+	// ErrNotFinalizable is returned when an input does not have enough signatures to be finalized.
+	ErrNotFinalizable = errors.New("PSBT input does not have enough signatures to be finalized")
+	// ErrUnsupportedScriptType is returned when an input spends an output of a type that can't be finalized.
+	ErrUnsupportedScriptType = errors.New("PSBT input spends an output of an unsupported type")
+)
+
+// Finalize builds the final signature script and witness of input idx from its partial signatures and scripts, and
+// then clears the key-value pairs only needed for signing. Inputs spending pay-to-pubkey, pay-to-pubkey-hash and
+// multisig outputs can be finalized, whether bare, nested in pay-to-script-hash or in witness programs of either kind.
+// An input that is already finalized is left unchanged.
+func (p *Packet) Finalize(idx int) error {
+	in := &p.Inputs[idx]
+	if in.IsFinalized() {
+		return nil
+	}
+	script, witness, err := p.SigningScript(idx)
+	if err != nil {
+		return err
+	}
+	// The output was already found by SigningScript, so this can't fail.
+	prevOut, _ := p.prevOutput(idx)
+	p2sh := txscript.IsPayToScriptHash(prevOut.PkScript)
+	program := prevOut.PkScript
+	if p2sh {
+		program = in.RedeemScript
+	}
+	p2wsh := txscript.IsPayToWitnessScriptHash(program)
+	// The stack is the data the signing script takes, with nil standing for the empty push.
+	var stack [][]byte
+	pushes, err := txscript.PushedData(script)
+	if err != nil {
+		return err
+	}
+	switch txscript.GetScriptClass(script) {
+	case txscript.PubKeyHashTy, txscript.WitnessV0PubKeyHashTy:
+		// The pubkey hash is the last push of both scripts.
+		pkHash := pushes[len(pushes)-1]
+		for _, ps := range in.PartialSigs {
+			if bytes.Equal(util.Hash160(ps.PubKey), pkHash) {
+				stack = [][]byte{ps.Signature, ps.PubKey}
+				break
+			}
+		}
+	case txscript.PubKeyTy:
+		for _, ps := range in.PartialSigs {
+			if bytes.Equal(ps.PubKey, pushes[0]) {
+				stack = [][]byte{ps.Signature}
+				break
+			}
+		}
+	case txscript.MultiSigTy:
+		_, nRequired, err := txscript.CalcMultiSigStats(script)
+		if err != nil {
+			return err
+		}
+		// The signatures are ordered as the public keys are in the script, after the extra item OP_CHECKMULTISIG pops.
+		sigs := [][]byte{nil}
+		for _, pubKey := range pushes {
+			for _, ps := range in.PartialSigs {
+				if len(sigs) <= nRequired && bytes.Equal(ps.PubKey, pubKey) {
+					sigs = append(sigs, ps.Signature)
+				}
+			}
+		}
+		if len(sigs) > nRequired {
+			stack = sigs
+		}
+	default:
+		return ErrUnsupportedScriptType
+	}
+	if stack == nil {
+		return ErrNotFinalizable
+	}
+	var sigScript []byte
+	if witness {
+		w := make(wire.TxWitness, 0, len(stack)+1)
+		for _, item := range stack {
+			w = append(w, append([]byte{}, item...))
+		}
+		if p2wsh {
+			w = append(w, in.WitnessScript)
+		}
+		if in.FinalScriptWitness, err = serializeWitness(w); err != nil {
+			return err
+		}
+		// A witness program nested in pay-to-script-hash is pushed by the signature script.
+		if p2sh {
+			sigScript, err = txscript.NewScriptBuilder().AddData(in.RedeemScript).Script()
+		}
+	} else {
+		builder := txscript.NewScriptBuilder()
+		for _, item := range stack {
+			builder.AddData(item)
+		}
+		if p2sh {
+			builder.AddData(in.RedeemScript)
+		}
+		sigScript, err = builder.Script()
+	}
+	if err != nil {
+		in.FinalScriptWitness = nil
+		return err
+	}
+	in.FinalScriptSig = sigScript
+	in.PartialSigs = nil
+	in.SighashType = 0
+	in.RedeemScript = nil
+	in.WitnessScript = nil
+	in.Bip32Derivation = nil
+	return nil
+}
+
+// MaybeFinalizeAll finalizes every input that can be finalized and returns whether all inputs of the packet are now
+// finalized.
+func (p *Packet) MaybeFinalizeAll() bool {
+	for i := range p.Inputs {
+		if err := p.Finalize(i); err != nil {
+			Debugf("input %d not finalized: %v", i, err)
+		}
+	}
+	return p.IsComplete()
+}
+
+// Extract returns the signed transaction of a packet whose inputs are all finalized, ready to be broadcast.
+func (p *Packet) Extract() (*wire.MsgTx, error) {
+	if !p.IsComplete() {
+		return nil, ErrIncompletePsbt
+	}
+	tx := p.UnsignedTx.Copy()
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		witness, err := in.FinalWitness()
+		if err != nil {
+			return nil, err
+		}
+		tx.TxIn[i].SignatureScript = in.FinalScriptSig
+		tx.TxIn[i].Witness = witness
+	}
+	return tx, nil
+}
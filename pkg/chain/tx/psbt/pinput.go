@@ -0,0 +1,194 @@
+package psbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+)
+
+// The key types of an input map.
+const (
+	inputNonWitnessUtxoType     = 0x00
+	inputWitnessUtxoType        = 0x01
+	inputPartialSigType         = 0x02
+	inputSighashType            = 0x03
+	inputRedeemScriptType       = 0x04
+	inputWitnessScriptType      = 0x05
+	inputBip32DerivationType    = 0x06
+	inputFinalScriptSigType     = 0x07
+	inputFinalScriptWitnessType = 0x08
+)
+
+// PInput is the map of an input of a packet. The fields that are not known are nil, and a zero SighashType means
+// SigHashAll is used.
+type PInput struct {
+	NonWitnessUtxo     *wire.MsgTx
+	WitnessUtxo        *wire.TxOut
+	PartialSigs        []*PartialSig
+	SighashType        txscript.SigHashType
+	RedeemScript       []byte
+	WitnessScript      []byte
+	Bip32Derivation    []*Bip32Derivation
+	FinalScriptSig     []byte
+	FinalScriptWitness []byte
+	Unknowns           []*Unknown
+}
+
+// IsFinalized returns whether the input has its final signature script or witness.
+func (pi *PInput) IsFinalized() bool {
+	return pi.FinalScriptSig != nil || pi.FinalScriptWitness != nil
+}
+
+// FinalWitness returns the witness stack of a finalized input, or nil if it has no final witness.
+func (pi *PInput) FinalWitness() (wire.TxWitness, error) {
+	if pi.FinalScriptWitness == nil {
+		return nil, nil
+	}
+	return readWitness(pi.FinalScriptWitness)
+}
+
+// addPartialSig adds a signature to the input, replacing any earlier signature of the same public key.
+func (pi *PInput) addPartialSig(sig *PartialSig) {
+	for i, ps := range pi.PartialSigs {
+		if bytes.Equal(ps.PubKey, sig.PubKey) {
+			pi.PartialSigs[i] = sig
+			return
+		}
+	}
+	pi.PartialSigs = append(pi.PartialSigs, sig)
+}
+
+// deserialize reads the input map up to and including its separator.
+func (pi *PInput) deserialize(r io.Reader) error {
+	seen := make(keySet)
+	for {
+		key, value, err := readKVPair(r)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return nil
+		}
+		if err = seen.add(key); err != nil {
+			return err
+		}
+		keyType, keyData := key[0], key[1:]
+		// Only the partial signatures and derivations are keyed by a public key, the other known types have no key
+		// data.
+		switch keyType {
+		case inputPartialSigType, inputBip32DerivationType:
+			if !validPubKey(keyData) {
+				return ErrInvalidKeyData
+			}
+		case inputNonWitnessUtxoType, inputWitnessUtxoType, inputSighashType, inputRedeemScriptType,
+			inputWitnessScriptType, inputFinalScriptSigType, inputFinalScriptWitnessType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+		}
+		switch keyType {
+		case inputNonWitnessUtxoType:
+			tx := &wire.MsgTx{}
+			if err = tx.Deserialize(bytes.NewReader(value)); err != nil {
+				return ErrInvalidPsbtFormat
+			}
+			pi.NonWitnessUtxo = tx
+		case inputWitnessUtxoType:
+			if pi.WitnessUtxo, err = readTxOut(value); err != nil {
+				return err
+			}
+		case inputPartialSigType:
+			pi.PartialSigs = append(pi.PartialSigs, &PartialSig{PubKey: keyData, Signature: value})
+		case inputSighashType:
+			if len(value) != 4 {
+				return ErrInvalidPsbtFormat
+			}
+			pi.SighashType = txscript.SigHashType(binary.LittleEndian.Uint32(value))
+		case inputRedeemScriptType:
+			pi.RedeemScript = value
+		case inputWitnessScriptType:
+			pi.WitnessScript = value
+		case inputBip32DerivationType:
+			d, err := readBip32Derivation(keyData, value)
+			if err != nil {
+				return err
+			}
+			pi.Bip32Derivation = append(pi.Bip32Derivation, d)
+		case inputFinalScriptSigType:
+			pi.FinalScriptSig = value
+		case inputFinalScriptWitnessType:
+			if _, err = readWitness(value); err != nil {
+				return err
+			}
+			pi.FinalScriptWitness = value
+		default:
+			pi.Unknowns = append(pi.Unknowns, &Unknown{Key: key, Value: value})
+		}
+	}
+}
+
+// serialize writes the input map followed by its separator.
+func (pi *PInput) serialize(w io.Writer) error {
+	if pi.NonWitnessUtxo != nil {
+		var buf bytes.Buffer
+		if err := pi.NonWitnessUtxo.Serialize(&buf); err != nil {
+			return err
+		}
+		if err := writeKVPair(w, inputNonWitnessUtxoType, nil, buf.Bytes()); err != nil {
+			return err
+		}
+	}
+	if pi.WitnessUtxo != nil {
+		value, err := serializeTxOut(pi.WitnessUtxo)
+		if err != nil {
+			return err
+		}
+		if err = writeKVPair(w, inputWitnessUtxoType, nil, value); err != nil {
+			return err
+		}
+	}
+	for _, ps := range pi.PartialSigs {
+		if err := writeKVPair(w, inputPartialSigType, ps.PubKey, ps.Signature); err != nil {
+			return err
+		}
+	}
+	if pi.SighashType != 0 {
+		var value [4]byte
+		binary.LittleEndian.PutUint32(value[:], uint32(pi.SighashType))
+		if err := writeKVPair(w, inputSighashType, nil, value[:]); err != nil {
+			return err
+		}
+	}
+	if pi.RedeemScript != nil {
+		if err := writeKVPair(w, inputRedeemScriptType, nil, pi.RedeemScript); err != nil {
+			return err
+		}
+	}
+	if pi.WitnessScript != nil {
+		if err := writeKVPair(w, inputWitnessScriptType, nil, pi.WitnessScript); err != nil {
+			return err
+		}
+	}
+	for _, d := range pi.Bip32Derivation {
+		if err := writeKVPair(w, inputBip32DerivationType, d.PubKey, serializeBip32Derivation(d)); err != nil {
+			return err
+		}
+	}
+	if pi.FinalScriptSig != nil {
+		if err := writeKVPair(w, inputFinalScriptSigType, nil, pi.FinalScriptSig); err != nil {
+			return err
+		}
+	}
+	if pi.FinalScriptWitness != nil {
+		if err := writeKVPair(w, inputFinalScriptWitnessType, nil, pi.FinalScriptWitness); err != nil {
+			return err
+		}
+	}
+	if err := writeUnknowns(w, pi.Unknowns); err != nil {
+		return err
+	}
+	return writeSeparator(w)
+}
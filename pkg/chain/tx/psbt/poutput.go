@@ -0,0 +1,85 @@
+package psbt
+
+import (
+	"io"
+)
+
+// The key types of an output map.
+const (
+	outputRedeemScriptType    = 0x00
+	outputWitnessScriptType   = 0x01
+	outputBip32DerivationType = 0x02
+)
+
+// POutput is the map of an output of a packet, which tells a signer what the output pays to so it can check change
+// outputs are its own.
+type POutput struct {
+	RedeemScript    []byte
+	WitnessScript   []byte
+	Bip32Derivation []*Bip32Derivation
+	Unknowns        []*Unknown
+}
+
+// deserialize reads the output map up to and including its separator.
+func (po *POutput) deserialize(r io.Reader) error {
+	seen := make(keySet)
+	for {
+		key, value, err := readKVPair(r)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return nil
+		}
+		if err = seen.add(key); err != nil {
+			return err
+		}
+		keyType, keyData := key[0], key[1:]
+		switch keyType {
+		case outputRedeemScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			po.RedeemScript = value
+		case outputWitnessScriptType:
+			if len(keyData) != 0 {
+				return ErrInvalidKeyData
+			}
+			po.WitnessScript = value
+		case outputBip32DerivationType:
+			if !validPubKey(keyData) {
+				return ErrInvalidKeyData
+			}
+			d, err := readBip32Derivation(keyData, value)
+			if err != nil {
+				return err
+			}
+			po.Bip32Derivation = append(po.Bip32Derivation, d)
+		default:
+			po.Unknowns = append(po.Unknowns, &Unknown{Key: key, Value: value})
+		}
+	}
+}
+
+// serialize writes the output map followed by its separator.
+func (po *POutput) serialize(w io.Writer) error {
+	if po.RedeemScript != nil {
+		if err := writeKVPair(w, outputRedeemScriptType, nil, po.RedeemScript); err != nil {
+			return err
+		}
+	}
+	if po.WitnessScript != nil {
+		if err := writeKVPair(w, outputWitnessScriptType, nil, po.WitnessScript); err != nil {
+			return err
+		}
+	}
+	for _, d := range po.Bip32Derivation {
+		if err := writeKVPair(w, outputBip32DerivationType, d.PubKey, serializeBip32Derivation(d)); err != nil {
+			return err
+		}
+	}
+	if err := writeUnknowns(w, po.Unknowns); err != nil {
+		return err
+	}
+	return writeSeparator(w)
+}
@@ -0,0 +1,221 @@
+// Package psbt implements the partially signed transaction format of BIP174. A packet carries an unsigned transaction
+// along with what each party needs to sign it, such as the outputs its inputs spend and their redeem scripts, so a
+// watch-only wallet can create a transaction that a cold-storage wallet signs, or the cosigners of a multisig address
+// can each add their signature, before the transaction is finalized and extracted for broadcast.
+package psbt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// globalUnsignedTxType is the key type of the unsigned transaction in the global map.
+const globalUnsignedTxType = 0x00
+
+// magic is the prefix of every serialized packet, "psbt" followed by 0xff.
+var magic = [5]byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+var (
+	// ErrInvalidMagicBytes is returned when the data does not start with the packet prefix.
+	ErrInvalidMagicBytes = errors.New("invalid PSBT magic bytes")
+	// ErrInvalidPsbtFormat is returned when the data of a packet is malformed.
+	ErrInvalidPsbtFormat = errors.New("invalid PSBT serialization format")
+	// ErrDuplicateKey is returned when a key appears twice in a map of a packet.
+	ErrDuplicateKey = errors.New("duplicate key in PSBT")
+	// ErrInvalidKeyData is returned when the key data of a key-value pair does not match its key type.
+	ErrInvalidKeyData = errors.New("invalid key data in PSBT")
+	// ErrNoUnsignedTx is returned when a packet does not hold an unsigned transaction.
+	ErrNoUnsignedTx = errors.New("PSBT has no unsigned transaction")
+	// ErrInvalidRawTxSigned is returned when the transaction of a packet has signature scripts or witnesses.
+	ErrInvalidRawTxSigned = errors.New("PSBT transaction has signature scripts or witnesses")
+	// ErrInvalidPrevOutNonWitnessTransaction is returned when the non-witness utxo of an input is not the
+	// transaction the input spends from.
+	ErrInvalidPrevOutNonWitnessTransaction = errors.New("PSBT non-witness utxo does not match the input outpoint")
+	// ErrUnsignedTxMismatch is returned when packets for different transactions are combined.
+	ErrUnsignedTxMismatch = errors.New("PSBTs are for different transactions")
+	// ErrMissingUtxo is returned when the output an input spends is not in the packet.
+	ErrMissingUtxo = errors.New("PSBT input is missing the output it spends")
+	// ErrIncompletePsbt is returned when a transaction is extracted from a packet whose inputs are not all finalized.
+	ErrIncompletePsbt = errors.New("PSBT is not finalized")
+)
+
+// Packet is a partially signed transaction. It holds the unsigned transaction, the global unknown key-value pairs
+// and a map for each input and output of the transaction.
+type Packet struct {
+	UnsignedTx *wire.MsgTx
+	Inputs     []PInput
+	Outputs    []POutput
+	Unknowns   []*Unknown
+}
+
+// NewFromUnsignedTx returns a packet for the passed transaction with empty input and output maps. The transaction
+// must not have signature scripts or witnesses.
+func NewFromUnsignedTx(tx *wire.MsgTx) (*Packet, error) {
+	if err := checkUnsigned(tx); err != nil {
+		return nil, err
+	}
+	return &Packet{
+		UnsignedTx: tx,
+		Inputs:     make([]PInput, len(tx.TxIn)),
+		Outputs:    make([]POutput, len(tx.TxOut)),
+	}, nil
+}
+
+// NewFromRawBytes decodes a packet from the reader, which holds the binary serialization or, if b64 is set, its base64
+// encoding.
+func NewFromRawBytes(r io.Reader, b64 bool) (*Packet, error) {
+	if b64 {
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	}
+	var prefix [5]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil || prefix != magic {
+		return nil, ErrInvalidMagicBytes
+	}
+	p := &Packet{}
+	seen := make(keySet)
+	for {
+		key, value, err := readKVPair(r)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			break
+		}
+		if err = seen.add(key); err != nil {
+			return nil, err
+		}
+		if key[0] != globalUnsignedTxType {
+			p.Unknowns = append(p.Unknowns, &Unknown{Key: key, Value: value})
+			continue
+		}
+		if len(key) != 1 {
+			return nil, ErrInvalidKeyData
+		}
+		tx := &wire.MsgTx{}
+		if err = tx.DeserializeNoWitness(bytes.NewReader(value)); err != nil {
+			return nil, ErrInvalidPsbtFormat
+		}
+		if err = checkUnsigned(tx); err != nil {
+			return nil, err
+		}
+		p.UnsignedTx = tx
+	}
+	if p.UnsignedTx == nil {
+		return nil, ErrNoUnsignedTx
+	}
+	p.Inputs = make([]PInput, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		if err := p.Inputs[i].deserialize(r); err != nil {
+			return nil, err
+		}
+		utxo := p.Inputs[i].NonWitnessUtxo
+		if utxo != nil && utxo.TxHash() != p.UnsignedTx.TxIn[i].PreviousOutPoint.Hash {
+			return nil, ErrInvalidPrevOutNonWitnessTransaction
+		}
+	}
+	p.Outputs = make([]POutput, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		if err := p.Outputs[i].deserialize(r); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Serialize writes the binary serialization of the packet.
+func (p *Packet) Serialize(w io.Writer) error {
+	if _, err := w.Write(magic[:]); err != nil {
+		return err
+	}
+	var tx bytes.Buffer
+	if err := p.UnsignedTx.SerializeNoWitness(&tx); err != nil {
+		return err
+	}
+	if err := writeKVPair(w, globalUnsignedTxType, nil, tx.Bytes()); err != nil {
+		return err
+	}
+	if err := writeUnknowns(w, p.Unknowns); err != nil {
+		return err
+	}
+	if err := writeSeparator(w); err != nil {
+		return err
+	}
+	for i := range p.Inputs {
+		if err := p.Inputs[i].serialize(w); err != nil {
+			return err
+		}
+	}
+	for i := range p.Outputs {
+		if err := p.Outputs[i].serialize(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// B64Encode returns the base64 encoding of the packet, which is how packets are passed over RPC.
+func (p *Packet) B64Encode() (string, error) {
+	var buf bytes.Buffer
+	if err := p.Serialize(&buf); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// IsComplete returns whether every input of the packet is finalized, so the signed transaction can be extracted.
+func (p *Packet) IsComplete() bool {
+	for i := range p.Inputs {
+		if !p.Inputs[i].IsFinalized() {
+			return false
+		}
+	}
+	return true
+}
+
+// Fee returns the fee paid by the transaction of the packet. ErrMissingUtxo is returned if the output spent by any of
+// the inputs is not in the packet.
+func (p *Packet) Fee() (util.Amount, error) {
+	var fee int64
+	for i := range p.Inputs {
+		prevOut, err := p.prevOutput(i)
+		if err != nil {
+			return 0, err
+		}
+		fee += prevOut.Value
+	}
+	for _, txOut := range p.UnsignedTx.TxOut {
+		fee -= txOut.Value
+	}
+	return util.Amount(fee), nil
+}
+
+// prevOutput returns the output spent by input idx, taken from its witness utxo or its non-witness utxo.
+func (p *Packet) prevOutput(idx int) (*wire.TxOut, error) {
+	in := &p.Inputs[idx]
+	if in.WitnessUtxo != nil {
+		return in.WitnessUtxo, nil
+	}
+	if in.NonWitnessUtxo != nil {
+		prevIndex := p.UnsignedTx.TxIn[idx].PreviousOutPoint.Index
+		if int(prevIndex) >= len(in.NonWitnessUtxo.TxOut) {
+			return nil, ErrInvalidPrevOutNonWitnessTransaction
+		}
+		return in.NonWitnessUtxo.TxOut[prevIndex], nil
+	}
+	return nil, ErrMissingUtxo
+}
+
+// checkUnsigned returns ErrInvalidRawTxSigned if any input of the transaction has a signature script or witness.
+func checkUnsigned(tx *wire.MsgTx) error {
+	for _, txIn := range tx.TxIn {
+		if len(txIn.SignatureScript) != 0 || len(txIn.Witness) != 0 {
+			return ErrInvalidRawTxSigned
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package psbt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	ec "github.com/p9c/pod/pkg/coding/elliptic"
+	"github.com/p9c/pod/pkg/util"
+)
+
+var (
+	// ErrMissingRedeemScript is returned when an input spending a pay-to-script-hash output has no redeem script.
+	ErrMissingRedeemScript = errors.New("PSBT input is missing its redeem script")
+	// ErrMissingWitnessScript is returned when an input spending a pay-to-witness-script-hash output has no witness
+	// script.
+	ErrMissingWitnessScript = errors.New("PSBT input is missing its witness script")
+	// ErrScriptMismatch is returned when the redeem or witness script of an input does not hash to the output it
+	// spends.
+	ErrScriptMismatch = errors.New("PSBT input script does not match the output it spends")
+)
+
+// SigningScript returns the script that the signatures of input idx commit to, which is the script of the output it
+// spends, or its redeem script or witness script when the output pays to a script hash, and whether the input is
+// signed as defined by BIP0143 for witness programs. A pay-to-witness-pubkey-hash program is returned as is, the
+// signature hash functions of txscript substitute the pay-to-pubkey-hash script it stands for.
+func (p *Packet) SigningScript(idx int) (script []byte, witness bool, err error) {
+	prevOut, err := p.prevOutput(idx)
+	if err != nil {
+		return nil, false, err
+	}
+	in := &p.Inputs[idx]
+	script = prevOut.PkScript
+	if txscript.IsPayToScriptHash(script) {
+		if in.RedeemScript == nil {
+			return nil, false, ErrMissingRedeemScript
+		}
+		// A pay-to-script-hash script is OP_HASH160 <20 byte hash> OP_EQUAL.
+		if !bytes.Equal(util.Hash160(in.RedeemScript), script[2:22]) {
+			return nil, false, ErrScriptMismatch
+		}
+		script = in.RedeemScript
+	}
+	switch {
+	case txscript.IsPayToWitnessScriptHash(script):
+		if in.WitnessScript == nil {
+			return nil, false, ErrMissingWitnessScript
+		}
+		// A pay-to-witness-script-hash script is OP_0 <32 byte hash>.
+		hash := sha256.Sum256(in.WitnessScript)
+		if !bytes.Equal(hash[:], script[2:]) {
+			return nil, false, ErrScriptMismatch
+		}
+		return in.WitnessScript, true, nil
+	case txscript.IsPayToWitnessPubKeyHash(script):
+		return script, true, nil
+	}
+	return script, false, nil
+}
+
+// SignInput signs input idx with the private key and adds the signature to the partial signatures of the input,
+// replacing an earlier signature of the same key. The public key is added in its compressed or uncompressed form as
+// given by compressed, which must match the form the script of the input commits to. The sighash type of the input is
+// used, or SigHashAll if it has none. The caller is responsible for the key being one the script of the input needs.
+func (p *Packet) SignInput(idx int, key *ec.PrivateKey, compressed bool) error {
+	script, witness, err := p.SigningScript(idx)
+	if err != nil {
+		return err
+	}
+	hashType := p.Inputs[idx].SighashType
+	if hashType == 0 {
+		hashType = txscript.SigHashAll
+	}
+	var sig []byte
+	if witness {
+		// The output was already found by SigningScript, so this can't fail.
+		prevOut, _ := p.prevOutput(idx)
+		sig, err = txscript.RawTxInWitnessSignature(p.UnsignedTx, txscript.NewTxSigHashes(p.UnsignedTx), idx,
+			prevOut.Value, script, hashType, key)
+	} else {
+		sig, err = txscript.RawTxInSignature(p.UnsignedTx, idx, script, hashType, key)
+	}
+	if err != nil {
+		return err
+	}
+	pubKey := key.PubKey().SerializeUncompressed()
+	if compressed {
+		pubKey = key.PubKey().SerializeCompressed()
+	}
+	p.Inputs[idx].addPartialSig(&PartialSig{PubKey: pubKey, Signature: sig})
+	return nil
+}
@@ -13,6 +13,10 @@ import (
 // DefaultRelayFeePerKb is the default minimum relay fee policy for a mempool.
 const DefaultRelayFeePerKb util.Amount = 1e3
 
+// MaxRBFSequence is the highest input sequence number that signals the transaction may be replaced by one paying a
+// higher fee, as defined by BIP125.
+const MaxRBFSequence = 0xfffffffd
+
 // Transaction rule violations
 var (
 	ErrAmountNegative   = errors.New("transaction output amount is negative")
@@ -0,0 +1,785 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// A utxo set snapshot is a file holding the utxo set as of a block in the main chain, along with the headers of the
+// chain up to that block, so a new node can start from it instead of from the genesis block.
+//
+// The serialized format is:
+//
+//   <magic><version><base hash><base height><total txns><txouts><hash serialized><headers><base block><entries>
+//   Field             Type               Size
+//   magic             [4]byte            4 bytes
+//   version           uint32             4 bytes
+//   base hash         chainhash.Hash     chainhash.HashSize
+//   base height       uint32             4 bytes
+//   total txns        uint64             8 bytes
+//   txouts            uint64             8 bytes
+//   hash serialized   chainhash.Hash     chainhash.HashSize
+//   headers           []wire.BlockHeader the headers from height 1 to base height - 1
+//   base block        wire.MsgBlock      variable
+//   entries           []entry            txouts entries
+//
+// Each entry is the key and value of the utxo in the utxo set bucket, in the order of the bucket:
+//
+//   <key length><key><value length><value>
+//   Field             Type               Size
+//   key length        VarInt             variable
+//   key               []byte             key length
+//   value length      VarInt             variable
+//   value             []byte             value length
+//
+// The integers of the snapshot header are little-endian. The hash serialized is the double sha256 of the entries, which
+// is the same as the hash reported for the utxo set by TxOutSetInfo.
+// -----------------------------------------------------------------------------
+
+const (
+	// txOutSetVersion is the version of the utxo set snapshot format
+	txOutSetVersion = 1
+	// txOutSetHeaderSize is the size of the fixed fields at the start of a utxo set snapshot
+	txOutSetHeaderSize = 4 + 4 + chainhash.HashSize + 4 + 8 + 8 + chainhash.HashSize
+	// maxUtxoKeySize is the largest key an entry of a snapshot may have
+	maxUtxoKeySize = chainhash.HashSize + 5
+	// maxUtxoValueSize is the largest value an entry of a snapshot may have
+	maxUtxoValueSize = wire.MaxBlockPayload
+)
+
+var (
+	// txOutSetBatchSize is how many utxos are written or deleted in each database transaction when a snapshot is loaded
+	// or a utxo set is cleared, so that a large utxo set is not held in memory by a single transaction
+	txOutSetBatchSize = 50000
+	// txOutSetMagic is the magic at the start of a utxo set snapshot
+	txOutSetMagic = [4]byte{'t', 'x', 'o', 's'}
+	// txOutSetSnapshotKeyName is the key used to record that the chain state was loaded from a utxo set snapshot. It
+	// holds the hash of the block the snapshot was taken at.
+	txOutSetSnapshotKeyName = []byte("txoutsetsnapshot")
+	// txOutSetHistoryKeyName is the key used to record the state of the validation of the blocks below the utxo set
+	// snapshot the chain state was loaded from, until it is finished.
+	txOutSetHistoryKeyName = []byte("txoutsethistory")
+	// txOutSetHistoryBucketName is the name of the bucket holding the utxo set rebuilt from the blocks below the utxo
+	// set snapshot the chain state was loaded from while they are validated.
+	txOutSetHistoryBucketName = []byte("txoutsethistoryutxo")
+)
+
+// TxOutSetInfo is the summary of the utxo set as of a block in the main chain.
+type TxOutSetInfo struct {
+	Height          int32
+	BestBlock       chainhash.Hash
+	Transactions    uint64
+	TxOuts          uint64
+	BytesSerialized uint64
+	HashSerialized  chainhash.Hash
+	TotalAmount     util.Amount
+}
+
+// txOutSetHasher computes the hash serialized of a utxo set from its entries.
+type txOutSetHasher struct {
+	hash.Hash
+}
+
+func newTxOutSetHasher() *txOutSetHasher {
+	return &txOutSetHasher{Hash: sha256.New()}
+}
+
+// add writes an entry to the hash in its snapshot serialization.
+func (h *txOutSetHasher) add(key, value []byte) {
+	_ = wire.WriteVarBytes(h, 0, key)
+	_ = wire.WriteVarBytes(h, 0, value)
+}
+
+// sum returns the double sha256 of the entries written.
+func (h *txOutSetHasher) sum() chainhash.Hash {
+	return chainhash.HashH(h.Sum(nil))
+}
+
+// dbFetchTxOutSetInfo uses an existing database transaction to compute the summary of the utxo set as of the best
+// block stored with it.
+func dbFetchTxOutSetInfo(dbTx database.Tx) (*TxOutSetInfo, error) {
+	state, err := deserializeBestChainState(dbTx.Metadata().Get(chainStateKeyName))
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	info := &TxOutSetInfo{Height: int32(state.height), BestBlock: state.hash}
+	if err = summarizeTxOutSet(dbTx.Metadata().Bucket(utxoSetBucketName), info); err != nil {
+		Error(err)
+		return nil, err
+	}
+	return info, nil
+}
+
+// summarizeTxOutSet adds the entries of the utxo set held by the bucket to the summary and sets its hash serialized.
+func summarizeTxOutSet(bucket database.Bucket, info *TxOutSetInfo) error {
+	hasher := newTxOutSetHasher()
+	var prevTx chainhash.Hash
+	cursor := bucket.Cursor()
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, value := cursor.Key(), cursor.Value()
+		if len(key) <= chainhash.HashSize {
+			return errDeserialize(fmt.Sprintf("utxo key of %d bytes is too short", len(key)))
+		}
+		entry, err := deserializeUtxoEntry(value)
+		if err != nil {
+			return err
+		}
+		// Keys start with the transaction hash so the outputs of each transaction are together.
+		if info.TxOuts == 0 || !bytes.Equal(prevTx[:], key[:chainhash.HashSize]) {
+			copy(prevTx[:], key)
+			info.Transactions++
+		}
+		info.TxOuts++
+		info.BytesSerialized += uint64(len(key) + len(value))
+		info.TotalAmount += util.Amount(entry.Amount())
+		hasher.add(key, value)
+	}
+	info.HashSerialized = hasher.sum()
+	return nil
+}
+
+// TxOutSetInfo returns the summary of the utxo set as of the current best block, including the number of unspent
+// outputs, their total amount and a hash that is the same for every node with the same utxo set.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) TxOutSetInfo() (info *TxOutSetInfo, err error) {
+	err = b.db.View(func(dbTx database.Tx) error {
+		info, err = dbFetchTxOutSetInfo(dbTx)
+		return err
+	})
+	return
+}
+
+// DumpTxOutSet writes a snapshot of the utxo set as of the current best block to w and returns its summary.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) DumpTxOutSet(w io.Writer) (info *TxOutSetInfo, err error) {
+	err = b.db.View(func(dbTx database.Tx) error {
+		// The summary is computed first so that the snapshot header is written before the entries. Both are read from
+		// the same database transaction so the best block does not change in between.
+		var err error
+		if info, err = dbFetchTxOutSetInfo(dbTx); err != nil {
+			return err
+		}
+		state, err := deserializeBestChainState(dbTx.Metadata().Get(chainStateKeyName))
+		if err != nil {
+			return err
+		}
+		base := b.Index.LookupNode(&info.BestBlock)
+		if base == nil {
+			return AssertError(fmt.Sprintf("DumpTxOutSet: cannot find best block %s in block index", info.BestBlock))
+		}
+		if base.height == 0 {
+			return errors.New("a utxo set snapshot cannot be taken at the genesis block")
+		}
+		block, err := dbFetchBlockByNode(dbTx, base)
+		if err != nil {
+			return err
+		}
+		var hdr [txOutSetHeaderSize]byte
+		copy(hdr[:], txOutSetMagic[:])
+		offset := 4
+		byteOrder.PutUint32(hdr[offset:], txOutSetVersion)
+		offset += 4
+		copy(hdr[offset:], base.hash[:])
+		offset += chainhash.HashSize
+		byteOrder.PutUint32(hdr[offset:], uint32(base.height))
+		offset += 4
+		byteOrder.PutUint64(hdr[offset:], state.totalTxns)
+		offset += 8
+		byteOrder.PutUint64(hdr[offset:], info.TxOuts)
+		offset += 8
+		copy(hdr[offset:], info.HashSerialized[:])
+		if _, err = w.Write(hdr[:]); err != nil {
+			return err
+		}
+		for height := int32(1); height < base.height; height++ {
+			header := base.Ancestor(height).Header()
+			if err = header.Serialize(w); err != nil {
+				return err
+			}
+		}
+		if err = block.MsgBlock().Serialize(w); err != nil {
+			return err
+		}
+		cursor := dbTx.Metadata().Bucket(utxoSetBucketName).Cursor()
+		for ok := cursor.First(); ok; ok = cursor.Next() {
+			if err = wire.WriteVarBytes(w, 0, cursor.Key()); err != nil {
+				return err
+			}
+			if err = wire.WriteVarBytes(w, 0, cursor.Value()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return info, nil
+}
+
+// LoadTxOutSet replaces the chain state of a chain that has only the genesis block with a utxo set snapshot written by
+// DumpTxOutSet, making the block the snapshot was taken at the best block. If expected is not nil the hash serialized
+// of the snapshot must match it.
+//
+// The headers the snapshot holds are checked as the headers of downloaded blocks are, for their proof of work,
+// difficulty and the checkpoints, but the utxo set is trusted until the blocks below the snapshot block have been
+// downloaded and validated in the background, which ProcessHistoryBlock does. The optional indexes cannot be built
+// without these blocks, so they must be disabled.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) LoadTxOutSet(r io.Reader, expected *chainhash.Hash) (*TxOutSetInfo, error) {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+	if b.BestChain.Height() != 0 {
+		return nil, errors.New("a utxo set snapshot can only be loaded by a chain that has only the genesis block")
+	}
+	if b.indexManager != nil {
+		return nil, errors.New("a utxo set snapshot cannot be loaded while optional indexes are enabled")
+	}
+	var hdr [txOutSetHeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		Error(err)
+		return nil, err
+	}
+	if !bytes.Equal(hdr[:4], txOutSetMagic[:]) {
+		return nil, errors.New("not a utxo set snapshot")
+	}
+	offset := 4
+	if version := byteOrder.Uint32(hdr[offset:]); version != txOutSetVersion {
+		return nil, fmt.Errorf("utxo set snapshot version %d is not supported", version)
+	}
+	offset += 4
+	info := &TxOutSetInfo{}
+	copy(info.BestBlock[:], hdr[offset:])
+	offset += chainhash.HashSize
+	info.Height = int32(byteOrder.Uint32(hdr[offset:]))
+	offset += 4
+	totalTxns := byteOrder.Uint64(hdr[offset:])
+	offset += 8
+	txOuts := byteOrder.Uint64(hdr[offset:])
+	offset += 8
+	var hashSerialized chainhash.Hash
+	copy(hashSerialized[:], hdr[offset:])
+	if expected != nil && !expected.IsEqual(&hashSerialized) {
+		return nil, fmt.Errorf("utxo set snapshot hash %s is not the expected %s", hashSerialized, expected)
+	}
+	if info.Height < 1 {
+		return nil, errors.New("utxo set snapshot is at the genesis block")
+	}
+	// Build the block nodes of the chain up to the snapshot block from the headers.
+	nodes := make([]*BlockNode, 0, info.Height)
+	prev := b.BestChain.Genesis()
+	for height := int32(1); height < info.Height; height++ {
+		var header wire.BlockHeader
+		if err := header.Deserialize(r); err != nil {
+			Error(err)
+			return nil, err
+		}
+		if err := b.checkSnapshotHeader(&header, prev); err != nil {
+			Error(err)
+			return nil, err
+		}
+		prev = NewBlockNode(&header, prev)
+		prev.status = statusValid
+		nodes = append(nodes, prev)
+	}
+	var msgBlock wire.MsgBlock
+	if err := msgBlock.Deserialize(r); err != nil {
+		Error(err)
+		return nil, err
+	}
+	block := util.NewBlock(&msgBlock)
+	block.SetHeight(info.Height)
+	if !block.Hash().IsEqual(&info.BestBlock) {
+		return nil, errors.New("utxo set snapshot block is not the block the snapshot was taken at")
+	}
+	if err := b.checkSnapshotHeader(&msgBlock.Header, prev); err != nil {
+		Error(err)
+		return nil, err
+	}
+	merkles := BuildMerkleTreeStore(block.Transactions(), false)
+	if !msgBlock.Header.MerkleRoot.IsEqual(merkles[len(merkles)-1]) {
+		return nil, errors.New("utxo set snapshot block merkle root does not match its transactions")
+	}
+	base := NewBlockNode(&msgBlock.Header, prev)
+	base.status = statusDataStored | statusValid
+	nodes = append(nodes, base)
+	state := newBestState(
+		base, uint64(msgBlock.SerializeSize()), uint64(GetBlockWeight(block)),
+		uint64(len(msgBlock.Transactions)), totalTxns, base.CalcPastMedianTime(),
+	)
+	// A chain that has only the genesis block has no utxos, so any entries are left by a load that was interrupted.
+	if err := clearBucket(b.db, utxoSetBucketName); err != nil {
+		Error(err)
+		return nil, err
+	}
+	err := b.loadTxOutSetEntries(r, txOuts, info)
+	if err == nil && !info.HashSerialized.IsEqual(&hashSerialized) {
+		err = fmt.Errorf("utxo set snapshot entries hash to %s, not %s", info.HashSerialized, hashSerialized)
+	}
+	if err != nil {
+		Error(err)
+		if clearErr := clearBucket(b.db, utxoSetBucketName); clearErr != nil {
+			Error(clearErr)
+		}
+		return nil, err
+	}
+	history := &snapshotHistory{base: base, next: 1, hashSerialized: hashSerialized}
+	err = b.db.Update(func(dbTx database.Tx) error {
+		for _, node := range nodes {
+			if err := dbStoreBlockNode(dbTx, node); err != nil {
+				return err
+			}
+			if err := dbPutBlockIndex(dbTx, &node.hash, node.height); err != nil {
+				return err
+			}
+		}
+		if err := dbStoreBlock(dbTx, block); err != nil {
+			return err
+		}
+		meta := dbTx.Metadata()
+		if err := meta.Put(txOutSetSnapshotKeyName, base.hash[:]); err != nil {
+			return err
+		}
+		if err := meta.Put(txOutSetHistoryKeyName, serializeSnapshotHistory(history)); err != nil {
+			return err
+		}
+		if _, err := meta.CreateBucketIfNotExists(txOutSetHistoryBucketName); err != nil {
+			return err
+		}
+		return dbPutBestState(dbTx, state, base.workSum)
+	})
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	b.Index.Lock()
+	for _, node := range nodes {
+		b.Index.addNode(node)
+	}
+	b.Index.Unlock()
+	b.BestChain.SetTip(base)
+	b.history = history
+	b.stateLock.Lock()
+	b.stateSnapshot = state
+	b.stateLock.Unlock()
+	// There are no blocks to download below a snapshot of the first block after the genesis block.
+	if base.height == 1 {
+		if err = b.finishSnapshotHistory(); err != nil {
+			return nil, err
+		}
+	}
+	Infof(
+		"loaded utxo set snapshot of %d outputs at block %s (height %d)", info.TxOuts, info.BestBlock,
+		info.Height,
+	)
+	return info, nil
+}
+
+// checkSnapshotHeader checks that a header of a utxo set snapshot connects to the header before it, has the proof of
+// work and difficulty the chain requires of it and matches the checkpoints, as ProcessBlock does for downloaded blocks.
+func (b *BlockChain) checkSnapshotHeader(header *wire.BlockHeader, prev *BlockNode) error {
+	height := prev.height + 1
+	if header.PrevBlock != prev.hash {
+		return fmt.Errorf("utxo set snapshot header at height %d does not connect to the chain", height)
+	}
+	return b.checkHeaderWork(header, prev)
+}
+
+// loadTxOutSetEntries reads the entries of a utxo set snapshot into the utxo set, adding them to the summary. The
+// entries are written in batches of txOutSetBatchSize so a database transaction never holds more than one batch.
+func (b *BlockChain) loadTxOutSetEntries(r io.Reader, txOuts uint64, info *TxOutSetInfo) error {
+	hasher := newTxOutSetHasher()
+	var prevTx chainhash.Hash
+	for i := uint64(0); i < txOuts; {
+		err := b.db.Update(func(dbTx database.Tx) error {
+			utxoBucket := dbTx.Metadata().Bucket(utxoSetBucketName)
+			for n := 0; n < txOutSetBatchSize && i < txOuts; n++ {
+				key, err := wire.ReadVarBytes(r, 0, maxUtxoKeySize, "utxo key")
+				if err != nil {
+					return err
+				}
+				value, err := wire.ReadVarBytes(r, 0, maxUtxoValueSize, "utxo value")
+				if err != nil {
+					return err
+				}
+				if len(key) <= chainhash.HashSize {
+					return errDeserialize(fmt.Sprintf("utxo key of %d bytes is too short", len(key)))
+				}
+				entry, err := deserializeUtxoEntry(value)
+				if err != nil {
+					return err
+				}
+				if i == 0 || !bytes.Equal(prevTx[:], key[:chainhash.HashSize]) {
+					copy(prevTx[:], key)
+					info.Transactions++
+				}
+				info.TxOuts++
+				info.BytesSerialized += uint64(len(key) + len(value))
+				info.TotalAmount += util.Amount(entry.Amount())
+				hasher.add(key, value)
+				if err = utxoBucket.Put(key, value); err != nil {
+					return err
+				}
+				i++
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	info.HashSerialized = hasher.sum()
+	return nil
+}
+
+// clearBucket deletes the entries of a bucket of the metadata in batches of txOutSetBatchSize.
+func clearBucket(db database.DB, name []byte) error {
+	for done := false; !done; {
+		err := db.Update(func(dbTx database.Tx) error {
+			bucket := dbTx.Metadata().Bucket(name)
+			if bucket == nil {
+				done = true
+				return nil
+			}
+			keys := make([][]byte, 0, txOutSetBatchSize)
+			cursor := bucket.Cursor()
+			for ok := cursor.First(); ok && len(keys) < txOutSetBatchSize; ok = cursor.Next() {
+				keys = append(keys, cursor.Key())
+			}
+			done = len(keys) < txOutSetBatchSize
+			for _, key := range keys {
+				if err := bucket.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// snapshotHistory is the state of the validation of the blocks below the utxo set snapshot the chain state was loaded
+// from. The blocks are connected in order to a utxo set of their own, which must hash to the hash serialized of the
+// snapshot once the snapshot block is connected to it.
+type snapshotHistory struct {
+	base           *BlockNode
+	next           int32
+	hashSerialized chainhash.Hash
+	failed         bool
+}
+
+// serializeSnapshotHistory returns the serialization of the state of the validation of the blocks below a utxo set
+// snapshot, which is:
+//
+//   <next height><hash serialized><failed>
+//   Field             Type             Size
+//   next height       uint32           4 bytes
+//   hash serialized   chainhash.Hash   chainhash.HashSize
+//   failed            bool             1 byte
+func serializeSnapshotHistory(h *snapshotHistory) []byte {
+	serialized := make([]byte, 4+chainhash.HashSize+1)
+	byteOrder.PutUint32(serialized, uint32(h.next))
+	copy(serialized[4:], h.hashSerialized[:])
+	if h.failed {
+		serialized[4+chainhash.HashSize] = 1
+	}
+	return serialized
+}
+
+// deserializeSnapshotHistory parses the state of the validation of the blocks below a utxo set snapshot serialized by
+// serializeSnapshotHistory. The base node is not part of it.
+func deserializeSnapshotHistory(serialized []byte) (*snapshotHistory, error) {
+	if len(serialized) != 4+chainhash.HashSize+1 {
+		return nil, database.DBError{
+			ErrorCode:   database.ErrCorruption,
+			Description: "corrupt utxo set snapshot history state",
+		}
+	}
+	h := &snapshotHistory{next: int32(byteOrder.Uint32(serialized))}
+	copy(h.hashSerialized[:], serialized[4:])
+	h.failed = serialized[4+chainhash.HashSize] != 0
+	return h, nil
+}
+
+// initSnapshotHistory loads the state of the validation of the blocks below the utxo set snapshot the chain state was
+// loaded from, if it is not finished.
+func (b *BlockChain) initSnapshotHistory() error {
+	return b.db.View(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		baseHash, serialized := meta.Get(txOutSetSnapshotKeyName), meta.Get(txOutSetHistoryKeyName)
+		if len(baseHash) != chainhash.HashSize || serialized == nil {
+			return nil
+		}
+		h, err := deserializeSnapshotHistory(serialized)
+		if err != nil {
+			return err
+		}
+		var hash chainhash.Hash
+		copy(hash[:], baseHash)
+		if h.base = b.Index.LookupNode(&hash); h.base == nil {
+			return AssertError(fmt.Sprintf("initSnapshotHistory: cannot find snapshot block %s in block index", hash))
+		}
+		if h.failed {
+			Errorf(
+				"the blocks below the utxo set snapshot at block %s (height %d) do not match it, the chain state is"+
+					" not valid and must be rebuilt from the genesis block", h.base.hash, h.base.height,
+			)
+		}
+		b.history = h
+		return nil
+	})
+}
+
+// SnapshotHistory returns the height of the next block below the utxo set snapshot the chain state was loaded from to
+// be passed to ProcessHistoryBlock, and the height of the snapshot block. Both are zero if there are no blocks left to
+// validate, including when the blocks were found not to match the snapshot.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) SnapshotHistory() (next, base int32) {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+	if h := b.history; h != nil && !h.failed && h.next < h.base.height {
+		return h.next, h.base.height
+	}
+	return 0, 0
+}
+
+// ProcessHistoryBlock validates the next block below the utxo set snapshot the chain state was loaded from, the one at
+// the height SnapshotHistory returns, and connects it to the utxo set rebuilt from the blocks before it. The block and
+// its spend journal are stored as they are for the blocks of the main chain. After the block before the snapshot
+// block, the snapshot block itself is connected, and the rebuilt utxo set must then match the snapshot, which from
+// then on is known to be valid.
+//
+// A block that does not pass the checks that only depend on the block itself may have been corrupted by the peer it
+// came from, and is only rejected. A block that does, but spends outputs it cannot or otherwise breaks the consensus
+// rules, shows the snapshot to be invalid, as does a rebuilt utxo set that does not match it, and ends the validation.
+//
+// This function is safe for concurrent access.
+func (b *BlockChain) ProcessHistoryBlock(block *util.Block) error {
+	b.chainLock.Lock()
+	defer b.chainLock.Unlock()
+	h := b.history
+	if h == nil || h.failed || h.next >= h.base.height {
+		return errors.New("there are no blocks below a utxo set snapshot to be validated")
+	}
+	node := h.base.Ancestor(h.next)
+	if !block.Hash().IsEqual(&node.hash) {
+		return fmt.Errorf("block %s is not the block at height %d below the utxo set snapshot", block.Hash(), h.next)
+	}
+	block.SetHeight(node.height)
+	algo := block.MsgBlock().Header.Version
+	if fork.GetCurrent(node.height) == 0 && algo != 514 {
+		algo = 2
+	}
+	powLimit := fork.GetMinDiff(fork.GetAlgoName(algo, node.height), node.height)
+	if err := checkBlockSanity(block, powLimit, b.timeSource, BFNone, false, node.height); err != nil {
+		Error(err)
+		return err
+	}
+	if err := b.checkBlockContext(0, block, node.parent, BFNone, false); err != nil {
+		Error(err)
+		return err
+	}
+	if err := b.connectHistoryBlock(node, block); err != nil {
+		return err
+	}
+	if h.next < h.base.height {
+		return nil
+	}
+	return b.finishSnapshotHistory()
+}
+
+// finishSnapshotHistory connects the snapshot block to the utxo set rebuilt from the blocks below it, once they are all
+// connected, and checks that the utxo set matches the snapshot. If it does the snapshot is no longer trusted but known
+// to be valid, and the chain state is from then on the same as that of a chain that connected every block. This
+// function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) finishSnapshotHistory() error {
+	h := b.history
+	// The snapshot block was stored when the snapshot was loaded, and is connected as soon as its parent is.
+	var baseBlock *util.Block
+	err := b.db.View(func(dbTx database.Tx) error {
+		var err error
+		baseBlock, err = dbFetchBlockByNode(dbTx, h.base)
+		return err
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	if err = b.connectHistoryBlock(h.base, baseBlock); err != nil {
+		return err
+	}
+	var hashSerialized chainhash.Hash
+	err = b.db.View(func(dbTx database.Tx) error {
+		info := &TxOutSetInfo{}
+		if err := summarizeTxOutSet(dbTx.Metadata().Bucket(txOutSetHistoryBucketName), info); err != nil {
+			return err
+		}
+		hashSerialized = info.HashSerialized
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	if !hashSerialized.IsEqual(&h.hashSerialized) {
+		err = fmt.Errorf(
+			"the blocks below the utxo set snapshot at block %s (height %d) make a utxo set that hashes to %s, not"+
+				" %s, the chain state is not valid and must be rebuilt from the genesis block", h.base.hash,
+			h.base.height, hashSerialized, h.hashSerialized,
+		)
+		Error(err)
+		b.failSnapshotHistory()
+		return err
+	}
+	if err = clearBucket(b.db, txOutSetHistoryBucketName); err != nil {
+		Error(err)
+		return err
+	}
+	err = b.db.Update(func(dbTx database.Tx) error {
+		meta := dbTx.Metadata()
+		if err := meta.DeleteBucket(txOutSetHistoryBucketName); err != nil {
+			return err
+		}
+		if err := meta.Delete(txOutSetHistoryKeyName); err != nil {
+			return err
+		}
+		return meta.Delete(txOutSetSnapshotKeyName)
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	b.history = nil
+	Infof(
+		"validated the blocks below the utxo set snapshot at block %s (height %d), which match it", h.base.hash,
+		h.base.height,
+	)
+	return nil
+}
+
+// connectHistoryBlock connects a block below or at the utxo set snapshot the chain state was loaded from to the utxo
+// set rebuilt from the blocks before it, and stores the block and its spend journal. This function MUST be called
+// with the chain state lock held (for writes).
+func (b *BlockChain) connectHistoryBlock(node *BlockNode, block *util.Block) error {
+	view := NewUtxoViewpoint()
+	view.SetBestHash(&node.parent.hash)
+	err := b.db.View(func(dbTx database.Tx) error {
+		return fetchHistoryUtxos(dbTx.Metadata().Bucket(txOutSetHistoryBucketName), view, block)
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	stxos := make([]SpentTxOut, 0, countSpentOutputs(block))
+	if err = b.checkConnectBlock(node, block, view, &stxos); err != nil {
+		Error(err)
+		if _, ok := err.(RuleError); ok {
+			Errorf(
+				"block %s (height %d) below the utxo set snapshot is not valid, the chain state is not valid and"+
+					" must be rebuilt from the genesis block", node.hash, node.height,
+			)
+			b.failSnapshotHistory()
+		}
+		return err
+	}
+	next := *b.history
+	next.next = node.height + 1
+	err = b.db.Update(func(dbTx database.Tx) error {
+		if err := dbPutUtxoViewBucket(dbTx.Metadata().Bucket(txOutSetHistoryBucketName), view); err != nil {
+			return err
+		}
+		if err := dbPutSpendJournalEntry(dbTx, &node.hash, stxos); err != nil {
+			return err
+		}
+		if err := dbStoreBlock(dbTx, block); err != nil {
+			return err
+		}
+		return dbTx.Metadata().Put(txOutSetHistoryKeyName, serializeSnapshotHistory(&next))
+	})
+	if err != nil {
+		Error(err)
+		return err
+	}
+	b.history.next = next.next
+	b.Index.SetStatusFlags(node, statusDataStored)
+	return b.Index.flushToDB()
+}
+
+// failSnapshotHistory records that the blocks below the utxo set snapshot the chain state was loaded from do not match
+// it, which ends their validation. This function MUST be called with the chain state lock held (for writes).
+func (b *BlockChain) failSnapshotHistory() {
+	b.history.failed = true
+	err := b.db.Update(func(dbTx database.Tx) error {
+		return dbTx.Metadata().Put(txOutSetHistoryKeyName, serializeSnapshotHistory(b.history))
+	})
+	if err != nil {
+		Error(err)
+	}
+}
+
+// fetchHistoryUtxos loads the outputs the transactions of the block spend and create from the utxo set held by the
+// bucket into the view, with nil entries for those it does not hold, so that connecting the block to the view does not
+// look them up in the utxo set of the main chain.
+func fetchHistoryUtxos(bucket database.Bucket, view *UtxoViewpoint, block *util.Block) error {
+	var outpoints []wire.OutPoint
+	for i, tx := range block.Transactions() {
+		if i > 0 {
+			for _, txIn := range tx.MsgTx().TxIn {
+				outpoints = append(outpoints, txIn.PreviousOutPoint)
+			}
+		}
+		for index := range tx.MsgTx().TxOut {
+			outpoints = append(outpoints, wire.OutPoint{Hash: *tx.Hash(), Index: uint32(index)})
+		}
+	}
+	for _, outpoint := range outpoints {
+		if _, ok := view.entries[outpoint]; ok {
+			continue
+		}
+		key := outpointKey(outpoint)
+		serialized := bucket.Get(*key)
+		recycleOutpointKey(key)
+		if serialized == nil {
+			view.entries[outpoint] = nil
+			continue
+		}
+		entry, err := deserializeUtxoEntry(serialized)
+		if err != nil {
+			return err
+		}
+		view.entries[outpoint] = entry
+	}
+	return nil
+}
+
+// SnapshotBase returns the hash of the block the chain state in the database was loaded from a utxo set snapshot at,
+// or nil if it was not.
+func SnapshotBase(db database.DB) (base *chainhash.Hash, err error) {
+	err = db.View(func(dbTx database.Tx) error {
+		if v := dbTx.Metadata().Get(txOutSetSnapshotKeyName); len(v) == chainhash.HashSize {
+			base = new(chainhash.Hash)
+			copy(base[:], v)
+		}
+		return nil
+	})
+	return
+}
@@ -0,0 +1,350 @@
+package blockchain
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+
+	chaincfg "github.com/p9c/pod/pkg/chain/config"
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/fork"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// solveTestBlock finds a nonce for which the hash of the block meets its target, since the proof of work of the
+// headers of a utxo set snapshot is checked.
+func solveTestBlock(block *util.Block) *util.Block {
+	msgBlock := block.MsgBlock()
+	target := fork.CompactToBig(msgBlock.Header.Bits)
+	for {
+		hash := msgBlock.Header.BlockHashWithAlgos(block.Height())
+		if HashToBig(&hash).Cmp(target) <= 0 {
+			break
+		}
+		msgBlock.Header.Nonce++
+	}
+	solved := util.NewBlock(msgBlock)
+	solved.SetHeight(block.Height())
+	return solved
+}
+
+// testTxOutSetChain adds numBlocks blocks to the chain in which, from the third block on, every block spends the
+// coinbase of the block two below it to two outputs, and returns the unspent outputs of the chain with their amounts.
+// If solve is set the proof of work of the blocks is solved.
+func testTxOutSetChain(chain *BlockChain, numBlocks int, solve bool) (map[wire.OutPoint]int64, error) {
+	chain.TstSetCoinbaseMaturity(1)
+	unspent := make(map[wire.OutPoint]int64)
+	for i := 0; i < numBlocks; i++ {
+		tip := chain.BestChain.Tip()
+		var txs []*wire.MsgTx
+		if tip.height >= 2 {
+			spent, err := chain.BlockByHeight(tip.height - 1)
+			if err != nil {
+				return nil, err
+			}
+			prevOut := wire.OutPoint{Hash: *spent.Transactions()[0].Hash()}
+			value := unspent[prevOut]
+			delete(unspent, prevOut)
+			tx := wire.NewMsgTx(wire.TxVersion)
+			tx.AddTxIn(wire.NewTxIn(&prevOut, nil, nil))
+			tx.AddTxOut(wire.NewTxOut(value/2, []byte{txscript.OP_TRUE}))
+			tx.AddTxOut(wire.NewTxOut(value-value/2, []byte{txscript.OP_TRUE}))
+			txs = append(txs, tx)
+		}
+		block, err := newTestBlock(chain, tip, 0, txs...)
+		if err != nil {
+			return nil, err
+		}
+		flags := BFNoPoWCheck
+		if solve {
+			block = solveTestBlock(block)
+			flags = BFNone
+		}
+		if _, _, err = chain.ProcessBlock(0, block, flags, block.Height()); err != nil {
+			return nil, err
+		}
+		for _, tx := range block.Transactions() {
+			for index, txOut := range tx.MsgTx().TxOut {
+				unspent[wire.OutPoint{Hash: *tx.Hash(), Index: uint32(index)}] = txOut.Value
+			}
+		}
+	}
+	return unspent, nil
+}
+
+// testSnapshot is a snapshot of a chain of solved blocks made by testTxOutSetChain, with the blocks below the snapshot
+// block. It is shared by the tests that load it, as solving the blocks takes a while.
+var testSnapshot struct {
+	sync.Once
+	blocks   []*util.Block
+	snapshot []byte
+	info     *TxOutSetInfo
+	err      error
+}
+
+// loadTestSnapshot returns the shared snapshot with its summary, and the blocks below the snapshot block from height 1
+// on.
+func loadTestSnapshot(t *testing.T) ([]byte, *TxOutSetInfo, []*util.Block) {
+	testSnapshot.Do(func() {
+		chain, teardown, err := chainSetup("txoutsetsnapshot", &netparams.RegressionTestParams)
+		if err != nil {
+			testSnapshot.err = err
+			return
+		}
+		defer teardown()
+		if _, err = testTxOutSetChain(chain, 5, true); err != nil {
+			testSnapshot.err = err
+			return
+		}
+		var buf bytes.Buffer
+		if testSnapshot.info, err = chain.DumpTxOutSet(&buf); err != nil {
+			testSnapshot.err = err
+			return
+		}
+		testSnapshot.snapshot = buf.Bytes()
+		for height := int32(1); height < testSnapshot.info.Height; height++ {
+			block, err := chain.BlockByHeight(height)
+			if err != nil {
+				testSnapshot.err = err
+				return
+			}
+			testSnapshot.blocks = append(testSnapshot.blocks, block)
+		}
+	})
+	if testSnapshot.err != nil {
+		t.Fatalf("unable to create snapshot: %v", testSnapshot.err)
+	}
+	return testSnapshot.snapshot, testSnapshot.info, testSnapshot.blocks
+}
+
+// testLoadChain returns a new chain with only the genesis block to load snapshots into. The returned function removes
+// the chain.
+func testLoadChain(t *testing.T, name string) (*BlockChain, func()) {
+	chain, teardown, err := chainSetup(name, &netparams.RegressionTestParams)
+	if err != nil {
+		t.Fatalf("failed to setup chain instance: %v", err)
+	}
+	chain.TstSetCoinbaseMaturity(1)
+	return chain, teardown
+}
+
+// TestTxOutSetInfo ensures the summary of the utxo set counts the unspent outputs of the chain and their amount, and
+// is the same as the summary written with a snapshot.
+func TestTxOutSetInfo(t *testing.T) {
+	chain, teardown := testLoadChain(t, "txoutsetinfo")
+	defer teardown()
+	unspent, err := testTxOutSetChain(chain, 12, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := chain.TxOutSetInfo()
+	if err != nil {
+		t.Fatalf("TxOutSetInfo: unexpected error: %v", err)
+	}
+	best := chain.BestSnapshot()
+	if info.Height != best.Height || info.BestBlock != best.Hash {
+		t.Errorf("summary is at block %s (height %d), want %s (height %d)", info.BestBlock, info.Height, best.Hash,
+			best.Height)
+	}
+	var total int64
+	txs := make(map[chainhash.Hash]struct{})
+	for outpoint, value := range unspent {
+		total += value
+		txs[outpoint.Hash] = struct{}{}
+	}
+	if info.TxOuts != uint64(len(unspent)) || info.Transactions != uint64(len(txs)) {
+		t.Errorf("summary has %d outputs of %d transactions, want %d of %d", info.TxOuts, info.Transactions,
+			len(unspent), len(txs))
+	}
+	if info.TotalAmount != util.Amount(total) {
+		t.Errorf("summary has a total amount of %v, want %v", info.TotalAmount, util.Amount(total))
+	}
+	var buf bytes.Buffer
+	dumped, err := chain.DumpTxOutSet(&buf)
+	if err != nil {
+		t.Fatalf("DumpTxOutSet: unexpected error: %v", err)
+	}
+	if *dumped != *info {
+		t.Errorf("snapshot summary is %+v, want %+v", dumped, info)
+	}
+}
+
+// TestLoadTxOutSet ensures a chain loaded from a snapshot has the utxo set and best block of the chain it was taken
+// from, validates the blocks below it in order, after which the snapshot is no longer trusted and the chain can be
+// reorganized past the snapshot block.
+func TestLoadTxOutSet(t *testing.T) {
+	snapshot, info, blocks := loadTestSnapshot(t)
+	chain, teardown := testLoadChain(t, "loadtxoutset")
+	defer teardown()
+	// Loading in batches smaller than the set ensures the entries of every batch are kept.
+	defer func(size int) { txOutSetBatchSize = size }(txOutSetBatchSize)
+	txOutSetBatchSize = 5
+	loaded, err := chain.LoadTxOutSet(bytes.NewReader(snapshot), &info.HashSerialized)
+	if err != nil {
+		t.Fatalf("LoadTxOutSet: unexpected error: %v", err)
+	}
+	if *loaded != *info {
+		t.Errorf("loaded summary is %+v, want %+v", loaded, info)
+	}
+	if got, err := chain.TxOutSetInfo(); err != nil || *got != *info {
+		t.Errorf("summary after loading is %+v (err %v), want %+v", got, err, info)
+	}
+	if best := chain.BestSnapshot(); best.Hash != info.BestBlock || best.Height != info.Height {
+		t.Errorf("best block is %s (height %d), want %s (height %d)", best.Hash, best.Height, info.BestBlock,
+			info.Height)
+	}
+	if base, err := SnapshotBase(chain.db); err != nil || base == nil || *base != info.BestBlock {
+		t.Errorf("snapshot base is %v (err %v), want %s", base, err, info.BestBlock)
+	}
+	if _, err := chain.LoadTxOutSet(bytes.NewReader(snapshot), nil); err == nil {
+		t.Errorf("LoadTxOutSet: expected an error loading into a chain beyond the genesis block")
+	}
+	if next, base := chain.SnapshotHistory(); next != 1 || base != info.Height {
+		t.Fatalf("history to validate is from %d to %d, want from 1 to %d", next, base, info.Height)
+	}
+	if _, err := chain.BlockByHeight(1); err == nil {
+		t.Errorf("block below the snapshot is available before it is validated")
+	}
+	if err = chain.ProcessHistoryBlock(blocks[1]); err == nil {
+		t.Errorf("ProcessHistoryBlock: expected an error for a block out of order")
+	}
+	for _, block := range blocks {
+		if err = chain.ProcessHistoryBlock(block); err != nil {
+			t.Fatalf("ProcessHistoryBlock: unexpected error for block %d: %v", block.Height(), err)
+		}
+	}
+	if next, base := chain.SnapshotHistory(); next != 0 || base != 0 {
+		t.Errorf("history to validate is from %d to %d after it was validated", next, base)
+	}
+	if base, err := SnapshotBase(chain.db); err != nil || base != nil {
+		t.Errorf("snapshot base is %v (err %v) after the history was validated, want none", base, err)
+	}
+	if got, err := chain.TxOutSetInfo(); err != nil || *got != *info {
+		t.Errorf("summary after validating the history is %+v (err %v), want %+v", got, err, info)
+	}
+	if _, err := chain.BlockByHeight(1); err != nil {
+		t.Errorf("block below the snapshot is not available after it was validated: %v", err)
+	}
+	err = chain.db.View(func(dbTx database.Tx) error {
+		if dbTx.Metadata().Bucket(txOutSetHistoryBucketName) != nil {
+			t.Errorf("rebuilt utxo set was not removed")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The spend journal of the snapshot block was written by the validation, so it can be disconnected.
+	if _, err = addTestBlocks(chain, chain.BestChain.Tip(), 1, 0); err != nil {
+		t.Fatalf("unable to add a block after the snapshot block: %v", err)
+	}
+	if err = chain.InvalidateBlock(&info.BestBlock); err != nil {
+		t.Fatalf("InvalidateBlock: unexpected error disconnecting the snapshot block: %v", err)
+	}
+	if best := chain.BestSnapshot(); best.Height != info.Height-1 {
+		t.Errorf("best block is at height %d after disconnecting the snapshot block, want %d", best.Height,
+			info.Height-1)
+	}
+}
+
+// TestLoadTxOutSetErrors ensures snapshots that do not have the expected hash, hold headers without enough proof of
+// work or off the checkpoints or entries that do not match their hash are not loaded, and leave the chain as it was.
+func TestLoadTxOutSetErrors(t *testing.T) {
+	snapshot, info, _ := loadTestSnapshot(t)
+	chain, teardown := testLoadChain(t, "loadtxoutseterr")
+	defer teardown()
+	defer func(size int) { txOutSetBatchSize = size }(txOutSetBatchSize)
+	txOutSetBatchSize = 5
+	// The first header follows the fixed fields, and its nonce the fields before it.
+	unsolved := append([]byte{}, snapshot...)
+	var header wire.BlockHeader
+	if err := header.Deserialize(bytes.NewReader(unsolved[txOutSetHeaderSize:])); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		header.Nonce++
+		hash := header.BlockHashWithAlgos(1)
+		if HashToBig(&hash).Cmp(fork.CompactToBig(header.Bits)) > 0 {
+			break
+		}
+	}
+	byteOrder.PutUint32(unsolved[txOutSetHeaderSize+76:], header.Nonce)
+	corrupt := append([]byte{}, snapshot...)
+	corrupt[len(corrupt)-1] ^= 0xff
+	wrongHash := info.HashSerialized
+	wrongHash[0] ^= 1
+	tests := []struct {
+		name     string
+		snapshot []byte
+		expected *chainhash.Hash
+		want     string
+	}{
+		{"unexpected hash", snapshot, &wrongHash, "is not the expected"},
+		{"header without proof of work", unsolved, nil, "higher than expected max"},
+		{"corrupt entry", corrupt, nil, ""},
+	}
+	for _, test := range tests {
+		_, err := chain.LoadTxOutSet(bytes.NewReader(test.snapshot), test.expected)
+		if err == nil || !strings.Contains(err.Error(), test.want) {
+			t.Errorf("%s: got error %v, want one containing %q", test.name, err, test.want)
+		}
+	}
+	// A checkpoint the headers do not match.
+	wrongCheckpoint := chainhash.Hash{1}
+	chain.checkpoints = []chaincfg.Checkpoint{{Height: 3, Hash: &wrongCheckpoint}}
+	chain.checkpointsByHeight = map[int32]*chaincfg.Checkpoint{3: &chain.checkpoints[0]}
+	_, err := chain.LoadTxOutSet(bytes.NewReader(snapshot), nil)
+	if rerr, ok := err.(RuleError); !ok || rerr.ErrorCode != ErrBadCheckpoint {
+		t.Errorf("checkpoint mismatch: got error %v, want %v", err, ErrBadCheckpoint)
+	}
+	if best := chain.BestSnapshot(); best.Height != 0 {
+		t.Errorf("best block is at height %d after failed loads, want the genesis block", best.Height)
+	}
+	if got, err := chain.TxOutSetInfo(); err != nil || got.TxOuts != 0 {
+		t.Errorf("utxo set is %+v (err %v) after failed loads, want it empty", got, err)
+	}
+	if base, err := SnapshotBase(chain.db); err != nil || base != nil {
+		t.Errorf("snapshot base is %v (err %v) after failed loads, want none", base, err)
+	}
+}
+
+// TestSnapshotHistoryMismatch ensures that blocks below a snapshot that do not make the utxo set of the snapshot end
+// the validation, which is recorded so it is not taken up again.
+func TestSnapshotHistoryMismatch(t *testing.T) {
+	snapshot, info, blocks := loadTestSnapshot(t)
+	chain, teardown := testLoadChain(t, "historymismatch")
+	defer teardown()
+	if _, err := chain.LoadTxOutSet(bytes.NewReader(snapshot), nil); err != nil {
+		t.Fatalf("LoadTxOutSet: unexpected error: %v", err)
+	}
+	// A snapshot whose entries match its hash but not the blocks.
+	chain.history.hashSerialized[0] ^= 1
+	for i, block := range blocks {
+		err := chain.ProcessHistoryBlock(block)
+		if i < len(blocks)-1 && err != nil {
+			t.Fatalf("ProcessHistoryBlock: unexpected error for block %d: %v", block.Height(), err)
+		}
+		if i == len(blocks)-1 && err == nil {
+			t.Fatalf("ProcessHistoryBlock: expected an error for blocks that do not match the snapshot")
+		}
+	}
+	if next, base := chain.SnapshotHistory(); next != 0 || base != 0 {
+		t.Errorf("history to validate is from %d to %d after it failed", next, base)
+	}
+	chain.history = nil
+	if err := chain.initSnapshotHistory(); err != nil {
+		t.Fatal(err)
+	}
+	if chain.history == nil || !chain.history.failed {
+		t.Errorf("failed validation was not recorded")
+	}
+	if base, err := SnapshotBase(chain.db); err != nil || base == nil {
+		t.Errorf("snapshot base is %v (err %v) after the history failed to validate, want %s", base, err,
+			info.BestBlock)
+	}
+}
@@ -113,19 +113,14 @@ func (b *BlockChain) checkConnectBlock(node *BlockNode, block *util.Block, view
 		Error(err)
 		return err
 	}
-	// BIP0016 describes a pay-to-script-hash type that is considered a "standard" type. The rules for this BIP only
-	// apply to transactions after the timestamp defined by txscript.Bip16Activation.
-	//
-	// See https://en.bitcoin.it/wiki/BIP_0016 for more details.
-	enforceBIP0016 := node.timestamp >= txscript.Bip16Activation.Unix()
-	// Query for the Version Bits state for the segwit soft-fork deployment. If segwit is active, we'll switch over to
-	// enforcing all the new rules.
-	segwitState, err := b.deploymentState(node.parent, chaincfg.DeploymentSegwit)
+	// The script flags the block is checked with also tell whether the pay-to-script-hash and segwit rules apply.
+	scriptFlags, err := b.blockScriptFlags(node, block)
 	if err != nil {
 		Error(err)
 		return err
 	}
-	enforceSegWit := segwitState == ThresholdActive
+	enforceBIP0016 := scriptFlags&txscript.ScriptBip16 == txscript.ScriptBip16
+	enforceSegWit := scriptFlags&txscript.ScriptVerifyWitness == txscript.ScriptVerifyWitness
 	// The number of signature operations must be less than the maximum allowed per block. Note that the preliminary
 	// sanity checks on a block also include a check similar to this one, but this check expands the count to include a
 	// precise count of pay-to -script-hash signature operations in each of the input transaction public key scripts.
@@ -271,37 +266,16 @@ func (b *BlockChain) checkConnectBlock(node *BlockNode, block *util.Block, view
 	if checkpoint != nil && node.height <= checkpoint.Height {
 		runScripts = false
 	}
-	// BlockC created after the BIP0016 activation time need to have the pay -to-script-hash checks enabled.
-	var scriptFlags txscript.ScriptFlags
-	if enforceBIP0016 {
-		scriptFlags |= txscript.ScriptBip16
-	}
-	// Enforce DER signatures for block versions 3+ once the historical activation threshold has been reached. This is
-	// part of BIP0066.
-	blockHeader := &block.MsgBlock().Header
-	if blockHeader.Version >= 3 && node.height >= b.params.BIP0066Height {
-		scriptFlags |= txscript.ScriptVerifyDERSignatures
-	}
-	// Enforce CHECKLOCKTIMEVERIFY for block versions 4+ once the historical activation threshold has been reached. This
-	// is part of BIP0065.
-	if blockHeader.Version >= 4 && node.height >= b.params.BIP0065Height {
-		scriptFlags |= txscript.ScriptVerifyCheckLockTimeVerify
-	}
-	// Enforce CHECKSEQUENCEVERIFY during all block validation checks once the soft-fork deployment is fully active.
-	csvState, err := b.deploymentState(node.parent, chaincfg.DeploymentCSV)
-	if err != nil {
-		Error(err)
-		return err
+	// Likewise the scripts of the ancestors of the assumed valid block are not run.
+	if b.isAssumedValid(node) {
+		runScripts = false
 	}
-	if csvState == ThresholdActive {
-		// If the CSV soft-fork is now active, then modify the scriptFlags to ensure that the CSV op code is properly
-		// validated during the script checks bleow.
-		scriptFlags |= txscript.ScriptVerifyCheckSequenceVerify
+	// Once the CSV soft-fork is active, the relative sequence number based lock-times within the inputs of all
+	// transactions in this candidate block are enforced as well.
+	if scriptFlags&txscript.ScriptVerifyCheckSequenceVerify == txscript.ScriptVerifyCheckSequenceVerify {
 		// We obtain the MTP of the *previous* block in order to determine if transactions in the current block are
 		// final.
 		medianTime := node.parent.CalcPastMedianTime()
-		// Additionally, if the CSV soft-fork package is now active, then we also enforce the relative sequence number
-		// based lock-times within the inputs of all transactions in this candidate block.
 		for _, tx := range block.Transactions() {
 			// A transaction can only be included within a block once the sequence locks of *all* its inputs are active.
 			sequenceLock, err := b.calcSequenceLock(node, tx, view,
@@ -319,11 +293,6 @@ func (b *BlockChain) checkConnectBlock(node *BlockNode, block *util.Block, view
 			}
 		}
 	}
-	// Enforce the segwit soft-fork package once the soft-fork has shifted into the "active" version bits state.
-	if enforceSegWit {
-		scriptFlags |= txscript.ScriptVerifyWitness
-		scriptFlags |= txscript.ScriptStrictMultiSig
-	}
 	// Now that the inexpensive checks are done and have passed, verify the transactions are actually allowed to spend
 	// the coins by running the expensive ECDSA signature check scripts. Doing this last helps prevent CPU exhaustion
 	// attacks.
@@ -341,6 +310,49 @@ func (b *BlockChain) checkConnectBlock(node *BlockNode, block *util.Block, view
 	return nil
 }
 
+// blockScriptFlags returns the script flags the scripts of the passed block are checked with, which depend on its
+// height, time and version and on the soft-forks active at its parent. This function MUST be called with the chain
+// state lock held (for reads).
+func (b *BlockChain) blockScriptFlags(node *BlockNode, block *util.Block) (txscript.ScriptFlags, error) {
+	// BIP0016 describes a pay-to-script-hash type that is considered a "standard" type. The rules for this BIP only
+	// apply to transactions after the timestamp defined by txscript.Bip16Activation.
+	//
+	// See https://en.bitcoin.it/wiki/BIP_0016 for more details.
+	var scriptFlags txscript.ScriptFlags
+	if node.timestamp >= txscript.Bip16Activation.Unix() {
+		scriptFlags |= txscript.ScriptBip16
+	}
+	// Enforce DER signatures for block versions 3+ once the historical activation threshold has been reached. This is
+	// part of BIP0066.
+	blockHeader := &block.MsgBlock().Header
+	if blockHeader.Version >= 3 && node.height >= b.params.BIP0066Height {
+		scriptFlags |= txscript.ScriptVerifyDERSignatures
+	}
+	// Enforce CHECKLOCKTIMEVERIFY for block versions 4+ once the historical activation threshold has been reached. This
+	// is part of BIP0065.
+	if blockHeader.Version >= 4 && node.height >= b.params.BIP0065Height {
+		scriptFlags |= txscript.ScriptVerifyCheckLockTimeVerify
+	}
+	// Enforce CHECKSEQUENCEVERIFY during all block validation checks once the soft-fork deployment is fully active.
+	csvState, err := b.deploymentState(node.parent, chaincfg.DeploymentCSV)
+	if err != nil {
+		return 0, err
+	}
+	if csvState == ThresholdActive {
+		scriptFlags |= txscript.ScriptVerifyCheckSequenceVerify
+	}
+	// Enforce the segwit soft-fork package once the soft-fork has shifted into the "active" version bits state.
+	segwitState, err := b.deploymentState(node.parent, chaincfg.DeploymentSegwit)
+	if err != nil {
+		return 0, err
+	}
+	if segwitState == ThresholdActive {
+		scriptFlags |= txscript.ScriptVerifyWitness
+		scriptFlags |= txscript.ScriptStrictMultiSig
+	}
+	return scriptFlags, nil
+}
+
 // CheckConnectBlockTemplate fully validates that connecting the passed block to the main chain does not violate any
 // consensus rules, aside from the proof of work requirement. The block must connect to the current tip of the main
 // chain. This function is safe for concurrent access.
@@ -654,6 +666,36 @@ func CheckProofOfWork(block *util.Block, powLimit *big.Int, height int32) error
 	return checkProofOfWork(&block.MsgBlock().Header, powLimit, BFNone, height)
 }
 
+// CheckHeaderProofOfWork ensures the passed header builds on a block in the block index, that its hash meets the
+// target it claims and that this target is the one required of its algorithm at its height. It is used to check an
+// announced block before the rest of it is requested. This function is safe for concurrent access.
+func (b *BlockChain) CheckHeaderProofOfWork(header *wire.BlockHeader) error {
+	b.chainLock.RLock()
+	defer b.chainLock.RUnlock()
+	prev := b.Index.LookupNode(&header.PrevBlock)
+	if prev == nil {
+		str := fmt.Sprintf("previous block %v is unknown", header.PrevBlock)
+		return ruleError(ErrPreviousBlockUnknown, str)
+	}
+	return b.checkHeaderWork(header, prev)
+}
+
+// checkHeaderWork performs the context free checks of the passed header, including its proof of work against the limit
+// of its algorithm, and the checks of its difficulty, timestamp and checkpoint against the block it builds on. This
+// function MUST be called with the chain state lock held (for reads).
+func (b *BlockChain) checkHeaderWork(header *wire.BlockHeader, prev *BlockNode) error {
+	height := prev.height + 1
+	algo := header.Version
+	if fork.GetCurrent(height) == 0 && algo != 514 {
+		algo = 2
+	}
+	powLimit := fork.GetMinDiff(fork.GetAlgoName(algo, height), height)
+	if err := checkBlockHeaderSanity(header, powLimit, b.timeSource, BFNone, height); err != nil {
+		return err
+	}
+	return b.checkBlockHeaderContext(0, header, prev, BFNone)
+}
+
 // CheckTransactionInputs performs a series of checks on the inputs to a transaction to ensure they are valid.
 //
 // An example of some of the checks include verifying all inputs exist, ensuring the coinbase seasoning requirements are
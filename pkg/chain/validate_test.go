@@ -2,11 +2,13 @@ package blockchain
 
 import (
 	"math"
+	"math/big"
 	"reflect"
 	"testing"
 	"time"
 
 	chaincfg "github.com/p9c/pod/pkg/chain/config"
+	"github.com/p9c/pod/pkg/chain/fork"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
 	"github.com/p9c/pod/pkg/chain/wire"
 	"github.com/p9c/pod/pkg/util"
@@ -205,6 +207,42 @@ func TestCheckSerializedHeight(t *testing.T) {
 	}
 }
 
+// TestCheckHeaderProofOfWork ensures the header of an announced block is only accepted with its proof of work solved
+// for the difficulty required of it, on top of a known block.
+func TestCheckHeaderProofOfWork(t *testing.T) {
+	_, _, blocks := loadTestSnapshot(t)
+	chain, teardown := testLoadChain(t, "checkheaderpow")
+	defer teardown()
+	solved := blocks[0].MsgBlock().Header
+	unsolved := solved
+	unsolved.Nonce++
+	easier := solved
+	easier.Bits = fork.BigToCompact(new(big.Int).Lsh(fork.CompactToBig(solved.Bits), 1))
+	orphan := blocks[1].MsgBlock().Header
+	tests := []struct {
+		name   string
+		header *wire.BlockHeader
+		code   ErrorCode
+	}{
+		{"solved", &solved, 0},
+		{"unsolved", &unsolved, ErrHighHash},
+		{"easier than required", &easier, ErrUnexpectedDifficulty},
+		{"unknown parent", &orphan, ErrPreviousBlockUnknown},
+	}
+	for _, test := range tests {
+		err := chain.CheckHeaderProofOfWork(test.header)
+		if test.code == 0 {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", test.name, err)
+			}
+			continue
+		}
+		if rErr, ok := err.(RuleError); !ok || rErr.ErrorCode != test.code {
+			t.Errorf("%s: got error %v, want %v", test.name, err, test.code)
+		}
+	}
+}
+
 // Block100000 defines block 100,000 of the block chain. It is used to test Block operations.
 var Block100000 = wire.MsgBlock{
 	Header: wire.BlockHeader{
@@ -66,6 +66,10 @@ func TestMessage(t *testing.T) {
 		[]byte("payload"))
 	msgCFHeaders := NewMsgCFHeaders()
 	msgCFCheckpt := NewMsgCFCheckpt(GCSFilterRegular, &chainhash.Hash{}, 0)
+	msgSendCmpct := NewMsgSendCmpct(true, CompactBlocksProtocolVersion)
+	msgCmpctBlock := NewMsgCmpctBlock(&blockOne, 123123)
+	msgGetBlockTxn := NewMsgGetBlockTxn(&chainhash.Hash{}, []uint32{})
+	msgBlockTxn := NewMsgBlockTxn(&chainhash.Hash{}, []*MsgTx{})
 	tests := []struct {
 		in     Message    // value to encode
 		out    Message    // Expected decoded value
@@ -100,6 +104,10 @@ func TestMessage(t *testing.T) {
 		{msgCFilter, msgCFilter, pver, MainNet, 65},
 		{msgCFHeaders, msgCFHeaders, pver, MainNet, 90},
 		{msgCFCheckpt, msgCFCheckpt, pver, MainNet, 58},
+		{msgSendCmpct, msgSendCmpct, pver, MainNet, 33},
+		{msgCmpctBlock, msgCmpctBlock, pver, MainNet, 249},
+		{msgGetBlockTxn, msgGetBlockTxn, pver, MainNet, 57},
+		{msgBlockTxn, msgBlockTxn, pver, MainNet, 57},
 	}
 	t.Logf("Running %d tests", len(tests))
 	for i, test := range tests {
@@ -0,0 +1,210 @@
+package wire
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/aead/siphash"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+)
+
+const (
+	// ShortTxIDSize is the number of bytes of a short transaction ID in a compact block.
+	ShortTxIDSize = 6
+	// shortTxIDMask keeps the bits of a siphash that make up a short transaction ID.
+	shortTxIDMask = 1<<(ShortTxIDSize*8) - 1
+)
+
+// PrefilledTx is a transaction sent in full within a compact block, along with its index in the block.
+type PrefilledTx struct {
+	Index uint32
+	Tx    *MsgTx
+}
+
+// MsgCmpctBlock implements the Message interface and represents a bitcoin cmpctblock message (BIP0152). It carries a
+// block header, the transactions the receiver is unlikely to have, usually only the coinbase, and short IDs of the
+// rest, which the receiver looks up in its memory pool to rebuild the block. The short IDs are keyed by the header and
+// a random nonce so they can't be made to collide for every peer at once. This message was not added until protocol
+// versions starting with CompactBlocksVersion.
+type MsgCmpctBlock struct {
+	Header        BlockHeader
+	Nonce         uint64
+	ShortIDs      []uint64
+	PrefilledTxns []PrefilledTx
+}
+
+// BtcDecode decodes r using the bitcoin protocol encoding into the receiver. This is part of the Message interface
+// implementation.
+func (msg *MsgCmpctBlock) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("cmpctblock message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+	if err := readBlockHeader(r, pver, &msg.Header); err != nil {
+		return err
+	}
+	if err := readElement(r, &msg.Nonce); err != nil {
+		return err
+	}
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	// Prevent more short IDs than could possibly fit into a block.
+	if count > maxTxPerBlock {
+		str := fmt.Sprintf("too many short IDs for message "+
+			"[count %v, max %v]", count, maxTxPerBlock)
+		return messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+	msg.ShortIDs = make([]uint64, count)
+	var buf [8]byte
+	for i := range msg.ShortIDs {
+		if _, err = io.ReadFull(r, buf[:ShortTxIDSize]); err != nil {
+			return err
+		}
+		msg.ShortIDs[i] = littleEndian.Uint64(buf[:])
+	}
+	count, err = ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	if count+uint64(len(msg.ShortIDs)) > maxTxPerBlock {
+		str := fmt.Sprintf("too many transactions for message "+
+			"[count %v, max %v]", count+uint64(len(msg.ShortIDs)), maxTxPerBlock)
+		return messageError("MsgCmpctBlock.BtcDecode", str)
+	}
+	total := count + uint64(len(msg.ShortIDs))
+	msg.PrefilledTxns = make([]PrefilledTx, count)
+	// The indexes are differentially encoded, each being the number of transactions skipped since the previous one.
+	var next uint64
+	for i := range msg.PrefilledTxns {
+		diff, err := ReadVarInt(r, pver)
+		if err != nil {
+			return err
+		}
+		index := next + diff
+		if index < next || index >= total {
+			str := fmt.Sprintf("prefilled transaction index %v out of range "+
+				"[transactions %v]", index, total)
+			return messageError("MsgCmpctBlock.BtcDecode", str)
+		}
+		tx := MsgTx{}
+		if err = tx.BtcDecode(r, pver, enc); err != nil {
+			return err
+		}
+		msg.PrefilledTxns[i] = PrefilledTx{Index: uint32(index), Tx: &tx}
+		next = index + 1
+	}
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the bitcoin protocol encoding. This is part of the Message interface
+// implementation.
+func (msg *MsgCmpctBlock) BtcEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("cmpctblock message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgCmpctBlock.BtcEncode", str)
+	}
+	if len(msg.ShortIDs)+len(msg.PrefilledTxns) > maxTxPerBlock {
+		str := fmt.Sprintf("too many transactions for message "+
+			"[count %v, max %v]", len(msg.ShortIDs)+len(msg.PrefilledTxns), maxTxPerBlock)
+		return messageError("MsgCmpctBlock.BtcEncode", str)
+	}
+	if err := writeBlockHeader(w, pver, &msg.Header); err != nil {
+		return err
+	}
+	if err := writeElement(w, msg.Nonce); err != nil {
+		return err
+	}
+	if err := WriteVarInt(w, pver, uint64(len(msg.ShortIDs))); err != nil {
+		return err
+	}
+	var buf [8]byte
+	for _, id := range msg.ShortIDs {
+		littleEndian.PutUint64(buf[:], id)
+		if _, err := w.Write(buf[:ShortTxIDSize]); err != nil {
+			return err
+		}
+	}
+	if err := WriteVarInt(w, pver, uint64(len(msg.PrefilledTxns))); err != nil {
+		return err
+	}
+	var next uint32
+	for i, ptx := range msg.PrefilledTxns {
+		if ptx.Index < next {
+			str := fmt.Sprintf("prefilled transaction %d is out of order", i)
+			return messageError("MsgCmpctBlock.BtcEncode", str)
+		}
+		if err := WriteVarInt(w, pver, uint64(ptx.Index-next)); err != nil {
+			return err
+		}
+		if err := ptx.Tx.BtcEncode(w, pver, enc); err != nil {
+			return err
+		}
+		next = ptx.Index + 1
+	}
+	return nil
+}
+
+// Command returns the protocol command string for the message. This is part of the Message interface implementation.
+func (msg *MsgCmpctBlock) Command() string {
+	return CmdCmpctBlock
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the receiver. This is part of the Message
+// interface implementation.
+func (msg *MsgCmpctBlock) MaxPayloadLength(pver uint32) uint32 {
+	return MaxBlockPayload
+}
+
+// BlockHash computes the block identifier hash for the block the message describes.
+func (msg *MsgCmpctBlock) BlockHash() chainhash.Hash {
+	return msg.Header.BlockHash()
+}
+
+// TxCount returns the number of transactions in the block the message describes.
+func (msg *MsgCmpctBlock) TxCount() int {
+	return len(msg.ShortIDs) + len(msg.PrefilledTxns)
+}
+
+// ShortIDKey returns the siphash key the short transaction IDs of the message are made with, which is the first 16
+// bytes of the sha256 of the block header followed by the nonce.
+func (msg *MsgCmpctBlock) ShortIDKey() (key [siphash.KeySize]byte) {
+	var buf bytes.Buffer
+	_ = writeBlockHeader(&buf, 0, &msg.Header)
+	_ = binary.Write(&buf, littleEndian, msg.Nonce)
+	sum := sha256.Sum256(buf.Bytes())
+	copy(key[:], sum[:siphash.KeySize])
+	return
+}
+
+// ShortTxID returns the short ID of the transaction with the given hash under the given key.
+func ShortTxID(key *[siphash.KeySize]byte, txHash *chainhash.Hash) uint64 {
+	return siphash.Sum64(txHash[:], key) & shortTxIDMask
+}
+
+// NewMsgCmpctBlock returns a new bitcoin cmpctblock message describing the passed block, which conforms to the Message
+// interface. The coinbase is sent in full and every other transaction by its short ID. See MsgCmpctBlock for details.
+func NewMsgCmpctBlock(block *MsgBlock, nonce uint64) *MsgCmpctBlock {
+	msg := &MsgCmpctBlock{
+		Header: block.Header,
+		Nonce:  nonce,
+	}
+	if len(block.Transactions) == 0 {
+		return msg
+	}
+	msg.PrefilledTxns = []PrefilledTx{{Index: 0, Tx: block.Transactions[0]}}
+	msg.ShortIDs = make([]uint64, 0, len(block.Transactions)-1)
+	key := msg.ShortIDKey()
+	for _, tx := range block.Transactions[1:] {
+		txHash := tx.TxHash()
+		msg.ShortIDs = append(msg.ShortIDs, ShortTxID(&key, &txHash))
+	}
+	return msg
+}
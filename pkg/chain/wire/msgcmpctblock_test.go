@@ -0,0 +1,71 @@
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestCmpctBlock tests that a compact block built from a block prefills its coinbase, holds the short IDs of the other
+// transactions and survives a round trip through the wire encoding.
+func TestCmpctBlock(t *testing.T) {
+	block := blockOne
+	tx := blockOne.Transactions[0].Copy()
+	tx.LockTime = 1
+	block.Transactions = []*MsgTx{blockOne.Transactions[0], tx}
+	msg := NewMsgCmpctBlock(&block, 0x0102030405060708)
+	if msg.TxCount() != 2 || len(msg.PrefilledTxns) != 1 || msg.PrefilledTxns[0].Index != 0 {
+		t.Fatalf("NewMsgCmpctBlock: got %d transactions with %d prefilled, want the coinbase prefilled of 2",
+			msg.TxCount(), len(msg.PrefilledTxns))
+	}
+	key := msg.ShortIDKey()
+	txHash := tx.TxHash()
+	id := ShortTxID(&key, &txHash)
+	if id != msg.ShortIDs[0] {
+		t.Errorf("NewMsgCmpctBlock: short ID got %x, want %x", msg.ShortIDs[0], id)
+	}
+	if id>>(ShortTxIDSize*8) != 0 {
+		t.Errorf("ShortTxID: %x is longer than %d bytes", id, ShortTxIDSize)
+	}
+	if blockHash := block.BlockHash(); msg.BlockHash() != blockHash {
+		t.Errorf("BlockHash: got %v, want %v", msg.BlockHash(), blockHash)
+	}
+	// A different nonce gives different short IDs.
+	if other := NewMsgCmpctBlock(&block, 1); other.ShortIDs[0] == msg.ShortIDs[0] {
+		t.Errorf("NewMsgCmpctBlock: short ID does not depend on the nonce")
+	}
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	// Header + nonce + short ID count + short ID + prefilled count + index + coinbase.
+	wantLen := 80 + 8 + 1 + ShortTxIDSize + 1 + 1 + blockOne.Transactions[0].SerializeSize()
+	if buf.Len() != wantLen {
+		t.Errorf("BtcEncode: got %d bytes, want %d", buf.Len(), wantLen)
+	}
+	var readmsg MsgCmpctBlock
+	if err := readmsg.BtcDecode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&readmsg, msg) {
+		t.Errorf("BtcDecode\n got: %s want: %s", spew.Sdump(readmsg), spew.Sdump(msg))
+	}
+}
+
+// TestCmpctBlockWireErrors performs negative tests against wire decode of MsgCmpctBlock to confirm a prefilled
+// transaction index beyond the transactions of the block is rejected.
+func TestCmpctBlockWireErrors(t *testing.T) {
+	msg := NewMsgCmpctBlock(&blockOne, 0)
+	msg.PrefilledTxns[0].Index = 1
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	var readmsg MsgCmpctBlock
+	err := readmsg.BtcDecode(&buf, ProtocolVersion, BaseEncoding)
+	if _, ok := err.(*MessageError); !ok {
+		t.Errorf("BtcDecode got: %v, want a MessageError", err)
+	}
+}
@@ -0,0 +1,111 @@
+package wire
+
+import (
+	"fmt"
+	"io"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+)
+
+// MsgGetBlockTxn implements the Message interface and represents a bitcoin getblocktxn message (BIP0152). It is used to
+// request the transactions of a block at the given indexes, which the requester could not find in its memory pool
+// while rebuilding the block from a cmpctblock message. The indexes must be in ascending order. This message was not
+// added until protocol versions starting with CompactBlocksVersion.
+type MsgGetBlockTxn struct {
+	BlockHash chainhash.Hash
+	Indexes   []uint32
+}
+
+// BtcDecode decodes r using the bitcoin protocol encoding into the receiver. This is part of the Message interface
+// implementation.
+func (msg *MsgGetBlockTxn) BtcDecode(r io.Reader, pver uint32, enc MessageEncoding) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("getblocktxn message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgGetBlockTxn.BtcDecode", str)
+	}
+	if err := readElement(r, &msg.BlockHash); err != nil {
+		return err
+	}
+	count, err := ReadVarInt(r, pver)
+	if err != nil {
+		return err
+	}
+	// Prevent more indexes than could possibly fit into a block.
+	if count > maxTxPerBlock {
+		str := fmt.Sprintf("too many transaction indexes for message "+
+			"[count %v, max %v]", count, maxTxPerBlock)
+		return messageError("MsgGetBlockTxn.BtcDecode", str)
+	}
+	msg.Indexes = make([]uint32, count)
+	// The indexes are differentially encoded, each being the number of transactions skipped since the previous one.
+	var next uint64
+	for i := range msg.Indexes {
+		diff, err := ReadVarInt(r, pver)
+		if err != nil {
+			return err
+		}
+		index := next + diff
+		if index < next || index >= maxTxPerBlock {
+			str := fmt.Sprintf("transaction index %v out of range", index)
+			return messageError("MsgGetBlockTxn.BtcDecode", str)
+		}
+		msg.Indexes[i] = uint32(index)
+		next = index + 1
+	}
+	return nil
+}
+
+// BtcEncode encodes the receiver to w using the bitcoin protocol encoding. This is part of the Message interface
+// implementation.
+func (msg *MsgGetBlockTxn) BtcEncode(w io.Writer, pver uint32, enc MessageEncoding) error {
+	if pver < CompactBlocksVersion {
+		str := fmt.Sprintf("getblocktxn message invalid for protocol "+
+			"version %d", pver)
+		return messageError("MsgGetBlockTxn.BtcEncode", str)
+	}
+	if len(msg.Indexes) > maxTxPerBlock {
+		str := fmt.Sprintf("too many transaction indexes for message "+
+			"[count %v, max %v]", len(msg.Indexes), maxTxPerBlock)
+		return messageError("MsgGetBlockTxn.BtcEncode", str)
+	}
+	if err := writeElement(w, &msg.BlockHash); err != nil {
+		return err
+	}
+	if err := WriteVarInt(w, pver, uint64(len(msg.Indexes))); err != nil {
+		return err
+	}
+	var next uint32
+	for i, index := range msg.Indexes {
+		if index < next {
+			str := fmt.Sprintf("transaction index %d is out of order", i)
+			return messageError("MsgGetBlockTxn.BtcEncode", str)
+		}
+		if err := WriteVarInt(w, pver, uint64(index-next)); err != nil {
+			return err
+		}
+		next = index + 1
+	}
+	return nil
+}
+
+// Command returns the protocol command string for the message. This is part of the Message interface implementation.
+func (msg *MsgGetBlockTxn) Command() string {
+	return CmdGetBlockTxn
+}
+
+// MaxPayloadLength returns the maximum length the payload can be for the receiver. This is part of the Message
+// interface implementation.
+func (msg *MsgGetBlockTxn) MaxPayloadLength(pver uint32) uint32 {
+	// Block hash + num indexes (varInt) + max allowed indexes, each up to a 9 byte varInt.
+	return chainhash.HashSize + MaxVarIntPayload + maxTxPerBlock*MaxVarIntPayload
+}
+
+// NewMsgGetBlockTxn returns a new bitcoin getblocktxn message that conforms to the Message interface. See
+// MsgGetBlockTxn for details.
+func NewMsgGetBlockTxn(blockHash *chainhash.Hash, indexes []uint32) *MsgGetBlockTxn {
+	return &MsgGetBlockTxn{
+		BlockHash: *blockHash,
+		Indexes:   indexes,
+	}
+}
@@ -0,0 +1,66 @@
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+)
+
+// TestGetBlockTxnWire tests the MsgGetBlockTxn wire encode and decode, which stores the indexes differentially.
+func TestGetBlockTxnWire(t *testing.T) {
+	hash := chainhash.Hash{0x01, 0x02, 0x03}
+	msg := NewMsgGetBlockTxn(&hash, []uint32{1, 2, 5, 300})
+	want := append(append([]byte{}, hash[:]...),
+		0x04,             // Varint for number of indexes
+		0x01,             // Index 1
+		0x00,             // Index 2
+		0x02,             // Index 5
+		0xfd, 0x26, 0x01, // Index 300
+	)
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Fatalf("BtcEncode\n got: %s want: %s", spew.Sdump(buf.Bytes()), spew.Sdump(want))
+	}
+	var readmsg MsgGetBlockTxn
+	if err := readmsg.BtcDecode(bytes.NewReader(want), ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&readmsg, msg) {
+		t.Errorf("BtcDecode\n got: %s want: %s", spew.Sdump(readmsg), spew.Sdump(msg))
+	}
+	// Indexes that are not in ascending order can't be encoded.
+	msg.Indexes = []uint32{5, 1}
+	err := msg.BtcEncode(&bytes.Buffer{}, ProtocolVersion, BaseEncoding)
+	if _, ok := err.(*MessageError); !ok {
+		t.Errorf("BtcEncode of unordered indexes got: %v, want a MessageError", err)
+	}
+}
+
+// TestBlockTxnWire tests the MsgBlockTxn wire encode and decode against the latest protocol version.
+func TestBlockTxnWire(t *testing.T) {
+	hash := blockOne.BlockHash()
+	msg := NewMsgBlockTxn(&hash, []*MsgTx{blockOne.Transactions[0], blockOne.Transactions[0]})
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcEncode error %v", err)
+	}
+	var readmsg MsgBlockTxn
+	if err := readmsg.BtcDecode(&buf, ProtocolVersion, BaseEncoding); err != nil {
+		t.Fatalf("BtcDecode error %v", err)
+	}
+	if !reflect.DeepEqual(&readmsg, msg) {
+		t.Errorf("BtcDecode\n got: %s want: %s", spew.Sdump(readmsg), spew.Sdump(msg))
+	}
+	// The message is not defined before CompactBlocksVersion.
+	err := msg.BtcEncode(&bytes.Buffer{}, CompactBlocksVersion-1, BaseEncoding)
+	if _, ok := err.(*MessageError); !ok {
+		t.Errorf("BtcEncode for old protocol version got: %v, want a MessageError", err)
+	}
+}
@@ -0,0 +1,82 @@
+package wire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+// TestSendCmpctWire tests the MsgSendCmpct wire encode and decode for various protocol versions.
+func TestSendCmpctWire(t *testing.T) {
+	tests := []struct {
+		in   MsgSendCmpct // Message to encode
+		out  MsgSendCmpct // Expected decoded message
+		buf  []byte       // Wire encoding
+		pver uint32       // Protocol version for wire encoding
+	}{
+		// Latest protocol version, high bandwidth mode.
+		{
+			MsgSendCmpct{Announce: true, Version: CompactBlocksProtocolVersion},
+			MsgSendCmpct{Announce: true, Version: CompactBlocksProtocolVersion},
+			[]byte{0x01, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			ProtocolVersion,
+		},
+		// Protocol version CompactBlocksVersion, low bandwidth mode.
+		{
+			MsgSendCmpct{Announce: false, Version: 2},
+			MsgSendCmpct{Announce: false, Version: 2},
+			[]byte{0x00, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			CompactBlocksVersion,
+		},
+	}
+	t.Logf("Running %d tests", len(tests))
+	for i, test := range tests {
+		// Encode the message to wire format.
+		var buf bytes.Buffer
+		err := test.in.BtcEncode(&buf, test.pver, BaseEncoding)
+		if err != nil {
+			t.Errorf("BtcEncode #%d error %v", i, err)
+			continue
+		}
+		if !bytes.Equal(buf.Bytes(), test.buf) {
+			t.Errorf("BtcEncode #%d\n got: %s want: %s", i,
+				spew.Sdump(buf.Bytes()), spew.Sdump(test.buf))
+			continue
+		}
+		// Decode the message from wire format.
+		var msg MsgSendCmpct
+		rbuf := bytes.NewReader(test.buf)
+		err = msg.BtcDecode(rbuf, test.pver, BaseEncoding)
+		if err != nil {
+			t.Errorf("BtcDecode #%d error %v", i, err)
+			continue
+		}
+		if !reflect.DeepEqual(msg, test.out) {
+			t.Errorf("BtcDecode #%d\n got: %s want: %s", i,
+				spew.Sdump(msg), spew.Sdump(test.out))
+			continue
+		}
+	}
+}
+
+// TestSendCmpctWireErrors performs negative tests against wire encode and decode of MsgSendCmpct to confirm error paths
+// work correctly.
+func TestSendCmpctWireErrors(t *testing.T) {
+	msg := NewMsgSendCmpct(true, CompactBlocksProtocolVersion)
+	if cmd := msg.Command(); cmd != "sendcmpct" {
+		t.Errorf("NewMsgSendCmpct: wrong command - got %v want sendcmpct", cmd)
+	}
+	// Force error due to unsupported protocol version.
+	pver := CompactBlocksVersion - 1
+	var buf bytes.Buffer
+	if err := msg.BtcEncode(&buf, pver, BaseEncoding); reflect.TypeOf(err) != reflect.TypeOf(&MessageError{}) {
+		t.Errorf("BtcEncode wrong error got: %v, want a MessageError", err)
+	}
+	var readmsg MsgSendCmpct
+	err := readmsg.BtcDecode(bytes.NewReader(make([]byte, 9)), pver, BaseEncoding)
+	if reflect.TypeOf(err) != reflect.TypeOf(&MessageError{}) {
+		t.Errorf("BtcDecode wrong error got: %v, want a MessageError", err)
+	}
+}
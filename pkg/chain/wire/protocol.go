@@ -9,7 +9,7 @@ import (
 // XXX pedro: we will probably need to bump this.
 const (
 	// ProtocolVersion is the latest protocol version this package supports.
-	ProtocolVersion uint32 = 70013
+	ProtocolVersion uint32 = 70014
 	// MultipleAddressVersion is the protocol version which added multiple addresses per message (pver >=
 	// MultipleAddressVersion).
 	MultipleAddressVersion uint32 = 209
@@ -31,6 +31,9 @@ const (
 	SendHeadersVersion uint32 = 70012
 	// FeeFilterVersion is the protocol version which added a new feefilter message.
 	FeeFilterVersion uint32 = 70013
+	// CompactBlocksVersion is the protocol version which added the sendcmpct, cmpctblock, getblocktxn and blocktxn
+	// messages for compact block relay (BIP0152).
+	CompactBlocksVersion uint32 = 70014
 )
 
 // ServiceFlag identifies services supported by a bitcoin peer.
@@ -54,18 +57,22 @@ const (
 	SFNodeCF
 	// SFNode2X is a flag used to indicate a peer is running the Segwit2X software.
 	SFNode2X
+	// SFNodeNetworkLimited is a flag used to indicate a peer only keeps the most recent blocks, as a pruned node does
+	// (BIP0159).
+	SFNodeNetworkLimited ServiceFlag = 1 << 10
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeGetUTXO: "SFNodeGetUTXO",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeWitness: "SFNodeWitness",
-	SFNodeXthin:   "SFNodeXthin",
-	SFNodeBit5:    "SFNodeBit5",
-	SFNodeCF:      "SFNodeCF",
-	SFNode2X:      "SFNode2X",
+	SFNodeNetwork:        "SFNodeNetwork",
+	SFNodeGetUTXO:        "SFNodeGetUTXO",
+	SFNodeBloom:          "SFNodeBloom",
+	SFNodeWitness:        "SFNodeWitness",
+	SFNodeXthin:          "SFNodeXthin",
+	SFNodeBit5:           "SFNodeBit5",
+	SFNodeCF:             "SFNodeCF",
+	SFNode2X:             "SFNode2X",
+	SFNodeNetworkLimited: "SFNodeNetworkLimited",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to lowest.
@@ -78,6 +85,7 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeBit5,
 	SFNodeCF,
 	SFNode2X,
+	SFNodeNetworkLimited,
 }
 
 // String returns the ServiceFlag in human-readable form.
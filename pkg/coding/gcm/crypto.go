@@ -3,25 +3,38 @@ package gcm
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+	"io"
 
 	"golang.org/x/crypto/argon2"
 )
 
-// GetCipher returns a GCM cipher given a password string. Note that this cipher must be renewed every 4gb of encrypted
-// data
-func GetCipher(password string) (gcm cipher.AEAD, err error) {
-	bytes := []byte(password)
+// SaltSize is the size of the random salt keys are derived with
+const SaltSize = 16
+
+// GetCipher returns a GCM cipher given a password string and a salt. The key is derived with Argon2id, which is memory
+// hard, so every salt needs its own expensive guessing of the password. Note that this cipher must be renewed every 4gb
+// of encrypted data
+func GetCipher(password string, salt []byte) (gcm cipher.AEAD, err error) {
+	if len(salt) != SaltSize {
+		err = fmt.Errorf("salt is %d bytes, must be %d", len(salt), SaltSize)
+		Error(err)
+		return
+	}
 	var c cipher.Block
-	if c, err = aes.NewCipher(argon2.IDKey(reverse(bytes), bytes, 1, 64*1024, 4, 32)); Check(err) {
+	if c, err = aes.NewCipher(argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, 32)); Check(err) {
+		return
 	}
 	if gcm, err = cipher.NewGCM(c); Check(err) {
 	}
 	return
 }
 
-func reverse(b []byte) []byte {
-	for i := range b {
-		b[i] = b[len(b)-1]
+// NewSalt returns a new random salt to derive a key with
+func NewSalt() (salt []byte, err error) {
+	salt = make([]byte, SaltSize)
+	if _, err = io.ReadFull(rand.Reader, salt); Check(err) {
 	}
-	return b
+	return
 }
@@ -2,6 +2,7 @@ package builder
 
 import (
 	"crypto/rand"
+	"encoding/binary"
 	"fmt"
 	"math"
 
@@ -261,6 +262,45 @@ func BuildBasicFilter(block *wire.MsgBlock, prevOutScripts [][]byte) (*gcs.Filte
 		Error(err)
 		return nil, err
 	}
+	addBasicEntries(b, block, prevOutScripts)
+	return b.Build()
+}
+
+// BuildExtendedFilter builds an extended GCS filter from a block. An extended GCS filter contains everything a basic
+// filter does, as well as the outpoints spent by inputs within the block in the form returned by OutPointEntry, so the
+// spending of an output can be detected without knowing its script.
+func BuildExtendedFilter(block *wire.MsgBlock, prevOutScripts [][]byte) (*gcs.Filter, error) {
+	blockHash := block.BlockHash()
+	b := WithKeyHash(&blockHash)
+	_, err := b.Key()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	addBasicEntries(b, block, prevOutScripts)
+	// The coinbase, which is always the first transaction, does not spend any outputs.
+	for i, tx := range block.Transactions {
+		if i == 0 {
+			continue
+		}
+		for _, txIn := range tx.TxIn {
+			b.AddEntry(OutPointEntry(&txIn.PreviousOutPoint))
+		}
+	}
+	return b.Build()
+}
+
+// OutPointEntry returns the entry an extended filter holds for a spent outpoint, which is the hash of the transaction
+// followed by the output index as 4 little endian bytes.
+func OutPointEntry(op *wire.OutPoint) []byte {
+	entry := make([]byte, chainhash.HashSize+4)
+	copy(entry, op.Hash[:])
+	binary.LittleEndian.PutUint32(entry[chainhash.HashSize:], op.Index)
+	return entry
+}
+
+// addBasicEntries adds the entries of a basic filter for a block to the passed builder.
+func addBasicEntries(b *GCSBuilder, block *wire.MsgBlock, prevOutScripts [][]byte) {
 	// In order to build a basic filter, we'll range over the entire block, adding each whole script itself.
 	for _, tx := range block.Transactions {
 		// For each output in a transaction, we'll add each of the individual data pushes within the script.
@@ -284,7 +324,6 @@ func BuildBasicFilter(block *wire.MsgBlock, prevOutScripts [][]byte) (*gcs.Filte
 		}
 		b.AddEntry(prevScript)
 	}
-	return b.Build()
 }
 
 // GetFilterHash returns the double-SHA256 of the filter.
@@ -248,3 +248,55 @@ func BuilderTest(b *builder.GCSBuilder, hash *chainhash.Hash, p uint8,
 		t.Fatal("Filter size increased with duplicate items")
 	}
 }
+
+// TestBuildExtendedFilter ensures the extended filter of a block matches the outpoints spent within it as well as the
+// scripts the basic filter holds, and that filter types are built and looked up through the registry.
+func TestBuildExtendedFilter(t *testing.T) {
+	hash, err := chainhash.NewHashFromStr(testHash)
+	if err != nil {
+		t.Fatalf("Hash from string failed: %s", err.Error())
+	}
+	spent := wire.OutPoint{Hash: *hash, Index: 4321}
+	pkScript := []byte{txscript.OP_TRUE}
+	prevScript := []byte{txscript.OP_2}
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: wire.MaxPrevOutIndex}, nil, nil))
+	coinbase.AddTxOut(wire.NewTxOut(50, pkScript))
+	spend := wire.NewMsgTx(1)
+	spend.AddTxIn(wire.NewTxIn(&spent, nil, nil))
+	spend.AddTxOut(wire.NewTxOut(40, pkScript))
+	block := wire.NewMsgBlock(&wire.BlockHeader{})
+	_ = block.AddTransaction(coinbase)
+	_ = block.AddTransaction(spend)
+	blockHash := block.BlockHash()
+	key := builder.DeriveKey(&blockHash)
+	basic, err := builder.BuildFilter(wire.GCSFilterRegular, block, [][]byte{prevScript})
+	if err != nil {
+		t.Fatalf("BuildFilter: unexpected err %v", err)
+	}
+	extended, err := builder.BuildFilter(wire.GCSFilterExtended, block, [][]byte{prevScript})
+	if err != nil {
+		t.Fatalf("BuildFilter: unexpected err %v", err)
+	}
+	for _, entry := range [][]byte{pkScript, prevScript, builder.OutPointEntry(&spent)} {
+		if match, err := extended.Match(key, entry); err != nil || !match {
+			t.Errorf("extended filter does not match %x", entry)
+		}
+	}
+	if match, _ := basic.Match(key, builder.OutPointEntry(&spent)); match {
+		t.Errorf("basic filter matches a spent outpoint")
+	}
+	if extended.N() != basic.N()+1 {
+		t.Errorf("extended filter holds %d entries, want %d", extended.N(), basic.N()+1)
+	}
+	// The registry knows both built-in types by name and refuses to register them again.
+	if ft, err := builder.ParseFilterType("extended"); err != nil || ft != wire.GCSFilterExtended {
+		t.Errorf("ParseFilterType: got %v, %v", ft, err)
+	}
+	if err = builder.RegisterFilterType(wire.GCSFilterRegular, "other", builder.BuildBasicFilter); err == nil {
+		t.Errorf("RegisterFilterType: registered a filter type twice")
+	}
+	if _, err = builder.BuildFilter(wire.FilterType(200), block, nil); err == nil {
+		t.Errorf("BuildFilter: built an unknown filter type")
+	}
+}
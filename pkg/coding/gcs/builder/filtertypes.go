@@ -0,0 +1,89 @@
+package builder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/coding/gcs"
+)
+
+// FilterBuilder builds the filter of a block given the public key scripts of the outputs spent by its inputs, in input
+// order.
+type FilterBuilder func(block *wire.MsgBlock, prevOutScripts [][]byte) (*gcs.Filter, error)
+
+// filterType is a registered filter type.
+type filterType struct {
+	name  string
+	build FilterBuilder
+}
+
+var (
+	registerLock sync.RWMutex
+	filterTypes  = map[wire.FilterType]filterType{
+		wire.GCSFilterRegular:  {"regular", BuildBasicFilter},
+		wire.GCSFilterExtended: {"extended", BuildExtendedFilter},
+	}
+)
+
+// RegisterFilterType adds a filter type that can be built by BuildFilter and served to peers once a node has built it.
+// An error is returned if the type or name is already registered.
+func RegisterFilterType(t wire.FilterType, name string, build FilterBuilder) error {
+	registerLock.Lock()
+	defer registerLock.Unlock()
+	for existing, ft := range filterTypes {
+		if existing == t || ft.name == name {
+			return fmt.Errorf("filter type %d %q is already registered as %d %q", t, name, existing, ft.name)
+		}
+	}
+	filterTypes[t] = filterType{name: name, build: build}
+	return nil
+}
+
+// BuildFilter builds the filter of the passed type for a block given the public key scripts of the outputs spent by its
+// inputs.
+func BuildFilter(t wire.FilterType, block *wire.MsgBlock, prevOutScripts [][]byte) (*gcs.Filter, error) {
+	registerLock.RLock()
+	ft, ok := filterTypes[t]
+	registerLock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown filter type %d", t)
+	}
+	return ft.build(block, prevOutScripts)
+}
+
+// FilterTypes returns the registered filter types in ascending order.
+func FilterTypes() []wire.FilterType {
+	registerLock.RLock()
+	defer registerLock.RUnlock()
+	types := make([]wire.FilterType, 0, len(filterTypes))
+	for t := range filterTypes {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// FilterTypeName returns the name of a registered filter type, or its number if it is not registered.
+func FilterTypeName(t wire.FilterType) string {
+	registerLock.RLock()
+	defer registerLock.RUnlock()
+	if ft, ok := filterTypes[t]; ok {
+		return ft.name
+	}
+	return strconv.Itoa(int(t))
+}
+
+// ParseFilterType returns the registered filter type with the passed name or number.
+func ParseFilterType(s string) (wire.FilterType, error) {
+	registerLock.RLock()
+	defer registerLock.RUnlock()
+	for t, ft := range filterTypes {
+		if ft.name == s || strconv.Itoa(int(t)) == s {
+			return t, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown filter type %q", s)
+}
@@ -868,6 +868,23 @@ func (a *AddrManager) AddLocalAddress(na *wire.NetAddress, priority AddressPrior
 	return nil
 }
 
+// LocalAddress is a local address known to the address manager along with the priority it is advertised with.
+type LocalAddress struct {
+	NA    *wire.NetAddress
+	Score AddressPriority
+}
+
+// LocalAddresses returns the local addresses that have been added with AddLocalAddress.
+func (a *AddrManager) LocalAddresses() []LocalAddress {
+	a.lamtx.Lock()
+	defer a.lamtx.Unlock()
+	addrs := make([]LocalAddress, 0, len(a.localAddresses))
+	for _, la := range a.localAddresses {
+		addrs = append(addrs, LocalAddress{NA: la.na, Score: la.score})
+	}
+	return addrs
+}
+
 // getReachabilityFrom returns the relative reachability of the provided local address to the provided remote address.
 func getReachabilityFrom(localAddr, remoteAddr *wire.NetAddress) int {
 	const (
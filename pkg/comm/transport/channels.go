@@ -1,14 +1,19 @@
 package transport
 
 import (
+	"bytes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	qu "github.com/p9c/pod/pkg/util/quit"
+	"io"
 	"net"
 	"runtime"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/p9c/pod/pkg/coding/fec"
@@ -22,6 +27,14 @@ const (
 	closed
 	other
 	DefaultPort = 11049
+	// MaxSalts is how many salts other than its own a channel accepts messages encrypted with
+	MaxSalts = 16
+	// NewSaltInterval is how often a channel derives the key for a salt it has not seen before for each source address,
+	// which bounds the expensive work strangers can make it do by sending messages with made up salts
+	NewSaltInterval = time.Second
+	// maxSaltSources is how many source addresses a channel keeps the time it last derived a key for, new sources are
+	// refused while they all derived one within NewSaltInterval
+	maxSaltSources = 256
 )
 
 var DefaultIP = net.IPv4(224, 0, 0, 1)
@@ -38,6 +51,8 @@ type (
 	HandlerFunc func(ctx interface{}, src net.Addr, dst string, b []byte) (err error)
 	Handlers    map[string]HandlerFunc
 	Channel     struct {
+		// seq is the sequence number of the last message sent, it is first so it is aligned for atomic access
+		seq             uint64
 		buffers         map[string]*MsgBuffer
 		Ready           qu.C
 		context         interface{}
@@ -45,13 +60,184 @@ type (
 		firstSender     *string
 		lastSent        *time.Time
 		MaxDatagramSize int
-		receiveCiph     cipher.AEAD
 		Receiver        *net.UDPConn
-		sendCiph        cipher.AEAD
 		Sender          *net.UDPConn
+		key             string
+		sender          senderID
+		replay          *replayCache
+		saltMx          sync.Mutex
+		salt            []byte
+		sendCiph        cipher.AEAD
+		ciphers         map[string]cipher.AEAD
+		salts           []string
+		// advertised holds the salts that were set or added rather than learned from a message, which are kept in
+		// preference to learned ones
+		advertised map[string]struct{}
+		// derived holds the time a key for an unknown salt was last derived for each source address
+		derived map[string]time.Time
 	}
 )
 
+// newChannel returns a channel with a new random sender ID that sends with a key derived from a new random salt
+func newChannel(creator string, ctx interface{}, key string, maxDatagramSize int) (channel *Channel, err error) {
+	channel = &Channel{Creator: creator, MaxDatagramSize: maxDatagramSize, buffers: make(map[string]*MsgBuffer),
+		context: ctx, Ready: qu.T(), key: key, replay: newReplayCache(), ciphers: make(map[string]cipher.AEAD),
+		advertised: make(map[string]struct{}), derived: make(map[string]time.Time)}
+	if _, err = io.ReadFull(rand.Reader, channel.sender[:]); Check(err) {
+		return
+	}
+	var salt []byte
+	if salt, err = gcm.NewSalt(); Check(err) {
+		return
+	}
+	err = channel.SetSalt(salt)
+	return
+}
+
+// Salt returns the salt of the key the channel sends messages with. Receivers that have not seen it before derive the
+// key for it when a message with it arrives, at most once every NewSaltInterval for each sender address.
+func (c *Channel) Salt() []byte {
+	c.saltMx.Lock()
+	defer c.saltMx.Unlock()
+	return c.salt
+}
+
+// SetSalt makes the channel send messages with a key derived from the given salt, such as one learned from the
+// advertisment of the channel it is sending to, so the receiver does not need to derive a key. Messages with the salt
+// are also accepted.
+func (c *Channel) SetSalt(salt []byte) (err error) {
+	c.saltMx.Lock()
+	defer c.saltMx.Unlock()
+	if bytes.Equal(salt, c.salt) {
+		return
+	}
+	var ciph cipher.AEAD
+	if ciph, err = c.cipher(salt); Check(err) {
+		return
+	}
+	c.salt, c.sendCiph = append([]byte{}, salt...), ciph
+	return
+}
+
+// AddSalt makes the channel accept messages with a key derived from the given salt, such as one learned from an
+// advertisment. Only the MaxSalts most recently added are kept, and salts added this way are only forgotten once those
+// learned from messages are.
+func (c *Channel) AddSalt(salt []byte) (err error) {
+	c.saltMx.Lock()
+	defer c.saltMx.Unlock()
+	_, err = c.cipher(salt)
+	return
+}
+
+// cipher returns the cipher for the key derived from a salt that was set or added, deriving and keeping it if the salt
+// is not known yet. This function MUST be called with the salt lock held.
+func (c *Channel) cipher(salt []byte) (ciph cipher.AEAD, err error) {
+	c.advertised[string(salt)] = struct{}{}
+	var ok bool
+	if ciph, ok = c.ciphers[string(salt)]; ok {
+		return
+	}
+	if ciph, err = gcm.GetCipher(c.key, salt); Check(err) {
+		delete(c.advertised, string(salt))
+		return
+	}
+	c.keepCipher(salt, ciph)
+	return
+}
+
+// keepCipher adds the cipher for a salt to those messages are accepted with. If there are more than MaxSalts other than
+// the one being sent with, the oldest learned from a message is forgotten, or the oldest added if there is none. This
+// function MUST be called with the salt lock held.
+func (c *Channel) keepCipher(salt []byte, ciph cipher.AEAD) {
+	if _, ok := c.ciphers[string(salt)]; ok {
+		return
+	}
+	c.ciphers[string(salt)] = ciph
+	c.salts = append(c.salts, string(salt))
+	if len(c.salts) <= MaxSalts+1 {
+		return
+	}
+	forget := -1
+	for i := range c.salts {
+		if c.salts[i] == string(c.salt) {
+			continue
+		}
+		if _, ok := c.advertised[c.salts[i]]; !ok {
+			forget = i
+			break
+		}
+		if forget < 0 {
+			forget = i
+		}
+	}
+	if forget >= 0 {
+		delete(c.ciphers, c.salts[forget])
+		delete(c.advertised, c.salts[forget])
+		c.salts = append(c.salts[:forget], c.salts[forget+1:]...)
+	}
+}
+
+// sendCipher returns the cipher and salt messages are currently sent with
+func (c *Channel) sendCipher() (ciph cipher.AEAD, salt []byte) {
+	c.saltMx.Lock()
+	defer c.saltMx.Unlock()
+	return c.sendCiph, c.salt
+}
+
+// receiveCipher returns the cipher for a salt received from the given source, or nil if the salt is not accepted. Known
+// salts, including those learned from advertisments, are always accepted. The key for an unknown salt is derived if
+// none has been for the address of the source within NewSaltInterval, so a stranger sending made up salts can not use
+// up the derivations for the salts of other senders. In that case derived is true and the caller should keep the
+// cipher with learnSalt once a message has been opened with it, so made up salts are never kept.
+func (c *Channel) receiveCipher(salt []byte, src net.Addr) (ciph cipher.AEAD, derived bool) {
+	c.saltMx.Lock()
+	var ok bool
+	if ciph, ok = c.ciphers[string(salt)]; ok {
+		c.saltMx.Unlock()
+		return
+	}
+	source := src.String()
+	if addr, ok := src.(*net.UDPAddr); ok {
+		source = addr.IP.String()
+	}
+	now := time.Now()
+	if last, ok := c.derived[source]; ok && now.Sub(last) < NewSaltInterval {
+		c.saltMx.Unlock()
+		return
+	}
+	if len(c.derived) >= maxSaltSources {
+		for addr, last := range c.derived {
+			if now.Sub(last) >= NewSaltInterval {
+				delete(c.derived, addr)
+			}
+		}
+		if len(c.derived) >= maxSaltSources {
+			c.saltMx.Unlock()
+			return
+		}
+	}
+	c.derived[source] = now
+	c.saltMx.Unlock()
+	var err error
+	if ciph, err = gcm.GetCipher(c.key, salt); Check(err) {
+		return nil, false
+	}
+	return ciph, true
+}
+
+// learnSalt makes the channel accept messages with a salt whose key was derived by receiveCipher
+func (c *Channel) learnSalt(salt []byte, ciph cipher.AEAD) {
+	c.saltMx.Lock()
+	defer c.saltMx.Unlock()
+	c.keepCipher(salt, ciph)
+}
+
+// newHeader returns the header for the next message sent with the given salt
+func (c *Channel) newHeader(salt []byte) []byte {
+	h := header{salt: salt, sender: c.sender, seq: atomic.AddUint64(&c.seq, 1), sent: time.Now()}
+	return h.encode()
+}
+
 // SetDestination changes the address the outbound connection of a multicast directs to
 func (c *Channel) SetDestination(dst string) (err error) {
 	Debug("sending to", dst)
@@ -67,8 +253,9 @@ func (c *Channel) Send(magic []byte, nonce []byte, data []byte) (n int, err erro
 		Error(err)
 		return
 	}
+	ciph, salt := c.sendCipher()
 	var msg []byte
-	if msg, err = EncryptMessage(c.Creator, c.sendCiph, magic, nonce, data); Check(err) {
+	if msg, err = EncryptMessage(c.Creator, ciph, magic, c.newHeader(salt), nonce, data); Check(err) {
 	}
 	n, err = c.Sender.Write(msg)
 	// DEBUG(msg)
@@ -77,16 +264,52 @@ func (c *Channel) Send(magic []byte, nonce []byte, data []byte) (n int, err erro
 
 // SendMany sends a BufIter of shards as produced by GetShards
 func (c *Channel) SendMany(magic []byte, b [][]byte) (err error) {
-	if nonce, err := GetNonce(c.sendCiph); Check(err) {
-	} else {
-		for i := 0; i < len(b); i++ {
-			// DEBUG(i)
-			if _, err = c.Send(magic, nonce, b[i]); Check(err) {
-				// debug.PrintStack()
-			}
+	ciph, salt := c.sendCipher()
+	return c.sendMany(ciph, salt, magic, b)
+}
+
+// SendManyTo sends a BufIter of shards as produced by GetShards to the given address from the receiving socket of the
+// channel
+func (c *Channel) SendManyTo(addr *net.UDPAddr, magic []byte, b [][]byte) (err error) {
+	ciph, salt := c.sendCipher()
+	return c.sendManyTo(addr, ciph, salt, magic, b)
+}
+
+func (c *Channel) sendMany(ciph cipher.AEAD, salt, magic []byte, b [][]byte) (err error) {
+	var nonce []byte
+	if nonce, err = GetNonce(ciph); Check(err) {
+		return
+	}
+	hdr := c.newHeader(salt)
+	for i := 0; i < len(b); i++ {
+		var msg []byte
+		if msg, err = EncryptMessage(c.Creator, ciph, magic, hdr, nonce, b[i]); Check(err) {
+			return
+		}
+		if _, err = c.Sender.Write(msg); Check(err) {
+			// debug.PrintStack()
+		}
+	}
+	Trace(c.Creator, "sent packets", string(magic), hex.EncodeToString(nonce), c.Sender.LocalAddr(), c.Sender.RemoteAddr())
+	return
+}
+
+func (c *Channel) sendManyTo(addr *net.UDPAddr, ciph cipher.AEAD, salt, magic []byte, b [][]byte) (err error) {
+	var nonce []byte
+	if nonce, err = GetNonce(ciph); Check(err) {
+		return
+	}
+	hdr := c.newHeader(salt)
+	for i := 0; i < len(b); i++ {
+		var msg []byte
+		if msg, err = EncryptMessage(c.Creator, ciph, magic, hdr, nonce, b[i]); Check(err) {
+			return
+		}
+		if _, err = c.Receiver.WriteToUDP(msg, addr); Check(err) {
+			return
 		}
-		Trace(c.Creator, "sent packets", string(magic), hex.EncodeToString(nonce), c.Sender.LocalAddr(), c.Sender.RemoteAddr())
 	}
+	Trace(c.Creator, "sent packets", string(magic), hex.EncodeToString(nonce), c.Receiver.LocalAddr(), addr)
 	return
 }
 
@@ -111,21 +334,14 @@ func GetShards(data []byte) (shards [][]byte) {
 // NewUnicastChannel sets up a listener and sender for a specified destination
 func NewUnicastChannel(creator string, ctx interface{}, key, sender, receiver string, maxDatagramSize int,
 	handlers Handlers, quit qu.C) (channel *Channel, err error) {
-	channel = &Channel{
-		Creator:         creator,
-		MaxDatagramSize: maxDatagramSize,
-		buffers:         make(map[string]*MsgBuffer),
-		context:         ctx,
+	if channel, err = newChannel(creator, ctx, key, maxDatagramSize); Check(err) {
+		return
 	}
 	var magics []string
 
 	for i := range handlers {
 		magics = append(magics, i)
 	}
-	if channel.sendCiph, err = gcm.GetCipher(key); Check(err) {
-	}
-	if channel.receiveCiph, err = gcm.GetCipher(key); Check(err) {
-	}
 	channel.Receiver, err = Listen(receiver, channel, maxDatagramSize, handlers, quit)
 	channel.Sender, err = NewSender(sender, maxDatagramSize)
 	if err != nil {
@@ -135,6 +351,46 @@ func NewUnicastChannel(creator string, ctx interface{}, key, sender, receiver st
 	return
 }
 
+// NewListenerChannel returns a channel that receives messages sent to a unicast address. It has no default
+// destination, messages are sent with SendManyTo from the listening socket so that replies reach senders behind NAT.
+func NewListenerChannel(creator string, ctx interface{}, key, address string, maxDatagramSize int, handlers Handlers,
+	quit qu.C) (channel *Channel, err error) {
+	if channel, err = newChannel(creator, ctx, key, maxDatagramSize); Check(err) {
+		return
+	}
+	var addr *net.UDPAddr
+	if addr, err = net.ResolveUDPAddr("udp4", address); Check(err) {
+		return
+	}
+	if channel.Receiver, err = net.ListenUDP("udp4", addr); Check(err) {
+		return
+	}
+	if err = channel.Receiver.SetReadBuffer(maxDatagramSize); Check(err) {
+	}
+	Debug("starting unicast listener", channel.Creator, channel.Receiver.LocalAddr())
+	go Handle(address, channel, handlers, maxDatagramSize, quit)
+	channel.Ready.Q()
+	return
+}
+
+// NewDialChannel returns a channel that sends messages to a unicast address and receives the replies to them on the
+// same socket, so a listener can reply to it through NAT.
+func NewDialChannel(creator string, ctx interface{}, key, address string, maxDatagramSize int, handlers Handlers,
+	quit qu.C) (channel *Channel, err error) {
+	if channel, err = newChannel(creator, ctx, key, maxDatagramSize); Check(err) {
+		return
+	}
+	if channel.Sender, err = NewSender(address, maxDatagramSize); Check(err) {
+		return
+	}
+	channel.Receiver = channel.Sender
+	if err = channel.Receiver.SetReadBuffer(maxDatagramSize); Check(err) {
+	}
+	go Handle(channel.Sender.LocalAddr().String(), channel, handlers, maxDatagramSize, quit)
+	channel.Ready.Q()
+	return
+}
+
 // NewSender creates a new UDP connection to a specified address
 func NewSender(address string, maxDatagramSize int) (conn *net.UDPConn, err error) {
 	var addr *net.UDPAddr
@@ -174,17 +430,8 @@ func Listen(address string, channel *Channel, maxDatagramSize int, handlers Hand
 // port. The handlers define the messages that will be processed and any other messages are ignored
 func NewBroadcastChannel(creator string, ctx interface{}, key string, port int, maxDatagramSize int, handlers Handlers,
 	quit qu.C) (channel *Channel, err error) {
-	channel = &Channel{Creator: creator, MaxDatagramSize: maxDatagramSize,
-		buffers: make(map[string]*MsgBuffer), context: ctx, Ready: qu.T()}
-	if channel.sendCiph, err = gcm.GetCipher(key); Check(err) {
-	}
-	if channel.sendCiph == nil {
-		panic("nil send cipher")
-	}
-	if channel.receiveCiph, err = gcm.GetCipher(key); Check(err) {
-	}
-	if channel.receiveCiph == nil {
-		panic("nil receive cipher")
+	if channel, err = newChannel(creator, ctx, key, maxDatagramSize); Check(err) {
+		panic("unable to create cipher")
 	}
 	if channel.Receiver, err = ListenBroadcast(port, channel, maxDatagramSize, handlers, quit); Check(err) {
 	}
@@ -276,19 +523,24 @@ out:
 				*channel.lastSent = time.Now()
 			}
 			msg := buffer[:numBytes]
-			nL := channel.receiveCiph.NonceSize()
-			nonceBytes := msg[4 : 4+nL]
-			nonce := string(nonceBytes)
-			// if nonce == seenNonce {
-			// 	DEBUG("seen this one")
-			// 	continue
-			// }
-			// seenNonce = nonce
+			if numBytes < magicSize+headerSize {
+				continue
+			}
+			hdr := decodeHeader(msg)
+			ciph, derived := channel.receiveCipher(hdr.salt, src)
+			if ciph == nil {
+				Trace(channel.Creator, "ignoring", magic, "message with unknown salt from", src)
+				continue
+			}
 			// decipher
 			var shard []byte
-			if shard, err = channel.receiveCiph.Open(nil, nonceBytes, msg[4+len(nonceBytes):], nil); err != nil {
+			if shard, err = DecryptMessage(channel.Creator, ciph, msg); err != nil {
 				continue
 			}
+			if derived {
+				channel.learnSalt(hdr.salt, ciph)
+			}
+			nonce := string(msg[magicSize+headerSize : magicSize+headerSize+ciph.NonceSize()])
 			// DEBUG("read", numBytes, "from", src, err, hex.EncodeToString(msg))
 			if bn, ok := channel.buffers[nonce]; ok {
 				if !bn.Decoded {
@@ -327,6 +579,11 @@ out:
 					}
 				}
 			} else {
+				// only the first shard of a message is checked, later ones are only used if it was accepted
+				if !channel.replay.accept(hdr.sender, hdr.seq, hdr.sent, time.Now()) {
+					Debug(channel.Creator, "dropping replayed or stale", magic, "message from", src)
+					continue
+				}
 				channel.buffers[nonce] = &MsgBuffer{[][]byte{},
 					time.Now(), false, src}
 				channel.buffers[nonce].Buffers = append(channel.buffers[nonce].
@@ -0,0 +1,257 @@
+package transport
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/p9c/pod/pkg/coding/gcm"
+	qu "github.com/p9c/pod/pkg/util/quit"
+)
+
+// TestUnicastChannels checks that a message sent through a dial channel reaches a listener channel, and that the
+// listener's reply to the source address arrives back at the dial channel.
+func TestUnicastChannels(t *testing.T) {
+	quit := qu.T()
+	defer quit.Q()
+	const key = "unicast test"
+	request, reply := []byte("requesting work"), []byte("here is some work")
+	requestMagic, replyMagic := []byte{'r', 'e', 'q', 'u'}, []byte{'r', 'e', 'p', 'l'}
+	received := make(chan []byte, 1)
+	replied := make(chan []byte, 1)
+	var listener *Channel
+	listener, err := NewListenerChannel("listener", nil, key, "127.0.0.1:0", 8192, Handlers{
+		string(requestMagic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+			received <- b
+			return listener.SendManyTo(src.(*net.UDPAddr), replyMagic, GetShards(reply))
+		},
+	}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Receiver.Close()
+	dialer, err := NewDialChannel("dialer", nil, key, listener.Receiver.LocalAddr().String(), 8192, Handlers{
+		string(replyMagic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+			replied <- b
+			return
+		},
+	}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Sender.Close()
+	if err = dialer.SetSalt(listener.Salt()); err != nil {
+		t.Fatal(err)
+	}
+	if err = dialer.SendMany(requestMagic, GetShards(request)); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		name string
+		ch   chan []byte
+		want []byte
+	}{{"request", received, request}, {"reply", replied, reply}} {
+		select {
+		case b := <-c.ch:
+			if !bytes.Equal(b, c.want) {
+				t.Fatalf("%s is %q, want %q", c.name, b, c.want)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("%s was not received", c.name)
+		}
+	}
+}
+
+// TestReplay checks that a message is read by a channel that has not learned the salt of the sender, and that its
+// packets sent again after another message has cleared it from the buffers are not handled twice.
+func TestReplay(t *testing.T) {
+	quit := qu.T()
+	defer quit.Q()
+	const key = "replay test"
+	advert, advertMagic := []byte("here i am"), []byte{'a', 'd', 'v', 't'}
+	other := []byte("here i am too")
+	received := make(chan []byte, 3)
+	listener, err := NewListenerChannel("listener", nil, key, "127.0.0.1:0", 8192, Handlers{
+		string(advertMagic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+			received <- b
+			return
+		},
+	}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Receiver.Close()
+	// capture the packets of a message so they can be sent to the listener twice
+	capture, err := net.ListenUDP("udp4", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer capture.Close()
+	dialer, err := NewDialChannel("dialer", nil, key, capture.LocalAddr().String(), 8192, Handlers{}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dialer.Sender.Close()
+	shards := GetShards(advert)
+	if err = dialer.SendMany(advertMagic, shards); err != nil {
+		t.Fatal(err)
+	}
+	if err = capture.SetReadDeadline(time.Now().Add(time.Second * 5)); err != nil {
+		t.Fatal(err)
+	}
+	packets := make([][]byte, len(shards))
+	for i := range packets {
+		buf := make([]byte, 8192)
+		var n int
+		if n, err = capture.Read(buf); err != nil {
+			t.Fatal(err)
+		}
+		packets[i] = buf[:n]
+	}
+	replayer, err := net.DialUDP("udp4", nil, listener.Receiver.LocalAddr().(*net.UDPAddr))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer replayer.Close()
+	sendPackets := func() {
+		for i := range packets {
+			if _, err = replayer.Write(packets[i]); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	sendPackets()
+	otherDialer, err := NewDialChannel("other", nil, key, listener.Receiver.LocalAddr().String(), 8192, Handlers{}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer otherDialer.Sender.Close()
+	// the listener has just derived the key of the first dialer, so it would not derive another one yet
+	if err = otherDialer.SetSalt(dialer.Salt()); err != nil {
+		t.Fatal(err)
+	}
+	if err = otherDialer.SendMany(advertMagic, GetShards(other)); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond * 100)
+	sendPackets()
+	for _, want := range [][]byte{advert, other} {
+		select {
+		case b := <-received:
+			if !bytes.Equal(b, want) {
+				t.Fatalf("message is %q, want %q", b, want)
+			}
+		case <-time.After(time.Second * 5):
+			t.Fatalf("message %q was not received", want)
+		}
+	}
+	select {
+	case b := <-received:
+		t.Fatalf("replayed message %q was handled", b)
+	case <-time.After(time.Millisecond * 500):
+	}
+}
+
+// TestNewSalts checks that channels send with salts of their own, and that a channel derives the key for a salt it has
+// not seen at most once every NewSaltInterval and keeps it.
+func TestNewSalts(t *testing.T) {
+	quit := qu.T()
+	defer quit.Q()
+	const key = "salt test"
+	magic := []byte{'s', 'a', 'l', 't'}
+	received := make(chan []byte, 4)
+	listener, err := NewListenerChannel("listener", nil, key, "127.0.0.1:0", 8192, Handlers{
+		string(magic): func(ctx interface{}, src net.Addr, dst string, b []byte) (err error) {
+			received <- b
+			return
+		},
+	}, quit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Receiver.Close()
+	dialers := make([]*Channel, 2)
+	for i := range dialers {
+		if dialers[i], err = NewDialChannel("dialer", nil, key, listener.Receiver.LocalAddr().String(), 8192,
+			Handlers{}, quit); err != nil {
+			t.Fatal(err)
+		}
+		defer dialers[i].Sender.Close()
+	}
+	if bytes.Equal(dialers[0].Salt(), dialers[1].Salt()) || bytes.Equal(dialers[0].Salt(), listener.Salt()) {
+		t.Fatal("channels send with the same salt")
+	}
+	expect := func(want []byte, wait time.Duration) {
+		select {
+		case b := <-received:
+			if want == nil {
+				t.Fatalf("message %q with a salt derived too soon was handled", b)
+			}
+			if !bytes.Equal(b, want) {
+				t.Fatalf("message is %q, want %q", b, want)
+			}
+		case <-time.After(wait):
+			if want != nil {
+				t.Fatalf("message %q was not received", want)
+			}
+		}
+	}
+	send := func(c *Channel, msg string) []byte {
+		if err := c.SendMany(magic, GetShards([]byte(msg))); err != nil {
+			t.Fatal(err)
+		}
+		return []byte(msg)
+	}
+	expect(send(dialers[0], "first"), time.Second*5)
+	send(dialers[1], "too soon")
+	expect(nil, NewSaltInterval/2)
+	// the key of the first salt was kept
+	expect(send(dialers[0], "first again"), time.Second*5)
+	time.Sleep(NewSaltInterval)
+	expect(send(dialers[1], "second"), time.Second*5)
+}
+
+// TestSaltSources checks that keys for unknown salts are derived at a bounded rate for each source address rather than
+// for the channel as a whole, and that salts learned from advertisments are accepted and kept in preference to those
+// learned from messages.
+func TestSaltSources(t *testing.T) {
+	c, err := newChannel("test", nil, "salt source test", 8192)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newSalt := func() []byte {
+		salt, err := gcm.NewSalt()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return salt
+	}
+	stranger := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1}
+	if ciph, derived := c.receiveCipher(newSalt(), stranger); ciph == nil || !derived {
+		t.Fatal("key for the first unknown salt of a source was not derived")
+	}
+	if ciph, _ := c.receiveCipher(newSalt(), &net.UDPAddr{IP: stranger.IP, Port: 2}); ciph != nil {
+		t.Fatal("key for an unknown salt was derived again for the same source address")
+	}
+	peer := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 1}
+	if ciph, derived := c.receiveCipher(newSalt(), peer); ciph == nil || !derived {
+		t.Fatal("key for the unknown salt of another source was not derived")
+	}
+	advertised := newSalt()
+	if err = c.AddSalt(advertised); err != nil {
+		t.Fatal(err)
+	}
+	if ciph, derived := c.receiveCipher(advertised, stranger); ciph == nil || derived {
+		t.Fatal("advertised salt was not accepted")
+	}
+	for i := 0; i < MaxSalts+1; i++ {
+		c.learnSalt(newSalt(), c.sendCiph)
+	}
+	if _, ok := c.ciphers[string(advertised)]; !ok {
+		t.Fatal("advertised salt was forgotten before salts learned from messages")
+	}
+	if len(c.ciphers) != MaxSalts+1 {
+		t.Fatalf("channel keeps %d salts, want %d", len(c.ciphers), MaxSalts+1)
+	}
+}
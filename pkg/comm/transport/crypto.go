@@ -3,35 +3,86 @@ package transport
 import (
 	"crypto/cipher"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"time"
+
+	"github.com/p9c/pod/pkg/coding/gcm"
+)
+
+const (
+	magicSize = 4
+	// headerSize is the size of the header that follows the magic of a packet, which is authenticated along with the
+	// message but not encrypted: the salt of the key the packet is encrypted with, the ID of the sending channel, the
+	// sequence number of the message from that channel and the time it was sent in nanoseconds since the unix epoch
+	headerSize = gcm.SaltSize + 8 + 8 + 8
 )
 
-func DecryptMessage(creator string, ciph cipher.AEAD, data []byte) (msg []byte, err error) {
+// header is the decoded header of a packet
+type header struct {
+	salt   []byte
+	sender senderID
+	seq    uint64
+	sent   time.Time
+}
+
+// encode returns the wire form of a header
+func (h *header) encode() (b []byte) {
+	b = make([]byte, headerSize)
+	copy(b, h.salt)
+	copy(b[gcm.SaltSize:], h.sender[:])
+	binary.BigEndian.PutUint64(b[gcm.SaltSize+8:], h.seq)
+	binary.BigEndian.PutUint64(b[gcm.SaltSize+16:], uint64(h.sent.UnixNano()))
+	return
+}
+
+// decodeHeader returns the header of a packet, which must be at least magicSize+headerSize long
+func decodeHeader(packet []byte) (h header) {
+	b := packet[magicSize : magicSize+headerSize]
+	h.salt = b[:gcm.SaltSize]
+	copy(h.sender[:], b[gcm.SaltSize:])
+	h.seq = binary.BigEndian.Uint64(b[gcm.SaltSize+8:])
+	h.sent = time.Unix(0, int64(binary.BigEndian.Uint64(b[gcm.SaltSize+16:])))
+	return
+}
+
+// DecryptMessage opens a packet made by EncryptMessage with the given cipher, checking that its magic and header were
+// not altered
+func DecryptMessage(creator string, ciph cipher.AEAD, packet []byte) (msg []byte, err error) {
 	nonceSize := ciph.NonceSize()
-	msg, err = ciph.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if len(packet) < magicSize+headerSize+nonceSize {
+		err = fmt.Errorf("%s packet of %d bytes is too short", creator, len(packet))
+		return
+	}
+	nonce := packet[magicSize+headerSize : magicSize+headerSize+nonceSize]
+	msg, err = ciph.Open(nil, nonce, packet[magicSize+headerSize+nonceSize:], packet[:magicSize+headerSize])
 	if err != nil {
 		err = errors.New(fmt.Sprintf("%s %s", creator, err.Error()))
 	} else {
-		Debug("decrypted message", hex.EncodeToString(data[:nonceSize]))
+		Trace("decrypted message", hex.EncodeToString(nonce))
 	}
 	return
 }
 
-// EncryptMessage encrypts a message, if the nonce is given it uses that otherwise it generates a new one. If there is
-// no cipher this just returns a message with the given magic prepended.
-func EncryptMessage(creator string, ciph cipher.AEAD, magic []byte, nonce, data []byte) (msg []byte, err error) {
+// EncryptMessage encrypts a message, if the nonce is given it uses that otherwise it generates a new one. The magic
+// and header are sent in the clear but authenticated with the message. If there is no cipher this just returns a
+// message with the given magic prepended.
+func EncryptMessage(creator string, ciph cipher.AEAD, magic, hdr, nonce, data []byte) (msg []byte, err error) {
 	if ciph != nil {
 		if nonce == nil {
-			nonce, err = GetNonce(ciph)
+			if nonce, err = GetNonce(ciph); Check(err) {
+				return
+			}
 		}
-		msg = append(append(magic, nonce...), ciph.Seal(nil, nonce, data, nil)...)
+		msg = make([]byte, 0, len(magic)+len(hdr)+len(nonce)+len(data)+ciph.Overhead())
+		msg = append(append(msg, magic...), hdr...)
+		msg = append(append(msg, nonce...), ciph.Seal(nil, nonce, data, msg[:len(magic)+len(hdr)])...)
 	} else {
-		msg = append(magic, data...)
+		msg = append(append([]byte{}, magic...), data...)
 	}
-
 	return
 }
 
@@ -1,3 +1,9 @@
 // Package transport provides a listener and sender channel for unicast and multicast UDP IPv4 short message chat
-// protocol with a pre shared key, forward error correction facilities with a nice friendly declaration syntax
+// protocol with a pre shared key, forward error correction facilities with a nice friendly declaration syntax.
+//
+// Each channel derives the key it sends with from the pre shared key and a random salt, so a key can not be guessed at
+// ahead of time. The salt is sent with every message, and receivers derive the key for a salt they have not seen
+// before, at most once every NewSaltInterval for each sender address, keeping it if the message opens. Every message
+// carries the ID of the sending channel, a sequence number and the time it was sent, which are authenticated with it,
+// and messages that were already received or sent outside of ReplayWindow are dropped.
 package transport
@@ -0,0 +1,94 @@
+package transport
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// ReplayWindow is how far the time a message says it was sent may be from the time it is received. Clocks of the
+	// machines on a channel must agree to within it.
+	ReplayWindow = time.Minute * 2
+	// MaxSenders is how many senders the replay cache of a channel remembers the sequence numbers of
+	MaxSenders = 1024
+	// seqWindow is how far behind the latest message from a sender another may arrive out of order
+	seqWindow = 64
+)
+
+// senderID is the random identity of a channel that messages are sent from
+type senderID [8]byte
+
+// senderState is the sliding window of sequence numbers received from a sender. Bit n of seen is set if the message
+// numbered latest-n was received.
+type senderState struct {
+	latest   uint64
+	seen     uint64
+	lastSeen time.Time
+}
+
+// replayCache rejects messages that were already received, or that were sent too long ago for the cache to know
+type replayCache struct {
+	sync.Mutex
+	senders map[senderID]*senderState
+}
+
+func newReplayCache() *replayCache {
+	return &replayCache{senders: make(map[senderID]*senderState)}
+}
+
+// accept returns true and records the message if a message with the given sender, sequence number and send time has
+// not been received before and was sent within ReplayWindow of now
+func (r *replayCache) accept(sender senderID, seq uint64, sent, now time.Time) bool {
+	if sent.Before(now.Add(-ReplayWindow)) || sent.After(now.Add(ReplayWindow)) {
+		return false
+	}
+	r.Lock()
+	defer r.Unlock()
+	s, ok := r.senders[sender]
+	switch {
+	case !ok:
+		r.prune(now)
+		s = &senderState{latest: seq, seen: 1}
+		r.senders[sender] = s
+	case seq > s.latest:
+		if shift := seq - s.latest; shift < seqWindow {
+			s.seen = s.seen<<shift | 1
+		} else {
+			s.seen = 1
+		}
+		s.latest = seq
+	case s.latest-seq >= seqWindow:
+		return false
+	default:
+		bit := uint64(1) << (s.latest - seq)
+		if s.seen&bit != 0 {
+			return false
+		}
+		s.seen |= bit
+	}
+	s.lastSeen = now
+	return true
+}
+
+// prune makes room for a new sender once the cache is full. Senders not heard from for twice the ReplayWindow are
+// forgotten, as any message of theirs that arrived after that would be too old to be accepted, and if that does not
+// free a place the sender heard from least recently is forgotten. This function MUST be called with the lock held.
+func (r *replayCache) prune(now time.Time) {
+	if len(r.senders) < MaxSenders {
+		return
+	}
+	var oldest senderID
+	var oldestSeen time.Time
+	for id, s := range r.senders {
+		if now.Sub(s.lastSeen) > ReplayWindow*2 {
+			delete(r.senders, id)
+			continue
+		}
+		if oldestSeen.IsZero() || s.lastSeen.Before(oldestSeen) {
+			oldest, oldestSeen = id, s.lastSeen
+		}
+	}
+	if len(r.senders) >= MaxSenders {
+		delete(r.senders, oldest)
+	}
+}
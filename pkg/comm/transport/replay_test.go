@@ -0,0 +1,58 @@
+package transport
+
+import (
+	"testing"
+	"time"
+)
+
+// TestReplayCache checks that messages are accepted once, in or out of order, and that messages sent too long ago or
+// too far behind the latest from their sender are rejected.
+func TestReplayCache(t *testing.T) {
+	r := newReplayCache()
+	now := time.Now()
+	a, b := senderID{1}, senderID{2}
+	for i, c := range []struct {
+		sender senderID
+		seq    uint64
+		sent   time.Time
+		want   bool
+	}{
+		{a, 10, now, true},
+		{a, 10, now, false},
+		{b, 10, now, true},
+		{a, 12, now, true},
+		{a, 11, now, true},
+		{a, 11, now, false},
+		{a, 12, now, false},
+		{a, 100, now, true},
+		{a, 36, now, false},
+		{a, 37, now, true},
+		{a, 101, now.Add(-ReplayWindow - time.Second), false},
+		{a, 102, now.Add(ReplayWindow + time.Second), false},
+		{a, 103, now.Add(-ReplayWindow / 2), true},
+	} {
+		if got := r.accept(c.sender, c.seq, c.sent, now); got != c.want {
+			t.Errorf("%d: sender %x seq %d accepted %v, want %v", i, c.sender, c.seq, got, c.want)
+		}
+	}
+}
+
+// TestReplayCachePrune checks that the cache remembers no more than MaxSenders, forgetting the least recently heard
+// from first.
+func TestReplayCachePrune(t *testing.T) {
+	r := newReplayCache()
+	now := time.Now()
+	for i := 0; i < MaxSenders+1; i++ {
+		var id senderID
+		id[0], id[1] = byte(i), byte(i>>8)
+		if !r.accept(id, 1, now, now.Add(time.Duration(i))) {
+			t.Fatalf("sender %d was rejected", i)
+		}
+	}
+	if len(r.senders) != MaxSenders {
+		t.Fatalf("cache has %d senders, want %d", len(r.senders), MaxSenders)
+	}
+	if _, ok := r.senders[senderID{}]; ok {
+		t.Fatal("least recently heard from sender was not forgotten")
+	}
+}
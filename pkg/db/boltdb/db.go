@@ -0,0 +1,1241 @@
+package boltdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	bolt "github.com/coreos/bbolt"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const (
+	// dataFileName is the name of the bolt database file within the database directory.
+	dataFileName = "blocks.db"
+	// blockHdrSize is the size of a block header. This is simply the constant from wire and is only provided here for
+	// convenience since wire.MaxBlockHeaderPayload is quite long.
+	blockHdrSize = wire.MaxBlockHeaderPayload
+	// openTimeout is how long opening the database waits for another process holding it open to close it.
+	openTimeout = 10 * time.Second
+	// initialMmapSize is the size the database file is initially memory mapped with. The map only has to be grown,
+	// which waits for every open read transaction to finish, once the database is larger than this.
+	initialMmapSize = 256 * 1024 * 1024
+)
+
+var (
+	// byteOrder is the preferred byte order used through the database. Sometimes big endian will be used to allow
+	// ordered byte sortable integer values.
+	byteOrder = binary.LittleEndian
+	// metadataBucketName is the name of the top level bucket that holds the metadata returned by Tx.Metadata.
+	metadataBucketName = []byte("metadata")
+	// blocksBucketName is the name of the top level bucket that holds the serialized blocks keyed by their hash.
+	blocksBucketName = []byte("blocks")
+	// blockOrderBucketName is the name of the top level bucket that holds the hash of every stored block keyed by a
+	// big endian sequence number, so the blocks can be visited in the order they were stored when pruning.
+	blockOrderBucketName = []byte("blockorder")
+	// infoBucketName is the name of the top level bucket that holds the internal state of the driver.
+	infoBucketName = []byte("info")
+	// networkKeyName is the name of the info key that holds the block network the database was created for.
+	networkKeyName = []byte("network")
+	// blocksSizeKeyName is the name of the info key that holds the total size in bytes of the stored blocks.
+	blocksSizeKeyName = []byte("blockssize")
+	// prunedKeyName is the name of the info key that is set once blocks were deleted by PruneBlocks.
+	prunedKeyName = []byte("pruned")
+)
+
+// Common error strings.
+const (
+	// errDbNotOpenStr is the text to use for the database.ErrDbNotOpen error code.
+	errDbNotOpenStr = "database is not open"
+	// errTxClosedStr is the text to use for the database.ErrTxClosed error code.
+	errTxClosedStr = "database tx is closed"
+)
+
+// makeDbErr creates a database.DBError given a set of arguments.
+func makeDbErr(c database.ErrorCode, desc string, err error) database.DBError {
+	return database.DBError{ErrorCode: c, Description: desc, Err: err}
+}
+
+// convertErr converts the passed bolt error into a database error with an equivalent error code and the passed
+// description.
+//
+// It also sets the passed error as the underlying error.
+func convertErr(desc string, boltErr error) database.DBError {
+	// Use the driver-specific error code by default.
+	//
+	// The code below will update this with the converted error if it's recognized.
+	var code = database.ErrDriverSpecific
+	switch boltErr {
+	// Database open/create errors.
+	case bolt.ErrDatabaseNotOpen:
+		code = database.ErrDbNotOpen
+	case bolt.ErrTimeout:
+		code = database.ErrDbAlreadyOpen
+	// Database corruption errors.
+	case bolt.ErrInvalid, bolt.ErrVersionMismatch, bolt.ErrChecksum:
+		code = database.ErrCorruption
+	// Transaction errors.
+	case bolt.ErrTxNotWritable:
+		code = database.ErrTxNotWritable
+	case bolt.ErrTxClosed:
+		code = database.ErrTxClosed
+	// Value/bucket errors.
+	case bolt.ErrBucketNotFound:
+		code = database.ErrBucketNotFound
+	case bolt.ErrBucketExists:
+		code = database.ErrBucketExists
+	case bolt.ErrBucketNameRequired:
+		code = database.ErrBucketNameRequired
+	case bolt.ErrKeyRequired:
+		code = database.ErrKeyRequired
+	case bolt.ErrKeyTooLarge:
+		code = database.ErrKeyTooLarge
+	case bolt.ErrValueTooLarge:
+		code = database.ErrValueTooLarge
+	case bolt.ErrIncompatibleValue:
+		code = database.ErrIncompatibleValue
+	}
+	return database.DBError{ErrorCode: code, Description: desc, Err: boltErr}
+}
+
+// copySlice returns a copy of the passed slice.
+//
+// Keys and values read from bolt point into its memory map, which may be remapped or reused once the transaction ends,
+// so they are copied before being handed out to keep them valid afterwards like those returned by ffldb.
+func copySlice(slice []byte) []byte {
+	ret := make([]byte, len(slice))
+	copy(ret, slice)
+	return ret
+}
+
+// cursor is an internal type used to represent a cursor over key/value pairs and nested buckets of a bucket and
+// implements the database.Cursor interface.
+type cursor struct {
+	bucket     *bucket
+	boltCursor *bolt.Cursor
+	// The key and value the cursor is at. The value is nil for a nested bucket and the key is nil once the cursor is
+	// exhausted.
+	key   []byte
+	value []byte
+	// deleted is set when the entry the cursor is at was deleted, so moving the cursor seeks from its key rather than
+	// relying on the position of the bolt cursor.
+	deleted bool
+}
+
+// Enforce cursor implements the database.Cursor interface.
+var _ database.Cursor = (*cursor)(nil)
+
+// set positions the cursor at the passed key and value and returns whether it is at an entry.
+func (c *cursor) set(key, value []byte) bool {
+	c.key, c.value, c.deleted = key, value, false
+	return key != nil
+}
+
+// Bucket returns the bucket the cursor was created for.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Bucket() database.Bucket {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return nil
+	}
+	return c.bucket
+}
+
+// Delete removes the current key/value pair the cursor is at without invalidating the cursor.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrIncompatibleValue if attempted when the cursor points to a nested bucket
+//
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Delete() error {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return err
+	}
+	// Ensure the transaction is writable.
+	if !c.bucket.tx.writable {
+		str := "deleting a value requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// DBError if the cursor is exhausted.
+	if c.key == nil || c.deleted {
+		str := "cursor is exhausted"
+		return makeDbErr(database.ErrIncompatibleValue, str, nil)
+	}
+	// Do not allow buckets to be deleted via the cursor.
+	if c.value == nil {
+		str := "buckets may not be deleted from a cursor"
+		return makeDbErr(database.ErrIncompatibleValue, str, nil)
+	}
+	if err := c.boltCursor.Delete(); err != nil {
+		str := fmt.Sprintf("failed to delete key %q", c.key)
+		return convertErr(str, err)
+	}
+	c.deleted = true
+	return nil
+}
+
+// First positions the cursor at the first key/value pair and returns whether or not the pair exists.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) First() bool {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return false
+	}
+	return c.set(c.boltCursor.First())
+}
+
+// Last positions the cursor at the last key/value pair and returns whether or not the pair exists.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Last() bool {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return false
+	}
+	return c.set(c.boltCursor.Last())
+}
+
+// Next moves the cursor one key/value pair forward and returns whether or not the pair exists.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Next() bool {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return false
+	}
+	// Nothing to return if cursor is exhausted.
+	if c.key == nil {
+		return false
+	}
+	// The first entry after a deleted one is the first at or after its key.
+	if c.deleted {
+		return c.set(c.boltCursor.Seek(c.key))
+	}
+	return c.set(c.boltCursor.Next())
+}
+
+// Prev moves the cursor one key/value pair backward and returns whether or not the pair exists.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Prev() bool {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return false
+	}
+	// Nothing to return if cursor is exhausted.
+	if c.key == nil {
+		return false
+	}
+	// The last entry before a deleted one is the one before the first at or after its key, or the last entry when
+	// there is none after it.
+	if c.deleted {
+		if key, _ := c.boltCursor.Seek(c.key); key == nil {
+			return c.set(c.boltCursor.Last())
+		}
+	}
+	return c.set(c.boltCursor.Prev())
+}
+
+// Seek positions the cursor at the first key/value pair that is greater than or equal to the passed seek key.
+//
+// Returns false if no suitable key was found.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Seek(seek []byte) bool {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return false
+	}
+	return c.set(c.boltCursor.Seek(seek))
+}
+
+// Key returns the current key the cursor is pointing to.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Key() []byte {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return nil
+	}
+	// Nothing to return if cursor is exhausted.
+	if c.key == nil {
+		return nil
+	}
+	return copySlice(c.key)
+}
+
+// Value returns the current value the cursor is pointing to.
+//
+// This will be nil for nested buckets.
+//
+// This function is part of the database.Cursor interface implementation.
+func (c *cursor) Value() []byte {
+	// Ensure transaction state is valid.
+	if err := c.bucket.tx.checkClosed(); err != nil {
+		return nil
+	}
+	// Nothing to return if cursor is exhausted, was deleted or is pointing to a nested bucket.
+	if c.key == nil || c.deleted || c.value == nil {
+		return nil
+	}
+	return copySlice(c.value)
+}
+
+// bucket is an internal type used to represent a collection of key/value pairs and implements the database.Bucket
+// interface.
+type bucket struct {
+	tx         *transaction
+	boltBucket *bolt.Bucket
+}
+
+// Enforce bucket implements the database.Bucket interface.
+var _ database.Bucket = (*bucket)(nil)
+
+// Bucket retrieves a nested bucket with the given key.
+//
+// Returns nil if the bucket does not exist.
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) Bucket(key []byte) database.Bucket {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return nil
+	}
+	childBucket := b.boltBucket.Bucket(key)
+	if childBucket == nil {
+		return nil
+	}
+	return &bucket{tx: b.tx, boltBucket: childBucket}
+}
+
+// CreateBucket creates and returns a new nested bucket with the given key.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBucketExists if the bucket already exists
+//
+//   - ErrBucketNameRequired if the key is empty
+//
+//   - ErrIncompatibleValue if the key is the same as an existing key
+//
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) CreateBucket(key []byte) (database.Bucket, error) {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return nil, err
+	}
+	// Ensure the transaction is writable.
+	if !b.tx.writable {
+		str := "create bucket requires a writable database transaction"
+		return nil, makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// Ensure a key was provided.
+	if len(key) == 0 {
+		str := "create bucket requires a key"
+		return nil, makeDbErr(database.ErrBucketNameRequired, str, nil)
+	}
+	childBucket, err := b.boltBucket.CreateBucket(key)
+	if err != nil {
+		str := fmt.Sprintf("failed to create bucket with key %q", key)
+		return nil, convertErr(str, err)
+	}
+	return &bucket{tx: b.tx, boltBucket: childBucket}, nil
+}
+
+// CreateBucketIfNotExists creates and returns a new nested bucket with the given key if it does not already exist.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBucketNameRequired if the key is empty
+//
+//   - ErrIncompatibleValue if the key is the same as an existing key
+//
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) CreateBucketIfNotExists(key []byte) (database.Bucket, error) {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return nil, err
+	}
+	// Ensure the transaction is writable.
+	if !b.tx.writable {
+		str := "create bucket requires a writable database transaction"
+		return nil, makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// Return existing bucket if it already exists, otherwise create it.
+	if bucket := b.Bucket(key); bucket != nil {
+		return bucket, nil
+	}
+	return b.CreateBucket(key)
+}
+
+// DeleteBucket removes a nested bucket with the given key.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBucketNotFound if the specified bucket does not exist
+//
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) DeleteBucket(key []byte) error {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return err
+	}
+	// Ensure the transaction is writable.
+	if !b.tx.writable {
+		str := "delete bucket requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// Bolt removes all nested buckets and their keys along with the bucket.
+	if err := b.boltBucket.DeleteBucket(key); err != nil {
+		str := fmt.Sprintf("bucket %q does not exist", key)
+		return convertErr(str, err)
+	}
+	return nil
+}
+
+// Cursor returns a new cursor, allowing for iteration over the bucket's key/value pairs and nested buckets in forward
+// or backward order.
+//
+// You must seek to a position using the First, Last, or Seek functions before calling the Next, Prev, Key, or value
+// functions. Failure to do so will result in the same return values as an exhausted cursor, which is false for the Prev
+// and Next functions and nil for Key and value functions.
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) Cursor() database.Cursor {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return &cursor{bucket: b}
+	}
+	return &cursor{bucket: b, boltCursor: b.boltBucket.Cursor()}
+}
+
+// ForEach invokes the passed function with every key/value pair in the bucket. This does not include nested buckets or
+// the key/value pairs within those nested buckets.
+//
+// WARNING: It is not safe to mutate data while iterating with this method.
+//
+// Doing so may cause the underlying cursor to be invalidated and return unexpected keys and/or values.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) ForEach(fn func(k, v []byte) error) error {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return err
+	}
+	// Invoke the callback for each key/value pair, skipping nested buckets which have no value. Return the error
+	// returned from the callback when it is non-nil.
+	return b.boltBucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+		return fn(copySlice(k), copySlice(v))
+	})
+}
+
+// ForEachBucket invokes the passed function with the key of every nested bucket in the current bucket.
+//
+// This does not include any nested buckets within those nested buckets.
+//
+// WARNING: It is not safe to mutate data while iterating with this method.
+//
+// Doing so may cause the underlying cursor to be invalidated and return unexpected keys.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) ForEachBucket(fn func(k []byte) error) error {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return err
+	}
+	// Invoke the callback for each nested bucket, which are the keys without a value. Return the error returned from
+	// the callback when it is non-nil.
+	return b.boltBucket.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return nil
+		}
+		return fn(copySlice(k))
+	})
+}
+
+// Writable returns whether or not the bucket is writable.
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) Writable() bool {
+	return b.tx.writable
+}
+
+// Put saves the specified key/value pair to the bucket.
+//
+// Keys that do not already exist are added and keys that already exist are overwritten.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrKeyRequired if the key is empty
+//   - ErrIncompatibleValue if the key is the same as an existing bucket
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) Put(key, value []byte) error {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return err
+	}
+	// Ensure the transaction is writable.
+	if !b.tx.writable {
+		str := "setting a key requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// Ensure a key was provided.
+	if len(key) == 0 {
+		str := "put requires a key"
+		return makeDbErr(database.ErrKeyRequired, str, nil)
+	}
+	// Bolt keeps references to the passed slices until the transaction ends, so they are copied in case the caller
+	// reuses them. This also stores a nil value as an empty one, which tells it apart from a nested bucket.
+	if err := b.boltBucket.Put(copySlice(key), copySlice(value)); err != nil {
+		str := fmt.Sprintf("failed to put key %q", key)
+		return convertErr(str, err)
+	}
+	return nil
+}
+
+// Get returns the value for the given key.
+//
+// Returns nil if the key does not exist in this bucket.
+//
+// An empty slice is returned for keys that exist but have no value assigned.
+//
+// NOTE: The value returned by this function must NOT be modified by the caller.
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) Get(key []byte) []byte {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return nil
+	}
+	// Nothing to return if there is no key.
+	if len(key) == 0 {
+		return nil
+	}
+	value := b.boltBucket.Get(key)
+	if value == nil {
+		return nil
+	}
+	return copySlice(value)
+}
+
+// Delete removes the specified key from the bucket.
+//
+// Deleting a key that does not exist does not return an error.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrKeyRequired if the key is empty
+//   - ErrIncompatibleValue if the key is the same as an existing bucket
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Bucket interface implementation.
+func (b *bucket) Delete(key []byte) error {
+	// Ensure transaction state is valid.
+	if err := b.tx.checkClosed(); err != nil {
+		return err
+	}
+	// Ensure the transaction is writable.
+	if !b.tx.writable {
+		str := "deleting a value requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// Nothing to do if there is no key.
+	if len(key) == 0 {
+		return nil
+	}
+	if err := b.boltBucket.Delete(key); err != nil {
+		str := fmt.Sprintf("failed to delete key %q", key)
+		return convertErr(str, err)
+	}
+	return nil
+}
+
+// transaction represents a database transaction.
+//
+// It can either be read-only or read-write and implements the database.Tx interface. The transaction provides a root
+// bucket against which all read and writes occur.
+type transaction struct {
+	managed    bool         // Is the transaction managed?
+	closed     bool         // Is the transaction closed?
+	writable   bool         // Is the transaction writable?
+	db         *db          // DB instance the tx was created from.
+	boltTx     *bolt.Tx     // Underlying bolt transaction.
+	metaBucket *bucket      // The root metadata bucket.
+	blocks     *bolt.Bucket // The bucket of stored blocks.
+	blockOrder *bolt.Bucket // The bucket of stored block hashes in the order they were stored.
+	infoBucket *bolt.Bucket // The bucket of internal driver state.
+}
+
+// Enforce transaction implements the database.Tx interface.
+var _ database.Tx = (*transaction)(nil)
+
+// checkClosed returns an error if the the database or transaction is closed.
+func (tx *transaction) checkClosed() error {
+	// The transaction is no longer valid if it has been closed.
+	if tx.closed {
+		return makeDbErr(database.ErrTxClosed, errTxClosedStr, nil)
+	}
+	return nil
+}
+
+// Metadata returns the top-most bucket for all metadata storage.
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) Metadata() database.Bucket {
+	return tx.metaBucket
+}
+
+// blocksSize returns the total size in bytes of the stored blocks.
+func (tx *transaction) blocksSize() uint64 {
+	if row := tx.infoBucket.Get(blocksSizeKeyName); len(row) == 8 {
+		return byteOrder.Uint64(row)
+	}
+	return 0
+}
+
+// putBlocksSize stores the total size in bytes of the stored blocks.
+func (tx *transaction) putBlocksSize(size uint64) error {
+	var row [8]byte
+	byteOrder.PutUint64(row[:], size)
+	if err := tx.infoBucket.Put(blocksSizeKeyName, row[:]); err != nil {
+		return convertErr("failed to store blocks size", err)
+	}
+	return nil
+}
+
+// StoreBlock stores the provided block into the database.
+//
+// There are no checks to ensure the block connects to a previous block, contains double spends, or any additional
+// functionality such as transaction indexing.
+//
+// It simply stores the block in the database.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockExists when the block hash already exists
+//
+//   - ErrTxNotWritable if attempted against a read-only transaction
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) StoreBlock(block *util.Block) error {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return err
+	}
+	// Ensure the transaction is writable.
+	if !tx.writable {
+		str := "store block requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	// Reject the block if it already exists.
+	blockHash := block.Hash()
+	if tx.blocks.Get(blockHash[:]) != nil {
+		str := fmt.Sprintf("block %s already exists", blockHash)
+		return makeDbErr(database.ErrBlockExists, str, nil)
+	}
+	blockBytes, err := block.Bytes()
+	if err != nil {
+		Error(err)
+		str := fmt.Sprintf("failed to get serialized bytes for block %s",
+			blockHash)
+		return makeDbErr(database.ErrDriverSpecific, str, err)
+	}
+	// Store the block along with its position in the store order, which is the next sequence number of the order
+	// bucket.
+	seq, err := tx.blockOrder.NextSequence()
+	if err != nil {
+		return convertErr("failed to get the next block sequence number", err)
+	}
+	var seqKey [8]byte
+	binary.BigEndian.PutUint64(seqKey[:], seq)
+	if err = tx.blocks.Put(copySlice(blockHash[:]), blockBytes); err != nil {
+		str := fmt.Sprintf("failed to store block %s", blockHash)
+		return convertErr(str, err)
+	}
+	if err = tx.blockOrder.Put(seqKey[:], copySlice(blockHash[:])); err != nil {
+		str := fmt.Sprintf("failed to store the order of block %s", blockHash)
+		return convertErr(str, err)
+	}
+	return tx.putBlocksSize(tx.blocksSize() + uint64(len(blockBytes)))
+}
+
+// HasBlock returns whether or not a block with the given hash exists in the database.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) HasBlock(hash *chainhash.Hash) (bool, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return false, err
+	}
+	return tx.blocks.Get(hash[:]) != nil, nil
+}
+
+// HasBlocks returns whether or not the blocks with the provided hashes exist in the database.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) HasBlocks(hashes []chainhash.Hash) ([]bool, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return nil, err
+	}
+	results := make([]bool, len(hashes))
+	for i := range hashes {
+		results[i] = tx.blocks.Get(hashes[i][:]) != nil
+	}
+	return results, nil
+}
+
+// fetchBlock returns the stored bytes of the block with the provided hash without copying them. It will return
+// ErrBlockNotFound if there is no such block.
+func (tx *transaction) fetchBlock(hash *chainhash.Hash) ([]byte, error) {
+	blockBytes := tx.blocks.Get(hash[:])
+	if blockBytes == nil {
+		str := fmt.Sprintf("block %s does not exist", hash)
+		return nil, makeDbErr(database.ErrBlockNotFound, str, nil)
+	}
+	return blockBytes, nil
+}
+
+// FetchBlockHeader returns the raw serialized bytes for the block header identified by the given hash.
+//
+// The raw bytes are in the format returned by Serialize on a wire.BlockHeader.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockNotFound if the requested block hash does not exist
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) FetchBlockHeader(hash *chainhash.Hash) ([]byte, error) {
+	return tx.FetchBlockRegion(&database.BlockRegion{
+		Hash:   hash,
+		Offset: 0,
+		Len:    blockHdrSize,
+	})
+}
+
+// FetchBlockHeaders returns the raw serialized bytes for the block headers identified by the given hashes.
+//
+// The raw bytes are in the format returned by Serialize on a wire.BlockHeader.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockNotFound if the any of the requested block hashes do not exist
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) FetchBlockHeaders(hashes []chainhash.Hash) ([][]byte, error) {
+	regions := make([]database.BlockRegion, len(hashes))
+	for i := range hashes {
+		regions[i].Hash = &hashes[i]
+		regions[i].Offset = 0
+		regions[i].Len = blockHdrSize
+	}
+	return tx.FetchBlockRegions(regions)
+}
+
+// FetchBlock returns the raw serialized bytes for the block identified by the given hash.
+//
+// The raw bytes are in the format returned by Serialize on a wire.MsgBlock.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockNotFound if the requested block hash does not exist
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) FetchBlock(hash *chainhash.Hash) ([]byte, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return nil, err
+	}
+	blockBytes, err := tx.fetchBlock(hash)
+	if err != nil {
+		return nil, err
+	}
+	return copySlice(blockBytes), nil
+}
+
+// FetchBlocks returns the raw serialized bytes for the blocks identified by the given hashes.
+//
+// The raw bytes are in the format returned by Serialize on a wire.MsgBlock.
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockNotFound if any of the requested block hashed do not exist
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) FetchBlocks(hashes []chainhash.Hash) ([][]byte, error) {
+	blocks := make([][]byte, len(hashes))
+	for i := range hashes {
+		var err error
+		blocks[i], err = tx.FetchBlock(&hashes[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// FetchBlockRegion returns the raw serialized bytes for the given block region.
+//
+// The raw bytes are in the format returned by Serialize on a wire.MsgBlock and the Offset field in the provided
+// BlockRegion is zero-based and relative to the start of the block (byte 0).
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockNotFound if the requested block hash does not exist
+//
+//   - ErrBlockRegionInvalid if the region exceeds the bounds of the associated block
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) FetchBlockRegion(region *database.BlockRegion) ([]byte, error) {
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return nil, err
+	}
+	blockBytes, err := tx.fetchBlock(region.Hash)
+	if err != nil {
+		return nil, err
+	}
+	// Ensure the region is within the bounds of the block.
+	endOffset := region.Offset + region.Len
+	if endOffset < region.Offset || endOffset > uint32(len(blockBytes)) {
+		str := fmt.Sprintf("block %s region offset %d, length %d "+
+			"exceeds block length of %d", region.Hash,
+			region.Offset, region.Len, len(blockBytes))
+		return nil, makeDbErr(database.ErrBlockRegionInvalid, str, nil)
+	}
+	return copySlice(blockBytes[region.Offset:endOffset]), nil
+}
+
+// FetchBlockRegions returns the raw serialized bytes for the given block regions.
+//
+// The raw bytes are in the format returned by Serialize on a wire.MsgBlock and the Offset fields in the provided
+// BlockRegions are zero-based and relative to the start of the block (byte 0).
+//
+// Returns the following errors as required by the interface contract:
+//
+//   - ErrBlockNotFound if any of the request block hashes do not exist
+//
+//   - ErrBlockRegionInvalid if one or more region exceed the bounds of the associated block
+//
+//   - ErrTxClosed if the transaction has already been closed
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) FetchBlockRegions(regions []database.BlockRegion) ([][]byte, error) {
+	blockRegions := make([][]byte, len(regions))
+	for i := range regions {
+		var err error
+		blockRegions[i], err = tx.FetchBlockRegion(&regions[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blockRegions, nil
+}
+
+// close marks the transaction closed then rolls back the underlying bolt transaction if it is still open and releases
+// the transaction read lock.
+func (tx *transaction) close() {
+	tx.closed = true
+	if tx.boltTx.DB() != nil {
+		_ = tx.boltTx.Rollback()
+	}
+	tx.db.closeLock.RUnlock()
+}
+
+// Commit commits all changes that have been made to the root metadata bucket and all of its sub-buckets and the stored
+// blocks to the database.
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) Commit() error {
+	// Prevent commits on managed transactions.
+	if tx.managed {
+		tx.close()
+		panic("managed transaction commit not allowed")
+	}
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return err
+	}
+	// Regardless of whether the commit succeeds, the transaction is closed on return.
+	defer tx.close()
+	// Ensure the transaction is writable.
+	if !tx.writable {
+		str := "Commit requires a writable database transaction"
+		return makeDbErr(database.ErrTxNotWritable, str, nil)
+	}
+	if err := tx.boltTx.Commit(); err != nil {
+		return convertErr("failed to commit transaction", err)
+	}
+	return nil
+}
+
+// Rollback undoes all changes that have been made to the root bucket and all of its sub-buckets.
+//
+// This function is part of the database.Tx interface implementation.
+func (tx *transaction) Rollback() error {
+	// Prevent rollbacks on managed transactions.
+	if tx.managed {
+		tx.close()
+		panic("managed transaction rollback not allowed")
+	}
+	// Ensure transaction state is valid.
+	if err := tx.checkClosed(); err != nil {
+		return err
+	}
+	tx.close()
+	return nil
+}
+
+// db represents a collection of namespaces which are persisted and implements the database.DB interface. All database
+// access is performed through transactions which are obtained through the specific Namespace.
+type db struct {
+	closeLock sync.RWMutex // Make database close block while txns active.
+	closed    bool         // Is the database closed?
+	boltDB    *bolt.DB     // The underlying bolt database.
+}
+
+// Enforce db implements the database.DB interface.
+var _ database.DB = (*db)(nil)
+
+// Type returns the database driver type the current database instance was created with.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) Type() string {
+	return dbType
+}
+
+// begin is the implementation function for the Begin database method.
+//
+// See its documentation for more details.
+//
+// This function is only separate because it returns the internal transaction which is used by the managed transaction
+// code while the database method returns the interface.
+func (db *db) begin(writable bool) (*transaction, error) {
+	// Whenever a new transaction is started, grab a read lock against the database to ensure Close will wait for the
+	// transaction to finish.
+	//
+	// This lock will not be released until the transaction is closed (via Rollback or Commit).
+	db.closeLock.RLock()
+	if db.closed {
+		db.closeLock.RUnlock()
+		return nil, makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr,
+			nil)
+	}
+	// Bolt only allows a single read-write transaction at a time and blocks starting another until it is closed.
+	boltTx, err := db.boltDB.Begin(writable)
+	if err != nil {
+		Error(err)
+		db.closeLock.RUnlock()
+		return nil, convertErr("failed to begin transaction", err)
+	}
+	tx := &transaction{
+		writable:   writable,
+		db:         db,
+		boltTx:     boltTx,
+		blocks:     boltTx.Bucket(blocksBucketName),
+		blockOrder: boltTx.Bucket(blockOrderBucketName),
+		infoBucket: boltTx.Bucket(infoBucketName),
+	}
+	tx.metaBucket = &bucket{tx: tx, boltBucket: boltTx.Bucket(metadataBucketName)}
+	return tx, nil
+}
+
+// Begin starts a transaction which is either read-only or read-write depending on the specified flag.
+//
+// Multiple read-only transactions can be started simultaneously while only a single read-write transaction can be
+// started at a time.
+//
+// The call will block when starting a read-write transaction when one is already open.
+//
+// NOTE: The transaction must be closed by calling Rollback or Commit on it when it is no longer needed.
+//
+// Failure to do so will prevent the database from being closed and the memory map from being grown.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) Begin(writable bool) (database.Tx, error) {
+	return db.begin(writable)
+}
+
+// rollbackOnPanic rolls the passed transaction back if the code in the calling function panics. This is needed since
+// the mutex on a transaction must be released and a panic in called code would prevent that from happening.
+func rollbackOnPanic(tx *transaction) {
+	if err := recover(); err != nil {
+		tx.managed = false
+		_ = tx.Rollback()
+		panic(err)
+	}
+}
+
+// View invokes the passed function in the context of a managed read-only transaction with the root bucket for the
+// namespace.
+//
+// Any errors returned from the user-supplied function are returned from this function. This function is part of the
+// database.DB interface implementation.
+func (db *db) View(fn func(database.Tx) error) error {
+	// Start a read-only transaction.
+	tx, err := db.begin(false)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	// Since the user-provided function might panic, ensure the transaction releases all mutexes and resources.
+	defer rollbackOnPanic(tx)
+	tx.managed = true
+	err = fn(tx)
+	tx.managed = false
+	if err != nil {
+		Error(err)
+		// The error is ignored here because nothing was written yet and regardless of a rollback failure, the tx is
+		// closed now anyways.
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Rollback()
+}
+
+// Update invokes the passed function in the context of a managed read-write transaction with the root bucket for the
+// namespace.
+//
+// Any errors returned from the user-supplied function will cause the transaction to be rolled back and are returned
+// from this function.
+//
+// Otherwise, the transaction is committed when the user-supplied function returns a nil error. This function is part of
+// the database.DB interface implementation.
+func (db *db) Update(fn func(database.Tx) error) error {
+	// Start a read-write transaction.
+	tx, err := db.begin(true)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	// Since the user-provided function might panic, ensure the transaction releases all mutexes and resources.
+	defer rollbackOnPanic(tx)
+	tx.managed = true
+	err = fn(tx)
+	tx.managed = false
+	if err != nil {
+		Error(err)
+		// The error is ignored here because nothing was written yet and regardless of a rollback failure, the tx is
+		// closed now anyways.
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// PruneBlocks deletes the blocks stored first until the stored blocks take no more than targetSize bytes. The block with
+// the keep hash and all blocks stored after it are never deleted. The hashes of the deleted blocks are returned.
+//
+// Bolt does not shrink its file, so the space of the deleted blocks is reused for the blocks stored later rather than
+// returned to the file system.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) PruneBlocks(targetSize uint64, keep *chainhash.Hash) (pruned []chainhash.Hash, err error) {
+	err = db.Update(func(dbTx database.Tx) error {
+		tx := dbTx.(*transaction)
+		if _, err := tx.fetchBlock(keep); err != nil {
+			return err
+		}
+		total := tx.blocksSize()
+		var seqKeys [][]byte
+		cursor := tx.blockOrder.Cursor()
+		for k, v := cursor.First(); k != nil && total > targetSize; k, v = cursor.Next() {
+			if bytes.Equal(v, keep[:]) {
+				break
+			}
+			var hash chainhash.Hash
+			copy(hash[:], v)
+			total -= uint64(len(tx.blocks.Get(v)))
+			pruned = append(pruned, hash)
+			seqKeys = append(seqKeys, copySlice(k))
+		}
+		if len(pruned) == 0 {
+			return nil
+		}
+		for i := range pruned {
+			if err := tx.blocks.Delete(pruned[i][:]); err != nil {
+				str := fmt.Sprintf("failed to delete block %s", pruned[i])
+				return convertErr(str, err)
+			}
+			if err := tx.blockOrder.Delete(seqKeys[i]); err != nil {
+				str := fmt.Sprintf("failed to delete the order of block %s", pruned[i])
+				return convertErr(str, err)
+			}
+		}
+		if err := tx.infoBucket.Put(prunedKeyName, []byte{1}); err != nil {
+			return convertErr("failed to store pruned flag", err)
+		}
+		return tx.putBlocksSize(total)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// BeenPruned returns whether any blocks were ever deleted from the database by PruneBlocks.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) BeenPruned() (pruned bool, err error) {
+	err = db.View(func(dbTx database.Tx) error {
+		pruned = dbTx.(*transaction).infoBucket.Get(prunedKeyName) != nil
+		return nil
+	})
+	return
+}
+
+// Close cleanly shuts down the database and syncs all data.
+//
+// It will block until all database transactions have been finalized (rolled back or committed).
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) Close() error {
+	// Since all transactions have a read lock on this mutex, this will cause Close to wait for all readers to complete.
+	db.closeLock.Lock()
+	defer db.closeLock.Unlock()
+	if db.closed {
+		return makeDbErr(database.ErrDbNotOpen, errDbNotOpenStr, nil)
+	}
+	db.closed = true
+	if err := db.boltDB.Close(); err != nil {
+		return convertErr("failed to close database", err)
+	}
+	return nil
+}
+
+// fileExists reports whether the named file or directory exists.
+func fileExists(name string) bool {
+	if _, err := os.Stat(name); err != nil {
+		if os.IsNotExist(err) {
+			return false
+		}
+	}
+	return true
+}
+
+// initDB creates the top level buckets used by the package and records the block network of a new database, or
+// ensures an existing database is for the passed network.
+func initDB(boltDB *bolt.DB, network wire.BitcoinNet) error {
+	return boltDB.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{metadataBucketName, blocksBucketName, blockOrderBucketName, infoBucketName} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				str := fmt.Sprintf("failed to create bucket %q", name)
+				return convertErr(str, err)
+			}
+		}
+		info := tx.Bucket(infoBucketName)
+		row := info.Get(networkKeyName)
+		if row == nil {
+			var netRow [4]byte
+			byteOrder.PutUint32(netRow[:], uint32(network))
+			if err := info.Put(networkKeyName, netRow[:]); err != nil {
+				return convertErr("failed to store block network", err)
+			}
+			return nil
+		}
+		if len(row) != 4 || wire.BitcoinNet(byteOrder.Uint32(row)) != network {
+			str := fmt.Sprintf("database is for block network %x, not %v", row, network)
+			return makeDbErr(database.ErrInvalid, str, nil)
+		}
+		return nil
+	})
+}
+
+// openDB opens the database at the provided path
+//
+// ErrDbDoesNotExist is returned if the database doesn't exist and the create flag is not set, and ErrDbExists if it
+// does and the create flag is set.
+func openDB(dbPath string, network wire.BitcoinNet, create bool) (database.DB, error) {
+	dataPath := filepath.Join(dbPath, dataFileName)
+	dbExists := fileExists(dataPath)
+	if !create && !dbExists {
+		str := fmt.Sprintf("database %q does not exist", dataPath)
+		return nil, makeDbErr(database.ErrDbDoesNotExist, str, nil)
+	}
+	if create && dbExists {
+		str := fmt.Sprintf("database %q already exists", dataPath)
+		return nil, makeDbErr(database.ErrDbExists, str, nil)
+	}
+	// Ensure the full path to the database exists.
+	if !dbExists {
+		// The error can be ignored here since the call to bolt.Open will fail if the directory couldn't be created.
+		_ = os.MkdirAll(dbPath, 0700)
+	}
+	boltDB, err := bolt.Open(dataPath, 0600, &bolt.Options{
+		Timeout:         openTimeout,
+		InitialMmapSize: initialMmapSize,
+	})
+	if err != nil {
+		Error(err)
+		return nil, convertErr(err.Error(), err)
+	}
+	if err = initDB(boltDB, network); err != nil {
+		Error(err)
+		_ = boltDB.Close()
+		return nil, err
+	}
+	return &db{boltDB: boltDB}, nil
+}
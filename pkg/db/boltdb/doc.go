@@ -0,0 +1,25 @@
+/*
+Package boltdb implements a driver for the database package that uses a single bolt database file for both the metadata
+and the block storage.
+
+This driver is an alternative to ffldb for systems where leveldb and its many flat files are a poor fit. Blocks and
+metadata are updated in the same transaction, so a crash can not leave them out of step, at the cost of the writes of
+large blocks going through the bolt B+tree rather than being appended to flat files.
+
+# Usage
+
+This package is a driver to the database package and provides the database type of "boltdb". The parameters the Open
+and Create functions take are the database path as a string and the block network:
+
+	db, err := database.Open("boltdb", "path/to/database", wire.MainNet)
+	if err != nil {
+		// Handle error
+	}
+	db, err := database.Create("boltdb", "path/to/database", wire.MainNet)
+	if err != nil {
+		// Handle error
+	}
+
+An existing block database can be copied to this driver with the migratedb command of the node.
+*/
+package boltdb
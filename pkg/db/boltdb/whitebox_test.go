@@ -0,0 +1,194 @@
+package boltdb
+
+// This file is part of the boltdb package rather than the boltdb_test package as it provides whitebox testing.
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	bolt "github.com/coreos/bbolt"
+
+	"github.com/p9c/pod/pkg/chain/wire"
+	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// TestPruneBlocks ensures pruning deletes the blocks stored first down to the target size without deleting the block to
+// keep or any stored after it, that pruned blocks are reported as not found, and that the stored size and the pruned
+// flag survive reopening the database.
+func TestPruneBlocks(t *testing.T) {
+	t.Parallel()
+	dbPath := filepath.Join(os.TempDir(), "boltdb-prunetest")
+	_ = os.RemoveAll(dbPath)
+	idb, err := openDB(dbPath, wire.MainNet, true)
+	if err != nil {
+		t.Errorf("openDB: unexpected error: %v", err)
+		return
+	}
+	defer os.RemoveAll(dbPath)
+	blocks := make([]*util.Block, 8)
+	for i := range blocks {
+		msgTx := wire.NewMsgTx(1)
+		msgTx.AddTxOut(wire.NewTxOut(int64(i), make([]byte, 600)))
+		msgBlock := wire.MsgBlock{Header: wire.BlockHeader{Nonce: uint32(i)}}
+		if err = msgBlock.AddTransaction(msgTx); err != nil {
+			t.Fatalf("AddTransaction: unexpected error: %v", err)
+		}
+		blocks[i] = util.NewBlock(&msgBlock)
+	}
+	blockBytes, _ := blocks[0].Bytes()
+	blockSize := uint64(len(blockBytes))
+	err = idb.Update(func(tx database.Tx) error {
+		for i := range blocks {
+			if err := tx.StoreBlock(blocks[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("StoreBlock: unexpected error: %v", err)
+	}
+	// checkPruned ensures the first n blocks are gone, the rest are still there and the stored size counts them.
+	checkPruned := func(testName string, n int) {
+		err := idb.View(func(dbTx database.Tx) error {
+			for i := range blocks {
+				_, err := dbTx.FetchBlock(blocks[i].Hash())
+				switch {
+				case i < n && !checkDbError(t, testName, err, database.ErrBlockNotFound):
+				case i >= n && err != nil:
+					t.Errorf("%s: block %d: unexpected error: %v", testName, i, err)
+				}
+			}
+			if size := dbTx.(*transaction).blocksSize(); size != blockSize*uint64(len(blocks)-n) {
+				t.Errorf("%s: stored size %d, want %d", testName, size, blockSize*uint64(len(blocks)-n))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: View: unexpected error: %v", testName, err)
+		}
+	}
+	if pruned, _ := idb.BeenPruned(); pruned {
+		t.Errorf("BeenPruned: database was not pruned yet")
+	}
+	// Pruning to the size of four blocks deletes the first four.
+	pruned, err := idb.PruneBlocks(blockSize*4, blocks[7].Hash())
+	if err != nil {
+		t.Fatalf("PruneBlocks: unexpected error: %v", err)
+	}
+	if len(pruned) != 4 {
+		t.Errorf("PruneBlocks: pruned %d blocks, want 4", len(pruned))
+	}
+	checkPruned("PruneBlocks to size", 4)
+	// Pruning to nothing stops at the block to keep.
+	if pruned, err = idb.PruneBlocks(0, blocks[6].Hash()); err != nil {
+		t.Fatalf("PruneBlocks: unexpected error: %v", err)
+	}
+	if len(pruned) != 2 {
+		t.Errorf("PruneBlocks: pruned %d blocks, want 2", len(pruned))
+	}
+	checkPruned("PruneBlocks to kept block", 6)
+	if pruned, _ := idb.BeenPruned(); !pruned {
+		t.Errorf("BeenPruned: database was pruned")
+	}
+	// Pruning with a block to keep that is not stored fails.
+	_, err = idb.PruneBlocks(0, blocks[0].Hash())
+	checkDbError(t, "PruneBlocks missing block", err, database.ErrBlockNotFound)
+	idb.Close()
+	if idb, err = openDB(dbPath, wire.MainNet, false); err != nil {
+		t.Fatalf("openDB: unexpected error: %v", err)
+	}
+	defer idb.Close()
+	checkPruned("reopened", 6)
+	if pruned, _ := idb.BeenPruned(); !pruned {
+		t.Errorf("BeenPruned: reopened database was pruned")
+	}
+}
+
+// TestCursorDelete ensures deleting the entries a cursor is at while iterating in either direction visits every entry
+// once.
+func TestCursorDelete(t *testing.T) {
+	t.Parallel()
+	dbPath := filepath.Join(os.TempDir(), "boltdb-cursordeletetest")
+	_ = os.RemoveAll(dbPath)
+	idb, err := openDB(dbPath, wire.MainNet, true)
+	if err != nil {
+		t.Errorf("openDB: unexpected error: %v", err)
+		return
+	}
+	defer os.RemoveAll(dbPath)
+	defer idb.Close()
+	keys := []string{"a", "b", "c", "d", "e"}
+	err = idb.Update(func(tx database.Tx) error {
+		for _, forward := range []bool{true, false} {
+			for _, k := range keys {
+				if err := tx.Metadata().Put([]byte(k), []byte(k)); err != nil {
+					return err
+				}
+			}
+			var visited []string
+			cursor := tx.Metadata().Cursor()
+			move, ok := cursor.Next, cursor.First()
+			if !forward {
+				move, ok = cursor.Prev, cursor.Last()
+			}
+			for ; ok; ok = move() {
+				visited = append(visited, string(cursor.Key()))
+				if err := cursor.Delete(); err != nil {
+					return err
+				}
+			}
+			if len(visited) != len(keys) {
+				t.Errorf("forward %v: visited %v, want %v", forward, visited, keys)
+			}
+			if cursor.First() {
+				t.Errorf("forward %v: key %q was not deleted", forward, cursor.Key())
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Errorf("Update: unexpected error: %v", err)
+	}
+}
+
+// TestConvertErr ensures the bolt error to database error conversion works as expected.
+func TestConvertErr(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err         error
+		wantErrCode database.ErrorCode
+	}{
+		{bolt.ErrDatabaseNotOpen, database.ErrDbNotOpen},
+		{bolt.ErrTimeout, database.ErrDbAlreadyOpen},
+		{bolt.ErrChecksum, database.ErrCorruption},
+		{bolt.ErrTxClosed, database.ErrTxClosed},
+		{bolt.ErrBucketExists, database.ErrBucketExists},
+		{bolt.ErrIncompatibleValue, database.ErrIncompatibleValue},
+	}
+	for i, test := range tests {
+		gotErr := convertErr("test", test.err)
+		if gotErr.ErrorCode != test.wantErrCode {
+			t.Errorf("convertErr #%d unexpected error - got %v, want %v", i, gotErr.ErrorCode, test.wantErrCode)
+			continue
+		}
+	}
+}
+
+// checkDbError ensures the passed error is a database.DBError with an error code that matches the passed  error code.
+func checkDbError(t *testing.T, testName string, gotErr error, wantErrCode database.ErrorCode) bool {
+	dbErr, ok := gotErr.(database.DBError)
+	if !ok {
+		t.Errorf("%s: unexpected error type - got %T, want %T",
+			testName, gotErr, database.DBError{})
+		return false
+	}
+	if dbErr.ErrorCode != wantErrCode {
+		t.Errorf("%s: unexpected error code - got %s (%s), want %s",
+			testName, dbErr.ErrorCode, dbErr.Description,
+			wantErrCode)
+		return false
+	}
+	return true
+}
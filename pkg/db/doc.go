@@ -7,8 +7,9 @@ turns out to be over 60GB of data). This package provides a database layer to st
 and efficient manner.
 
 The default backend, ffldb, has a strong focus on speed, efficiency, and robustness. It makes use leveldb for the
-metadata, flat files for block storage, and strict checksums in key areas to ensure data integrity. A quick overview of
-the features database provides are as follows:
+metadata, flat files for block storage, and strict checksums in key areas to ensure data integrity. The boltdb backend
+keeps the metadata and blocks together in a single bolt database file instead. A quick overview of the features database
+provides are as follows:
 
  - Key/value metadata store
 
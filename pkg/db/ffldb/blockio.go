@@ -8,6 +8,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
@@ -133,6 +135,14 @@ type (
 		openBlockFiles   map[uint32]*lockableFile
 		// writeCursor houses the state for the current file and location that new blocks are written to.
 		writeCursor *writeCursor
+		// firstFileNum is the number of the oldest block file, which is not zero once old files have been pruned. It is
+		// only changed while pruning, which happens under the database write lock.
+		firstFileNum uint32
+		// finishedSizes holds the size of each block file from the oldest up to but not including the current write
+		// file, and finishedTotal their sum. They are read from the file system on the first prune and kept up to date
+		// as files are finished, rolled back and pruned after that, all of which happens under the database write lock.
+		finishedSizes map[uint32]uint64
+		finishedTotal uint64
 		// These functions are set to openFile, openWriteFile, and deleteFile by default, but are exposed here to allow
 		// the whitebox tests to replace them when working with mock files.
 		openFileFunc      func(fileNum uint32) (*lockableFile, error)
@@ -258,6 +268,83 @@ func (s *blockStore) deleteFile(fileNum uint32) error {
 	return nil
 }
 
+// closeFile closes the passed flat file number if it is open for reading so that it can be deleted.
+func (s *blockStore) closeFile(fileNum uint32) {
+	s.obfMutex.Lock()
+	defer s.obfMutex.Unlock()
+	blockFile, ok := s.openBlockFiles[fileNum]
+	if !ok {
+		return
+	}
+	s.lruMutex.Lock()
+	s.openBlocksLRU.Remove(s.fileNumToLRUElem[fileNum])
+	delete(s.fileNumToLRUElem, fileNum)
+	s.lruMutex.Unlock()
+	// Close the file under the write lock for the file in case any readers are currently reading from it.
+	blockFile.Lock()
+	_ = blockFile.file.Close()
+	blockFile.Unlock()
+	delete(s.openBlockFiles, fileNum)
+}
+
+// storedSize returns the total size of the block files from the oldest up to and including the current write file. The
+// size of the current write file is that of the data written to it so far.
+//
+// This function MUST be called during a write transaction so the write cursor does not move.
+func (s *blockStore) storedSize() (total uint64, err error) {
+	wc := s.writeCursor
+	wc.RLock()
+	curFileNum, curOffset := wc.curFileNum, wc.curOffset
+	wc.RUnlock()
+	if s.finishedSizes == nil {
+		sizes := make(map[uint32]uint64)
+		var finished uint64
+		for fileNum := s.firstFileNum; fileNum < curFileNum; fileNum++ {
+			var st os.FileInfo
+			if st, err = os.Stat(blockFilePath(s.basePath, fileNum)); err != nil {
+				return 0, makeDbErr(database.ErrDriverSpecific, err.Error(), err)
+			}
+			sizes[fileNum] = uint64(st.Size())
+			finished += uint64(st.Size())
+		}
+		s.finishedSizes, s.finishedTotal = sizes, finished
+	}
+	return s.finishedTotal + uint64(curOffset), nil
+}
+
+// finishFile records the size of a block file that is no longer written to. Nothing is recorded before the sizes were
+// first read by storedSize, which then reads the file from the file system.
+func (s *blockStore) finishFile(fileNum uint32, size uint64) {
+	if s.finishedSizes == nil {
+		return
+	}
+	s.finishedSizes[fileNum] = size
+	s.finishedTotal += size
+}
+
+// forgetFile removes the recorded size of a block file that was deleted or is written to again.
+func (s *blockStore) forgetFile(fileNum uint32) {
+	if size, ok := s.finishedSizes[fileNum]; ok {
+		s.finishedTotal -= size
+		delete(s.finishedSizes, fileNum)
+	}
+}
+
+// pruneFiles closes and deletes the block files from the oldest up to but not including the passed file number.
+func (s *blockStore) pruneFiles(beforeFileNum uint32) error {
+	for ; s.firstFileNum < beforeFileNum; s.firstFileNum++ {
+		s.closeFile(s.firstFileNum)
+		if err := s.deleteFileFunc(s.firstFileNum); err != nil {
+			// A file that is already gone was deleted by an earlier prune that was interrupted.
+			if dbErr, ok := err.(database.DBError); !ok || !os.IsNotExist(dbErr.Err) {
+				return err
+			}
+		}
+		s.forgetFile(s.firstFileNum)
+	}
+	return nil
+}
+
 // blockFile attempts to return an existing file handle for the passed flat file number if it is already open as well as
 // marking it as most recently used. It will also open the file when it's not already open subject to the rules
 // described in openFile.
@@ -362,6 +449,7 @@ func (s *blockStore) writeBlock(rawBlock []byte) (blockLocation, error) {
 		}
 		wc.curFile.Unlock()
 		// Start writes into next file.
+		s.finishFile(wc.curFileNum, uint64(wc.curOffset))
 		wc.curFileNum++
 		wc.curOffset = 0
 		wc.Unlock()
@@ -551,6 +639,9 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 	}
 	// Regardless of any failures that happen below, reposition the write cursor to the old block file and offset.
 	defer func() {
+		for fileNum := oldBlockFileNum; fileNum <= wc.curFileNum; fileNum++ {
+			s.forgetFile(fileNum)
+		}
 		wc.curFileNum = oldBlockFileNum
 		wc.curOffset = oldBlockOffset
 	}()
@@ -618,7 +709,7 @@ func (s *blockStore) handleRollback(oldBlockFileNum, oldBlockOffset uint32) {
 func scanBlockFiles(dbPath string) (int, uint32) {
 	lastFile := -1
 	fileLen := uint32(0)
-	for i := 0; ; i++ {
+	for i := int(firstBlockFile(dbPath)); ; i++ {
 		filePath := blockFilePath(dbPath, uint32(i))
 		st, err := os.Stat(filePath)
 		if err != nil {
@@ -632,6 +723,26 @@ func scanBlockFiles(dbPath string) (int, uint32) {
 	return lastFile, fileLen
 }
 
+// firstBlockFile returns the number of the oldest flat block file in the database directory, which is not zero once
+// old files have been pruned.
+func firstBlockFile(dbPath string) (first uint32) {
+	paths, err := filepath.Glob(filepath.Join(dbPath, "*.fdb"))
+	if err != nil {
+		return
+	}
+	found := false
+	for _, path := range paths {
+		n, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(path), ".fdb"), 10, 32)
+		if err != nil {
+			continue
+		}
+		if !found || uint32(n) < first {
+			first, found = uint32(n), true
+		}
+	}
+	return
+}
+
 // newBlockStore returns a new block store with the current block file number and offset set and all fields initialized.
 func newBlockStore(basePath string, network wire.BitcoinNet) *blockStore {
 	// Look for the end of the latest block to file to determine what the write cursor position is from the viewpoint of
@@ -645,6 +756,7 @@ func newBlockStore(basePath string, network wire.BitcoinNet) *blockStore {
 		network:          network,
 		basePath:         basePath,
 		maxBlockFileSize: maxBlockFileSize,
+		firstFileNum:     firstBlockFile(basePath),
 		openBlockFiles:   make(map[uint32]*lockableFile),
 		openBlocksLRU:    list.New(),
 		fileNumToLRUElem: make(map[uint32]*list.Element),
@@ -59,6 +59,9 @@ var (
 	blockIdxBucketName = []byte("ffldb-blockidx")
 	// writeLocKeyName is the key used to store the current write file location.
 	writeLocKeyName = []byte("ffldb-writeloc")
+	// prunedKeyName is the key used to record that blocks have been deleted by pruning. It holds the number of the
+	// oldest block file that was kept, as a little-endian uint32.
+	prunedKeyName = []byte("ffldb-pruned")
 )
 
 // Common error strings.
@@ -1761,6 +1764,82 @@ func (db *db) Update(fn func(database.Tx) error) error {
 	return tx.Commit()
 }
 
+// PruneBlocks deletes the oldest block files until the block files take no more than targetSize bytes. The file holding
+// the block with the keep hash and all later files are never deleted, so the current write file is always kept. The
+// block index entries of the blocks in the deleted files are removed so they are reported as not found, and their
+// hashes are returned.
+//
+// The block index is updated before the files are deleted, so an interruption can only leave behind files that are no
+// longer referenced, which the next prune deletes first.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) PruneBlocks(targetSize uint64, keep *chainhash.Hash) (pruned []chainhash.Hash, err error) {
+	var beforeFileNum uint32
+	err = db.Update(func(dbTx database.Tx) error {
+		tx := dbTx.(*transaction)
+		keepRow, err := tx.fetchBlockRow(keep)
+		if err != nil {
+			return err
+		}
+		keepFileNum := deserializeBlockLoc(keepRow).blockFileNum
+		total, err := db.store.storedSize()
+		if err != nil {
+			return err
+		}
+		var prunedBefore uint32
+		if row := tx.metaBucket.Get(prunedKeyName); len(row) == 4 {
+			prunedBefore = byteOrder.Uint32(row)
+		}
+		for beforeFileNum = db.store.firstFileNum; beforeFileNum < keepFileNum &&
+			(total > targetSize || beforeFileNum < prunedBefore); beforeFileNum++ {
+			total -= db.store.finishedSizes[beforeFileNum]
+		}
+		if beforeFileNum == db.store.firstFileNum {
+			return nil
+		}
+		err = tx.blockIdxBucket.ForEach(func(k, v []byte) error {
+			if deserializeBlockLoc(v).blockFileNum < beforeFileNum {
+				var hash chainhash.Hash
+				copy(hash[:], k)
+				pruned = append(pruned, hash)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for i := range pruned {
+			if err = tx.blockIdxBucket.Delete(pruned[i][:]); err != nil {
+				return err
+			}
+		}
+		var row [4]byte
+		byteOrder.PutUint32(row[:], beforeFileNum)
+		return tx.metaBucket.Put(prunedKeyName, row[:])
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The files are deleted under the write lock so no block can be written while they are.
+	db.writeLock.Lock()
+	defer db.writeLock.Unlock()
+	if err = db.store.pruneFiles(beforeFileNum); err != nil {
+		return nil, err
+	}
+	return pruned, nil
+}
+
+// BeenPruned returns whether any blocks were ever deleted from the database by PruneBlocks.
+//
+// This function is part of the database.DB interface implementation.
+func (db *db) BeenPruned() (pruned bool, err error) {
+	err = db.View(func(tx database.Tx) error {
+		pruned = tx.Metadata().Get(prunedKeyName) != nil
+		return nil
+	})
+	return
+}
+
 // Close cleanly shuts down the database and syncs all data.
 //
 // It will block until all database transactions have been finalized ( rolled back or committed). This function is part
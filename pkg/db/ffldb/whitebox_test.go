@@ -117,6 +117,118 @@ type testContext struct {
 	blocks       []*util.Block
 }
 
+// TestPruneBlocks ensures pruning deletes the oldest block files down to the target size without deleting the file of
+// the block to keep or any later one, that pruned blocks are reported as not found, that the size of the block files
+// stays tracked as blocks are stored and pruned, and that the database opens again with its oldest block files gone.
+func TestPruneBlocks(t *testing.T) {
+	t.Parallel()
+	dbPath := filepath.Join(os.TempDir(), "ffldb-prunetest")
+	_ = os.RemoveAll(dbPath)
+	idb, err := openDB(dbPath, blockDataNet, true)
+	if err != nil {
+		t.Errorf("openDB: unexpected error: %v", err)
+		return
+	}
+	defer os.RemoveAll(dbPath)
+	// Make the block files small enough that each of the test blocks goes in a file of its own.
+	idb.(*db).store.maxBlockFileSize = 1024
+	blocks := make([]*util.Block, 10)
+	for i := range blocks {
+		msgTx := wire.NewMsgTx(1)
+		msgTx.AddTxOut(wire.NewTxOut(int64(i), make([]byte, 600)))
+		msgBlock := wire.MsgBlock{Header: wire.BlockHeader{Nonce: uint32(i)}}
+		if err = msgBlock.AddTransaction(msgTx); err != nil {
+			t.Fatalf("AddTransaction: unexpected error: %v", err)
+		}
+		blocks[i] = util.NewBlock(&msgBlock)
+	}
+	blockBytes, _ := blocks[0].Bytes()
+	// Each block is stored with the network, length and checksum.
+	fileSize := uint64(len(blockBytes) + 12)
+	// stored is the number of the test blocks stored so far.
+	var stored int
+	storeBlocks := func(blocks []*util.Block) {
+		err := idb.Update(func(tx database.Tx) error {
+			for i := range blocks {
+				if err := tx.StoreBlock(blocks[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("StoreBlock: unexpected error: %v", err)
+		}
+		stored += len(blocks)
+	}
+	// checkStoredSize ensures the tracked size of the block files is that of n blocks.
+	checkStoredSize := func(testName string, n int) {
+		size, err := idb.(*db).store.storedSize()
+		if err != nil {
+			t.Fatalf("%s: storedSize: unexpected error: %v", testName, err)
+		}
+		if size != fileSize*uint64(n) {
+			t.Errorf("%s: stored size is %d, want %d", testName, size, fileSize*uint64(n))
+		}
+	}
+	storeBlocks(blocks[:8])
+	// checkPruned ensures the first n blocks and their files are gone and the rest of the stored blocks are still there.
+	checkPruned := func(testName string, n int) {
+		err := idb.View(func(tx database.Tx) error {
+			for i := range blocks[:stored] {
+				_, err := tx.FetchBlock(blocks[i].Hash())
+				_, statErr := os.Stat(blockFilePath(dbPath, uint32(i)))
+				switch {
+				case i < n && !checkDbError(t, testName, err, database.ErrBlockNotFound):
+				case i < n && !os.IsNotExist(statErr):
+					t.Errorf("%s: file %d was not deleted", testName, i)
+				case i >= n && err != nil:
+					t.Errorf("%s: block %d: unexpected error: %v", testName, i, err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("%s: View: unexpected error: %v", testName, err)
+		}
+	}
+	if pruned, _ := idb.BeenPruned(); pruned {
+		t.Errorf("BeenPruned: database was not pruned yet")
+	}
+	// Pruning to the size of four files deletes the first four.
+	pruned, err := idb.PruneBlocks(fileSize*4, blocks[7].Hash())
+	if err != nil {
+		t.Fatalf("PruneBlocks: unexpected error: %v", err)
+	}
+	if len(pruned) != 4 {
+		t.Errorf("PruneBlocks: pruned %d blocks, want 4", len(pruned))
+	}
+	checkPruned("PruneBlocks to size", 4)
+	checkStoredSize("PruneBlocks to size", 4)
+	// The blocks stored after a prune add to the tracked size.
+	storeBlocks(blocks[8:])
+	checkStoredSize("stored after prune", 6)
+	// Pruning to nothing stops at the file of the block to keep.
+	if pruned, err = idb.PruneBlocks(0, blocks[6].Hash()); err != nil {
+		t.Fatalf("PruneBlocks: unexpected error: %v", err)
+	}
+	if len(pruned) != 2 {
+		t.Errorf("PruneBlocks: pruned %d blocks, want 2", len(pruned))
+	}
+	checkPruned("PruneBlocks to kept block", 6)
+	checkStoredSize("PruneBlocks to kept block", 4)
+	if pruned, _ := idb.BeenPruned(); !pruned {
+		t.Errorf("BeenPruned: database was pruned")
+	}
+	// The database must open again with the oldest block files missing.
+	idb.Close()
+	if idb, err = openDB(dbPath, blockDataNet, false); err != nil {
+		t.Fatalf("openDB: unexpected error: %v", err)
+	}
+	defer idb.Close()
+	checkPruned("reopened", 6)
+}
+
 // TestConvertErr ensures the leveldb error to database error conversion works as expected.
 func TestConvertErr(t *testing.T) {
 	t.Parallel()
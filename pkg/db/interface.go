@@ -387,6 +387,12 @@ type DB interface {
 	//
 	// Calling Rollback or Commit on the transaction passed to the user-supplied function will result in a panic.
 	Update(fn func(tx Tx) error) error
+	// PruneBlocks deletes stored blocks, oldest first, until the blocks stored take no more than targetSize bytes. The
+	// block with the keep hash and all blocks stored after it are never deleted, nor is any metadata. Deleted blocks are
+	// reported as not found by the fetch functions. The hashes of the deleted blocks are returned.
+	PruneBlocks(targetSize uint64, keep *chainhash.Hash) ([]chainhash.Hash, error)
+	// BeenPruned returns whether any blocks were ever deleted from the database by PruneBlocks.
+	BeenPruned() (bool, error)
 	// Close cleanly shuts down the database and syncs all data. It will block until all database transactions have been
 	// finalized (rolled back or committed).
 	Close() error
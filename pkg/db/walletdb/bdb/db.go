@@ -300,6 +300,11 @@ func (db *db) Copy(w io.Writer) error {
 	}))
 }
 
+// Path returns the path of the database file.
+func (db *db) Path() string {
+	return (*bolt.DB)(db).Path()
+}
+
 // Close cleanly shuts down the database and syncs all data.
 //
 // This function is part of the walletdb.Db interface implementation.
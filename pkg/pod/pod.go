@@ -127,6 +127,9 @@ type Config struct {
 	AddCheckpoints         *cli.StringSlice `group:"debug" label:"AddCheckpoints" description:"add custom checkpoints" type:"" widget:"multi" json:"AddCheckpoints" hook:"restart"`
 	AddPeers               *cli.StringSlice `group:"node" label:"Add Peers" description:"manually adds addresses to try to connect to" type:"address" widget:"multi" json:"AddPeers" hook:"addpeer"`
 	AddrIndex              *bool            `group:"node" label:"Addr Index" description:"maintain a full address-based transaction index which makes the searchrawtransactions RPC available" type:"" widget:"toggle"  json:"AddrIndex" hook:"dropaddrindex"`
+	AddrUtxoIndex          *bool            `group:"node" label:"Addr Utxo Index" description:"maintain an index of address balances and unspent outputs which makes the getaddress* RPCs available" type:"" widget:"toggle" json:"AddrUtxoIndex" hook:"dropaddrutxoindex"`
+	AlgoStatsIndex         *bool            `group:"node" label:"Algo Stats Index" description:"maintain an index of the proof of work of each block which makes the getalgostats RPC available" type:"" widget:"toggle" json:"AlgoStatsIndex" hook:"dropalgostatsindex"`
+	AssumeValid            *string          `group:"debug" label:"Assume Valid" description:"skip the script checks of the ancestors of this block, format '<height>:<hash>' (empty = network default, 0 = check all scripts)" type:"" widget:"string" json:"AssumeValid" hook:"restart"`
 	AutoPorts              *bool            `group:"" label:"AutomaticPorts" description:"RPC and controller ports are randomized, use with controller for automatic peer discovery" type:"" widget:"toggle" json:"AutoPorts" hook:"restart"`
 	BanDuration            *time.Duration   `group:"debug" label:"Ban Duration" description:"how long a ban of a misbehaving peer lasts" type:"" widget:"time" json:"BanDuration" hook:"restart"`
 	BanThreshold           *int             `group:"debug" label:"Ban Threshold" description:"ban score that triggers a ban (default 100)" type:"" widget:"integer" json:"BanThreshold" hook:"restart"`
@@ -140,9 +143,10 @@ type Config struct {
 	ConfigFile             *string          `group:"" label:"Configuration File" description:"location of configuration file, cannot actually be changed" type:"path" widget:"string" json:"ConfigFile" hook:"restart"`
 	ConnectPeers           *cli.StringSlice `group:"node" label:"Connect Peers" description:"connect ONLY to these addresses (disables inbound connections)" type:"address" widget:"multi" json:"ConnectPeers" hook:"restart"`
 	Controller             *string          `group:"node" label:"Controller Listener" description:"address to bind miner controller to" type:"address" widget:"string" json:"Controller" hook:"controller"`
+	ControllerConnect      *string          `group:"mining" label:"Controller Connect" description:"address of a mining controller kopach registers with to be sent work by unicast instead of listening for multicast (empty = multicast)" type:"address" widget:"string" json:"ControllerConnect" hook:"restart"`
 	CPUProfile             *string          `group:"debug" label:"CPU Profile" description:"write cpu profile to this file" type:"path" widget:"string" json:"CPUProfile" hook:"restart"`
 	DataDir                *string          `group:"" label:"Data Directory" description:"root folder where application data is stored" type:"path" widget:"string" json:"DataDir" hook:"restart"`
-	DbType                 *string          `group:"" label:"Database Type" description:"type of database storage engine to use (only one right now)" type:"" widget:"string" json:"DbType" hook:"restart"`
+	DbType                 *string          `group:"" label:"Database Type" description:"type of database storage engine to use (ffldb or boltdb)" type:"" widget:"string" json:"DbType" hook:"restart"`
 	DisableBanning         *bool            `group:"debug" label:"Disable Banning" description:"disables banning of misbehaving peers" type:"" widget:"toggle" json:"DisableBanning" hook:"restart"`
 	DisableCheckpoints     *bool            `group:"debug" label:"Disable Checkpoints" description:"disables all checkpoints" type:"" widget:"toggle" json:"DisableCheckpoints" hook:"restart"`
 	DisableDNSSeed         *bool            `group:"node" label:"Disable DNS Seed" description:"disable seeding of addresses to peers" type:"" widget:"toggle" json:"DisableDNSSeed" hook:"restart"`
@@ -158,8 +162,10 @@ type Config struct {
 	Listeners              *cli.StringSlice `group:"node" label:"Listeners" description:"list of addresses to bind the node listener to" type:"address" widget:"multi" json:"Listeners" hook:"restart"`
 	LogDir                 *string          `group:"config" label:"Log Dir" description:"folder where log files are written" type:"path" widget:"string" json:"LogDir" hook:"restart"`
 	LogLevel               *string          `group:"config" label:"Log Level" description:"maximum log level to output\n(fatal error check warning info debug trace - what is selected includes all items to the left of the one in that list)" type:"" widget:"radio" json:"LogLevel" hook:"loglevel"`
+	MaxMempool             *int             `group:"policy" label:"Max Mempool" description:"maximum size of the transaction memory pool in megabytes (0 = unbounded)" type:"" widget:"integer" json:"MaxMempool" hook:"restart"`
 	MaxOrphanTxs           *int             `group:"policy" label:"Max Orphan Txs" description:"max number of orphan transactions to keep in memory" type:"" widget:"integer" json:"MaxOrphanTxs" hook:"restart"`
 	MaxPeers               *int             `group:"node" label:"Max Peers" description:"maximum number of peers to hold connections with" type:"" widget:"integer" json:"MaxPeers" hook:"restart"`
+	MinerListener          *string          `group:"mining" label:"Miner Listener" description:"address the mining controller accepts kopach registrations on to send them work by unicast, for miners outside the local network (empty = multicast only)" type:"address" widget:"string" json:"MinerListener" hook:"restart"`
 	MinerPass              *string          `group:"mining" label:"Miner Pass" description:"password that encrypts the connection to the mining controller" type:"" widget:"password" json:"MinerPass" hook:"restart"`
 	MiningAddrs            *cli.StringSlice `group:"" label:"Mining Addrs" description:"addresses to pay block rewards to (TODO, make this auto)" type:"base58" widget:"multi" json:"MiningAddrs" hook:"miningaddr"`
 	MinRelayTxFee          *float64         `group:"policy" label:"Min Relay Tx Fee" description:"the minimum transaction fee in DUO/kB to be considered a non-zero fee" type:"" widget:"float" json:"MinRelayTxFee" hook:"restart"`
@@ -180,7 +186,9 @@ type Config struct {
 	Proxy                  *string          `group:"proxy" label:"Proxy" description:"address of proxy to connect to for outbound connections" type:"url" widget:"string" json:"Proxy" hook:"restart"`
 	ProxyPass              *string          `group:"proxy" label:"Proxy Pass" description:"proxy password, if required" type:"" widget:"password" json:"ProxyPass" hook:"restart"`
 	ProxyUser              *string          `group:"proxy" label:"ProxyUser" description:"proxy username, if required" type:"" widget:"string" json:"ProxyUser" hook:"restart"`
+	Prune                  *int             `group:"node" label:"Prune" description:"delete the oldest block files to keep them below this many megabytes, requires the transaction and address indexes to be disabled (0 = disabled, minimum 550)" type:"" widget:"integer" json:"Prune" hook:"restart"`
 	RejectNonStd           *bool            `group:"node" label:"Reject Non Std" description:"reject non-standard transactions regardless of the default settings for the active network" type:"" widget:"toggle" json:"RejectNonStd" hook:"restart"`
+	RejectReplacement      *bool            `group:"policy" label:"Reject Replacement" description:"reject transactions that replace transactions in the mempool even if they signal replaceability (BIP125)" type:"" widget:"toggle" json:"RejectReplacement" hook:"restart"`
 	RelayNonStd            *bool            `group:"node" label:"Relay Non Std" description:"relay non-standard transactions regardless of the default settings for the active network" type:"" widget:"toggle" json:"RelayNonStd" hook:"restart"`
 	RPCCert                *string          `group:"rpc" label:"RPC Cert" description:"location of RPC TLS certificate" type:"path" widget:"string" json:"RPCCert" hook:"restart"`
 	RPCConnect             *string          `group:"wallet" label:"RPC Connect" description:"full node RPC for wallet" type:"address" widget:"string" json:"RPCConnect" hook:"restart"`
@@ -194,6 +202,8 @@ type Config struct {
 	ServerTLS              *bool            `group:"wallet" label:"Server TLS" description:"enable TLS for the wallet connection to node RPC server" type:"" widget:"toggle" json:"ServerTLS" hook:"restart"`
 	ServerUser             *string          `group:"rpc" label:"Server User" description:"username for chain server connections" type:"" widget:"string" json:"ServerUser" hook:"restart"`
 	SigCacheMaxSize        *int             `group:"node" label:"Sig Cache Max Size" description:"the maximum number of entries in the signature verification cache" type:"" widget:"integer" json:"SigCacheMaxSize" hook:"restart"`
+	Stratum                *string          `group:"mining" label:"Stratum Listener" description:"address of the Stratum mining server, each algorithm is served on consecutive ports from this one (empty = disabled)" type:"address" widget:"string" json:"Stratum" hook:"restart"`
+	StratumPass            *string          `group:"mining" label:"Stratum Pass" description:"password Stratum miners must authorize with (empty = any)" type:"" widget:"password" json:"StratumPass" hook:"restart"`
 	Solo                   *bool            `group:"mining" label:"Solo Generate" description:"mine even if not connected to a network" type:"" widget:"toggle" json:"Solo" hook:"restart"`
 	TLS                    *bool            `group:"tls" label:"TLS" description:"enable TLS for RPC connections" type:"" widget:"toggle" json:"TLS" hook:"restart"`
 	TLSSkipVerify          *bool            `group:"tls" label:"TLS Skip Verify" description:"skip TLS certificate verification (ignore CA errors)" type:"" widget:"toggle" json:"TLSSkipVerify" hook:"restart"`
@@ -204,13 +214,16 @@ type Config struct {
 	UserAgentComments      *cli.StringSlice `group:"" label:"User Agent Comments" description:"comment to add to the user agent -- See BIP 14 for more information" type:"" widget:"multi" json:"UserAgentComments" hook:"restart"`
 	Username               *string          `group:"rpc" label:"Username" description:"password for client RPC connections" type:"" widget:"string" json:"Username" hook:"restart"`
 	Wallet                 *bool            `group:"debug" label:"Connect to Wallet" description:"set ctl to connect to wallet instead of chain server" type:"" widget:"toggle" json:"Wallet"`
+	WalletCoinSelection    *string          `group:"wallet" label:"Wallet Coin Selection" description:"strategy choosing the outputs a transaction spends: bnb, knapsack, privacy, largest or valueage" type:"" widget:"string" json:"WalletCoinSelection" hook:""`
 	WalletFile             *string          `group:"config" label:"Wallet File" description:"wallet database file" type:"path" widget:"string" featured:"true" json:"WalletFile" hook:"restart"`
 	WalletOff              *bool            `group:"debug" label:"Wallet Off" description:"turn off the wallet backend" type:"" widget:"toggle" json:"WalletOff" hook:"wallet"`
 	WalletPass             *string          `group:"" label:"Wallet Pass" description:"password encrypting public data in wallet - hash is stored so give on command line" type:"" widget:"password" json:"WalletPass" hook:"restart"`
+	WalletRBF              *bool            `group:"wallet" label:"Wallet RBF" description:"signal that the transactions the wallet sends may be replaced with a higher fee (BIP125)" type:"" widget:"toggle" json:"WalletRBF" hook:""`
 	WalletRPCListeners     *cli.StringSlice `group:"wallet" label:"Legacy RPC Listeners" description:"addresses for wallet RPC server to listen on" type:"address" widget:"multi" json:"WalletRPCListeners" hook:"restart"`
 	WalletRPCMaxClients    *int             `group:"wallet" label:"Legacy RPC Max Clients" description:"maximum number of RPC clients allowed for wallet RPC" type:"" widget:"integer" json:"WalletRPCMaxClients" hook:"restart"`
 	WalletRPCMaxWebsockets *int             `group:"wallet" label:"Legacy RPC Max Websockets" description:"maximum number of websocket clients allowed for wallet RPC" type:"" widget:"integer" json:"WalletRPCMaxWebsockets" hook:"restart"`
 	WalletServer           *string          `group:"wallet" label:"Wallet Server" description:"node address to connect wallet server to" type:"address" widget:"string" json:"WalletServer" hook:"restart"`
+	WalletWatchOnly        *string          `group:"wallet" label:"Wallet Watch Only" description:"extended public key (xpub, ypub or zpub) of the account to create a watching-only wallet from" type:"" widget:"string" json:"WalletWatchOnly" hook:"restart"`
 	Whitelists             *cli.StringSlice `group:"debug" label:"Whitelists" description:"peers that you don't want to ever ban" type:"address" widget:"multi" json:"Whitelists" hook:"restart"`
 	LAN                    *bool            `group:"debug" label:"LAN" description:"run without any connection to nodes on the internet (does not apply on mainnet)" type:"" widget:"toggle" json:"LAN" hook:"restart"`
 	KopachGUI              *bool            `group:"" label:"Kopach GUI" description:"enables GUI for miner" type:"" widget:"toggle" json:"KopachGUI" hook:"restart"`
@@ -226,6 +239,9 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		AddCheckpoints:         newStringSlice(),
 		AddPeers:               newStringSlice(),
 		AddrIndex:              newbool(),
+		AddrUtxoIndex:          newbool(),
+		AlgoStatsIndex:         newbool(),
+		AssumeValid:            newstring(),
 		AutoPorts:              newbool(),
 		BanDuration:            newDuration(),
 		BanThreshold:           newint(),
@@ -240,6 +256,7 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		ConfigFile:             newstring(),
 		ConnectPeers:           newStringSlice(),
 		Controller:             newstring(),
+		ControllerConnect:      newstring(),
 		CPUProfile:             newstring(),
 		DarkTheme:              newbool(),
 		DataDir:                &datadir,
@@ -262,8 +279,10 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		Listeners:              newStringSlice(),
 		LogDir:                 newstring(),
 		LogLevel:               newstring(),
+		MaxMempool:             newint(),
 		MaxOrphanTxs:           newint(),
 		MaxPeers:               newint(),
+		MinerListener:          newstring(),
 		MinerPass:              newstring(),
 		MiningAddrs:            newStringSlice(),
 		MinRelayTxFee:          newfloat64(),
@@ -284,7 +303,9 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		Proxy:                  newstring(),
 		ProxyPass:              newstring(),
 		ProxyUser:              newstring(),
+		Prune:                  newint(),
 		RejectNonStd:           newbool(),
+		RejectReplacement:      newbool(),
 		RelayNonStd:            newbool(),
 		RPCCert:                newstring(),
 		RPCConnect:             newstring(),
@@ -300,6 +321,8 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		ServerUser:             newstring(),
 		SigCacheMaxSize:        newint(),
 		Solo:                   newbool(),
+		Stratum:                newstring(),
+		StratumPass:            newstring(),
 		TLS:                    newbool(),
 		TLSSkipVerify:          newbool(),
 		TorIsolation:           newbool(),
@@ -309,19 +332,25 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		UserAgentComments:      newStringSlice(),
 		Username:               newstring(),
 		Wallet:                 newbool(),
+		WalletCoinSelection:    newstring(),
 		WalletFile:             newstring(),
 		WalletOff:              newbool(),
 		WalletPass:             newstring(),
+		WalletRBF:              newbool(),
 		WalletRPCListeners:     newStringSlice(),
 		WalletRPCMaxClients:    newint(),
 		WalletRPCMaxWebsockets: newint(),
 		WalletServer:           newstring(),
+		WalletWatchOnly:        newstring(),
 		Whitelists:             newStringSlice(),
 	}
 	conf = map[string]interface{}{
 		"AddCheckpoints":         c.AddCheckpoints,
 		"AddPeers":               c.AddPeers,
 		"AddrIndex":              c.AddrIndex,
+		"AddrUtxoIndex":          c.AddrUtxoIndex,
+		"AlgoStatsIndex":         c.AlgoStatsIndex,
+		"AssumeValid":            c.AssumeValid,
 		"AutoPorts":              c.AutoPorts,
 		"BanDuration":            c.BanDuration,
 		"BanThreshold":           c.BanThreshold,
@@ -336,6 +365,7 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		"ConfigFile":             c.ConfigFile,
 		"ConnectPeers":           c.ConnectPeers,
 		"Controller":             c.Controller,
+		"ControllerConnect":      c.ControllerConnect,
 		"CPUProfile":             c.CPUProfile,
 		"DarkTheme":              c.DarkTheme,
 		"DataDir":                c.DataDir,
@@ -358,8 +388,10 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		"Listeners":              c.Listeners,
 		"LogDir":                 c.LogDir,
 		"LogLevel":               c.LogLevel,
+		"MaxMempool":             c.MaxMempool,
 		"MaxOrphanTxs":           c.MaxOrphanTxs,
 		"MaxPeers":               c.MaxPeers,
+		"MinerListener":          c.MinerListener,
 		"MinerPass":              c.MinerPass,
 		"MiningAddrs":            c.MiningAddrs,
 		"MinRelayTxFee":          c.MinRelayTxFee,
@@ -380,7 +412,9 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		"Proxy":                  c.Proxy,
 		"ProxyPass":              c.ProxyPass,
 		"ProxyUser":              c.ProxyUser,
+		"Prune":                  c.Prune,
 		"RejectNonStd":           c.RejectNonStd,
+		"RejectReplacement":      c.RejectReplacement,
 		"RelayNonStd":            c.RelayNonStd,
 		"RPCCert":                c.RPCCert,
 		"RPCConnect":             c.RPCConnect,
@@ -396,6 +430,8 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		"ServerUser":             c.ServerUser,
 		"SigCacheMaxSize":        c.SigCacheMaxSize,
 		"Solo":                   c.Solo,
+		"Stratum":                c.Stratum,
+		"StratumPass":            c.StratumPass,
 		"TLS":                    c.TLS,
 		"TLSSkipVerify":          c.TLSSkipVerify,
 		"TorIsolation":           c.TorIsolation,
@@ -405,13 +441,16 @@ func EmptyConfig() (c *Config, conf map[string]interface{}) {
 		"UserAgentComments":      c.UserAgentComments,
 		"Username":               c.Username,
 		"Wallet":                 c.Wallet,
+		"WalletCoinSelection":    c.WalletCoinSelection,
 		"WalletFile":             c.WalletFile,
 		"WalletOff":              c.WalletOff,
 		"WalletPass":             c.WalletPass,
+		"WalletRBF":              c.WalletRBF,
 		"WalletRPCListeners":     c.WalletRPCListeners,
 		"WalletRPCMaxClients":    c.WalletRPCMaxClients,
 		"WalletRPCMaxWebsockets": c.WalletRPCMaxWebsockets,
 		"WalletServer":           c.WalletServer,
+		"WalletWatchOnly":        c.WalletWatchOnly,
 		"Whitelists":             c.Whitelists,
 	}
 	return
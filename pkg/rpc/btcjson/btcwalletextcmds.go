@@ -24,6 +24,22 @@ func NewDumpWalletCmd(filename string) *DumpWalletCmd {
 	}
 }
 
+// ImportAccountCmd defines the importaccount JSON-RPC command.
+type ImportAccountCmd struct {
+	Account     string
+	ExtendedKey string
+	Rescan      *bool `jsonrpcdefault:"true"`
+}
+
+// NewImportAccountCmd returns a new instance which can be used to issue an importaccount JSON-RPC command.
+func NewImportAccountCmd(account, extendedKey string, rescan *bool) *ImportAccountCmd {
+	return &ImportAccountCmd{
+		Account:     account,
+		ExtendedKey: extendedKey,
+		Rescan:      rescan,
+	}
+}
+
 // ImportAddressCmd defines the importaddress JSON-RPC command.
 type ImportAddressCmd struct {
 	Address string
@@ -79,14 +95,36 @@ func NewRenameAccountCmd(oldAccount, newAccount string) *RenameAccountCmd {
 		NewAccount: newAccount,
 	}
 }
+
+// SetTxCommentCmd defines the settxcomment JSON-RPC command.
+type SetTxCommentCmd struct {
+	Txid      string
+	Comment   string
+	CommentTo *string
+}
+
+// NewSetTxCommentCmd returns a new instance which can be used to issue a settxcomment JSON-RPC command.
+//
+// The parameters which are pointers indicate they are optional. Passing nil for optional parameters will use the
+// default value.
+func NewSetTxCommentCmd(txHash, comment string, commentTo *string) *SetTxCommentCmd {
+	return &SetTxCommentCmd{
+		Txid:      txHash,
+		Comment:   comment,
+		CommentTo: commentTo,
+	}
+}
+
 func init() {
 	// The commands in this file are only usable with a wallet server.
 	flags := UFWalletOnly
 	MustRegisterCmd("createnewaccount", (*CreateNewAccountCmd)(nil), flags)
 	MustRegisterCmd("dumpwallet", (*DumpWalletCmd)(nil), flags)
+	MustRegisterCmd("importaccount", (*ImportAccountCmd)(nil), flags)
 	MustRegisterCmd("importaddress", (*ImportAddressCmd)(nil), flags)
 	MustRegisterCmd("importpubkey", (*ImportPubKeyCmd)(nil), flags)
 	MustRegisterCmd("importwallet", (*ImportWalletCmd)(nil), flags)
 	MustRegisterCmd("renameaccount", (*RenameAccountCmd)(nil), flags)
+	MustRegisterCmd("settxcomment", (*SetTxCommentCmd)(nil), flags)
 
 }
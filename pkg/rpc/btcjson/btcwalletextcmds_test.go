@@ -49,6 +49,36 @@ func TestBtcWalletExtCmds(t *testing.T) {
 				Filename: "filename",
 			},
 		},
+		{
+			name: "importaccount",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("importaccount", "cold", "xpub")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewImportAccountCmd("cold", "xpub", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importaccount","netparams":["cold","xpub"],"id":1}`,
+			unmarshalled: &btcjson.ImportAccountCmd{
+				Account:     "cold",
+				ExtendedKey: "xpub",
+				Rescan:      btcjson.Bool(true),
+			},
+		},
+		{
+			name: "importaccount optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("importaccount", "cold", "xpub", false)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewImportAccountCmd("cold", "xpub", btcjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"importaccount","netparams":["cold","xpub",false],"id":1}`,
+			unmarshalled: &btcjson.ImportAccountCmd{
+				Account:     "cold",
+				ExtendedKey: "xpub",
+				Rescan:      btcjson.Bool(false),
+			},
+		},
 		{
 			name: "importaddress",
 			newCmd: func() (interface{}, error) {
@@ -133,6 +163,36 @@ func TestBtcWalletExtCmds(t *testing.T) {
 				NewAccount: "newacct",
 			},
 		},
+		{
+			name: "settxcomment",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("settxcomment", "123", "rent")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetTxCommentCmd("123", "rent", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"settxcomment","netparams":["123","rent"],"id":1}`,
+			unmarshalled: &btcjson.SetTxCommentCmd{
+				Txid:      "123",
+				Comment:   "rent",
+				CommentTo: nil,
+			},
+		},
+		{
+			name: "settxcomment optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("settxcomment", "123", "rent", "landlord")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetTxCommentCmd("123", "rent", btcjson.String("landlord"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"settxcomment","netparams":["123","rent","landlord"],"id":1}`,
+			unmarshalled: &btcjson.SetTxCommentCmd{
+				Txid:      "123",
+				Comment:   "rent",
+				CommentTo: btcjson.String("landlord"),
+			},
+		},
 	}
 	t.Logf("Running %d tests", len(tests))
 	for i, test := range tests {
@@ -96,6 +96,17 @@ func TestChainSvrCmds(t *testing.T) {
 			marshalled:   `{"jsonrpc":"1.0","method":"decodescript","netparams":["00"],"id":1}`,
 			unmarshalled: &btcjson.DecodeScriptCmd{HexScript: "00"},
 		},
+		{
+			name: "dumptxoutset",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("dumptxoutset", "utxo.dat")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewDumpTxOutSetCmd("utxo.dat")
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"dumptxoutset","netparams":["utxo.dat"],"id":1}`,
+			unmarshalled: &btcjson.DumpTxOutSetCmd{Path: "utxo.dat"},
+		},
 		{
 			name: "getaddednodeinfo",
 			newCmd: func() (interface{}, error) {
@@ -121,6 +132,81 @@ func TestChainSvrCmds(t *testing.T) {
 				Node: btcjson.String("127.0.0.1"),
 			},
 		},
+		{
+			name: "getaddressbalance",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddressbalance", []string{"1Address"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddressBalanceCmd([]string{"1Address"})
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getaddressbalance","netparams":[["1Address"]],"id":1}`,
+			unmarshalled: &btcjson.GetAddressBalanceCmd{Addresses: []string{"1Address"}},
+		},
+		{
+			name: "getaddressdeltas",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddressdeltas", []string{"1Address"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddressDeltasCmd([]string{"1Address"}, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressdeltas","netparams":[["1Address"]],"id":1}`,
+			unmarshalled: &btcjson.GetAddressDeltasCmd{
+				Addresses: []string{"1Address"},
+				Start:     nil,
+				End:       nil,
+			},
+		},
+		{
+			name: "getaddressdeltas optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddressdeltas", []string{"1Address", "1Address2"}, 100, 200)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddressDeltasCmd([]string{"1Address", "1Address2"}, btcjson.Int32(100),
+					btcjson.Int32(200))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"getaddressdeltas","netparams":[["1Address","1Address2"],100,200],"id":1}`,
+			unmarshalled: &btcjson.GetAddressDeltasCmd{
+				Addresses: []string{"1Address", "1Address2"},
+				Start:     btcjson.Int32(100),
+				End:       btcjson.Int32(200),
+			},
+		},
+		{
+			name: "getaddressmempool",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddressmempool", []string{"1Address"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddressMempoolCmd([]string{"1Address"})
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getaddressmempool","netparams":[["1Address"]],"id":1}`,
+			unmarshalled: &btcjson.GetAddressMempoolCmd{Addresses: []string{"1Address"}},
+		},
+		{
+			name: "getaddressutxos",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getaddressutxos", []string{"1Address"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAddressUtxosCmd([]string{"1Address"})
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getaddressutxos","netparams":[["1Address"]],"id":1}`,
+			unmarshalled: &btcjson.GetAddressUtxosCmd{Addresses: []string{"1Address"}},
+		},
+		{
+			name: "getalgostats",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getalgostats", 100, 200)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetAlgoStatsCmd(100, 200)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getalgostats","netparams":[100,200],"id":1}`,
+			unmarshalled: &btcjson.GetAlgoStatsCmd{From: 100, To: 200},
+		},
 		{
 			name: "getbestblockhash",
 			newCmd: func() (interface{}, error) {
@@ -668,6 +754,17 @@ func TestChainSvrCmds(t *testing.T) {
 				Data: btcjson.String("00112233"),
 			},
 		},
+		{
+			name: "getworkerstats",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("getworkerstats")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewGetWorkerStatsCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"getworkerstats","netparams":[],"id":1}`,
+			unmarshalled: &btcjson.GetWorkerStatsCmd{},
+		},
 		{
 			name: "help",
 			newCmd: func() (interface{}, error) {
@@ -707,6 +804,31 @@ func TestChainSvrCmds(t *testing.T) {
 				BlockHash: "123",
 			},
 		},
+		{
+			name: "loadtxoutset",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("loadtxoutset", "utxo.dat")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewLoadTxOutSetCmd("utxo.dat", nil)
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"loadtxoutset","netparams":["utxo.dat"],"id":1}`,
+			unmarshalled: &btcjson.LoadTxOutSetCmd{Path: "utxo.dat", Hash: nil},
+		},
+		{
+			name: "loadtxoutset optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("loadtxoutset", "utxo.dat", "123")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewLoadTxOutSetCmd("utxo.dat", btcjson.String("123"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"loadtxoutset","netparams":["utxo.dat","123"],"id":1}`,
+			unmarshalled: &btcjson.LoadTxOutSetCmd{
+				Path: "utxo.dat",
+				Hash: btcjson.String("123"),
+			},
+		},
 		{
 			name: "ping",
 			newCmd: func() (interface{}, error) {
@@ -744,6 +866,17 @@ func TestChainSvrCmds(t *testing.T) {
 				BlockHash: "123",
 			},
 		},
+		{
+			name: "savemempool",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("savemempool")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSaveMempoolCmd()
+			},
+			marshalled:   `{"jsonrpc":"1.0","method":"savemempool","netparams":[],"id":1}`,
+			unmarshalled: &btcjson.SaveMempoolCmd{},
+		},
 		{
 			name: "searchrawtransactions",
 			newCmd: func() (interface{}, error) {
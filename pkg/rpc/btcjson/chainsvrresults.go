@@ -41,6 +41,68 @@ type GetAddedNodeInfoResultAddr struct {
 	Connected string `json:"connected"`
 }
 
+// GetAddressBalanceResult models the data from the getaddressbalance command. Amounts are in satoshis.
+type GetAddressBalanceResult struct {
+	Balance  int64 `json:"balance"`
+	Received int64 `json:"received"`
+}
+
+// GetAddressDeltasResult models the data of each change to the balance of an address returned from the
+// getaddressdeltas command.
+type GetAddressDeltasResult struct {
+	Address    string `json:"address"`
+	TxID       string `json:"txid"`
+	Index      uint32 `json:"index"`
+	BlockIndex uint32 `json:"blockindex"`
+	Height     int32  `json:"height"`
+	Satoshis   int64  `json:"satoshis"`
+}
+
+// GetAddressMempoolResult models the data of each change to the balance of an address returned from the
+// getaddressmempool command. PrevTxID and PrevOut are set for the inputs spending from the address.
+type GetAddressMempoolResult struct {
+	Address   string  `json:"address"`
+	TxID      string  `json:"txid"`
+	Index     uint32  `json:"index"`
+	Satoshis  int64   `json:"satoshis"`
+	Timestamp int64   `json:"timestamp"`
+	PrevTxID  string  `json:"prevtxid,omitempty"`
+	PrevOut   *uint32 `json:"prevout,omitempty"`
+}
+
+// GetAddressUtxosResult models the data of each unspent output returned from the getaddressutxos command.
+type GetAddressUtxosResult struct {
+	Address     string `json:"address"`
+	TxID        string `json:"txid"`
+	OutputIndex uint32 `json:"outputIndex"`
+	Script      string `json:"script"`
+	Satoshis    int64  `json:"satoshis"`
+	Height      int32  `json:"height"`
+}
+
+// GetAlgoStatsResult models the data from the getalgostats command.
+type GetAlgoStatsResult struct {
+	From   int32             `json:"from"`
+	To     int32             `json:"to"`
+	Blocks int32             `json:"blocks"`
+	Span   int64             `json:"span"`
+	Algos  []AlgoStatsResult `json:"algos"`
+}
+
+// AlgoStatsResult models the statistics of each algorithm returned from the getalgostats command.
+type AlgoStatsResult struct {
+	Algo           string  `json:"algo"`
+	Version        int32   `json:"version"`
+	Blocks         int32   `json:"blocks"`
+	Share          float64 `json:"share"`
+	AvgInterval    float64 `json:"avginterval"`
+	TargetInterval int64   `json:"targetinterval"`
+	Bits           string  `json:"bits"`
+	Difficulty     float64 `json:"difficulty"`
+	Hashrate       float64 `json:"hashrate"`
+	ChainWork      string  `json:"chainwork"`
+}
+
 // GetBlockChainInfoResult models the data returned from the getblockchaininfo command.
 type GetBlockChainInfoResult struct {
 	Chain                string                              `json:"chain"`
@@ -152,28 +214,40 @@ type GetBlockVerboseResult struct {
 	NextHash      string        `json:"nextblockhash,omitempty"`
 }
 
+// GetChainTipsResult models the data returned from the getchaintips command.
+type GetChainTipsResult struct {
+	Height    int32  `json:"height"`
+	Hash      string `json:"hash"`
+	BranchLen int32  `json:"branchlen"`
+	Status    string `json:"status"`
+}
+
 // GetMempoolEntryResult models the data returned from the getmempoolentry command.
 type GetMempoolEntryResult struct {
-	Size             int32    `json:"size"`
-	Fee              float64  `json:"fee"`
-	ModifiedFee      float64  `json:"modifiedfee"`
-	Time             int64    `json:"time"`
-	Height           int64    `json:"height"`
-	StartingPriority float64  `json:"startingpriority"`
-	CurrentPriority  float64  `json:"currentpriority"`
-	DescendantCount  int64    `json:"descendantcount"`
-	DescendantSize   int64    `json:"descendantsize"`
-	DescendantFees   float64  `json:"descendantfees"`
-	AncestorCount    int64    `json:"ancestorcount"`
-	AncestorSize     int64    `json:"ancestorsize"`
-	AncestorFees     float64  `json:"ancestorfees"`
-	Depends          []string `json:"depends"`
+	Size              int32    `json:"size"`
+	Fee               float64  `json:"fee"`
+	ModifiedFee       float64  `json:"modifiedfee"`
+	Time              int64    `json:"time"`
+	Height            int64    `json:"height"`
+	StartingPriority  float64  `json:"startingpriority"`
+	CurrentPriority   float64  `json:"currentpriority"`
+	DescendantCount   int64    `json:"descendantcount"`
+	DescendantSize    int64    `json:"descendantsize"`
+	DescendantFees    float64  `json:"descendantfees"`
+	AncestorCount     int64    `json:"ancestorcount"`
+	AncestorSize      int64    `json:"ancestorsize"`
+	AncestorFees      float64  `json:"ancestorfees"`
+	Depends           []string `json:"depends"`
+	BIP125Replaceable bool     `json:"bip125-replaceable"`
 }
 
 // GetMempoolInfoResult models the data returned from the getmempoolinfo command.
 type GetMempoolInfoResult struct {
-	Size  int64 `json:"size"`
-	Bytes int64 `json:"bytes"`
+	Size          int64   `json:"size"`
+	Bytes         int64   `json:"bytes"`
+	MaxMempool    int64   `json:"maxmempool"`
+	MempoolMinFee float64 `json:"mempoolminfee"`
+	MinRelayTxFee float64 `json:"minrelaytxfee"`
 }
 
 // GetMiningInfoResult models the data from the getmininginfo command.
@@ -294,6 +368,44 @@ type GetTxOutResult struct {
 	Coinbase      bool               `json:"coinbase"`
 }
 
+// GetTxOutSetInfoResult models the data from the gettxoutsetinfo command.
+type GetTxOutSetInfoResult struct {
+	Height          int32   `json:"height"`
+	BestBlock       string  `json:"bestblock"`
+	Transactions    int64   `json:"transactions"`
+	TxOuts          int64   `json:"txouts"`
+	BytesSerialized int64   `json:"bytes_serialized"`
+	HashSerialized  string  `json:"hash_serialized"`
+	TotalAmount     float64 `json:"total_amount"`
+}
+
+// TxOutSetSnapshotResult models the data from the dumptxoutset and loadtxoutset commands.
+type TxOutSetSnapshotResult struct {
+	Path           string `json:"path"`
+	BaseHash       string `json:"base_hash"`
+	BaseHeight     int32  `json:"base_height"`
+	Coins          int64  `json:"coins"`
+	HashSerialized string `json:"hash_serialized"`
+}
+
+// GetWorkerStatsResult models the data of each kopach miner returned from the getworkerstats command.
+type GetWorkerStatsResult struct {
+	ID       string                  `json:"id"`
+	Address  string                  `json:"address"`
+	LastSeen int64                   `json:"lastseen"`
+	Algos    []WorkerAlgoStatsResult `json:"algos"`
+}
+
+// WorkerAlgoStatsResult models the work of a kopach miner on one algorithm in the getworkerstats command.
+type WorkerAlgoStatsResult struct {
+	Algo         string  `json:"algo"`
+	HashesPerSec float64 `json:"hashespersec"`
+	Hashes       uint64  `json:"hashes"`
+	Accepted     uint64  `json:"accepted"`
+	Stale        uint64  `json:"stale"`
+	Invalid      uint64  `json:"invalid"`
+}
+
 // GetWorkResult models the data from the getwork command.
 type GetWorkResult struct {
 	Data     string `json:"data"`
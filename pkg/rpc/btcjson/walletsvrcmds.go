@@ -30,6 +30,50 @@ func NewAddWitnessAddressCmd(address string) *AddWitnessAddressCmd {
 	}
 }
 
+// BackupWalletCmd defines the backupwallet JSON-RPC command.
+type BackupWalletCmd struct {
+	Destination string
+}
+
+// NewBackupWalletCmd returns a new instance which can be used to issue a backupwallet JSON-RPC command.
+func NewBackupWalletCmd(destination string) *BackupWalletCmd {
+	return &BackupWalletCmd{
+		Destination: destination,
+	}
+}
+
+// BumpFeeOptions houses the optional parameters of the bumpfee JSON-RPC command.
+type BumpFeeOptions struct {
+	FeeRate *float64 `json:"feerate,omitempty"`
+}
+
+// BumpFeeCmd defines the bumpfee JSON-RPC command.
+type BumpFeeCmd struct {
+	TxID    string
+	Options *BumpFeeOptions
+}
+
+// NewBumpFeeCmd returns a new instance which can be used to issue a bumpfee JSON-RPC command. The parameters which are
+// pointers indicate they are optional. Passing nil for optional parameters will use the default value.
+func NewBumpFeeCmd(txHash string, options *BumpFeeOptions) *BumpFeeCmd {
+	return &BumpFeeCmd{
+		TxID:    txHash,
+		Options: options,
+	}
+}
+
+// CombinePsbtCmd defines the combinepsbt JSON-RPC command.
+type CombinePsbtCmd struct {
+	Psbts []string
+}
+
+// NewCombinePsbtCmd returns a new instance which can be used to issue a combinepsbt JSON-RPC command.
+func NewCombinePsbtCmd(psbts []string) *CombinePsbtCmd {
+	return &CombinePsbtCmd{
+		Psbts: psbts,
+	}
+}
+
 // CreateMultisigCmd defines the createmultisig JSON-RPC command.
 type CreateMultisigCmd struct {
 	NRequired int
@@ -44,6 +88,18 @@ func NewCreateMultisigCmd(nRequired int, keys []string) *CreateMultisigCmd {
 	}
 }
 
+// DecodePsbtCmd defines the decodepsbt JSON-RPC command.
+type DecodePsbtCmd struct {
+	Psbt string
+}
+
+// NewDecodePsbtCmd returns a new instance which can be used to issue a decodepsbt JSON-RPC command.
+func NewDecodePsbtCmd(psbt string) *DecodePsbtCmd {
+	return &DecodePsbtCmd{
+		Psbt: psbt,
+	}
+}
+
 // DropWalletHistoryCmd defines the restart JSON-RPC command.
 type DropWalletHistoryCmd struct{}
 
@@ -100,6 +156,21 @@ func NewEstimatePriorityCmd(numBlocks int64) *EstimatePriorityCmd {
 	}
 }
 
+// FinalizePsbtCmd defines the finalizepsbt JSON-RPC command.
+type FinalizePsbtCmd struct {
+	Psbt    string
+	Extract *bool `jsonrpcdefault:"true"`
+}
+
+// NewFinalizePsbtCmd returns a new instance which can be used to issue a finalizepsbt JSON-RPC command. The parameters
+// which are pointers indicate they are optional. Passing nil for optional parameters will use the default value.
+func NewFinalizePsbtCmd(psbt string, extract *bool) *FinalizePsbtCmd {
+	return &FinalizePsbtCmd{
+		Psbt:    psbt,
+		Extract: extract,
+	}
+}
+
 // GetAccountCmd defines the getaccount JSON-RPC command.
 type GetAccountCmd struct {
 	Address string
@@ -426,63 +497,71 @@ func NewMoveCmd(fromAccount, toAccount string, amount float64, minConf *int, com
 
 // SendFromCmd defines the sendfrom JSON-RPC command.
 type SendFromCmd struct {
-	FromAccount string
-	ToAddress   string
-	Amount      float64 // In DUO
-	MinConf     *int    `jsonrpcdefault:"1"`
-	Comment     *string
-	CommentTo   *string
+	FromAccount   string
+	ToAddress     string
+	Amount        float64 // In DUO
+	MinConf       *int    `jsonrpcdefault:"1"`
+	Comment       *string
+	CommentTo     *string
+	CoinSelection *string
 }
 
 // NewSendFromCmd returns a new instance which can be used to issue a sendfrom JSON-RPC command. The parameters which
 // are pointers indicate they are optional. Passing nil for optional parameters will use the default value.
-func NewSendFromCmd(fromAccount, toAddress string, amount float64, minConf *int, comment, commentTo *string) *SendFromCmd {
+func NewSendFromCmd(fromAccount, toAddress string, amount float64, minConf *int, comment, commentTo,
+	coinSelection *string) *SendFromCmd {
 	return &SendFromCmd{
-		FromAccount: fromAccount,
-		ToAddress:   toAddress,
-		Amount:      amount,
-		MinConf:     minConf,
-		Comment:     comment,
-		CommentTo:   commentTo,
+		FromAccount:   fromAccount,
+		ToAddress:     toAddress,
+		Amount:        amount,
+		MinConf:       minConf,
+		Comment:       comment,
+		CommentTo:     commentTo,
+		CoinSelection: coinSelection,
 	}
 }
 
 // SendManyCmd defines the sendmany JSON-RPC command.
 type SendManyCmd struct {
-	FromAccount string
-	Amounts     map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"` // In DUO
-	MinConf     *int               `jsonrpcdefault:"1"`
-	Comment     *string
+	FromAccount   string
+	Amounts       map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"` // In DUO
+	MinConf       *int               `jsonrpcdefault:"1"`
+	Comment       *string
+	CoinSelection *string
 }
 
 // NewSendManyCmd returns a new instance which can be used to issue a sendmany JSON-RPC command. The parameters which
 // are pointers indicate they are optional. Passing nil for optional parameters will use the default value.
-func NewSendManyCmd(fromAccount string, amounts map[string]float64, minConf *int, comment *string) *SendManyCmd {
+func NewSendManyCmd(fromAccount string, amounts map[string]float64, minConf *int, comment,
+	coinSelection *string) *SendManyCmd {
 	return &SendManyCmd{
-		FromAccount: fromAccount,
-		Amounts:     amounts,
-		MinConf:     minConf,
-		Comment:     comment,
+		FromAccount:   fromAccount,
+		Amounts:       amounts,
+		MinConf:       minConf,
+		Comment:       comment,
+		CoinSelection: coinSelection,
 	}
 }
 
 // SendToAddressCmd defines the sendtoaddress JSON-RPC command.
 type SendToAddressCmd struct {
-	Address   string
-	Amount    float64
-	Comment   *string
-	CommentTo *string
+	Address       string
+	Amount        float64
+	Comment       *string
+	CommentTo     *string
+	CoinSelection *string
 }
 
 // NewSendToAddressCmd returns a new instance which can be used to issue a sendtoaddress JSON-RPC command. The
 // parameters which are pointers indicate they are optional. Passing nil for optional parameters will use the default
 // value.
-func NewSendToAddressCmd(address string, amount float64, comment, commentTo *string) *SendToAddressCmd {
+func NewSendToAddressCmd(address string, amount float64, comment, commentTo, coinSelection *string) *SendToAddressCmd {
 	return &SendToAddressCmd{
-		Address:   address,
-		Amount:    amount,
-		Comment:   comment,
-		CommentTo: commentTo,
+		Address:       address,
+		Amount:        amount,
+		Comment:       comment,
+		CommentTo:     commentTo,
+		CoinSelection: coinSelection,
 	}
 }
 
@@ -500,6 +579,20 @@ func NewSetAccountCmd(address, account string) *SetAccountCmd {
 	}
 }
 
+// SetLabelCmd defines the setlabel JSON-RPC command.
+type SetLabelCmd struct {
+	Address string
+	Label   string
+}
+
+// NewSetLabelCmd returns a new instance which can be used to issue a setlabel JSON-RPC command.
+func NewSetLabelCmd(address, label string) *SetLabelCmd {
+	return &SetLabelCmd{
+		Address: address,
+		Label:   label,
+	}
+}
+
 // SetTxFeeCmd defines the settxfee JSON-RPC command.
 type SetTxFeeCmd struct {
 	Amount float64 // In DUO
@@ -554,6 +647,42 @@ func NewSignRawTransactionCmd(hexEncodedTx string, inputs *[]RawTxInput, privKey
 	}
 }
 
+// PsbtInput models an input to spend that is used in the WalletCreateFundedPsbtCmd struct.
+type PsbtInput struct {
+	Txid     string  `json:"txid"`
+	Vout     uint32  `json:"vout"`
+	Sequence *uint32 `json:"sequence,omitempty"`
+}
+
+// WalletCreateFundedPsbtOptions houses the optional parameters of the walletcreatefundedpsbt JSON-RPC command.
+type WalletCreateFundedPsbtOptions struct {
+	Account       *string  `json:"account,omitempty"`
+	FeeRate       *float64 `json:"feerate,omitempty"`
+	LockUnspents  *bool    `json:"lockunspents,omitempty"`
+	CoinSelection *string  `json:"coinselection,omitempty"`
+}
+
+// WalletCreateFundedPsbtCmd defines the walletcreatefundedpsbt JSON-RPC command.
+type WalletCreateFundedPsbtCmd struct {
+	Inputs   []PsbtInput
+	Outputs  map[string]float64 `jsonrpcusage:"{\"address\":amount,...}"` // In DUO
+	LockTime *uint32
+	Options  *WalletCreateFundedPsbtOptions
+}
+
+// NewWalletCreateFundedPsbtCmd returns a new instance which can be used to issue a walletcreatefundedpsbt JSON-RPC
+// command. Amounts are in DUO. The parameters which are pointers indicate they are optional. Passing nil for optional
+// parameters will use the default value.
+func NewWalletCreateFundedPsbtCmd(inputs []PsbtInput, outputs map[string]float64, lockTime *uint32,
+	options *WalletCreateFundedPsbtOptions) *WalletCreateFundedPsbtCmd {
+	return &WalletCreateFundedPsbtCmd{
+		Inputs:   inputs,
+		Outputs:  outputs,
+		LockTime: lockTime,
+		Options:  options,
+	}
+}
+
 // WalletLockCmd defines the walletlock JSON-RPC command.
 type WalletLockCmd struct{}
 
@@ -576,6 +705,24 @@ func NewWalletPassphraseCmd(passphrase string, timeout int64) *WalletPassphraseC
 	}
 }
 
+// WalletProcessPsbtCmd defines the walletprocesspsbt JSON-RPC command.
+type WalletProcessPsbtCmd struct {
+	Psbt        string
+	Sign        *bool   `jsonrpcdefault:"true"`
+	SighashType *string `jsonrpcdefault:"\"ALL\""`
+}
+
+// NewWalletProcessPsbtCmd returns a new instance which can be used to issue a walletprocesspsbt JSON-RPC command. The
+// parameters which are pointers indicate they are optional. Passing nil for optional parameters will use the default
+// value.
+func NewWalletProcessPsbtCmd(psbt string, sign *bool, sighashType *string) *WalletProcessPsbtCmd {
+	return &WalletProcessPsbtCmd{
+		Psbt:        psbt,
+		Sign:        sign,
+		SighashType: sighashType,
+	}
+}
+
 // WalletPassphraseChangeCmd defines the walletpassphrase JSON-RPC command.
 type WalletPassphraseChangeCmd struct {
 	OldPassphrase string
@@ -595,12 +742,17 @@ func init() {
 	flags := UFWalletOnly
 	MustRegisterCmd("addmultisigaddress", (*AddMultisigAddressCmd)(nil), flags)
 	MustRegisterCmd("addwitnessaddress", (*AddWitnessAddressCmd)(nil), flags)
+	MustRegisterCmd("backupwallet", (*BackupWalletCmd)(nil), flags)
+	MustRegisterCmd("bumpfee", (*BumpFeeCmd)(nil), flags)
+	MustRegisterCmd("combinepsbt", (*CombinePsbtCmd)(nil), flags)
 	MustRegisterCmd("createmultisig", (*CreateMultisigCmd)(nil), flags)
+	MustRegisterCmd("decodepsbt", (*DecodePsbtCmd)(nil), flags)
 	MustRegisterCmd("dropwallethistory", (*DropWalletHistoryCmd)(nil), flags)
 	MustRegisterCmd("dumpprivkey", (*DumpPrivKeyCmd)(nil), flags)
 	MustRegisterCmd("encryptwallet", (*EncryptWalletCmd)(nil), flags)
 	MustRegisterCmd("estimatefee", (*EstimateFeeCmd)(nil), flags)
 	MustRegisterCmd("estimatepriority", (*EstimatePriorityCmd)(nil), flags)
+	MustRegisterCmd("finalizepsbt", (*FinalizePsbtCmd)(nil), flags)
 	MustRegisterCmd("getaccount", (*GetAccountCmd)(nil), flags)
 	MustRegisterCmd("getaccountaddress", (*GetAccountAddressCmd)(nil), flags)
 	MustRegisterCmd("getaddressesbyaccount", (*GetAddressesByAccountCmd)(nil), flags)
@@ -627,10 +779,13 @@ func init() {
 	MustRegisterCmd("sendmany", (*SendManyCmd)(nil), flags)
 	MustRegisterCmd("sendtoaddress", (*SendToAddressCmd)(nil), flags)
 	MustRegisterCmd("setaccount", (*SetAccountCmd)(nil), flags)
+	MustRegisterCmd("setlabel", (*SetLabelCmd)(nil), flags)
 	MustRegisterCmd("settxfee", (*SetTxFeeCmd)(nil), flags)
 	MustRegisterCmd("signmessage", (*SignMessageCmd)(nil), flags)
 	MustRegisterCmd("signrawtransaction", (*SignRawTransactionCmd)(nil), flags)
+	MustRegisterCmd("walletcreatefundedpsbt", (*WalletCreateFundedPsbtCmd)(nil), flags)
 	MustRegisterCmd("walletlock", (*WalletLockCmd)(nil), flags)
 	MustRegisterCmd("walletpassphrase", (*WalletPassphraseCmd)(nil), flags)
 	MustRegisterCmd("walletpassphrasechange", (*WalletPassphraseChangeCmd)(nil), flags)
+	MustRegisterCmd("walletprocesspsbt", (*WalletProcessPsbtCmd)(nil), flags)
 }
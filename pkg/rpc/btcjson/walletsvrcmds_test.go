@@ -68,6 +68,64 @@ func TestWalletSvrCmds(t *testing.T) {
 				Address: "1address",
 			},
 		},
+		{
+			name: "backupwallet",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("backupwallet", "backup.db")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewBackupWalletCmd("backup.db")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"backupwallet","netparams":["backup.db"],"id":1}`,
+			unmarshalled: &btcjson.BackupWalletCmd{
+				Destination: "backup.db",
+			},
+		},
+		{
+			name: "bumpfee",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("bumpfee", "123")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewBumpFeeCmd("123", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"bumpfee","netparams":["123"],"id":1}`,
+			unmarshalled: &btcjson.BumpFeeCmd{
+				TxID:    "123",
+				Options: nil,
+			},
+		},
+		{
+			name: "bumpfee optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("bumpfee", "123", `{"feerate":0.0002}`)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewBumpFeeCmd("123", &btcjson.BumpFeeOptions{
+					FeeRate: btcjson.Float64(0.0002),
+				})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"bumpfee","netparams":["123",{"feerate":0.0002}],"id":1}`,
+			unmarshalled: &btcjson.BumpFeeCmd{
+				TxID: "123",
+				Options: &btcjson.BumpFeeOptions{
+					FeeRate: btcjson.Float64(0.0002),
+				},
+			},
+		},
+		{
+			name: "combinepsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("combinepsbt", []string{"cHNidP8B", "cHNidP8C"})
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewCombinePsbtCmd([]string{"cHNidP8B", "cHNidP8C"})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"combinepsbt","netparams":[["cHNidP8B","cHNidP8C"]],"id":1}`,
+			unmarshalled: &btcjson.CombinePsbtCmd{
+				Psbts: []string{"cHNidP8B", "cHNidP8C"},
+			},
+		},
 		{
 			name: "createmultisig",
 			newCmd: func() (interface{}, error) {
@@ -83,6 +141,19 @@ func TestWalletSvrCmds(t *testing.T) {
 				Keys:      []string{"031234", "035678"},
 			},
 		},
+		{
+			name: "decodepsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("decodepsbt", "cHNidP8B")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewDecodePsbtCmd("cHNidP8B")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"decodepsbt","netparams":["cHNidP8B"],"id":1}`,
+			unmarshalled: &btcjson.DecodePsbtCmd{
+				Psbt: "cHNidP8B",
+			},
+		},
 		{
 			name: "dumpprivkey",
 			newCmd: func() (interface{}, error) {
@@ -135,6 +206,34 @@ func TestWalletSvrCmds(t *testing.T) {
 				NumBlocks: 6,
 			},
 		},
+		{
+			name: "finalizepsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("finalizepsbt", "cHNidP8B")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewFinalizePsbtCmd("cHNidP8B", nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","netparams":["cHNidP8B"],"id":1}`,
+			unmarshalled: &btcjson.FinalizePsbtCmd{
+				Psbt:    "cHNidP8B",
+				Extract: btcjson.Bool(true),
+			},
+		},
+		{
+			name: "finalizepsbt optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("finalizepsbt", "cHNidP8B", false)
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewFinalizePsbtCmd("cHNidP8B", btcjson.Bool(false))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"finalizepsbt","netparams":["cHNidP8B",false],"id":1}`,
+			unmarshalled: &btcjson.FinalizePsbtCmd{
+				Psbt:    "cHNidP8B",
+				Extract: btcjson.Bool(false),
+			},
+		},
 		{
 			name: "getaccount",
 			newCmd: func() (interface{}, error) {
@@ -881,7 +980,7 @@ func TestWalletSvrCmds(t *testing.T) {
 				return btcjson.NewCmd("sendfrom", "from", "1Address", 0.5)
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewSendFromCmd("from", "1Address", 0.5, nil, nil, nil)
+				return btcjson.NewSendFromCmd("from", "1Address", 0.5, nil, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","netparams":["from","1Address",0.5],"id":1}`,
 			unmarshalled: &btcjson.SendFromCmd{
@@ -899,7 +998,7 @@ func TestWalletSvrCmds(t *testing.T) {
 				return btcjson.NewCmd("sendfrom", "from", "1Address", 0.5, 6)
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewSendFromCmd("from", "1Address", 0.5, btcjson.Int(6), nil, nil)
+				return btcjson.NewSendFromCmd("from", "1Address", 0.5, btcjson.Int(6), nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","netparams":["from","1Address",0.5,6],"id":1}`,
 			unmarshalled: &btcjson.SendFromCmd{
@@ -918,7 +1017,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return btcjson.NewSendFromCmd("from", "1Address", 0.5, btcjson.Int(6),
-					btcjson.String("comment"), nil)
+					btcjson.String("comment"), nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","netparams":["from","1Address",0.5,6,"comment"],"id":1}`,
 			unmarshalled: &btcjson.SendFromCmd{
@@ -937,7 +1036,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return btcjson.NewSendFromCmd("from", "1Address", 0.5, btcjson.Int(6),
-					btcjson.String("comment"), btcjson.String("commentto"))
+					btcjson.String("comment"), btcjson.String("commentto"), nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","netparams":["from","1Address",0.5,6,"comment","commentto"],"id":1}`,
 			unmarshalled: &btcjson.SendFromCmd{
@@ -949,6 +1048,26 @@ func TestWalletSvrCmds(t *testing.T) {
 				CommentTo:   btcjson.String("commentto"),
 			},
 		},
+		{
+			name: "sendfrom optional4",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("sendfrom", "from", "1Address", 0.5, 6, "comment", "commentto", "privacy")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSendFromCmd("from", "1Address", 0.5, btcjson.Int(6),
+					btcjson.String("comment"), btcjson.String("commentto"), btcjson.String("privacy"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sendfrom","netparams":["from","1Address",0.5,6,"comment","commentto","privacy"],"id":1}`,
+			unmarshalled: &btcjson.SendFromCmd{
+				FromAccount:   "from",
+				ToAddress:     "1Address",
+				Amount:        0.5,
+				MinConf:       btcjson.Int(6),
+				Comment:       btcjson.String("comment"),
+				CommentTo:     btcjson.String("commentto"),
+				CoinSelection: btcjson.String("privacy"),
+			},
+		},
 		{
 			name: "sendmany",
 			newCmd: func() (interface{}, error) {
@@ -956,7 +1075,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				amounts := map[string]float64{"1Address": 0.5}
-				return btcjson.NewSendManyCmd("from", amounts, nil, nil)
+				return btcjson.NewSendManyCmd("from", amounts, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendmany","netparams":["from",{"1Address":0.5}],"id":1}`,
 			unmarshalled: &btcjson.SendManyCmd{
@@ -973,7 +1092,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				amounts := map[string]float64{"1Address": 0.5}
-				return btcjson.NewSendManyCmd("from", amounts, btcjson.Int(6), nil)
+				return btcjson.NewSendManyCmd("from", amounts, btcjson.Int(6), nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendmany","netparams":["from",{"1Address":0.5},6],"id":1}`,
 			unmarshalled: &btcjson.SendManyCmd{
@@ -990,7 +1109,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				amounts := map[string]float64{"1Address": 0.5}
-				return btcjson.NewSendManyCmd("from", amounts, btcjson.Int(6), btcjson.String("comment"))
+				return btcjson.NewSendManyCmd("from", amounts, btcjson.Int(6), btcjson.String("comment"), nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendmany","netparams":["from",{"1Address":0.5},6,"comment"],"id":1}`,
 			unmarshalled: &btcjson.SendManyCmd{
@@ -1000,13 +1119,32 @@ func TestWalletSvrCmds(t *testing.T) {
 				Comment:     btcjson.String("comment"),
 			},
 		},
+		{
+			name: "sendmany optional3",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("sendmany", "from", `{"1Address":0.5}`, 6, "comment", "knapsack")
+			},
+			staticCmd: func() interface{} {
+				amounts := map[string]float64{"1Address": 0.5}
+				return btcjson.NewSendManyCmd("from", amounts, btcjson.Int(6), btcjson.String("comment"),
+					btcjson.String("knapsack"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sendmany","netparams":["from",{"1Address":0.5},6,"comment","knapsack"],"id":1}`,
+			unmarshalled: &btcjson.SendManyCmd{
+				FromAccount:   "from",
+				Amounts:       map[string]float64{"1Address": 0.5},
+				MinConf:       btcjson.Int(6),
+				Comment:       btcjson.String("comment"),
+				CoinSelection: btcjson.String("knapsack"),
+			},
+		},
 		{
 			name: "sendtoaddress",
 			newCmd: func() (interface{}, error) {
 				return btcjson.NewCmd("sendtoaddress", "1Address", 0.5)
 			},
 			staticCmd: func() interface{} {
-				return btcjson.NewSendToAddressCmd("1Address", 0.5, nil, nil)
+				return btcjson.NewSendToAddressCmd("1Address", 0.5, nil, nil, nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","netparams":["1Address",0.5],"id":1}`,
 			unmarshalled: &btcjson.SendToAddressCmd{
@@ -1023,7 +1161,7 @@ func TestWalletSvrCmds(t *testing.T) {
 			},
 			staticCmd: func() interface{} {
 				return btcjson.NewSendToAddressCmd("1Address", 0.5, btcjson.String("comment"),
-					btcjson.String("commentto"))
+					btcjson.String("commentto"), nil)
 			},
 			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","netparams":["1Address",0.5,"comment","commentto"],"id":1}`,
 			unmarshalled: &btcjson.SendToAddressCmd{
@@ -1033,6 +1171,24 @@ func TestWalletSvrCmds(t *testing.T) {
 				CommentTo: btcjson.String("commentto"),
 			},
 		},
+		{
+			name: "sendtoaddress optional2",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("sendtoaddress", "1Address", 0.5, "comment", "commentto", "bnb")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSendToAddressCmd("1Address", 0.5, btcjson.String("comment"),
+					btcjson.String("commentto"), btcjson.String("bnb"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"sendtoaddress","netparams":["1Address",0.5,"comment","commentto","bnb"],"id":1}`,
+			unmarshalled: &btcjson.SendToAddressCmd{
+				Address:       "1Address",
+				Amount:        0.5,
+				Comment:       btcjson.String("comment"),
+				CommentTo:     btcjson.String("commentto"),
+				CoinSelection: btcjson.String("bnb"),
+			},
+		},
 		{
 			name: "setaccount",
 			newCmd: func() (interface{}, error) {
@@ -1047,6 +1203,20 @@ func TestWalletSvrCmds(t *testing.T) {
 				Account: "acct",
 			},
 		},
+		{
+			name: "setlabel",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("setlabel", "1Address", "rent")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewSetLabelCmd("1Address", "rent")
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"setlabel","netparams":["1Address","rent"],"id":1}`,
+			unmarshalled: &btcjson.SetLabelCmd{
+				Address: "1Address",
+				Label:   "rent",
+			},
+		},
 		{
 			name: "settxfee",
 			newCmd: func() (interface{}, error) {
@@ -1158,6 +1328,68 @@ func TestWalletSvrCmds(t *testing.T) {
 				Flags:    btcjson.String("ALL"),
 			},
 		},
+		{
+			name: "walletcreatefundedpsbt",
+			newCmd: func() (interface{}, error) {
+				txInputs := []btcjson.PsbtInput{
+					{Txid: "123", Vout: 1},
+				}
+				outputs := map[string]float64{"1Address": 0.5}
+				return btcjson.NewCmd("walletcreatefundedpsbt", txInputs, outputs)
+			},
+			staticCmd: func() interface{} {
+				txInputs := []btcjson.PsbtInput{
+					{Txid: "123", Vout: 1},
+				}
+				outputs := map[string]float64{"1Address": 0.5}
+				return btcjson.NewWalletCreateFundedPsbtCmd(txInputs, outputs, nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletcreatefundedpsbt","netparams":[[{"txid":"123","vout":1}],{"1Address":0.5}],"id":1}`,
+			unmarshalled: &btcjson.WalletCreateFundedPsbtCmd{
+				Inputs: []btcjson.PsbtInput{
+					{Txid: "123", Vout: 1},
+				},
+				Outputs: map[string]float64{"1Address": 0.5},
+			},
+		},
+		{
+			name: "walletcreatefundedpsbt optional",
+			newCmd: func() (interface{}, error) {
+				txInputs := []btcjson.PsbtInput{
+					{Txid: "123", Vout: 1, Sequence: btcjson.Uint32(0xfffffffd)},
+				}
+				outputs := map[string]float64{"1Address": 0.5}
+				return btcjson.NewCmd("walletcreatefundedpsbt", txInputs, outputs, 12312333,
+					`{"account":"acct","feerate":0.0002,"lockunspents":true,"coinselection":"privacy"}`)
+			},
+			staticCmd: func() interface{} {
+				txInputs := []btcjson.PsbtInput{
+					{Txid: "123", Vout: 1, Sequence: btcjson.Uint32(0xfffffffd)},
+				}
+				outputs := map[string]float64{"1Address": 0.5}
+				return btcjson.NewWalletCreateFundedPsbtCmd(txInputs, outputs, btcjson.Uint32(12312333),
+					&btcjson.WalletCreateFundedPsbtOptions{
+						Account:       btcjson.String("acct"),
+						FeeRate:       btcjson.Float64(0.0002),
+						LockUnspents:  btcjson.Bool(true),
+						CoinSelection: btcjson.String("privacy"),
+					})
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletcreatefundedpsbt","netparams":[[{"txid":"123","vout":1,"sequence":4294967293}],{"1Address":0.5},12312333,{"account":"acct","feerate":0.0002,"lockunspents":true,"coinselection":"privacy"}],"id":1}`,
+			unmarshalled: &btcjson.WalletCreateFundedPsbtCmd{
+				Inputs: []btcjson.PsbtInput{
+					{Txid: "123", Vout: 1, Sequence: btcjson.Uint32(0xfffffffd)},
+				},
+				Outputs:  map[string]float64{"1Address": 0.5},
+				LockTime: btcjson.Uint32(12312333),
+				Options: &btcjson.WalletCreateFundedPsbtOptions{
+					Account:       btcjson.String("acct"),
+					FeeRate:       btcjson.Float64(0.0002),
+					LockUnspents:  btcjson.Bool(true),
+					CoinSelection: btcjson.String("privacy"),
+				},
+			},
+		},
 		{
 			name: "walletlock",
 			newCmd: func() (interface{}, error) {
@@ -1197,6 +1429,37 @@ func TestWalletSvrCmds(t *testing.T) {
 				NewPassphrase: "new",
 			},
 		},
+		{
+			name: "walletprocesspsbt",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("walletprocesspsbt", "cHNidP8B")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewWalletProcessPsbtCmd("cHNidP8B", nil, nil)
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletprocesspsbt","netparams":["cHNidP8B"],"id":1}`,
+			unmarshalled: &btcjson.WalletProcessPsbtCmd{
+				Psbt:        "cHNidP8B",
+				Sign:        btcjson.Bool(true),
+				SighashType: btcjson.String("ALL"),
+			},
+		},
+		{
+			name: "walletprocesspsbt optional",
+			newCmd: func() (interface{}, error) {
+				return btcjson.NewCmd("walletprocesspsbt", "cHNidP8B", false, "SINGLE|ANYONECANPAY")
+			},
+			staticCmd: func() interface{} {
+				return btcjson.NewWalletProcessPsbtCmd("cHNidP8B", btcjson.Bool(false),
+					btcjson.String("SINGLE|ANYONECANPAY"))
+			},
+			marshalled: `{"jsonrpc":"1.0","method":"walletprocesspsbt","netparams":["cHNidP8B",false,"SINGLE|ANYONECANPAY"],"id":1}`,
+			unmarshalled: &btcjson.WalletProcessPsbtCmd{
+				Psbt:        "cHNidP8B",
+				Sign:        btcjson.Bool(false),
+				SighashType: btcjson.String("SINGLE|ANYONECANPAY"),
+			},
+		},
 	}
 	t.Logf("Running %d tests", len(tests))
 	for i, test := range tests {
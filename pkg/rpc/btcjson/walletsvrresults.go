@@ -1,6 +1,61 @@
 package btcjson
 
 type (
+	// BumpFeeResult models the data from the bumpfee command.
+	BumpFeeResult struct {
+		TxID    string  `json:"txid"`
+		OrigFee float64 `json:"origfee"`
+		Fee     float64 `json:"fee"`
+	}
+	// DecodePsbtBip32Deriv models the BIP0032 derivation of a public key in the data from the decodepsbt command.
+	DecodePsbtBip32Deriv struct {
+		PubKey            string `json:"pubkey"`
+		MasterFingerprint string `json:"master_fingerprint"`
+		Path              string `json:"path"`
+	}
+	// DecodePsbtInput models an input in the data from the decodepsbt command.
+	DecodePsbtInput struct {
+		NonWitnessUtxo     *TxRawDecodeResult     `json:"non_witness_utxo,omitempty"`
+		WitnessUtxo        *DecodePsbtWitnessUtxo `json:"witness_utxo,omitempty"`
+		PartialSignatures  map[string]string      `json:"partial_signatures,omitempty"`
+		Sighash            string                 `json:"sighash,omitempty"`
+		RedeemScript       *ScriptPubKeyResult    `json:"redeem_script,omitempty"`
+		WitnessScript      *ScriptPubKeyResult    `json:"witness_script,omitempty"`
+		Bip32Derivs        []DecodePsbtBip32Deriv `json:"bip32_derivs,omitempty"`
+		FinalScriptSig     *ScriptSig             `json:"final_scriptsig,omitempty"`
+		FinalScriptWitness []string               `json:"final_scriptwitness,omitempty"`
+		Unknown            map[string]string      `json:"unknown,omitempty"`
+	}
+	// DecodePsbtOutput models an output in the data from the decodepsbt command.
+	DecodePsbtOutput struct {
+		RedeemScript  *ScriptPubKeyResult    `json:"redeem_script,omitempty"`
+		WitnessScript *ScriptPubKeyResult    `json:"witness_script,omitempty"`
+		Bip32Derivs   []DecodePsbtBip32Deriv `json:"bip32_derivs,omitempty"`
+		Unknown       map[string]string      `json:"unknown,omitempty"`
+	}
+	// DecodePsbtResult models the data from the decodepsbt command.
+	DecodePsbtResult struct {
+		Tx      TxRawDecodeResult  `json:"tx"`
+		Unknown map[string]string  `json:"unknown"`
+		Inputs  []DecodePsbtInput  `json:"inputs"`
+		Outputs []DecodePsbtOutput `json:"outputs"`
+		Fee     *float64           `json:"fee,omitempty"`
+	}
+	// DecodePsbtWitnessUtxo models the output spent by a witness input in the data from the decodepsbt command.
+	DecodePsbtWitnessUtxo struct {
+		Amount       float64            `json:"amount"`
+		ScriptPubKey ScriptPubKeyResult `json:"scriptPubKey"`
+	}
+	// DumpWalletResult models the data from the dumpwallet command.
+	DumpWalletResult struct {
+		Filename string `json:"filename"`
+	}
+	// FinalizePsbtResult models the data from the finalizepsbt command.
+	FinalizePsbtResult struct {
+		Psbt     string `json:"psbt,omitempty"`
+		Hex      string `json:"hex,omitempty"`
+		Complete bool   `json:"complete"`
+	}
 	// GetTransactionDetailsResult models the details data from the gettransaction command. This models the "short" version of the ListTransactionsResult type, which excludes fields common to the transaction.  These common fields are instead part of the GetTransactionResult.
 	GetTransactionDetailsResult struct {
 		Account           string   `json:"account"`
@@ -10,6 +65,7 @@ type (
 		InvolvesWatchOnly bool     `json:"involveswatchonly,omitempty"`
 		Fee               *float64 `json:"fee,omitempty"`
 		Vout              uint32   `json:"vout"`
+		Label             string   `json:"label,omitempty"`
 	}
 	// GetTransactionResult models the data from the gettransaction command.
 	GetTransactionResult struct {
@@ -25,6 +81,8 @@ type (
 		TimeReceived    int64                         `json:"timereceived"`
 		Details         []GetTransactionDetailsResult `json:"details"`
 		Hex             string                        `json:"hex"`
+		Comment         string                        `json:"comment,omitempty"`
+		To              string                        `json:"to,omitempty"`
 	}
 	// InfoWalletResult models the data returned by the wallet server getinfo command.
 	InfoWalletResult struct {
@@ -67,6 +125,8 @@ type (
 		Vout              uint32   `json:"vout"`
 		WalletConflicts   []string `json:"walletconflicts"`
 		Comment           string   `json:"comment,omitempty"`
+		To                string   `json:"to,omitempty"`
+		Label             string   `json:"label,omitempty"`
 		OtherAccount      string   `json:"otheraccount,omitempty"`
 	}
 	// ListReceivedByAccountResult models the data from the listreceivedbyaccount command.
@@ -131,6 +191,17 @@ type (
 		Script       string   `json:"script,omitempty"`
 		SigsRequired int32    `json:"sigsrequired,omitempty"`
 	}
+	// WalletCreateFundedPsbtResult models the data from the walletcreatefundedpsbt command.
+	WalletCreateFundedPsbtResult struct {
+		Psbt      string  `json:"psbt"`
+		Fee       float64 `json:"fee"`
+		ChangePos int     `json:"changepos"`
+	}
+	// WalletProcessPsbtResult models the data from the walletprocesspsbt command.
+	WalletProcessPsbtResult struct {
+		Psbt     string `json:"psbt"`
+		Complete bool   `json:"complete"`
+	}
 	// GetBestBlockResult models the data from the getbestblock command.
 	GetBestBlockResult struct {
 		Hash   string `json:"hash"`
@@ -58,12 +58,24 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.DecodeScriptCmd",
 		ResType: "btcjson.DecodeScriptResult",
 	},
+	{
+		Method:  "dumptxoutset",
+		Handler: "DumpTxOutSet",
+		Cmd:     "*btcjson.DumpTxOutSetCmd",
+		ResType: "btcjson.TxOutSetSnapshotResult",
+	},
 	{
 		Method:  "estimatefee",
 		Handler: "EstimateFee",
 		Cmd:     "*btcjson.EstimateFeeCmd",
 		ResType: "float64",
 	},
+	{
+		Method:  "estimatepriority",
+		Handler: "EstimatePriority",
+		Cmd:     "*btcjson.EstimatePriorityCmd",
+		ResType: "float64",
+	},
 	{
 		Method:  "generate",
 		Handler: "Generate",
@@ -76,6 +88,36 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.GetAddedNodeInfoCmd",
 		ResType: "[]btcjson.GetAddedNodeInfoResultAddr",
 	},
+	{
+		Method:  "getaddressbalance",
+		Handler: "GetAddressBalance",
+		Cmd:     "*btcjson.GetAddressBalanceCmd",
+		ResType: "btcjson.GetAddressBalanceResult",
+	},
+	{
+		Method:  "getaddressdeltas",
+		Handler: "GetAddressDeltas",
+		Cmd:     "*btcjson.GetAddressDeltasCmd",
+		ResType: "[]btcjson.GetAddressDeltasResult",
+	},
+	{
+		Method:  "getaddressmempool",
+		Handler: "GetAddressMempool",
+		Cmd:     "*btcjson.GetAddressMempoolCmd",
+		ResType: "[]btcjson.GetAddressMempoolResult",
+	},
+	{
+		Method:  "getaddressutxos",
+		Handler: "GetAddressUtxos",
+		Cmd:     "*btcjson.GetAddressUtxosCmd",
+		ResType: "[]btcjson.GetAddressUtxosResult",
+	},
+	{
+		Method:  "getalgostats",
+		Handler: "GetAlgoStats",
+		Cmd:     "*btcjson.GetAlgoStatsCmd",
+		ResType: "btcjson.GetAlgoStatsResult",
+	},
 	{
 		Method:  "getbestblock",
 		Handler: "GetBestBlock",
@@ -136,6 +178,12 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.GetCFilterHeaderCmd",
 		ResType: "string",
 	},
+	{
+		Method:  "getchaintips",
+		Handler: "GetChainTips",
+		Cmd:     "*btcjson.GetChainTipsCmd",
+		ResType: "[]btcjson.GetChainTipsResult",
+	},
 	{
 		Method:  "getconnectioncount",
 		Handler: "GetConnectionCount",
@@ -178,6 +226,12 @@ var handlers = handlersT{
 		Cmd:     "*None",
 		ResType: "btcjson.InfoChainResult0",
 	},
+	{
+		Method:  "getmempoolentry",
+		Handler: "GetMempoolEntry",
+		Cmd:     "*btcjson.GetMempoolEntryCmd",
+		ResType: "btcjson.GetMempoolEntryResult",
+	},
 	{
 		Method:  "getmempoolinfo",
 		Handler: "GetMempoolInfo",
@@ -202,6 +256,12 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.GetNetworkHashPSCmd",
 		ResType: "[]btcjson.GetPeerInfoResult",
 	},
+	{
+		Method:  "getnetworkinfo",
+		Handler: "GetNetworkInfo",
+		Cmd:     "*None",
+		ResType: "btcjson.GetNetworkInfoResult",
+	},
 	{
 		Method:  "getpeerinfo",
 		Handler: "GetPeerInfo",
@@ -226,12 +286,36 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.GetTxOutCmd",
 		ResType: "string",
 	},
+	{
+		Method:  "gettxoutsetinfo",
+		Handler: "GetTxOutSetInfo",
+		Cmd:     "*None",
+		ResType: "btcjson.GetTxOutSetInfoResult",
+	},
+	{
+		Method:  "getworkerstats",
+		Handler: "GetWorkerStats",
+		Cmd:     "*None",
+		ResType: "[]btcjson.GetWorkerStatsResult",
+	},
 	{
 		Method:  "help",
 		Handler: "Help",
 		Cmd:     "*btcjson.HelpCmd",
 		ResType: "string",
 	},
+	{
+		Method:  "invalidateblock",
+		Handler: "InvalidateBlock",
+		Cmd:     "*btcjson.InvalidateBlockCmd",
+		ResType: "None",
+	},
+	{
+		Method:  "loadtxoutset",
+		Handler: "LoadTxOutSet",
+		Cmd:     "*btcjson.LoadTxOutSetCmd",
+		ResType: "btcjson.TxOutSetSnapshotResult",
+	},
 	{
 		Method:  "node",
 		Handler: "Node",
@@ -244,6 +328,24 @@ var handlers = handlersT{
 		Cmd:     "*None",
 		ResType: "None",
 	},
+	{
+		Method:  "preciousblock",
+		Handler: "PreciousBlock",
+		Cmd:     "*btcjson.PreciousBlockCmd",
+		ResType: "None",
+	},
+	{
+		Method:  "reconsiderblock",
+		Handler: "ReconsiderBlock",
+		Cmd:     "*btcjson.ReconsiderBlockCmd",
+		ResType: "None",
+	},
+	{
+		Method:  "savemempool",
+		Handler: "SaveMempool",
+		Cmd:     "*None",
+		ResType: "None",
+	},
 	{
 		Method:  "searchrawtransactions",
 		Handler: "SearchRawTransactions",
@@ -1,6 +1,7 @@
 package chainrpc
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/base64"
 	"encoding/hex"
@@ -9,6 +10,9 @@ import (
 	qu "github.com/p9c/pod/pkg/util/quit"
 	"math/big"
 	"net"
+	"os"
+	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -21,10 +25,12 @@ import (
 	chaincfg "github.com/p9c/pod/pkg/chain/config"
 	"github.com/p9c/pod/pkg/chain/fork"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	indexers "github.com/p9c/pod/pkg/chain/index"
 	txscript "github.com/p9c/pod/pkg/chain/tx/script"
 	"github.com/p9c/pod/pkg/chain/wire"
 	ec "github.com/p9c/pod/pkg/coding/elliptic"
 	database "github.com/p9c/pod/pkg/db"
+	"github.com/p9c/pod/pkg/pod"
 	"github.com/p9c/pod/pkg/rpc/btcjson"
 	"github.com/p9c/pod/pkg/util"
 	"github.com/p9c/pod/pkg/util/interrupt"
@@ -312,6 +318,76 @@ func HandleDecodeScript(
 	return reply, nil
 }
 
+// HandleDumpTxOutSet implements the dumptxoutset command.
+func HandleDumpTxOutSet(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.DumpTxOutSetCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("dumptxoutset")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	path := TxOutSetPath(s, c.Path)
+	if _, err = os.Stat(path); err == nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: path + " already exists",
+		}
+	}
+	// The snapshot is written to a temporary file that is renamed once complete so a partial snapshot is never left at
+	// the path.
+	tmpPath := path + ".incomplete"
+	var f *os.File
+	if f, err = os.Create(tmpPath); err != nil {
+		return nil, InternalRPCError(err.Error(), "Unable to create utxo set snapshot")
+	}
+	w := bufio.NewWriter(f)
+	info, err := s.Cfg.Chain.DumpTxOutSet(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if e := f.Close(); err == nil {
+		err = e
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		if e := os.Remove(tmpPath); e != nil && !os.IsNotExist(e) {
+			Error(e)
+		}
+		return nil, InternalRPCError(err.Error(), "Unable to write utxo set snapshot")
+	}
+	return TxOutSetSnapshotResult(path, info), nil
+}
+
+// TxOutSetPath returns the path of a utxo set snapshot file, which if relative is in the data directory of the network.
+func TxOutSetPath(s *Server, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(*s.Config.DataDir, s.Cfg.ChainParams.Name, path)
+}
+
+// TxOutSetSnapshotResult returns the reply to the dumptxoutset and loadtxoutset commands.
+func TxOutSetSnapshotResult(path string, info *blockchain.TxOutSetInfo) *btcjson.TxOutSetSnapshotResult {
+	return &btcjson.TxOutSetSnapshotResult{
+		Path:           path,
+		BaseHash:       info.BestBlock.String(),
+		BaseHeight:     info.Height,
+		Coins:          int64(info.TxOuts),
+		HashSerialized: info.HashSerialized.String(),
+	}
+}
+
 // HandleEstimateFee handles estimatefee commands.
 func HandleEstimateFee(
 	s *Server,
@@ -349,6 +425,29 @@ func HandleEstimateFee(
 	return float64(feeRate), nil
 }
 
+// HandleEstimatePriority handles estimatepriority commands.
+func HandleEstimatePriority(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.EstimatePriorityCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("estimatepriority")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	if c.NumBlocks <= 0 {
+		return -1.0, errors.New("parameter NumBlocks must be positive")
+	}
+	return s.Cfg.TxMemPool.EstimatePriority(c.NumBlocks), nil
+}
+
 // HandleGenerate handles generate commands.
 func HandleGenerate(
 	s *Server,
@@ -505,6 +604,205 @@ func HandleGetAddedNodeInfo(
 	return results, nil
 }
 
+// AddrUtxoIndexAddresses returns the address utxo index and the passed addresses decoded, or an error if the index is
+// not enabled or an address is invalid.
+func AddrUtxoIndexAddresses(s *Server, addresses []string) (*indexers.AddrUtxoIndex, []util.Address, error) {
+	if s.Cfg.AddrUtxoIndex == nil {
+		return nil, nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Address utxo index must be enabled (--addrutxoindex)",
+		}
+	}
+	addrs := make([]util.Address, 0, len(addresses))
+	for _, address := range addresses {
+		addr, err := util.DecodeAddress(address, s.Cfg.ChainParams)
+		if err != nil {
+			Error(err)
+			return nil, nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidAddressOrKey,
+				Message: "Invalid address or key: " + err.Error(),
+			}
+		}
+		addrs = append(addrs, addr)
+	}
+	return s.Cfg.AddrUtxoIndex, addrs, nil
+}
+
+// HandleGetAddressBalance implements the getaddressbalance command.
+func HandleGetAddressBalance(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	c := cmd.(*btcjson.GetAddressBalanceCmd)
+	idx, addrs, err := AddrUtxoIndexAddresses(s, c.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	var reply btcjson.GetAddressBalanceResult
+	for _, addr := range addrs {
+		balance, err := idx.Balance(addr)
+		if err != nil {
+			return nil, InternalRPCError(err.Error(), "Unable to fetch address balance")
+		}
+		reply.Balance += balance.Balance
+		reply.Received += balance.Received
+	}
+	return reply, nil
+}
+
+// HandleGetAddressDeltas implements the getaddressdeltas command.
+func HandleGetAddressDeltas(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	c := cmd.(*btcjson.GetAddressDeltasCmd)
+	idx, addrs, err := AddrUtxoIndexAddresses(s, c.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	var start, end int32
+	if c.Start != nil {
+		start = *c.Start
+	}
+	if c.End != nil {
+		end = *c.End
+	}
+	if start < 0 || end < 0 || (end > 0 && end < start) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Start and end must be positive heights with end not below start",
+		}
+	}
+	reply := []btcjson.GetAddressDeltasResult{}
+	for i, addr := range addrs {
+		deltas, err := idx.Deltas(addr, start, end)
+		if err != nil {
+			return nil, InternalRPCError(err.Error(), "Unable to fetch address deltas")
+		}
+		for _, delta := range deltas {
+			reply = append(reply, btcjson.GetAddressDeltasResult{
+				Address:    c.Addresses[i],
+				TxID:       delta.TxHash.String(),
+				Index:      delta.Index,
+				BlockIndex: delta.BlockIndex,
+				Height:     delta.Height,
+				Satoshis:   delta.Amount,
+			})
+		}
+	}
+	// The deltas of each address are in chain order, those of several addresses are merged into it.
+	sort.SliceStable(reply, func(i, j int) bool {
+		if reply[i].Height != reply[j].Height {
+			return reply[i].Height < reply[j].Height
+		}
+		return reply[i].BlockIndex < reply[j].BlockIndex
+	})
+	return reply, nil
+}
+
+// HandleGetAddressMempool implements the getaddressmempool command.
+func HandleGetAddressMempool(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	c := cmd.(*btcjson.GetAddressMempoolCmd)
+	idx, addrs, err := AddrUtxoIndexAddresses(s, c.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	reply := []btcjson.GetAddressMempoolResult{}
+	for i, addr := range addrs {
+		for _, delta := range idx.UnconfirmedDeltas(addr) {
+			result := btcjson.GetAddressMempoolResult{
+				Address:   c.Addresses[i],
+				TxID:      delta.TxHash.String(),
+				Index:     delta.Index,
+				Satoshis:  delta.Amount,
+				Timestamp: delta.Time.Unix(),
+			}
+			if delta.PrevOut != nil {
+				result.PrevTxID = delta.PrevOut.Hash.String()
+				result.PrevOut = &delta.PrevOut.Index
+			}
+			reply = append(reply, result)
+		}
+	}
+	sort.SliceStable(reply, func(i, j int) bool {
+		return reply[i].Timestamp < reply[j].Timestamp
+	})
+	return reply, nil
+}
+
+// HandleGetAddressUtxos implements the getaddressutxos command.
+func HandleGetAddressUtxos(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	c := cmd.(*btcjson.GetAddressUtxosCmd)
+	idx, addrs, err := AddrUtxoIndexAddresses(s, c.Addresses)
+	if err != nil {
+		return nil, err
+	}
+	reply := []btcjson.GetAddressUtxosResult{}
+	for i, addr := range addrs {
+		utxos, err := idx.Utxos(addr)
+		if err != nil {
+			return nil, InternalRPCError(err.Error(), "Unable to fetch address unspent outputs")
+		}
+		for _, utxo := range utxos {
+			reply = append(reply, btcjson.GetAddressUtxosResult{
+				Address:     c.Addresses[i],
+				TxID:        utxo.OutPoint.Hash.String(),
+				OutputIndex: utxo.OutPoint.Index,
+				Script:      hex.EncodeToString(utxo.PkScript),
+				Satoshis:    utxo.Amount,
+				Height:      utxo.Height,
+			})
+		}
+	}
+	return reply, nil
+}
+
+// HandleGetAlgoStats implements the getalgostats command.
+func HandleGetAlgoStats(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	c := cmd.(*btcjson.GetAlgoStatsCmd)
+	if s.Cfg.AlgoStatsIndex == nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Algo stats index must be enabled (--algostatsindex)",
+		}
+	}
+	best := s.Cfg.Chain.BestSnapshot()
+	if c.From < 0 || c.To < c.From || c.To > best.Height {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCOutOfRange,
+			Message: fmt.Sprintf("Range must be within 0 and %d with from not above to", best.Height),
+		}
+	}
+	stats, err := s.Cfg.AlgoStatsIndex.Stats(c.From, c.To)
+	if err != nil {
+		return nil, InternalRPCError(err.Error(), "Unable to compute algo stats")
+	}
+	blocks := c.To - c.From + 1
+	reply := btcjson.GetAlgoStatsResult{
+		From:   stats.From,
+		To:     stats.To,
+		Blocks: blocks,
+		Span:   stats.Span,
+		Algos:  make([]btcjson.AlgoStatsResult, 0, len(stats.Algos)),
+	}
+	for _, algo := range stats.Algos {
+		result := btcjson.AlgoStatsResult{
+			Algo:           algo.Algo,
+			Version:        algo.Version,
+			Blocks:         int32(algo.Blocks),
+			Share:          float64(algo.Blocks) / float64(blocks),
+			TargetInterval: algo.TargetInterval,
+			Bits:           strconv.FormatInt(int64(algo.Bits), 16),
+			Difficulty:     GetDifficultyRatio(algo.Bits, s.Cfg.ChainParams, algo.Version),
+			ChainWork:      algo.CumulativeWork.Text(16),
+		}
+		if algo.Intervals > 0 {
+			result.AvgInterval = float64(algo.IntervalSum) / float64(algo.Intervals)
+		}
+		// The work of a block is the expected number of hashes needed to find it.
+		if stats.Span > 0 {
+			work, _ := new(big.Float).SetInt(algo.Work).Float64()
+			result.Hashrate = work / float64(stats.Span)
+		}
+		reply.Algos = append(reply.Algos, result)
+	}
+	return reply, nil
+}
+
 // HandleGetBestBlock implements the getbestblock command.
 func HandleGetBestBlock(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
 	// All other "get block" commands give either the height, the hash, or both but require the block SHA. This gets
@@ -557,6 +855,15 @@ func HandleGetBlock(s *Server, cmd interface{}, closeChan qu.C) (interface{}, er
 	)
 	if err != nil {
 		Error(err)
+		// A block the chain knows of that is not stored was deleted by pruning.
+		if s.Cfg.Chain.IsPruned() {
+			if _, e := s.Cfg.Chain.BlockHeightByHash(hash); e == nil {
+				return nil, &btcjson.RPCError{
+					Code:    btcjson.ErrRPCMisc,
+					Message: "Block not available (pruned data)",
+				}
+			}
+		}
 		return nil, &btcjson.RPCError{
 			Code:    btcjson.ErrRPCBlockNotFound,
 			Message: "Block not found",
@@ -1240,6 +1547,21 @@ func HandleGetCFilterHeader(s *Server, cmd interface{}, closeChan qu.C) (interfa
 	return hash.String(), nil
 }
 
+// HandleGetChainTips implements the getchaintips command.
+func HandleGetChainTips(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	tips := s.Cfg.Chain.ChainTips()
+	results := make([]btcjson.GetChainTipsResult, len(tips))
+	for i := range tips {
+		results[i] = btcjson.GetChainTipsResult{
+			Height:    tips[i].Height,
+			Hash:      tips[i].Hash.String(),
+			BranchLen: tips[i].BranchLen,
+			Status:    tips[i].Status,
+		}
+	}
+	return results, nil
+}
+
 // HandleGetConnectionCount implements the getconnectioncount command.
 func HandleGetConnectionCount(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
 	return s.Cfg.ConnMgr.ConnectedCount(), nil
@@ -1534,6 +1856,38 @@ func HandleGetInfo(
 	return ret, nil
 }
 
+// HandleGetMempoolEntry implements the getmempoolentry command.
+func HandleGetMempoolEntry(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.GetMempoolEntryCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("getmempoolentry")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	txHash, err := chainhash.NewHashFromStr(c.TxID)
+	if err != nil {
+		Error(err)
+		return nil, DecodeHexError(c.TxID)
+	}
+	entry, err := s.Cfg.TxMemPool.MempoolEntry(txHash)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCNoTxInfo,
+			Message: "Transaction not in mempool",
+		}
+	}
+	return entry, nil
+}
+
 // HandleGetMempoolInfo implements the getmempoolinfo command.
 func HandleGetMempoolInfo(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
 	mempoolTxns := s.Cfg.TxMemPool.TxDescs()
@@ -1542,8 +1896,11 @@ func HandleGetMempoolInfo(s *Server, cmd interface{}, closeChan qu.C) (interface
 		numBytes += int64(txD.Tx.MsgTx().SerializeSize())
 	}
 	ret := &btcjson.GetMempoolInfoResult{
-		Size:  int64(len(mempoolTxns)),
-		Bytes: numBytes,
+		Size:          int64(len(mempoolTxns)),
+		Bytes:         numBytes,
+		MaxMempool:    s.Cfg.TxMemPool.MaxSize(),
+		MempoolMinFee: s.Cfg.TxMemPool.MinFee().ToDUO(),
+		MinRelayTxFee: s.StateCfg.ActiveMinRelayTxFee.ToDUO(),
 	}
 	return ret, nil
 }
@@ -1803,6 +2160,98 @@ func HandleGetNetworkHashPS(s *Server, cmd interface{}, closeChan qu.C) (interfa
 	return hashesPerSec.Int64(), nil
 }
 
+// reachableNetworks returns whether the node makes outbound connections to ipv4, ipv6 and onion addresses under the
+// passed configuration. A node that only connects to the configured peers reaches only the networks of those peers, and
+// onion addresses are only reached with tor enabled and a proxy to reach them through.
+func reachableNetworks(cfg *pod.Config) (ipv4, ipv6, onion bool) {
+	torReachable := *cfg.Onion && (*cfg.Proxy != "" || *cfg.OnionProxy != "")
+	if len(*cfg.ConnectPeers) == 0 {
+		return true, true, torReachable
+	}
+	for _, addr := range *cfg.ConnectPeers {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		ip := net.ParseIP(host)
+		switch {
+		case strings.HasSuffix(host, ".onion"):
+			onion = onion || torReachable
+		case ip == nil:
+			// A host name may resolve to addresses of either network.
+			ipv4, ipv6 = true, true
+		case ip.To4() != nil:
+			ipv4 = true
+		default:
+			ipv6 = true
+		}
+	}
+	return
+}
+
+// HandleGetNetworkInfo implements the getnetworkinfo command.
+func HandleGetNetworkInfo(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	subVersion := fmt.Sprintf("%s:%s", UserAgentName, UserAgentVersion)
+	if comments := *s.Config.UserAgentComments; len(comments) != 0 {
+		subVersion = fmt.Sprintf("%s(%s)", subVersion, strings.Join(comments, "; "))
+	}
+	// Onion addresses go through the onion proxy if one is configured and the regular proxy otherwise.
+	onionProxy := *s.Config.Proxy
+	if *s.Config.OnionProxy != "" {
+		onionProxy = *s.Config.OnionProxy
+	}
+	ipv4, ipv6, onion := reachableNetworks(s.Config)
+	networks := []btcjson.NetworksResult{
+		{
+			Name:      "ipv4",
+			Limited:   !ipv4,
+			Reachable: ipv4,
+			Proxy:     *s.Config.Proxy,
+		},
+		{
+			Name:      "ipv6",
+			Limited:   !ipv6,
+			Reachable: ipv6,
+			Proxy:     *s.Config.Proxy,
+		},
+		{
+			Name:                      "onion",
+			Limited:                   !onion,
+			Reachable:                 onion,
+			Proxy:                     onionProxy,
+			ProxyRandomizeCredentials: *s.Config.TorIsolation,
+		},
+	}
+	localAddrs := s.Cfg.ConnMgr.LocalAddresses()
+	localAddresses := make([]btcjson.LocalAddressesResult, len(localAddrs))
+	for i, la := range localAddrs {
+		localAddresses[i] = btcjson.LocalAddressesResult{
+			Address: la.NA.IP.String(),
+			Port:    la.NA.Port,
+			Score:   int32(la.Score),
+		}
+	}
+	ret := &btcjson.GetNetworkInfoResult{
+		Version: int32(
+			1000000*version.AppMajor +
+				10000*version.AppMinor +
+				100*version.AppPatch,
+		),
+		SubVersion:      wire.DefaultUserAgent + subVersion + "/",
+		ProtocolVersion: int32(MaxProtocolVersion),
+		LocalServices:   fmt.Sprintf("%016x", uint64(s.Cfg.ConnMgr.Services())),
+		LocalRelay:      !*s.Config.BlocksOnly,
+		TimeOffset:      int64(s.Cfg.TimeSource.Offset().Seconds()),
+		Connections:     s.Cfg.ConnMgr.ConnectedCount(),
+		NetworkActive:   true,
+		Networks:        networks,
+		RelayFee:        s.StateCfg.ActiveMinRelayTxFee.ToDUO(),
+		IncrementalFee:  s.StateCfg.ActiveMinRelayTxFee.ToDUO(),
+		LocalAddresses:  localAddresses,
+	}
+	return ret, nil
+}
+
 // HandleGetPeerInfo implements the getpeerinfo command.
 func HandleGetPeerInfo(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
 	peers := s.Cfg.ConnMgr.ConnectedPeers()
@@ -2098,6 +2547,35 @@ func HandleGetTxOut(s *Server, cmd interface{}, closeChan qu.C) (interface{}, er
 	return txOutReply, nil
 }
 
+// HandleGetTxOutSetInfo implements the gettxoutsetinfo command.
+func HandleGetTxOutSetInfo(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	info, err := s.Cfg.Chain.TxOutSetInfo()
+	if err != nil {
+		return nil, InternalRPCError(err.Error(), "Unable to read the utxo set")
+	}
+	return &btcjson.GetTxOutSetInfoResult{
+		Height:          info.Height,
+		BestBlock:       info.BestBlock.String(),
+		Transactions:    int64(info.Transactions),
+		TxOuts:          int64(info.TxOuts),
+		BytesSerialized: int64(info.BytesSerialized),
+		HashSerialized:  info.HashSerialized.String(),
+		TotalAmount:     info.TotalAmount.ToDUO(),
+	}, nil
+}
+
+// HandleGetWorkerStats implements the getworkerstats command.
+func HandleGetWorkerStats(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	workerStats, ok := s.Cfg.WorkerStats.Load().(func() []btcjson.GetWorkerStatsResult)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "the kopach miner controller is not running",
+		}
+	}
+	return workerStats(), nil
+}
+
 // HandleHelp implements the help command.
 func HandleHelp(s *Server, cmd interface{}, closeChan qu.C) (
 	interface{}, error,
@@ -2151,6 +2629,86 @@ func HandleHelp(s *Server, cmd interface{}, closeChan qu.C) (
 	return help, nil
 }
 
+// HandleInvalidateBlock implements the invalidateblock command.
+func HandleInvalidateBlock(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.InvalidateBlockCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("invalidateblock")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	if err != nil {
+		Error(err)
+		return nil, DecodeHexError(c.BlockHash)
+	}
+	if !s.Cfg.Chain.Index.HaveBlock(hash) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCBlockNotFound,
+			Message: "Block not found",
+		}
+	}
+	if err = s.Cfg.Chain.InvalidateBlock(hash); err != nil {
+		return nil, InternalRPCError(err.Error(), "Failed to invalidate block "+c.BlockHash)
+	}
+	return nil, nil
+}
+
+// HandleLoadTxOutSet implements the loadtxoutset command.
+func HandleLoadTxOutSet(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.LoadTxOutSetCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("loadtxoutset")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	var expected *chainhash.Hash
+	if c.Hash != nil {
+		if expected, err = chainhash.NewHashFromStr(*c.Hash); err != nil {
+			return nil, DecodeHexError(*c.Hash)
+		}
+	}
+	path := TxOutSetPath(s, c.Path)
+	var f *os.File
+	if f, err = os.Open(path); err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: err.Error(),
+		}
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			Error(err)
+		}
+	}()
+	info, err := s.Cfg.Chain.LoadTxOutSet(bufio.NewReader(f), expected)
+	if err != nil {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCMisc,
+			Message: "Unable to load utxo set snapshot: " + err.Error(),
+		}
+	}
+	return TxOutSetSnapshotResult(path, info), nil
+}
+
 // HandleNode handles node commands.
 func HandleNode(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
 	var msg string
@@ -2268,6 +2826,82 @@ func HandlePing(s *Server, cmd interface{}, closeChan qu.C) (
 	return nil, nil
 }
 
+// HandlePreciousBlock implements the preciousblock command.
+func HandlePreciousBlock(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.PreciousBlockCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("preciousblock")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	if err != nil {
+		Error(err)
+		return nil, DecodeHexError(c.BlockHash)
+	}
+	if !s.Cfg.Chain.Index.HaveBlock(hash) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCBlockNotFound,
+			Message: "Block not found",
+		}
+	}
+	if err = s.Cfg.Chain.PreciousBlock(hash); err != nil {
+		return nil, InternalRPCError(err.Error(), "Failed to prioritise block "+c.BlockHash)
+	}
+	return nil, nil
+}
+
+// HandleReconsiderBlock implements the reconsiderblock command.
+func HandleReconsiderBlock(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	var msg string
+	var err error
+	c, ok := cmd.(*btcjson.ReconsiderBlockCmd)
+	if !ok {
+		var h string
+		h, err = s.HelpCacher.RPCMethodHelp("reconsiderblock")
+		if err != nil {
+			msg = err.Error() + "\n\n"
+		}
+		msg += h
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: msg,
+		}
+	}
+	hash, err := chainhash.NewHashFromStr(c.BlockHash)
+	if err != nil {
+		Error(err)
+		return nil, DecodeHexError(c.BlockHash)
+	}
+	if !s.Cfg.Chain.Index.HaveBlock(hash) {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCBlockNotFound,
+			Message: "Block not found",
+		}
+	}
+	if err = s.Cfg.Chain.ReconsiderBlock(hash); err != nil {
+		return nil, InternalRPCError(err.Error(), "Failed to reconsider block "+c.BlockHash)
+	}
+	return nil, nil
+}
+
+// HandleSaveMempool implements the savemempool command.
+func HandleSaveMempool(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
+	if err := s.Cfg.TxMemPool.SaveFile(s.Cfg.MempoolFile); err != nil {
+		return nil, InternalRPCError(err.Error(), "Unable to dump mempool to disk")
+	}
+	return nil, nil
+}
+
 // HandleSearchRawTransactions implements the searchrawtransactions command.
 // TODO: simplify this, break it up
 func HandleSearchRawTransactions(s *Server, cmd interface{}, closeChan qu.C) (interface{}, error) {
@@ -9,6 +9,7 @@ import (
 	netsync "github.com/p9c/pod/pkg/chain/sync"
 	"github.com/p9c/pod/pkg/chain/wire"
 	"github.com/p9c/pod/pkg/comm/peer"
+	"github.com/p9c/pod/pkg/comm/peer/addrmgr"
 	"github.com/p9c/pod/pkg/util"
 )
 
@@ -199,6 +200,20 @@ func (cm *ConnManager) RelayTransactions(txns []*mempool.TxDesc) {
 	cm.server.RelayTransactions(txns)
 }
 
+// Services returns the service flags the server advertises to its peers.
+//
+// This function is safe for concurrent access and is part of the RPCServerConnManager interface implementation.
+func (cm *ConnManager) Services() wire.ServiceFlag {
+	return cm.server.Services
+}
+
+// LocalAddresses returns the local addresses the server advertises to its peers.
+//
+// This function is safe for concurrent access and is part of the RPCServerConnManager interface implementation.
+func (cm *ConnManager) LocalAddresses() []addrmgr.LocalAddress {
+	return cm.server.AddrManager.LocalAddresses()
+}
+
 // SyncManager provides a block manager for use with the RPC server and implements the RPCServerSyncManager interface.
 type SyncManager struct {
 	server  *Node
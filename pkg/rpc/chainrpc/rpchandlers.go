@@ -72,11 +72,21 @@ type (
 		Res *btcjson.DecodeScriptResult
 		Err error
 	}
+	// DumpTxOutSetRes is the result from a call to DumpTxOutSet
+	DumpTxOutSetRes struct {
+		Res *btcjson.TxOutSetSnapshotResult
+		Err error
+	}
 	// EstimateFeeRes is the result from a call to EstimateFee
 	EstimateFeeRes struct {
 		Res *float64
 		Err error
 	}
+	// EstimatePriorityRes is the result from a call to EstimatePriority
+	EstimatePriorityRes struct {
+		Res *float64
+		Err error
+	}
 	// GenerateRes is the result from a call to Generate
 	GenerateRes struct {
 		Res *[]string
@@ -87,6 +97,31 @@ type (
 		Res *[]btcjson.GetAddedNodeInfoResultAddr
 		Err error
 	}
+	// GetAddressBalanceRes is the result from a call to GetAddressBalance
+	GetAddressBalanceRes struct {
+		Res *btcjson.GetAddressBalanceResult
+		Err error
+	}
+	// GetAddressDeltasRes is the result from a call to GetAddressDeltas
+	GetAddressDeltasRes struct {
+		Res *[]btcjson.GetAddressDeltasResult
+		Err error
+	}
+	// GetAddressMempoolRes is the result from a call to GetAddressMempool
+	GetAddressMempoolRes struct {
+		Res *[]btcjson.GetAddressMempoolResult
+		Err error
+	}
+	// GetAddressUtxosRes is the result from a call to GetAddressUtxos
+	GetAddressUtxosRes struct {
+		Res *[]btcjson.GetAddressUtxosResult
+		Err error
+	}
+	// GetAlgoStatsRes is the result from a call to GetAlgoStats
+	GetAlgoStatsRes struct {
+		Res *btcjson.GetAlgoStatsResult
+		Err error
+	}
 	// GetBestBlockRes is the result from a call to GetBestBlock
 	GetBestBlockRes struct {
 		Res *btcjson.GetBestBlockResult
@@ -137,6 +172,11 @@ type (
 		Res *string
 		Err error
 	}
+	// GetChainTipsRes is the result from a call to GetChainTips
+	GetChainTipsRes struct {
+		Res *[]btcjson.GetChainTipsResult
+		Err error
+	}
 	// GetConnectionCountRes is the result from a call to GetConnectionCount
 	GetConnectionCountRes struct {
 		Res *int32
@@ -172,6 +212,11 @@ type (
 		Res *btcjson.InfoChainResult0
 		Err error
 	}
+	// GetMempoolEntryRes is the result from a call to GetMempoolEntry
+	GetMempoolEntryRes struct {
+		Res *btcjson.GetMempoolEntryResult
+		Err error
+	}
 	// GetMempoolInfoRes is the result from a call to GetMempoolInfo
 	GetMempoolInfoRes struct {
 		Res *btcjson.GetMempoolInfoResult
@@ -192,6 +237,11 @@ type (
 		Res *[]btcjson.GetPeerInfoResult
 		Err error
 	}
+	// GetNetworkInfoRes is the result from a call to GetNetworkInfo
+	GetNetworkInfoRes struct {
+		Res *btcjson.GetNetworkInfoResult
+		Err error
+	}
 	// GetPeerInfoRes is the result from a call to GetPeerInfo
 	GetPeerInfoRes struct {
 		Res *[]btcjson.GetPeerInfoResult
@@ -212,11 +262,31 @@ type (
 		Res *string
 		Err error
 	}
+	// GetTxOutSetInfoRes is the result from a call to GetTxOutSetInfo
+	GetTxOutSetInfoRes struct {
+		Res *btcjson.GetTxOutSetInfoResult
+		Err error
+	}
+	// GetWorkerStatsRes is the result from a call to GetWorkerStats
+	GetWorkerStatsRes struct {
+		Res *[]btcjson.GetWorkerStatsResult
+		Err error
+	}
 	// HelpRes is the result from a call to Help
 	HelpRes struct {
 		Res *string
 		Err error
 	}
+	// InvalidateBlockRes is the result from a call to InvalidateBlock
+	InvalidateBlockRes struct {
+		Res *None
+		Err error
+	}
+	// LoadTxOutSetRes is the result from a call to LoadTxOutSet
+	LoadTxOutSetRes struct {
+		Res *btcjson.TxOutSetSnapshotResult
+		Err error
+	}
 	// NodeRes is the result from a call to Node
 	NodeRes struct {
 		Res *None
@@ -227,6 +297,16 @@ type (
 		Res *None
 		Err error
 	}
+	// PreciousBlockRes is the result from a call to PreciousBlock
+	PreciousBlockRes struct {
+		Res *None
+		Err error
+	}
+	// ReconsiderBlockRes is the result from a call to ReconsiderBlock
+	ReconsiderBlockRes struct {
+		Res *None
+		Err error
+	}
 	// ResetChainRes is the result from a call to ResetChain
 	ResetChainRes struct {
 		Res *None
@@ -237,6 +317,11 @@ type (
 		Res *None
 		Err error
 	}
+	// SaveMempoolRes is the result from a call to SaveMempool
+	SaveMempoolRes struct {
+		Res *None
+		Err error
+	}
 	// SearchRawTransactionsRes is the result from a call to SearchRawTransactions
 	SearchRawTransactionsRes struct {
 		Res *[]btcjson.SearchRawTransactionsResult
@@ -315,15 +400,36 @@ var RPCHandlersBeforeInit = map[string]CommandHandler{
 	"decodescript": {
 		Fn: HandleDecodeScript, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan DecodeScriptRes)} }},
+	"dumptxoutset": {
+		Fn: HandleDumpTxOutSet, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan DumpTxOutSetRes)} }},
 	"estimatefee": {
 		Fn: HandleEstimateFee, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan EstimateFeeRes)} }},
+	"estimatepriority": {
+		Fn: HandleEstimatePriority, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan EstimatePriorityRes)} }},
 	"generate": {
 		Fn: HandleGenerate, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GenerateRes)} }},
 	"getaddednodeinfo": {
 		Fn: HandleGetAddedNodeInfo, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetAddedNodeInfoRes)} }},
+	"getaddressbalance": {
+		Fn: HandleGetAddressBalance, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetAddressBalanceRes)} }},
+	"getaddressdeltas": {
+		Fn: HandleGetAddressDeltas, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetAddressDeltasRes)} }},
+	"getaddressmempool": {
+		Fn: HandleGetAddressMempool, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetAddressMempoolRes)} }},
+	"getaddressutxos": {
+		Fn: HandleGetAddressUtxos, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetAddressUtxosRes)} }},
+	"getalgostats": {
+		Fn: HandleGetAlgoStats, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetAlgoStatsRes)} }},
 	"getbestblock": {
 		Fn: HandleGetBestBlock, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetBestBlockRes)} }},
@@ -354,6 +460,9 @@ var RPCHandlersBeforeInit = map[string]CommandHandler{
 	"getcfilterheader": {
 		Fn: HandleGetCFilterHeader, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetCFilterHeaderRes)} }},
+	"getchaintips": {
+		Fn: HandleGetChainTips, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetChainTipsRes)} }},
 	"getconnectioncount": {
 		Fn: HandleGetConnectionCount, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetConnectionCountRes)} }},
@@ -375,6 +484,9 @@ var RPCHandlersBeforeInit = map[string]CommandHandler{
 	"getinfo": {
 		Fn: HandleGetInfo, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetInfoRes)} }},
+	"getmempoolentry": {
+		Fn: HandleGetMempoolEntry, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetMempoolEntryRes)} }},
 	"getmempoolinfo": {
 		Fn: HandleGetMempoolInfo, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetMempoolInfoRes)} }},
@@ -387,6 +499,9 @@ var RPCHandlersBeforeInit = map[string]CommandHandler{
 	"getnetworkhashps": {
 		Fn: HandleGetNetworkHashPS, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetNetworkHashPSRes)} }},
+	"getnetworkinfo": {
+		Fn: HandleGetNetworkInfo, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetNetworkInfoRes)} }},
 	"getpeerinfo": {
 		Fn: HandleGetPeerInfo, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetPeerInfoRes)} }},
@@ -399,21 +514,42 @@ var RPCHandlersBeforeInit = map[string]CommandHandler{
 	"gettxout": {
 		Fn: HandleGetTxOut, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetTxOutRes)} }},
+	"gettxoutsetinfo": {
+		Fn: HandleGetTxOutSetInfo, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetTxOutSetInfoRes)} }},
+	"getworkerstats": {
+		Fn: HandleGetWorkerStats, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan GetWorkerStatsRes)} }},
 	"help": {
 		Fn: HandleHelp, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan HelpRes)} }},
+	"invalidateblock": {
+		Fn: HandleInvalidateBlock, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan InvalidateBlockRes)} }},
+	"loadtxoutset": {
+		Fn: HandleLoadTxOutSet, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan LoadTxOutSetRes)} }},
 	"node": {
 		Fn: HandleNode, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan NodeRes)} }},
 	"ping": {
 		Fn: HandlePing, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan PingRes)} }},
+	"preciousblock": {
+		Fn: HandlePreciousBlock, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan PreciousBlockRes)} }},
+	"reconsiderblock": {
+		Fn: HandleReconsiderBlock, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan ReconsiderBlockRes)} }},
 	"resetchain": {
 		Fn: HandleResetChain, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan ResetChainRes)} }},
 	"restart": {
 		Fn: HandleRestart, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan RestartRes)} }},
+	"savemempool": {
+		Fn: HandleSaveMempool, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan SaveMempoolRes)} }},
 	"searchrawtransactions": {
 		Fn: HandleSearchRawTransactions, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan SearchRawTransactionsRes)} }},
@@ -616,6 +752,47 @@ func (a API) DecodeScriptWait(cmd *btcjson.DecodeScriptCmd) (out *btcjson.Decode
 	return
 }
 
+// DumpTxOutSet calls the method with the given parameters
+func (a API) DumpTxOutSet(cmd *btcjson.DumpTxOutSetCmd) (err error) {
+	RPCHandlers["dumptxoutset"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// DumpTxOutSetCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) DumpTxOutSetCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan DumpTxOutSetRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// DumpTxOutSetGetRes returns a pointer to the value in the Result field
+func (a API) DumpTxOutSetGetRes() (out *btcjson.TxOutSetSnapshotResult, err error) {
+	out, _ = a.Result.(*btcjson.TxOutSetSnapshotResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// DumpTxOutSetWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) DumpTxOutSetWait(cmd *btcjson.DumpTxOutSetCmd) (out *btcjson.TxOutSetSnapshotResult, err error) {
+	RPCHandlers["dumptxoutset"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan DumpTxOutSetRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // EstimateFee calls the method with the given parameters
 func (a API) EstimateFee(cmd *btcjson.EstimateFeeCmd) (err error) {
 	RPCHandlers["estimatefee"].Call <- API{a.Ch, cmd, nil}
@@ -657,6 +834,47 @@ func (a API) EstimateFeeWait(cmd *btcjson.EstimateFeeCmd) (out *float64, err err
 	return
 }
 
+// EstimatePriority calls the method with the given parameters
+func (a API) EstimatePriority(cmd *btcjson.EstimatePriorityCmd) (err error) {
+	RPCHandlers["estimatepriority"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// EstimatePriorityCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) EstimatePriorityCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan EstimatePriorityRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// EstimatePriorityGetRes returns a pointer to the value in the Result field
+func (a API) EstimatePriorityGetRes() (out *float64, err error) {
+	out, _ = a.Result.(*float64)
+	err, _ = a.Result.(error)
+	return
+}
+
+// EstimatePriorityWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) EstimatePriorityWait(cmd *btcjson.EstimatePriorityCmd) (out *float64, err error) {
+	RPCHandlers["estimatepriority"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan EstimatePriorityRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // Generate calls the method with the given parameters
 func (a API) Generate(cmd *None) (err error) {
 	RPCHandlers["generate"].Call <- API{a.Ch, cmd, nil}
@@ -739,6 +957,211 @@ func (a API) GetAddedNodeInfoWait(cmd *btcjson.GetAddedNodeInfoCmd) (out *[]btcj
 	return
 }
 
+// GetAddressBalance calls the method with the given parameters
+func (a API) GetAddressBalance(cmd *btcjson.GetAddressBalanceCmd) (err error) {
+	RPCHandlers["getaddressbalance"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetAddressBalanceCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetAddressBalanceCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetAddressBalanceRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetAddressBalanceGetRes returns a pointer to the value in the Result field
+func (a API) GetAddressBalanceGetRes() (out *btcjson.GetAddressBalanceResult, err error) {
+	out, _ = a.Result.(*btcjson.GetAddressBalanceResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetAddressBalanceWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetAddressBalanceWait(cmd *btcjson.GetAddressBalanceCmd) (out *btcjson.GetAddressBalanceResult, err error) {
+	RPCHandlers["getaddressbalance"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetAddressBalanceRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// GetAddressDeltas calls the method with the given parameters
+func (a API) GetAddressDeltas(cmd *btcjson.GetAddressDeltasCmd) (err error) {
+	RPCHandlers["getaddressdeltas"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetAddressDeltasCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetAddressDeltasCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetAddressDeltasRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetAddressDeltasGetRes returns a pointer to the value in the Result field
+func (a API) GetAddressDeltasGetRes() (out *[]btcjson.GetAddressDeltasResult, err error) {
+	out, _ = a.Result.(*[]btcjson.GetAddressDeltasResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetAddressDeltasWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetAddressDeltasWait(cmd *btcjson.GetAddressDeltasCmd) (out *[]btcjson.GetAddressDeltasResult, err error) {
+	RPCHandlers["getaddressdeltas"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetAddressDeltasRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// GetAddressMempool calls the method with the given parameters
+func (a API) GetAddressMempool(cmd *btcjson.GetAddressMempoolCmd) (err error) {
+	RPCHandlers["getaddressmempool"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetAddressMempoolCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetAddressMempoolCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetAddressMempoolRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetAddressMempoolGetRes returns a pointer to the value in the Result field
+func (a API) GetAddressMempoolGetRes() (out *[]btcjson.GetAddressMempoolResult, err error) {
+	out, _ = a.Result.(*[]btcjson.GetAddressMempoolResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetAddressMempoolWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetAddressMempoolWait(cmd *btcjson.GetAddressMempoolCmd) (out *[]btcjson.GetAddressMempoolResult, err error) {
+	RPCHandlers["getaddressmempool"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetAddressMempoolRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// GetAddressUtxos calls the method with the given parameters
+func (a API) GetAddressUtxos(cmd *btcjson.GetAddressUtxosCmd) (err error) {
+	RPCHandlers["getaddressutxos"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetAddressUtxosCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetAddressUtxosCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetAddressUtxosRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetAddressUtxosGetRes returns a pointer to the value in the Result field
+func (a API) GetAddressUtxosGetRes() (out *[]btcjson.GetAddressUtxosResult, err error) {
+	out, _ = a.Result.(*[]btcjson.GetAddressUtxosResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetAddressUtxosWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetAddressUtxosWait(cmd *btcjson.GetAddressUtxosCmd) (out *[]btcjson.GetAddressUtxosResult, err error) {
+	RPCHandlers["getaddressutxos"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetAddressUtxosRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// GetAlgoStats calls the method with the given parameters
+func (a API) GetAlgoStats(cmd *btcjson.GetAlgoStatsCmd) (err error) {
+	RPCHandlers["getalgostats"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetAlgoStatsCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetAlgoStatsCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetAlgoStatsRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetAlgoStatsGetRes returns a pointer to the value in the Result field
+func (a API) GetAlgoStatsGetRes() (out *btcjson.GetAlgoStatsResult, err error) {
+	out, _ = a.Result.(*btcjson.GetAlgoStatsResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetAlgoStatsWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetAlgoStatsWait(cmd *btcjson.GetAlgoStatsCmd) (out *btcjson.GetAlgoStatsResult, err error) {
+	RPCHandlers["getalgostats"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetAlgoStatsRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // GetBestBlock calls the method with the given parameters
 func (a API) GetBestBlock(cmd *None) (err error) {
 	RPCHandlers["getbestblock"].Call <- API{a.Ch, cmd, nil}
@@ -1149,17 +1572,17 @@ func (a API) GetCFilterHeaderWait(cmd *btcjson.GetCFilterHeaderCmd) (out *string
 	return
 }
 
-// GetConnectionCount calls the method with the given parameters
-func (a API) GetConnectionCount(cmd *None) (err error) {
-	RPCHandlers["getconnectioncount"].Call <- API{a.Ch, cmd, nil}
+// GetChainTips calls the method with the given parameters
+func (a API) GetChainTips(cmd *btcjson.GetChainTipsCmd) (err error) {
+	RPCHandlers["getchaintips"].Call <- API{a.Ch, cmd, nil}
 	return
 }
 
-// GetConnectionCountCheck checks if a new message arrived on the result channel and 
+// GetChainTipsCheck checks if a new message arrived on the result channel and
 // returns true if it does, as well as storing the value in the Result field
-func (a API) GetConnectionCountCheck() (isNew bool) {
+func (a API) GetChainTipsCheck() (isNew bool) {
 	select {
-	case o := <-a.Ch.(chan GetConnectionCountRes):
+	case o := <-a.Ch.(chan GetChainTipsRes):
 		if o.Err != nil {
 			a.Result = o.Err
 		} else {
@@ -1171,32 +1594,73 @@ func (a API) GetConnectionCountCheck() (isNew bool) {
 	return
 }
 
-// GetConnectionCountGetRes returns a pointer to the value in the Result field
-func (a API) GetConnectionCountGetRes() (out *int32, err error) {
-	out, _ = a.Result.(*int32)
+// GetChainTipsGetRes returns a pointer to the value in the Result field
+func (a API) GetChainTipsGetRes() (out *[]btcjson.GetChainTipsResult, err error) {
+	out, _ = a.Result.(*[]btcjson.GetChainTipsResult)
 	err, _ = a.Result.(error)
 	return
 }
 
-// GetConnectionCountWait calls the method and blocks until it returns or 5 seconds passes
-func (a API) GetConnectionCountWait(cmd *None) (out *int32, err error) {
-	RPCHandlers["getconnectioncount"].Call <- API{a.Ch, cmd, nil}
+// GetChainTipsWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetChainTipsWait(cmd *btcjson.GetChainTipsCmd) (out *[]btcjson.GetChainTipsResult, err error) {
+	RPCHandlers["getchaintips"].Call <- API{a.Ch, cmd, nil}
 	select {
 	case <-time.After(time.Second * 5):
 		break
-	case o := <-a.Ch.(chan GetConnectionCountRes):
+	case o := <-a.Ch.(chan GetChainTipsRes):
 		out, err = o.Res, o.Err
 	}
 	return
 }
 
-// GetCurrentNet calls the method with the given parameters
-func (a API) GetCurrentNet(cmd *None) (err error) {
-	RPCHandlers["getcurrentnet"].Call <- API{a.Ch, cmd, nil}
+// GetConnectionCount calls the method with the given parameters
+func (a API) GetConnectionCount(cmd *None) (err error) {
+	RPCHandlers["getconnectioncount"].Call <- API{a.Ch, cmd, nil}
 	return
 }
 
-// GetCurrentNetCheck checks if a new message arrived on the result channel and 
+// GetConnectionCountCheck checks if a new message arrived on the result channel and 
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetConnectionCountCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetConnectionCountRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetConnectionCountGetRes returns a pointer to the value in the Result field
+func (a API) GetConnectionCountGetRes() (out *int32, err error) {
+	out, _ = a.Result.(*int32)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetConnectionCountWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetConnectionCountWait(cmd *None) (out *int32, err error) {
+	RPCHandlers["getconnectioncount"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetConnectionCountRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// GetCurrentNet calls the method with the given parameters
+func (a API) GetCurrentNet(cmd *None) (err error) {
+	RPCHandlers["getcurrentnet"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetCurrentNetCheck checks if a new message arrived on the result channel and 
 // returns true if it does, as well as storing the value in the Result field
 func (a API) GetCurrentNetCheck() (isNew bool) {
 	select {
@@ -1436,6 +1900,47 @@ func (a API) GetInfoWait(cmd *None) (out *btcjson.InfoChainResult0, err error) {
 	return
 }
 
+// GetMempoolEntry calls the method with the given parameters
+func (a API) GetMempoolEntry(cmd *btcjson.GetMempoolEntryCmd) (err error) {
+	RPCHandlers["getmempoolentry"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetMempoolEntryCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetMempoolEntryCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetMempoolEntryRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetMempoolEntryGetRes returns a pointer to the value in the Result field
+func (a API) GetMempoolEntryGetRes() (out *btcjson.GetMempoolEntryResult, err error) {
+	out, _ = a.Result.(*btcjson.GetMempoolEntryResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetMempoolEntryWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetMempoolEntryWait(cmd *btcjson.GetMempoolEntryCmd) (out *btcjson.GetMempoolEntryResult, err error) {
+	RPCHandlers["getmempoolentry"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetMempoolEntryRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // GetMempoolInfo calls the method with the given parameters
 func (a API) GetMempoolInfo(cmd *None) (err error) {
 	RPCHandlers["getmempoolinfo"].Call <- API{a.Ch, cmd, nil}
@@ -1600,6 +2105,47 @@ func (a API) GetNetworkHashPSWait(cmd *btcjson.GetNetworkHashPSCmd) (out *[]btcj
 	return
 }
 
+// GetNetworkInfo calls the method with the given parameters
+func (a API) GetNetworkInfo(cmd *None) (err error) {
+	RPCHandlers["getnetworkinfo"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetNetworkInfoCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetNetworkInfoCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetNetworkInfoRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetNetworkInfoGetRes returns a pointer to the value in the Result field
+func (a API) GetNetworkInfoGetRes() (out *btcjson.GetNetworkInfoResult, err error) {
+	out, _ = a.Result.(*btcjson.GetNetworkInfoResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetNetworkInfoWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetNetworkInfoWait(cmd *None) (out *btcjson.GetNetworkInfoResult, err error) {
+	RPCHandlers["getnetworkinfo"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetNetworkInfoRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // GetPeerInfo calls the method with the given parameters
 func (a API) GetPeerInfo(cmd *None) (err error) {
 	RPCHandlers["getpeerinfo"].Call <- API{a.Ch, cmd, nil}
@@ -1764,6 +2310,88 @@ func (a API) GetTxOutWait(cmd *btcjson.GetTxOutCmd) (out *string, err error) {
 	return
 }
 
+// GetTxOutSetInfo calls the method with the given parameters
+func (a API) GetTxOutSetInfo(cmd *None) (err error) {
+	RPCHandlers["gettxoutsetinfo"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetTxOutSetInfoCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetTxOutSetInfoCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetTxOutSetInfoRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetTxOutSetInfoGetRes returns a pointer to the value in the Result field
+func (a API) GetTxOutSetInfoGetRes() (out *btcjson.GetTxOutSetInfoResult, err error) {
+	out, _ = a.Result.(*btcjson.GetTxOutSetInfoResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetTxOutSetInfoWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetTxOutSetInfoWait(cmd *None) (out *btcjson.GetTxOutSetInfoResult, err error) {
+	RPCHandlers["gettxoutsetinfo"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetTxOutSetInfoRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// GetWorkerStats calls the method with the given parameters
+func (a API) GetWorkerStats(cmd *None) (err error) {
+	RPCHandlers["getworkerstats"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// GetWorkerStatsCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) GetWorkerStatsCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan GetWorkerStatsRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// GetWorkerStatsGetRes returns a pointer to the value in the Result field
+func (a API) GetWorkerStatsGetRes() (out *[]btcjson.GetWorkerStatsResult, err error) {
+	out, _ = a.Result.(*[]btcjson.GetWorkerStatsResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// GetWorkerStatsWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) GetWorkerStatsWait(cmd *None) (out *[]btcjson.GetWorkerStatsResult, err error) {
+	RPCHandlers["getworkerstats"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan GetWorkerStatsRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // Help calls the method with the given parameters
 func (a API) Help(cmd *btcjson.HelpCmd) (err error) {
 	RPCHandlers["help"].Call <- API{a.Ch, cmd, nil}
@@ -1805,6 +2433,88 @@ func (a API) HelpWait(cmd *btcjson.HelpCmd) (out *string, err error) {
 	return
 }
 
+// InvalidateBlock calls the method with the given parameters
+func (a API) InvalidateBlock(cmd *btcjson.InvalidateBlockCmd) (err error) {
+	RPCHandlers["invalidateblock"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// InvalidateBlockCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) InvalidateBlockCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan InvalidateBlockRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// InvalidateBlockGetRes returns a pointer to the value in the Result field
+func (a API) InvalidateBlockGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// InvalidateBlockWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) InvalidateBlockWait(cmd *btcjson.InvalidateBlockCmd) (out *None, err error) {
+	RPCHandlers["invalidateblock"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan InvalidateBlockRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// LoadTxOutSet calls the method with the given parameters
+func (a API) LoadTxOutSet(cmd *btcjson.LoadTxOutSetCmd) (err error) {
+	RPCHandlers["loadtxoutset"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// LoadTxOutSetCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) LoadTxOutSetCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan LoadTxOutSetRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// LoadTxOutSetGetRes returns a pointer to the value in the Result field
+func (a API) LoadTxOutSetGetRes() (out *btcjson.TxOutSetSnapshotResult, err error) {
+	out, _ = a.Result.(*btcjson.TxOutSetSnapshotResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// LoadTxOutSetWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) LoadTxOutSetWait(cmd *btcjson.LoadTxOutSetCmd) (out *btcjson.TxOutSetSnapshotResult, err error) {
+	RPCHandlers["loadtxoutset"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan LoadTxOutSetRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // Node calls the method with the given parameters
 func (a API) Node(cmd *btcjson.NodeCmd) (err error) {
 	RPCHandlers["node"].Call <- API{a.Ch, cmd, nil}
@@ -1887,6 +2597,88 @@ func (a API) PingWait(cmd *None) (out *None, err error) {
 	return
 }
 
+// PreciousBlock calls the method with the given parameters
+func (a API) PreciousBlock(cmd *btcjson.PreciousBlockCmd) (err error) {
+	RPCHandlers["preciousblock"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// PreciousBlockCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) PreciousBlockCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan PreciousBlockRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// PreciousBlockGetRes returns a pointer to the value in the Result field
+func (a API) PreciousBlockGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// PreciousBlockWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) PreciousBlockWait(cmd *btcjson.PreciousBlockCmd) (out *None, err error) {
+	RPCHandlers["preciousblock"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan PreciousBlockRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// ReconsiderBlock calls the method with the given parameters
+func (a API) ReconsiderBlock(cmd *btcjson.ReconsiderBlockCmd) (err error) {
+	RPCHandlers["reconsiderblock"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// ReconsiderBlockCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) ReconsiderBlockCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan ReconsiderBlockRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// ReconsiderBlockGetRes returns a pointer to the value in the Result field
+func (a API) ReconsiderBlockGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// ReconsiderBlockWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) ReconsiderBlockWait(cmd *btcjson.ReconsiderBlockCmd) (out *None, err error) {
+	RPCHandlers["reconsiderblock"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan ReconsiderBlockRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // ResetChain calls the method with the given parameters
 func (a API) ResetChain(cmd *None) (err error) {
 	RPCHandlers["resetchain"].Call <- API{a.Ch, cmd, nil}
@@ -1969,6 +2761,47 @@ func (a API) RestartWait(cmd *None) (out *None, err error) {
 	return
 }
 
+// SaveMempool calls the method with the given parameters
+func (a API) SaveMempool(cmd *None) (err error) {
+	RPCHandlers["savemempool"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// SaveMempoolCheck checks if a new message arrived on the result channel and
+// returns true if it does, as well as storing the value in the Result field
+func (a API) SaveMempoolCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan SaveMempoolRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// SaveMempoolGetRes returns a pointer to the value in the Result field
+func (a API) SaveMempoolGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// SaveMempoolWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) SaveMempoolWait(cmd *None) (out *None, err error) {
+	RPCHandlers["savemempool"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan SaveMempoolRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // SearchRawTransactions calls the method with the given parameters
 func (a API) SearchRawTransactions(cmd *btcjson.SearchRawTransactionsCmd) (err error) {
 	RPCHandlers["searchrawtransactions"].Call <- API{a.Ch, cmd, nil}
@@ -2418,6 +3251,13 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(btcjson.DecodeScriptResult); ok {
 					msg.Ch.(chan DecodeScriptRes) <- DecodeScriptRes{&r, err}
 				}
+			case msg := <-nrh["dumptxoutset"].Call:
+				if res, err = nrh["dumptxoutset"].
+					Fn(server, msg.Params.(*btcjson.DumpTxOutSetCmd), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.TxOutSetSnapshotResult); ok {
+					msg.Ch.(chan DumpTxOutSetRes) <- DumpTxOutSetRes{&r, err}
+				}
 			case msg := <-nrh["estimatefee"].Call:
 				if res, err = nrh["estimatefee"].
 					Fn(server, msg.Params.(*btcjson.EstimateFeeCmd), nil); Check(err) {
@@ -2425,6 +3265,13 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(float64); ok {
 					msg.Ch.(chan EstimateFeeRes) <- EstimateFeeRes{&r, err}
 				}
+			case msg := <-nrh["estimatepriority"].Call:
+				if res, err = nrh["estimatepriority"].
+					Fn(server, msg.Params.(*btcjson.EstimatePriorityCmd), nil); Check(err) {
+				}
+				if r, ok := res.(float64); ok {
+					msg.Ch.(chan EstimatePriorityRes) <- EstimatePriorityRes{&r, err}
+				}
 			case msg := <-nrh["generate"].Call:
 				if res, err = nrh["generate"].
 					Fn(server, msg.Params.(*None), nil); Check(err) {
@@ -2439,6 +3286,41 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.([]btcjson.GetAddedNodeInfoResultAddr); ok {
 					msg.Ch.(chan GetAddedNodeInfoRes) <- GetAddedNodeInfoRes{&r, err}
 				}
+			case msg := <-nrh["getaddressbalance"].Call:
+				if res, err = nrh["getaddressbalance"].
+					Fn(server, msg.Params.(*btcjson.GetAddressBalanceCmd), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.GetAddressBalanceResult); ok {
+					msg.Ch.(chan GetAddressBalanceRes) <- GetAddressBalanceRes{&r, err}
+				}
+			case msg := <-nrh["getaddressdeltas"].Call:
+				if res, err = nrh["getaddressdeltas"].
+					Fn(server, msg.Params.(*btcjson.GetAddressDeltasCmd), nil); Check(err) {
+				}
+				if r, ok := res.([]btcjson.GetAddressDeltasResult); ok {
+					msg.Ch.(chan GetAddressDeltasRes) <- GetAddressDeltasRes{&r, err}
+				}
+			case msg := <-nrh["getaddressmempool"].Call:
+				if res, err = nrh["getaddressmempool"].
+					Fn(server, msg.Params.(*btcjson.GetAddressMempoolCmd), nil); Check(err) {
+				}
+				if r, ok := res.([]btcjson.GetAddressMempoolResult); ok {
+					msg.Ch.(chan GetAddressMempoolRes) <- GetAddressMempoolRes{&r, err}
+				}
+			case msg := <-nrh["getaddressutxos"].Call:
+				if res, err = nrh["getaddressutxos"].
+					Fn(server, msg.Params.(*btcjson.GetAddressUtxosCmd), nil); Check(err) {
+				}
+				if r, ok := res.([]btcjson.GetAddressUtxosResult); ok {
+					msg.Ch.(chan GetAddressUtxosRes) <- GetAddressUtxosRes{&r, err}
+				}
+			case msg := <-nrh["getalgostats"].Call:
+				if res, err = nrh["getalgostats"].
+					Fn(server, msg.Params.(*btcjson.GetAlgoStatsCmd), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.GetAlgoStatsResult); ok {
+					msg.Ch.(chan GetAlgoStatsRes) <- GetAlgoStatsRes{&r, err}
+				}
 			case msg := <-nrh["getbestblock"].Call:
 				if res, err = nrh["getbestblock"].
 					Fn(server, msg.Params.(*None), nil); Check(err) {
@@ -2509,6 +3391,13 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan GetCFilterHeaderRes) <- GetCFilterHeaderRes{&r, err}
 				}
+			case msg := <-nrh["getchaintips"].Call:
+				if res, err = nrh["getchaintips"].
+					Fn(server, msg.Params.(*btcjson.GetChainTipsCmd), nil); Check(err) {
+				}
+				if r, ok := res.([]btcjson.GetChainTipsResult); ok {
+					msg.Ch.(chan GetChainTipsRes) <- GetChainTipsRes{&r, err}
+				}
 			case msg := <-nrh["getconnectioncount"].Call:
 				if res, err = nrh["getconnectioncount"].
 					Fn(server, msg.Params.(*None), nil); Check(err) {
@@ -2558,6 +3447,13 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(btcjson.InfoChainResult0); ok {
 					msg.Ch.(chan GetInfoRes) <- GetInfoRes{&r, err}
 				}
+			case msg := <-nrh["getmempoolentry"].Call:
+				if res, err = nrh["getmempoolentry"].
+					Fn(server, msg.Params.(*btcjson.GetMempoolEntryCmd), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.GetMempoolEntryResult); ok {
+					msg.Ch.(chan GetMempoolEntryRes) <- GetMempoolEntryRes{&r, err}
+				}
 			case msg := <-nrh["getmempoolinfo"].Call:
 				if res, err = nrh["getmempoolinfo"].
 					Fn(server, msg.Params.(*None), nil); Check(err) {
@@ -2586,6 +3482,13 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.([]btcjson.GetPeerInfoResult); ok {
 					msg.Ch.(chan GetNetworkHashPSRes) <- GetNetworkHashPSRes{&r, err}
 				}
+			case msg := <-nrh["getnetworkinfo"].Call:
+				if res, err = nrh["getnetworkinfo"].
+					Fn(server, msg.Params.(*None), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.GetNetworkInfoResult); ok {
+					msg.Ch.(chan GetNetworkInfoRes) <- GetNetworkInfoRes{&r, err}
+				}
 			case msg := <-nrh["getpeerinfo"].Call:
 				if res, err = nrh["getpeerinfo"].
 					Fn(server, msg.Params.(*None), nil); Check(err) {
@@ -2614,6 +3517,20 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan GetTxOutRes) <- GetTxOutRes{&r, err}
 				}
+			case msg := <-nrh["gettxoutsetinfo"].Call:
+				if res, err = nrh["gettxoutsetinfo"].
+					Fn(server, msg.Params.(*None), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.GetTxOutSetInfoResult); ok {
+					msg.Ch.(chan GetTxOutSetInfoRes) <- GetTxOutSetInfoRes{&r, err}
+				}
+			case msg := <-nrh["getworkerstats"].Call:
+				if res, err = nrh["getworkerstats"].
+					Fn(server, msg.Params.(*None), nil); Check(err) {
+				}
+				if r, ok := res.([]btcjson.GetWorkerStatsResult); ok {
+					msg.Ch.(chan GetWorkerStatsRes) <- GetWorkerStatsRes{&r, err}
+				}
 			case msg := <-nrh["help"].Call:
 				if res, err = nrh["help"].
 					Fn(server, msg.Params.(*btcjson.HelpCmd), nil); Check(err) {
@@ -2621,6 +3538,20 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan HelpRes) <- HelpRes{&r, err}
 				}
+			case msg := <-nrh["invalidateblock"].Call:
+				if res, err = nrh["invalidateblock"].
+					Fn(server, msg.Params.(*btcjson.InvalidateBlockCmd), nil); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan InvalidateBlockRes) <- InvalidateBlockRes{&r, err}
+				}
+			case msg := <-nrh["loadtxoutset"].Call:
+				if res, err = nrh["loadtxoutset"].
+					Fn(server, msg.Params.(*btcjson.LoadTxOutSetCmd), nil); Check(err) {
+				}
+				if r, ok := res.(btcjson.TxOutSetSnapshotResult); ok {
+					msg.Ch.(chan LoadTxOutSetRes) <- LoadTxOutSetRes{&r, err}
+				}
 			case msg := <-nrh["node"].Call:
 				if res, err = nrh["node"].
 					Fn(server, msg.Params.(*btcjson.NodeCmd), nil); Check(err) {
@@ -2635,6 +3566,20 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(None); ok {
 					msg.Ch.(chan PingRes) <- PingRes{&r, err}
 				}
+			case msg := <-nrh["preciousblock"].Call:
+				if res, err = nrh["preciousblock"].
+					Fn(server, msg.Params.(*btcjson.PreciousBlockCmd), nil); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan PreciousBlockRes) <- PreciousBlockRes{&r, err}
+				}
+			case msg := <-nrh["reconsiderblock"].Call:
+				if res, err = nrh["reconsiderblock"].
+					Fn(server, msg.Params.(*btcjson.ReconsiderBlockCmd), nil); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan ReconsiderBlockRes) <- ReconsiderBlockRes{&r, err}
+				}
 			case msg := <-nrh["resetchain"].Call:
 				if res, err = nrh["resetchain"].
 					Fn(server, msg.Params.(*None), nil); Check(err) {
@@ -2649,6 +3594,13 @@ func RunAPI(server *Server, quit qu.C) {
 				if r, ok := res.(None); ok {
 					msg.Ch.(chan RestartRes) <- RestartRes{&r, err}
 				}
+			case msg := <-nrh["savemempool"].Call:
+				if res, err = nrh["savemempool"].
+					Fn(server, msg.Params.(*None), nil); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan SaveMempoolRes) <- SaveMempoolRes{&r, err}
+				}
 			case msg := <-nrh["searchrawtransactions"].Call:
 				if res, err = nrh["searchrawtransactions"].
 					Fn(server, msg.Params.(*btcjson.SearchRawTransactionsCmd), nil); Check(err) {
@@ -2727,93 +3679,184 @@ func RunAPI(server *Server, quit qu.C) {
 	}()
 }
 
-// RPC API functions to use with net/rpc
-
-func (c *CAPI) AddNode(req *btcjson.AddNodeCmd, resp None) (err error) {
+// RPC API functions to use with net/rpc
+
+func (c *CAPI) AddNode(req *btcjson.AddNodeCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["addnode"].Result()
+	res.Params = req
+	nrh["addnode"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) CreateRawTransaction(req *btcjson.CreateRawTransactionCmd, resp string) (err error) {
+	nrh := RPCHandlers
+	res := nrh["createrawtransaction"].Result()
+	res.Params = req
+	nrh["createrawtransaction"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan string):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) DecodeRawTransaction(req *btcjson.DecodeRawTransactionCmd, resp btcjson.TxRawDecodeResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["decoderawtransaction"].Result()
+	res.Params = req
+	nrh["decoderawtransaction"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.TxRawDecodeResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) DecodeScript(req *btcjson.DecodeScriptCmd, resp btcjson.DecodeScriptResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["decodescript"].Result()
+	res.Params = req
+	nrh["decodescript"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.DecodeScriptResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) DumpTxOutSet(req *btcjson.DumpTxOutSetCmd, resp btcjson.TxOutSetSnapshotResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["dumptxoutset"].Result()
+	res.Params = req
+	nrh["dumptxoutset"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.TxOutSetSnapshotResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) EstimateFee(req *btcjson.EstimateFeeCmd, resp float64) (err error) {
+	nrh := RPCHandlers
+	res := nrh["estimatefee"].Result()
+	res.Params = req
+	nrh["estimatefee"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan float64):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) EstimatePriority(req *btcjson.EstimatePriorityCmd, resp float64) (err error) {
+	nrh := RPCHandlers
+	res := nrh["estimatepriority"].Result()
+	res.Params = req
+	nrh["estimatepriority"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan float64):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) Generate(req *None, resp []string) (err error) {
 	nrh := RPCHandlers
-	res := nrh["addnode"].Result()
+	res := nrh["generate"].Result()
 	res.Params = req
-	nrh["addnode"].Call <- res
+	nrh["generate"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan None):
+	case resp = <-res.Ch.(chan []string):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
 	return
 }
 
-func (c *CAPI) CreateRawTransaction(req *btcjson.CreateRawTransactionCmd, resp string) (err error) {
+func (c *CAPI) GetAddedNodeInfo(req *btcjson.GetAddedNodeInfoCmd, resp []btcjson.GetAddedNodeInfoResultAddr) (err error) {
 	nrh := RPCHandlers
-	res := nrh["createrawtransaction"].Result()
+	res := nrh["getaddednodeinfo"].Result()
 	res.Params = req
-	nrh["createrawtransaction"].Call <- res
+	nrh["getaddednodeinfo"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan string):
+	case resp = <-res.Ch.(chan []btcjson.GetAddedNodeInfoResultAddr):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
 	return
 }
 
-func (c *CAPI) DecodeRawTransaction(req *btcjson.DecodeRawTransactionCmd, resp btcjson.TxRawDecodeResult) (err error) {
+func (c *CAPI) GetAddressBalance(req *btcjson.GetAddressBalanceCmd, resp btcjson.GetAddressBalanceResult) (err error) {
 	nrh := RPCHandlers
-	res := nrh["decoderawtransaction"].Result()
+	res := nrh["getaddressbalance"].Result()
 	res.Params = req
-	nrh["decoderawtransaction"].Call <- res
+	nrh["getaddressbalance"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan btcjson.TxRawDecodeResult):
+	case resp = <-res.Ch.(chan btcjson.GetAddressBalanceResult):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
 	return
 }
 
-func (c *CAPI) DecodeScript(req *btcjson.DecodeScriptCmd, resp btcjson.DecodeScriptResult) (err error) {
+func (c *CAPI) GetAddressDeltas(req *btcjson.GetAddressDeltasCmd, resp []btcjson.GetAddressDeltasResult) (err error) {
 	nrh := RPCHandlers
-	res := nrh["decodescript"].Result()
+	res := nrh["getaddressdeltas"].Result()
 	res.Params = req
-	nrh["decodescript"].Call <- res
+	nrh["getaddressdeltas"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan btcjson.DecodeScriptResult):
+	case resp = <-res.Ch.(chan []btcjson.GetAddressDeltasResult):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
 	return
 }
 
-func (c *CAPI) EstimateFee(req *btcjson.EstimateFeeCmd, resp float64) (err error) {
+func (c *CAPI) GetAddressMempool(req *btcjson.GetAddressMempoolCmd, resp []btcjson.GetAddressMempoolResult) (err error) {
 	nrh := RPCHandlers
-	res := nrh["estimatefee"].Result()
+	res := nrh["getaddressmempool"].Result()
 	res.Params = req
-	nrh["estimatefee"].Call <- res
+	nrh["getaddressmempool"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan float64):
+	case resp = <-res.Ch.(chan []btcjson.GetAddressMempoolResult):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
 	return
 }
 
-func (c *CAPI) Generate(req *None, resp []string) (err error) {
+func (c *CAPI) GetAddressUtxos(req *btcjson.GetAddressUtxosCmd, resp []btcjson.GetAddressUtxosResult) (err error) {
 	nrh := RPCHandlers
-	res := nrh["generate"].Result()
+	res := nrh["getaddressutxos"].Result()
 	res.Params = req
-	nrh["generate"].Call <- res
+	nrh["getaddressutxos"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan []string):
+	case resp = <-res.Ch.(chan []btcjson.GetAddressUtxosResult):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
 	return
 }
 
-func (c *CAPI) GetAddedNodeInfo(req *btcjson.GetAddedNodeInfoCmd, resp []btcjson.GetAddedNodeInfoResultAddr) (err error) {
+func (c *CAPI) GetAlgoStats(req *btcjson.GetAlgoStatsCmd, resp btcjson.GetAlgoStatsResult) (err error) {
 	nrh := RPCHandlers
-	res := nrh["getaddednodeinfo"].Result()
+	res := nrh["getalgostats"].Result()
 	res.Params = req
-	nrh["getaddednodeinfo"].Call <- res
+	nrh["getalgostats"].Call <- res
 	select {
-	case resp = <-res.Ch.(chan []btcjson.GetAddedNodeInfoResultAddr):
+	case resp = <-res.Ch.(chan btcjson.GetAlgoStatsResult):
 	case <-time.After(c.Timeout):
 	case <-c.quit:
 	}
@@ -2950,6 +3993,19 @@ func (c *CAPI) GetCFilterHeader(req *btcjson.GetCFilterHeaderCmd, resp string) (
 	return
 }
 
+func (c *CAPI) GetChainTips(req *btcjson.GetChainTipsCmd, resp []btcjson.GetChainTipsResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["getchaintips"].Result()
+	res.Params = req
+	nrh["getchaintips"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan []btcjson.GetChainTipsResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) GetConnectionCount(req *None, resp int32) (err error) {
 	nrh := RPCHandlers
 	res := nrh["getconnectioncount"].Result()
@@ -3041,6 +4097,19 @@ func (c *CAPI) GetInfo(req *None, resp btcjson.InfoChainResult0) (err error) {
 	return
 }
 
+func (c *CAPI) GetMempoolEntry(req *btcjson.GetMempoolEntryCmd, resp btcjson.GetMempoolEntryResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["getmempoolentry"].Result()
+	res.Params = req
+	nrh["getmempoolentry"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.GetMempoolEntryResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) GetMempoolInfo(req *None, resp btcjson.GetMempoolInfoResult) (err error) {
 	nrh := RPCHandlers
 	res := nrh["getmempoolinfo"].Result()
@@ -3093,6 +4162,19 @@ func (c *CAPI) GetNetworkHashPS(req *btcjson.GetNetworkHashPSCmd, resp []btcjson
 	return
 }
 
+func (c *CAPI) GetNetworkInfo(req *None, resp btcjson.GetNetworkInfoResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["getnetworkinfo"].Result()
+	res.Params = req
+	nrh["getnetworkinfo"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.GetNetworkInfoResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) GetPeerInfo(req *None, resp []btcjson.GetPeerInfoResult) (err error) {
 	nrh := RPCHandlers
 	res := nrh["getpeerinfo"].Result()
@@ -3145,6 +4227,32 @@ func (c *CAPI) GetTxOut(req *btcjson.GetTxOutCmd, resp string) (err error) {
 	return
 }
 
+func (c *CAPI) GetTxOutSetInfo(req *None, resp btcjson.GetTxOutSetInfoResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["gettxoutsetinfo"].Result()
+	res.Params = req
+	nrh["gettxoutsetinfo"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.GetTxOutSetInfoResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) GetWorkerStats(req *None, resp []btcjson.GetWorkerStatsResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["getworkerstats"].Result()
+	res.Params = req
+	nrh["getworkerstats"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan []btcjson.GetWorkerStatsResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) Help(req *btcjson.HelpCmd, resp string) (err error) {
 	nrh := RPCHandlers
 	res := nrh["help"].Result()
@@ -3158,6 +4266,32 @@ func (c *CAPI) Help(req *btcjson.HelpCmd, resp string) (err error) {
 	return
 }
 
+func (c *CAPI) InvalidateBlock(req *btcjson.InvalidateBlockCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["invalidateblock"].Result()
+	res.Params = req
+	nrh["invalidateblock"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) LoadTxOutSet(req *btcjson.LoadTxOutSetCmd, resp btcjson.TxOutSetSnapshotResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["loadtxoutset"].Result()
+	res.Params = req
+	nrh["loadtxoutset"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.TxOutSetSnapshotResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) Node(req *btcjson.NodeCmd, resp None) (err error) {
 	nrh := RPCHandlers
 	res := nrh["node"].Result()
@@ -3184,6 +4318,32 @@ func (c *CAPI) Ping(req *None, resp None) (err error) {
 	return
 }
 
+func (c *CAPI) PreciousBlock(req *btcjson.PreciousBlockCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["preciousblock"].Result()
+	res.Params = req
+	nrh["preciousblock"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) ReconsiderBlock(req *btcjson.ReconsiderBlockCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["reconsiderblock"].Result()
+	res.Params = req
+	nrh["reconsiderblock"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) ResetChain(req *None, resp None) (err error) {
 	nrh := RPCHandlers
 	res := nrh["resetchain"].Result()
@@ -3210,6 +4370,19 @@ func (c *CAPI) Restart(req *None, resp None) (err error) {
 	return
 }
 
+func (c *CAPI) SaveMempool(req *None, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["savemempool"].Result()
+	res.Params = req
+	nrh["savemempool"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) SearchRawTransactions(req *btcjson.SearchRawTransactionsCmd, resp []btcjson.SearchRawTransactionsResult) (err error) {
 	nrh := RPCHandlers
 	res := nrh["searchrawtransactions"].Result()
@@ -3382,6 +4555,16 @@ func (r *CAPIClient) DecodeScript(cmd ...*btcjson.DecodeScriptCmd) (res btcjson.
 	return
 }
 
+func (r *CAPIClient) DumpTxOutSet(cmd ...*btcjson.DumpTxOutSetCmd) (res btcjson.TxOutSetSnapshotResult, err error) {
+	var c *btcjson.DumpTxOutSetCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.DumpTxOutSet", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) EstimateFee(cmd ...*btcjson.EstimateFeeCmd) (res float64, err error) {
 	var c *btcjson.EstimateFeeCmd
 	if len(cmd) > 0 {
@@ -3392,6 +4575,16 @@ func (r *CAPIClient) EstimateFee(cmd ...*btcjson.EstimateFeeCmd) (res float64, e
 	return
 }
 
+func (r *CAPIClient) EstimatePriority(cmd ...*btcjson.EstimatePriorityCmd) (res float64, err error) {
+	var c *btcjson.EstimatePriorityCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.EstimatePriority", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) Generate(cmd ...*None) (res []string, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3412,6 +4605,56 @@ func (r *CAPIClient) GetAddedNodeInfo(cmd ...*btcjson.GetAddedNodeInfoCmd) (res
 	return
 }
 
+func (r *CAPIClient) GetAddressBalance(cmd ...*btcjson.GetAddressBalanceCmd) (res btcjson.GetAddressBalanceResult, err error) {
+	var c *btcjson.GetAddressBalanceCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetAddressBalance", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) GetAddressDeltas(cmd ...*btcjson.GetAddressDeltasCmd) (res []btcjson.GetAddressDeltasResult, err error) {
+	var c *btcjson.GetAddressDeltasCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetAddressDeltas", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) GetAddressMempool(cmd ...*btcjson.GetAddressMempoolCmd) (res []btcjson.GetAddressMempoolResult, err error) {
+	var c *btcjson.GetAddressMempoolCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetAddressMempool", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) GetAddressUtxos(cmd ...*btcjson.GetAddressUtxosCmd) (res []btcjson.GetAddressUtxosResult, err error) {
+	var c *btcjson.GetAddressUtxosCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetAddressUtxos", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) GetAlgoStats(cmd ...*btcjson.GetAlgoStatsCmd) (res btcjson.GetAlgoStatsResult, err error) {
+	var c *btcjson.GetAlgoStatsCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetAlgoStats", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) GetBestBlock(cmd ...*None) (res btcjson.GetBestBlockResult, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3512,6 +4755,16 @@ func (r *CAPIClient) GetCFilterHeader(cmd ...*btcjson.GetCFilterHeaderCmd) (res
 	return
 }
 
+func (r *CAPIClient) GetChainTips(cmd ...*btcjson.GetChainTipsCmd) (res []btcjson.GetChainTipsResult, err error) {
+	var c *btcjson.GetChainTipsCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetChainTips", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) GetConnectionCount(cmd ...*None) (res int32, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3582,6 +4835,16 @@ func (r *CAPIClient) GetInfo(cmd ...*None) (res btcjson.InfoChainResult0, err er
 	return
 }
 
+func (r *CAPIClient) GetMempoolEntry(cmd ...*btcjson.GetMempoolEntryCmd) (res btcjson.GetMempoolEntryResult, err error) {
+	var c *btcjson.GetMempoolEntryCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetMempoolEntry", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) GetMempoolInfo(cmd ...*None) (res btcjson.GetMempoolInfoResult, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3622,6 +4885,16 @@ func (r *CAPIClient) GetNetworkHashPS(cmd ...*btcjson.GetNetworkHashPSCmd) (res
 	return
 }
 
+func (r *CAPIClient) GetNetworkInfo(cmd ...*None) (res btcjson.GetNetworkInfoResult, err error) {
+	var c *None
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetNetworkInfo", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) GetPeerInfo(cmd ...*None) (res []btcjson.GetPeerInfoResult, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3662,6 +4935,26 @@ func (r *CAPIClient) GetTxOut(cmd ...*btcjson.GetTxOutCmd) (res string, err erro
 	return
 }
 
+func (r *CAPIClient) GetTxOutSetInfo(cmd ...*None) (res btcjson.GetTxOutSetInfoResult, err error) {
+	var c *None
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetTxOutSetInfo", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) GetWorkerStats(cmd ...*None) (res []btcjson.GetWorkerStatsResult, err error) {
+	var c *None
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.GetWorkerStats", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) Help(cmd ...*btcjson.HelpCmd) (res string, err error) {
 	var c *btcjson.HelpCmd
 	if len(cmd) > 0 {
@@ -3672,6 +4965,26 @@ func (r *CAPIClient) Help(cmd ...*btcjson.HelpCmd) (res string, err error) {
 	return
 }
 
+func (r *CAPIClient) InvalidateBlock(cmd ...*btcjson.InvalidateBlockCmd) (res None, err error) {
+	var c *btcjson.InvalidateBlockCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.InvalidateBlock", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) LoadTxOutSet(cmd ...*btcjson.LoadTxOutSetCmd) (res btcjson.TxOutSetSnapshotResult, err error) {
+	var c *btcjson.LoadTxOutSetCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.LoadTxOutSet", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) Node(cmd ...*btcjson.NodeCmd) (res None, err error) {
 	var c *btcjson.NodeCmd
 	if len(cmd) > 0 {
@@ -3692,6 +5005,26 @@ func (r *CAPIClient) Ping(cmd ...*None) (res None, err error) {
 	return
 }
 
+func (r *CAPIClient) PreciousBlock(cmd ...*btcjson.PreciousBlockCmd) (res None, err error) {
+	var c *btcjson.PreciousBlockCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.PreciousBlock", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) ReconsiderBlock(cmd ...*btcjson.ReconsiderBlockCmd) (res None, err error) {
+	var c *btcjson.ReconsiderBlockCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.ReconsiderBlock", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) ResetChain(cmd ...*None) (res None, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3712,6 +5045,16 @@ func (r *CAPIClient) Restart(cmd ...*None) (res None, err error) {
 	return
 }
 
+func (r *CAPIClient) SaveMempool(cmd ...*None) (res None, err error) {
+	var c *None
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.SaveMempool", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) SearchRawTransactions(cmd ...*btcjson.SearchRawTransactionsCmd) (res []btcjson.SearchRawTransactionsResult, err error) {
 	var c *btcjson.SearchRawTransactionsCmd
 	if len(cmd) > 0 {
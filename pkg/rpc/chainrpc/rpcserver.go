@@ -37,6 +37,7 @@ import (
 	txscript "github.com/p9c/pod/pkg/chain/tx/script"
 	"github.com/p9c/pod/pkg/chain/wire"
 	p "github.com/p9c/pod/pkg/comm/peer"
+	"github.com/p9c/pod/pkg/comm/peer/addrmgr"
 	database "github.com/p9c/pod/pkg/db"
 	"github.com/p9c/pod/pkg/pod"
 	"github.com/p9c/pod/pkg/rpc/btcjson"
@@ -146,17 +147,24 @@ type ServerConfig struct {
 	// CPUMiner  *cpuminer.CPUMiner
 	//
 	// These fields define any optional indexes the RPC server can make use of to provide additional data when queried.
-	TxIndex   *indexers.TxIndex
-	AddrIndex *indexers.AddrIndex
-	CfIndex   *indexers.CFIndex
+	TxIndex        *indexers.TxIndex
+	AddrIndex      *indexers.AddrIndex
+	AddrUtxoIndex  *indexers.AddrUtxoIndex
+	AlgoStatsIndex *indexers.AlgoStatsIndex
+	CfIndex        *indexers.CFIndex
 	// The fee estimator keeps track of how long transactions are left in the mempool before they are mined into blocks.
 	FeeEstimator *mempool.FeeEstimator
+	// MempoolFile is the path of the file the mempool is saved to.
+	MempoolFile string
 	// Algo sets the algorithm expected from the RPC endpoint. This allows multiple ports to serve multiple types of
 	// miners with one main node per algorithm. Currently 514 for Scrypt and anything else passes for SHA256d.
 	Algo string
 	// CPUMiner *exec.Cmd
 	Hashrate uberatomic.Uint64
-	Quit     qu.C
+	// WorkerStats holds the function returning the record the kopach miner controller keeps of each miner, it is empty
+	// while the controller is not running.
+	WorkerStats uberatomic.Value
+	Quit        qu.C
 }
 
 // ServerConnManager represents a connection manager for use with the RPC server. The interface contract requires that
@@ -201,6 +209,10 @@ type ServerConnManager interface {
 	// RelayTransactions generates and relays inventory vectors for all of the passed transactions to all connected
 	// peers.
 	RelayTransactions(txns []*mempool.TxDesc)
+	// Services returns the service flags the server advertises to its peers.
+	Services() wire.ServiceFlag
+	// LocalAddresses returns the local addresses the server advertises to its peers.
+	LocalAddresses() []addrmgr.LocalAddress
 }
 
 // ServerPeer represents a peer for use with the RPC server.
@@ -315,7 +327,6 @@ var (
 		"getreceivedbyaccount":   {},
 		"getreceivedbyaddress":   {},
 		"gettransaction":         {},
-		"gettxoutsetinfo":        {},
 		"getunconfirmedbalance":  {},
 		"getwalletinfo":          {},
 		"importprivkey":          {},
@@ -649,6 +660,12 @@ var (
 		"decoderawtransaction":  {},
 		"decodescript":          {},
 		"estimatefee":           {},
+		"estimatepriority":      {},
+		"getaddressbalance":     {},
+		"getaddressdeltas":      {},
+		"getaddressmempool":     {},
+		"getaddressutxos":       {},
+		"getalgostats":          {},
 		"getbestblock":          {},
 		"getbestblockhash":      {},
 		"getblock":              {},
@@ -657,15 +674,19 @@ var (
 		"getblockheader":        {},
 		"getcfilter":            {},
 		"getcfilterheader":      {},
+		"getchaintips":          {},
 		"getcurrentnet":         {},
 		"getdifficulty":         {},
 		"getheaders":            {},
 		"getinfo":               {},
+		"getmempoolentry":       {},
 		"getnettotals":          {},
 		"getnetworkhashps":      {},
+		"getnetworkinfo":        {},
 		"getrawmempool":         {},
 		"getrawtransaction":     {},
 		"gettxout":              {},
+		"gettxoutsetinfo":       {},
 		"searchrawtransactions": {},
 		"sendrawtransaction":    {},
 		"submitblock":           {},
@@ -676,14 +697,7 @@ var (
 	}
 	// RPCUnimplemented is commands that are currently unimplemented, but should ultimately be.
 	RPCUnimplemented = map[string]struct{}{
-		"estimatepriority": {},
-		"getchaintips":     {},
-		"getmempoolentry":  {},
-		"getnetworkinfo":   {},
-		"getwork":          {},
-		"invalidateblock":  {},
-		"preciousblock":    {},
-		"reconsiderblock":  {},
+		"getwork": {},
 	}
 )
 
@@ -111,6 +111,18 @@ var HelpDescsEnUS = map[string]string{
 	"decodescript--synopsis": "Returns a JSON object with information about" +
 		" the provided hex-encoded script.",
 	"decodescript-hexscript": "Hex-encoded script",
+	// DumpTxOutSetCmd help.
+	"dumptxoutset--synopsis": "Writes a snapshot of the utxo set as of the best block to a file, along with the block headers up to it.\n" +
+		"The snapshot can be loaded by a new node with loadtxoutset.",
+	"dumptxoutset-path": "The file to write the snapshot to, relative to the data directory of the network if not absolute",
+
+	// TxOutSetSnapshotResult help.
+	"txoutsetsnapshotresult-path":            "The path of the snapshot file",
+	"txoutsetsnapshotresult-base_hash":       "The hash of the block the snapshot was taken at",
+	"txoutsetsnapshotresult-base_height":     "The height of the block the snapshot was taken at",
+	"txoutsetsnapshotresult-coins":           "The number of unspent transaction outputs in the snapshot",
+	"txoutsetsnapshotresult-hash_serialized": "The hash of the utxo set, as reported by gettxoutsetinfo",
+
 	// EstimateFeeCmd help.
 	"estimatefee--synopsis": "Estimate the fee per kilobyte in satoshis " +
 		"required for a transaction to be mined before a certain number of " +
@@ -119,6 +131,14 @@ var HelpDescsEnUS = map[string]string{
 		"generated before the transaction is mined.",
 	"estimatefee--result0": "Estimated fee per kilobyte in satoshis for a block to " +
 		"be mined in the next NumBlocks blocks.",
+	// EstimatePriorityCmd help.
+	"estimatepriority--synopsis": "Estimate the priority a zero-fee transaction needs " +
+		"to be mined before a certain number of blocks have been generated.",
+	"estimatepriority-numblocks": "The maximum number of blocks which can be " +
+		"generated before the transaction is mined.",
+	"estimatepriority--result0": "Estimated priority for a transaction to be " +
+		"mined in the next NumBlocks blocks, or -1 if blocks have no " +
+		"high-priority area.",
 	// GenerateCmd help
 	"generate--synopsis": "Generates a set number of blocks (simnet or" +
 		" regtest only) and returns a JSON\n" +
@@ -141,6 +161,71 @@ var HelpDescsEnUS = map[string]string{
 	"getaddednodeinfo--condition0": "dns=false",
 	"getaddednodeinfo--condition1": "dns=true",
 	"getaddednodeinfo--result0":    "List of added peers",
+	// GetAddressBalanceCmd help.
+	"getaddressbalance--synopsis": "Returns the balance of the given addresses and the total they received in the main chain.\n" +
+		"Requires the address utxo index (--addrutxoindex).",
+	"getaddressbalance-addresses": "The addresses to sum the balances of",
+	// GetAddressBalanceResult help.
+	"getaddressbalanceresult-balance":  "The balance in satoshis",
+	"getaddressbalanceresult-received": "The total received in satoshis, including what was spent since",
+	// GetAddressDeltasCmd help.
+	"getaddressdeltas--synopsis": "Returns the changes transactions in the main chain made to the balance of the given addresses, in chain order.\n" +
+		"Requires the address utxo index (--addrutxoindex).",
+	"getaddressdeltas-addresses": "The addresses to return the changes to",
+	"getaddressdeltas-start":     "The height of the first block to return changes from",
+	"getaddressdeltas-end":       "The height of the last block to return changes from (0 = the best block)",
+	// GetAddressDeltasResult help.
+	"getaddressdeltasresult-address":    "The address whose balance changed",
+	"getaddressdeltasresult-txid":       "The hash of the transaction",
+	"getaddressdeltasresult-index":      "The index of the input spending from the address or of the output paying to it",
+	"getaddressdeltasresult-blockindex": "The index of the transaction within its block",
+	"getaddressdeltasresult-height":     "The height of the block containing the transaction",
+	"getaddressdeltasresult-satoshis":   "The change to the balance in satoshis, negative for an input",
+	// GetAddressMempoolCmd help.
+	"getaddressmempool--synopsis": "Returns the changes the transactions in the memory pool make to the balance of the given addresses.\n" +
+		"Requires the address utxo index (--addrutxoindex).",
+	"getaddressmempool-addresses": "The addresses to return the changes to",
+	// GetAddressMempoolResult help.
+	"getaddressmempoolresult-address":   "The address whose balance changes",
+	"getaddressmempoolresult-txid":      "The hash of the transaction",
+	"getaddressmempoolresult-index":     "The index of the input spending from the address or of the output paying to it",
+	"getaddressmempoolresult-satoshis":  "The change to the balance in satoshis, negative for an input",
+	"getaddressmempoolresult-timestamp": "The time the transaction entered the memory pool in seconds since 1 Jan 1970 GMT",
+	"getaddressmempoolresult-prevtxid":  "The hash of the transaction whose output the input spends",
+	"getaddressmempoolresult-prevout":   "The index of the output the input spends",
+	// GetAddressUtxosCmd help.
+	"getaddressutxos--synopsis": "Returns the unspent outputs paying to the given addresses in the main chain.\n" +
+		"Requires the address utxo index (--addrutxoindex).",
+	"getaddressutxos-addresses": "The addresses to return the unspent outputs of",
+	// GetAddressUtxosResult help.
+	"getaddressutxosresult-address":     "The address the output pays to",
+	"getaddressutxosresult-txid":        "The hash of the transaction containing the output",
+	"getaddressutxosresult-outputIndex": "The index of the output",
+	"getaddressutxosresult-script":      "The hex-encoded public key script of the output",
+	"getaddressutxosresult-satoshis":    "The amount of the output in satoshis",
+	"getaddressutxosresult-height":      "The height of the block containing the output",
+	// GetAlgoStatsCmd help.
+	"getalgostats--synopsis": "Returns the share of blocks, the block interval and the estimated hashrate of each mining algorithm over a range of main chain blocks.\n" +
+		"Requires the algo stats index (--algostatsindex).",
+	"getalgostats-from": "The height of the first block of the range",
+	"getalgostats-to":   "The height of the last block of the range",
+	// GetAlgoStatsResult help.
+	"getalgostatsresult-from":   "The height of the first block of the range",
+	"getalgostatsresult-to":     "The height of the last block of the range",
+	"getalgostatsresult-blocks": "The number of blocks in the range",
+	"getalgostatsresult-span":   "The seconds from the block before the range to the last block of the range",
+	"getalgostatsresult-algos":  "The statistics of each algorithm that mined blocks in the range",
+	// AlgoStatsResult help.
+	"algostatsresult-algo":           "The name of the algorithm",
+	"algostatsresult-version":        "The block version of the algorithm",
+	"algostatsresult-blocks":         "The number of blocks mined with the algorithm",
+	"algostatsresult-share":          "The fraction of the blocks in the range mined with the algorithm",
+	"algostatsresult-avginterval":    "The average seconds between a block of the algorithm and the previous one",
+	"algostatsresult-targetinterval": "The seconds between blocks of the algorithm the difficulty adjustment aims for",
+	"algostatsresult-bits":           "The hex-encoded difficulty bits of the last block of the algorithm in the range",
+	"algostatsresult-difficulty":     "The difficulty of the last block of the algorithm in the range",
+	"algostatsresult-hashrate":       "The estimated hashes per second of the algorithm over the range",
+	"algostatsresult-chainwork":      "The hex-encoded total work of the algorithm up to its last block in the range",
 	// GetBestBlockResult help.
 	"getbestblockresult-hash":   "Hex-encoded bytes of the best block hash",
 	"getbestblockresult-height": "Height of the best block",
@@ -368,6 +453,15 @@ var HelpDescsEnUS = map[string]string{
 	"getcfilterheader-hash":       "The hash of the block",
 	"getcfilterheader--result0":   "The block's gcs filter header",
 
+	// GetChainTipsCmd help.
+	"getchaintips--synopsis": "Returns information about all known tips in the block tree, including the main chain as well as orphaned branches.",
+
+	// GetChainTipsResult help.
+	"getchaintipsresult-height":    "Height of the chain tip",
+	"getchaintipsresult-hash":      "Block hash of the tip",
+	"getchaintipsresult-branchlen": "Length of the branch connecting the tip to the main chain (zero for the main chain)",
+	"getchaintipsresult-status":    "Status of the chain: 'active', 'invalid', 'valid-fork', 'valid-headers' or 'headers-only'",
+
 	// GetConnectionCountCmd help.
 	"getconnectioncount--synopsis": "Returns the number of active connections to other peers.",
 	"getconnectioncount--result0":  "The number of connections",
@@ -427,12 +521,36 @@ var HelpDescsEnUS = map[string]string{
 	// GetInfoCmd help.
 	"getinfo--synopsis": "Returns a JSON object containing various state info.",
 
+	// GetMempoolEntryCmd help.
+	"getmempoolentry--synopsis": "Returns mempool data for the given transaction",
+	"getmempoolentry-txid":      "The hash of the transaction",
+
+	// GetMempoolEntryResult help.
+	"getmempoolentryresult-size":               "Transaction size in bytes",
+	"getmempoolentryresult-fee":                "Transaction fee in DUO",
+	"getmempoolentryresult-modifiedfee":        "Transaction fee in DUO with fee deltas used for mining priority",
+	"getmempoolentryresult-time":               "Local time transaction entered pool in seconds since 1 Jan 1970 GMT",
+	"getmempoolentryresult-height":             "Block height when transaction entered the pool",
+	"getmempoolentryresult-startingpriority":   "Priority when transaction entered the pool",
+	"getmempoolentryresult-currentpriority":    "Current priority",
+	"getmempoolentryresult-descendantcount":    "Number of in-mempool descendant transactions (including this one)",
+	"getmempoolentryresult-descendantsize":     "Size of in-mempool descendants (including this one)",
+	"getmempoolentryresult-descendantfees":     "Fees of in-mempool descendants (including this one) in DUO",
+	"getmempoolentryresult-ancestorcount":      "Number of in-mempool ancestor transactions (including this one)",
+	"getmempoolentryresult-ancestorsize":       "Size of in-mempool ancestors (including this one)",
+	"getmempoolentryresult-ancestorfees":       "Fees of in-mempool ancestors (including this one) in DUO",
+	"getmempoolentryresult-depends":            "Unconfirmed transactions used as inputs for this transaction",
+	"getmempoolentryresult-bip125-replaceable": "Whether this transaction could be replaced due to BIP125 (replace-by-fee)",
+
 	// GetMempoolInfoCmd help.
 	"getmempoolinfo--synopsis": "Returns memory pool information",
 
 	// GetMempoolInfoResult help.
-	"getmempoolinforesult-bytes": "Size in bytes of the mempool",
-	"getmempoolinforesult-size":  "Number of transactions in the mempool",
+	"getmempoolinforesult-bytes":         "Size in bytes of the mempool",
+	"getmempoolinforesult-size":          "Number of transactions in the mempool",
+	"getmempoolinforesult-maxmempool":    "Maximum size in bytes of the mempool (0 if unbounded)",
+	"getmempoolinforesult-mempoolminfee": "Minimum fee rate in DUO/kB for a transaction to be accepted, raised when the mempool is full",
+	"getmempoolinforesult-minrelaytxfee": "Configured minimum relay fee rate in DUO/kB",
 
 	// GetMiningInfoResult help.
 	"getmininginforesult-blocks":             "Height of the latest best block",
@@ -457,6 +575,36 @@ var HelpDescsEnUS = map[string]string{
 	"getnetworkhashps-height":    "Perform estimate ending with this height or -1 for current best chain block height",
 	"getnetworkhashps--result0":  "Estimated hashes per second",
 
+	// GetNetworkInfoCmd help.
+	"getnetworkinfo--synopsis": "Returns a JSON object containing various state info regarding P2P networking.",
+
+	// GetNetworkInfoResult help.
+	"getnetworkinforesult-version":         "The version of the node as a numeric",
+	"getnetworkinforesult-subversion":      "The user agent the node sends to its peers",
+	"getnetworkinforesult-protocolversion": "The latest supported protocol version",
+	"getnetworkinforesult-localservices":   "The services the node offers to the network as a hex string",
+	"getnetworkinforesult-localrelay":      "Whether transaction relay is requested from peers",
+	"getnetworkinforesult-timeoffset":      "The time offset in seconds",
+	"getnetworkinforesult-connections":     "The number of connections",
+	"getnetworkinforesult-networkactive":   "Whether p2p networking is enabled",
+	"getnetworkinforesult-networks":        "Information per network",
+	"getnetworkinforesult-relayfee":        "Minimum relay fee for transactions in DUO/kB",
+	"getnetworkinforesult-incrementalfee":  "Minimum fee increment for mempool limiting or replacement in DUO/kB",
+	"getnetworkinforesult-localaddresses":  "List of local addresses",
+	"getnetworkinforesult-warnings":        "Any network warnings",
+
+	// NetworksResult help.
+	"networksresult-name":                        "Network name (ipv4, ipv6 or onion)",
+	"networksresult-limited":                     "Whether the node is limited to connections on this network",
+	"networksresult-reachable":                   "Whether the network is reachable",
+	"networksresult-proxy":                       "The proxy that is used for this network, or empty if none",
+	"networksresult-proxy_randomize_credentials": "Whether randomized credentials are used for the proxy",
+
+	// LocalAddressesResult help.
+	"localaddressesresult-address": "Network address",
+	"localaddressesresult-port":    "Network port",
+	"localaddressesresult-score":   "Relative score",
+
 	// GetNetTotalsCmd help.
 	"getnettotals--synopsis": "Returns a JSON object containing network traffic statistics.",
 
@@ -531,6 +679,36 @@ var HelpDescsEnUS = map[string]string{
 	"gettxout-vout":           "The index of the output",
 	"gettxout-includemempool": "Include the mempool when true",
 
+	// GetTxOutSetInfoCmd help.
+	"gettxoutsetinfo--synopsis": "Returns statistics about the unspent transaction output set.\n" +
+		"This may take some time as the whole set is read.",
+
+	// GetTxOutSetInfoResult help.
+	"gettxoutsetinforesult-height":           "The height of the best block",
+	"gettxoutsetinforesult-bestblock":        "The hash of the best block",
+	"gettxoutsetinforesult-transactions":     "The number of transactions with unspent outputs",
+	"gettxoutsetinforesult-txouts":           "The number of unspent transaction outputs",
+	"gettxoutsetinforesult-bytes_serialized": "The size of the serialized utxo set in bytes",
+	"gettxoutsetinforesult-hash_serialized":  "The hash of the serialized utxo set, which is the same on every node at the same best block",
+	"gettxoutsetinforesult-total_amount":     "The total amount of the unspent transaction outputs in DUO",
+
+	// GetWorkerStatsCmd help.
+	"getworkerstats--synopsis": "Returns the hashrate and share counts of each kopach miner the miner controller has heard from, per algorithm.",
+
+	// GetWorkerStatsResult help.
+	"getworkerstatsresult-id":       "The ID the miner reports its work under",
+	"getworkerstatsresult-address":  "The address the miner was last heard from",
+	"getworkerstatsresult-lastseen": "The time the miner was last heard from in seconds since 1 Jan 1970 GMT",
+	"getworkerstatsresult-algos":    "The work of the miner on each algorithm",
+
+	// WorkerAlgoStatsResult help.
+	"workeralgostatsresult-algo":         "The name of the algorithm",
+	"workeralgostatsresult-hashespersec": "The average hashrate of the miner on the algorithm",
+	"workeralgostatsresult-hashes":       "The number of hashes the miner has reported",
+	"workeralgostatsresult-accepted":     "The number of shares accepted",
+	"workeralgostatsresult-stale":        "The number of shares on a block that is no longer the best block",
+	"workeralgostatsresult-invalid":      "The number of shares that did not meet the share target or were duplicates",
+
 	// HelpCmd help.
 	"help--synopsis":   "Returns a list of all commands or help for a specified command.",
 	"help-command":     "The command to retrieve help for",
@@ -539,10 +717,34 @@ var HelpDescsEnUS = map[string]string{
 	"help--result0":    "List of commands",
 	"help--result1":    "Help for specified command",
 
+	// InvalidateBlockCmd help.
+	"invalidateblock--synopsis": "Permanently marks a block as invalid, as if it violated a consensus rule.\n" +
+		"If the block is in the main chain the chain is reorganized onto the best remaining valid branch.",
+	"invalidateblock-blockhash": "The hash of the block to mark as invalid",
+
+	// LoadTxOutSetCmd help.
+	"loadtxoutset--synopsis": "Makes a node that has only the genesis block start from a utxo set snapshot written by dumptxoutset.\n" +
+		"The headers of the snapshot are checked, but its utxo set is trusted until the blocks below it have been downloaded and validated in the background. The optional indexes must be disabled.",
+	"loadtxoutset-path": "The file to read the snapshot from, relative to the data directory of the network if not absolute",
+	"loadtxoutset-hash": "The hash_serialized the snapshot must have, as reported by a trusted node",
+
 	// PingCmd help.
 	"ping--synopsis": "Queues a ping to be sent to each connected peer.\n" +
 		"Ping times are provided by getpeerinfo via the pingtime and pingwait fields.",
 
+	// PreciousBlockCmd help.
+	"preciousblock--synopsis": "Treats a block as if it were received before others with the same work.\n" +
+		"A later preciousblock call can override the effect of an earlier one.",
+	"preciousblock-blockhash": "The hash of the block to mark as precious",
+
+	// ReconsiderBlockCmd help.
+	"reconsiderblock--synopsis": "Removes invalidity status of a block, its ancestors and its descendants, reconsidering them for activation.\n" +
+		"This can be used to undo the effects of invalidateblock.",
+	"reconsiderblock-blockhash": "The hash of the block to reconsider",
+
+	// SaveMempoolCmd help.
+	"savemempool--synopsis": "Dumps the mempool and fee estimator state to disk so they can be restored when the node restarts.",
+
 	// SearchRawTransactionsCmd help.
 	"searchrawtransactions--synopsis": "Returns raw data for transactions involving the passed address.\n" +
 		"Returned transactions are pulled from both the database, and transactions currently in the mempool.\n" +
@@ -705,9 +907,16 @@ var ResultTypes = map[string][]interface{}{
 	"debuglevel":            {(*string)(nil), (*string)(nil)},
 	"decoderawtransaction":  {(*btcjson.TxRawDecodeResult)(nil)},
 	"decodescript":          {(*btcjson.DecodeScriptResult)(nil)},
+	"dumptxoutset":          {(*btcjson.TxOutSetSnapshotResult)(nil)},
 	"estimatefee":           {(*float64)(nil)},
+	"estimatepriority":      {(*float64)(nil)},
 	"generate":              {(*[]string)(nil)},
 	"getaddednodeinfo":      {(*[]string)(nil), (*[]btcjson.GetAddedNodeInfoResult)(nil)},
+	"getaddressbalance":     {(*btcjson.GetAddressBalanceResult)(nil)},
+	"getaddressdeltas":      {(*[]btcjson.GetAddressDeltasResult)(nil)},
+	"getaddressmempool":     {(*[]btcjson.GetAddressMempoolResult)(nil)},
+	"getaddressutxos":       {(*[]btcjson.GetAddressUtxosResult)(nil)},
+	"getalgostats":          {(*btcjson.GetAlgoStatsResult)(nil)},
 	"getbestblock":          {(*btcjson.GetBestBlockResult)(nil)},
 	"getbestblockhash":      {(*string)(nil)},
 	"getblock":              {(*string)(nil), (*btcjson.GetBlockVerboseResult)(nil)},
@@ -718,6 +927,7 @@ var ResultTypes = map[string][]interface{}{
 	"getblockchaininfo":     {(*btcjson.GetBlockChainInfoResult)(nil)},
 	"getcfilter":            {(*string)(nil)},
 	"getcfilterheader":      {(*string)(nil)},
+	"getchaintips":          {(*[]btcjson.GetChainTipsResult)(nil)},
 	"getconnectioncount":    {(*int32)(nil)},
 	"getcurrentnet":         {(*uint32)(nil)},
 	"getdifficulty":         {(*float64)(nil)},
@@ -725,17 +935,26 @@ var ResultTypes = map[string][]interface{}{
 	"gethashespersec":       {(*float64)(nil)},
 	"getheaders":            {(*[]string)(nil)},
 	"getinfo":               {(*btcjson.InfoChainResult)(nil)},
+	"getmempoolentry":       {(*btcjson.GetMempoolEntryResult)(nil)},
 	"getmempoolinfo":        {(*btcjson.GetMempoolInfoResult)(nil)},
 	"getmininginfo":         {(*btcjson.GetMiningInfoResult)(nil)},
 	"getnettotals":          {(*btcjson.GetNetTotalsResult)(nil)},
 	"getnetworkhashps":      {(*int64)(nil)},
+	"getnetworkinfo":        {(*btcjson.GetNetworkInfoResult)(nil)},
 	"getpeerinfo":           {(*[]btcjson.GetPeerInfoResult)(nil)},
 	"getrawmempool":         {(*[]string)(nil), (*btcjson.GetRawMempoolVerboseResult)(nil)},
 	"getrawtransaction":     {(*string)(nil), (*btcjson.TxRawResult)(nil)},
 	"gettxout":              {(*btcjson.GetTxOutResult)(nil)},
+	"gettxoutsetinfo":       {(*btcjson.GetTxOutSetInfoResult)(nil)},
+	"getworkerstats":        {(*[]btcjson.GetWorkerStatsResult)(nil)},
 	"node":                  nil,
 	"help":                  {(*string)(nil), (*string)(nil)},
+	"invalidateblock":       nil,
+	"loadtxoutset":          {(*btcjson.TxOutSetSnapshotResult)(nil)},
 	"ping":                  nil,
+	"preciousblock":         nil,
+	"reconsiderblock":       nil,
+	"savemempool":           nil,
 	"searchrawtransactions": {(*string)(nil), (*[]btcjson.SearchRawTransactionsResult)(nil)},
 	"sendrawtransaction":    {(*string)(nil)},
 	"setgenerate":           nil,
@@ -13,6 +13,7 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
@@ -120,6 +121,7 @@ type (
 		BytesReceived        uint64 // Total bytes received from all peers since start.
 		BytesSent            uint64 // Total bytes sent by all peers since start.
 		StartupTime          int64
+		CmpctHighBandwidth   int32 // Number of peers asked to announce new blocks as compact blocks.
 		ChainParams          *netparams.Params
 		AddrManager          *addrmgr.AddrManager
 		ConnManager          *connmgr.ConnManager
@@ -148,12 +150,16 @@ type (
 		//
 		// These fields are set during initial creation of the server and never changed afterwards, so they do not need
 		// to be protected for concurrent access.
-		TxIndex   *indexers.TxIndex
-		AddrIndex *indexers.AddrIndex
-		CFIndex   *indexers.CFIndex
+		TxIndex        *indexers.TxIndex
+		AddrIndex      *indexers.AddrIndex
+		AddrUtxoIndex  *indexers.AddrUtxoIndex
+		AlgoStatsIndex *indexers.AlgoStatsIndex
+		CFIndex        *indexers.CFIndex
 		// The fee estimator keeps track of how long transactions are left in the mempool before they are mined into
 		// blocks.
 		FeeEstimator *mempool.FeeEstimator
+		// MempoolFile is the path of the file the mempool is saved to on shutdown and restored from on startup.
+		MempoolFile string
 		// CFCheckptCaches stores a cached slice of filter headers for cfcheckpt messages for each filter type.
 		CFCheckptCaches    map[wire.FilterType][]CFHeaderKV
 		CFCheckptCachesMtx sync.RWMutex
@@ -186,6 +192,9 @@ type (
 		IsWhitelisted  bool
 		Persistent     bool
 		DisableRelayTx bool
+		// CmpctHighBandwidth is set when the peer was asked to announce new blocks as compact blocks. It is set by the
+		// input handler of the peer and read by the peer handler when it is done.
+		CmpctHighBandwidth uberatomic.Bool
 	}
 	// SimpleAddr implements the net.Addr interface with two struct fields
 	SimpleAddr struct {
@@ -215,6 +224,13 @@ const (
 	// ConnectionRetryInterval is the base amount of time to wait in between retries when connecting to persistent
 	// peers. It is adjusted by the number of retries such that there is a retry backoff.
 	ConnectionRetryInterval = time.Second
+	// MaxCmpctHighBandwidthPeers is the maximum number of peers that are asked to announce new blocks by sending their
+	// compact block right away (high bandwidth mode). Other peers supporting compact blocks announce them with an inv
+	// or headers message first.
+	MaxCmpctHighBandwidthPeers = 3
+	// MaxCmpctBlockDepth is how far below the best block a block may be to be served as a compact block. Peers are sent
+	// deeper blocks in full, as they are unlikely to have their transactions in the memory pool.
+	MaxCmpctBlockDepth = 10
 )
 
 var (
@@ -472,17 +488,8 @@ func (n *Node) Stop() (err error) {
 			}
 		}
 	}
-	// Save fee estimator state in the database.
-	if err = n.DB.Update(
-		func(tx database.Tx) error {
-			metadata := tx.Metadata()
-			err := metadata.Put(mempool.EstimateFeeDatabaseKey, n.FeeEstimator.Save())
-			if err != nil {
-				Error(err)
-			}
-			return nil
-		},
-	); Check(err) {
+	// Save the mempool and the fee estimator state so they survive the restart.
+	if err = n.TxMemPool.SaveFile(n.MempoolFile); Check(err) {
 	}
 	// Stop the CPU miner if needed
 	// consume.Kill(n.StateCfg.Miner)
@@ -610,6 +617,9 @@ func (n *Node) HandleBroadcastMsg(state *PeerState, bmsg *BroadcastMsg) {
 
 // HandleDonePeerMsg deals with peers that have signalled they are done. It is invoked from the peerHandler goroutine.
 func (n *Node) HandleDonePeerMsg(state *PeerState, sp *NodePeer) {
+	if sp.CmpctHighBandwidth.Load() {
+		atomic.AddInt32(&n.CmpctHighBandwidth, -1)
+	}
 	var list map[int32]*NodePeer
 	switch {
 	case sp.Persistent:
@@ -781,11 +791,30 @@ func (n *Node) HandleQuery(state *PeerState, querymsg interface{}) {
 // HandleRelayInvMsg deals with relaying inventory to peers that are not already known to have it. It is invoked from
 // the peerHandler goroutine.
 func (n *Node) HandleRelayInvMsg(state *PeerState, msg RelayMsg) {
+	// The compact block sent to peers in high bandwidth mode is only made once it is needed.
+	var cmpctBlock *wire.MsgCmpctBlock
 	state.ForAllPeers(
 		func(sp *NodePeer) {
 			if !sp.Connected() {
 				return
 			}
+			// If the inventory is a block and the peer asked for new blocks as compact blocks, send it the compact block
+			// right away.
+			if msg.InvVect.Type == wire.InvTypeBlock && sp.WantsCompactAnnounce() {
+				if sp.HasKnownInventory(msg.InvVect) {
+					return
+				}
+				if cmpctBlock == nil {
+					var err error
+					if cmpctBlock, err = n.NewCmpctBlock(&msg.InvVect.Hash); err != nil {
+						Error("failed to make compact block:", err)
+						return
+					}
+				}
+				sp.AddKnownInventory(msg.InvVect)
+				sp.QueueMessage(cmpctBlock, nil)
+				return
+			}
 			// If the inventory is a block and the peer prefers headers, generate and send a headers message instead of an
 			// inventory message.
 			if msg.InvVect.Type == wire.InvTypeBlock && sp.WantsHeaders() {
@@ -1069,6 +1098,53 @@ func (n *Node) PushBlockMsg(
 	return nil
 }
 
+// NewCmpctBlock returns a compact block of the main chain block with the given hash, made with a random nonce.
+func (n *Node) NewCmpctBlock(hash *chainhash.Hash) (*wire.MsgCmpctBlock, error) {
+	block, err := n.Chain.BlockByHash(hash)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := wire.RandomUint64()
+	if err != nil {
+		return nil, err
+	}
+	return wire.NewMsgCmpctBlock(block.MsgBlock(), nonce), nil
+}
+
+// PushCmpctBlockMsg sends a cmpctblock message for the provided block hash to the connected peer, or a block message
+// if the block is more than MaxCmpctBlockDepth below the best block. An error is returned if the block hash is not
+// known.
+func (n *Node) PushCmpctBlockMsg(
+	sp *NodePeer, hash *chainhash.Hash,
+	doneChan chan<- struct{}, waitChan qu.C,
+) error {
+	height, err := sp.Server.Chain.BlockHeightByHash(hash)
+	if err == nil && height < sp.Server.Chain.BestSnapshot().Height-MaxCmpctBlockDepth {
+		encoding := wire.BaseEncoding
+		if sp.IsWitnessEnabled() {
+			encoding = wire.WitnessEncoding
+		}
+		return n.PushBlockMsg(sp, hash, doneChan, waitChan, encoding)
+	}
+	cmpctBlock, err := n.NewCmpctBlock(hash)
+	if err != nil {
+		Errorf(
+			"unable to make compact block of requested block hash %v: %v",
+			hash, err,
+		)
+		if doneChan != nil {
+			doneChan <- struct{}{}
+		}
+		return err
+	}
+	// Once we have fetched data wait for any previous operation to finish.
+	if waitChan != nil {
+		<-waitChan
+	}
+	sp.QueueMessage(cmpctBlock, doneChan)
+	return nil
+}
+
 // PushMerkleBlockMsg sends a merkleblock message for the provided block hash to the connected peer. Since a merkle
 // block requires the peer to have a filter loaded, this call will simply be ignored if there is no filter loaded.
 //
@@ -1332,6 +1408,20 @@ func (np *NodePeer) OnBlock(_ *peer.Peer, msg *wire.MsgBlock, buf []byte) {
 	<-np.BlockProcessed
 }
 
+// OnBlockTxn is invoked when a peer receives a blocktxn bitcoin message, which carries the transactions of a compact
+// block that were requested from the peer. It blocks until the completed block has been fully processed.
+func (np *NodePeer) OnBlockTxn(_ *peer.Peer, msg *wire.MsgBlockTxn) {
+	np.Server.SyncManager.QueueBlockTxn(msg, np.Peer, np.BlockProcessed)
+	<-np.BlockProcessed
+}
+
+// OnCmpctBlock is invoked when a peer receives a cmpctblock bitcoin message. It blocks until the block has been fully
+// processed, or the transactions missing to rebuild it have been requested.
+func (np *NodePeer) OnCmpctBlock(_ *peer.Peer, msg *wire.MsgCmpctBlock) {
+	np.Server.SyncManager.QueueCmpctBlock(msg, np.Peer, np.BlockProcessed)
+	<-np.BlockProcessed
+}
+
 // OnFeeFilter is invoked when a peer receives a feefilter bitcoin message and is used by remote peers to request that
 // no transactions which have a fee rate lower than provided value are inventoried to them. The peer will be
 // disconnected if an invalid fee filter value is provided.
@@ -1436,6 +1526,45 @@ func (np *NodePeer) OnGetAddr(
 	np.PreparePushAddrMsg(addrCache)
 }
 
+// OnGetBlockTxn is invoked when a peer receives a getblocktxn bitcoin message and is used to send the transactions of a
+// block the peer could not find in its memory pool while rebuilding the block from its compact block. Blocks more than
+// MaxCmpctBlockDepth below the best block are sent in full instead.
+func (np *NodePeer) OnGetBlockTxn(_ *peer.Peer, msg *wire.MsgGetBlockTxn) {
+	chain := np.Server.Chain
+	height, err := chain.BlockHeightByHash(&msg.BlockHash)
+	if err != nil {
+		Debugf("unable to serve transactions of unknown block %v to %s", msg.BlockHash, np)
+		return
+	}
+	if height < chain.BestSnapshot().Height-MaxCmpctBlockDepth {
+		encoding := wire.BaseEncoding
+		if np.IsWitnessEnabled() {
+			encoding = wire.WitnessEncoding
+		}
+		if err = np.Server.PushBlockMsg(np, &msg.BlockHash, nil, nil, encoding); err != nil {
+			Error(err)
+		}
+		return
+	}
+	block, err := chain.BlockByHash(&msg.BlockHash)
+	if err != nil {
+		Error(err)
+		return
+	}
+	txns := block.MsgBlock().Transactions
+	blockTxn := wire.NewMsgBlockTxn(&msg.BlockHash, make([]*wire.MsgTx, 0, len(msg.Indexes)))
+	for _, index := range msg.Indexes {
+		if int(index) >= len(txns) {
+			Warnf("peer %s asked for transaction %d of block %v with %d transactions -- disconnecting",
+				np, index, msg.BlockHash, len(txns))
+			np.Disconnect()
+			return
+		}
+		blockTxn.Transactions = append(blockTxn.Transactions, txns[index])
+	}
+	np.QueueMessage(blockTxn, nil)
+}
+
 // OnGetBlocks is invoked when a peer receives a getblocks bitcoin message.
 func (np *NodePeer) OnGetBlocks(
 	_ *peer.Peer,
@@ -1485,10 +1614,7 @@ func (np *NodePeer) OnGetCFCheckpt(
 	}
 	// We'll also ensure that the remote party is requesting a set of checkpoints for filters that we actually currently
 	// maintain.
-	switch msg.FilterType {
-	case wire.GCSFilterRegular:
-		break
-	default:
+	if !np.Server.CFIndex.HasFilterType(msg.FilterType) {
 		Debug(
 			"filter request for unknown checkpoints for filter:",
 			msg.FilterType,
@@ -1611,10 +1737,7 @@ func (np *NodePeer) OnGetCFHeaders(
 	}
 	// We'll also ensure that the remote party is requesting a set of headers for filters that we actually currently
 	// maintain.
-	switch msg.FilterType {
-	case wire.GCSFilterRegular:
-		break
-	default:
+	if !np.Server.CFIndex.HasFilterType(msg.FilterType) {
 		Debug("filter request for unknown headers for filter:", msg.FilterType)
 		return
 	}
@@ -1709,10 +1832,7 @@ func (np *NodePeer) OnGetCFilters(
 		return
 	}
 	// We'll also ensure that the remote party is requesting a set of filters that we actually currently maintain.
-	switch msg.FilterType {
-	case wire.GCSFilterRegular:
-		break
-	default:
+	if !np.Server.CFIndex.HasFilterType(msg.FilterType) {
 		Debug("filter request for unknown filter:", msg.FilterType)
 		return
 	}
@@ -1800,6 +1920,8 @@ func (np *NodePeer) OnGetData(_ *peer.Peer, msg *wire.MsgGetData) {
 				np, &iv.Hash, c, waitChan,
 				wire.BaseEncoding,
 			)
+		case wire.InvTypeCmpctBlock:
+			err = np.Server.PushCmpctBlockMsg(np, &iv.Hash, c, waitChan)
 		case wire.InvTypeFilteredWitnessBlock:
 			err = np.Server.PushMerkleBlockMsg(
 				np, &iv.Hash, c, waitChan,
@@ -1990,6 +2112,23 @@ func (np *NodePeer) OnTx(
 	<-np.TxProcessed
 }
 
+// OnVerAck is invoked when a peer receives a verack bitcoin message and is used to offer compact block relay to peers
+// that support it. The first outbound peers are asked to announce new blocks as compact blocks right away, up to
+// MaxCmpctHighBandwidthPeers of them.
+func (np *NodePeer) OnVerAck(_ *peer.Peer, msg *wire.MsgVerAck) {
+	if np.ProtocolVersion() < wire.CompactBlocksVersion {
+		return
+	}
+	if !np.Inbound() {
+		if atomic.AddInt32(&np.Server.CmpctHighBandwidth, 1) <= MaxCmpctHighBandwidthPeers {
+			np.CmpctHighBandwidth.Store(true)
+		} else {
+			atomic.AddInt32(&np.Server.CmpctHighBandwidth, -1)
+		}
+	}
+	np.QueueMessage(wire.NewMsgSendCmpct(np.CmpctHighBandwidth.Load(), wire.CompactBlocksProtocolVersion), nil)
+}
+
 // OnVersion is invoked when a peer receives a version bitcoin message and is used to negotiate the protocol version
 // details as well as kick start the communications.
 func (np *NodePeer) OnVersion(
@@ -2519,9 +2658,13 @@ NewPeerConfig(sp *NodePeer) *peer.Config {
 	return &peer.Config{
 		Listeners: peer.MessageListeners{
 			OnVersion:      sp.OnVersion,
+			OnVerAck:       sp.OnVerAck,
 			OnMemPool:      sp.OnMemPool,
 			OnTx:           sp.OnTx,
 			OnBlock:        sp.OnBlock,
+			OnCmpctBlock:   sp.OnCmpctBlock,
+			OnBlockTxn:     sp.OnBlockTxn,
+			OnGetBlockTxn:  sp.OnGetBlockTxn,
 			OnInv:          sp.OnInv,
 			OnHeaders:      sp.OnHeaders,
 			OnGetData:      sp.OnGetData,
@@ -2581,6 +2724,43 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 	if *cx.Config.NoCFilters {
 		services &^= wire.SFNodeCF
 	}
+	// A node that deletes old blocks cannot serve the whole chain, so it only advertises serving the recent blocks.
+	var prune uint64
+	if *cx.Config.Prune > 0 {
+		if *cx.Config.Prune < blockchain.MinPruneTarget/1024/1024 {
+			return nil, fmt.Errorf(
+				"prune target of %d MB is below the minimum of %d MB", *cx.Config.Prune,
+				blockchain.MinPruneTarget/1024/1024,
+			)
+		}
+		prune = uint64(*cx.Config.Prune) * 1024 * 1024
+	}
+	pruned, err := db.BeenPruned()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	snapshot, err := blockchain.SnapshotBase(db)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if prune > 0 || pruned || snapshot != nil {
+		if *cx.Config.TxIndex || *cx.Config.AddrIndex || *cx.Config.AddrUtxoIndex || *cx.Config.AlgoStatsIndex {
+			return nil, errors.New(
+				"the transaction, address and algo stats indexes cannot be built from a pruned database, disable" +
+					" them with --txindex=false --addrindex=false --addrutxoindex=false --algostatsindex=false",
+			)
+		}
+		// The blocks below a utxo set snapshot were never stored, so the filter index cannot catch up either.
+		if snapshot != nil && !*cx.Config.NoCFilters {
+			return nil, errors.New(
+				"the committed filter index cannot be built from a utxo set snapshot, disable it with --nocfilters",
+			)
+		}
+		services &^= wire.SFNodeNetwork
+		services |= wire.SFNodeNetworkLimited
+	}
 	aMgr := addrmgr.New(*cx.Config.DataDir+string(os.PathSeparator)+cx.ActiveNet.Name, Lookup(cx.StateCfg))
 	var listeners []net.Listener
 	var nat upnp.NAT
@@ -2651,6 +2831,16 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 		s.AddrIndex = indexers.NewAddrIndex(db, cx.ActiveNet)
 		indexes = append(indexes, s.AddrIndex)
 	}
+	if *cx.Config.AddrUtxoIndex {
+		Info("address utxo index is enabled")
+		s.AddrUtxoIndex = indexers.NewAddrUtxoIndex(db, cx.ActiveNet)
+		indexes = append(indexes, s.AddrUtxoIndex)
+	}
+	if *cx.Config.AlgoStatsIndex {
+		Info("algo stats index is enabled")
+		s.AlgoStatsIndex = indexers.NewAlgoStatsIndex(db)
+		indexes = append(indexes, s.AlgoStatsIndex)
+	}
 	if !*cx.Config.NoCFilters {
 		Trace("committed filter index is enabled")
 		s.CFIndex = indexers.NewCfIndex(db, cx.ActiveNet)
@@ -2668,8 +2858,19 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 			s.ChainParams.Checkpoints, cx.StateCfg.AddedCheckpoints,
 		)
 	}
+	// Use the network's assumed valid block unless another is given or script checks of all blocks are asked for.
+	assumeValid := s.ChainParams.AssumeValid
+	switch {
+	case *cx.Config.AssumeValid == "0":
+		assumeValid = nil
+	case cx.StateCfg.AssumeValid != nil:
+		assumeValid = cx.StateCfg.AssumeValid
+	}
+	if assumeValid != nil {
+		Infof("assuming the ancestors of block %v (height %d) have valid scripts", assumeValid.Hash,
+			assumeValid.Height)
+	}
 	// Create a new block chain instance with the appropriate configuration.
-	var err error
 	s.Chain, err = blockchain.New(
 		&blockchain.Config{
 			DB:           s.DB,
@@ -2680,6 +2881,8 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 			SigCache:     s.SigCache,
 			IndexManager: indexManager,
 			HashCache:    s.HashCache,
+			Prune:        prune,
+			AssumeValid:  assumeValid,
 		},
 	)
 	if err != nil {
@@ -2688,9 +2891,30 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 	}
 	s.Chain.DifficultyAdjustments = make(map[string]float64)
 	s.Chain.DifficultyBits.Store(make(blockchain.TargetBits))
-	// Search for a FeeEstimator state in the database. If none can be found or if it cannot be loaded, create a new
-	// one.
-	e := db.Update(
+	// Build the committed filters of a type for the existing chain if requested, before it is extended.
+	if cx.StateCfg.BuildCfIndex {
+		if s.CFIndex == nil {
+			return nil, errors.New("cannot build committed filters while the committed filter index is disabled")
+		}
+		if err = s.CFIndex.BuildFilterType(s.Chain, cx.StateCfg.BuildCfIndexType, interruptChan); err != nil {
+			Error(err)
+			return nil, err
+		}
+	}
+	// Read the mempool saved at the last shutdown, which also carries the FeeEstimator state.
+	s.MempoolFile = filepath.Join(*cx.Config.DataDir, cx.ActiveNet.Name, mempool.DumpFileName)
+	poolDump, e := mempool.ReadDumpFile(s.MempoolFile)
+	if e != nil && !os.IsNotExist(e) {
+		Warn("failed to read saved mempool", e)
+	}
+	if poolDump != nil && poolDump.FeeEstimator != nil {
+		if s.FeeEstimator, e = mempool.RestoreFeeEstimator(poolDump.FeeEstimator); e != nil {
+			Warn("failed to restore fee estimator", e)
+		}
+	}
+	// Older versions stored the FeeEstimator state in the database. If none could be loaded from the mempool file, use
+	// that instead. If it cannot be loaded either, create a new one.
+	e = db.Update(
 		func(tx database.Tx) error {
 			metadata := tx.Metadata()
 			feeEstimationData := metadata.Get(mempool.EstimateFeeDatabaseKey)
@@ -2700,6 +2924,9 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 				if e != nil {
 					return e
 				}
+				if s.FeeEstimator != nil {
+					return nil
+				}
 				// If there is an error, log it and make a new fee estimator.
 				var err error
 				s.FeeEstimator, err = mempool.RestoreFeeEstimator(feeEstimationData)
@@ -2733,6 +2960,11 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 			MaxSigOpCostPerTx:    blockchain.MaxBlockSigOpsCost / 4,
 			MinRelayTxFee:        cx.StateCfg.ActiveMinRelayTxFee,
 			MaxTxVersion:         2,
+			MaxPoolSize:          int64(*cx.Config.MaxMempool) * 1000000,
+			RejectReplacement:    *cx.Config.RejectReplacement,
+			MaxAncestorCount:     mempool.DefaultMaxAncestorCount,
+			MaxAncestorSize:      mempool.DefaultMaxAncestorSize,
+			BlockPrioritySize:    int64(*cx.Config.BlockPrioritySize),
 		},
 		ChainParams:   cx.ActiveNet,
 		FetchUtxoView: s.Chain.FetchUtxoView,
@@ -2751,9 +2983,14 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 		SigCache:           s.SigCache,
 		HashCache:          s.HashCache,
 		AddrIndex:          s.AddrIndex,
+		AddrUtxoIndex:      s.AddrUtxoIndex,
 		FeeEstimator:       s.FeeEstimator,
 	}
 	s.TxMemPool = mempool.New(&txC)
+	if poolDump != nil {
+		restored := s.TxMemPool.Restore(s.Chain, poolDump)
+		Infof("restored %d of %d saved mempool transactions", restored, len(poolDump.Entries))
+	}
 	s.SyncManager, err =
 		netsync.New(
 			&netsync.Config{
@@ -2901,13 +3138,16 @@ func NewNode(listenAddrs []string, db database.DB, interruptChan qu.C, cx *Conte
 					TxMemPool:   s.TxMemPool,
 					// Generator:    blockTemplateGenerator,
 					// CPUMiner:     s.CPUMiner,
-					TxIndex:      s.TxIndex,
-					AddrIndex:    s.AddrIndex,
-					CfIndex:      s.CFIndex,
-					FeeEstimator: s.FeeEstimator,
-					Algo:         l,
-					Hashrate:     cx.Hashrate,
-					Quit:         s.Quit,
+					TxIndex:        s.TxIndex,
+					AddrIndex:      s.AddrIndex,
+					AddrUtxoIndex:  s.AddrUtxoIndex,
+					AlgoStatsIndex: s.AlgoStatsIndex,
+					CfIndex:        s.CFIndex,
+					FeeEstimator:   s.FeeEstimator,
+					MempoolFile:    s.MempoolFile,
+					Algo:           l,
+					Hashrate:       cx.Hashrate,
+					Quit:           s.Quit,
 				}, cx.StateCfg, cx.Config,
 			)
 			if err != nil {
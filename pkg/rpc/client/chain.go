@@ -8,6 +8,7 @@ import (
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
 	"github.com/p9c/pod/pkg/chain/wire"
 	"github.com/p9c/pod/pkg/rpc/btcjson"
+	"github.com/p9c/pod/pkg/util"
 )
 
 // FutureGetBestBlockHashResult is a future promise to deliver the result of a GetBestBlockAsync RPC invocation (or an
@@ -528,6 +529,41 @@ func (c *Client) EstimateFee(numBlocks int64) (float64, error) {
 	return c.EstimateFeeAsync(numBlocks).Receive()
 }
 
+// FutureEstimatePriorityResult is a future promise to deliver the result of a EstimatePriorityAsync RPC invocation (or
+// an applicable error).
+type FutureEstimatePriorityResult chan *response
+
+// Receive waits for the response promised by the future and returns the estimated priority.
+func (r FutureEstimatePriorityResult) Receive() (float64, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return -1, err
+	}
+	// Unmarshal result as a float64.
+	var priority float64
+	err = js.Unmarshal(res, &priority)
+	if err != nil {
+		Error(err)
+		return -1, err
+	}
+	return priority, nil
+}
+
+// EstimatePriorityAsync returns an instance of a type that can be used to get the result of the RPC at some future time
+// by invoking the Receive function on the returned instance. See EstimatePriority for the blocking version and more
+// details.
+func (c *Client) EstimatePriorityAsync(numBlocks int64) FutureEstimatePriorityResult {
+	cmd := btcjson.NewEstimatePriorityCmd(numBlocks)
+	return c.sendCmd(cmd)
+}
+
+// EstimatePriority provides an estimate of the priority a zero-fee transaction needs to be mined within numBlocks
+// blocks.
+func (c *Client) EstimatePriority(numBlocks int64) (float64, error) {
+	return c.EstimatePriorityAsync(numBlocks).Receive()
+}
+
 // FutureVerifyChainResult is a future promise to deliver the result of a VerifyChainAsync, VerifyChainLevelAsyncRPC, or
 // VerifyChainBlocksAsync invocation (or an applicable error).
 type FutureVerifyChainResult chan *response
@@ -636,6 +672,91 @@ func (c *Client) GetTxOut(txHash *chainhash.Hash, index uint32, mempool bool) (*
 	return c.GetTxOutAsync(txHash, index, mempool).Receive()
 }
 
+// FutureGetTxOutSetInfoResult is a future promise to deliver the result of a GetTxOutSetInfoAsync RPC invocation (or
+// an applicable error).
+type FutureGetTxOutSetInfoResult chan *response
+
+// Receive waits for the response promised by the future and returns the statistics of the utxo set.
+func (r FutureGetTxOutSetInfoResult) Receive() (*btcjson.GetTxOutSetInfoResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as a gettxoutsetinfo result object.
+	var txOutSetInfo btcjson.GetTxOutSetInfoResult
+	err = js.Unmarshal(res, &txOutSetInfo)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &txOutSetInfo, nil
+}
+
+// GetTxOutSetInfoAsync returns an instance of a type that can be used to get the result of the RPC at some future time
+// by invoking the Receive function on the returned instance. See GetTxOutSetInfo for the blocking version and more
+// details.
+func (c *Client) GetTxOutSetInfoAsync() FutureGetTxOutSetInfoResult {
+	cmd := btcjson.NewGetTxOutSetInfoCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetTxOutSetInfo returns statistics about the utxo set, including a hash of it that is the same on every node with the
+// same best block.
+func (c *Client) GetTxOutSetInfo() (*btcjson.GetTxOutSetInfoResult, error) {
+	return c.GetTxOutSetInfoAsync().Receive()
+}
+
+// FutureTxOutSetSnapshotResult is a future promise to deliver the result of a DumpTxOutSetAsync or LoadTxOutSetAsync
+// RPC invocation (or an applicable error).
+type FutureTxOutSetSnapshotResult chan *response
+
+// Receive waits for the response promised by the future and returns the summary of the utxo set snapshot.
+func (r FutureTxOutSetSnapshotResult) Receive() (*btcjson.TxOutSetSnapshotResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var snapshot btcjson.TxOutSetSnapshotResult
+	err = js.Unmarshal(res, &snapshot)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// DumpTxOutSetAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance. See DumpTxOutSet for the blocking version and more details.
+func (c *Client) DumpTxOutSetAsync(path string) FutureTxOutSetSnapshotResult {
+	cmd := btcjson.NewDumpTxOutSetCmd(path)
+	return c.sendCmd(cmd)
+}
+
+// DumpTxOutSet makes the node write a snapshot of its utxo set to the given path, which is relative to the data
+// directory of the node if not absolute.
+func (c *Client) DumpTxOutSet(path string) (*btcjson.TxOutSetSnapshotResult, error) {
+	return c.DumpTxOutSetAsync(path).Receive()
+}
+
+// LoadTxOutSetAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance. See LoadTxOutSet for the blocking version and more details.
+func (c *Client) LoadTxOutSetAsync(path string, hash *chainhash.Hash) FutureTxOutSetSnapshotResult {
+	var h *string
+	if hash != nil {
+		h = btcjson.String(hash.String())
+	}
+	cmd := btcjson.NewLoadTxOutSetCmd(path, h)
+	return c.sendCmd(cmd)
+}
+
+// LoadTxOutSet makes a node that has only the genesis block start from the utxo set snapshot at the given path. If hash
+// is not nil the snapshot must have it as its hash serialized.
+func (c *Client) LoadTxOutSet(path string, hash *chainhash.Hash) (*btcjson.TxOutSetSnapshotResult, error) {
+	return c.LoadTxOutSetAsync(path, hash).Receive()
+}
+
 // FutureRescanBlocksResult is a future promise to deliver the result of a RescanBlocksAsync RPC invocation (or an
 // applicable error).
 //
@@ -708,6 +829,113 @@ func (c *Client) InvalidateBlock(blockHash *chainhash.Hash) error {
 	return c.InvalidateBlockAsync(blockHash).Receive()
 }
 
+// FutureSaveMempoolResult is a future promise to deliver the result of a SaveMempoolAsync RPC invocation (or an
+// applicable error).
+type FutureSaveMempoolResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if the mempool could not be saved.
+func (r FutureSaveMempoolResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// SaveMempoolAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance. See SaveMempool for the blocking version and more details.
+func (c *Client) SaveMempoolAsync() FutureSaveMempoolResult {
+	cmd := btcjson.NewSaveMempoolCmd()
+	return c.sendCmd(cmd)
+}
+
+// SaveMempool asks the server to write its mempool to disk so it is restored when the server restarts.
+func (c *Client) SaveMempool() error {
+	return c.SaveMempoolAsync().Receive()
+}
+
+// FutureReconsiderBlockResult is a future promise to deliver the result of a ReconsiderBlockAsync RPC invocation (or an
+// applicable error).
+type FutureReconsiderBlockResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if the block could not be reconsidered.
+func (r FutureReconsiderBlockResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// ReconsiderBlockAsync returns an instance of a type that can be used to get the result of the RPC at some future time
+// by invoking the Receive function on the returned instance. See ReconsiderBlock for the blocking version and more
+// details.
+func (c *Client) ReconsiderBlockAsync(blockHash *chainhash.Hash) FutureReconsiderBlockResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+	cmd := btcjson.NewReconsiderBlockCmd(hash)
+	return c.sendCmd(cmd)
+}
+
+// ReconsiderBlock removes the invalid status from a block previously marked invalid by InvalidateBlock.
+func (c *Client) ReconsiderBlock(blockHash *chainhash.Hash) error {
+	return c.ReconsiderBlockAsync(blockHash).Receive()
+}
+
+// FuturePreciousBlockResult is a future promise to deliver the result of a PreciousBlockAsync RPC invocation (or an
+// applicable error).
+type FuturePreciousBlockResult chan *response
+
+// Receive waits for the response promised by the future and returns an error if the block could not be marked as
+// precious.
+func (r FuturePreciousBlockResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// PreciousBlockAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance. See PreciousBlock for the blocking version and more details.
+func (c *Client) PreciousBlockAsync(blockHash *chainhash.Hash) FuturePreciousBlockResult {
+	hash := ""
+	if blockHash != nil {
+		hash = blockHash.String()
+	}
+	cmd := btcjson.NewPreciousBlockCmd(hash)
+	return c.sendCmd(cmd)
+}
+
+// PreciousBlock treats a block as if it were received before others with the same work.
+func (c *Client) PreciousBlock(blockHash *chainhash.Hash) error {
+	return c.PreciousBlockAsync(blockHash).Receive()
+}
+
+// FutureGetChainTipsResult is a future promise to deliver the result of a GetChainTipsAsync RPC invocation (or an
+// applicable error).
+type FutureGetChainTipsResult chan *response
+
+// Receive waits for the response promised by the future and returns the chain tips known to the server.
+func (r FutureGetChainTipsResult) Receive() ([]btcjson.GetChainTipsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var chainTips []btcjson.GetChainTipsResult
+	if err := js.Unmarshal(res, &chainTips); err != nil {
+		return nil, err
+	}
+	return chainTips, nil
+}
+
+// GetChainTipsAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance. See GetChainTips for the blocking version and more details.
+func (c *Client) GetChainTipsAsync() FutureGetChainTipsResult {
+	cmd := btcjson.NewGetChainTipsCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetChainTips returns information about all known tips in the block tree, including the main chain and any side
+// chains.
+func (c *Client) GetChainTips() ([]btcjson.GetChainTipsResult, error) {
+	return c.GetChainTipsAsync().Receive()
+}
+
 // FutureGetCFilterResult is a future promise to deliver the result of a GetCFilterAsync RPC invocation (or an
 // applicable error).
 type FutureGetCFilterResult chan *response
@@ -806,3 +1034,183 @@ func (c *Client) GetCFilterHeader(blockHash *chainhash.Hash,
 	filterType wire.FilterType) (*wire.MsgCFHeaders, error) {
 	return c.GetCFilterHeaderAsync(blockHash, filterType).Receive()
 }
+
+// encodeAddresses returns the passed addresses encoded as strings.
+func encodeAddresses(addresses []util.Address) []string {
+	addrs := make([]string, 0, len(addresses))
+	for _, addr := range addresses {
+		addrs = append(addrs, addr.EncodeAddress())
+	}
+	return addrs
+}
+
+// FutureGetAddressBalanceResult is a future promise to deliver the result of a GetAddressBalanceAsync RPC invocation
+// (or an applicable error).
+type FutureGetAddressBalanceResult chan *response
+
+// Receive waits for the response promised by the future and returns the balance of the addresses.
+func (r FutureGetAddressBalanceResult) Receive() (*btcjson.GetAddressBalanceResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var balance btcjson.GetAddressBalanceResult
+	err = js.Unmarshal(res, &balance)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// GetAddressBalanceAsync returns an instance of a type that can be used to get the result of the RPC at some future
+// time by invoking the Receive function on the returned instance. See GetAddressBalance for the blocking version and
+// more details.
+func (c *Client) GetAddressBalanceAsync(addresses []util.Address) FutureGetAddressBalanceResult {
+	cmd := btcjson.NewGetAddressBalanceCmd(encodeAddresses(addresses))
+	return c.sendCmd(cmd)
+}
+
+// GetAddressBalance returns the balance of the passed addresses and the total they received, in satoshis. The server
+// must have the address utxo index enabled.
+func (c *Client) GetAddressBalance(addresses []util.Address) (*btcjson.GetAddressBalanceResult, error) {
+	return c.GetAddressBalanceAsync(addresses).Receive()
+}
+
+// FutureGetAddressDeltasResult is a future promise to deliver the result of a GetAddressDeltasAsync RPC invocation (or
+// an applicable error).
+type FutureGetAddressDeltasResult chan *response
+
+// Receive waits for the response promised by the future and returns the changes to the balance of the addresses.
+func (r FutureGetAddressDeltasResult) Receive() ([]btcjson.GetAddressDeltasResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var deltas []btcjson.GetAddressDeltasResult
+	err = js.Unmarshal(res, &deltas)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return deltas, nil
+}
+
+// GetAddressDeltasAsync returns an instance of a type that can be used to get the result of the RPC at some future time
+// by invoking the Receive function on the returned instance. See GetAddressDeltas for the blocking version and more
+// details.
+func (c *Client) GetAddressDeltasAsync(addresses []util.Address, start, end int32) FutureGetAddressDeltasResult {
+	cmd := btcjson.NewGetAddressDeltasCmd(encodeAddresses(addresses), &start, &end)
+	return c.sendCmd(cmd)
+}
+
+// GetAddressDeltas returns the changes transactions in the blocks from start to end height made to the balance of the
+// passed addresses, in chain order. An end of zero means up to the best block. The server must have the address utxo
+// index enabled.
+func (c *Client) GetAddressDeltas(addresses []util.Address, start, end int32) ([]btcjson.GetAddressDeltasResult, error) {
+	return c.GetAddressDeltasAsync(addresses, start, end).Receive()
+}
+
+// FutureGetAddressMempoolResult is a future promise to deliver the result of a GetAddressMempoolAsync RPC invocation
+// (or an applicable error).
+type FutureGetAddressMempoolResult chan *response
+
+// Receive waits for the response promised by the future and returns the changes the memory pool makes to the balance
+// of the addresses.
+func (r FutureGetAddressMempoolResult) Receive() ([]btcjson.GetAddressMempoolResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var deltas []btcjson.GetAddressMempoolResult
+	err = js.Unmarshal(res, &deltas)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return deltas, nil
+}
+
+// GetAddressMempoolAsync returns an instance of a type that can be used to get the result of the RPC at some future
+// time by invoking the Receive function on the returned instance. See GetAddressMempool for the blocking version and
+// more details.
+func (c *Client) GetAddressMempoolAsync(addresses []util.Address) FutureGetAddressMempoolResult {
+	cmd := btcjson.NewGetAddressMempoolCmd(encodeAddresses(addresses))
+	return c.sendCmd(cmd)
+}
+
+// GetAddressMempool returns the changes the transactions in the memory pool make to the balance of the passed
+// addresses. The server must have the address utxo index enabled.
+func (c *Client) GetAddressMempool(addresses []util.Address) ([]btcjson.GetAddressMempoolResult, error) {
+	return c.GetAddressMempoolAsync(addresses).Receive()
+}
+
+// FutureGetAddressUtxosResult is a future promise to deliver the result of a GetAddressUtxosAsync RPC invocation (or an
+// applicable error).
+type FutureGetAddressUtxosResult chan *response
+
+// Receive waits for the response promised by the future and returns the unspent outputs of the addresses.
+func (r FutureGetAddressUtxosResult) Receive() ([]btcjson.GetAddressUtxosResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var utxos []btcjson.GetAddressUtxosResult
+	err = js.Unmarshal(res, &utxos)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return utxos, nil
+}
+
+// GetAddressUtxosAsync returns an instance of a type that can be used to get the result of the RPC at some future time
+// by invoking the Receive function on the returned instance. See GetAddressUtxos for the blocking version and more
+// details.
+func (c *Client) GetAddressUtxosAsync(addresses []util.Address) FutureGetAddressUtxosResult {
+	cmd := btcjson.NewGetAddressUtxosCmd(encodeAddresses(addresses))
+	return c.sendCmd(cmd)
+}
+
+// GetAddressUtxos returns the unspent outputs paying to the passed addresses. The server must have the address utxo
+// index enabled.
+func (c *Client) GetAddressUtxos(addresses []util.Address) ([]btcjson.GetAddressUtxosResult, error) {
+	return c.GetAddressUtxosAsync(addresses).Receive()
+}
+
+// FutureGetAlgoStatsResult is a future promise to deliver the result of a GetAlgoStatsAsync RPC invocation (or an
+// applicable error).
+type FutureGetAlgoStatsResult chan *response
+
+// Receive waits for the response promised by the future and returns the statistics of each algorithm.
+func (r FutureGetAlgoStatsResult) Receive() (*btcjson.GetAlgoStatsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	var stats btcjson.GetAlgoStatsResult
+	err = js.Unmarshal(res, &stats)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// GetAlgoStatsAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance. See GetAlgoStats for the blocking version and more details.
+func (c *Client) GetAlgoStatsAsync(from, to int32) FutureGetAlgoStatsResult {
+	cmd := btcjson.NewGetAlgoStatsCmd(from, to)
+	return c.sendCmd(cmd)
+}
+
+// GetAlgoStats returns the share of blocks, the block interval and the estimated hashrate of each mining algorithm over
+// the main chain blocks from the passed heights. The server must have the algo stats index enabled.
+func (c *Client) GetAlgoStats(from, to int32) (*btcjson.GetAlgoStatsResult, error) {
+	return c.GetAlgoStatsAsync(from, to).Receive()
+}
@@ -177,6 +177,41 @@ func (c *Client) GetMiningInfo() (*btcjson.GetMiningInfoResult, error) {
 	return c.GetMiningInfoAsync().Receive()
 }
 
+// FutureGetWorkerStatsResult is a future promise to deliver the result of a GetWorkerStatsAsync RPC invocation (or an
+// applicable error).
+type FutureGetWorkerStatsResult chan *response
+
+// Receive waits for the response promised by the future and returns the record of each kopach miner.
+func (r FutureGetWorkerStatsResult) Receive() ([]btcjson.GetWorkerStatsResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as an array of getworkerstats result objects.
+	var workerStats []btcjson.GetWorkerStatsResult
+	err = js.Unmarshal(res, &workerStats)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return workerStats, nil
+}
+
+// GetWorkerStatsAsync returns an instance of a type that can be used to get the result of the RPC at some future time
+// by invoking the Receive function on the returned instance. See GetWorkerStats for the blocking version and more
+// details.
+func (c *Client) GetWorkerStatsAsync() FutureGetWorkerStatsResult {
+	cmd := btcjson.NewGetWorkerStatsCmd()
+	return c.sendCmd(cmd)
+}
+
+// GetWorkerStats returns the hashrate and share counts per algorithm of each kopach miner the miner controller of the
+// node has heard from.
+func (c *Client) GetWorkerStats() ([]btcjson.GetWorkerStatsResult, error) {
+	return c.GetWorkerStatsAsync().Receive()
+}
+
 // FutureGetNetworkHashPS is a future promise to deliver the result of a GetNetworkHashPSAsync RPC invocation (or an
 // applicable error).
 type FutureGetNetworkHashPS chan *response
@@ -401,6 +401,38 @@ func (c *Client) SetTxFee(fee util.Amount) error {
 	return c.SetTxFeeAsync(fee).Receive()
 }
 
+// FutureSetTxCommentResult is a future promise to deliver the result of a SetTxCommentAsync RPC invocation (or an
+// applicable error).
+type FutureSetTxCommentResult chan *response
+
+// Receive waits for the response promised by the future and returns the result of recording a comment for a
+// transaction.
+func (r FutureSetTxCommentResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// SetTxCommentAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See SetTxComment for the blocking version and more details.
+func (c *Client) SetTxCommentAsync(txHash *chainhash.Hash, comment, commentTo string) FutureSetTxCommentResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+	cmd := btcjson.NewSetTxCommentCmd(hash, comment, &commentTo)
+	return c.sendCmd(cmd)
+}
+
+// SetTxComment records the comment, and who the transaction pays in commentTo, for a wallet transaction, replacing
+// the ones recorded earlier. Empty strings remove them.
+//
+// NOTE: This is a pod extension.
+func (c *Client) SetTxComment(txHash *chainhash.Hash, comment, commentTo string) error {
+	return c.SetTxCommentAsync(txHash, comment, commentTo).Receive()
+}
+
 // FutureSendToAddressResult is a future promise to deliver the result of a SendToAddressAsync RPC invocation (or an
 // applicable error).
 type FutureSendToAddressResult chan *response
@@ -429,7 +461,7 @@ func (r FutureSendToAddressResult) Receive() (*chainhash.Hash, error) {
 // See SendToAddress for the blocking version and more details.
 func (c *Client) SendToAddressAsync(address util.Address, amount util.Amount) FutureSendToAddressResult {
 	addr := address.EncodeAddress()
-	cmd := btcjson.NewSendToAddressCmd(addr, amount.ToDUO(), nil, nil)
+	cmd := btcjson.NewSendToAddressCmd(addr, amount.ToDUO(), nil, nil, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -454,7 +486,7 @@ func (c *Client) SendToAddressCommentAsync(address util.Address,
 	commentTo string) FutureSendToAddressResult {
 	addr := address.EncodeAddress()
 	cmd := btcjson.NewSendToAddressCmd(addr, amount.ToDUO(), &comment,
-		&commentTo)
+		&commentTo, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -501,7 +533,7 @@ func (r FutureSendFromResult) Receive() (*chainhash.Hash, error) {
 func (c *Client) SendFromAsync(fromAccount string, toAddress util.Address, amount util.Amount) FutureSendFromResult {
 	addr := toAddress.EncodeAddress()
 	cmd := btcjson.NewSendFromCmd(fromAccount, addr, amount.ToDUO(), nil,
-		nil, nil)
+		nil, nil, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -522,7 +554,7 @@ func (c *Client) SendFrom(fromAccount string, toAddress util.Address, amount uti
 func (c *Client) SendFromMinConfAsync(fromAccount string, toAddress util.Address, amount util.Amount, minConfirms int) FutureSendFromResult {
 	addr := toAddress.EncodeAddress()
 	cmd := btcjson.NewSendFromCmd(fromAccount, addr, amount.ToDUO(),
-		&minConfirms, nil, nil)
+		&minConfirms, nil, nil, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -546,7 +578,7 @@ func (c *Client) SendFromCommentAsync(fromAccount string,
 	comment, commentTo string) FutureSendFromResult {
 	addr := toAddress.EncodeAddress()
 	cmd := btcjson.NewSendFromCmd(fromAccount, addr, amount.ToDUO(),
-		&minConfirms, &comment, &commentTo)
+		&minConfirms, &comment, &commentTo, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -596,7 +628,7 @@ func (c *Client) SendManyAsync(fromAccount string, amounts map[util.Address]util
 	for addr, amount := range amounts {
 		convertedAmounts[addr.EncodeAddress()] = amount.ToDUO()
 	}
-	cmd := btcjson.NewSendManyCmd(fromAccount, convertedAmounts, nil, nil)
+	cmd := btcjson.NewSendManyCmd(fromAccount, convertedAmounts, nil, nil, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -622,7 +654,7 @@ func (c *Client) SendManyMinConfAsync(fromAccount string,
 		convertedAmounts[addr.EncodeAddress()] = amount.ToDUO()
 	}
 	cmd := btcjson.NewSendManyCmd(fromAccount, convertedAmounts,
-		&minConfirms, nil)
+		&minConfirms, nil, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -650,7 +682,7 @@ func (c *Client) SendManyCommentAsync(fromAccount string,
 		convertedAmounts[addr.EncodeAddress()] = amount.ToDUO()
 	}
 	cmd := btcjson.NewSendManyCmd(fromAccount, convertedAmounts,
-		&minConfirms, &comment)
+		&minConfirms, &comment, nil)
 	return c.sendCmd(cmd)
 }
 
@@ -668,6 +700,276 @@ func (c *Client) SendManyComment(fromAccount string,
 		comment).Receive()
 }
 
+// SendManyCoinSelectionAsync returns an instance of a type that can be used to get the result of the RPC at some
+// future time by invoking the Receive function on the returned instance.
+//
+// See SendManyCoinSelection for the blocking version and more details.
+func (c *Client) SendManyCoinSelectionAsync(fromAccount string,
+	amounts map[util.Address]util.Amount, minConfirms int,
+	comment, coinSelection string) FutureSendManyResult {
+	convertedAmounts := make(map[string]float64, len(amounts))
+	for addr, amount := range amounts {
+		convertedAmounts[addr.EncodeAddress()] = amount.ToDUO()
+	}
+	cmd := btcjson.NewSendManyCmd(fromAccount, convertedAmounts,
+		&minConfirms, &comment, &coinSelection)
+	return c.sendCmd(cmd)
+}
+
+// SendManyCoinSelection sends multiple amounts to multiple addresses like SendManyComment, choosing the outputs to
+// spend with the named coin selection strategy instead of the one of the wallet configuration.
+//
+// NOTE: This function requires to the wallet to be unlocked. See the WalletPassphrase function for more details.
+func (c *Client) SendManyCoinSelection(fromAccount string,
+	amounts map[util.Address]util.Amount, minConfirms int,
+	comment, coinSelection string) (*chainhash.Hash, error) {
+	return c.SendManyCoinSelectionAsync(fromAccount, amounts, minConfirms,
+		comment, coinSelection).Receive()
+}
+
+// FutureBumpFeeResult is a future promise to deliver the result of a BumpFeeAsync RPC invocation (or an applicable
+// error).
+type FutureBumpFeeResult chan *response
+
+// Receive waits for the response promised by the future and returns the hash of the replacement transaction along
+// with the fees paid by the original and the replacement.
+func (r FutureBumpFeeResult) Receive() (*btcjson.BumpFeeResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as a bumpfee result object.
+	var bumpFee btcjson.BumpFeeResult
+	err = js.Unmarshal(res, &bumpFee)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &bumpFee, nil
+}
+
+// BumpFeeAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See BumpFee for the blocking version and more details.
+func (c *Client) BumpFeeAsync(txHash *chainhash.Hash, feeRate util.Amount) FutureBumpFeeResult {
+	hash := ""
+	if txHash != nil {
+		hash = txHash.String()
+	}
+	var options *btcjson.BumpFeeOptions
+	if feeRate != 0 {
+		rate := feeRate.ToDUO()
+		options = &btcjson.BumpFeeOptions{FeeRate: &rate}
+	}
+	cmd := btcjson.NewBumpFeeCmd(hash, options)
+	return c.sendCmd(cmd)
+}
+
+// BumpFee replaces an unconfirmed wallet transaction that signals replaceability with one paying the passed fee rate
+// per kilobyte. A zero fee rate lets the wallet choose the lowest rate that will be accepted as a replacement.
+//
+// NOTE: This function requires to the wallet to be unlocked. See the WalletPassphrase function for more details.
+func (c *Client) BumpFee(txHash *chainhash.Hash, feeRate util.Amount) (*btcjson.BumpFeeResult, error) {
+	return c.BumpFeeAsync(txHash, feeRate).Receive()
+}
+
+// FutureWalletCreateFundedPsbtResult is a future promise to deliver the result of a WalletCreateFundedPsbtAsync RPC
+// invocation (or an applicable error).
+type FutureWalletCreateFundedPsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the base64-encoded partially signed transaction
+// along with the fee it pays and the index of its change output.
+func (r FutureWalletCreateFundedPsbtResult) Receive() (*btcjson.WalletCreateFundedPsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as a walletcreatefundedpsbt result object.
+	var result btcjson.WalletCreateFundedPsbtResult
+	err = js.Unmarshal(res, &result)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WalletCreateFundedPsbtAsync returns an instance of a type that can be used to get the result of the RPC at some
+// future time by invoking the Receive function on the returned instance.
+//
+// See WalletCreateFundedPsbt for the blocking version and more details.
+func (c *Client) WalletCreateFundedPsbtAsync(inputs []btcjson.PsbtInput, amounts map[util.Address]util.Amount,
+	lockTime *uint32, options *btcjson.WalletCreateFundedPsbtOptions) FutureWalletCreateFundedPsbtResult {
+	convertedAmts := make(map[string]float64, len(amounts))
+	for addr, amount := range amounts {
+		convertedAmts[addr.String()] = amount.ToDUO()
+	}
+	cmd := btcjson.NewWalletCreateFundedPsbtCmd(inputs, convertedAmts, lockTime, options)
+	return c.sendCmd(cmd)
+}
+
+// WalletCreateFundedPsbt creates a partially signed transaction (BIP174) paying the passed amounts to the addresses.
+// The inputs are always spent, and the wallet adds further inputs and a change output as needed to pay for the outputs
+// and the fee.
+func (c *Client) WalletCreateFundedPsbt(inputs []btcjson.PsbtInput, amounts map[util.Address]util.Amount,
+	lockTime *uint32, options *btcjson.WalletCreateFundedPsbtOptions) (*btcjson.WalletCreateFundedPsbtResult, error) {
+	return c.WalletCreateFundedPsbtAsync(inputs, amounts, lockTime, options).Receive()
+}
+
+// FutureWalletProcessPsbtResult is a future promise to deliver the result of a WalletProcessPsbtAsync RPC invocation
+// (or an applicable error).
+type FutureWalletProcessPsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the updated partially signed transaction and
+// whether all of its inputs are finalized.
+func (r FutureWalletProcessPsbtResult) Receive() (*btcjson.WalletProcessPsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as a walletprocesspsbt result object.
+	var result btcjson.WalletProcessPsbtResult
+	err = js.Unmarshal(res, &result)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// WalletProcessPsbtAsync returns an instance of a type that can be used to get the result of the RPC at some future
+// time by invoking the Receive function on the returned instance.
+//
+// See WalletProcessPsbt for the blocking version and more details.
+func (c *Client) WalletProcessPsbtAsync(psbt string, sign *bool, sighashType *string) FutureWalletProcessPsbtResult {
+	cmd := btcjson.NewWalletProcessPsbtCmd(psbt, sign, sighashType)
+	return c.sendCmd(cmd)
+}
+
+// WalletProcessPsbt adds what the wallet knows about the inputs of a base64-encoded partially signed transaction,
+// signs them with the wallet's keys unless sign is false, and finalizes the inputs that have all their signatures.
+//
+// NOTE: This function requires to the wallet to be unlocked to sign. See the WalletPassphrase function for more
+// details.
+func (c *Client) WalletProcessPsbt(psbt string, sign *bool, sighashType *string) (*btcjson.WalletProcessPsbtResult,
+	error) {
+	return c.WalletProcessPsbtAsync(psbt, sign, sighashType).Receive()
+}
+
+// FutureFinalizePsbtResult is a future promise to deliver the result of a FinalizePsbtAsync RPC invocation (or an
+// applicable error).
+type FutureFinalizePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the finalized partially signed transaction or,
+// once it is complete and extraction was requested, the signed transaction.
+func (r FutureFinalizePsbtResult) Receive() (*btcjson.FinalizePsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as a finalizepsbt result object.
+	var result btcjson.FinalizePsbtResult
+	err = js.Unmarshal(res, &result)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// FinalizePsbtAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See FinalizePsbt for the blocking version and more details.
+func (c *Client) FinalizePsbtAsync(psbt string, extract *bool) FutureFinalizePsbtResult {
+	cmd := btcjson.NewFinalizePsbtCmd(psbt, extract)
+	return c.sendCmd(cmd)
+}
+
+// FinalizePsbt finalizes the inputs of a base64-encoded partially signed transaction that have all their signatures
+// and, when every input is finalized and extract is not false, returns the signed transaction hex-encoded.
+func (c *Client) FinalizePsbt(psbt string, extract *bool) (*btcjson.FinalizePsbtResult, error) {
+	return c.FinalizePsbtAsync(psbt, extract).Receive()
+}
+
+// FutureCombinePsbtResult is a future promise to deliver the result of a CombinePsbtAsync RPC invocation (or an
+// applicable error).
+type FutureCombinePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns the combined base64-encoded partially signed
+// transaction.
+func (r FutureCombinePsbtResult) Receive() (string, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return "", err
+	}
+	// Unmarshal result as a string.
+	var psbt string
+	err = js.Unmarshal(res, &psbt)
+	if err != nil {
+		Error(err)
+		return "", err
+	}
+	return psbt, nil
+}
+
+// CombinePsbtAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See CombinePsbt for the blocking version and more details.
+func (c *Client) CombinePsbtAsync(psbts []string) FutureCombinePsbtResult {
+	cmd := btcjson.NewCombinePsbtCmd(psbts)
+	return c.sendCmd(cmd)
+}
+
+// CombinePsbt merges base64-encoded partially signed transactions for the same transaction, such as those signed by
+// each cosigner of a multisig address, into one.
+func (c *Client) CombinePsbt(psbts []string) (string, error) {
+	return c.CombinePsbtAsync(psbts).Receive()
+}
+
+// FutureDecodePsbtResult is a future promise to deliver the result of a DecodePsbtAsync RPC invocation (or an
+// applicable error).
+type FutureDecodePsbtResult chan *response
+
+// Receive waits for the response promised by the future and returns information about a partially signed
+// transaction.
+func (r FutureDecodePsbtResult) Receive() (*btcjson.DecodePsbtResult, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	// Unmarshal result as a decodepsbt result object.
+	var result btcjson.DecodePsbtResult
+	err = js.Unmarshal(res, &result)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// DecodePsbtAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See DecodePsbt for the blocking version and more details.
+func (c *Client) DecodePsbtAsync(psbt string) FutureDecodePsbtResult {
+	cmd := btcjson.NewDecodePsbtCmd(psbt)
+	return c.sendCmd(cmd)
+}
+
+// DecodePsbt returns information about a base64-encoded partially signed transaction.
+func (c *Client) DecodePsbt(psbt string) (*btcjson.DecodePsbtResult, error) {
+	return c.DecodePsbtAsync(psbt).Receive()
+}
+
 // *************************
 // Address/Account Functions
 // *************************
@@ -985,6 +1287,32 @@ func (c *Client) SetAccount(address util.Address, account string) error {
 	return c.SetAccountAsync(address, account).Receive()
 }
 
+// FutureSetLabelResult is a future promise to deliver the result of a SetLabelAsync RPC invocation (or an applicable
+// error).
+type FutureSetLabelResult chan *response
+
+// Receive waits for the response promised by the future and returns the result of setting the label of the passed
+// address.
+func (r FutureSetLabelResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// SetLabelAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See SetLabel for the blocking version and more details.
+func (c *Client) SetLabelAsync(address util.Address, label string) FutureSetLabelResult {
+	addr := address.EncodeAddress()
+	cmd := btcjson.NewSetLabelCmd(addr, label)
+	return c.sendCmd(cmd)
+}
+
+// SetLabel sets the label of the passed address. An empty label removes it.
+func (c *Client) SetLabel(address util.Address, label string) error {
+	return c.SetLabelAsync(address, label).Receive()
+}
+
 // FutureGetAddressesByAccountResult is a future promise to deliver the result of a GetAddressesByAccountAsync RPC
 // invocation (or an applicable error).
 type FutureGetAddressesByAccountResult chan *response
@@ -2026,6 +2354,119 @@ func (c *Client) ImportPubKeyRescan(pubKey string, rescan bool) error {
 	return c.ImportPubKeyRescanAsync(pubKey, rescan).Receive()
 }
 
+// FutureImportAccountResult is a future promise to deliver the result of an ImportAccountAsync RPC invocation (or an
+// applicable error).
+type FutureImportAccountResult chan *response
+
+// Receive waits for the response promised by the future and returns the result of importing the passed account key.
+func (r FutureImportAccountResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// ImportAccountAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See ImportAccount for the blocking version and more details.
+func (c *Client) ImportAccountAsync(account, extendedKey string, rescan bool) FutureImportAccountResult {
+	cmd := btcjson.NewImportAccountCmd(account, extendedKey, &rescan)
+	return c.sendCmd(cmd)
+}
+
+// ImportAccount imports the extended public key (xpub, ypub or zpub) of an account as a watching-only account with the
+// passed name. When rescan is true, the block history is scanned for transactions addressed to the account.
+func (c *Client) ImportAccount(account, extendedKey string, rescan bool) error {
+	return c.ImportAccountAsync(account, extendedKey, rescan).Receive()
+}
+
+// FutureBackupWalletResult is a future promise to deliver the result of a BackupWalletAsync RPC invocation (or an
+// applicable error).
+type FutureBackupWalletResult chan *response
+
+// Receive waits for the response promised by the future and returns the result of backing up the wallet.
+func (r FutureBackupWalletResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// BackupWalletAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See BackupWallet for the blocking version and more details.
+func (c *Client) BackupWalletAsync(destination string) FutureBackupWalletResult {
+	cmd := btcjson.NewBackupWalletCmd(destination)
+	return c.sendCmd(cmd)
+}
+
+// BackupWallet makes the server write a copy of the wallet database to destination, which is a file or directory
+// on the server.
+func (c *Client) BackupWallet(destination string) error {
+	return c.BackupWalletAsync(destination).Receive()
+}
+
+// FutureDumpWalletResult is a future promise to deliver the result of a DumpWalletAsync RPC invocation (or an
+// applicable error).
+type FutureDumpWalletResult chan *response
+
+// Receive waits for the response promised by the future and returns the absolute path of the wallet dump file.
+func (r FutureDumpWalletResult) Receive() (string, error) {
+	res, err := receiveFuture(r)
+	if err != nil {
+		return "", err
+	}
+	// Unmarshal result as a dumpwallet result object.
+	var result btcjson.DumpWalletResult
+	err = js.Unmarshal(res, &result)
+	if err != nil {
+		return "", err
+	}
+	return result.Filename, nil
+}
+
+// DumpWalletAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See DumpWallet for the blocking version and more details.
+func (c *Client) DumpWalletAsync(filename string) FutureDumpWalletResult {
+	cmd := btcjson.NewDumpWalletCmd(filename)
+	return c.sendCmd(cmd)
+}
+
+// DumpWallet makes the server write all wallet keys in the text format of the reference client to a new file on the
+// server, and returns the absolute path of the file.
+//
+// NOTE: This function requires to the wallet to be unlocked. See the WalletPassphrase function for more details.
+func (c *Client) DumpWallet(filename string) (string, error) {
+	return c.DumpWalletAsync(filename).Receive()
+}
+
+// FutureImportWalletResult is a future promise to deliver the result of an ImportWalletAsync RPC invocation (or an
+// applicable error).
+type FutureImportWalletResult chan *response
+
+// Receive waits for the response promised by the future and returns the result of importing the wallet dump.
+func (r FutureImportWalletResult) Receive() error {
+	_, err := receiveFuture(r)
+	return err
+}
+
+// ImportWalletAsync returns an instance of a type that can be used to get the result of the RPC at some future time by
+// invoking the Receive function on the returned instance.
+//
+// See ImportWallet for the blocking version and more details.
+func (c *Client) ImportWalletAsync(filename string) FutureImportWalletResult {
+	cmd := btcjson.NewImportWalletCmd(filename)
+	return c.sendCmd(cmd)
+}
+
+// ImportWallet makes the server import the keys of a wallet dump file on the server, as written by DumpWallet, and
+// rescan the chain for them.
+//
+// NOTE: This function requires to the wallet to be unlocked. See the WalletPassphrase function for more details.
+func (c *Client) ImportWallet(filename string) error {
+	return c.ImportWalletAsync(filename).Receive()
+}
+
 // ***********************
 // Miscellaneous Functions
 // ***********************
@@ -2070,11 +2511,8 @@ func (c *Client) GetInfo() (*btcjson.InfoWalletResult, error) {
 }
 
 // TODO(davec): Implement
-//  backupwallet (NYI in btcwallet)
 //  encryptwallet (Won't be supported by btcwallet since it's always encrypted)
 //  getwalletinfo (NYI in btcwallet or json)
 //  listaddressgroupings (NYI in btcwallet)
 //  listreceivedbyaccount (NYI in btcwallet)
 //  DUMP
-//  importwallet (NYI in btcwallet)
-//  dumpwallet (NYI in btcwallet)
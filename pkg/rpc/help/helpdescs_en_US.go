@@ -1,3 +1,4 @@
+//go:build !generate
 // +build !generate
 
 package rpchelp
@@ -9,6 +10,23 @@ var helpDescsEnUS = map[string]string{
 	"addmultisigaddress-keys":      "Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address",
 	"addmultisigaddress-nrequired": "The number of signatures required to redeem outputs paid to this address",
 	"addmultisigaddress--result0":  "The imported pay-to-script-hash address",
+	// BackupWalletCmd help.
+	"backupwallet--synopsis":   "Writes a consistent copy of the wallet database while the wallet keeps running.",
+	"backupwallet-destination": "The file to write the copy to, or the directory to write it to under the name of the wallet database file",
+	// BumpFeeCmd help.
+	"bumpfee--synopsis": "Replaces an unconfirmed wallet transaction that signals replaceability (BIP125) with one paying a higher fee.",
+	"bumpfee-txid":      "The hash of the transaction to replace",
+	"bumpfee-options":   "Optional replacement settings",
+	// BumpFeeOptions help.
+	"bumpfeeoptions-feerate": "The fee rate in DUO/kB to pay (default: the lowest rate accepted as a replacement)",
+	// BumpFeeResult help.
+	"bumpfeeresult-txid":    "The hash of the replacement transaction",
+	"bumpfeeresult-origfee": "The fee paid by the replaced transaction in DUO",
+	"bumpfeeresult-fee":     "The fee paid by the replacement transaction in DUO",
+	// CombinePsbtCmd help.
+	"combinepsbt--synopsis": "Combines partially signed transactions (BIP174) for the same transaction into one holding the data of all of them.",
+	"combinepsbt-psbts":     "The base64-encoded partially signed transactions to combine",
+	"combinepsbt--result0":  "The combined partially signed transaction encoded as a base64 string",
 	// CreateMultisigCmd help.
 	"createmultisig--synopsis": "Generate a multisig address and redeem script.",
 	"createmultisig-keys":      "Pubkeys and/or pay-to-pubkey-hash addresses to partially control the multisig address",
@@ -16,10 +34,94 @@ var helpDescsEnUS = map[string]string{
 	// CreateMultisigResult help.
 	"createmultisigresult-address":      "The generated pay-to-script-hash address",
 	"createmultisigresult-redeemScript": "The script required to redeem outputs paid to the multisig address",
+	// DecodePsbtCmd help.
+	"decodepsbt--synopsis": "Returns a JSON object representing the provided base64-encoded partially signed transaction (BIP174).",
+	"decodepsbt-psbt":      "The base64-encoded partially signed transaction",
+	// DecodePsbtResult help.
+	"decodepsbtresult-tx":             "The unsigned transaction as a JSON object",
+	"decodepsbtresult-unknown":        "The unknown global key-value pairs",
+	"decodepsbtresult-unknown--key":   "key",
+	"decodepsbtresult-unknown--value": "value",
+	"decodepsbtresult-unknown--desc":  "The hex-encoded keys and values of unknown pairs",
+	"decodepsbtresult-inputs":         "The data of each input",
+	"decodepsbtresult-outputs":        "The data of each output",
+	"decodepsbtresult-fee":            "The fee paid by the transaction in DUO, if the outputs spent by all inputs are known",
+	// DecodePsbtInput help.
+	"decodepsbtinput-non_witness_utxo":          "The transaction holding the output spent by a non-witness input",
+	"decodepsbtinput-witness_utxo":              "The output spent by a witness input",
+	"decodepsbtinput-partial_signatures":        "The signatures made so far",
+	"decodepsbtinput-partial_signatures--key":   "pubkey",
+	"decodepsbtinput-partial_signatures--value": "signature",
+	"decodepsbtinput-partial_signatures--desc":  "The hex-encoded public keys and signatures made with them",
+	"decodepsbtinput-sighash":                   "The sighash type to sign with",
+	"decodepsbtinput-redeem_script":             "The redeem script of a pay-to-script-hash output",
+	"decodepsbtinput-witness_script":            "The witness script of a pay-to-witness-script-hash output",
+	"decodepsbtinput-bip32_derivs":              "The derivation paths of the public keys",
+	"decodepsbtinput-final_scriptsig":           "The final signature script of a finalized input",
+	"decodepsbtinput-final_scriptwitness":       "The hex-encoded witness items of a finalized input",
+	"decodepsbtinput-unknown":                   "The unknown key-value pairs of the input",
+	"decodepsbtinput-unknown--key":              "key",
+	"decodepsbtinput-unknown--value":            "value",
+	"decodepsbtinput-unknown--desc":             "The hex-encoded keys and values of unknown pairs",
+	// DecodePsbtOutput help.
+	"decodepsbtoutput-redeem_script":  "The redeem script of a pay-to-script-hash output",
+	"decodepsbtoutput-witness_script": "The witness script of a pay-to-witness-script-hash output",
+	"decodepsbtoutput-bip32_derivs":   "The derivation paths of the public keys",
+	"decodepsbtoutput-unknown":        "The unknown key-value pairs of the output",
+	"decodepsbtoutput-unknown--key":   "key",
+	"decodepsbtoutput-unknown--value": "value",
+	"decodepsbtoutput-unknown--desc":  "The hex-encoded keys and values of unknown pairs",
+	// DecodePsbtWitnessUtxo help.
+	"decodepsbtwitnessutxo-amount":       "The amount of the output in DUO",
+	"decodepsbtwitnessutxo-scriptPubKey": "The public key script of the output as a JSON object",
+	// DecodePsbtBip32Deriv help.
+	"decodepsbtbip32deriv-pubkey":             "The hex-encoded public key",
+	"decodepsbtbip32deriv-master_fingerprint": "The fingerprint of the master key as a hexadecimal string",
+	"decodepsbtbip32deriv-path":               "The derivation path of the key, e.g. m/0'/0/1",
+	// TxRawDecodeResult help.
+	"txrawdecoderesult-txid":     "The hash of the transaction",
+	"txrawdecoderesult-version":  "The transaction version",
+	"txrawdecoderesult-locktime": "The transaction lock time",
+	"txrawdecoderesult-vin":      "The transaction inputs as JSON objects",
+	"txrawdecoderesult-vout":     "The transaction outputs as JSON objects",
+	// Vin help.
+	"vin-coinbase":    "The hex-encoded bytes of the signature script (coinbase txns only)",
+	"vin-txid":        "The hash of the origin transaction (non-coinbase txns only)",
+	"vin-vout":        "The index of the output being redeemed from the origin transaction (non-coinbase txns only)",
+	"vin-scriptSig":   "The signature script used to redeem the origin transaction as a JSON object (non-coinbase txns only)",
+	"vin-txinwitness": "The witness used to redeem the input encoded as a string array of its items",
+	"vin-sequence":    "The script sequence number",
+	// Vout help.
+	"vout-value":        "The amount in DUO",
+	"vout-n":            "The index of this transaction output",
+	"vout-scriptPubKey": "The public key script used to pay coins as a JSON object",
+	// ScriptSig help.
+	"scriptsig-asm": "Disassembly of the script",
+	"scriptsig-hex": "Hex-encoded bytes of the script",
+	// ScriptPubKeyResult help.
+	"scriptpubkeyresult-asm":       "Disassembly of the script",
+	"scriptpubkeyresult-hex":       "Hex-encoded bytes of the script",
+	"scriptpubkeyresult-reqSigs":   "The number of required signatures",
+	"scriptpubkeyresult-type":      "The type of the script (e.g. 'pubkeyhash')",
+	"scriptpubkeyresult-addresses": "The addresses associated with this script",
 	// DumpPrivKeyCmd help.
 	"dumpprivkey--synopsis": "Returns the private key in WIF encoding that controls some wallet address.",
 	"dumpprivkey-address":   "The address to return a private key for",
 	"dumpprivkey--result0":  "The WIF-encoded private key",
+	// DumpWalletCmd help.
+	"dumpwallet--synopsis": "Writes all private keys and scripts of the wallet to a new file in the text format of the reference client, with their labels and derivation paths.\n" +
+		"The wallet birthday is given as the creation time of every key. The wallet must be unlocked.",
+	"dumpwallet-filename": "The file to write the dump to, which must not exist yet",
+	// DumpWalletResult help.
+	"dumpwalletresult-filename": "The absolute path of the file the dump was written to",
+	// FinalizePsbtCmd help.
+	"finalizepsbt--synopsis": "Finalizes the inputs of a partially signed transaction (BIP174) that have all their signatures and, when all of them are finalized, extracts the signed transaction.",
+	"finalizepsbt-psbt":      "The base64-encoded partially signed transaction",
+	"finalizepsbt-extract":   "Whether to return the signed transaction instead of the partially signed one when all inputs are finalized",
+	// FinalizePsbtResult help.
+	"finalizepsbtresult-psbt":     "The partially signed transaction encoded as a base64 string (unless the signed transaction was extracted)",
+	"finalizepsbtresult-hex":      "The signed transaction encoded as a hexadecimal string (if extracted)",
+	"finalizepsbtresult-complete": "Whether all inputs of the transaction are finalized",
 	// GetAccountCmd help.
 	"getaccount--synopsis": "DEPRECATED -- Lookup the account name that some wallet address belongs to.",
 	"getaccount-address":   "The address to query the account for",
@@ -108,6 +210,8 @@ var helpDescsEnUS = map[string]string{
 	"gettransactionresult-timereceived":    "The earliest Unix time this transaction was known to exist",
 	"gettransactionresult-details":         "Additional details for each recorded wallet credit and debit",
 	"gettransactionresult-hex":             "The transaction encoded as a hexadecimal string",
+	"gettransactionresult-comment":         "The comment recorded for the transaction, if any",
+	"gettransactionresult-to":              "The comment recorded about who the transaction pays, if any",
 	// GetTransactionDetailsResult help.
 	"gettransactiondetailsresult-account":           "DEPRECATED -- Unset",
 	"gettransactiondetailsresult-address":           "The address an output was paid to, or the empty string if the output is nonstandard or this detail is regarding a transaction input",
@@ -116,11 +220,16 @@ var helpDescsEnUS = map[string]string{
 	"gettransactiondetailsresult-fee":               "The included fee for a sent transaction",
 	"gettransactiondetailsresult-vout":              "The transaction output index",
 	"gettransactiondetailsresult-involveswatchonly": "Unset",
+	"gettransactiondetailsresult-label":             "The label of the address, if any",
 	// ImportPrivKeyCmd help.
 	"importprivkey--synopsis": "Imports a WIF-encoded private key to the 'imported' account.",
 	"importprivkey-privkey":   "The WIF-encoded private key",
 	"importprivkey-label":     "Unused (must be unset or 'imported')",
 	"importprivkey-rescan":    "Rescan the blockchain (since the genesis block) for outputs controlled by the imported key",
+	// ImportWalletCmd help.
+	"importwallet--synopsis": "Imports the private keys, scripts and labels of a wallet dump written by dumpwallet or the reference client.\n" +
+		"The blockchain is rescanned for the new keys from the earliest key creation time in the dump. The wallet must be unlocked.",
+	"importwallet-filename": "The wallet dump file to import",
 	// KeypoolRefillCmd help.
 	"keypoolrefill--synopsis": "DEPRECATED -- This request does nothing since no keypool is maintained.",
 	"keypoolrefill-newsize":   "Unused",
@@ -185,7 +294,9 @@ var helpDescsEnUS = map[string]string{
 	"listtransactionsresult-time":               "The earliest Unix time this transaction was known to exist",
 	"listtransactionsresult-timereceived":       "The earliest Unix time this transaction was known to exist",
 	"listtransactionsresult-involveswatchonly":  "Unset",
-	"listtransactionsresult-comment":            "Unset",
+	"listtransactionsresult-comment":            "The comment recorded for the transaction, if any",
+	"listtransactionsresult-to":                 "The comment recorded about who the transaction pays, if any",
+	"listtransactionsresult-label":              "The label of the address, if any",
 	"listtransactionsresult-otheraccount":       "Unset",
 	"listtransactionsresult-trusted":            "Unset",
 	"listtransactionsresult-bip125-replaceable": "Unset",
@@ -222,13 +333,14 @@ var helpDescsEnUS = map[string]string{
 	// SendFromCmd help.
 	"sendfrom--synopsis": "DEPRECATED -- Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
-	"sendfrom-fromaccount": "Account to pick unspent outputs from",
-	"sendfrom-toaddress":   "Address to pay",
-	"sendfrom-amount":      "Amount to send to the payment address valued in bitcoin",
-	"sendfrom-minconf":     "Minimum number of block confirmations required before a transaction output is eligible to be spent",
-	"sendfrom-comment":     "Unused",
-	"sendfrom-commentto":   "Unused",
-	"sendfrom--result0":    "The transaction hash of the sent transaction",
+	"sendfrom-fromaccount":   "Account to pick unspent outputs from",
+	"sendfrom-toaddress":     "Address to pay",
+	"sendfrom-amount":        "Amount to send to the payment address valued in bitcoin",
+	"sendfrom-minconf":       "Minimum number of block confirmations required before a transaction output is eligible to be spent",
+	"sendfrom-comment":       "A comment to record for the transaction",
+	"sendfrom-commentto":     "A comment to record about who the transaction pays",
+	"sendfrom-coinselection": "Coin selection strategy choosing the outputs to spend: bnb, knapsack, privacy, largest or valueage (default: the wallet configuration)",
+	"sendfrom--result0":      "The transaction hash of the sent transaction",
 	// SendManyCmd help.
 	"sendmany--synopsis": "Authors, signs, and sends a transaction that outputs to many payment addresses.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
@@ -238,17 +350,23 @@ var helpDescsEnUS = map[string]string{
 	"sendmany-amounts--key":   "Address to pay",
 	"sendmany-amounts--value": "Amount to send to the payment address valued in bitcoin",
 	"sendmany-minconf":        "Minimum number of block confirmations required before a transaction output is eligible to be spent",
-	"sendmany-comment":        "Unused",
+	"sendmany-comment":        "A comment to record for the transaction",
+	"sendmany-coinselection":  "Coin selection strategy choosing the outputs to spend: bnb, knapsack, privacy, largest or valueage (default: the wallet configuration)",
 	"sendmany--result0":       "The transaction hash of the sent transaction",
 	// SendToAddressCmd help.
 	"sendtoaddress--synopsis": "Authors, signs, and sends a transaction that outputs some amount to a payment address.\n" +
 		"Unlike sendfrom, outputs are always chosen from the default account.\n" +
 		"A change output is automatically included to send extra output value back to the original account.",
-	"sendtoaddress-address":   "Address to pay",
-	"sendtoaddress-amount":    "Amount to send to the payment address valued in bitcoin",
-	"sendtoaddress-comment":   "Unused",
-	"sendtoaddress-commentto": "Unused",
-	"sendtoaddress--result0":  "The transaction hash of the sent transaction",
+	"sendtoaddress-address":       "Address to pay",
+	"sendtoaddress-amount":        "Amount to send to the payment address valued in bitcoin",
+	"sendtoaddress-comment":       "A comment to record for the transaction",
+	"sendtoaddress-commentto":     "A comment to record about who the transaction pays",
+	"sendtoaddress-coinselection": "Coin selection strategy choosing the outputs to spend: bnb, knapsack, privacy, largest or valueage (default: the wallet configuration)",
+	"sendtoaddress--result0":      "The transaction hash of the sent transaction",
+	// SetLabelCmd help.
+	"setlabel--synopsis": "Sets the label of a wallet address. An empty label removes it.",
+	"setlabel-address":   "The wallet address to label",
+	"setlabel-label":     "The label for the address",
 	// SetTxFeeCmd help.
 	"settxfee--synopsis": "Modify the increment used each time more fee is required for an authored transaction.",
 	"settxfee-amount":    "The new fee increment valued in bitcoin",
@@ -302,6 +420,30 @@ var helpDescsEnUS = map[string]string{
 	"verifymessage-signature": "The signature to verify",
 	"verifymessage-message":   "The message to verify",
 	"verifymessage--result0":  "Whether the message was signed with the private key of 'address'",
+	// WalletCreateFundedPsbtCmd help.
+	"walletcreatefundedpsbt--synopsis": "Creates a partially signed transaction (BIP174) paying to the outputs and funded by the wallet.\n" +
+		"The inputs are always spent and, if they do not pay for the outputs and the fee, further inputs from the account are added as chosen by the coin selection strategy, with any change going to a new change address of the account.\n" +
+		"The wallet need not be unlocked, the transaction is signed with 'walletprocesspsbt'.",
+	"walletcreatefundedpsbt-inputs":         "The inputs to spend",
+	"walletcreatefundedpsbt-outputs":        "The addresses to pay and the amounts to pay them",
+	"walletcreatefundedpsbt-outputs--key":   "address",
+	"walletcreatefundedpsbt-outputs--value": "n.nnn",
+	"walletcreatefundedpsbt-outputs--desc":  "The destination address as the key and the amount in DUO as the value",
+	"walletcreatefundedpsbt-locktime":       "The lock time of the transaction",
+	"walletcreatefundedpsbt-options":        "Optional funding settings",
+	// PsbtInput help.
+	"psbtinput-txid":     "The transaction hash of the output to spend",
+	"psbtinput-vout":     "The output index of the output to spend",
+	"psbtinput-sequence": "The sequence number of the input (default: signals replaceability)",
+	// WalletCreateFundedPsbtOptions help.
+	"walletcreatefundedpsbtoptions-account":      "The account to fund the transaction from and send change to (default=\"default\")",
+	"walletcreatefundedpsbtoptions-feerate":      "The fee rate in DUO/kB to pay (default: the wallet's fee rate)",
+	"walletcreatefundedpsbtoptions-lockunspents":  "Whether to lock the spent outputs (default=false)",
+	"walletcreatefundedpsbtoptions-coinselection": "Coin selection strategy choosing the outputs to add: bnb, knapsack, privacy, largest or valueage (default: the wallet configuration)",
+	// WalletCreateFundedPsbtResult help.
+	"walletcreatefundedpsbtresult-psbt":      "The partially signed transaction encoded as a base64 string",
+	"walletcreatefundedpsbtresult-fee":       "The fee paid by the transaction in DUO",
+	"walletcreatefundedpsbtresult-changepos": "The index of the change output, or -1 if there is none",
 	// WalletLockCmd help.
 	"walletlock--synopsis": "Lock the wallet.",
 	// WalletPassphraseCmd help.
@@ -312,6 +454,16 @@ var helpDescsEnUS = map[string]string{
 	"walletpassphrasechange--synopsis":     "Change the wallet passphrase.",
 	"walletpassphrasechange-oldpassphrase": "The old wallet passphrase",
 	"walletpassphrasechange-newpassphrase": "The new wallet passphrase",
+	// WalletProcessPsbtCmd help.
+	"walletprocesspsbt--synopsis": "Adds what the wallet knows about the inputs of a partially signed transaction (BIP174), signs them with the keys of the wallet and finalizes the inputs that have all their signatures.\n" +
+		"The valid sighashtype options are ALL, NONE, SINGLE, ALL|ANYONECANPAY, NONE|ANYONECANPAY, and SINGLE|ANYONECANPAY.\n" +
+		"The wallet must be unlocked to sign.",
+	"walletprocesspsbt-psbt":        "The base64-encoded partially signed transaction",
+	"walletprocesspsbt-sign":        "Whether to sign the inputs",
+	"walletprocesspsbt-sighashtype": "The sighash type to sign with, unless an input asks for another",
+	// WalletProcessPsbtResult help.
+	"walletprocesspsbtresult-psbt":     "The partially signed transaction encoded as a base64 string",
+	"walletprocesspsbtresult-complete": "Whether all inputs of the transaction are finalized",
 	// CreateNewAccountCmd help.
 	"createnewaccount--synopsis": "Creates a new account.\n" +
 		"The wallet must be unlocked for this request to succeed.",
@@ -330,6 +482,13 @@ var helpDescsEnUS = map[string]string{
 	"getunconfirmedbalance--synopsis": "Calculates the unspent output value of all unmined transaction outputs for an account.",
 	"getunconfirmedbalance-account":   "The account to query the unconfirmed balance for (default=\"default\")",
 	"getunconfirmedbalance--result0":  "Total amount of all unmined unspent outputs of the account valued in bitcoin.",
+	// ImportAccountCmd help.
+	"importaccount--synopsis": "Imports the extended public key of an account (xpub, ypub or zpub) as a watching-only account.\n" +
+		"The key version selects the address type of the account: xpub for pay-to-pubkey-hash, ypub for nested and zpub for native segwit addresses.\n" +
+		"The wallet tracks the account's balance and creates its addresses, but can not sign for it; use walletcreatefundedpsbt to create transactions to sign elsewhere.",
+	"importaccount-account":     "Name of the new account",
+	"importaccount-extendedkey": "The extended public key of the account, at the path m/purpose'/cointype'/account'",
+	"importaccount-rescan":      "Rescan the blockchain (since the genesis block) in the background for outputs paying to the account",
 	// ListAddressTransactionsCmd help.
 	"listaddresstransactions--synopsis": "Returns a JSON array of objects containing verbose details for wallet transactions pertaining some addresses.",
 	"listaddresstransactions-addresses": "Addresses to filter transaction results by",
@@ -341,6 +500,11 @@ var helpDescsEnUS = map[string]string{
 	"renameaccount--synopsis":  "Renames an account.",
 	"renameaccount-oldaccount": "The old account name to rename",
 	"renameaccount-newaccount": "The new name for the account",
+	// SetTxCommentCmd help.
+	"settxcomment--synopsis": "Records a comment for a wallet transaction, replacing any recorded earlier. Empty comments remove them.",
+	"settxcomment-txid":      "Hash of the transaction",
+	"settxcomment-comment":   "The comment for the transaction",
+	"settxcomment-commentto": "A comment about who the transaction pays",
 	// WalletIsLockedCmd help.
 	"walletislocked--synopsis": "Returns whether or not the wallet is locked.",
 	"walletislocked--result0":  "Whether the wallet is locked",
@@ -35,18 +35,54 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.AddMultisigAddressCmd",
 		ResType: "string",
 	},
+	{
+		Method:  "backupwallet",
+		Handler: "BackupWallet",
+		Cmd:     "*btcjson.BackupWalletCmd",
+		ResType: "None",
+	},
+	{
+		Method:  "bumpfee",
+		Handler: "BumpFee",
+		Cmd:     "*btcjson.BumpFeeCmd",
+		ResType: "btcjson.BumpFeeResult",
+	},
+	{
+		Method:  "combinepsbt",
+		Handler: "CombinePsbt",
+		Cmd:     "*btcjson.CombinePsbtCmd",
+		ResType: "string",
+	},
 	{
 		Method:  "createmultisig",
 		Handler: "CreateMultiSig",
 		Cmd:     "*btcjson.CreateMultisigCmd",
 		ResType: "btcjson.CreateMultiSigResult",
 	},
+	{
+		Method:  "decodepsbt",
+		Handler: "DecodePsbt",
+		Cmd:     "*btcjson.DecodePsbtCmd",
+		ResType: "btcjson.DecodePsbtResult",
+	},
 	{
 		Method:  "dumpprivkey",
 		Handler: "DumpPrivKey",
 		Cmd:     "*btcjson.DumpPrivKeyCmd",
 		ResType: "string",
 	},
+	{
+		Method:  "dumpwallet",
+		Handler: "DumpWallet",
+		Cmd:     "*btcjson.DumpWalletCmd",
+		ResType: "btcjson.DumpWalletResult",
+	},
+	{
+		Method:  "finalizepsbt",
+		Handler: "FinalizePsbt",
+		Cmd:     "*btcjson.FinalizePsbtCmd",
+		ResType: "btcjson.FinalizePsbtResult",
+	},
 	{
 		Method:  "getaccount",
 		Handler: "GetAccount",
@@ -132,6 +168,12 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.ImportPrivKeyCmd",
 		ResType: "None",
 	},
+	{
+		Method:  "importwallet",
+		Handler: "ImportWallet",
+		Cmd:     "*btcjson.ImportWalletCmd",
+		ResType: "None",
+	},
 	{
 		Method:  "keypoolrefill",
 		Handler: "KeypoolRefill",
@@ -200,6 +242,12 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.SendToAddressCmd",
 		ResType: "string",
 	},
+	{
+		Method:  "setlabel",
+		Handler: "SetLabel",
+		Cmd:     "*btcjson.SetLabelCmd",
+		ResType: "None",
+	},
 	{
 		Method:  "settxfee",
 		Handler: "SetTxFee",
@@ -249,6 +297,12 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.WalletPassphraseChangeCmd",
 		ResType: "None",
 	},
+	{
+		Method:  "walletprocesspsbt",
+		Handler: "WalletProcessPsbt",
+		Cmd:     "*btcjson.WalletProcessPsbtCmd",
+		ResType: "btcjson.WalletProcessPsbtResult",
+	},
 	{
 		Method:  "createnewaccount",
 		Handler: "CreateNewAccount",
@@ -267,6 +321,12 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.GetUnconfirmedBalanceCmd",
 		ResType: "float64",
 	},
+	{
+		Method:  "importaccount",
+		Handler: "ImportAccount",
+		Cmd:     "*btcjson.ImportAccountCmd",
+		ResType: "None",
+	},
 	{
 		Method:  "listaddresstransactions",
 		Handler: "ListAddressTransactions",
@@ -285,6 +345,18 @@ var handlers = handlersT{
 		Cmd:     "*btcjson.RenameAccountCmd",
 		ResType: "None",
 	},
+	{
+		Method:  "settxcomment",
+		Handler: "SetTxComment",
+		Cmd:     "*btcjson.SetTxCommentCmd",
+		ResType: "None",
+	},
+	{
+		Method:  "walletcreatefundedpsbt",
+		Handler: "WalletCreateFundedPsbt",
+		Cmd:     "*btcjson.WalletCreateFundedPsbtCmd",
+		ResType: "btcjson.WalletCreateFundedPsbtResult",
+	},
 	{
 		Method:  "walletislocked",
 		Handler: "WalletIsLocked",
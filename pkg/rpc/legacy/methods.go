@@ -3,16 +3,21 @@ package legacy
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/hex"
 	js "encoding/json"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/p9c/pod/pkg/chain/config/netparams"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
 	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	"github.com/p9c/pod/pkg/chain/tx/psbt"
 	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
 	txscript "github.com/p9c/pod/pkg/chain/tx/script"
 	"github.com/p9c/pod/pkg/chain/wire"
@@ -20,6 +25,7 @@ import (
 	"github.com/p9c/pod/pkg/rpc/btcjson"
 	rpcclient "github.com/p9c/pod/pkg/rpc/client"
 	"github.com/p9c/pod/pkg/util"
+	"github.com/p9c/pod/pkg/util/hdkeychain"
 	"github.com/p9c/pod/pkg/util/interrupt"
 	"github.com/p9c/pod/pkg/wallet"
 	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
@@ -246,10 +252,7 @@ func Confirms(txHeight, curHeight int32) int32 {
 // 		Return:  func() interface{} { return make(chan WalletPassphraseChangeRes) },
 // 	},
 // 	// Reference implementation methods (still unimplemented)
-// 	"backupwallet":         {Handler: Unimplemented, NoHelp: true},
-// 	"dumpwallet":           {Handler: Unimplemented, NoHelp: true},
 // 	"getwalletinfo":        {Handler: Unimplemented, NoHelp: true},
-// 	"importwallet":         {Handler: Unimplemented, NoHelp: true},
 // 	"listaddressgroupings": {Handler: Unimplemented, NoHelp: true},
 // 	// Reference methods which can't be implemented by btcwallet due to
 // 	// design decision differences
@@ -528,6 +531,103 @@ func AddMultiSigAddress(icmd interface{}, w *wallet.Wallet, chainClient ...*chai
 	return p2shAddr.EncodeAddress(), nil
 }
 
+// BackupWallet handles a backupwallet request by writing a consistent copy of the wallet database to the destination
+// file, or to a file named as the wallet database in the destination directory.
+func BackupWallet(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.BackupWalletCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["backupwallet"],
+		}
+	}
+	if err := w.Backup(cmd.Destination); err != nil {
+		Error(err)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: "Wallet backup failed: " + err.Error(),
+		}
+	}
+	return nil, nil
+}
+
+// BumpFee handles a bumpfee request by replacing an unconfirmed wallet transaction that signals replaceability with
+// one paying a higher fee. Without a fee rate the lowest fee rate the network will accept as a replacement is used.
+func BumpFee(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.BumpFeeCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["bumpfee"],
+		}
+	}
+	txHash, err := chainhash.NewHashFromStr(cmd.TxID)
+	if err != nil {
+		Error(err)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+	var feeSatPerKb util.Amount
+	if cmd.Options != nil && cmd.Options.FeeRate != nil {
+		feeSatPerKb, err = util.NewAmount(*cmd.Options.FeeRate)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		if feeSatPerKb <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+	}
+	newHash, origFee, fee, err := w.BumpFee(txHash, feeSatPerKb)
+	if err != nil {
+		Error(err)
+		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	return btcjson.BumpFeeResult{
+		TxID:    newHash.String(),
+		OrigFee: origFee.ToDUO(),
+		Fee:     fee.ToDUO(),
+	}, nil
+}
+
+// CombinePsbt handles a combinepsbt request by merging partially signed transactions for the same transaction into one
+// holding the data of all of them.
+func CombinePsbt(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.CombinePsbtCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["combinepsbt"],
+		}
+	}
+	if len(cmd.Psbts) == 0 {
+		return nil, InvalidParameterError{errors.New("no partially signed transactions to combine")}
+	}
+	packets := make([]*psbt.Packet, len(cmd.Psbts))
+	for i, b64 := range cmd.Psbts {
+		p, err := DecodePsbtStr(b64)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		packets[i] = p
+	}
+	combined, err := psbt.Combine(packets...)
+	if err != nil {
+		Error(err)
+		return nil, InvalidParameterError{err}
+	}
+	return combined.B64Encode()
+}
+
 // CreateMultiSig handles an createmultisig request by returning a multisig address for the given inputs.
 func CreateMultiSig(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
 	var msg string
@@ -559,6 +659,187 @@ func CreateMultiSig(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RP
 	}, nil
 }
 
+// DecodePsbt handles a decodepsbt request by returning a JSON object describing a partially signed transaction. The fee
+// is only included when the outputs spent by all of its inputs are in the packet.
+func DecodePsbt(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.DecodePsbtCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["decodepsbt"],
+		}
+	}
+	p, err := DecodePsbtStr(cmd.Psbt)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	params := w.ChainParams()
+	result := btcjson.DecodePsbtResult{
+		Tx:      DecodeTx(p.UnsignedTx, params),
+		Unknown: make(map[string]string, len(p.Unknowns)),
+		Inputs:  make([]btcjson.DecodePsbtInput, len(p.Inputs)),
+		Outputs: make([]btcjson.DecodePsbtOutput, len(p.Outputs)),
+	}
+	for _, u := range p.Unknowns {
+		result.Unknown[hex.EncodeToString(u.Key)] = hex.EncodeToString(u.Value)
+	}
+	for i := range p.Inputs {
+		in := &p.Inputs[i]
+		res := &result.Inputs[i]
+		if in.NonWitnessUtxo != nil {
+			tx := DecodeTx(in.NonWitnessUtxo, params)
+			res.NonWitnessUtxo = &tx
+		}
+		if in.WitnessUtxo != nil {
+			res.WitnessUtxo = &btcjson.DecodePsbtWitnessUtxo{
+				Amount:       util.Amount(in.WitnessUtxo.Value).ToDUO(),
+				ScriptPubKey: *DecodeScript(in.WitnessUtxo.PkScript, params),
+			}
+		}
+		if len(in.PartialSigs) > 0 {
+			res.PartialSignatures = make(map[string]string, len(in.PartialSigs))
+			for _, ps := range in.PartialSigs {
+				res.PartialSignatures[hex.EncodeToString(ps.PubKey)] = hex.EncodeToString(ps.Signature)
+			}
+		}
+		if in.SighashType != 0 {
+			res.Sighash = SigHashTypeString(in.SighashType)
+		}
+		res.RedeemScript = DecodeScript(in.RedeemScript, params)
+		res.WitnessScript = DecodeScript(in.WitnessScript, params)
+		res.Bip32Derivs = DecodeBip32Derivations(in.Bip32Derivation)
+		if in.FinalScriptSig != nil {
+			// The disassembled string will contain [error] inline if the script doesn't fully parse, so ignore the
+			// error here.
+			disbuf, _ := txscript.DisasmString(in.FinalScriptSig)
+			res.FinalScriptSig = &btcjson.ScriptSig{
+				Asm: disbuf,
+				Hex: hex.EncodeToString(in.FinalScriptSig),
+			}
+		}
+		witness, err := in.FinalWitness()
+		if err != nil {
+			Error(err)
+			return nil, DeserializationError{err}
+		}
+		res.FinalScriptWitness = WitnessToHex(witness)
+		res.Unknown = DecodeUnknowns(in.Unknowns)
+	}
+	for i := range p.Outputs {
+		out := &p.Outputs[i]
+		result.Outputs[i] = btcjson.DecodePsbtOutput{
+			RedeemScript:  DecodeScript(out.RedeemScript, params),
+			WitnessScript: DecodeScript(out.WitnessScript, params),
+			Bip32Derivs:   DecodeBip32Derivations(out.Bip32Derivation),
+			Unknown:       DecodeUnknowns(out.Unknowns),
+		}
+	}
+	if fee, err := p.Fee(); err == nil {
+		feeDUO := fee.ToDUO()
+		result.Fee = &feeDUO
+	}
+	return result, nil
+}
+
+// DecodeTx returns a JSON object describing a transaction, as the decoderawtransaction command of the chain server
+// does.
+func DecodeTx(mtx *wire.MsgTx, params *netparams.Params) btcjson.TxRawDecodeResult {
+	vinList := make([]btcjson.Vin, len(mtx.TxIn))
+	for i, txIn := range mtx.TxIn {
+		// The disassembled string will contain [error] inline if the script doesn't fully parse, so ignore the error
+		// here.
+		disbuf, _ := txscript.DisasmString(txIn.SignatureScript)
+		vinList[i] = btcjson.Vin{
+			Txid:     txIn.PreviousOutPoint.Hash.String(),
+			Vout:     txIn.PreviousOutPoint.Index,
+			Sequence: txIn.Sequence,
+			ScriptSig: &btcjson.ScriptSig{
+				Asm: disbuf,
+				Hex: hex.EncodeToString(txIn.SignatureScript),
+			},
+			Witness: WitnessToHex(txIn.Witness),
+		}
+	}
+	voutList := make([]btcjson.Vout, len(mtx.TxOut))
+	for i, txOut := range mtx.TxOut {
+		voutList[i] = btcjson.Vout{
+			Value:        util.Amount(txOut.Value).ToDUO(),
+			N:            uint32(i),
+			ScriptPubKey: *DecodeScript(txOut.PkScript, params),
+		}
+	}
+	return btcjson.TxRawDecodeResult{
+		Txid:     mtx.TxHash().String(),
+		Version:  mtx.Version,
+		Locktime: mtx.LockTime,
+		Vin:      vinList,
+		Vout:     voutList,
+	}
+}
+
+// DecodeScript returns a JSON object describing a script, or nil if there is no script.
+func DecodeScript(script []byte, params *netparams.Params) *btcjson.ScriptPubKeyResult {
+	if script == nil {
+		return nil
+	}
+	// The disassembled string will contain [error] inline if the script doesn't fully parse, so ignore the error here.
+	disbuf, _ := txscript.DisasmString(script)
+	// Ignore the error here since an error means the script couldn't parse and there is no additional information
+	// about it anyways.
+	scriptClass, addrs, reqSigs, _ := txscript.ExtractPkScriptAddrs(script, params)
+	encodedAddrs := make([]string, len(addrs))
+	for i, addr := range addrs {
+		encodedAddrs[i] = addr.EncodeAddress()
+	}
+	return &btcjson.ScriptPubKeyResult{
+		Asm:       disbuf,
+		Hex:       hex.EncodeToString(script),
+		ReqSigs:   int32(reqSigs),
+		Type:      scriptClass.String(),
+		Addresses: encodedAddrs,
+	}
+}
+
+// DecodeBip32Derivations returns JSON objects describing the BIP0032 derivations of the public keys of a partially
+// signed transaction input or output, with the paths written as in BIP0032.
+func DecodeBip32Derivations(derivations []*psbt.Bip32Derivation) []btcjson.DecodePsbtBip32Deriv {
+	if len(derivations) == 0 {
+		return nil
+	}
+	result := make([]btcjson.DecodePsbtBip32Deriv, len(derivations))
+	for i, d := range derivations {
+		var fingerprint [4]byte
+		binary.LittleEndian.PutUint32(fingerprint[:], d.MasterKeyFingerprint)
+		path := "m"
+		for _, index := range d.Bip32Path {
+			if index >= hdkeychain.HardenedKeyStart {
+				path += fmt.Sprintf("/%d'", index-hdkeychain.HardenedKeyStart)
+			} else {
+				path += fmt.Sprintf("/%d", index)
+			}
+		}
+		result[i] = btcjson.DecodePsbtBip32Deriv{
+			PubKey:            hex.EncodeToString(d.PubKey),
+			MasterFingerprint: hex.EncodeToString(fingerprint[:]),
+			Path:              path,
+		}
+	}
+	return result
+}
+
+// DecodeUnknowns returns the hex encoded keys and values of unknown key-value pairs, or nil if there are none.
+func DecodeUnknowns(unknowns []*psbt.Unknown) map[string]string {
+	if len(unknowns) == 0 {
+		return nil
+	}
+	result := make(map[string]string, len(unknowns))
+	for _, u := range unknowns {
+		result[hex.EncodeToString(u.Key)] = hex.EncodeToString(u.Value)
+	}
+	return result
+}
+
 // DumpPrivKey handles a dumpprivkey request with the private key for a single address, or an appropriate error if the
 // wallet is locked.
 func DumpPrivKey(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
@@ -585,16 +866,92 @@ func DumpPrivKey(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCCl
 	return key, err
 }
 
-// // dumpWallet handles a dumpwallet request by returning  all private
-// // keys in a wallet, or an appropiate error if the wallet is locked.
-// // TODO: finish this to match bitcoind by writing the dump to a file.
-// func dumpWallet(// 	icmd interface{}, w *wallet.Wallet) (interface{}, error) {
-// 	keys, err := w.DumpPrivKeys()
-// 	if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
-// 		return nil, &ErrWalletUnlockNeeded
-// 	}
-// 	return keys, err
-// }
+// DumpWallet handles a dumpwallet request by writing all private keys and scripts of the wallet to a new file in the
+// text format of the reference client. An existing file is never overwritten.
+func DumpWallet(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.DumpWalletCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["dumpwallet"],
+		}
+	}
+	filename, err := filepath.Abs(cmd.Filename)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		Error(err)
+		if os.IsExist(err) {
+			return nil, &btcjson.RPCError{
+				Code:    btcjson.ErrRPCInvalidParameter,
+				Message: filename + " already exists. Move it out of the way first if it is to be replaced.",
+			}
+		}
+		return nil, err
+	}
+	err = w.DumpWallet(f)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		Error(err)
+		// Do not leave a partial dump behind that could be mistaken for a complete one.
+		_ = os.Remove(filename)
+		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		return nil, err
+	}
+	return btcjson.DumpWalletResult{Filename: filename}, nil
+}
+
+// FinalizePsbt handles a finalizepsbt request by finalizing the inputs of a partially signed transaction that have all
+// their signatures. When every input is finalized and extraction is requested, the signed transaction is returned
+// instead of the partially signed one.
+func FinalizePsbt(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.FinalizePsbtCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["finalizepsbt"],
+		}
+	}
+	p, err := DecodePsbtStr(cmd.Psbt)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	complete := p.MaybeFinalizeAll()
+	if complete && *cmd.Extract {
+		tx, err := p.Extract()
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		var buf bytes.Buffer
+		buf.Grow(tx.SerializeSize())
+		if err = tx.Serialize(&buf); err != nil {
+			Error(err)
+			return nil, err
+		}
+		return btcjson.FinalizePsbtResult{
+			Hex:      hex.EncodeToString(buf.Bytes()),
+			Complete: true,
+		}, nil
+	}
+	b64, err := p.B64Encode()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return btcjson.FinalizePsbtResult{
+		Psbt:     b64,
+		Complete: complete,
+	}, nil
+}
 
 // GetAddressesByAccount handles a getaddressesbyaccount request by returning all addresses for an account, or an error
 // if the requested account does not exist.
@@ -867,6 +1224,40 @@ func ImportPrivKey(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPC
 	return nil, err
 }
 
+// ImportWallet handles an importwallet request by importing the keys, scripts and labels of a wallet dump file and
+// rescanning the chain for them from the earliest key creation time in the dump.
+func ImportWallet(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.ImportWalletCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["importwallet"],
+		}
+	}
+	f, err := os.Open(cmd.Filename)
+	if err != nil {
+		Error(err)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: "Cannot open wallet dump file: " + err.Error(),
+		}
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			Error(err)
+		}
+	}()
+	_, err = w.ImportWallet(f)
+	if err != nil {
+		Error(err)
+		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
 // KeypoolRefill handles the keypoolrefill command. Since we handle the keypool automatically this does nothing since
 // refilling is never manually required.
 func KeypoolRefill(icmd interface{}, w *wallet.Wallet,
@@ -901,6 +1292,34 @@ func CreateNewAccount(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.
 	return nil, err
 }
 
+// ImportAccount handles an importaccount request by adding a watching-only account from the extended public key of a
+// BIP0044, BIP0049 or BIP0084 account. The key scope of the account follows from the key's version. The wallet does not
+// need to be unlocked, as no private keys are involved.
+func ImportAccount(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.ImportAccountCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["importaccount"],
+		}
+	}
+	// The wildcard * is reserved by the rpc server with the special meaning of "all accounts", so disallow naming
+	// accounts to this string.
+	if cmd.Account == "*" {
+		return nil, &ErrReservedAccountName
+	}
+	acctKey, scope, err := waddrmgr.ParseAccountPubKey(cmd.ExtendedKey, w.ChainParams())
+	if err != nil {
+		Error(err)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidAddressOrKey,
+			Message: err.Error(),
+		}
+	}
+	_, err = w.ImportAccount(scope, cmd.Account, acctKey, nil, *cmd.Rescan)
+	return nil, err
+}
+
 // RenameAccount handles a renameaccount request by renaming an account. If the account does not exist an appropiate
 // error will be returned.
 func RenameAccount(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
@@ -1089,6 +1508,8 @@ func GetTransaction(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RP
 		Time:            details.Received.Unix(),
 		TimeReceived:    details.Received.Unix(),
 		WalletConflicts: []string{}, // Not saved
+		Comment:         details.Comment.Comment,
+		To:              details.Comment.CommentTo,
 		// Generated:     blockchain.IsCoinBaseTx(&details.MsgTx),
 	}
 	if details.Block.Height != -1 {
@@ -1151,6 +1572,7 @@ func GetTransaction(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RP
 		}
 		var address string
 		var accountName string
+		var label string
 		_, addrs, _, err := txscript.ExtractPkScriptAddrs(
 			details.MsgTx.TxOut[cred.Index].PkScript, w.ChainParams())
 		if err == nil && len(addrs) == 1 {
@@ -1163,6 +1585,11 @@ func GetTransaction(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RP
 					accountName = name
 				}
 			}
+			label, err = w.AddressLabel(addr)
+			if err != nil {
+				Error(err)
+				label = ""
+			}
 		}
 		ret.Details = append(ret.Details, btcjson.GetTransactionDetailsResult{
 			// Fields left zeroed:
@@ -1173,6 +1600,7 @@ func GetTransaction(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RP
 			Category: credCat,
 			Amount:   cred.Amount.ToDUO(),
 			Vout:     cred.Index,
+			Label:    label,
 		})
 	}
 	ret.Amount = creditTotal.ToDUO()
@@ -1712,16 +2140,18 @@ func MakeOutputs(pairs map[string]util.Amount, chainParams *netparams.Params) ([
 	return outputs, nil
 }
 
-// SendPairs creates and sends payment transactions. It returns the transaction hash in string format upon success All
-// errors are returned in json.RPCError format
+// SendPairs creates and sends payment transactions, spending the outputs chosen by the coin selector, or by the one of
+// the wallet configuration if it is nil, and records the comment, if any, for the sent transaction. It returns the
+// transaction hash in string format upon success All errors are returned in json.RPCError format
 func SendPairs(w *wallet.Wallet, amounts map[string]util.Amount,
-	account uint32, minconf int32, feeSatPerKb util.Amount) (string, error) {
+	account uint32, minconf int32, feeSatPerKb util.Amount, comment *wtxmgr.TxComment,
+	coinSelector wallet.CoinSelector) (string, error) {
 	outputs, err := MakeOutputs(amounts, w.ChainParams())
 	if err != nil {
 		Error(err)
 		return "", err
 	}
-	txHash, err := w.SendOutputs(outputs, account, minconf, feeSatPerKb)
+	txHash, err := w.SendOutputs(outputs, account, minconf, feeSatPerKb, coinSelector)
 	if err != nil {
 		Error(err)
 		if err == txrules.ErrAmountNegative {
@@ -1741,12 +2171,48 @@ func SendPairs(w *wallet.Wallet, amounts map[string]util.Amount,
 	}
 	txHashStr := txHash.String()
 	Info("successfully sent transaction", txHashStr)
+	// The transaction is already on its way, so failing to record the comment must not fail the request, which could
+	// lead the caller to send the payment again.
+	if comment != nil && !comment.IsEmpty() {
+		if err = w.SetTxComment(txHash, comment); err != nil {
+			Error(err)
+		}
+	}
 	return txHashStr, nil
 }
 func IsNilOrEmpty(s *string) bool {
 	return s == nil || *s == ""
 }
 
+// txComment returns the transaction comment made of the optional comment and comment_to parameters of a send request.
+func txComment(comment, commentTo *string) *wtxmgr.TxComment {
+	c := &wtxmgr.TxComment{}
+	if comment != nil {
+		c.Comment = *comment
+	}
+	if commentTo != nil {
+		c.CommentTo = *commentTo
+	}
+	return c
+}
+
+// coinSelector returns the coin selector of the strategy named by the optional coinselection parameter of a send
+// request, or nil for the one of the wallet configuration when it is not given.
+func coinSelector(name *string) (wallet.CoinSelector, error) {
+	if IsNilOrEmpty(name) {
+		return nil, nil
+	}
+	selector, err := wallet.CoinSelectorByName(*name)
+	if err != nil {
+		Error(err)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: err.Error(),
+		}
+	}
+	return selector, nil
+}
+
 // SendFrom handles a sendfrom RPC request by creating a new transaction spending unspent transaction outputs for a
 // wallet to another payment address. Leftover inputs not sent to the payment address or a fee for the miner are sent
 // back to a new address in the wallet. Upon success, the TxID for the created transaction is returned.
@@ -1759,13 +2225,6 @@ func SendFrom(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient)
 			// "invalid subcommand for addnode",
 		}
 	}
-	// Transaction comments are not yet supported. ScriptError instead of pretending to save them.
-	if !IsNilOrEmpty(cmd.Comment) || !IsNilOrEmpty(cmd.CommentTo) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
-	}
 	account, err := w.AccountNumber(
 		waddrmgr.KeyScopeBIP0044, cmd.FromAccount,
 	)
@@ -1790,8 +2249,12 @@ func SendFrom(icmd interface{}, w *wallet.Wallet, chainClient *chain.RPCClient)
 	pairs := map[string]util.Amount{
 		cmd.ToAddress: amt,
 	}
+	selector, err := coinSelector(cmd.CoinSelection)
+	if err != nil {
+		return nil, err
+	}
 	return SendPairs(w, pairs, account, minConf,
-		txrules.DefaultRelayFeePerKb)
+		txrules.DefaultRelayFeePerKb, txComment(cmd.Comment, cmd.CommentTo), selector)
 }
 
 // SendMany handles a sendmany RPC request by creating a new transaction spending unspent transaction outputs for a
@@ -1809,13 +2272,6 @@ func SendMany(icmd interface{}, w *wallet.Wallet,
 			// "invalid subcommand for addnode",
 		}
 	}
-	// Transaction comments are not yet supported. ScriptError instead of pretending to save them.
-	if !IsNilOrEmpty(cmd.Comment) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
-	}
 	account, err := w.AccountNumber(waddrmgr.KeyScopeBIP0044, cmd.FromAccount)
 	if err != nil {
 		Error(err)
@@ -1836,7 +2292,12 @@ func SendMany(icmd interface{}, w *wallet.Wallet,
 		}
 		pairs[k] = amt
 	}
-	return SendPairs(w, pairs, account, minConf, txrules.DefaultRelayFeePerKb)
+	selector, err := coinSelector(cmd.CoinSelection)
+	if err != nil {
+		return nil, err
+	}
+	return SendPairs(w, pairs, account, minConf, txrules.DefaultRelayFeePerKb,
+		txComment(cmd.Comment, nil), selector)
 }
 
 // SendToAddress handles a sendtoaddress RPC request by creating a new transaction spending unspent transaction outputs
@@ -1854,14 +2315,6 @@ func SendToAddress(icmd interface{}, w *wallet.Wallet,
 			// "invalid subcommand for addnode",
 		}
 	}
-	// Transaction comments are not yet supported.  ScriptError instead of
-	// pretending to save them.
-	if !IsNilOrEmpty(cmd.Comment) || !IsNilOrEmpty(cmd.CommentTo) {
-		return nil, &btcjson.RPCError{
-			Code:    btcjson.ErrRPCUnimplemented,
-			Message: "Transaction comments are not yet supported",
-		}
-	}
 	amt, err := util.NewAmount(cmd.Amount)
 	if err != nil {
 		Error(err)
@@ -1875,9 +2328,75 @@ func SendToAddress(icmd interface{}, w *wallet.Wallet,
 	pairs := map[string]util.Amount{
 		cmd.Address: amt,
 	}
+	selector, err := coinSelector(cmd.CoinSelection)
+	if err != nil {
+		return nil, err
+	}
 	// sendtoaddress always spends from the default account, this matches bitcoind
 	return SendPairs(w, pairs, waddrmgr.DefaultAccountNum, 1,
-		txrules.DefaultRelayFeePerKb)
+		txrules.DefaultRelayFeePerKb, txComment(cmd.Comment, cmd.CommentTo), selector)
+}
+
+// SetLabel handles a setlabel request by setting the label of a wallet address. An empty label removes it.
+func SetLabel(icmd interface{}, w *wallet.Wallet,
+	chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.SetLabelCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["setlabel"],
+		}
+	}
+	addr, err := DecodeAddress(cmd.Address, w.ChainParams())
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	err = w.SetAddressLabel(addr, cmd.Label)
+	if err != nil {
+		Error(err)
+		if waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+			return nil, &ErrAddressNotInWallet
+		}
+		return nil, err
+	}
+	return nil, nil
+}
+
+// SetTxComment handles a settxcomment request by recording the comment for a wallet transaction, replacing the one
+// recorded earlier. Empty comments remove it.
+func SetTxComment(icmd interface{}, w *wallet.Wallet,
+	chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.SetTxCommentCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["settxcomment"],
+		}
+	}
+	txHash, err := chainhash.NewHashFromStr(cmd.Txid)
+	if err != nil {
+		Error(err)
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCDecodeHexString,
+			Message: "Transaction hash string decode failed: " + err.Error(),
+		}
+	}
+	// Only transactions of the wallet can be commented, so that the comments do not pile up for hashes mistyped.
+	details, err := wallet.ExposeUnstableAPI(w).TxDetails(txHash)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if details == nil {
+		return nil, &ErrNoTransactionInfo
+	}
+	err = w.SetTxComment(txHash, txComment(&cmd.Comment, cmd.CommentTo))
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return nil, nil
 }
 
 // SetTxFee sets the transaction fee per kilobyte added to transactions.
@@ -1941,6 +2460,34 @@ func SignMessage(icmd interface{}, w *wallet.Wallet,
 	return base64.StdEncoding.EncodeToString(sigbytes), nil
 }
 
+// SigHashTypeNames are the sighash flags of signing requests for the sighash types they stand for.
+var SigHashTypeNames = map[txscript.SigHashType]string{
+	txscript.SigHashAll:                                   "ALL",
+	txscript.SigHashNone:                                  "NONE",
+	txscript.SigHashSingle:                                "SINGLE",
+	txscript.SigHashAll | txscript.SigHashAnyOneCanPay:    "ALL|ANYONECANPAY",
+	txscript.SigHashNone | txscript.SigHashAnyOneCanPay:   "NONE|ANYONECANPAY",
+	txscript.SigHashSingle | txscript.SigHashAnyOneCanPay: "SINGLE|ANYONECANPAY",
+}
+
+// ParseSigHashType returns the sighash type the sighash flags of a signing request stand for.
+func ParseSigHashType(flags string) (txscript.SigHashType, error) {
+	for hashType, name := range SigHashTypeNames {
+		if name == flags {
+			return hashType, nil
+		}
+	}
+	return 0, InvalidParameterError{errors.New("Invalid sighash parameter")}
+}
+
+// SigHashTypeString returns the sighash flags of a sighash type, or its number for a type that has none.
+func SigHashTypeString(hashType txscript.SigHashType) string {
+	if name, ok := SigHashTypeNames[hashType]; ok {
+		return name
+	}
+	return fmt.Sprintf("%#x", uint32(hashType))
+}
+
 // SignRawTransaction handles the signrawtransaction command.
 func SignRawTransaction(icmd interface{}, w *wallet.Wallet,
 	cc ...*chain.RPCClient) (interface{}, error) {
@@ -1971,23 +2518,10 @@ func SignRawTransaction(icmd interface{}, w *wallet.Wallet,
 		e := errors.New("TX decode failed")
 		return nil, DeserializationError{e}
 	}
-	var hashType txscript.SigHashType
-	switch *cmd.Flags {
-	case "ALL":
-		hashType = txscript.SigHashAll
-	case "NONE":
-		hashType = txscript.SigHashNone
-	case "SINGLE":
-		hashType = txscript.SigHashSingle
-	case "ALL|ANYONECANPAY":
-		hashType = txscript.SigHashAll | txscript.SigHashAnyOneCanPay
-	case "NONE|ANYONECANPAY":
-		hashType = txscript.SigHashNone | txscript.SigHashAnyOneCanPay
-	case "SINGLE|ANYONECANPAY":
-		hashType = txscript.SigHashSingle | txscript.SigHashAnyOneCanPay
-	default:
-		e := errors.New("Invalid sighash parameter")
-		return nil, InvalidParameterError{e}
+	hashType, err := ParseSigHashType(*cmd.Flags)
+	if err != nil {
+		Error(err)
+		return nil, err
 	}
 	// TODO: really we probably should look these up with pod anyway to
 	// make sure that they match the blockchain if present.
@@ -2252,6 +2786,107 @@ func VerifyMessage(icmd interface{}, w *wallet.Wallet,
 	}
 }
 
+// WalletCreateFundedPsbt handles a walletcreatefundedpsbt request by creating a partially signed transaction paying to
+// the requested outputs, funded by the passed inputs and as many further outputs of the account as it takes to pay the
+// fee. The wallet does not need to be unlocked, as the transaction is not signed.
+func WalletCreateFundedPsbt(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.WalletCreateFundedPsbtCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["walletcreatefundedpsbt"],
+		}
+	}
+	inputs := make([]*wire.TxIn, len(cmd.Inputs))
+	for i, input := range cmd.Inputs {
+		txHash, err := chainhash.NewHashFromStr(input.Txid)
+		if err != nil {
+			Error(err)
+			return nil, DeserializationError{err}
+		}
+		inputs[i] = wire.NewTxIn(&wire.OutPoint{Hash: *txHash, Index: input.Vout}, nil, nil)
+		// Signal replaceability as the wallet does for the transactions it sends.
+		inputs[i].Sequence = w.InputSequence()
+		if input.Sequence != nil {
+			inputs[i].Sequence = *input.Sequence
+		}
+	}
+	pairs := make(map[string]util.Amount, len(cmd.Outputs))
+	for k, v := range cmd.Outputs {
+		amt, err := util.NewAmount(v)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		if amt <= 0 {
+			return nil, ErrNeedPositiveAmount
+		}
+		pairs[k] = amt
+	}
+	outputs, err := MakeOutputs(pairs, w.ChainParams())
+	if err != nil {
+		Error(err)
+		return nil, InvalidParameterError{err}
+	}
+	var lockTime uint32
+	if cmd.LockTime != nil {
+		lockTime = *cmd.LockTime
+	}
+	accountName := "default"
+	feeSatPerKb := txrules.DefaultRelayFeePerKb
+	var lockInputs bool
+	var selector wallet.CoinSelector
+	if opts := cmd.Options; opts != nil {
+		if opts.Account != nil {
+			accountName = *opts.Account
+		}
+		if opts.FeeRate != nil {
+			feeSatPerKb, err = util.NewAmount(*opts.FeeRate)
+			if err != nil {
+				Error(err)
+				return nil, err
+			}
+			if feeSatPerKb <= 0 {
+				return nil, ErrNeedPositiveAmount
+			}
+		}
+		if opts.LockUnspents != nil {
+			lockInputs = *opts.LockUnspents
+		}
+		if selector, err = coinSelector(opts.CoinSelection); err != nil {
+			return nil, err
+		}
+	}
+	// The account may be in any key scope, as one imported from a ypub or zpub is not in BIP0044.
+	scope, account, err := w.LookupAccount(accountName)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	p, fee, changeIndex, err := w.CreateFundedPsbt(inputs, outputs, lockTime, scope, account, 1, feeSatPerKb,
+		lockInputs, selector)
+	if err != nil {
+		Error(err)
+		if err == txrules.ErrAmountNegative {
+			return nil, ErrNeedPositiveAmount
+		}
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	b64, err := p.B64Encode()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return btcjson.WalletCreateFundedPsbtResult{
+		Psbt:      b64,
+		Fee:       fee.ToDUO(),
+		ChangePos: changeIndex,
+	}, nil
+}
+
 // WalletIsLocked handles the walletislocked extension request by returning the current lock state (false for unlocked,
 // true for locked) of an account.
 func WalletIsLocked(icmd interface{}, w *wallet.Wallet,
@@ -2313,6 +2948,52 @@ func WalletPassphraseChange(icmd interface{}, w *wallet.Wallet,
 	return nil, err
 }
 
+// WalletProcessPsbt handles a walletprocesspsbt request by adding what the wallet knows about the inputs of a partially
+// signed transaction, signing them with the keys of the wallet if requested and finalizing those that have all their
+// signatures.
+func WalletProcessPsbt(icmd interface{}, w *wallet.Wallet, chainClient ...*chain.RPCClient) (interface{}, error) {
+	cmd, ok := icmd.(*btcjson.WalletProcessPsbtCmd)
+	if !ok {
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCInvalidParameter,
+			Message: HelpDescsEnUS()["walletprocesspsbt"],
+		}
+	}
+	p, err := DecodePsbtStr(cmd.Psbt)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	hashType, err := ParseSigHashType(*cmd.SighashType)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	complete, err := w.ProcessPsbt(p, *cmd.Sign, hashType)
+	if err != nil {
+		Error(err)
+		if waddrmgr.IsError(err, waddrmgr.ErrLocked) {
+			return nil, &ErrWalletUnlockNeeded
+		}
+		if err == wallet.ErrSighashMismatch {
+			return nil, InvalidParameterError{err}
+		}
+		return nil, &btcjson.RPCError{
+			Code:    btcjson.ErrRPCWallet,
+			Message: err.Error(),
+		}
+	}
+	b64, err := p.B64Encode()
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	return btcjson.WalletProcessPsbtResult{
+		Psbt:     b64,
+		Complete: complete,
+	}, nil
+}
+
 // DecodeHexStr decodes the hex encoding of a string, possibly prepending a leading '0' character if there is an odd
 // number of bytes in the hex string. This is to prevent an error for an invalid hex string when using an odd number of
 // bytes when calling hex.Decode.
@@ -2330,3 +3011,26 @@ func DecodeHexStr(hexStr string) ([]byte, error) {
 	}
 	return decoded, nil
 }
+
+// DecodePsbtStr decodes a base64 encoded partially signed transaction.
+func DecodePsbtStr(b64 string) (*psbt.Packet, error) {
+	p, err := psbt.NewFromRawBytes(strings.NewReader(b64), true)
+	if err != nil {
+		Error(err)
+		return nil, DeserializationError{fmt.Errorf("PSBT decode failed: %v", err)}
+	}
+	return p, nil
+}
+
+// WitnessToHex formats the passed witness stack as a slice of hex-encoded strings, or nil if it is empty so it can be
+// omitted.
+func WitnessToHex(witness wire.TxWitness) []string {
+	if len(witness) == 0 {
+		return nil
+	}
+	result := make([]string, 0, len(witness))
+	for _, wit := range witness {
+		result = append(result, hex.EncodeToString(wit))
+	}
+	return result
+}
@@ -57,6 +57,21 @@ type (
 		Res *string
 		Err error
 	}
+	// BackupWalletRes is the result from a call to BackupWallet
+	BackupWalletRes struct {
+		Res *None
+		Err error
+	}
+	// BumpFeeRes is the result from a call to BumpFee
+	BumpFeeRes struct {
+		Res *btcjson.BumpFeeResult
+		Err error
+	}
+	// CombinePsbtRes is the result from a call to CombinePsbt
+	CombinePsbtRes struct {
+		Res *string
+		Err error
+	}
 	// CreateMultiSigRes is the result from a call to CreateMultiSig
 	CreateMultiSigRes struct {
 		Res *btcjson.CreateMultiSigResult
@@ -72,11 +87,26 @@ type (
 		Res *string
 		Err error
 	}
+	// DecodePsbtRes is the result from a call to DecodePsbt
+	DecodePsbtRes struct {
+		Res *btcjson.DecodePsbtResult
+		Err error
+	}
 	// DumpPrivKeyRes is the result from a call to DumpPrivKey
 	DumpPrivKeyRes struct {
 		Res *string
 		Err error
 	}
+	// DumpWalletRes is the result from a call to DumpWallet
+	DumpWalletRes struct {
+		Res *btcjson.DumpWalletResult
+		Err error
+	}
+	// FinalizePsbtRes is the result from a call to FinalizePsbt
+	FinalizePsbtRes struct {
+		Res *btcjson.FinalizePsbtResult
+		Err error
+	}
 	// GetAccountRes is the result from a call to GetAccount
 	GetAccountRes struct {
 		Res *string
@@ -147,6 +177,11 @@ type (
 		Res *float64
 		Err error
 	}
+	// ImportAccountRes is the result from a call to ImportAccount
+	ImportAccountRes struct {
+		Res *None
+		Err error
+	}
 	// HelpNoChainRPCRes is the result from a call to HelpNoChainRPC
 	HelpNoChainRPCRes struct {
 		Res *string
@@ -157,6 +192,11 @@ type (
 		Res *None
 		Err error
 	}
+	// ImportWalletRes is the result from a call to ImportWallet
+	ImportWalletRes struct {
+		Res *None
+		Err error
+	}
 	// KeypoolRefillRes is the result from a call to KeypoolRefill
 	KeypoolRefillRes struct {
 		Res *None
@@ -227,6 +267,16 @@ type (
 		Res *string
 		Err error
 	}
+	// SetLabelRes is the result from a call to SetLabel
+	SetLabelRes struct {
+		Res *None
+		Err error
+	}
+	// SetTxCommentRes is the result from a call to SetTxComment
+	SetTxCommentRes struct {
+		Res *None
+		Err error
+	}
 	// SetTxFeeRes is the result from a call to SetTxFee
 	SetTxFeeRes struct {
 		Res *bool
@@ -252,6 +302,11 @@ type (
 		Res *bool
 		Err error
 	}
+	// WalletCreateFundedPsbtRes is the result from a call to WalletCreateFundedPsbt
+	WalletCreateFundedPsbtRes struct {
+		Res *btcjson.WalletCreateFundedPsbtResult
+		Err error
+	}
 	// WalletIsLockedRes is the result from a call to WalletIsLocked
 	WalletIsLockedRes struct {
 		Res *bool
@@ -272,6 +327,11 @@ type (
 		Res *None
 		Err error
 	}
+	// WalletProcessPsbtRes is the result from a call to WalletProcessPsbt
+	WalletProcessPsbtRes struct {
+		Res *btcjson.WalletProcessPsbtResult
+		Err error
+	}
 )
 
 // RequestHandler is a handler function to handle an unmarshaled and parsed request into a marshalable response.  If the 
@@ -310,6 +370,15 @@ var RPCHandlers = map[string]struct {
 	"addmultisigaddress": {
 		Handler: AddMultiSigAddress, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan AddMultiSigAddressRes)} }},
+	"backupwallet": {
+		Handler: BackupWallet, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan BackupWalletRes)} }},
+	"bumpfee": {
+		Handler: BumpFee, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan BumpFeeRes)} }},
+	"combinepsbt": {
+		Handler: CombinePsbt, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan CombinePsbtRes)} }},
 	"createmultisig": {
 		Handler: CreateMultiSig, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan CreateMultiSigRes)} }},
@@ -319,9 +388,18 @@ var RPCHandlers = map[string]struct {
 	"dropwallethistory": {
 		Handler: HandleDropWalletHistory, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan HandleDropWalletHistoryRes)} }},
+	"decodepsbt": {
+		Handler: DecodePsbt, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan DecodePsbtRes)} }},
 	"dumpprivkey": {
 		Handler: DumpPrivKey, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan DumpPrivKeyRes)} }},
+	"dumpwallet": {
+		Handler: DumpWallet, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan DumpWalletRes)} }},
+	"finalizepsbt": {
+		Handler: FinalizePsbt, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan FinalizePsbtRes)} }},
 	"getaccount": {
 		Handler: GetAccount, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetAccountRes)} }},
@@ -364,12 +442,18 @@ var RPCHandlers = map[string]struct {
 	"getunconfirmedbalance": {
 		Handler: GetUnconfirmedBalance, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan GetUnconfirmedBalanceRes)} }},
+	"importaccount": {
+		Handler: ImportAccount, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan ImportAccountRes)} }},
 	"help": {
 		Handler: HelpNoChainRPC, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan HelpNoChainRPCRes)} }},
 	"importprivkey": {
 		Handler: ImportPrivKey, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan ImportPrivKeyRes)} }},
+	"importwallet": {
+		Handler: ImportWallet, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan ImportWalletRes)} }},
 	"keypoolrefill": {
 		Handler: KeypoolRefill, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan KeypoolRefillRes)} }},
@@ -412,6 +496,12 @@ var RPCHandlers = map[string]struct {
 	"sendtoaddress": {
 		Handler: SendToAddress, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan SendToAddressRes)} }},
+	"setlabel": {
+		Handler: SetLabel, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan SetLabelRes)} }},
+	"settxcomment": {
+		Handler: SetTxComment, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan SetTxCommentRes)} }},
 	"settxfee": {
 		Handler: SetTxFee, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan SetTxFeeRes)} }},
@@ -427,6 +517,9 @@ var RPCHandlers = map[string]struct {
 	"verifymessage": {
 		Handler: VerifyMessage, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan VerifyMessageRes)} }},
+	"walletcreatefundedpsbt": {
+		Handler: WalletCreateFundedPsbt, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan WalletCreateFundedPsbtRes)} }},
 	"walletislocked": {
 		Handler: WalletIsLocked, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan WalletIsLockedRes)} }},
@@ -439,6 +532,9 @@ var RPCHandlers = map[string]struct {
 	"walletpassphrasechange": {
 		Handler: WalletPassphraseChange, Call: make(chan API, 32),
 		Result: func() API { return API{Ch: make(chan WalletPassphraseChangeRes)} }},
+	"walletprocesspsbt": {
+		Handler: WalletProcessPsbt, Call: make(chan API, 32),
+		Result: func() API { return API{Ch: make(chan WalletProcessPsbtRes)} }},
 }
 
 // API functions
@@ -487,6 +583,129 @@ func (a API) AddMultiSigAddressWait(cmd *btcjson.AddMultisigAddressCmd) (out *st
 	return
 }
 
+// BackupWallet calls the method with the given parameters
+func (a API) BackupWallet(cmd *btcjson.BackupWalletCmd) (err error) {
+	RPCHandlers["backupwallet"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// BackupWalletCheck checks if a new message arrived on the result channel and returns true if it does, as well as 
+// storing the value in the Result field
+func (a API) BackupWalletCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan BackupWalletRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// BackupWalletGetRes returns a pointer to the value in the Result field
+func (a API) BackupWalletGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// BackupWalletWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) BackupWalletWait(cmd *btcjson.BackupWalletCmd) (out *None, err error) {
+	RPCHandlers["backupwallet"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan BackupWalletRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// BumpFee calls the method with the given parameters
+func (a API) BumpFee(cmd *btcjson.BumpFeeCmd) (err error) {
+	RPCHandlers["bumpfee"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// BumpFeeCheck checks if a new message arrived on the result channel and returns true if it does, as well as
+// storing the value in the Result field
+func (a API) BumpFeeCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan BumpFeeRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// BumpFeeGetRes returns a pointer to the value in the Result field
+func (a API) BumpFeeGetRes() (out *btcjson.BumpFeeResult, err error) {
+	out, _ = a.Result.(*btcjson.BumpFeeResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// BumpFeeWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) BumpFeeWait(cmd *btcjson.BumpFeeCmd) (out *btcjson.BumpFeeResult, err error) {
+	RPCHandlers["bumpfee"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan BumpFeeRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// CombinePsbt calls the method with the given parameters
+func (a API) CombinePsbt(cmd *btcjson.CombinePsbtCmd) (err error) {
+	RPCHandlers["combinepsbt"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// CombinePsbtCheck checks if a new message arrived on the result channel and returns true if it does, as well as
+// storing the value in the Result field
+func (a API) CombinePsbtCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan CombinePsbtRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// CombinePsbtGetRes returns a pointer to the value in the Result field
+func (a API) CombinePsbtGetRes() (out *string, err error) {
+	out, _ = a.Result.(*string)
+	err, _ = a.Result.(error)
+	return
+}
+
+// CombinePsbtWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) CombinePsbtWait(cmd *btcjson.CombinePsbtCmd) (out *string, err error) {
+	RPCHandlers["combinepsbt"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan CombinePsbtRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // CreateMultiSig calls the method with the given parameters
 func (a API) CreateMultiSig(cmd *btcjson.CreateMultisigCmd) (err error) {
 	RPCHandlers["createmultisig"].Call <- API{a.Ch, cmd, nil}
@@ -610,6 +829,47 @@ func (a API) HandleDropWalletHistoryWait(cmd *None) (out *string, err error) {
 	return
 }
 
+// DecodePsbt calls the method with the given parameters
+func (a API) DecodePsbt(cmd *btcjson.DecodePsbtCmd) (err error) {
+	RPCHandlers["decodepsbt"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// DecodePsbtCheck checks if a new message arrived on the result channel and returns true if it does, as well as
+// storing the value in the Result field
+func (a API) DecodePsbtCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan DecodePsbtRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// DecodePsbtGetRes returns a pointer to the value in the Result field
+func (a API) DecodePsbtGetRes() (out *btcjson.DecodePsbtResult, err error) {
+	out, _ = a.Result.(*btcjson.DecodePsbtResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// DecodePsbtWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) DecodePsbtWait(cmd *btcjson.DecodePsbtCmd) (out *btcjson.DecodePsbtResult, err error) {
+	RPCHandlers["decodepsbt"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan DecodePsbtRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // DumpPrivKey calls the method with the given parameters
 func (a API) DumpPrivKey(cmd *btcjson.DumpPrivKeyCmd) (err error) {
 	RPCHandlers["dumpprivkey"].Call <- API{a.Ch, cmd, nil}
@@ -651,6 +911,88 @@ func (a API) DumpPrivKeyWait(cmd *btcjson.DumpPrivKeyCmd) (out *string, err erro
 	return
 }
 
+// DumpWallet calls the method with the given parameters
+func (a API) DumpWallet(cmd *btcjson.DumpWalletCmd) (err error) {
+	RPCHandlers["dumpwallet"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// DumpWalletCheck checks if a new message arrived on the result channel and returns true if it does, as well as 
+// storing the value in the Result field
+func (a API) DumpWalletCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan DumpWalletRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// DumpWalletGetRes returns a pointer to the value in the Result field
+func (a API) DumpWalletGetRes() (out *btcjson.DumpWalletResult, err error) {
+	out, _ = a.Result.(*btcjson.DumpWalletResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// DumpWalletWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) DumpWalletWait(cmd *btcjson.DumpWalletCmd) (out *btcjson.DumpWalletResult, err error) {
+	RPCHandlers["dumpwallet"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan DumpWalletRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// FinalizePsbt calls the method with the given parameters
+func (a API) FinalizePsbt(cmd *btcjson.FinalizePsbtCmd) (err error) {
+	RPCHandlers["finalizepsbt"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// FinalizePsbtCheck checks if a new message arrived on the result channel and returns true if it does, as well as
+// storing the value in the Result field
+func (a API) FinalizePsbtCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan FinalizePsbtRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// FinalizePsbtGetRes returns a pointer to the value in the Result field
+func (a API) FinalizePsbtGetRes() (out *btcjson.FinalizePsbtResult, err error) {
+	out, _ = a.Result.(*btcjson.FinalizePsbtResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// FinalizePsbtWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) FinalizePsbtWait(cmd *btcjson.FinalizePsbtCmd) (out *btcjson.FinalizePsbtResult, err error) {
+	RPCHandlers["finalizepsbt"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan FinalizePsbtRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // GetAccount calls the method with the given parameters
 func (a API) GetAccount(cmd *btcjson.GetAccountCmd) (err error) {
 	RPCHandlers["getaccount"].Call <- API{a.Ch, cmd, nil}
@@ -1225,6 +1567,47 @@ func (a API) GetUnconfirmedBalanceWait(cmd *btcjson.GetUnconfirmedBalanceCmd) (o
 	return
 }
 
+// ImportAccount calls the method with the given parameters
+func (a API) ImportAccount(cmd *btcjson.ImportAccountCmd) (err error) {
+	RPCHandlers["importaccount"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// ImportAccountCheck checks if a new message arrived on the result channel and returns true if it does, as well as 
+// storing the value in the Result field
+func (a API) ImportAccountCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan ImportAccountRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// ImportAccountGetRes returns a pointer to the value in the Result field
+func (a API) ImportAccountGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// ImportAccountWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) ImportAccountWait(cmd *btcjson.ImportAccountCmd) (out *None, err error) {
+	RPCHandlers["importaccount"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan ImportAccountRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // HelpNoChainRPC calls the method with the given parameters
 func (a API) HelpNoChainRPC(cmd btcjson.HelpCmd) (err error) {
 	RPCHandlers["help"].Call <- API{a.Ch, cmd, nil}
@@ -1307,6 +1690,47 @@ func (a API) ImportPrivKeyWait(cmd *btcjson.ImportPrivKeyCmd) (out *None, err er
 	return
 }
 
+// ImportWallet calls the method with the given parameters
+func (a API) ImportWallet(cmd *btcjson.ImportWalletCmd) (err error) {
+	RPCHandlers["importwallet"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// ImportWalletCheck checks if a new message arrived on the result channel and returns true if it does, as well as 
+// storing the value in the Result field
+func (a API) ImportWalletCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan ImportWalletRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// ImportWalletGetRes returns a pointer to the value in the Result field
+func (a API) ImportWalletGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// ImportWalletWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) ImportWalletWait(cmd *btcjson.ImportWalletCmd) (out *None, err error) {
+	RPCHandlers["importwallet"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan ImportWalletRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // KeypoolRefill calls the method with the given parameters
 func (a API) KeypoolRefill(cmd *None) (err error) {
 	RPCHandlers["keypoolrefill"].Call <- API{a.Ch, cmd, nil}
@@ -1875,7 +2299,89 @@ func (a API) SendToAddressWait(cmd *btcjson.SendToAddressCmd) (out *string, err
 	select {
 	case <-time.After(time.Second * 5):
 		break
-	case o := <-a.Ch.(chan SendToAddressRes):
+	case o := <-a.Ch.(chan SendToAddressRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// SetLabel calls the method with the given parameters
+func (a API) SetLabel(cmd *btcjson.SetLabelCmd) (err error) {
+	RPCHandlers["setlabel"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// SetLabelCheck checks if a new message arrived on the result channel and returns true if it does, as well as 
+// storing the value in the Result field
+func (a API) SetLabelCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan SetLabelRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// SetLabelGetRes returns a pointer to the value in the Result field
+func (a API) SetLabelGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// SetLabelWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) SetLabelWait(cmd *btcjson.SetLabelCmd) (out *None, err error) {
+	RPCHandlers["setlabel"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan SetLabelRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
+// SetTxComment calls the method with the given parameters
+func (a API) SetTxComment(cmd *btcjson.SetTxCommentCmd) (err error) {
+	RPCHandlers["settxcomment"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// SetTxCommentCheck checks if a new message arrived on the result channel and returns true if it does, as well as 
+// storing the value in the Result field
+func (a API) SetTxCommentCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan SetTxCommentRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// SetTxCommentGetRes returns a pointer to the value in the Result field
+func (a API) SetTxCommentGetRes() (out *None, err error) {
+	out, _ = a.Result.(*None)
+	err, _ = a.Result.(error)
+	return
+}
+
+// SetTxCommentWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) SetTxCommentWait(cmd *btcjson.SetTxCommentCmd) (out *None, err error) {
+	RPCHandlers["settxcomment"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan SetTxCommentRes):
 		out, err = o.Res, o.Err
 	}
 	return
@@ -2086,6 +2592,47 @@ func (a API) VerifyMessageWait(cmd *btcjson.VerifyMessageCmd) (out *bool, err er
 	return
 }
 
+// WalletCreateFundedPsbt calls the method with the given parameters
+func (a API) WalletCreateFundedPsbt(cmd *btcjson.WalletCreateFundedPsbtCmd) (err error) {
+	RPCHandlers["walletcreatefundedpsbt"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// WalletCreateFundedPsbtCheck checks if a new message arrived on the result channel and returns true if it does, as well as
+// storing the value in the Result field
+func (a API) WalletCreateFundedPsbtCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan WalletCreateFundedPsbtRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// WalletCreateFundedPsbtGetRes returns a pointer to the value in the Result field
+func (a API) WalletCreateFundedPsbtGetRes() (out *btcjson.WalletCreateFundedPsbtResult, err error) {
+	out, _ = a.Result.(*btcjson.WalletCreateFundedPsbtResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// WalletCreateFundedPsbtWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) WalletCreateFundedPsbtWait(cmd *btcjson.WalletCreateFundedPsbtCmd) (out *btcjson.WalletCreateFundedPsbtResult, err error) {
+	RPCHandlers["walletcreatefundedpsbt"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan WalletCreateFundedPsbtRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // WalletIsLocked calls the method with the given parameters
 func (a API) WalletIsLocked(cmd *None) (err error) {
 	RPCHandlers["walletislocked"].Call <- API{a.Ch, cmd, nil}
@@ -2250,6 +2797,47 @@ func (a API) WalletPassphraseChangeWait(cmd *btcjson.WalletPassphraseChangeCmd)
 	return
 }
 
+// WalletProcessPsbt calls the method with the given parameters
+func (a API) WalletProcessPsbt(cmd *btcjson.WalletProcessPsbtCmd) (err error) {
+	RPCHandlers["walletprocesspsbt"].Call <- API{a.Ch, cmd, nil}
+	return
+}
+
+// WalletProcessPsbtCheck checks if a new message arrived on the result channel and returns true if it does, as well as
+// storing the value in the Result field
+func (a API) WalletProcessPsbtCheck() (isNew bool) {
+	select {
+	case o := <-a.Ch.(chan WalletProcessPsbtRes):
+		if o.Err != nil {
+			a.Result = o.Err
+		} else {
+			a.Result = o.Res
+		}
+		isNew = true
+	default:
+	}
+	return
+}
+
+// WalletProcessPsbtGetRes returns a pointer to the value in the Result field
+func (a API) WalletProcessPsbtGetRes() (out *btcjson.WalletProcessPsbtResult, err error) {
+	out, _ = a.Result.(*btcjson.WalletProcessPsbtResult)
+	err, _ = a.Result.(error)
+	return
+}
+
+// WalletProcessPsbtWait calls the method and blocks until it returns or 5 seconds passes
+func (a API) WalletProcessPsbtWait(cmd *btcjson.WalletProcessPsbtCmd) (out *btcjson.WalletProcessPsbtResult, err error) {
+	RPCHandlers["walletprocesspsbt"].Call <- API{a.Ch, cmd, nil}
+	select {
+	case <-time.After(time.Second * 5):
+		break
+	case o := <-a.Ch.(chan WalletProcessPsbtRes):
+		out, err = o.Res, o.Err
+	}
+	return
+}
+
 // RunAPI starts up the api handler server that receives rpc.API messages and runs the handler and returns the result
 // Note that the parameters are type asserted to prevent the consumer of the API from sending wrong message types not
 // because it's necessary since they are interfaces end to end
@@ -2270,6 +2858,30 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan AddMultiSigAddressRes) <- AddMultiSigAddressRes{&r, err}
 				}
+			case msg := <-nrh["backupwallet"].Call:
+				if res, err = nrh["backupwallet"].
+					Handler(msg.Params.(*btcjson.BackupWalletCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan BackupWalletRes) <- BackupWalletRes{&r, err}
+				}
+			case msg := <-nrh["bumpfee"].Call:
+				if res, err = nrh["bumpfee"].
+					Handler(msg.Params.(*btcjson.BumpFeeCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(btcjson.BumpFeeResult); ok {
+					msg.Ch.(chan BumpFeeRes) <- BumpFeeRes{&r, err}
+				}
+			case msg := <-nrh["combinepsbt"].Call:
+				if res, err = nrh["combinepsbt"].
+					Handler(msg.Params.(*btcjson.CombinePsbtCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(string); ok {
+					msg.Ch.(chan CombinePsbtRes) <- CombinePsbtRes{&r, err}
+				}
 			case msg := <-nrh["createmultisig"].Call:
 				if res, err = nrh["createmultisig"].
 					Handler(msg.Params.(*btcjson.CreateMultisigCmd), wallet,
@@ -2294,6 +2906,14 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan HandleDropWalletHistoryRes) <- HandleDropWalletHistoryRes{&r, err}
 				}
+			case msg := <-nrh["decodepsbt"].Call:
+				if res, err = nrh["decodepsbt"].
+					Handler(msg.Params.(*btcjson.DecodePsbtCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(btcjson.DecodePsbtResult); ok {
+					msg.Ch.(chan DecodePsbtRes) <- DecodePsbtRes{&r, err}
+				}
 			case msg := <-nrh["dumpprivkey"].Call:
 				if res, err = nrh["dumpprivkey"].
 					Handler(msg.Params.(*btcjson.DumpPrivKeyCmd), wallet,
@@ -2302,6 +2922,22 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan DumpPrivKeyRes) <- DumpPrivKeyRes{&r, err}
 				}
+			case msg := <-nrh["dumpwallet"].Call:
+				if res, err = nrh["dumpwallet"].
+					Handler(msg.Params.(*btcjson.DumpWalletCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(btcjson.DumpWalletResult); ok {
+					msg.Ch.(chan DumpWalletRes) <- DumpWalletRes{&r, err}
+				}
+			case msg := <-nrh["finalizepsbt"].Call:
+				if res, err = nrh["finalizepsbt"].
+					Handler(msg.Params.(*btcjson.FinalizePsbtCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(btcjson.FinalizePsbtResult); ok {
+					msg.Ch.(chan FinalizePsbtRes) <- FinalizePsbtRes{&r, err}
+				}
 			case msg := <-nrh["getaccount"].Call:
 				if res, err = nrh["getaccount"].
 					Handler(msg.Params.(*btcjson.GetAccountCmd), wallet,
@@ -2414,6 +3050,14 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(float64); ok {
 					msg.Ch.(chan GetUnconfirmedBalanceRes) <- GetUnconfirmedBalanceRes{&r, err}
 				}
+			case msg := <-nrh["importaccount"].Call:
+				if res, err = nrh["importaccount"].
+					Handler(msg.Params.(*btcjson.ImportAccountCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan ImportAccountRes) <- ImportAccountRes{&r, err}
+				}
 			case msg := <-nrh["help"].Call:
 				if res, err = nrh["help"].
 					Handler(msg.Params.(btcjson.HelpCmd), wallet,
@@ -2430,6 +3074,14 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(None); ok {
 					msg.Ch.(chan ImportPrivKeyRes) <- ImportPrivKeyRes{&r, err}
 				}
+			case msg := <-nrh["importwallet"].Call:
+				if res, err = nrh["importwallet"].
+					Handler(msg.Params.(*btcjson.ImportWalletCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan ImportWalletRes) <- ImportWalletRes{&r, err}
+				}
 			case msg := <-nrh["keypoolrefill"].Call:
 				if res, err = nrh["keypoolrefill"].
 					Handler(msg.Params.(*None), wallet,
@@ -2542,6 +3194,22 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(string); ok {
 					msg.Ch.(chan SendToAddressRes) <- SendToAddressRes{&r, err}
 				}
+			case msg := <-nrh["setlabel"].Call:
+				if res, err = nrh["setlabel"].
+					Handler(msg.Params.(*btcjson.SetLabelCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan SetLabelRes) <- SetLabelRes{&r, err}
+				}
+			case msg := <-nrh["settxcomment"].Call:
+				if res, err = nrh["settxcomment"].
+					Handler(msg.Params.(*btcjson.SetTxCommentCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(None); ok {
+					msg.Ch.(chan SetTxCommentRes) <- SetTxCommentRes{&r, err}
+				}
 			case msg := <-nrh["settxfee"].Call:
 				if res, err = nrh["settxfee"].
 					Handler(msg.Params.(*btcjson.SetTxFeeCmd), wallet,
@@ -2582,6 +3250,14 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(bool); ok {
 					msg.Ch.(chan VerifyMessageRes) <- VerifyMessageRes{&r, err}
 				}
+			case msg := <-nrh["walletcreatefundedpsbt"].Call:
+				if res, err = nrh["walletcreatefundedpsbt"].
+					Handler(msg.Params.(*btcjson.WalletCreateFundedPsbtCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(btcjson.WalletCreateFundedPsbtResult); ok {
+					msg.Ch.(chan WalletCreateFundedPsbtRes) <- WalletCreateFundedPsbtRes{&r, err}
+				}
 			case msg := <-nrh["walletislocked"].Call:
 				if res, err = nrh["walletislocked"].
 					Handler(msg.Params.(*None), wallet,
@@ -2614,6 +3290,14 @@ func RunAPI(chainRPC *chain.RPCClient, wallet *wallet.Wallet,
 				if r, ok := res.(None); ok {
 					msg.Ch.(chan WalletPassphraseChangeRes) <- WalletPassphraseChangeRes{&r, err}
 				}
+			case msg := <-nrh["walletprocesspsbt"].Call:
+				if res, err = nrh["walletprocesspsbt"].
+					Handler(msg.Params.(*btcjson.WalletProcessPsbtCmd), wallet,
+						chainRPC); Check(err) {
+				}
+				if r, ok := res.(btcjson.WalletProcessPsbtResult); ok {
+					msg.Ch.(chan WalletProcessPsbtRes) <- WalletProcessPsbtRes{&r, err}
+				}
 			case <-quit:
 				Debug("stopping wallet cAPI")
 				return
@@ -2637,6 +3321,45 @@ func (c *CAPI) AddMultiSigAddress(req *btcjson.AddMultisigAddressCmd, resp strin
 	return
 }
 
+func (c *CAPI) BackupWallet(req *btcjson.BackupWalletCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["backupwallet"].Result()
+	res.Params = req
+	nrh["backupwallet"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) BumpFee(req *btcjson.BumpFeeCmd, resp btcjson.BumpFeeResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["bumpfee"].Result()
+	res.Params = req
+	nrh["bumpfee"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.BumpFeeResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) CombinePsbt(req *btcjson.CombinePsbtCmd, resp string) (err error) {
+	nrh := RPCHandlers
+	res := nrh["combinepsbt"].Result()
+	res.Params = req
+	nrh["combinepsbt"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan string):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) CreateMultiSig(req *btcjson.CreateMultisigCmd, resp btcjson.CreateMultiSigResult) (err error) {
 	nrh := RPCHandlers
 	res := nrh["createmultisig"].Result()
@@ -2676,6 +3399,19 @@ func (c *CAPI) HandleDropWalletHistory(req *None, resp string) (err error) {
 	return
 }
 
+func (c *CAPI) DecodePsbt(req *btcjson.DecodePsbtCmd, resp btcjson.DecodePsbtResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["decodepsbt"].Result()
+	res.Params = req
+	nrh["decodepsbt"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.DecodePsbtResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) DumpPrivKey(req *btcjson.DumpPrivKeyCmd, resp string) (err error) {
 	nrh := RPCHandlers
 	res := nrh["dumpprivkey"].Result()
@@ -2689,6 +3425,32 @@ func (c *CAPI) DumpPrivKey(req *btcjson.DumpPrivKeyCmd, resp string) (err error)
 	return
 }
 
+func (c *CAPI) DumpWallet(req *btcjson.DumpWalletCmd, resp btcjson.DumpWalletResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["dumpwallet"].Result()
+	res.Params = req
+	nrh["dumpwallet"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.DumpWalletResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) FinalizePsbt(req *btcjson.FinalizePsbtCmd, resp btcjson.FinalizePsbtResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["finalizepsbt"].Result()
+	res.Params = req
+	nrh["finalizepsbt"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.FinalizePsbtResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) GetAccount(req *btcjson.GetAccountCmd, resp string) (err error) {
 	nrh := RPCHandlers
 	res := nrh["getaccount"].Result()
@@ -2871,6 +3633,19 @@ func (c *CAPI) GetUnconfirmedBalance(req *btcjson.GetUnconfirmedBalanceCmd, resp
 	return
 }
 
+func (c *CAPI) ImportAccount(req *btcjson.ImportAccountCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["importaccount"].Result()
+	res.Params = req
+	nrh["importaccount"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) HelpNoChainRPC(req btcjson.HelpCmd, resp string) (err error) {
 	nrh := RPCHandlers
 	res := nrh["help"].Result()
@@ -2897,6 +3672,19 @@ func (c *CAPI) ImportPrivKey(req *btcjson.ImportPrivKeyCmd, resp None) (err erro
 	return
 }
 
+func (c *CAPI) ImportWallet(req *btcjson.ImportWalletCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["importwallet"].Result()
+	res.Params = req
+	nrh["importwallet"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) KeypoolRefill(req *None, resp None) (err error) {
 	nrh := RPCHandlers
 	res := nrh["keypoolrefill"].Result()
@@ -3079,6 +3867,32 @@ func (c *CAPI) SendToAddress(req *btcjson.SendToAddressCmd, resp string) (err er
 	return
 }
 
+func (c *CAPI) SetLabel(req *btcjson.SetLabelCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["setlabel"].Result()
+	res.Params = req
+	nrh["setlabel"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
+func (c *CAPI) SetTxComment(req *btcjson.SetTxCommentCmd, resp None) (err error) {
+	nrh := RPCHandlers
+	res := nrh["settxcomment"].Result()
+	res.Params = req
+	nrh["settxcomment"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan None):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) SetTxFee(req *btcjson.SetTxFeeCmd, resp bool) (err error) {
 	nrh := RPCHandlers
 	res := nrh["settxfee"].Result()
@@ -3144,6 +3958,19 @@ func (c *CAPI) VerifyMessage(req *btcjson.VerifyMessageCmd, resp bool) (err erro
 	return
 }
 
+func (c *CAPI) WalletCreateFundedPsbt(req *btcjson.WalletCreateFundedPsbtCmd, resp btcjson.WalletCreateFundedPsbtResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["walletcreatefundedpsbt"].Result()
+	res.Params = req
+	nrh["walletcreatefundedpsbt"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.WalletCreateFundedPsbtResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 func (c *CAPI) WalletIsLocked(req *None, resp bool) (err error) {
 	nrh := RPCHandlers
 	res := nrh["walletislocked"].Result()
@@ -3196,6 +4023,19 @@ func (c *CAPI) WalletPassphraseChange(req *btcjson.WalletPassphraseChangeCmd, re
 	return
 }
 
+func (c *CAPI) WalletProcessPsbt(req *btcjson.WalletProcessPsbtCmd, resp btcjson.WalletProcessPsbtResult) (err error) {
+	nrh := RPCHandlers
+	res := nrh["walletprocesspsbt"].Result()
+	res.Params = req
+	nrh["walletprocesspsbt"].Call <- res
+	select {
+	case resp = <-res.Ch.(chan btcjson.WalletProcessPsbtResult):
+	case <-time.After(c.Timeout):
+	case <-c.quit:
+	}
+	return
+}
+
 // Client call wrappers for a CAPI client with a given Conn
 
 func (r *CAPIClient) AddMultiSigAddress(cmd ...*btcjson.AddMultisigAddressCmd) (res string, err error) {
@@ -3208,6 +4048,26 @@ func (r *CAPIClient) AddMultiSigAddress(cmd ...*btcjson.AddMultisigAddressCmd) (
 	return
 }
 
+func (r *CAPIClient) BumpFee(cmd ...*btcjson.BumpFeeCmd) (res btcjson.BumpFeeResult, err error) {
+	var c *btcjson.BumpFeeCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.BumpFee", c, &res); Check(err) {
+	}
+	return
+}
+
+func (r *CAPIClient) CombinePsbt(cmd ...*btcjson.CombinePsbtCmd) (res string, err error) {
+	var c *btcjson.CombinePsbtCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.CombinePsbt", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) CreateMultiSig(cmd ...*btcjson.CreateMultisigCmd) (res btcjson.CreateMultiSigResult, err error) {
 	var c *btcjson.CreateMultisigCmd
 	if len(cmd) > 0 {
@@ -3238,6 +4098,16 @@ func (r *CAPIClient) HandleDropWalletHistory(cmd ...*None) (res string, err erro
 	return
 }
 
+func (r *CAPIClient) DecodePsbt(cmd ...*btcjson.DecodePsbtCmd) (res btcjson.DecodePsbtResult, err error) {
+	var c *btcjson.DecodePsbtCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.DecodePsbt", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) DumpPrivKey(cmd ...*btcjson.DumpPrivKeyCmd) (res string, err error) {
 	var c *btcjson.DumpPrivKeyCmd
 	if len(cmd) > 0 {
@@ -3248,6 +4118,16 @@ func (r *CAPIClient) DumpPrivKey(cmd ...*btcjson.DumpPrivKeyCmd) (res string, er
 	return
 }
 
+func (r *CAPIClient) FinalizePsbt(cmd ...*btcjson.FinalizePsbtCmd) (res btcjson.FinalizePsbtResult, err error) {
+	var c *btcjson.FinalizePsbtCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.FinalizePsbt", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) GetAccount(cmd ...*btcjson.GetAccountCmd) (res string, err error) {
 	var c *btcjson.GetAccountCmd
 	if len(cmd) > 0 {
@@ -3598,6 +4478,16 @@ func (r *CAPIClient) VerifyMessage(cmd ...*btcjson.VerifyMessageCmd) (res bool,
 	return
 }
 
+func (r *CAPIClient) WalletCreateFundedPsbt(cmd ...*btcjson.WalletCreateFundedPsbtCmd) (res btcjson.WalletCreateFundedPsbtResult, err error) {
+	var c *btcjson.WalletCreateFundedPsbtCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.WalletCreateFundedPsbt", c, &res); Check(err) {
+	}
+	return
+}
+
 func (r *CAPIClient) WalletIsLocked(cmd ...*None) (res bool, err error) {
 	var c *None
 	if len(cmd) > 0 {
@@ -3637,3 +4527,13 @@ func (r *CAPIClient) WalletPassphraseChange(cmd ...*btcjson.WalletPassphraseChan
 	}
 	return
 }
+
+func (r *CAPIClient) WalletProcessPsbt(cmd ...*btcjson.WalletProcessPsbtCmd) (res btcjson.WalletProcessPsbtResult, err error) {
+	var c *btcjson.WalletProcessPsbtCmd
+	if len(cmd) > 0 {
+		c = cmd[0]
+	}
+	if err = r.Call("CAPI.WalletProcessPsbt", c, &res); Check(err) {
+	}
+	return
+}
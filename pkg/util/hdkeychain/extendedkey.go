@@ -137,6 +137,12 @@ func (k *ExtendedKey) Depth() uint8 {
 	return k.depth
 }
 
+// Version returns the version bytes the extended key is serialized with, which identify the network and the kind of
+// key it is for.
+func (k *ExtendedKey) Version() []byte {
+	return k.version
+}
+
 // ParentFingerprint returns a fingerprint of the parent extended key from which this one was derived.
 func (k *ExtendedKey) ParentFingerprint() uint32 {
 	return binary.BigEndian.Uint32(k.parentFP)
@@ -0,0 +1,75 @@
+package waddrmgr
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/util/hdkeychain"
+)
+
+// accountKeyVersion maps the SLIP-0132 version bytes of an extended public key to the key scope of the accounts it is
+// used for. The version bytes are only valid together with the extended public key version of the network they belong
+// to.
+type accountKeyVersion struct {
+	netPubKeyID [4]byte
+	version     [4]byte
+	scope       KeyScope
+}
+
+// accountKeyVersions are the extended public key versions other than the network's own that are known to identify the
+// key scope of an account.
+var accountKeyVersions = []accountKeyVersion{
+	// ypub and zpub on the main network.
+	{[4]byte{0x04, 0x88, 0xb2, 0x1e}, [4]byte{0x04, 0x9d, 0x7c, 0xb2}, KeyScopeBIP0049Plus},
+	{[4]byte{0x04, 0x88, 0xb2, 0x1e}, [4]byte{0x04, 0xb2, 0x47, 0x46}, KeyScopeBIP0084},
+	// upub and vpub on the test networks.
+	{[4]byte{0x04, 0x35, 0x87, 0xcf}, [4]byte{0x04, 0x4a, 0x52, 0x62}, KeyScopeBIP0049Plus},
+	{[4]byte{0x04, 0x35, 0x87, 0xcf}, [4]byte{0x04, 0x5f, 0x1c, 0xf6}, KeyScopeBIP0084},
+}
+
+// ParseAccountPubKey parses the extended public key of a BIP0044-like account, which is the key at
+// m/purpose'/cointype'/account', for the passed network, and returns it along with the key scope it belongs to. A key
+// with the network's own version (xpub, tpub) is taken to be a BIP0044 account, while the SLIP-0132 versions ypub/upub
+// and zpub/vpub are BIP0049 and BIP0084 accounts. The returned key is converted to the network's version, as the
+// manager stores it. Extended private keys are refused, since only the public key of an account is ever imported.
+func ParseAccountPubKey(key string, net *netparams.Params) (*hdkeychain.ExtendedKey, KeyScope, error) {
+	extKey, err := hdkeychain.NewKeyFromString(key)
+	if err != nil {
+		Error(err)
+		str := "failed to parse the extended public key"
+		return nil, KeyScope{}, managerError(ErrKeyChain, str, err)
+	}
+	if extKey.IsPrivate() {
+		str := "an extended private key was given where an extended public key is required"
+		return nil, KeyScope{}, managerError(ErrKeyChain, str, nil)
+	}
+	// The account key is the third level below the master key, purpose and cointype being the first two.
+	if extKey.Depth() != 3 {
+		str := fmt.Sprintf("the extended public key has depth %d while an account key has depth 3",
+			extKey.Depth())
+		return nil, KeyScope{}, managerError(ErrKeyChain, str, nil)
+	}
+	scope, ok := accountKeyScope(extKey.Version(), net)
+	if !ok {
+		str := fmt.Sprintf("the extended public key version %x is not known for the %s network",
+			extKey.Version(), net.Name)
+		return nil, KeyScope{}, managerError(ErrWrongNet, str, nil)
+	}
+	extKey.SetNet(net)
+	return extKey, scope, nil
+}
+
+// accountKeyScope returns the key scope of the accounts an extended public key of the passed version is used for on the
+// passed network, and whether the version is known at all.
+func accountKeyScope(version []byte, net *netparams.Params) (KeyScope, bool) {
+	if bytes.Equal(version, net.HDPublicKeyID[:]) {
+		return KeyScopeBIP0044, true
+	}
+	for _, v := range accountKeyVersions {
+		if v.netPubKeyID == net.HDPublicKeyID && bytes.Equal(version, v.version[:]) {
+			return v.scope, true
+		}
+	}
+	return KeyScope{}, false
+}
@@ -244,6 +244,10 @@ func (a *managedAddress) PrivKey() (*ec.PrivateKey, error) {
 	if a.manager.rootManager.IsLocked() {
 		return nil, managerError(ErrLocked, errLocked, nil)
 	}
+	// Addresses of accounts imported from extended public keys have no private key even when unlocked.
+	if len(a.privKeyEncrypted) == 0 {
+		return nil, managerError(ErrWatchingOnly, errWatchingOnly, nil)
+	}
 	// Decrypt the key as needed. Also, make sure it's a copy since the private key stored in memory can be cleared at
 	// any time. Otherwise the returned private key could be invalidated from under the caller.
 	privKeyCopy, err := a.unlock(a.manager.rootManager.cryptoKeyPriv)
@@ -174,6 +174,12 @@ var (
 	acctIDIdxBucketName = []byte("acctididx")
 	// usedAddrBucketName is the name of the bucket that stores an addresses hash if the address has been used or not.
 	usedAddrBucketName = []byte("usedaddrs")
+	// addrLabelBucketName is the name of the bucket that stores the label the user gave an address, keyed by the
+	// address hash. The bucket was added without a manager version bump, so it is created on the first label written
+	// to a scope of an older wallet and a missing bucket reads as no labels.
+	//
+	// addr hash => label string
+	addrLabelBucketName = []byte("addrlabels")
 	// meta is used to store meta-data about the address manager e.g. last account number
 	metaBucketName = []byte("meta")
 	// lastAccountName is used to store the metadata - last account in the manager
@@ -1231,6 +1237,50 @@ func markAddressUsed(ns walletdb.ReadWriteBucket, scope *KeyScope,
 	return nil
 }
 
+// fetchAddrLabel returns the label of the provided address id, which is empty if the address has none.
+func fetchAddrLabel(ns walletdb.ReadBucket, scope *KeyScope,
+	addressID []byte) (string, error) {
+	scopedBucket, err := fetchReadScopeBucket(ns, scope)
+	if err != nil {
+		Error(err)
+		return "", err
+	}
+	bucket := scopedBucket.NestedReadBucket(addrLabelBucketName)
+	if bucket == nil {
+		return "", nil
+	}
+	addrHash := sha256.Sum256(addressID)
+	return string(bucket.Get(addrHash[:])), nil
+}
+
+// putAddrLabel stores the label of the provided address id in the database. An empty label removes it.
+func putAddrLabel(ns walletdb.ReadWriteBucket, scope *KeyScope,
+	addressID []byte, label string) error {
+	scopedBucket, err := fetchWriteScopeBucket(ns, scope)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	bucket, err := scopedBucket.CreateBucketIfNotExists(addrLabelBucketName)
+	if err != nil {
+		Error(err)
+		str := "failed to create address label bucket"
+		return managerError(ErrDatabase, str, err)
+	}
+	addrHash := sha256.Sum256(addressID)
+	if label == "" {
+		err = bucket.Delete(addrHash[:])
+	} else {
+		err = bucket.Put(addrHash[:], []byte(label))
+	}
+	if err != nil {
+		Error(err)
+		str := fmt.Sprintf("failed to store label of address %x", addressID)
+		return managerError(ErrDatabase, str, err)
+	}
+	return nil
+}
+
 // fetchAddress loads address information for the provided address id from the database. The returned value is one of
 // the address rows for the specific address type. The caller should use type assertions to ascertain the type. The
 // caller should prefix the error message with the address which caused the failure.
@@ -1787,6 +1837,12 @@ func createScopedManagerNS(ns walletdb.ReadWriteBucket, scope *KeyScope) error {
 		str := "failed to create address index bucket"
 		return managerError(ErrDatabase, str, err)
 	}
+	_, err = scopeBucket.CreateBucket(addrLabelBucketName)
+	if err != nil {
+		Error(err)
+		str := "failed to create address label bucket"
+		return managerError(ErrDatabase, str, err)
+	}
 	_, err = scopeBucket.CreateBucket(acctNameIdxBucketName)
 	if err != nil {
 		Error(err)
@@ -4,6 +4,7 @@ import (
 	"crypto/rand"
 	"crypto/sha512"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -116,6 +117,12 @@ type accountInfo struct {
 	nextInternalIndex uint32
 }
 
+// watchOnly returns whether the account was created from an extended public key, in which case there is no private
+// extended key for it even when the address manager is unlocked.
+func (a *accountInfo) watchOnly() bool {
+	return len(a.acctKeyEncrypted) == 0
+}
+
 // AccountProperties contains properties associated with each account, such as the account name, number, and the nubmer
 // of derived and imported keys.
 type AccountProperties struct {
@@ -124,6 +131,9 @@ type AccountProperties struct {
 	ExternalKeyCount uint32
 	InternalKeyCount uint32
 	ImportedKeyCount uint32
+	// IsWatchOnly is set when no private keys can be had for the account, either because the whole address manager is
+	// watching-only or because the account was imported from an extended public key.
+	IsWatchOnly bool
 }
 
 // unlockDeriveInfo houses the information needed to derive a private key for a managed address when the address manager
@@ -442,7 +452,7 @@ func (m *Manager) FetchScopedKeyManager(scope KeyScope) (*ScopedKeyManager, erro
 func (m *Manager) ActiveScopedKeyManagers() []*ScopedKeyManager {
 	m.mtx.RLock()
 	defer m.mtx.RUnlock()
-	scopedManagers := make([]*ScopedKeyManager, len(m.scopedManagers))
+	scopedManagers := make([]*ScopedKeyManager, 0, len(m.scopedManagers))
 	for _, smgr := range m.scopedManagers {
 		scopedManagers = append(scopedManagers, smgr)
 	}
@@ -551,6 +561,28 @@ func (m *Manager) AddrAccount(ns walletdb.ReadBucket,
 	return nil, 0, managerError(ErrAddressNotFound, str, nil)
 }
 
+// SetAddrLabel sets the label of the given address in the scoped manager that owns it. An empty label removes it.
+func (m *Manager) SetAddrLabel(ns walletdb.ReadWriteBucket,
+	address util.Address, label string) error {
+	scopedMgr, _, err := m.AddrAccount(ns, address)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	return scopedMgr.SetAddrLabel(ns, address, label)
+}
+
+// AddrLabel returns the label of the given address, which is empty if the address has none.
+func (m *Manager) AddrLabel(ns walletdb.ReadBucket,
+	address util.Address) (string, error) {
+	scopedMgr, _, err := m.AddrAccount(ns, address)
+	if err != nil {
+		Error(err)
+		return "", err
+	}
+	return scopedMgr.AddrLabel(ns, address)
+}
+
 // ForEachActiveAccountAddress calls the given function with each active address of the given account stored in the
 // manager, across all active scopes, breaking early on error.
 //
@@ -569,6 +601,35 @@ func (m *Manager) ForEachActiveAccountAddress(ns walletdb.ReadBucket,
 	return nil
 }
 
+// LookupAccount returns the key scope and number of the account with the passed name, which may be in any scope. The
+// scopes are searched in order of purpose and coin type, so a name found in several of them, like that of the default
+// account, is taken from BIP0044 first. An error with code ErrAccountNotFound is returned if no scope has the account.
+func (m *Manager) LookupAccount(ns walletdb.ReadBucket, name string) (KeyScope, uint32, error) {
+	m.mtx.RLock()
+	defer m.mtx.RUnlock()
+	scopes := make([]KeyScope, 0, len(m.scopedManagers))
+	for scope := range m.scopedManagers {
+		scopes = append(scopes, scope)
+	}
+	sort.Slice(scopes, func(i, j int) bool {
+		if scopes[i].Purpose != scopes[j].Purpose {
+			return scopes[i].Purpose < scopes[j].Purpose
+		}
+		return scopes[i].Coin < scopes[j].Coin
+	})
+	for _, scope := range scopes {
+		account, err := m.scopedManagers[scope].LookupAccount(ns, name)
+		if err == nil {
+			return scope, account, nil
+		}
+		if !IsError(err, ErrAccountNotFound) {
+			return KeyScope{}, 0, err
+		}
+	}
+	str := fmt.Sprintf("account name '%s' not found", name)
+	return KeyScope{}, 0, managerError(ErrAccountNotFound, str, nil)
+}
+
 // ForEachActiveAddress calls the given function with each active address stored in the manager, breaking early on
 // error.
 func (m *Manager) ForEachActiveAddress(ns walletdb.ReadBucket, fn func(addr util.Address) error) error {
@@ -896,6 +957,10 @@ func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
 	// Use the crypto private key to decrypt all of the account private extended keys.
 	for _, manager := range m.scopedManagers {
 		for account, acctInfo := range manager.acctInfo {
+			// Accounts imported from extended public keys have no private key to decrypt.
+			if acctInfo.watchOnly() {
+				continue
+			}
 			decrypted, err := m.cryptoKeyPriv.Decrypt(acctInfo.acctKeyEncrypted)
 			if err != nil {
 				Error(err)
@@ -918,6 +983,13 @@ func (m *Manager) Unlock(ns walletdb.ReadBucket, passphrase []byte) error {
 		// We'll also derive any private keys that are pending due to them being created while the address manager was
 		// locked.
 		for _, info := range manager.deriveOnUnlock {
+			// There is nothing to derive for the addresses of accounts imported from extended public keys.
+			acctInfo, ok := manager.acctInfo[info.managedAddr.Account()]
+			if ok && acctInfo.watchOnly() {
+				manager.deriveOnUnlock[0] = nil
+				manager.deriveOnUnlock = manager.deriveOnUnlock[1:]
+				continue
+			}
 			addressKey, err := manager.deriveKeyFromPath(
 				ns, info.managedAddr.Account(), info.branch,
 				info.index, true,
@@ -1414,13 +1486,40 @@ func createManagerKeyScope(ns walletdb.ReadWriteBucket,
 func Create(ns walletdb.ReadWriteBucket, seed, pubPassphrase, privPassphrase []byte,
 	chainParams *netparams.Params, config *ScryptOptions,
 	birthday time.Time) error {
+	return createManager(
+		ns, seed, pubPassphrase, privPassphrase, chainParams, config,
+		birthday, false,
+	)
+}
+
+// CreateWatchingOnly creates a new watching-only address manager in the given namespace. The manager holds no seed and
+// no private keys, so only the public passphrase is required. The default key scopes are created without any accounts;
+// accounts are added to them from extended public keys with ScopedKeyManager.NewAccountWatchingOnly.
+//
+// If a config structure is passed to the function, that configuration will override the defaults.
+//
+// A ManagerError with an error code of ErrAlreadyExists will be returned the address manager already exists in the
+// specified namespace.
+func CreateWatchingOnly(ns walletdb.ReadWriteBucket, pubPassphrase []byte,
+	chainParams *netparams.Params, config *ScryptOptions,
+	birthday time.Time) error {
+	return createManager(
+		ns, nil, pubPassphrase, nil, chainParams, config, birthday, true,
+	)
+}
+
+// createManager creates a new address manager in the given namespace, either from a seed or, when isWatchingOnly is
+// set, as a watching-only manager without any private key material.
+func createManager(ns walletdb.ReadWriteBucket, seed, pubPassphrase, privPassphrase []byte,
+	chainParams *netparams.Params, config *ScryptOptions,
+	birthday time.Time, isWatchingOnly bool) error {
 	// Return an error if the manager has already been created in the given database namespace.
 	exists := managerExists(ns)
 	if exists {
 		return managerError(ErrAlreadyExists, errAlreadyExists, nil)
 	}
 	// Ensure the private passphrase is not empty.
-	if len(privPassphrase) == 0 {
+	if !isWatchingOnly && len(privPassphrase) == 0 {
 		str := "private passphrase may not be empty"
 		return managerError(ErrEmptyPassphrase, str, nil)
 	}
@@ -1431,37 +1530,100 @@ func Create(ns walletdb.ReadWriteBucket, seed, pubPassphrase, privPassphrase []b
 	if config == nil {
 		config = &DefaultScryptOptions
 	}
-	// Generate new master keys. These master keys are used to protect the crypto keys that will be generated next.
+	// Generate the master public key, which protects the crypto public key generated next.
 	masterKeyPub, err := newSecretKey(&pubPassphrase, config)
 	if err != nil {
 		Error(err)
 		str := "failed to master public key"
 		return managerError(ErrCrypto, str, err)
 	}
-	masterKeyPriv, err := newSecretKey(&privPassphrase, config)
+	// Generate the crypto public key, which is used to protect the public data such as addresses and extended keys.
+	cryptoKeyPub, err := newCryptoKey()
 	if err != nil {
 		Error(err)
-		str := "failed to master private key"
+		str := "failed to generate crypto public key"
 		return managerError(ErrCrypto, str, err)
 	}
-	defer masterKeyPriv.Zero()
-	// Generate the private passphrase salt. This is used when hashing passwords to detect whether an unlock can be
-	// avoided when the manager is already unlocked.
-	var privPassphraseSalt [saltSize]byte
-	_, err = rand.Read(privPassphraseSalt[:])
+	cryptoKeyPubEnc, err := masterKeyPub.Encrypt(cryptoKeyPub.Bytes())
 	if err != nil {
 		Error(err)
-		str := "failed to read random source for passphrase salt"
+		str := "failed to encrypt crypto public key"
 		return managerError(ErrCrypto, str, err)
 	}
-	// Generate new crypto public, private, and script keys. These keys are used to protect the actual public and
-	// private data such as addresses, extended keys, and scripts.
-	cryptoKeyPub, err := newCryptoKey()
+	// Use the genesis block for the passed chain as the created at block for the default.
+	createdAt := &BlockStamp{Hash: *chainParams.GenesisHash, Height: 0}
+	// Create the initial sync state.
+	syncInfo := newSyncState(createdAt, createdAt)
+	if isWatchingOnly {
+		// A watching-only manager only stores the public parts. Each default scope still gets the reserved imported
+		// account, while the BIP0044-like accounts are only created when their extended public keys are imported.
+		err = putMasterKeyParams(ns, masterKeyPub.Marshal(), nil)
+		if err != nil {
+			Error(err)
+			return maybeConvertDbError(err)
+		}
+		for _, defaultScope := range DefaultKeyScopes {
+			err = putAccountInfo(
+				ns, &defaultScope, ImportedAddrAccount, nil, nil, 0, 0,
+				ImportedAddrAccountName,
+			)
+			if err != nil {
+				Error(err)
+				return maybeConvertDbError(err)
+			}
+		}
+		err = putCryptoKeys(ns, cryptoKeyPubEnc, nil, nil)
+		if err != nil {
+			Error(err)
+			return maybeConvertDbError(err)
+		}
+	} else {
+		err = createManagerPrivate(
+			ns, seed, privPassphrase, chainParams, config, masterKeyPub,
+			cryptoKeyPub, cryptoKeyPubEnc,
+		)
+		if err != nil {
+			Error(err)
+			return err
+		}
+	}
+	// Save whether this is a watching-only address manager to the database.
+	err = putWatchingOnly(ns, isWatchingOnly)
 	if err != nil {
 		Error(err)
-		str := "failed to generate crypto public key"
+		return maybeConvertDbError(err)
+	}
+	// Save the initial synced to state.
+	err = putSyncedTo(ns, &syncInfo.syncedTo)
+	if err != nil {
+		Error(err)
+		return maybeConvertDbError(err)
+	}
+	err = putStartBlock(ns, &syncInfo.startBlock)
+	if err != nil {
+		Error(err)
+		return maybeConvertDbError(err)
+	}
+	// Use 48 hours as margin of safety for wallet birthday.
+	return putBirthday(ns, birthday.Add(-48*time.Hour))
+}
+
+// createManagerPrivate stores the private parts of a new address manager created from a seed: the master private key
+// parameters, the encrypted crypto keys, the master HD keys and the cointype and default account keys of each default
+// scope.
+func createManagerPrivate(ns walletdb.ReadWriteBucket, seed, privPassphrase []byte,
+	chainParams *netparams.Params, config *ScryptOptions, masterKeyPub *snacl.SecretKey,
+	cryptoKeyPub EncryptorDecryptor, cryptoKeyPubEnc []byte) error {
+	// Generate the master private key. This is used to protect the crypto private and script keys generated next.
+	masterKeyPriv, err := newSecretKey(&privPassphrase, config)
+	if err != nil {
+		Error(err)
+		str := "failed to master private key"
 		return managerError(ErrCrypto, str, err)
 	}
+	defer masterKeyPriv.Zero()
+	// Generate new crypto private and script keys. These keys are used to protect the actual private data such as
+	// extended keys and scripts.
 	cryptoKeyPriv, err := newCryptoKey()
 	if err != nil {
 		Error(err)
@@ -1476,13 +1638,7 @@ func Create(ns walletdb.ReadWriteBucket, seed, pubPassphrase, privPassphrase []b
 		return managerError(ErrCrypto, str, err)
 	}
 	defer cryptoKeyScript.Zero()
-	// Encrypt the crypto keys with the associated master keys.
-	cryptoKeyPubEnc, err := masterKeyPub.Encrypt(cryptoKeyPub.Bytes())
-	if err != nil {
-		Error(err)
-		str := "failed to encrypt crypto public key"
-		return managerError(ErrCrypto, str, err)
-	}
+	// Encrypt the crypto keys with the master private key.
 	cryptoKeyPrivEnc, err := masterKeyPriv.Encrypt(cryptoKeyPriv.Bytes())
 	if err != nil {
 		Error(err)
@@ -1495,10 +1651,6 @@ func Create(ns walletdb.ReadWriteBucket, seed, pubPassphrase, privPassphrase []b
 		str := "failed to encrypt crypto script key"
 		return managerError(ErrCrypto, str, err)
 	}
-	// Use the genesis block for the passed chain as the created at block for the default.
-	createdAt := &BlockStamp{Hash: *chainParams.GenesisHash, Height: 0}
-	// Create the initial sync state.
-	syncInfo := newSyncState(createdAt, createdAt)
 	// Save the master key netparams to the database.
 	pubParams := masterKeyPub.Marshal()
 	privParams := masterKeyPriv.Marshal()
@@ -1556,23 +1708,5 @@ func Create(ns walletdb.ReadWriteBucket, seed, pubPassphrase, privPassphrase []b
 		Error(err)
 		return maybeConvertDbError(err)
 	}
-	// Save the fact this is not a watching-only address manager to the database.
-	err = putWatchingOnly(ns, false)
-	if err != nil {
-		Error(err)
-		return maybeConvertDbError(err)
-	}
-	// Save the initial synced to state.
-	err = putSyncedTo(ns, &syncInfo.syncedTo)
-	if err != nil {
-		Error(err)
-		return maybeConvertDbError(err)
-	}
-	err = putStartBlock(ns, &syncInfo.startBlock)
-	if err != nil {
-		Error(err)
-		return maybeConvertDbError(err)
-	}
-	// Use 48 hours as margin of safety for wallet birthday.
-	return putBirthday(ns, birthday.Add(-48*time.Hour))
+	return nil
 }
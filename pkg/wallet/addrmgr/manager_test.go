@@ -15,9 +15,11 @@ import (
 	chaincfg "github.com/p9c/pod/pkg/chain/config"
 	"github.com/p9c/pod/pkg/chain/config/netparams"
 	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	"github.com/p9c/pod/pkg/coding/base58"
 	"github.com/p9c/pod/pkg/coding/snacl"
 	"github.com/p9c/pod/pkg/db/walletdb"
 	"github.com/p9c/pod/pkg/util"
+	"github.com/p9c/pod/pkg/util/hdkeychain"
 	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
 )
 
@@ -974,6 +976,52 @@ func testMarkUsed(tc *testContext) bool {
 	return true
 }
 
+// testAddrLabel ensures address labels can be set, replaced and removed, and that only addresses of the manager can be
+// labelled.
+func testAddrLabel(tc *testContext) bool {
+	prefix := testNamePrefix(tc) + " testAddrLabel"
+	chainParams := tc.manager.ChainParams()
+	addr, err := util.NewAddressPubKeyHash(
+		hexToBytes("2ef94abb9ee8f785d087c3ec8d6ee467e92d0d0a"), chainParams)
+	if err != nil {
+		tc.t.Errorf("%s: NewAddress unexpected error: %v", prefix, err)
+		return false
+	}
+	unknown, err := util.NewAddressPubKeyHash(make([]byte, 20), chainParams)
+	if err != nil {
+		tc.t.Errorf("%s: NewAddress unexpected error: %v", prefix, err)
+		return false
+	}
+	for _, label := range []string{"rent", "rent for march", ""} {
+		err = walletdb.Update(tc.db, func(tx walletdb.ReadWriteTx) error {
+			ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			if err := tc.manager.SetAddrLabel(ns, addr, label); err != nil {
+				return err
+			}
+			gotLabel, err := tc.manager.AddrLabel(ns, addr)
+			if err != nil {
+				return err
+			}
+			if gotLabel != label {
+				tc.t.Errorf("%s: unexpected label -- got %q, want %q", prefix, gotLabel, label)
+			}
+			return nil
+		})
+		if err != nil {
+			tc.t.Errorf("%s: unexpected error: %v", prefix, err)
+			return false
+		}
+	}
+	err = walletdb.Update(tc.db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		return tc.manager.SetAddrLabel(ns, unknown, "not ours")
+	})
+	if !checkManagerError(tc.t, prefix, err, waddrmgr.ErrAddressNotFound) {
+		return false
+	}
+	return true
+}
+
 // testChangePassphrase ensures changes both the public and private passphrases works as intended.
 func testChangePassphrase(tc *testContext) bool {
 	// Force an error when changing the passphrase due to failure to generate a new secret key by replacing the
@@ -1428,6 +1476,7 @@ func testManagerAPI(tc *testContext) {
 	testImportPrivateKey(tc)
 	testImportScript(tc)
 	testMarkUsed(tc)
+	testAddrLabel(tc)
 	testChangePassphrase(tc)
 	// Reset default account
 	tc.account = 0
@@ -2157,3 +2206,173 @@ func TestNewRawAccount(t *testing.T) {
 			accountTargetAddr.AddrHash())
 	}
 }
+
+// TestWatchingOnlyAccount ensures a watching-only manager can be created without a seed and that an account imported
+// into it from an extended public key derives the same addresses as the account of the manager holding the seed, while
+// no private keys can be had for it, neither in the watching-only manager nor in a manager holding a seed.
+func TestWatchingOnlyAccount(t *testing.T) {
+	t.Parallel()
+	teardown, db := emptyDB(t)
+	defer teardown()
+	// The account key of the first BIP0084 account, m/84'/0'/0', is derived from the seed the seeded manager uses.
+	acctKey, err := hdkeychain.NewMaster(seed, &netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to create master key: %v", err)
+	}
+	for _, index := range []uint32{84, 0, 0} {
+		acctKey, err = acctKey.Child(hdkeychain.HardenedKeyStart + index)
+		if err != nil {
+			t.Fatalf("unable to derive account key: %v", err)
+		}
+	}
+	acctPubKey, err := acctKey.Neuter()
+	if err != nil {
+		t.Fatalf("unable to neuter account key: %v", err)
+	}
+	// The zpub encoding of the key has the BIP0084 version bytes in place of the xpub ones.
+	payload := base58.Decode(acctPubKey.String())
+	payload = payload[:len(payload)-4]
+	copy(payload, []byte{0x04, 0xb2, 0x47, 0x46})
+	zpub := base58.Encode(append(payload, chainhash.DoubleHashB(payload)[:4]...))
+	parsedKey, scope, err := waddrmgr.ParseAccountPubKey(zpub, &netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to parse zpub: %v", err)
+	}
+	if scope != waddrmgr.KeyScopeBIP0084 {
+		t.Fatalf("zpub parsed to scope %v, want %v", scope, waddrmgr.KeyScopeBIP0084)
+	}
+	if parsedKey.String() != acctPubKey.String() {
+		t.Fatalf("zpub parsed to %v, want %v", parsedKey, acctPubKey)
+	}
+	_, scope, err = waddrmgr.ParseAccountPubKey(acctPubKey.String(), &netparams.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to parse xpub: %v", err)
+	}
+	if scope != waddrmgr.KeyScopeBIP0044 {
+		t.Fatalf("xpub parsed to scope %v, want %v", scope, waddrmgr.KeyScopeBIP0044)
+	}
+	_, _, err = waddrmgr.ParseAccountPubKey(acctKey.String(), &netparams.MainNetParams)
+	checkManagerError(t, "parse xprv", err, waddrmgr.ErrKeyChain)
+	// Both managers live in the same database under different namespaces.
+	seededNamespaceKey := []byte("seeded")
+	var seededMgr, watchMgr *waddrmgr.Manager
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns, err := tx.CreateTopLevelBucket(seededNamespaceKey)
+		if err != nil {
+			return err
+		}
+		err = waddrmgr.Create(
+			ns, seed, pubPassphrase, privPassphrase,
+			&netparams.MainNetParams, fastScrypt, time.Time{},
+		)
+		if err != nil {
+			return err
+		}
+		seededMgr, err = waddrmgr.Open(ns, pubPassphrase, &netparams.MainNetParams)
+		if err != nil {
+			return err
+		}
+		if err = seededMgr.Unlock(ns, privPassphrase); err != nil {
+			return err
+		}
+		ns, err = tx.CreateTopLevelBucket(waddrmgrNamespaceKey)
+		if err != nil {
+			return err
+		}
+		err = waddrmgr.CreateWatchingOnly(
+			ns, pubPassphrase, &netparams.MainNetParams, fastScrypt, time.Time{},
+		)
+		if err != nil {
+			return err
+		}
+		watchMgr, err = waddrmgr.Open(ns, pubPassphrase, &netparams.MainNetParams)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("create/open: unexpected error: %v", err)
+	}
+	defer seededMgr.Close()
+	defer watchMgr.Close()
+	if !watchMgr.WatchOnly() {
+		t.Fatalf("manager created watching-only is not watching-only")
+	}
+	seededScope, err := seededMgr.FetchScopedKeyManager(waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		t.Fatalf("unable to fetch scope: %v", err)
+	}
+	watchScope, err := watchMgr.FetchScopedKeyManager(waddrmgr.KeyScopeBIP0084)
+	if err != nil {
+		t.Fatalf("unable to fetch scope: %v", err)
+	}
+	var wantAddr, gotAddr waddrmgr.ManagedAddress
+	var props *waddrmgr.AccountProperties
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		addrs, err := seededScope.NextExternalAddresses(
+			tx.ReadWriteBucket(seededNamespaceKey), waddrmgr.DefaultAccountNum, 1,
+		)
+		if err != nil {
+			return err
+		}
+		wantAddr = addrs[0]
+		ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+		// The account becomes the default account as there is none in the watching-only manager.
+		account, err := watchScope.NewAccountWatchingOnly(ns, "treasury", parsedKey)
+		if err != nil {
+			return err
+		}
+		if account != waddrmgr.DefaultAccountNum {
+			return fmt.Errorf("imported account number %d, want %d", account, waddrmgr.DefaultAccountNum)
+		}
+		addrs, err = watchScope.NextExternalAddresses(ns, account, 1)
+		if err != nil {
+			return err
+		}
+		gotAddr = addrs[0]
+		props, err = watchScope.AccountProperties(ns, account)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unable to import account: %v", err)
+	}
+	if gotAddr.Address().String() != wantAddr.Address().String() {
+		t.Fatalf("watching-only account derived %v, want %v", gotAddr.Address(), wantAddr.Address())
+	}
+	if !props.IsWatchOnly || props.AccountName != "treasury" || props.ExternalKeyCount != 1 {
+		t.Fatalf("unexpected account properties %+v", props)
+	}
+	_, err = gotAddr.(waddrmgr.ManagedPubKeyAddress).PrivKey()
+	checkManagerError(t, "watching-only private key", err, waddrmgr.ErrWatchingOnly)
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		return watchMgr.Unlock(tx.ReadBucket(waddrmgrNamespaceKey), privPassphrase)
+	})
+	checkManagerError(t, "unlock watching-only", err, waddrmgr.ErrWatchingOnly)
+	// The same key imported into the manager holding the seed is the next account, and stays without private keys
+	// across locking and unlocking.
+	err = walletdb.Update(db, func(tx walletdb.ReadWriteTx) error {
+		ns := tx.ReadWriteBucket(seededNamespaceKey)
+		account, err := seededScope.NewAccountWatchingOnly(ns, "treasury", parsedKey)
+		if err != nil {
+			return err
+		}
+		if account != 1 {
+			return fmt.Errorf("imported account number %d, want 1", account)
+		}
+		if err = seededMgr.Lock(); err != nil {
+			return err
+		}
+		addrs, err := seededScope.NextExternalAddresses(ns, account, 1)
+		if err != nil {
+			return err
+		}
+		gotAddr = addrs[0]
+		return seededMgr.Unlock(ns, privPassphrase)
+	})
+	if err != nil {
+		t.Fatalf("unable to import account into seeded manager: %v", err)
+	}
+	if gotAddr.Address().String() != wantAddr.Address().String() {
+		t.Fatalf("imported account derived %v, want %v", gotAddr.Address(), wantAddr.Address())
+	}
+	_, err = gotAddr.(waddrmgr.ManagedPubKeyAddress).PrivKey()
+	checkManagerError(t, "imported account private key", err, waddrmgr.ErrWatchingOnly)
+}
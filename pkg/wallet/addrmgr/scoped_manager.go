@@ -218,7 +218,7 @@ func (s *ScopedKeyManager) deriveKey(acctInfo *accountInfo, branch,
 	// Choose the public or private extended key based on whether or not the private flag was specified. This, in turn,
 	// allows for public or private child derivation.
 	acctKey := acctInfo.acctKeyPub
-	if private {
+	if private && !acctInfo.watchOnly() {
 		acctKey = acctInfo.acctKeyPriv
 	}
 	// Derive and return the key.
@@ -285,7 +285,7 @@ func (s *ScopedKeyManager) loadAccountInfo(ns walletdb.ReadBucket,
 		nextExternalIndex: row.nextExternalIndex,
 		nextInternalIndex: row.nextInternalIndex,
 	}
-	if !s.rootManager.isLocked() {
+	if !s.rootManager.isLocked() && !acctInfo.watchOnly() {
 		// Use the crypto private key to decrypt the account private extended keys.
 		decrypted, err := s.rootManager.cryptoKeyPriv.Decrypt(acctInfo.acctKeyEncrypted)
 		if err != nil {
@@ -368,6 +368,7 @@ func (s *ScopedKeyManager) AccountProperties(ns walletdb.ReadBucket,
 		props.AccountName = acctInfo.acctName
 		props.ExternalKeyCount = acctInfo.nextExternalIndex
 		props.InternalKeyCount = acctInfo.nextInternalIndex
+		props.IsWatchOnly = s.rootManager.WatchOnly() || acctInfo.watchOnly()
 	} else {
 		props.AccountName = ImportedAddrAccountName // reserved, nonchangable
 		// Could be more efficient if this was tracked by the db.
@@ -382,6 +383,7 @@ func (s *ScopedKeyManager) AccountProperties(ns walletdb.ReadBucket,
 			return nil, err
 		}
 		props.ImportedKeyCount = importedKeyCount
+		props.IsWatchOnly = s.rootManager.WatchOnly()
 	}
 	return props, nil
 }
@@ -587,9 +589,9 @@ func (s *ScopedKeyManager) nextAddresses(ns walletdb.ReadWriteBucket,
 		Error(err)
 		return nil, err
 	}
-	// Choose the account key to used based on whether the address manager is locked.
+	// Choose the account key to used based on whether the address manager is locked and the account has a private key.
 	acctKey := acctInfo.acctKeyPub
-	if !s.rootManager.IsLocked() {
+	if !s.rootManager.IsLocked() && !acctInfo.watchOnly() {
 		acctKey = acctInfo.acctKeyPriv
 	}
 	// Choose the branch key and index depending on whether or not this is an internal address.
@@ -712,7 +714,7 @@ func (s *ScopedKeyManager) nextAddresses(ns walletdb.ReadWriteBucket,
 		s.addrs[addrKey(ma.Address().ScriptAddress())] = ma
 		// Add the new managed address to the list of addresses that need their private keys derived when the address
 		// manager is next unlocked.
-		if s.rootManager.IsLocked() && !s.rootManager.WatchOnly() {
+		if s.rootManager.IsLocked() && !s.rootManager.WatchOnly() && !acctInfo.watchOnly() {
 			s.deriveOnUnlock = append(s.deriveOnUnlock, info)
 		}
 		managedAddresses = append(managedAddresses, ma)
@@ -742,9 +744,9 @@ func (s *ScopedKeyManager) extendAddresses(ns walletdb.ReadWriteBucket,
 		Error(err)
 		return err
 	}
-	// Choose the account key to used based on whether the address manager is locked.
+	// Choose the account key to used based on whether the address manager is locked and the account has a private key.
 	acctKey := acctInfo.acctKeyPub
-	if !s.rootManager.IsLocked() {
+	if !s.rootManager.IsLocked() && !acctInfo.watchOnly() {
 		acctKey = acctInfo.acctKeyPriv
 	}
 	// Choose the branch key and index depending on whether or not this is an internal address.
@@ -870,7 +872,7 @@ func (s *ScopedKeyManager) extendAddresses(ns walletdb.ReadWriteBucket,
 		s.addrs[addrKey(ma.Address().ScriptAddress())] = ma
 		// Add the new managed address to the list of addresses that need their private keys derived when the address
 		// manager is next unlocked.
-		if s.rootManager.IsLocked() && !s.rootManager.WatchOnly() {
+		if s.rootManager.IsLocked() && !s.rootManager.WatchOnly() && !acctInfo.watchOnly() {
 			s.deriveOnUnlock = append(s.deriveOnUnlock, info)
 		}
 	}
@@ -1041,6 +1043,76 @@ func (s *ScopedKeyManager) NewAccount(ns walletdb.ReadWriteBucket, name string)
 	return account, nil
 }
 
+// NewAccountWatchingOnly creates and returns a new account stored in the manager from the extended public key of a
+// BIP0044-like account, which is the key at m/purpose'/cointype'/account' of this scope. The account can derive
+// addresses and watch them like any other, but there are no private keys for it, so neither the manager needs to be
+// unlocked nor a seed needs to be held, and it works the same in a watching-only manager. The first account created
+// from an extended public key in a scope without a default account becomes the default account, so a watching-only
+// manager can be used just like one created from a seed. If an account with the same name already exists,
+// ErrDuplicateAccount will be returned.
+func (s *ScopedKeyManager) NewAccountWatchingOnly(ns walletdb.ReadWriteBucket, name string,
+	pubKey *hdkeychain.ExtendedKey) (uint32, error) {
+	if pubKey.IsPrivate() {
+		str := "the extended key of a watching-only account must be public"
+		return 0, managerError(ErrKeyChain, str, nil)
+	}
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	// Validate the account name.
+	if err := ValidateAccountName(name); err != nil {
+		return 0, err
+	}
+	// Check that account with the same name does not exist
+	if _, err := s.lookupAccount(ns, name); err == nil {
+		str := fmt.Sprintf("account with the same name already exists")
+		return 0, managerError(ErrDuplicateAccount, str, err)
+	}
+	// Ensure the branch keys can be derived from the account key.
+	if err := checkBranchKeys(pubKey); err != nil {
+		str := "the extended public key can not derive both branches"
+		return 0, managerError(ErrKeyChain, str, err)
+	}
+	// The account takes the default account number if it is free, and otherwise the one after the last account.
+	account := uint32(DefaultAccountNum)
+	if _, err := fetchAccountName(ns, &s.scope, DefaultAccountNum); err == nil {
+		lastAccount, err := fetchLastAccount(ns, &s.scope)
+		if err != nil {
+			Error(err)
+			return 0, err
+		}
+		account = lastAccount + 1
+	}
+	if account > MaxAccountNum {
+		str := fmt.Sprintf("account number %d is above the maximum %d", account, MaxAccountNum)
+		return 0, managerError(ErrAccountNumTooHigh, str, nil)
+	}
+	// Only the public key is stored, the missing private key is what marks the account as watching-only.
+	acctKeyPub, err := hdkeychain.NewKeyFromString(pubKey.String())
+	if err != nil {
+		Error(err)
+		str := "failed to copy the extended public key for the account"
+		return 0, managerError(ErrKeyChain, str, err)
+	}
+	acctKeyPub.SetNet(s.rootManager.chainParams)
+	acctPubEnc, err := s.rootManager.cryptoKeyPub.Encrypt(
+		[]byte(acctKeyPub.String()),
+	)
+	if err != nil {
+		Error(err)
+		str := "failed to encrypt public key for account"
+		return 0, managerError(ErrCrypto, str, err)
+	}
+	err = putAccountInfo(ns, &s.scope, account, acctPubEnc, nil, 0, 0, name)
+	if err != nil {
+		Error(err)
+		return 0, err
+	}
+	if err := putLastAccount(ns, &s.scope, account); err != nil {
+		return 0, err
+	}
+	return account, nil
+}
+
 // newAccount is a helper function that derives a new precise account number, and creates a mapping from the passed name
 // to the account number in the database.
 //
@@ -1421,6 +1493,34 @@ func (s *ScopedKeyManager) MarkUsed(ns walletdb.ReadWriteBucket,
 	return nil
 }
 
+// SetAddrLabel sets the label of an address of this scope. An empty label removes it. An error with code
+// ErrAddressNotFound is returned if the address does not belong to the scope.
+func (s *ScopedKeyManager) SetAddrLabel(ns walletdb.ReadWriteBucket,
+	address util.Address, label string) error {
+	addressID := address.ScriptAddress()
+	if _, err := fetchAddress(ns, &s.scope, addressID); err != nil {
+		Error(err)
+		return maybeConvertDbError(err)
+	}
+	err := putAddrLabel(ns, &s.scope, addressID, label)
+	if err != nil {
+		Error(err)
+		return maybeConvertDbError(err)
+	}
+	return nil
+}
+
+// AddrLabel returns the label of an address of this scope, which is empty if the address has none.
+func (s *ScopedKeyManager) AddrLabel(ns walletdb.ReadBucket,
+	address util.Address) (string, error) {
+	label, err := fetchAddrLabel(ns, &s.scope, address.ScriptAddress())
+	if err != nil {
+		Error(err)
+		return "", maybeConvertDbError(err)
+	}
+	return label, nil
+}
+
 // ChainParams returns the chain parameters for this address manager.
 func (s *ScopedKeyManager) ChainParams() *netparams.Params {
 	// NOTE: No need for mutex here since the net field does not change after the manager instance is created.
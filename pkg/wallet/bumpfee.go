@@ -0,0 +1,256 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	blockchain "github.com/p9c/pod/pkg/chain"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txauthor "github.com/p9c/pod/pkg/chain/tx/author"
+	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/rpc/btcjson"
+	"github.com/p9c/pod/pkg/util"
+	h "github.com/p9c/pod/pkg/util/helpers"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+	"github.com/p9c/pod/pkg/wallet/chain"
+)
+
+// ErrNotReplaceable is returned by BumpFee when the transaction does not signal that it may be replaced.
+var ErrNotReplaceable = errors.New("transaction does not signal replaceability (BIP125)")
+
+// BumpFee replaces an unconfirmed wallet transaction that signals replaceability with one paying the same recipients
+// at a higher fee rate. The replacement spends every input of the original, adding further confirmed outputs from the
+// same account if the original inputs can not cover the higher fee, and returns any change to the original change
+// script. If feeSatPerKb is zero the lowest fee rate the network will accept as a replacement is used. The replacement
+// is published and takes the place of the original in the wallet. The hash of the replacement and the fees paid by the
+// original and the replacement are returned. The wallet must be unlocked.
+func (w *Wallet) BumpFee(txHash *chainhash.Hash, feeSatPerKb util.Amount) (newHash *chainhash.Hash,
+	origFee, newFee util.Amount, err error) {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		Error(err)
+		return
+	}
+	var origRec *wtxmgr.TxRecord
+	var tx *txauthor.AuthoredTx
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		details, err := w.TxStore.TxDetails(txmgrNs, txHash)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		if details == nil {
+			return fmt.Errorf("transaction %v is not in the wallet", txHash)
+		}
+		if details.Block.Height != -1 {
+			return fmt.Errorf("transaction %v is already confirmed", txHash)
+		}
+		if len(details.Debits) != len(details.MsgTx.TxIn) {
+			return fmt.Errorf("transaction %v spends outputs not controlled by the wallet", txHash)
+		}
+		if !signalsReplacement(&details.MsgTx) {
+			return ErrNotReplaceable
+		}
+		origRec = &details.TxRecord
+		// Look up the outputs spent by the original so they can be signed again.
+		inputs := make([]*wire.TxIn, len(details.MsgTx.TxIn))
+		inputValues := make([]util.Amount, len(inputs))
+		prevScripts := make([][]byte, len(inputs))
+		var totalInput util.Amount
+		for i, txIn := range details.MsgTx.TxIn {
+			prevOut := txIn.PreviousOutPoint
+			prevDetails, err := w.TxStore.TxDetails(txmgrNs, &prevOut.Hash)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			if prevDetails == nil {
+				return fmt.Errorf("%v not found", prevOut)
+			}
+			prevTxOut := prevDetails.MsgTx.TxOut[prevOut.Index]
+			inputs[i] = wire.NewTxIn(&prevOut, nil, nil)
+			// The replacement signals replaceability as the original did, so its fee can be bumped again.
+			inputs[i].Sequence = txrules.MaxRBFSequence
+			inputValues[i] = util.Amount(prevTxOut.Value)
+			prevScripts[i] = prevTxOut.PkScript
+			totalInput += inputValues[i]
+		}
+		origFee = totalInput - h.SumOutputValues(details.MsgTx.TxOut)
+		// The replacement must pay a higher fee rate than the original by at least the incremental relay fee of the
+		// chain server, so it pays for its own relay on top of the fee it replaces.
+		weight := blockchain.GetTransactionWeight(util.NewTx(&details.MsgTx))
+		origSize := (weight + blockchain.WitnessScaleFactor - 1) / blockchain.WitnessScaleFactor
+		minFeeRate := origFee*1000/util.Amount(origSize) + incrementalRelayFee(chainClient)
+		switch {
+		case feeSatPerKb == 0:
+			feeSatPerKb = minFeeRate
+		case feeSatPerKb < minFeeRate:
+			return fmt.Errorf("fee rate %v is below the minimum of %v to replace transaction %v",
+				feeSatPerKb, minFeeRate, txHash)
+		}
+		// Keep every output except the change, which is recreated for the new fee.
+		change := make(map[uint32]struct{})
+		for _, credit := range details.Credits {
+			if credit.Change {
+				change[credit.Index] = struct{}{}
+			}
+		}
+		var outputs []*wire.TxOut
+		var changeScript []byte
+		for i, txOut := range details.MsgTx.TxOut {
+			if _, ok := change[uint32(i)]; ok {
+				changeScript = txOut.PkScript
+				continue
+			}
+			outputs = append(outputs, wire.NewTxOut(txOut.Value, txOut.PkScript))
+		}
+		// Additional inputs and new change come from the account that funded the original.
+		account := uint32(waddrmgr.DefaultAccountNum)
+		_, addrs, _, err := txscript.ExtractPkScriptAddrs(prevScripts[0], w.chainParams)
+		if err == nil && len(addrs) == 1 {
+			if _, addrAcct, err := w.Manager.AddrAccount(addrmgrNs, addrs[0]); err == nil {
+				account = addrAcct
+			}
+		}
+		bs, err := chainClient.BlockStamp()
+		if err != nil {
+			Error(err)
+			return err
+		}
+		// Only confirmed outputs may be added, as a replacement can't spend unconfirmed outputs the original did not.
+		eligible, err := w.findEligibleOutputs(dbtx, nil, account, 1, bs)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		inputSource := makePresetInputSource(inputs, inputValues, prevScripts,
+			makeInputSource(eligible, txrules.MaxRBFSequence))
+		changeSource := func() ([]byte, error) {
+			if changeScript != nil {
+				return changeScript, nil
+			}
+			// As when sending, change for a spend from the imported account goes to the default account.
+			changeAccount := account
+			if changeAccount == waddrmgr.ImportedAddrAccount {
+				changeAccount = waddrmgr.DefaultAccountNum
+			}
+			changeAddr, err := w.newChangeAddress(addrmgrNs, changeAccount)
+			if err != nil {
+				Error(err)
+				return nil, err
+			}
+			return txscript.PayToAddrScript(changeAddr)
+		}
+		tx, err = txauthor.NewUnsignedTransaction(outputs, feeSatPerKb, inputSource, changeSource)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		if tx.ChangeIndex >= 0 {
+			tx.RandomizeChangePosition()
+		}
+		return tx.AddAllInputScripts(secretSource{w.Manager, addrmgrNs})
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	if err = validateMsgTx(tx.Tx, tx.PrevScripts, tx.PrevInputValues); err != nil {
+		Error(err)
+		return
+	}
+	newFee = tx.TotalInput - h.SumOutputValues(tx.Tx.TxOut)
+	// The original is only dropped from the wallet once the node has accepted the replacement, so a rejected
+	// replacement leaves the wallet unchanged.
+	if newHash, err = chainClient.SendRawTransaction(tx.Tx, false); err != nil {
+		Error(err)
+		return
+	}
+	newRec, err := wtxmgr.NewTxRecordFromMsgTx(tx.Tx, time.Now())
+	if err != nil {
+		Error(err)
+		return
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		txmgrNs := dbtx.ReadWriteBucket(wtxmgrNamespaceKey)
+		if err := w.TxStore.RemoveUnminedTx(txmgrNs, origRec); err != nil {
+			return err
+		}
+		return w.addRelevantTx(dbtx, newRec, nil)
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	Infof("replaced transaction %v with %v, fee %v -> %v", txHash, newHash, origFee, newFee)
+	return
+}
+
+// makePresetInputSource returns an input source that always spends all of the passed inputs, such as those of a
+// transaction being replaced so the replacement conflicts with it, and only draws on the extra input source when those
+// inputs are not enough to reach the target.
+func makePresetInputSource(inputs []*wire.TxIn, inputValues []util.Amount, prevScripts [][]byte,
+	extraSource txauthor.InputSource) txauthor.InputSource {
+	var origTotal util.Amount
+	for _, value := range inputValues {
+		origTotal += value
+	}
+	n := len(inputs)
+	return func(target util.Amount) (util.Amount, []*wire.TxIn, []util.Amount, [][]byte, error) {
+		if target <= origTotal {
+			return origTotal, inputs[:n:n], inputValues[:n:n], prevScripts[:n:n], nil
+		}
+		extraTotal, extraInputs, extraValues, extraScripts, err := extraSource(target - origTotal)
+		if err != nil {
+			Error(err)
+			return 0, nil, nil, nil, err
+		}
+		return origTotal + extraTotal,
+			append(inputs[:n:n], extraInputs...),
+			append(inputValues[:n:n], extraValues...),
+			append(prevScripts[:n:n], extraScripts...),
+			nil
+	}
+}
+
+// incrementalRelayFee returns the fee rate by which the chain server requires a replacement to raise the fee rate of the
+// transactions it replaces. Back ends that do not report it are assumed to use the default relay fee.
+func incrementalRelayFee(chainClient chain.Interface) util.Amount {
+	var info *btcjson.GetNetworkInfoResult
+	var err error
+	switch client := chainClient.(type) {
+	case *chain.RPCClient:
+		info, err = client.GetNetworkInfo()
+	case *chain.BitcoindClient:
+		info, err = client.GetNetworkInfo()
+	default:
+		return txrules.DefaultRelayFeePerKb
+	}
+	if err != nil {
+		Warn("unable to get the incremental relay fee of the chain server, using the default relay fee:", err)
+		return txrules.DefaultRelayFeePerKb
+	}
+	fee, err := util.NewAmount(info.IncrementalFee)
+	if err != nil || fee <= 0 {
+		return txrules.DefaultRelayFeePerKb
+	}
+	return fee
+}
+
+// signalsReplacement returns whether the transaction explicitly signals that it may be replaced, which is the case if
+// any of its inputs has a sequence number no greater than txrules.MaxRBFSequence.
+func signalsReplacement(tx *wire.MsgTx) bool {
+	for _, txIn := range tx.TxIn {
+		if txIn.Sequence <= txrules.MaxRBFSequence {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/pod"
+	"github.com/p9c/pod/pkg/util"
+)
+
+// TestBumpFee ensures a replaceable transaction is replaced by one spending the same inputs at a fee rate higher by at
+// least the incremental relay fee, which takes its place in the wallet, and that transactions sent with
+// replaceability turned off can not be replaced.
+func TestBumpFee(t *testing.T) {
+	w, chainClient, teardown := testWallet(t)
+	defer teardown()
+	fundTestWallet(t, w, 1e8, 100)
+	addr, err := util.NewAddressPubKeyHash(make([]byte, 20), &netparams.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(5e7, pkScript)}
+	origHash, err := w.SendOutputs(outputs, 0, 1, txrules.DefaultRelayFeePerKb, nil)
+	if err != nil {
+		t.Fatalf("SendOutputs: unexpected err %v", err)
+	}
+	orig := chainClient.sent[0]
+	for _, txIn := range orig.TxIn {
+		if txIn.Sequence != txrules.MaxRBFSequence {
+			t.Fatalf("input of the sent transaction has sequence %x, want %x", txIn.Sequence,
+				txrules.MaxRBFSequence)
+		}
+	}
+	newHash, origFee, newFee, err := w.BumpFee(origHash, 0)
+	if err != nil {
+		t.Fatalf("BumpFee: unexpected err %v", err)
+	}
+	if len(chainClient.sent) != 2 || chainClient.sent[1].TxHash() != *newHash {
+		t.Fatalf("replacement was not sent")
+	}
+	replacement := chainClient.sent[1]
+	if len(replacement.TxIn) != len(orig.TxIn) || replacement.TxIn[0].PreviousOutPoint != orig.TxIn[0].PreviousOutPoint {
+		t.Errorf("replacement does not spend the inputs of the original")
+	}
+	origSize := util.Amount(orig.SerializeSize())
+	newSize := util.Amount(replacement.SerializeSize())
+	minFee := (origFee*1000/origSize + txrules.DefaultRelayFeePerKb) * newSize / 1000
+	if newFee < minFee {
+		t.Errorf("replacement pays fee %v, want at least %v (original fee %v)", newFee, minFee, origFee)
+	}
+	var paid bool
+	for _, txOut := range replacement.TxOut {
+		if txOut.Value == outputs[0].Value && string(txOut.PkScript) == string(pkScript) {
+			paid = true
+		}
+	}
+	if !paid {
+		t.Errorf("replacement does not pay the recipient of the original")
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if details, err := w.TxStore.TxDetails(txmgrNs, origHash); err != nil || details != nil {
+			t.Errorf("original is still in the wallet (err %v)", err)
+		}
+		if details, err := w.TxStore.TxDetails(txmgrNs, newHash); err != nil || details == nil {
+			t.Errorf("replacement is not in the wallet (err %v)", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := w.BumpFee(origHash, 0); err == nil {
+		t.Errorf("BumpFee: expected an error replacing a transaction that is no longer in the wallet")
+	}
+	if _, _, _, err := w.BumpFee(newHash, newFee*1000/newSize); err == nil {
+		t.Errorf("BumpFee: expected an error for a fee rate that does not pay the incremental relay fee")
+	}
+	// With replaceability turned off the wallet sends final transactions, which can not be bumped.
+	rbf := false
+	w.PodConfig = &pod.Config{WalletRBF: &rbf}
+	outputs = []*wire.TxOut{wire.NewTxOut(1e7, pkScript)}
+	finalHash, err := w.SendOutputs(outputs, 0, 0, txrules.DefaultRelayFeePerKb, nil)
+	if err != nil {
+		t.Fatalf("SendOutputs: unexpected err %v", err)
+	}
+	for _, txIn := range chainClient.sent[2].TxIn {
+		if txIn.Sequence != wire.MaxTxInSequenceNum {
+			t.Fatalf("input of the sent transaction has sequence %x, want %x", txIn.Sequence,
+				uint32(wire.MaxTxInSequenceNum))
+		}
+	}
+	if _, _, _, err := w.BumpFee(finalHash, 0); err != ErrNotReplaceable {
+		t.Errorf("BumpFee: got err %v, want %v", err, ErrNotReplaceable)
+	}
+}
@@ -155,6 +155,11 @@ func (c *BitcoindClient) GetTxOut(txHash *chainhash.Hash, index uint32,
 	return c.chainConn.client.GetTxOut(txHash, index, mempool)
 }
 
+// GetNetworkInfo returns information about the network state of the bitcoind node, including its relay fees.
+func (c *BitcoindClient) GetNetworkInfo() (*btcjson.GetNetworkInfoResult, error) {
+	return c.chainConn.client.GetNetworkInfo()
+}
+
 // SendRawTransaction sends a raw transaction via bitcoind.
 func (c *BitcoindClient) SendRawTransaction(tx *wire.MsgTx,
 	allowHighFees bool) (*chainhash.Hash, error) {
@@ -253,6 +253,18 @@ func (w *Wallet) addRelevantTx(dbtx walletdb.ReadWriteTx, rec *tm.TxRecord, bloc
 		}
 		for _, addr := range addrs {
 			ma, err := w.Manager.Address(addrmgrNs, addr)
+			if wm.IsError(err, wm.ErrAddressNotFound) {
+				// An address in the lookahead window of a watching-only account is issued with those before it, so the
+				// output is credited to the account.
+				issued, issueErr := w.issueLookaheadAddr(addrmgrNs, addr)
+				if issueErr != nil {
+					Error(issueErr)
+					return issueErr
+				}
+				if issued {
+					ma, err = w.Manager.Address(addrmgrNs, addr)
+				}
+			}
 			if err == nil {
 				// TODO: Credits should be added with the account they belong to, so tm is able to track per-account
 				//  balances.
@@ -0,0 +1,596 @@
+package wallet
+
+import (
+	cryptorand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	txauthor "github.com/p9c/pod/pkg/chain/tx/author"
+	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	txsizes "github.com/p9c/pod/pkg/chain/tx/sizes"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+	h "github.com/p9c/pod/pkg/util/helpers"
+	"github.com/p9c/pod/pkg/wallet/coinset"
+)
+
+// The coin selection strategies, by the names they are given in the wallet configuration and the coinselection
+// parameter of the send RPCs.
+const (
+	// CoinSelectBnB looks for a set of outputs that pays for a transaction without change, and falls back to knapsack
+	// selection when there is none.
+	CoinSelectBnB = "bnb"
+	// CoinSelectKnapsack picks the set of outputs that comes closest to the amount to pay plus a change output that is
+	// not dust.
+	CoinSelectKnapsack = "knapsack"
+	// CoinSelectPrivacy spends all outputs paid to an address together, choosing between addresses like CoinSelectBnB.
+	CoinSelectPrivacy = "privacy"
+	// CoinSelectLargest spends the largest outputs first, which is how transactions were funded before coin selection
+	// could be chosen.
+	CoinSelectLargest = "largest"
+	// CoinSelectValueAge spends the outputs with the highest value times confirmations first.
+	CoinSelectValueAge = "valueage"
+)
+
+// DefaultCoinSelection is the coin selection strategy used when the wallet configuration does not name one.
+const DefaultCoinSelection = CoinSelectBnB
+
+// bnbMaxTries bounds the number of branches the branch and bound search visits before it gives up, which keeps the
+// search fast for wallets with many outputs.
+const bnbMaxTries = 100000
+
+// knapsackIterations is the number of random rounds of the knapsack approximation.
+const knapsackIterations = 1000
+
+// knapsackRand makes the random choices of the knapsack approximation. It is seeded from the system's secure source, so
+// that the outputs chosen differ from run to run and can not be predicted, and is guarded by knapsackRandMtx as a
+// rand.Rand is not safe for concurrent use.
+var (
+	knapsackRand    = rand.New(rand.NewSource(knapsackSeed()))
+	knapsackRandMtx sync.Mutex
+)
+
+// knapsackSeed returns a random seed for knapsackRand, or the current time if the secure source fails.
+func knapsackSeed() int64 {
+	var b [8]byte
+	if _, err := cryptorand.Read(b[:]); err != nil {
+		Error(err)
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+// CoinSelector chooses the unspent outputs that are spent by a new transaction.
+type CoinSelector interface {
+	// SelectCoins returns the credits out of eligible that, with the preset credits the transaction spends in any
+	// case, pay for the outputs and the fee at feeSatPerKb of a transaction spending them, which is estimated as by
+	// txauthor with a change output. The preset credits are not returned. height is the height of the best block, for
+	// the selectors that consider the number of confirmations of a credit. If the credits cannot pay for the
+	// transaction an error implementing txauthor.InputSourceError is returned.
+	SelectCoins(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset, eligible []wtxmgr.Credit,
+		height int32) ([]wtxmgr.Credit, error)
+}
+
+// CoinSelectorByName returns the coin selector of one of the named coin selection strategies. An empty name selects
+// DefaultCoinSelection.
+func CoinSelectorByName(name string) (CoinSelector, error) {
+	switch name {
+	case "", CoinSelectBnB:
+		return FallbackCoinSelector{BranchAndBoundCoinSelector{}, KnapsackCoinSelector{}}, nil
+	case CoinSelectKnapsack:
+		return KnapsackCoinSelector{}, nil
+	case CoinSelectPrivacy:
+		return PrivacyCoinSelector{}, nil
+	case CoinSelectLargest:
+		return CoinsetCoinSelector{func(maxInputs int) coinset.CoinSelector {
+			return coinset.MinNumberCoinSelector{MaxInputs: maxInputs}
+		}}, nil
+	case CoinSelectValueAge:
+		return CoinsetCoinSelector{func(maxInputs int) coinset.CoinSelector {
+			return coinset.MaxValueAgeCoinSelector{MaxInputs: maxInputs}
+		}}, nil
+	}
+	return nil, fmt.Errorf("unknown coin selection strategy %q", name)
+}
+
+// insufficientFundsError is returned by the coin selectors when the eligible credits cannot pay for a transaction.
+type insufficientFundsError struct{}
+
+func (insufficientFundsError) InputSourceError() {
+}
+func (insufficientFundsError) Error() string {
+	return "insufficient funds available to construct transaction"
+}
+
+// noChangelessSelectionError is returned by BranchAndBoundCoinSelector when no set of credits pays for a transaction
+// without change.
+type noChangelessSelectionError struct{}
+
+func (noChangelessSelectionError) InputSourceError() {
+}
+func (noChangelessSelectionError) Error() string {
+	return "no selection of outputs pays for the transaction without change"
+}
+
+// FallbackCoinSelector tries each of its coin selectors in turn, and returns the selection of the first that succeeds,
+// or the error of the last.
+type FallbackCoinSelector []CoinSelector
+
+// SelectCoins implements CoinSelector.
+func (s FallbackCoinSelector) SelectCoins(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset,
+	eligible []wtxmgr.Credit, height int32) (selected []wtxmgr.Credit, err error) {
+	err = insufficientFundsError{}
+	for _, selector := range s {
+		if selected, err = selector.SelectCoins(outputs, feeSatPerKb, preset, eligible, height); err == nil {
+			return
+		}
+		Debug(err)
+	}
+	return nil, err
+}
+
+// BranchAndBoundCoinSelector searches for the set of credits that pays for a transaction without change and gives the
+// least to the miners beyond the fee. The value left over after the fee must be too small for a change output, which
+// is what txauthor then adds to the fee. Credits that cost more to spend than they are worth are never selected.
+type BranchAndBoundCoinSelector struct{}
+
+// SelectCoins implements CoinSelector.
+func (BranchAndBoundCoinSelector) SelectCoins(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset,
+	eligible []wtxmgr.Credit, height int32) ([]wtxmgr.Credit, error) {
+	t := newSelectionTarget(outputs, feeSatPerKb, preset)
+	selected := t.branchAndBound(singleCoinGroups(eligible, feeSatPerKb))
+	if selected == nil {
+		return nil, noChangelessSelectionError{}
+	}
+	return selected, nil
+}
+
+// KnapsackCoinSelector picks the set of credits that comes closest to paying for a transaction exactly, or else with a
+// change output that is not dust, by random approximation. When no such set exists it spends the smallest credit that
+// pays for the transaction with change on its own.
+type KnapsackCoinSelector struct{}
+
+// SelectCoins implements CoinSelector.
+func (KnapsackCoinSelector) SelectCoins(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset,
+	eligible []wtxmgr.Credit, height int32) ([]wtxmgr.Credit, error) {
+	t := newSelectionTarget(outputs, feeSatPerKb, preset)
+	return t.knapsack(singleCoinGroups(eligible, feeSatPerKb))
+}
+
+// PrivacyCoinSelector spends all credits paid to the same address together. Spending some outputs of an address links
+// the address to the other inputs of the transaction, and any output left behind would later link it again to the
+// inputs it is spent with, so no address is left holding coins once it has been spent from. The groups of credits are
+// selected as by CoinSelectBnB, preferring a changeless transaction.
+type PrivacyCoinSelector struct{}
+
+// SelectCoins implements CoinSelector.
+func (PrivacyCoinSelector) SelectCoins(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset,
+	eligible []wtxmgr.Credit, height int32) ([]wtxmgr.Credit, error) {
+	t := newSelectionTarget(outputs, feeSatPerKb, preset)
+	groups := addressCoinGroups(eligible, feeSatPerKb)
+	if selected := t.branchAndBound(groups); selected != nil {
+		return selected, nil
+	}
+	return t.knapsack(groups)
+}
+
+// CoinsetCoinSelector adapts the selectors of the coinset package, which know nothing of fees, to select credits for a
+// transaction. The coinset selector is asked for the value of the outputs and the fee of the inputs it selected before,
+// until its selection pays for its own fee. New creates the coinset selector for the number of eligible credits.
+type CoinsetCoinSelector struct {
+	New func(maxInputs int) coinset.CoinSelector
+}
+
+// SelectCoins implements CoinSelector.
+func (s CoinsetCoinSelector) SelectCoins(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset,
+	eligible []wtxmgr.Credit, height int32) ([]wtxmgr.Credit, error) {
+	coins := make([]coinset.Coin, len(eligible))
+	byOutPoint := make(map[wire.OutPoint]*wtxmgr.Credit, len(eligible))
+	for i := range eligible {
+		coins[i] = &creditCoin{&eligible[i], height}
+		byOutPoint[eligible[i].OutPoint] = &eligible[i]
+	}
+	selector := s.New(len(eligible))
+	outputValue := h.SumOutputValues(outputs)
+	var presetValue util.Amount
+	for i := range preset {
+		presetValue += preset[i].Amount
+	}
+	n := len(preset)
+	selected := preset[:n:n]
+	// The fee only grows as inputs are added, so each round asks for more until the selection pays for itself, which
+	// takes at most a round for every credit.
+	for round := 0; round <= len(eligible); round++ {
+		target := outputValue + selectionFee(outputs, feeSatPerKb, selected) - presetValue
+		set, err := selector.CoinSelect(target, coins)
+		if err != nil {
+			Debug(err)
+			return nil, insufficientFundsError{}
+		}
+		selected = preset[:n:n]
+		for _, coin := range set.Coins() {
+			op := wire.OutPoint{Hash: *coin.Hash(), Index: coin.Index()}
+			selected = append(selected, *byOutPoint[op])
+		}
+		if selectionExcess(outputs, feeSatPerKb, selected) >= 0 {
+			return selected[n:], nil
+		}
+	}
+	return nil, insufficientFundsError{}
+}
+
+// creditCoin is a coinset.Coin for a credit of the wallet at the height of the best block.
+type creditCoin struct {
+	credit *wtxmgr.Credit
+	height int32
+}
+
+func (c *creditCoin) Hash() *chainhash.Hash { return &c.credit.OutPoint.Hash }
+func (c *creditCoin) Index() uint32         { return c.credit.OutPoint.Index }
+func (c *creditCoin) Value() util.Amount    { return c.credit.Amount }
+func (c *creditCoin) PkScript() []byte      { return c.credit.PkScript }
+func (c *creditCoin) ValueAge() int64       { return int64(c.credit.Amount) * c.NumConfs() }
+func (c *creditCoin) NumConfs() int64 {
+	if c.credit.Height == -1 {
+		return 0
+	}
+	return int64(c.height - c.credit.Height + 1)
+}
+
+// coinGroup is a set of credits that are only ever selected together, with their total value and their effective
+// value, which is their value less the fee for spending them.
+type coinGroup struct {
+	credits   []wtxmgr.Credit
+	value     util.Amount
+	effective util.Amount
+}
+
+// add adds a credit to the group.
+func (g *coinGroup) add(credit wtxmgr.Credit, feeSatPerKb util.Amount) {
+	g.credits = append(g.credits, credit)
+	g.value += credit.Amount
+	g.effective += credit.Amount - inputFee(credit.PkScript, feeSatPerKb)
+}
+
+// singleCoinGroups puts every credit in a group of its own.
+func singleCoinGroups(eligible []wtxmgr.Credit, feeSatPerKb util.Amount) []*coinGroup {
+	groups := make([]*coinGroup, len(eligible))
+	for i := range eligible {
+		groups[i] = &coinGroup{}
+		groups[i].add(eligible[i], feeSatPerKb)
+	}
+	return groups
+}
+
+// addressCoinGroups groups the credits by the output script, and so the address, they are paid to.
+func addressCoinGroups(eligible []wtxmgr.Credit, feeSatPerKb util.Amount) []*coinGroup {
+	var groups []*coinGroup
+	byScript := make(map[string]*coinGroup)
+	for i := range eligible {
+		g, ok := byScript[string(eligible[i].PkScript)]
+		if !ok {
+			g = &coinGroup{}
+			byScript[string(eligible[i].PkScript)] = g
+			groups = append(groups, g)
+		}
+		g.add(eligible[i], feeSatPerKb)
+	}
+	return groups
+}
+
+// groupCredits returns the credits of the groups.
+func groupCredits(groups []*coinGroup) []wtxmgr.Credit {
+	var credits []wtxmgr.Credit
+	for _, g := range groups {
+		credits = append(credits, g.credits...)
+	}
+	return credits
+}
+
+// selectionTarget is the transaction that coins are selected for. The selection algorithms work with effective values,
+// and the target is the value of the outputs plus the fee for the transaction without its inputs, since the effective
+// values already paid for those, less the effective value of the preset credits the transaction spends in any case. The
+// fee of a transaction is not quite the sum of the fees of its parts, so a selection is only taken once it is checked
+// to pay for the transaction exactly.
+type selectionTarget struct {
+	outputs     []*wire.TxOut
+	feeSatPerKb util.Amount
+	preset      []wtxmgr.Credit
+	target      util.Amount
+	// minChange is the smallest change output that is not dust. Smaller leftovers are added to the fee by txauthor.
+	minChange util.Amount
+}
+
+func newSelectionTarget(outputs []*wire.TxOut, feeSatPerKb util.Amount, preset []wtxmgr.Credit) *selectionTarget {
+	size := txsizes.EstimateVirtualSize(0, 0, 0, outputs, true)
+	target := h.SumOutputValues(outputs) + txrules.FeeForSerializeSize(feeSatPerKb, size)
+	for i := range preset {
+		target -= preset[i].Amount - inputFee(preset[i].PkScript, feeSatPerKb)
+	}
+	return &selectionTarget{
+		outputs:     outputs,
+		feeSatPerKb: feeSatPerKb,
+		preset:      preset,
+		target:      target,
+		minChange:   txrules.GetDustThreshold(txsizes.P2WPKHPkScriptSize, feeSatPerKb),
+	}
+}
+
+// slack is how far the effective value of n groups may fall short of the target while the groups still pay for the
+// transaction. The fee of each input is rounded down, and the segregated witness marker and the rounding of the witness
+// to virtual bytes are left out, which is at most a virtual byte for each input, the preset ones included, and one more
+// for the marker.
+func (t *selectionTarget) slack(n int) util.Amount {
+	n += len(t.preset)
+	return t.feeSatPerKb*util.Amount(n+1)/1000 + util.Amount(n)
+}
+
+// excess returns what the groups and the preset credits are worth beyond the outputs and fee of the transaction
+// spending them.
+func (t *selectionTarget) excess(groups []*coinGroup) util.Amount {
+	n := len(t.preset)
+	return selectionExcess(t.outputs, t.feeSatPerKb, append(t.preset[:n:n], groupCredits(groups)...))
+}
+
+// changeless returns whether a transaction spending the groups pays for its outputs and fee and leaves too little to
+// be paid back as change.
+func (t *selectionTarget) changeless(groups []*coinGroup) bool {
+	excess := t.excess(groups)
+	return excess >= 0 && txrules.IsDustAmount(excess, txsizes.P2WPKHPkScriptSize, t.feeSatPerKb)
+}
+
+// branchAndBound searches the groups depth first for the selection that pays for the transaction with the least left
+// over, as long as that is too little for a change output, so that the transaction needs no change. The search tries
+// the largest groups first and gives up after bnbMaxTries branches. nil is returned when there is no such selection.
+func (t *selectionTarget) branchAndBound(groups []*coinGroup) []wtxmgr.Credit {
+	candidates := make([]*coinGroup, 0, len(groups))
+	for _, g := range groups {
+		if g.effective > 0 {
+			candidates = append(candidates, g)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].effective > candidates[j].effective
+	})
+	// remaining[i] is the effective value of the candidates from i on, which bounds what a branch can still reach.
+	remaining := make([]util.Amount, len(candidates)+1)
+	for i := len(candidates) - 1; i >= 0; i-- {
+		remaining[i] = remaining[i+1] + candidates[i].effective
+	}
+	var (
+		best       []*coinGroup
+		bestExcess util.Amount
+		selection  []*coinGroup
+		tries      int
+		done       bool
+	)
+	var search func(i int, value util.Amount)
+	search = func(i int, value util.Amount) {
+		tries++
+		if done || tries > bnbMaxTries || value > t.target+t.minChange {
+			return
+		}
+		if value >= t.target-t.slack(len(selection)) && t.changeless(selection) {
+			if excess := t.excess(selection); best == nil || excess < bestExcess {
+				best = append(best[:0], selection...)
+				bestExcess = excess
+				done = excess == 0
+			}
+		}
+		// Adding to a selection that reached the target only leaves more over.
+		if value >= t.target || i == len(candidates) ||
+			value+remaining[i] < t.target-t.slack(len(candidates)) {
+			return
+		}
+		selection = append(selection, candidates[i])
+		search(i+1, value+candidates[i].effective)
+		selection = selection[:len(selection)-1]
+		// Leaving out a group and then taking one of the same value gives a selection that was already tried, so the
+		// groups of the same value are left out together.
+		next := i + 1
+		for next < len(candidates) && candidates[next].effective == candidates[i].effective {
+			next++
+		}
+		search(next, value)
+	}
+	search(0, 0)
+	if best == nil {
+		return nil
+	}
+	return groupCredits(best)
+}
+
+// knapsack selects a single group or all the small groups if they pay for the transaction without change, or
+// otherwise the groups that come closest to the target, or to the target plus the smallest change output, or the
+// single smallest group larger than that if it comes closer. Groups that cost more to spend than they are worth are
+// never selected.
+func (t *selectionTarget) knapsack(groups []*coinGroup) ([]wtxmgr.Credit, error) {
+	var (
+		applicable    []*coinGroup
+		lowestLarger  *coinGroup
+		applicableSum util.Amount
+	)
+	for _, g := range groups {
+		switch {
+		case g.effective <= 0:
+		case t.changeless([]*coinGroup{g}):
+			return groupCredits([]*coinGroup{g}), nil
+		case g.effective < t.target+t.minChange:
+			applicable = append(applicable, g)
+			applicableSum += g.effective
+		case lowestLarger == nil || g.effective < lowestLarger.effective:
+			lowestLarger = g
+		}
+	}
+	if t.changeless(applicable) {
+		return groupCredits(applicable), nil
+	}
+	// The smaller groups may pay for the transaction even when their effective value falls short by the slack.
+	target := t.target - t.slack(len(applicable))
+	if applicableSum < target {
+		if lowestLarger == nil {
+			return nil, insufficientFundsError{}
+		}
+		return t.complete([]*coinGroup{lowestLarger}, groups)
+	}
+	sort.Slice(applicable, func(i, j int) bool {
+		return applicable[i].effective > applicable[j].effective
+	})
+	best, bestValue := approximateBestSubset(applicable, applicableSum, target)
+	exact := t.changeless(best)
+	if !exact && applicableSum >= t.target+t.minChange {
+		best, bestValue = approximateBestSubset(applicable, applicableSum, t.target+t.minChange)
+	}
+	// The single larger group is preferred when the subset leaves change that would be dust, or overshoots by more.
+	if lowestLarger != nil && ((!exact && bestValue < t.target+t.minChange) || lowestLarger.effective <= bestValue) {
+		return t.complete([]*coinGroup{lowestLarger}, groups)
+	}
+	return t.complete(best, groups)
+}
+
+// complete returns the credits of the selected groups, adding more of the groups, largest first, where the estimate of
+// the fee fell short of what the selection has to pay.
+func (t *selectionTarget) complete(selected, groups []*coinGroup) ([]wtxmgr.Credit, error) {
+	if t.excess(selected) >= 0 {
+		return groupCredits(selected), nil
+	}
+	in := make(map[*coinGroup]struct{}, len(selected))
+	for _, g := range selected {
+		in[g] = struct{}{}
+	}
+	rest := make([]*coinGroup, 0, len(groups))
+	for _, g := range groups {
+		if _, ok := in[g]; !ok && g.effective > 0 {
+			rest = append(rest, g)
+		}
+	}
+	sort.Slice(rest, func(i, j int) bool {
+		return rest[i].effective > rest[j].effective
+	})
+	for _, g := range rest {
+		selected = append(selected, g)
+		if t.excess(selected) >= 0 {
+			return groupCredits(selected), nil
+		}
+	}
+	return nil, insufficientFundsError{}
+}
+
+// approximateBestSubset looks for the subset of the groups, sorted by descending effective value and worth total
+// together, whose effective value is closest to but not below the target. Each round includes every group by chance in
+// a first pass, and every group left out in a second pass if the target is not yet reached, dropping a group again as
+// soon as the target is reached.
+func approximateBestSubset(groups []*coinGroup, total, target util.Amount) ([]*coinGroup, util.Amount) {
+	knapsackRandMtx.Lock()
+	defer knapsackRandMtx.Unlock()
+	best := make([]bool, len(groups))
+	for i := range best {
+		best[i] = true
+	}
+	bestValue := total
+	included := make([]bool, len(groups))
+	for round := 0; round < knapsackIterations && bestValue != target; round++ {
+		for i := range included {
+			included[i] = false
+		}
+		var value util.Amount
+		reached := false
+		for pass := 0; pass < 2 && !reached; pass++ {
+			for i, g := range groups {
+				// The first pass includes at random, the second includes whatever the first left out.
+				if included[i] || (pass == 0 && knapsackRand.Intn(2) == 0) {
+					continue
+				}
+				value += g.effective
+				included[i] = true
+				if value >= target {
+					reached = true
+					if value < bestValue {
+						bestValue = value
+						copy(best, included)
+					}
+					value -= g.effective
+					included[i] = false
+				}
+			}
+		}
+	}
+	subset := make([]*coinGroup, 0, len(groups))
+	for i, g := range groups {
+		if best[i] {
+			subset = append(subset, g)
+		}
+	}
+	return subset, bestValue
+}
+
+// inputFee returns the fee at feeSatPerKb for spending an output with the passed script, by the weight of the input,
+// rounded down. Like txauthor, P2SH outputs are taken to be nested P2WPKH and any other script but P2WPKH to be P2PKH.
+func inputFee(pkScript []byte, feeSatPerKb util.Amount) util.Amount {
+	var weight int
+	switch {
+	case txscript.IsPayToScriptHash(pkScript):
+		weight = txsizes.RedeemNestedP2WPKHInputSize*4 + txsizes.RedeemP2WPKHInputWitnessWeight
+	case txscript.IsPayToWitnessPubKeyHash(pkScript):
+		weight = txsizes.RedeemP2WPKHInputSize*4 + txsizes.RedeemP2WPKHInputWitnessWeight
+	default:
+		weight = txsizes.RedeemP2PKHInputSize * 4
+	}
+	return feeSatPerKb * util.Amount(weight) / 4000
+}
+
+// selectionFee returns the fee at feeSatPerKb of a transaction spending the credits to the outputs and a change
+// output, estimated the way txauthor.NewUnsignedTransaction does.
+func selectionFee(outputs []*wire.TxOut, feeSatPerKb util.Amount, credits []wtxmgr.Credit) util.Amount {
+	var nested, p2wpkh, p2pkh int
+	for i := range credits {
+		switch {
+		case txscript.IsPayToScriptHash(credits[i].PkScript):
+			nested++
+		case txscript.IsPayToWitnessPubKeyHash(credits[i].PkScript):
+			p2wpkh++
+		default:
+			p2pkh++
+		}
+	}
+	size := txsizes.EstimateVirtualSize(p2pkh, p2wpkh, nested, outputs, true)
+	return txrules.FeeForSerializeSize(feeSatPerKb, size)
+}
+
+// selectionExcess returns what the credits are worth beyond the outputs and the fee of a transaction spending them,
+// which is negative if they do not pay for it.
+func selectionExcess(outputs []*wire.TxOut, feeSatPerKb util.Amount, credits []wtxmgr.Credit) util.Amount {
+	var value util.Amount
+	for i := range credits {
+		value += credits[i].Amount
+	}
+	return value - h.SumOutputValues(outputs) - selectionFee(outputs, feeSatPerKb, credits)
+}
+
+// makeSelectedInputSource creates an input source that spends every one of the credits chosen by a CoinSelector,
+// whatever the target, as they were selected for the transaction being built. The inputs are given the passed sequence
+// number.
+func makeSelectedInputSource(selected []wtxmgr.Credit, sequence uint32) txauthor.InputSource {
+	total := util.Amount(0)
+	inputs := make([]*wire.TxIn, 0, len(selected))
+	scripts := make([][]byte, 0, len(selected))
+	inputValues := make([]util.Amount, 0, len(selected))
+	for i := range selected {
+		input := wire.NewTxIn(&selected[i].OutPoint, nil, nil)
+		input.Sequence = sequence
+		total += selected[i].Amount
+		inputs = append(inputs, input)
+		scripts = append(scripts, selected[i].PkScript)
+		inputValues = append(inputValues, selected[i].Amount)
+	}
+	return func(util.Amount) (util.Amount, []*wire.TxIn, []util.Amount, [][]byte, error) {
+		return total, inputs, inputValues, scripts, nil
+	}
+}
@@ -0,0 +1,275 @@
+package wallet
+
+import (
+	"testing"
+
+	txauthor "github.com/p9c/pod/pkg/chain/tx/author"
+	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txsizes "github.com/p9c/pod/pkg/chain/tx/sizes"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+)
+
+const testFeeRate = txrules.DefaultRelayFeePerKb
+
+// p2wpkhScript returns a pay-to-witness-pubkey-hash script for an address told apart by n.
+func p2wpkhScript(n byte) []byte {
+	script := make([]byte, 22)
+	script[1] = 0x14
+	script[2] = n
+	return script
+}
+
+// testCredit returns a credit of the passed amount paying to the P2WPKH script of address addr, mined at height.
+func testCredit(n int, amount util.Amount, addr byte, height int32) wtxmgr.Credit {
+	c := wtxmgr.Credit{
+		OutPoint: wire.OutPoint{Index: uint32(n)},
+		Amount:   amount,
+		PkScript: p2wpkhScript(addr),
+	}
+	c.OutPoint.Hash[0] = byte(n)
+	c.Height = height
+	return c
+}
+
+// creditSet returns the outpoints of the credits.
+func creditSet(credits []wtxmgr.Credit) map[wire.OutPoint]struct{} {
+	set := make(map[wire.OutPoint]struct{}, len(credits))
+	for _, c := range credits {
+		set[c.OutPoint] = struct{}{}
+	}
+	return set
+}
+
+// checkSelection ensures the selection pays for the outputs, and that the transaction txauthor builds from it spends
+// all of it and has change exactly when wantChange is set.
+func checkSelection(t *testing.T, name string, outputs []*wire.TxOut, selected []wtxmgr.Credit, wantChange bool) {
+	excess := selectionExcess(outputs, testFeeRate, selected)
+	if excess < 0 {
+		t.Errorf("%s: selection does not pay for the transaction, short by %v", name, -excess)
+		return
+	}
+	tx, err := txauthor.NewUnsignedTransaction(outputs, testFeeRate, makeSelectedInputSource(selected, txrules.MaxRBFSequence),
+		func() ([]byte, error) { return p2wpkhScript(0xff), nil })
+	if err != nil {
+		t.Errorf("%s: unexpected error authoring transaction: %v", name, err)
+		return
+	}
+	if len(tx.Tx.TxIn) != len(selected) {
+		t.Errorf("%s: transaction spends %d inputs, want %d", name, len(tx.Tx.TxIn), len(selected))
+	}
+	if hasChange := tx.ChangeIndex >= 0; hasChange != wantChange {
+		t.Errorf("%s: transaction has change %v, want %v (excess %v)", name, hasChange, wantChange, excess)
+	}
+}
+
+// TestBranchAndBound ensures the branch and bound selection finds the set of credits paying for a transaction without
+// change, and that the default strategy falls back to knapsack selection when there is none.
+func TestBranchAndBound(t *testing.T) {
+	eligible := []wtxmgr.Credit{
+		testCredit(1, 1e6, 1, 10),
+		testCredit(2, 2e6, 2, 10),
+		testCredit(3, 5e6, 3, 10),
+		testCredit(4, 33e5, 4, 10),
+	}
+	// The outputs are set to be paid exactly by the first two credits.
+	outputs := []*wire.TxOut{wire.NewTxOut(0, p2wpkhScript(0x80))}
+	outputs[0].Value = int64(3e6 - selectionFee(outputs, testFeeRate, eligible[:2]))
+	selected, err := BranchAndBoundCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	set := creditSet(selected)
+	if _, ok := set[eligible[0].OutPoint]; !ok || len(set) != 2 {
+		t.Errorf("unexpected selection %v", set)
+	}
+	if _, ok := set[eligible[1].OutPoint]; !ok {
+		t.Errorf("unexpected selection %v", set)
+	}
+	checkSelection(t, "exact match", outputs, selected, false)
+	// Paying a little less still needs no change, as the difference is dust.
+	outputs[0].Value -= int64(txrules.GetDustThreshold(txsizes.P2WPKHPkScriptSize, testFeeRate) / 2)
+	selected, err = BranchAndBoundCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSelection(t, "match within dust", outputs, selected, false)
+	// No set pays for 1.5e6 without change.
+	outputs[0].Value = 15e5
+	_, err = BranchAndBoundCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if _, ok := err.(txauthor.InputSourceError); !ok {
+		t.Fatalf("expected an input source error, got %v", err)
+	}
+	selector, err := CoinSelectorByName(CoinSelectBnB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	selected, err = selector.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if err != nil {
+		t.Fatalf("unexpected error falling back to knapsack: %v", err)
+	}
+	checkSelection(t, "knapsack fallback", outputs, selected, true)
+}
+
+// TestKnapsack ensures the knapsack selection prefers an exact match, spends the smallest credit large enough when no
+// combination of smaller ones is, and reports insufficient funds.
+func TestKnapsack(t *testing.T) {
+	eligible := []wtxmgr.Credit{
+		testCredit(1, 1e5, 1, 10),
+		testCredit(2, 2e5, 2, 10),
+		testCredit(3, 3e5, 3, 10),
+		testCredit(4, 5e7, 4, 10),
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(0, p2wpkhScript(0x80))}
+	outputs[0].Value = int64(5e5 - selectionFee(outputs, testFeeRate, eligible[1:3]))
+	selected, err := KnapsackCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkSelection(t, "exact subset", outputs, selected, false)
+	if _, ok := creditSet(selected)[eligible[3].OutPoint]; ok {
+		t.Errorf("the large credit was spent for an exactly matching subset")
+	}
+	outputs[0].Value = 1e6
+	selected, err = KnapsackCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(selected) != 1 || selected[0].OutPoint != eligible[3].OutPoint {
+		t.Errorf("expected the large credit alone, got %v", creditSet(selected))
+	}
+	checkSelection(t, "lowest larger", outputs, selected, true)
+	outputs[0].Value = 1e8
+	_, err = KnapsackCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+	if _, ok := err.(txauthor.InputSourceError); !ok {
+		t.Fatalf("expected an input source error, got %v", err)
+	}
+}
+
+// TestPrivacyCoinSelector ensures the credits paid to an address are always spent together.
+func TestPrivacyCoinSelector(t *testing.T) {
+	eligible := []wtxmgr.Credit{
+		testCredit(1, 1e6, 1, 10),
+		testCredit(2, 4e6, 2, 10),
+		testCredit(3, 2e6, 1, 10),
+		testCredit(4, 6e5, 3, 10),
+		testCredit(5, 7e5, 3, 10),
+	}
+	for _, value := range []int64{5e5, 25e5, 35e5, 6e6} {
+		outputs := []*wire.TxOut{wire.NewTxOut(value, p2wpkhScript(0x80))}
+		selected, err := PrivacyCoinSelector{}.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+		if err != nil {
+			t.Errorf("paying %d: unexpected error: %v", value, err)
+			continue
+		}
+		if selectionExcess(outputs, testFeeRate, selected) < 0 {
+			t.Errorf("paying %d: selection does not pay for the transaction", value)
+		}
+		set := creditSet(selected)
+		spent := make(map[byte]int)
+		for _, c := range eligible {
+			if _, ok := set[c.OutPoint]; ok {
+				spent[c.PkScript[2]]++
+			}
+		}
+		for _, c := range eligible {
+			if n, ok := spent[c.PkScript[2]]; ok {
+				var total int
+				for _, d := range eligible {
+					if d.PkScript[2] == c.PkScript[2] {
+						total++
+					}
+				}
+				if n != total {
+					t.Errorf("paying %d: spent %d of the %d outputs of address %d", value, n, total, c.PkScript[2])
+				}
+			}
+		}
+	}
+}
+
+// TestCoinsetCoinSelector ensures the coinset selectors are wired in with the fee of the inputs they select.
+func TestCoinsetCoinSelector(t *testing.T) {
+	eligible := []wtxmgr.Credit{
+		testCredit(1, 3e6, 1, 19),
+		testCredit(2, 5e6, 2, 20),
+		testCredit(3, 4e6, 3, 5),
+		testCredit(4, 2e6, 4, 1),
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(5e6, p2wpkhScript(0x80))}
+	tests := []struct {
+		strategy string
+		want     []int
+	}{
+		// The largest credit falls short by the fee, so the next largest is added.
+		{CoinSelectLargest, []int{1, 2}},
+		// Value times confirmations is 6.4e7 and 4e7 for the oldest credits, ahead of 6e6 and 5e6 for the others.
+		{CoinSelectValueAge, []int{2, 3}},
+	}
+	for _, test := range tests {
+		selector, err := CoinSelectorByName(test.strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		selected, err := selector.SelectCoins(outputs, testFeeRate, nil, eligible, 20)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.strategy, err)
+			continue
+		}
+		set := creditSet(selected)
+		if len(set) != len(test.want) {
+			t.Errorf("%s: unexpected selection %v", test.strategy, set)
+			continue
+		}
+		for _, i := range test.want {
+			if _, ok := set[eligible[i].OutPoint]; !ok {
+				t.Errorf("%s: credit %d not selected", test.strategy, i)
+			}
+		}
+		checkSelection(t, test.strategy, outputs, selected, true)
+	}
+	if _, err := CoinSelectorByName("random"); err == nil {
+		t.Errorf("expected an error for an unknown strategy")
+	}
+}
+
+// TestPresetCoinSelection ensures every strategy only selects the credits the preset ones fall short by, and returns
+// none of the preset credits.
+func TestPresetCoinSelection(t *testing.T) {
+	preset := []wtxmgr.Credit{testCredit(1, 3e6, 1, 10)}
+	eligible := []wtxmgr.Credit{
+		testCredit(2, 1e6, 2, 10),
+		testCredit(3, 4e6, 3, 10),
+		testCredit(4, 25e5, 2, 10),
+		testCredit(5, 9e6, 4, 10),
+	}
+	outputs := []*wire.TxOut{wire.NewTxOut(5e6, p2wpkhScript(0x80))}
+	for _, strategy := range []string{CoinSelectBnB, CoinSelectKnapsack, CoinSelectPrivacy, CoinSelectLargest,
+		CoinSelectValueAge} {
+		selector, err := CoinSelectorByName(strategy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		selected, err := selector.SelectCoins(outputs, testFeeRate, preset, eligible, 20)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", strategy, err)
+			continue
+		}
+		set := creditSet(selected)
+		if _, ok := set[preset[0].OutPoint]; ok {
+			t.Errorf("%s: preset credit returned with the selection", strategy)
+		}
+		if len(set) == 0 {
+			t.Errorf("%s: nothing selected to pay for the rest of the outputs", strategy)
+		}
+		if excess := selectionExcess(outputs, testFeeRate, append(append([]wtxmgr.Credit{}, preset...),
+			selected...)); excess < 0 {
+			t.Errorf("%s: selection with the preset credit is short by %v", strategy, -excess)
+		}
+		// Without the preset credit, the selection alone pays for less than the outputs.
+		if _, ok := set[eligible[3].OutPoint]; !ok && selectionExcess(outputs, testFeeRate, selected) >= 0 {
+			t.Errorf("%s: selection pays for the outputs without the preset credit", strategy)
+		}
+	}
+}
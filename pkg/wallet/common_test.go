@@ -0,0 +1,164 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	_ "github.com/p9c/pod/pkg/db/walletdb/bdb"
+	"github.com/p9c/pod/pkg/util"
+	"github.com/p9c/pod/pkg/util/hdkeychain"
+	qu "github.com/p9c/pod/pkg/util/quit"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+	"github.com/p9c/pod/pkg/wallet/chain"
+)
+
+var (
+	testPubPass  = []byte("public")
+	testPrivPass = []byte("private")
+)
+
+// mockChainClient is a chain.Interface for the tests with a fixed best block, which records the transactions it is
+// asked to send. Filtering blocks finds nothing unless filterBlocks is set.
+type mockChainClient struct {
+	sync.Mutex
+	bestBlock    waddrmgr.BlockStamp
+	sent         []*wire.MsgTx
+	filterBlocks func(*chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error)
+}
+
+var _ chain.Interface = (*mockChainClient)(nil)
+
+func (c *mockChainClient) Start() error {
+	return nil
+}
+func (c *mockChainClient) Stop()            {}
+func (c *mockChainClient) WaitForShutdown() {}
+func (c *mockChainClient) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return &c.bestBlock.Hash, c.bestBlock.Height, nil
+}
+func (c *mockChainClient) GetBlock(*chainhash.Hash) (*wire.MsgBlock, error) {
+	return nil, nil
+}
+func (c *mockChainClient) GetBlockHash(height int64) (*chainhash.Hash, error) {
+	return &chainhash.Hash{byte(height)}, nil
+}
+func (c *mockChainClient) GetBlockHeader(hash *chainhash.Hash) (*wire.BlockHeader, error) {
+	return &wire.BlockHeader{Timestamp: mockBlockTime(int32(hash[0]))}, nil
+}
+func (c *mockChainClient) FilterBlocks(req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+	if c.filterBlocks == nil {
+		return nil, nil
+	}
+	return c.filterBlocks(req)
+}
+func (c *mockChainClient) BlockStamp() (*waddrmgr.BlockStamp, error) {
+	bs := c.bestBlock
+	return &bs, nil
+}
+func (c *mockChainClient) SendRawTransaction(tx *wire.MsgTx, allowHighFees bool) (*chainhash.Hash, error) {
+	c.Lock()
+	c.sent = append(c.sent, tx)
+	c.Unlock()
+	hash := tx.TxHash()
+	return &hash, nil
+}
+func (c *mockChainClient) Rescan(*chainhash.Hash, []util.Address, map[wire.OutPoint]util.Address) error {
+	return nil
+}
+func (c *mockChainClient) NotifyReceived([]util.Address) error {
+	return nil
+}
+func (c *mockChainClient) NotifyBlocks() error {
+	return nil
+}
+func (c *mockChainClient) Notifications() <-chan interface{} {
+	return nil
+}
+func (c *mockChainClient) BackEnd() string {
+	return "mock"
+}
+
+// mockBlockTime returns the timestamp of the block of the mock chain client at the passed height. The blocks are ten
+// minutes apart, and the hash of a block is its height in the first byte.
+func mockBlockTime(height int32) time.Time {
+	return time.Unix(1600000000+int64(height)*600, 0)
+}
+
+// testWallet creates a new wallet in a temporary directory, connected to a mock chain client whose best block is at
+// height 200, and unlocks it. The returned function stops the wallet and removes it.
+func testWallet(t *testing.T) (*Wallet, *mockChainClient, func()) {
+	dir, err := ioutil.TempDir("", "testwallet")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unable to generate seed: %v", err)
+	}
+	loader := NewLoader(&netparams.TestNet3Params, filepath.Join(dir, WalletDbName), 250)
+	w, err := loader.CreateNewWallet(testPubPass, testPrivPass, seed, time.Now(), false, nil, qu.T())
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+	chainClient := &mockChainClient{bestBlock: waddrmgr.BlockStamp{Height: 200}}
+	w.chainClient = chainClient
+	teardown := func() {
+		w.Stop()
+		w.WaitForShutdown()
+		w.db.Close()
+		os.RemoveAll(dir)
+	}
+	if err := w.Unlock(testPrivPass, nil); err != nil {
+		teardown()
+		t.Fatalf("unable to unlock wallet: %v", err)
+	}
+	return w, chainClient, teardown
+}
+
+// fundTestWallet adds a transaction mined at the passed height to the wallet, paying amount to a new address of the
+// default account, and returns the address.
+func fundTestWallet(t *testing.T, w *Wallet, amount util.Amount, height int32) util.Address {
+	addr, err := w.NewAddress(waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0044, true)
+	if err != nil {
+		t.Fatalf("unable to get a new address: %v", err)
+	}
+	fundTestAddress(t, w, addr, amount, height)
+	return addr
+}
+
+// fundTestAddress adds a transaction mined at the passed height to the wallet, paying amount to an address of it.
+func fundTestAddress(t *testing.T, w *Wallet, addr util.Address, amount util.Amount, height int32) {
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: chainhash.Hash{byte(height)}}, nil, nil))
+	tx.AddTxOut(wire.NewTxOut(int64(amount), pkScript))
+	rec, err := wtxmgr.NewTxRecordFromMsgTx(tx, time.Now())
+	if err != nil {
+		t.Fatalf("unable to create tx record: %v", err)
+	}
+	block := &wtxmgr.BlockMeta{
+		Block: wtxmgr.Block{Hash: chainhash.Hash{byte(height)}, Height: height},
+		Time:  time.Now(),
+	}
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		return w.addRelevantTx(dbtx, rec, block)
+	})
+	if err != nil {
+		t.Fatalf("unable to add funding transaction: %v", err)
+	}
+}
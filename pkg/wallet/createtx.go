@@ -22,7 +22,10 @@ type byAmount []wtxmgr.Credit
 func (s byAmount) Len() int           { return len(s) }
 func (s byAmount) Less(i, j int) bool { return s[i].Amount < s[j].Amount }
 func (s byAmount) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
-func makeInputSource(eligible []wtxmgr.Credit) txauthor.InputSource {
+
+// makeInputSource creates an input source that spends the eligible credits, largest first, until the target is reached.
+// The inputs are given the passed sequence number.
+func makeInputSource(eligible []wtxmgr.Credit, sequence uint32) txauthor.InputSource {
 	// Pick largest outputs first. This is only done for compatibility with previous tx creation code, not because it's
 	// a good idea.
 	sort.Sort(sort.Reverse(byAmount(eligible)))
@@ -38,6 +41,7 @@ func makeInputSource(eligible []wtxmgr.Credit) txauthor.InputSource {
 			nextCredit := &eligible[0]
 			eligible = eligible[1:]
 			nextInput := wire.NewTxIn(&nextCredit.OutPoint, nil, nil)
+			nextInput.Sequence = sequence
 			currentTotal += nextCredit.Amount
 			currentInputs = append(currentInputs, nextInput)
 			currentScripts = append(currentScripts, nextCredit.PkScript)
@@ -88,11 +92,11 @@ func (s secretSource) GetScript(addr util.Address) ([]byte, error) {
 }
 
 // txToOutputs creates a signed transaction which includes each output from outputs. Previous outputs to reedeem are
-// chosen from the passed account's UTXO set and minconf policy. An additional output may be added to return change to
-// the wallet. An appropriate fee is included based on the wallet's current relay fee. The wallet must be unlocked to
-// create the transaction.
+// chosen by the coin selector from the passed account's UTXO set and minconf policy. An additional output may be added
+// to return change to the wallet. An appropriate fee is included based on the wallet's current relay fee. The wallet
+// must be unlocked to create the transaction.
 func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32,
-	minconf int32, feeSatPerKb util.Amount) (tx *txauthor.AuthoredTx, err error) {
+	minconf int32, feeSatPerKb util.Amount, coinSelector CoinSelector) (tx *txauthor.AuthoredTx, err error) {
 	chainClient, err := w.requireChainClient()
 	if err != nil {
 		Error(err)
@@ -106,12 +110,17 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32,
 			Error(err)
 			return err
 		}
-		eligible, err := w.findEligibleOutputs(dbtx, account, minconf, bs)
+		eligible, err := w.findEligibleOutputs(dbtx, nil, account, minconf, bs)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		selected, err := coinSelector.SelectCoins(outputs, feeSatPerKb, nil, eligible, bs.Height)
 		if err != nil {
 			Error(err)
 			return err
 		}
-		inputSource := makeInputSource(eligible)
+		inputSource := makeSelectedInputSource(selected, w.InputSequence())
 		changeSource := func() ([]byte, error) {
 			// Derive the change output script. As a hack to allow spending from the imported account, change addresses
 			// are created from account 0.
@@ -159,7 +168,11 @@ func (w *Wallet) txToOutputs(outputs []*wire.TxOut, account uint32,
 	}
 	return tx, nil
 }
-func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, account uint32, minconf int32, bs *waddrmgr.BlockStamp) ([]wtxmgr.Credit, error) {
+
+// findEligibleOutputs returns the unspent outputs of an account that have minconf confirmations, are mature and are not
+// locked. The account is looked for in the passed key scope, or in every scope if it is nil.
+func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, keyScope *waddrmgr.KeyScope, account uint32, minconf int32,
+	bs *waddrmgr.BlockStamp) ([]wtxmgr.Credit, error) {
 	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
 	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
 	unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
@@ -197,10 +210,13 @@ func (w *Wallet) findEligibleOutputs(dbtx walletdb.ReadTx, account uint32, minco
 		if err != nil || len(addrs) != 1 {
 			continue
 		}
-		_, addrAcct, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
+		scopedMgr, addrAcct, err := w.Manager.AddrAccount(addrmgrNs, addrs[0])
 		if err != nil || addrAcct != account {
 			continue
 		}
+		if keyScope != nil && scopedMgr.Scope() != *keyScope {
+			continue
+		}
 		eligible = append(eligible, *output)
 	}
 	return eligible, nil
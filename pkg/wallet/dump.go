@@ -0,0 +1,469 @@
+package wallet
+
+import (
+	"bufio"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/util"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+)
+
+const (
+	// dumpTimeFormat is the ISO 8601 format the times of a wallet dump are written in.
+	dumpTimeFormat = "2006-01-02T15:04:05Z"
+	// dumpBlockMargin is how far before the earliest key creation time of an imported wallet dump the rescan starts,
+	// as block header timestamps are only loosely ordered.
+	dumpBlockMargin = 48 * time.Hour
+)
+
+// Backup writes a consistent copy of the wallet database to dest while the wallet keeps running. When dest is a
+// directory the copy is given the name of the wallet database file inside it. The copy is written to a temporary file
+// next to dest and only moved into place once complete, so a failed backup never leaves a truncated file behind.
+func (w *Wallet) Backup(dest string) error {
+	if fi, err := os.Stat(dest); err == nil && fi.IsDir() {
+		dest = filepath.Join(dest, WalletDbName)
+	}
+	if db, ok := w.db.(interface{ Path() string }); ok && sameFile(db.Path(), dest) {
+		return fmt.Errorf("the backup would overwrite the wallet database %s", dest)
+	}
+	f, err := ioutil.TempFile(filepath.Dir(dest), filepath.Base(dest)+".tmp")
+	if err != nil {
+		Error(err)
+		return err
+	}
+	if err = w.db.Copy(f); err == nil {
+		err = f.Sync()
+	}
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), dest)
+	}
+	if err != nil {
+		Error(err)
+		_ = os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+// sameFile returns whether both paths name the same existing file.
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(fa, fb)
+}
+
+// DumpWallet writes the private keys and scripts of the wallet to wr in the text format of the reference client's
+// dumpwallet. Every key is written on a line of its own together with its creation time, its label or whether it is
+// a change or reserve key, its address and, for keys derived from the HD root, its derivation path. The wallet does
+// not keep creation times for single keys, so the wallet birthday is written for all of them.
+//
+// The wallet must be unlocked. Keys of watching-only accounts are left out, as there is no private key to write.
+func (w *Wallet) DumpWallet(wr io.Writer) error {
+	syncedTo := w.Manager.SyncedTo()
+	birthday := w.Manager.Birthday()
+	var lines []string
+	err := walletdb.View(
+		w.db, func(tx walletdb.ReadTx) error {
+			addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+			// The addresses are looked up once the iteration is done, as it holds the lock of the scoped managers.
+			var addrs []util.Address
+			err := w.Manager.ForEachActiveAddress(
+				addrmgrNs, func(addr util.Address) error {
+					addrs = append(addrs, addr)
+					return nil
+				},
+			)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			for _, addr := range addrs {
+				ma, err := w.Manager.Address(addrmgrNs, addr)
+				if err != nil {
+					Error(err)
+					return err
+				}
+				label, err := w.Manager.AddrLabel(addrmgrNs, addr)
+				if err != nil {
+					Error(err)
+					return err
+				}
+				line, err := dumpLine(ma, label, birthday)
+				if err != nil {
+					Error(err)
+					return err
+				}
+				if line != "" {
+					lines = append(lines, line)
+				}
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	bw := bufio.NewWriter(wr)
+	_, _ = fmt.Fprintf(bw, "# Wallet dump created by pod\n")
+	_, _ = fmt.Fprintf(bw, "# * Created on %s\n", time.Now().UTC().Format(dumpTimeFormat))
+	_, _ = fmt.Fprintf(bw, "# * Best block at time of backup was %d (%s),\n", syncedTo.Height, syncedTo.Hash)
+	_, _ = fmt.Fprintf(bw, "#   mined on %s\n", syncedTo.Timestamp.UTC().Format(dumpTimeFormat))
+	_, _ = fmt.Fprintf(bw, "# * Wallet birthday is %s\n\n", birthday.UTC().Format(dumpTimeFormat))
+	for _, line := range lines {
+		_, _ = fmt.Fprintln(bw, line)
+	}
+	_, _ = fmt.Fprintf(bw, "\n# End of dump\n")
+	return bw.Flush()
+}
+
+// dumpLine returns the wallet dump line of a managed address, or an empty string if the address has no secret to
+// dump.
+func dumpLine(ma waddrmgr.ManagedAddress, label string, created time.Time) (string, error) {
+	createdStr := created.UTC().Format(dumpTimeFormat)
+	addrStr := ma.Address().EncodeAddress()
+	switch a := ma.(type) {
+	case waddrmgr.ManagedPubKeyAddress:
+		wif, err := a.ExportPrivKey()
+		if err != nil {
+			if waddrmgr.IsError(err, waddrmgr.ErrWatchingOnly) {
+				return "", nil
+			}
+			return "", err
+		}
+		kind := "reserve=1"
+		switch {
+		case label != "":
+			kind = "label=" + encodeDumpString(label)
+		case a.Internal():
+			kind = "change=1"
+		}
+		line := fmt.Sprintf("%s %s %s # addr=%s", wif, createdStr, kind, addrStr)
+		if scope, path, ok := a.DerivationInfo(); ok {
+			line += fmt.Sprintf(" hdkeypath=m/%d'/%d'/%d'/%d/%d",
+				scope.Purpose, scope.Coin, path.Account, path.Branch, path.Index)
+		}
+		return line, nil
+	case waddrmgr.ManagedScriptAddress:
+		script, err := a.Script()
+		if err != nil {
+			if waddrmgr.IsError(err, waddrmgr.ErrWatchingOnly) {
+				return "", nil
+			}
+			return "", err
+		}
+		kind := "script=1"
+		if label != "" {
+			kind += " label=" + encodeDumpString(label)
+		}
+		return fmt.Sprintf("%x %s %s # addr=%s", script, createdStr, kind, addrStr), nil
+	}
+	return "", nil
+}
+
+// encodeDumpString escapes the characters of a label that can not be written as they are in a wallet dump the way the
+// reference client does, as a percent sign followed by the hexadecimal value of the byte.
+func encodeDumpString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x80 || c == '%' {
+			_, _ = fmt.Fprintf(&b, "%%%02x", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// decodeDumpString reverses encodeDumpString. Percent signs that are not followed by two hexadecimal digits are kept as
+// they are.
+func decodeDumpString(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if c, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// dumpEntry is a key or script read from a wallet dump.
+type dumpEntry struct {
+	wif     *util.WIF
+	script  []byte
+	created time.Time
+	label   string
+	// addr is the address the dump names for the entry, or the address the key or script pays to by default if it
+	// names none.
+	addr util.Address
+}
+
+// scope returns the key scope a key of the entry is imported into, which follows from the kind of address the dump
+// names for it.
+func (e *dumpEntry) scope() waddrmgr.KeyScope {
+	switch e.addr.(type) {
+	case *util.AddressScriptHash:
+		return waddrmgr.KeyScopeBIP0049Plus
+	case *util.AddressWitnessPubKeyHash:
+		return waddrmgr.KeyScopeBIP0084
+	}
+	return waddrmgr.KeyScopeBIP0044
+}
+
+// parseDumpLine parses a line of a wallet dump. Nil is returned for lines without a key, such as comments.
+func parseDumpLine(line string, net *netparams.Params) (*dumpEntry, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+		return nil, nil
+	}
+	if len(fields) < 2 {
+		return nil, fmt.Errorf("missing key creation time")
+	}
+	e := &dumpEntry{}
+	// An unknown creation time, which the reference client writes as 0, leaves the time zero.
+	if created, err := time.Parse(dumpTimeFormat, fields[1]); err == nil {
+		e.created = created
+	}
+	var isScript bool
+	var addrStr string
+	comment := false
+	for _, f := range fields[2:] {
+		if strings.HasPrefix(f, "#") {
+			comment = true
+			continue
+		}
+		switch {
+		case comment && strings.HasPrefix(f, "addr="):
+			// The reference client lists every address of a key separated by commas, the first being its own kind.
+			addrStr = strings.Split(strings.TrimPrefix(f, "addr="), ",")[0]
+		case comment:
+		case f == "script=1":
+			isScript = true
+		case strings.HasPrefix(f, "label="):
+			e.label = decodeDumpString(strings.TrimPrefix(f, "label="))
+		}
+	}
+	var err error
+	if isScript {
+		if e.script, err = hex.DecodeString(fields[0]); err != nil {
+			return nil, fmt.Errorf("invalid script: %v", err)
+		}
+		e.addr, err = util.NewAddressScriptHash(e.script, net)
+		if err != nil {
+			return nil, err
+		}
+		return e, nil
+	}
+	if e.wif, err = util.DecodeWIF(fields[0]); err != nil {
+		return nil, fmt.Errorf("invalid private key: %v", err)
+	}
+	if !e.wif.IsForNet(net) {
+		return nil, fmt.Errorf("private key is not for the %s network", net.Name)
+	}
+	if addrStr != "" {
+		if addr, err := util.DecodeAddress(addrStr, net); err == nil {
+			e.addr = addr
+		}
+	}
+	if e.addr == nil {
+		e.addr, err = util.NewAddressPubKeyHash(util.Hash160(e.wif.SerializePubKey()), net)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// ImportWallet imports the private keys and scripts of a wallet dump in the reference client's dumpwallet format, as
+// DumpWallet writes it, together with their labels. Keys are imported into the key scope matching the kind of address
+// the dump names for them. Keys and scripts the wallet already has only have their label updated. The whole dump is
+// read before anything is imported, so a malformed dump changes nothing.
+//
+// Once imported, the chain is rescanned for the new keys and scripts from the block mined before the earliest key
+// creation time in the dump. The number of keys and scripts new to the wallet is returned.
+func (w *Wallet) ImportWallet(r io.Reader) (int, error) {
+	var entries []*dumpEntry
+	var earliest time.Time
+	var unknownTime bool
+	scanner := bufio.NewScanner(r)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		e, err := parseDumpLine(scanner.Text(), w.chainParams)
+		if err != nil {
+			Error(err)
+			return 0, fmt.Errorf("line %d of the wallet dump: %v", lineNo, err)
+		}
+		if e == nil {
+			continue
+		}
+		switch {
+		case e.created.IsZero():
+			unknownTime = true
+		case earliest.IsZero() || e.created.Before(earliest):
+			earliest = e.created
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		Error(err)
+		return 0, err
+	}
+	// A key of unknown age may have been used at any time.
+	if unknownTime {
+		earliest = time.Time{}
+	}
+	bs := w.blockStampBefore(earliest)
+	var imported []util.Address
+	err := walletdb.Update(
+		w.db, func(tx walletdb.ReadWriteTx) error {
+			addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			for _, e := range entries {
+				addr, err := w.importDumpEntry(addrmgrNs, e, &bs)
+				switch {
+				case err == nil:
+					imported = append(imported, addr)
+				case waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress):
+					addr = e.addr
+				default:
+					Error(err)
+					return err
+				}
+				if e.label == "" {
+					continue
+				}
+				err = w.Manager.SetAddrLabel(addrmgrNs, addr, e.label)
+				if err != nil {
+					Error(err)
+					if !waddrmgr.IsError(err, waddrmgr.ErrAddressNotFound) {
+						return err
+					}
+				}
+			}
+			if len(imported) == 0 || !bs.Timestamp.Before(w.Manager.Birthday()) {
+				return nil
+			}
+			return w.Manager.SetBirthday(addrmgrNs, bs.Timestamp)
+		},
+	)
+	if err != nil {
+		Error(err)
+		return 0, err
+	}
+	if len(imported) != 0 {
+		Infof("imported %d keys and scripts from the wallet dump, rescanning from block %d",
+			len(imported), bs.Height)
+		// Do not block on the rescan, its outcome is logged elsewhere.
+		_ = w.SubmitRescan(&RescanJob{Addrs: imported, BlockStamp: bs})
+	}
+	return len(imported), nil
+}
+
+// importDumpEntry imports the key or script of a wallet dump entry and returns its address.
+func (w *Wallet) importDumpEntry(addrmgrNs walletdb.ReadWriteBucket, e *dumpEntry,
+	bs *waddrmgr.BlockStamp) (util.Address, error) {
+	if e.script != nil {
+		// Scripts go to the same scope as those imported with ImportP2SHRedeemScript.
+		manager, err := w.Manager.FetchScopedKeyManager(waddrmgr.KeyScopeBIP0084)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		ma, err := manager.ImportScript(addrmgrNs, e.script, bs)
+		if err != nil {
+			return nil, err
+		}
+		return ma.Address(), nil
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(e.scope())
+	if err != nil {
+		manager, err = w.Manager.FetchScopedKeyManager(waddrmgr.KeyScopeBIP0044)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+	}
+	ma, err := manager.ImportPrivateKey(addrmgrNs, e.wif, bs)
+	if err != nil {
+		return nil, err
+	}
+	return ma.Address(), nil
+}
+
+// blockStampBefore returns the block stamp of the last block mined well before t, which is where a rescan for keys
+// created at t has to start. The genesis block is returned when t is unknown or the chain server can not tell.
+func (w *Wallet) blockStampBefore(t time.Time) waddrmgr.BlockStamp {
+	genesis := waddrmgr.BlockStamp{
+		Hash:      *w.chainParams.GenesisHash,
+		Timestamp: w.chainParams.GenesisBlock.Header.Timestamp,
+	}
+	if t.IsZero() {
+		return genesis
+	}
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		Error(err)
+		return genesis
+	}
+	_, bestHeight, err := chainClient.GetBestBlock()
+	if err != nil {
+		Error(err)
+		return genesis
+	}
+	t = t.Add(-dumpBlockMargin)
+	blockStamp := func(height int32) (*waddrmgr.BlockStamp, error) {
+		hash, err := chainClient.GetBlockHash(int64(height))
+		if err != nil {
+			return nil, err
+		}
+		header, err := chainClient.GetBlockHeader(hash)
+		if err != nil {
+			return nil, err
+		}
+		return &waddrmgr.BlockStamp{Height: height, Hash: *hash, Timestamp: header.Timestamp}, nil
+	}
+	// Search for the last block with a timestamp before t.
+	low, high := int32(0), bestHeight
+	for low < high {
+		mid := low + (high-low+1)/2
+		stamp, err := blockStamp(mid)
+		if err != nil {
+			Error(err)
+			return genesis
+		}
+		if stamp.Timestamp.Before(t) {
+			low = mid
+		} else {
+			high = mid - 1
+		}
+	}
+	stamp, err := blockStamp(low)
+	if err != nil {
+		Error(err)
+		return genesis
+	}
+	return *stamp
+}
@@ -0,0 +1,302 @@
+package wallet
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	chainhash "github.com/p9c/pod/pkg/chain/hash"
+	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	ec "github.com/p9c/pod/pkg/coding/elliptic"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/util"
+	qu "github.com/p9c/pod/pkg/util/quit"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+)
+
+// TestDumpString ensures labels survive the escaping of a wallet dump.
+func TestDumpString(t *testing.T) {
+	tests := []struct {
+		label   string
+		encoded string
+	}{
+		{"rent", "rent"},
+		{"rent for march", "rent%20for%20march"},
+		{"100% paid", "100%25%20paid"},
+		{"café", "caf%c3%a9"},
+	}
+	for _, test := range tests {
+		encoded := encodeDumpString(test.label)
+		if encoded != test.encoded {
+			t.Errorf("encodeDumpString(%q) = %q, want %q", test.label, encoded, test.encoded)
+		}
+		if decoded := decodeDumpString(encoded); decoded != test.label {
+			t.Errorf("decodeDumpString(%q) = %q, want %q", encoded, decoded, test.label)
+		}
+	}
+	if decoded := decodeDumpString("50%"); decoded != "50%" {
+		t.Errorf("decodeDumpString(%q) = %q, want %q", "50%", decoded, "50%")
+	}
+}
+
+// TestParseDumpLine ensures the lines of a wallet dump in the reference client's format are read as intended.
+func TestParseDumpLine(t *testing.T) {
+	net := &netparams.MainNetParams
+	privKey, err := ec.NewPrivateKey(ec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wif, err := util.NewWIF(privKey, net, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKeyHash := util.Hash160(wif.SerializePubKey())
+	p2pkh, err := util.NewAddressPubKeyHash(pubKeyHash, net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p2wkh, err := util.NewAddressWitnessPubKeyHash(pubKeyHash, net)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		line    string
+		label   string
+		created time.Time
+		addr    util.Address
+		scope   waddrmgr.KeyScope
+	}{
+		{
+			name:    "labelled key",
+			line:    fmt.Sprintf("%s 2020-03-01T12:00:00Z label=rent%%20paid # addr=%s hdkeypath=m/44'/0'/0'/0/1", wif, p2pkh),
+			label:   "rent paid",
+			created: created,
+			addr:    p2pkh,
+			scope:   waddrmgr.KeyScopeBIP0044,
+		},
+		{
+			name:    "change key of a segwit account",
+			line:    fmt.Sprintf("%s 2020-03-01T12:00:00Z change=1 # addr=%s", wif, p2wkh),
+			created: created,
+			addr:    p2wkh,
+			scope:   waddrmgr.KeyScopeBIP0084,
+		},
+		{
+			name:  "key of unknown age without address",
+			line:  fmt.Sprintf("%s 0 reserve=1", wif),
+			addr:  p2pkh,
+			scope: waddrmgr.KeyScopeBIP0044,
+		},
+	}
+	for _, test := range tests {
+		e, err := parseDumpLine(test.line, net)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if e.wif.String() != wif.String() {
+			t.Errorf("%s: unexpected key %s", test.name, e.wif)
+		}
+		if e.label != test.label {
+			t.Errorf("%s: unexpected label -- got %q, want %q", test.name, e.label, test.label)
+		}
+		if !e.created.Equal(test.created) {
+			t.Errorf("%s: unexpected creation time -- got %v, want %v", test.name, e.created, test.created)
+		}
+		if e.addr.EncodeAddress() != test.addr.EncodeAddress() {
+			t.Errorf("%s: unexpected address -- got %s, want %s", test.name, e.addr, test.addr)
+		}
+		if e.scope() != test.scope {
+			t.Errorf("%s: unexpected key scope -- got %v, want %v", test.name, e.scope(), test.scope)
+		}
+	}
+	script := []byte{0x51}
+	e, err := parseDumpLine("51 0 script=1 label=multisig # addr=unused", net)
+	if err != nil {
+		t.Fatalf("script: unexpected error: %v", err)
+	}
+	if e.wif != nil || string(e.script) != string(script) || e.label != "multisig" {
+		t.Errorf("script: unexpected entry %+v", e)
+	}
+	for _, line := range []string{"", "# Wallet dump created by pod", "   # End of dump"} {
+		if e, err := parseDumpLine(line, net); e != nil || err != nil {
+			t.Errorf("comment %q: unexpected entry %+v, error %v", line, e, err)
+		}
+	}
+	for _, line := range []string{wif.String(), "notakey 0 reserve=1", "zz 0 script=1"} {
+		if _, err := parseDumpLine(line, net); err == nil {
+			t.Errorf("malformed line %q: expected an error", line)
+		}
+	}
+	testNet := &netparams.TestNet3Params
+	if _, err := parseDumpLine(fmt.Sprintf("%s 0 reserve=1", wif), testNet); err == nil {
+		t.Errorf("key for another network: expected an error")
+	}
+}
+
+// TestBackup ensures a backup of a running wallet holds its addresses and transactions, and that a backup over the
+// wallet database itself is refused.
+func TestBackup(t *testing.T) {
+	w, _, teardown := testWallet(t)
+	defer teardown()
+	addr := fundTestWallet(t, w, 1e8, 100)
+	dir, err := ioutil.TempDir("", "testbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	if err = w.Backup(dir); err != nil {
+		t.Fatalf("Backup: unexpected error: %v", err)
+	}
+	// The copy is written to a temporary file first, which must be gone.
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name() != WalletDbName {
+		t.Fatalf("backup directory holds %d files, want only %s", len(files), WalletDbName)
+	}
+	db, err := walletdb.Open("bdb", filepath.Join(dir, WalletDbName))
+	if err != nil {
+		t.Fatalf("unable to open backup: %v", err)
+	}
+	defer db.Close()
+	backup, err := Open(db, testPubPass, nil, &netparams.TestNet3Params, 0, nil, qu.T())
+	if err != nil {
+		t.Fatalf("unable to open backup wallet: %v", err)
+	}
+	if ok, err := backup.HaveAddress(addr); err != nil || !ok {
+		t.Errorf("backup does not have address %v (err %v)", addr, err)
+	}
+	var unspent []wtxmgr.Credit
+	err = walletdb.View(db, func(tx walletdb.ReadTx) error {
+		var err error
+		unspent, err = backup.TxStore.UnspentOutputs(tx.ReadBucket(wtxmgrNamespaceKey))
+		return err
+	})
+	if err != nil {
+		t.Fatalf("unable to read backup transactions: %v", err)
+	}
+	if len(unspent) != 1 || unspent[0].Amount != 1e8 {
+		t.Errorf("backup has unspent outputs %+v, want one of %v", unspent, util.Amount(1e8))
+	}
+	// Neither the wallet database nor its directory can be the destination.
+	live := w.db.(interface{ Path() string }).Path()
+	for _, dest := range []string{live, filepath.Dir(live)} {
+		if err := w.Backup(dest); err == nil {
+			t.Errorf("Backup(%s): expected an error backing up over the wallet database", dest)
+		}
+	}
+	if ok, err := w.HaveAddress(addr); err != nil || !ok {
+		t.Errorf("wallet does not have address %v after a refused backup (err %v)", addr, err)
+	}
+}
+
+// TestDumpImportWallet ensures the keys of a wallet dump are imported into another wallet with their labels, once.
+func TestDumpImportWallet(t *testing.T) {
+	w, _, teardown := testWallet(t)
+	defer teardown()
+	var addrs []util.Address
+	for _, scope := range []waddrmgr.KeyScope{waddrmgr.KeyScopeBIP0044, waddrmgr.KeyScopeBIP0084} {
+		addr, err := w.NewAddress(waddrmgr.DefaultAccountNum, scope, true)
+		if err != nil {
+			t.Fatalf("NewAddress: unexpected error: %v", err)
+		}
+		addrs = append(addrs, addr)
+	}
+	change, err := w.NewChangeAddress(waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0044)
+	if err != nil {
+		t.Fatalf("NewChangeAddress: unexpected error: %v", err)
+	}
+	addrs = append(addrs, change)
+	const label = "rent for march"
+	err = walletdb.Update(w.db, func(tx walletdb.ReadWriteTx) error {
+		return w.Manager.SetAddrLabel(tx.ReadWriteBucket(waddrmgrNamespaceKey), addrs[0], label)
+	})
+	if err != nil {
+		t.Fatalf("unable to label address: %v", err)
+	}
+	var dump bytes.Buffer
+	if err = w.DumpWallet(&dump); err != nil {
+		t.Fatalf("DumpWallet: unexpected error: %v", err)
+	}
+	other, _, otherTeardown := testWallet(t)
+	defer otherTeardown()
+	// The rescan handlers only run with a chain server, so the rescan job is taken here.
+	jobs := make(chan *RescanJob, 1)
+	go func() {
+		jobs <- <-other.rescanAddJob
+	}()
+	n, err := other.ImportWallet(bytes.NewReader(dump.Bytes()))
+	if err != nil {
+		t.Fatalf("ImportWallet: unexpected error: %v", err)
+	}
+	if n != len(addrs) {
+		t.Errorf("ImportWallet imported %d keys, want %d", n, len(addrs))
+	}
+	if job := <-jobs; len(job.Addrs) != len(addrs) {
+		t.Errorf("rescan is for %d addresses, want %d", len(job.Addrs), len(addrs))
+	}
+	for _, addr := range addrs {
+		want, err := w.DumpWIFPrivateKey(addr)
+		if err != nil {
+			t.Fatalf("DumpWIFPrivateKey: unexpected error: %v", err)
+		}
+		if got, err := other.DumpWIFPrivateKey(addr); err != nil || got != want {
+			t.Errorf("imported key of %v is %s (err %v), want %s", addr, got, err, want)
+		}
+	}
+	err = walletdb.View(other.db, func(tx walletdb.ReadTx) error {
+		got, err := other.Manager.AddrLabel(tx.ReadBucket(waddrmgrNamespaceKey), addrs[0])
+		if err != nil || got != label {
+			t.Errorf("imported label of %v is %q (err %v), want %q", addrs[0], got, err, label)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err = other.ImportWallet(bytes.NewReader(dump.Bytes())); err != nil || n != 0 {
+		t.Errorf("second ImportWallet imported %d keys (err %v), want none", n, err)
+	}
+}
+
+// TestBlockStampBefore ensures the rescan for the keys of a wallet dump starts at the last block mined well before
+// their creation time, and at the genesis block if the time or the chain is unknown.
+func TestBlockStampBefore(t *testing.T) {
+	w, chainClient, teardown := testWallet(t)
+	defer teardown()
+	tests := []struct {
+		name   string
+		t      time.Time
+		height int32
+	}{
+		{"creation time within the chain", mockBlockTime(100).Add(dumpBlockMargin), 99},
+		{"creation time between blocks", mockBlockTime(100).Add(dumpBlockMargin + time.Minute), 100},
+		{"creation time after the best block", mockBlockTime(500).Add(dumpBlockMargin), chainClient.bestBlock.Height},
+		{"creation time before the first block", mockBlockTime(0), 0},
+	}
+	for _, test := range tests {
+		bs := w.blockStampBefore(test.t)
+		if bs.Height != test.height || bs.Hash != (chainhash.Hash{byte(test.height)}) ||
+			!bs.Timestamp.Equal(mockBlockTime(test.height)) {
+			t.Errorf("%s: rescan starts at block %d %v mined %v, want block %d", test.name, bs.Height, bs.Hash,
+				bs.Timestamp, test.height)
+		}
+	}
+	genesis := *w.chainParams.GenesisHash
+	if bs := w.blockStampBefore(time.Time{}); bs.Height != 0 || bs.Hash != genesis {
+		t.Errorf("unknown creation time: rescan starts at block %d %v, want the genesis block", bs.Height, bs.Hash)
+	}
+	w.chainClient = nil
+	if bs := w.blockStampBefore(mockBlockTime(100)); bs.Height != 0 || bs.Hash != genesis {
+		t.Errorf("no chain server: rescan starts at block %d %v, want the genesis block", bs.Height, bs.Hash)
+	}
+}
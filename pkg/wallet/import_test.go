@@ -0,0 +1,253 @@
+package wallet
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/util"
+	"github.com/p9c/pod/pkg/util/hdkeychain"
+	qu "github.com/p9c/pod/pkg/util/quit"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+	"github.com/p9c/pod/pkg/wallet/chain"
+)
+
+// testAccountKey returns the extended public key of the first account of the passed key scope of a new seed for the
+// test network.
+func testAccountKey(t *testing.T, scope waddrmgr.KeyScope) *hdkeychain.ExtendedKey {
+	seed, err := hdkeychain.GenerateSeed(hdkeychain.RecommendedSeedLen)
+	if err != nil {
+		t.Fatalf("unable to generate seed: %v", err)
+	}
+	key, err := hdkeychain.NewMaster(seed, &netparams.TestNet3Params)
+	if err != nil {
+		t.Fatalf("unable to create master key: %v", err)
+	}
+	for _, i := range []uint32{scope.Purpose, scope.Coin, 0} {
+		if key, err = key.Child(i + hdkeychain.HardenedKeyStart); err != nil {
+			t.Fatalf("unable to derive account key: %v", err)
+		}
+	}
+	if key, err = key.Neuter(); err != nil {
+		t.Fatalf("unable to neuter account key: %v", err)
+	}
+	return key
+}
+
+// accountKeyAddress returns the address at branch/index below an account key.
+func accountKeyAddress(t *testing.T, accountKey *hdkeychain.ExtendedKey, branch, index uint32) util.Address {
+	branchKey, err := accountKey.Child(branch)
+	if err != nil {
+		t.Fatalf("unable to derive branch key: %v", err)
+	}
+	key, err := branchKey.Child(index)
+	if err != nil {
+		t.Fatalf("unable to derive address key: %v", err)
+	}
+	addr, err := key.Address(&netparams.TestNet3Params)
+	if err != nil {
+		t.Fatalf("unable to get address: %v", err)
+	}
+	return addr
+}
+
+// TestImportAccount ensures an account imported from an extended public key hands out its addresses from the first
+// one, and after a rescan from the one after the last address found on the chain.
+func TestImportAccount(t *testing.T) {
+	w, chainClient, teardown := testWallet(t)
+	defer teardown()
+	scope := waddrmgr.KeyScopeBIP0044
+	accountKey := testAccountKey(t, scope)
+	props, err := w.ImportAccount(scope, "xpub", accountKey, nil, false)
+	if err != nil {
+		t.Fatalf("ImportAccount: unexpected error: %v", err)
+	}
+	if !props.IsWatchOnly || props.ExternalKeyCount != 0 || props.InternalKeyCount != 0 {
+		t.Errorf("imported account has properties %+v, want a watching-only account without addresses", props)
+	}
+	addr, err := w.NewAddress(props.AccountNumber, scope, true)
+	if err != nil {
+		t.Fatalf("NewAddress: unexpected error: %v", err)
+	}
+	if want := accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 0); addr.EncodeAddress() != want.EncodeAddress() {
+		t.Errorf("first address of the imported account is %v, want %v", addr, want)
+	}
+	// The chain holds the fourth external address of the account imported next, which its rescan must find.
+	chainClient.filterBlocks = func(req *chain.FilterBlocksRequest) (*chain.FilterBlocksResponse, error) {
+		want := accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 3)
+		index := waddrmgr.ScopedIndex{Scope: scope, Index: 3}
+		if addr := req.ExternalAddrs[index]; addr == nil || addr.EncodeAddress() != want.EncodeAddress() {
+			return nil, nil
+		}
+		return &chain.FilterBlocksResponse{
+			BlockMeta:          req.Blocks[0],
+			FoundExternalAddrs: map[waddrmgr.KeyScope]map[uint32]struct{}{scope: {3: {}}},
+		}, nil
+	}
+	accountKey = testAccountKey(t, scope)
+	if props, err = w.ImportAccount(scope, "rescanned", accountKey, nil, true); err != nil {
+		t.Fatalf("ImportAccount: unexpected error: %v", err)
+	}
+	// The scan runs in the background, and the rescan handlers only run with a chain server, so the rescan job it
+	// submits when done is taken here.
+	if job := <-w.rescanAddJob; len(job.Addrs) != 4+2*int(w.importLookahead()) {
+		t.Errorf("rescan is for %d addresses, want the 4 found and the lookahead windows", len(job.Addrs))
+	}
+	if props, err = w.AccountProperties(scope, props.AccountNumber); err != nil {
+		t.Fatalf("AccountProperties: unexpected error: %v", err)
+	}
+	if props.ExternalKeyCount != 4 || props.InternalKeyCount != 0 {
+		t.Errorf("rescanned account has %d external and %d internal addresses, want 4 and 0",
+			props.ExternalKeyCount, props.InternalKeyCount)
+	}
+	addr, err = w.NewAddress(props.AccountNumber, scope, true)
+	if err != nil {
+		t.Fatalf("NewAddress: unexpected error: %v", err)
+	}
+	if want := accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 4); addr.EncodeAddress() != want.EncodeAddress() {
+		t.Errorf("first new address of the rescanned account is %v, want %v", addr, want)
+	}
+}
+
+// TestImportAccountLookahead ensures a payment to an address of an imported account that the wallet has not issued is
+// credited to the account when it is in the lookahead window, and moves the window past it.
+func TestImportAccountLookahead(t *testing.T) {
+	w, _, teardown := testWallet(t)
+	defer teardown()
+	scope := waddrmgr.KeyScopeBIP0044
+	accountKey := testAccountKey(t, scope)
+	props, err := w.ImportAccount(scope, "xpub", accountKey, nil, false)
+	if err != nil {
+		t.Fatalf("ImportAccount: unexpected error: %v", err)
+	}
+	lookahead := w.importLookahead()
+	fundTestAddress(t, w, accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 5), 1e8, 100)
+	balances, err := w.CalculateAccountBalances(props.AccountNumber, 1)
+	if err != nil {
+		t.Fatalf("CalculateAccountBalances: unexpected error: %v", err)
+	}
+	if balances.Total != 1e8 {
+		t.Errorf("imported account holds %v, want the payment to its lookahead window", balances.Total)
+	}
+	if props, err = w.AccountProperties(scope, props.AccountNumber); err != nil {
+		t.Fatalf("AccountProperties: unexpected error: %v", err)
+	}
+	if props.ExternalKeyCount != 6 || props.InternalKeyCount != 0 {
+		t.Errorf("account has %d external and %d internal addresses, want 6 and 0",
+			props.ExternalKeyCount, props.InternalKeyCount)
+	}
+	// The window now reaches the lookahead past the paid address, and a payment there is credited too.
+	fundTestAddress(t, w, accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 5+lookahead), 1e8, 101)
+	if balances, err = w.CalculateAccountBalances(props.AccountNumber, 1); err != nil {
+		t.Fatalf("CalculateAccountBalances: unexpected error: %v", err)
+	}
+	if balances.Total != 2e8 {
+		t.Errorf("imported account holds %v, want both payments", balances.Total)
+	}
+	// A payment past the window is not the wallet's.
+	fundTestAddress(t, w, accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 6+2*lookahead), 1e8, 102)
+	if balances, err = w.CalculateAccountBalances(props.AccountNumber, 1); err != nil {
+		t.Fatalf("CalculateAccountBalances: unexpected error: %v", err)
+	}
+	if balances.Total != 2e8 {
+		t.Errorf("imported account holds %v, want the payment past the window ignored", balances.Total)
+	}
+	addr, err := w.NewAddress(props.AccountNumber, scope, true)
+	if err != nil {
+		t.Fatalf("NewAddress: unexpected error: %v", err)
+	}
+	if want := accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 6+lookahead); addr.EncodeAddress() != want.EncodeAddress() {
+		t.Errorf("first new address of the account is %v, want %v", addr, want)
+	}
+}
+
+// TestCreateWatchingOnly ensures a wallet created from the extended public key of an account hands out the addresses
+// of its default account from the first one.
+func TestCreateWatchingOnly(t *testing.T) {
+	dir, err := ioutil.TempDir("", "testwatchingonly")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	accountKey := testAccountKey(t, waddrmgr.KeyScopeBIP0044)
+	loader := NewLoader(&netparams.TestNet3Params, filepath.Join(dir, WalletDbName), 250)
+	w, err := loader.CreateNewWatchingOnlyWallet(testPubPass, accountKey.String(), time.Now(), false, nil, qu.T())
+	if err != nil {
+		t.Fatalf("unable to create wallet: %v", err)
+	}
+	defer func() {
+		w.Stop()
+		w.WaitForShutdown()
+		w.db.Close()
+	}()
+	addr, err := w.NewAddress(waddrmgr.DefaultAccountNum, waddrmgr.KeyScopeBIP0044, true)
+	if err != nil {
+		t.Fatalf("NewAddress: unexpected error: %v", err)
+	}
+	if want := accountKeyAddress(t, accountKey, waddrmgr.ExternalBranch, 0); addr.EncodeAddress() != want.EncodeAddress() {
+		t.Errorf("first address of the watching-only wallet is %v, want %v", addr, want)
+	}
+}
+
+// TestCreateFundedPsbtImportedAccount ensures a packet can be funded from an account imported from the extended public
+// key of a BIP0084 account, which is found by its name outside BIP0044, spending only its outputs and paying the change
+// back to it.
+func TestCreateFundedPsbtImportedAccount(t *testing.T) {
+	w, _, teardown := testWallet(t)
+	defer teardown()
+	props, err := w.ImportAccount(waddrmgr.KeyScopeBIP0084, "zpub", testAccountKey(t, waddrmgr.KeyScopeBIP0084), nil,
+		false)
+	if err != nil {
+		t.Fatalf("ImportAccount: unexpected error: %v", err)
+	}
+	scope, account, err := w.LookupAccount("zpub")
+	if err != nil {
+		t.Fatalf("LookupAccount: unexpected error: %v", err)
+	}
+	if scope != waddrmgr.KeyScopeBIP0084 || account != props.AccountNumber {
+		t.Fatalf("LookupAccount: found account %d in scope %v, want %d in %v", account, scope, props.AccountNumber,
+			waddrmgr.KeyScopeBIP0084)
+	}
+	// The default account has funds too, which must not be spent.
+	fundTestWallet(t, w, 1e8, 100)
+	addr, err := w.NewAddress(account, scope, true)
+	if err != nil {
+		t.Fatalf("NewAddress: unexpected error: %v", err)
+	}
+	fundTestAddress(t, w, addr, 2e8, 101)
+	p, _, changeIndex, err := w.CreateFundedPsbt(nil, testPsbtOutputs(t, 15e7), 0, scope, account, 1,
+		txrules.DefaultRelayFeePerKb, false, nil)
+	if err != nil {
+		t.Fatalf("CreateFundedPsbt: unexpected error: %v", err)
+	}
+	if len(p.UnsignedTx.TxIn) != 1 || p.Inputs[0].WitnessUtxo == nil {
+		t.Fatalf("packet does not spend the witness output of the imported account alone")
+	}
+	if _, addrs, _, _ := txscript.ExtractPkScriptAddrs(p.Inputs[0].WitnessUtxo.PkScript, w.chainParams); len(addrs) != 1 ||
+		addrs[0].EncodeAddress() != addr.EncodeAddress() {
+		t.Errorf("packet spends an output not paying to the imported account")
+	}
+	if changeIndex < 0 {
+		t.Fatalf("packet has no change")
+	}
+	_, changeAddrs, _, _ := txscript.ExtractPkScriptAddrs(p.UnsignedTx.TxOut[changeIndex].PkScript, w.chainParams)
+	if len(changeAddrs) != 1 {
+		t.Fatalf("change output does not pay to one address")
+	}
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		scopedMgr, changeAccount, err := w.Manager.AddrAccount(dbtx.ReadBucket(waddrmgrNamespaceKey), changeAddrs[0])
+		if err != nil || scopedMgr.Scope() != scope || changeAccount != account {
+			t.Errorf("change does not go to the imported account (err %v)", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
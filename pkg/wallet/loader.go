@@ -56,6 +56,38 @@ func (ld *Loader) CreateNewWallet(
 	noStart bool,
 	podConfig *pod.Config,
 	quit qu.C,
+) (*Wallet, error) {
+	return ld.createNewWallet(
+		pubPassphrase, func(db walletdb.DB) error {
+			return Create(db, pubPassphrase, privPassphrase, seed, ld.ChainParams, bday)
+		}, noStart, podConfig, quit,
+	)
+}
+
+// CreateNewWatchingOnlyWallet creates a new watching-only wallet from the extended public key of an account (xpub, ypub
+// or zpub) using the provided public passphrase. The wallet holds no private keys and has no private passphrase.
+func (ld *Loader) CreateNewWatchingOnlyWallet(
+	pubPassphrase []byte,
+	accountPubKey string,
+	bday time.Time,
+	noStart bool,
+	podConfig *pod.Config,
+	quit qu.C,
+) (*Wallet, error) {
+	return ld.createNewWallet(
+		pubPassphrase, func(db walletdb.DB) error {
+			return CreateWatchingOnly(db, pubPassphrase, accountPubKey, ld.ChainParams, bday)
+		}, noStart, podConfig, quit,
+	)
+}
+
+// createNewWallet creates the wallet database, initializes it with the passed function and opens the new wallet.
+func (ld *Loader) createNewWallet(
+	pubPassphrase []byte,
+	create func(db walletdb.DB) error,
+	noStart bool,
+	podConfig *pod.Config,
+	quit qu.C,
 ) (*Wallet, error) {
 	ld.Mutex.Lock()
 	defer ld.Mutex.Unlock()
@@ -84,7 +116,7 @@ func (ld *Loader) CreateNewWallet(
 		return nil, err
 	}
 	// Initialize the newly created database for the wallet before opening.
-	err = Create(db, pubPassphrase, privPassphrase, seed, ld.ChainParams, bday)
+	err = create(db)
 	if err != nil {
 		Error(err)
 		return nil, err
@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"sync"
+
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/util"
+	"github.com/p9c/pod/pkg/util/hdkeychain"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+)
+
+// lookaheadBranch identifies a branch of an account of a key scope.
+type lookaheadBranch struct {
+	scope   waddrmgr.KeyScope
+	account uint32
+	branch  uint32
+}
+
+// lookaheadAddr is a watched address past the last issued address of a branch.
+type lookaheadAddr struct {
+	lookaheadBranch
+	index uint32
+}
+
+// lookaheadWindow holds the addresses watched past the last issued addresses of the branches of the watching-only
+// accounts. The history of such an account is not known to the wallet, and its addresses may be handed out by another
+// wallet, so payments to them must be seen without issuing them, as issuing them would make the wallet skip them when it
+// hands out addresses itself. The window is only held in memory and is derived again when the wallet syncs.
+type lookaheadWindow struct {
+	sync.Mutex
+	addrs    map[string]lookaheadAddr
+	watched  []util.Address
+	horizons map[lookaheadBranch]uint32
+}
+
+// importLookahead returns the number of addresses watched past the last issued address on each branch of a
+// watching-only account.
+func (w *Wallet) importLookahead() uint32 {
+	if w.recoveryWindow > 0 {
+		return w.recoveryWindow
+	}
+	return defaultImportLookahead
+}
+
+// watchAccountLookahead derives the addresses of the lookahead window of an account that are not watched yet, and
+// returns them. Nothing is watched for an account the wallet has the private keys of.
+func (w *Wallet) watchAccountLookahead(
+	ns walletdb.ReadBucket, manager *waddrmgr.ScopedKeyManager, account uint32,
+) ([]util.Address, error) {
+	if account == waddrmgr.ImportedAddrAccount {
+		return nil, nil
+	}
+	props, err := manager.AccountProperties(ns, account)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if !props.IsWatchOnly {
+		return nil, nil
+	}
+	lookahead := w.importLookahead()
+	w.lookahead.Lock()
+	defer w.lookahead.Unlock()
+	var addrs []util.Address
+	issued := map[uint32]uint32{
+		waddrmgr.ExternalBranch: props.ExternalKeyCount,
+		waddrmgr.InternalBranch: props.InternalKeyCount,
+	}
+	for branch, count := range issued {
+		b := lookaheadBranch{scope: manager.Scope(), account: account, branch: branch}
+		index := w.lookahead.horizons[b]
+		if index < count {
+			index = count
+		}
+		for ; index < count+lookahead; index++ {
+			kp := waddrmgr.DerivationPath{Account: account, Branch: branch, Index: index}
+			maddr, err := manager.DeriveFromKeyPath(ns, kp)
+			switch {
+			case err == hdkeychain.ErrInvalidChild:
+				// The address manager skips invalid children as well, so there is no address to watch.
+				continue
+			case err != nil:
+				return nil, err
+			}
+			addr := maddr.Address()
+			w.lookahead.addrs[addr.EncodeAddress()] = lookaheadAddr{lookaheadBranch: b, index: index}
+			w.lookahead.watched = append(w.lookahead.watched, addr)
+			addrs = append(addrs, addr)
+		}
+		w.lookahead.horizons[b] = index
+	}
+	return addrs, nil
+}
+
+// watchLookahead derives the addresses of the lookahead windows of all watching-only accounts that are not watched yet,
+// and returns all the watched addresses.
+func (w *Wallet) watchLookahead(ns walletdb.ReadBucket) ([]util.Address, error) {
+	for _, manager := range w.Manager.ActiveScopedKeyManagers() {
+		err := manager.ForEachAccount(
+			ns, func(account uint32) error {
+				_, err := w.watchAccountLookahead(ns, manager, account)
+				return err
+			},
+		)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+	}
+	w.lookahead.Lock()
+	defer w.lookahead.Unlock()
+	return append([]util.Address(nil), w.lookahead.watched...), nil
+}
+
+// issueLookaheadAddr issues the addresses of a branch of a watching-only account up to the passed address if it is in
+// the lookahead window of the branch, so a payment to it is credited, and watches the window past it. It returns whether
+// the address was issued.
+func (w *Wallet) issueLookaheadAddr(ns walletdb.ReadWriteBucket, addr util.Address) (bool, error) {
+	w.lookahead.Lock()
+	la, ok := w.lookahead.addrs[addr.EncodeAddress()]
+	w.lookahead.Unlock()
+	if !ok {
+		return false, nil
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(la.scope)
+	if err != nil {
+		Error(err)
+		return false, err
+	}
+	if la.branch == waddrmgr.InternalBranch {
+		err = manager.ExtendInternalAddresses(ns, la.account, la.index)
+	} else {
+		err = manager.ExtendExternalAddresses(ns, la.account, la.index)
+	}
+	if err != nil {
+		Error(err)
+		return false, err
+	}
+	addrs, err := w.watchAccountLookahead(ns, manager, la.account)
+	if err != nil {
+		Error(err)
+		return false, err
+	}
+	// This runs while a notification of the chain server is handled, so the server is not waited for.
+	if chainClient := w.ChainClient(); chainClient != nil && len(addrs) > 0 {
+		go func() {
+			if err := chainClient.NotifyReceived(addrs); err != nil {
+				Error(err)
+			}
+		}()
+	}
+	return true, nil
+}
@@ -0,0 +1,325 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+
+	txauthor "github.com/p9c/pod/pkg/chain/tx/author"
+	wtxmgr "github.com/p9c/pod/pkg/chain/tx/mgr"
+	"github.com/p9c/pod/pkg/chain/tx/psbt"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/db/walletdb"
+	"github.com/p9c/pod/pkg/util"
+	h "github.com/p9c/pod/pkg/util/helpers"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+)
+
+// ErrSighashMismatch is returned by ProcessPsbt when an input of the packet asks for a different sighash type than the
+// one to sign with.
+var ErrSighashMismatch = errors.New("sighash type does not match the one stored in the PSBT")
+
+// CreateFundedPsbt creates a packet for an unsigned transaction paying to the passed outputs. The passed inputs are
+// always spent and must spend outputs known to the wallet, and if they do not pay for the outputs and the fee at
+// feeSatPerKb further confirmed outputs of the account of the key scope are added as chosen by the coin selector, or by
+// the one of the wallet configuration if it is nil, with any change going to a new change address of the account, or of
+// the default account when spending from the imported account. The change of a scope whose change addresses are not
+// witness addresses goes to the account of the same number in the witness scope, as when sending. The packet holds the
+// outputs the inputs spend and the redeem scripts the wallet knows, so it can be signed by a wallet that is offline.
+// When lockInputs is set the inputs are locked so they are not spent again before the packet is broadcast. The packet,
+// the fee and the index of the change output, or -1 if there is none, are returned. The wallet need not be unlocked.
+func (w *Wallet) CreateFundedPsbt(inputs []*wire.TxIn, outputs []*wire.TxOut, lockTime uint32,
+	scope waddrmgr.KeyScope, account uint32, minconf int32, feeSatPerKb util.Amount, lockInputs bool,
+	coinSelector CoinSelector) (p *psbt.Packet, fee util.Amount, changeIndex int, err error) {
+	chainClient, err := w.requireChainClient()
+	if err != nil {
+		Error(err)
+		return
+	}
+	manager, err := w.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		Error(err)
+		return
+	}
+	var witnessChange bool
+	for _, s := range w.Manager.ScopesForInternalAddrTypes(waddrmgr.WitnessPubKey) {
+		witnessChange = witnessChange || s == scope
+	}
+	// Ensure the outputs to be created adhere to the network's consensus rules.
+	for _, output := range outputs {
+		if err = txrules.CheckOutput(output, feeSatPerKb); err != nil {
+			return
+		}
+	}
+	if coinSelector == nil {
+		coinSelector = w.defaultCoinSelector()
+	}
+	var tx *txauthor.AuthoredTx
+	err = walletdb.Update(w.db, func(dbtx walletdb.ReadWriteTx) error {
+		addrmgrNs := dbtx.ReadWriteBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		chosen := make(map[wire.OutPoint]struct{}, len(inputs))
+		inputValues := make([]util.Amount, len(inputs))
+		prevScripts := make([][]byte, len(inputs))
+		preset := make([]wtxmgr.Credit, len(inputs))
+		for i, txIn := range inputs {
+			prevTxOut, _, err := w.fetchPrevOutput(txmgrNs, txIn.PreviousOutPoint)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			chosen[txIn.PreviousOutPoint] = struct{}{}
+			inputValues[i] = util.Amount(prevTxOut.Value)
+			prevScripts[i] = prevTxOut.PkScript
+			preset[i] = wtxmgr.Credit{
+				OutPoint: txIn.PreviousOutPoint,
+				Amount:   inputValues[i],
+				PkScript: prevTxOut.PkScript,
+			}
+		}
+		bs, err := chainClient.BlockStamp()
+		if err != nil {
+			Error(err)
+			return err
+		}
+		credits, err := w.findEligibleOutputs(dbtx, &scope, account, minconf, bs)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		// The chosen inputs are spent anyway, so they must not be selected a second time.
+		eligible := credits[:0]
+		for _, credit := range credits {
+			if _, ok := chosen[credit.OutPoint]; !ok {
+				eligible = append(eligible, credit)
+			}
+		}
+		var selected []wtxmgr.Credit
+		if selectionExcess(outputs, feeSatPerKb, preset) < 0 {
+			selected, err = coinSelector.SelectCoins(outputs, feeSatPerKb, preset, eligible, bs.Height)
+			if err != nil {
+				Error(err)
+				return err
+			}
+		}
+		inputSource := makePresetInputSource(inputs, inputValues, prevScripts,
+			makeSelectedInputSource(selected, w.InputSequence()))
+		changeSource := func() ([]byte, error) {
+			// As when sending, change for a spend from the imported account goes to the default account.
+			changeAccount := account
+			if changeAccount == waddrmgr.ImportedAddrAccount {
+				changeAccount = waddrmgr.DefaultAccountNum
+			}
+			// The fee estimate needs change no larger than a P2WPKH output, so a scope whose change is not a witness
+			// output, like BIP0044, has its change sent to the witness scope as when sending.
+			if !witnessChange {
+				changeAddr, err := w.newChangeAddress(addrmgrNs, changeAccount)
+				if err != nil {
+					Error(err)
+					return nil, err
+				}
+				return txscript.PayToAddrScript(changeAddr)
+			}
+			changeAddrs, err := manager.NextInternalAddresses(addrmgrNs, changeAccount, 1)
+			if err != nil {
+				Error(err)
+				return nil, err
+			}
+			return txscript.PayToAddrScript(changeAddrs[0].Address())
+		}
+		tx, err = txauthor.NewUnsignedTransaction(outputs, feeSatPerKb, inputSource, changeSource)
+		if err != nil {
+			Error(err)
+			return err
+		}
+		if tx.ChangeIndex >= 0 {
+			tx.RandomizeChangePosition()
+		}
+		tx.Tx.LockTime = lockTime
+		if p, err = psbt.NewFromUnsignedTx(tx.Tx); err != nil {
+			Error(err)
+			return err
+		}
+		return w.updatePsbt(addrmgrNs, txmgrNs, p)
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	if lockInputs {
+		for _, txIn := range tx.Tx.TxIn {
+			w.LockOutpoint(txIn.PreviousOutPoint)
+		}
+	}
+	return p, tx.TotalInput - h.SumOutputValues(tx.Tx.TxOut), tx.ChangeIndex, nil
+}
+
+// ProcessPsbt adds what the wallet knows about the inputs of a packet that are not yet finalized, which is the outputs
+// they spend and the redeem scripts of the wallet's pay-to-script-hash addresses, and if sign is set signs them with
+// every key of the wallet their scripts need, using hashType unless an input asks for another sighash type, in which
+// case ErrSighashMismatch is returned. The inputs that have enough signatures are then finalized, and whether the
+// whole packet is finalized is returned. The wallet must be unlocked to sign.
+func (w *Wallet) ProcessPsbt(p *psbt.Packet, sign bool, hashType txscript.SigHashType) (complete bool, err error) {
+	err = walletdb.View(w.db, func(dbtx walletdb.ReadTx) error {
+		addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
+		txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
+		if err := w.updatePsbt(addrmgrNs, txmgrNs, p); err != nil {
+			Error(err)
+			return err
+		}
+		if !sign {
+			return nil
+		}
+		for i := range p.Inputs {
+			in := &p.Inputs[i]
+			if in.IsFinalized() {
+				continue
+			}
+			switch in.SighashType {
+			case 0:
+				if hashType != txscript.SigHashAll {
+					in.SighashType = hashType
+				}
+			case hashType:
+			default:
+				return ErrSighashMismatch
+			}
+			if err := w.signPsbtInput(addrmgrNs, p, i); err != nil {
+				Error(err)
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		Error(err)
+		return
+	}
+	return p.MaybeFinalizeAll(), nil
+}
+
+// updatePsbt adds the outputs spent by the inputs of a packet and the redeem scripts of the pay-to-script-hash outputs
+// they spend where the wallet knows them and the packet does not have them. Inputs spending outputs the wallet does not
+// know are left as they are.
+func (w *Wallet) updatePsbt(addrmgrNs walletdb.ReadBucket, txmgrNs walletdb.ReadBucket, p *psbt.Packet) error {
+	for i, txIn := range p.UnsignedTx.TxIn {
+		in := &p.Inputs[i]
+		if in.IsFinalized() {
+			continue
+		}
+		prevTxOut, prevTx, err := w.fetchPrevOutput(txmgrNs, txIn.PreviousOutPoint)
+		if err != nil {
+			// The packet may well spend outputs of other wallets.
+			Debug(err)
+			continue
+		}
+		program := prevTxOut.PkScript
+		if txscript.IsPayToScriptHash(program) {
+			if in.RedeemScript == nil {
+				in.RedeemScript = w.redeemScript(addrmgrNs, program)
+			}
+			program = in.RedeemScript
+		}
+		// Witness programs commit to the amount they spend, so the output is enough for them, while the whole
+		// transaction is needed for other outputs so a signer can check the amount.
+		switch {
+		case program != nil && txscript.IsWitnessProgram(program):
+			if in.WitnessUtxo == nil {
+				in.WitnessUtxo = prevTxOut
+			}
+		case in.NonWitnessUtxo == nil:
+			in.NonWitnessUtxo = prevTx
+		}
+	}
+	return nil
+}
+
+// redeemScript returns the redeem script of a pay-to-script-hash output script paying to an address of the wallet,
+// which is the script of a script address, or the witness program of an address that nests one, or nil if the wallet
+// does not have it or its scripts are locked.
+func (w *Wallet) redeemScript(addrmgrNs walletdb.ReadBucket, pkScript []byte) []byte {
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(pkScript, w.chainParams)
+	if err != nil || len(addrs) != 1 {
+		return nil
+	}
+	ma, err := w.Manager.Address(addrmgrNs, addrs[0])
+	if err != nil {
+		return nil
+	}
+	switch a := ma.(type) {
+	case waddrmgr.ManagedScriptAddress:
+		script, err := a.Script()
+		if err != nil {
+			Debug(err)
+			return nil
+		}
+		return script
+	case waddrmgr.ManagedPubKeyAddress:
+		if a.AddrType() != waddrmgr.NestedWitnessPubKey {
+			return nil
+		}
+		witnessAddr, err := util.NewAddressWitnessPubKeyHash(
+			util.Hash160(a.PubKey().SerializeCompressed()), w.chainParams)
+		if err != nil {
+			return nil
+		}
+		program, err := txscript.PayToAddrScript(witnessAddr)
+		if err != nil {
+			return nil
+		}
+		return program
+	}
+	return nil
+}
+
+// signPsbtInput signs input idx of a packet with every key of the wallet its signing script needs. Inputs the packet
+// lacks the information to sign are skipped.
+func (w *Wallet) signPsbtInput(addrmgrNs walletdb.ReadBucket, p *psbt.Packet, idx int) error {
+	script, _, err := p.SigningScript(idx)
+	if err != nil {
+		Debugf("not signing input %d: %v", idx, err)
+		return nil
+	}
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(script, w.chainParams)
+	if err != nil {
+		Debugf("not signing input %d: %v", idx, err)
+		return nil
+	}
+	for _, addr := range addrs {
+		ma, err := w.Manager.Address(addrmgrNs, addr)
+		if err != nil {
+			continue
+		}
+		mpka, ok := ma.(waddrmgr.ManagedPubKeyAddress)
+		if !ok {
+			continue
+		}
+		privKey, err := mpka.PrivKey()
+		if err != nil {
+			return err
+		}
+		// The public key of a script must be given in the form the script has it.
+		compressed := mpka.Compressed()
+		if pka, ok := addr.(*util.AddressPubKey); ok {
+			compressed = pka.Format() == util.PKFCompressed
+		}
+		if err = p.SignInput(idx, privKey, compressed); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchPrevOutput returns the output spent by an outpoint and the transaction holding it, which must be in the
+// wallet.
+func (w *Wallet) fetchPrevOutput(txmgrNs walletdb.ReadBucket, op wire.OutPoint) (*wire.TxOut, *wire.MsgTx, error) {
+	details, err := w.TxStore.TxDetails(txmgrNs, &op.Hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if details == nil || int(op.Index) >= len(details.MsgTx.TxOut) {
+		return nil, nil, fmt.Errorf("output %v is not known to the wallet", op)
+	}
+	return details.MsgTx.TxOut[op.Index], &details.MsgTx, nil
+}
@@ -0,0 +1,149 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/p9c/pod/pkg/chain/config/netparams"
+	"github.com/p9c/pod/pkg/chain/tx/psbt"
+	txrules "github.com/p9c/pod/pkg/chain/tx/rules"
+	txscript "github.com/p9c/pod/pkg/chain/tx/script"
+	"github.com/p9c/pod/pkg/chain/wire"
+	"github.com/p9c/pod/pkg/util"
+	h "github.com/p9c/pod/pkg/util/helpers"
+	waddrmgr "github.com/p9c/pod/pkg/wallet/addrmgr"
+)
+
+// testPsbtOutputs returns outputs paying amount to an address that is not in the wallet.
+func testPsbtOutputs(t *testing.T, amount util.Amount) []*wire.TxOut {
+	addr, err := util.NewAddressPubKeyHash(make([]byte, 20), &netparams.TestNet3Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*wire.TxOut{wire.NewTxOut(int64(amount), pkScript)}
+}
+
+// TestCreateFundedPsbt ensures a packet funded by the wallet spends the inputs it is given and further outputs of the
+// account when they are not enough, pays its change to the wallet, carries the outputs its inputs spend and locks its
+// inputs when asked to.
+func TestCreateFundedPsbt(t *testing.T) {
+	w, _, teardown := testWallet(t)
+	defer teardown()
+	fundTestWallet(t, w, 1e8, 100)
+	fundTestWallet(t, w, 1e8, 101)
+	outputs := testPsbtOutputs(t, 15e7)
+	p, fee, changeIndex, err := w.CreateFundedPsbt(nil, outputs, 0, waddrmgr.KeyScopeBIP0044,
+		waddrmgr.DefaultAccountNum, 1, txrules.DefaultRelayFeePerKb, true, nil)
+	if err != nil {
+		t.Fatalf("CreateFundedPsbt: unexpected err %v", err)
+	}
+	tx := p.UnsignedTx
+	if len(tx.TxIn) != 2 {
+		t.Fatalf("packet spends %d inputs, want 2", len(tx.TxIn))
+	}
+	if changeIndex < 0 || changeIndex >= len(tx.TxOut) {
+		t.Fatalf("change index %d is not an output of the packet", changeIndex)
+	}
+	if fee <= 0 || util.Amount(2e8)-h.SumOutputValues(tx.TxOut) != fee {
+		t.Errorf("packet pays fee %v, want the inputs less the outputs", fee)
+	}
+	for i, txIn := range tx.TxIn {
+		in := p.Inputs[i]
+		if in.NonWitnessUtxo == nil || in.NonWitnessUtxo.TxHash() != txIn.PreviousOutPoint.Hash {
+			t.Errorf("input %d does not hold the transaction it spends", i)
+		}
+		if !w.LockedOutpoint(txIn.PreviousOutPoint) {
+			t.Errorf("input %d was not locked", i)
+		}
+	}
+	// A given input that pays for the outputs is spent alone, and no further input is added.
+	w.ResetLockedOutpoints()
+	inputs := []*wire.TxIn{wire.NewTxIn(&tx.TxIn[0].PreviousOutPoint, nil, nil)}
+	p, _, _, err = w.CreateFundedPsbt(inputs, testPsbtOutputs(t, 5e7), 0, waddrmgr.KeyScopeBIP0044,
+		waddrmgr.DefaultAccountNum, 1, txrules.DefaultRelayFeePerKb, false, nil)
+	if err != nil {
+		t.Fatalf("CreateFundedPsbt: unexpected err %v", err)
+	}
+	if len(p.UnsignedTx.TxIn) != 1 || p.UnsignedTx.TxIn[0].PreviousOutPoint != inputs[0].PreviousOutPoint {
+		t.Errorf("packet does not spend only the given input")
+	}
+	if w.LockedOutpoint(inputs[0].PreviousOutPoint) {
+		t.Errorf("input was locked")
+	}
+	// The account can not pay for more than it holds.
+	_, _, _, err = w.CreateFundedPsbt(nil, testPsbtOutputs(t, 3e8), 0, waddrmgr.KeyScopeBIP0044,
+		waddrmgr.DefaultAccountNum, 1, txrules.DefaultRelayFeePerKb, false, nil)
+	if err == nil {
+		t.Errorf("CreateFundedPsbt: expected an error paying more than the account holds")
+	}
+}
+
+// TestProcessPsbt ensures the wallet fills in the outputs spent by a bare packet, signs and finalizes it so the
+// extracted transaction is valid, refuses to sign with a sighash type other than the one an input asks for, and can
+// not sign while locked.
+func TestProcessPsbt(t *testing.T) {
+	w, _, teardown := testWallet(t)
+	defer teardown()
+	fundTestWallet(t, w, 1e8, 100)
+	funded, _, _, err := w.CreateFundedPsbt(nil, testPsbtOutputs(t, 5e7), 0, waddrmgr.KeyScopeBIP0044,
+		waddrmgr.DefaultAccountNum, 1, txrules.DefaultRelayFeePerKb, false, nil)
+	if err != nil {
+		t.Fatalf("CreateFundedPsbt: unexpected err %v", err)
+	}
+	// newPacket returns a packet for the funded transaction without what the wallet knows about its inputs.
+	newPacket := func() *psbt.Packet {
+		p, err := psbt.NewFromUnsignedTx(funded.UnsignedTx.Copy())
+		if err != nil {
+			t.Fatalf("NewFromUnsignedTx: unexpected err %v", err)
+		}
+		return p
+	}
+	p := newPacket()
+	complete, err := w.ProcessPsbt(p, false, txscript.SigHashAll)
+	if err != nil {
+		t.Fatalf("ProcessPsbt: unexpected err %v", err)
+	}
+	if complete {
+		t.Errorf("ProcessPsbt: packet is complete without signing")
+	}
+	prevTx := p.Inputs[0].NonWitnessUtxo
+	if prevTx == nil {
+		t.Fatalf("ProcessPsbt: the transaction spent by the input was not added")
+	}
+	if complete, err = w.ProcessPsbt(p, true, txscript.SigHashAll); err != nil {
+		t.Fatalf("ProcessPsbt: unexpected err %v", err)
+	}
+	if !complete {
+		t.Fatalf("ProcessPsbt: packet is not complete after signing")
+	}
+	tx, err := p.Extract()
+	if err != nil {
+		t.Fatalf("Extract: unexpected err %v", err)
+	}
+	prevTxOut := prevTx.TxOut[tx.TxIn[0].PreviousOutPoint.Index]
+	vm, err := txscript.NewEngine(prevTxOut.PkScript, tx, 0, txscript.StandardVerifyFlags, nil, nil,
+		prevTxOut.Value)
+	if err != nil {
+		t.Fatalf("NewEngine: unexpected err %v", err)
+	}
+	if err = vm.Execute(); err != nil {
+		t.Errorf("signed input does not verify: %v", err)
+	}
+	// An input asking for another sighash type is not signed with this one.
+	p = newPacket()
+	p.Inputs[0].SighashType = txscript.SigHashSingle
+	if _, err = w.ProcessPsbt(p, true, txscript.SigHashAll); err != ErrSighashMismatch {
+		t.Errorf("ProcessPsbt: got err %v, want %v", err, ErrSighashMismatch)
+	}
+	// A locked wallet can not sign.
+	w.Lock()
+	if !w.Locked() {
+		t.Fatalf("wallet was not locked")
+	}
+	if _, err = w.ProcessPsbt(newPacket(), true, txscript.SigHashAll); err == nil {
+		t.Errorf("ProcessPsbt: expected an error signing with a locked wallet")
+	}
+}
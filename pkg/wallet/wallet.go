@@ -43,6 +43,9 @@ const (
 	// walletDbWatchingOnlyName = "wowallet.db" recoveryBatchSize is the default number of blocks that will be scanned
 	// successively by the recovery manager, in the event that the wallet is started in recovery mode.
 	recoveryBatchSize = 2000
+	// defaultImportLookahead is the number of addresses looked ahead on each branch when scanning the chain for the used
+	// addresses of an account imported from an extended public key, when the wallet has no recovery window.
+	defaultImportLookahead = 250
 )
 
 // ErrNotSynced describes an error where an operation cannot complete due wallet being out of sync (and perhaps
@@ -69,6 +72,7 @@ type Wallet struct {
 	chainClientSyncMtx sync.Mutex
 	lockedOutpoints    map[wire.OutPoint]struct{}
 	recoveryWindow     uint32
+	lookahead          lookaheadWindow
 	// Channels for rescan processing. Requests are added and merged with any waiting requests, before being sent to
 	// another goroutine to call the rescan RPC.
 	rescanAddJob        chan *RescanJob
@@ -268,8 +272,8 @@ func (w *Wallet) SetChainSynced(synced bool) {
 	w.chainClientSyncMtx.Unlock()
 }
 
-// activeData returns the currently-active receiving addresses and all unspent outputs. This is primarily intended to
-// provide the parameters for a rescan request.
+// activeData returns the currently-active receiving addresses, including the lookahead windows of the watching-only
+// accounts, and all unspent outputs. This is primarily intended to provide the parameters for a rescan request.
 func (w *Wallet) activeData(dbtx walletdb.ReadTx) ([]util.Address, []wtxmgr.Credit, error) {
 	addrmgrNs := dbtx.ReadBucket(waddrmgrNamespaceKey)
 	txmgrNs := dbtx.ReadBucket(wtxmgrNamespaceKey)
@@ -284,6 +288,12 @@ func (w *Wallet) activeData(dbtx walletdb.ReadTx) ([]util.Address, []wtxmgr.Cred
 		Error(err)
 		return nil, nil, err
 	}
+	lookaheadAddrs, err := w.watchLookahead(addrmgrNs)
+	if err != nil {
+		Error(err)
+		return nil, nil, err
+	}
+	addrs = append(addrs, lookaheadAddrs...)
 	unspent, err := w.TxStore.UnspentOutputs(txmgrNs)
 	return addrs, unspent, err
 }
@@ -373,7 +383,7 @@ func (w *Wallet) syncWithChain() error {
 			)
 			// In the event that this recovery is being resumed, we will need to repopulate all found addresses from the
 			// database. For basic recovery, we will only do so for the default scopes.
-			scopedMgrs, err := w.defaultScopeManagers()
+			scopedMgrs, err := w.defaultScopeManagers(ns)
 			if err != nil {
 				Error(err)
 				return err
@@ -621,8 +631,10 @@ func (w *Wallet) syncWithChain() error {
 	return w.rescanWithTarget(addrs, unspent, birthdayStamp)
 }
 
-// defaultScopeManagers fetches the ScopedKeyManagers from the wallet using the default set of key scopes.
-func (w *Wallet) defaultScopeManagers() (
+// defaultScopeManagers fetches the ScopedKeyManagers from the wallet using the default set of key scopes. Scopes
+// without a default account, which a watching-only wallet has for the scopes no extended public key was imported into,
+// are left out as there is nothing to recover in them.
+func (w *Wallet) defaultScopeManagers(ns walletdb.ReadBucket) (
 	map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager, error,
 ) {
 	scopedMgrs := make(map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager)
@@ -632,6 +644,14 @@ func (w *Wallet) defaultScopeManagers() (
 			Error(err)
 			return nil, err
 		}
+		_, err = scopedMgr.AccountName(ns, waddrmgr.DefaultAccountNum)
+		if waddrmgr.IsError(err, waddrmgr.ErrAccountNotFound) {
+			continue
+		}
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
 		scopedMgrs[scope] = scopedMgr
 	}
 	return scopedMgrs, nil
@@ -648,13 +668,13 @@ func (w *Wallet) recoverDefaultScopes(
 	batch []wtxmgr.BlockMeta,
 	recoveryState *RecoveryState,
 ) error {
-	scopedMgrs, err := w.defaultScopeManagers()
+	scopedMgrs, err := w.defaultScopeManagers(ns)
 	if err != nil {
 		Error(err)
 		return err
 	}
 	return w.recoverScopedAddresses(
-		chainClient, tx, ns, batch, recoveryState, scopedMgrs,
+		chainClient, tx, ns, batch, recoveryState, scopedMgrs, waddrmgr.DefaultAccountNum,
 	)
 }
 
@@ -679,6 +699,7 @@ func (w *Wallet) recoverScopedAddresses(
 	batch []wtxmgr.BlockMeta,
 	recoveryState *RecoveryState,
 	scopedMgrs map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager,
+	account uint32,
 ) error {
 	// If there are no blocks in the batch, we are done.
 	if len(batch) == 0 {
@@ -691,7 +712,7 @@ func (w *Wallet) recoverScopedAddresses(
 expandHorizons:
 	for scope, scopedMgr := range scopedMgrs {
 		scopeState := recoveryState.StateForScope(scope)
-		err := expandScopeHorizons(ns, scopedMgr, scopeState)
+		err := expandScopeHorizons(ns, scopedMgr, account, scopeState)
 		if err != nil {
 			Error(err)
 			return err
@@ -721,7 +742,7 @@ expandHorizons:
 	// Report any external or internal addresses found as a result of the appropriate branch recovery state. Adding
 	// indexes above the last-found index of either will result in the horizons being expanded upon the next iteration.
 	// Any found addresses are also marked used using the scoped key manager.
-	err = extendFoundAddresses(ns, filterResp, scopedMgrs, recoveryState)
+	err = extendFoundAddresses(ns, filterResp, scopedMgrs, account, recoveryState)
 	if err != nil {
 		Error(err)
 		return err
@@ -763,6 +784,7 @@ expandHorizons:
 func expandScopeHorizons(
 	ns walletdb.ReadWriteBucket,
 	scopedMgr *waddrmgr.ScopedKeyManager,
+	account uint32,
 	scopeState *ScopeRecoveryState,
 ) error {
 	// Compute the current external horizon and the number of addresses we must derive to ensure we maintain a
@@ -770,7 +792,7 @@ func expandScopeHorizons(
 	exHorizon, exWindow := scopeState.ExternalBranch.ExtendHorizon()
 	count, childIndex := uint32(0), exHorizon
 	for count < exWindow {
-		keyPath := externalKeyPath(childIndex)
+		keyPath := externalKeyPath(account, childIndex)
 		addr, err := scopedMgr.DeriveFromKeyPath(ns, keyPath)
 		switch {
 		case err == hdkeychain.ErrInvalidChild:
@@ -792,7 +814,7 @@ func expandScopeHorizons(
 	inHorizon, inWindow := scopeState.InternalBranch.ExtendHorizon()
 	count, childIndex = 0, inHorizon
 	for count < inWindow {
-		keyPath := internalKeyPath(childIndex)
+		keyPath := internalKeyPath(account, childIndex)
 		addr, err := scopedMgr.DeriveFromKeyPath(ns, keyPath)
 		switch {
 		case err == hdkeychain.ErrInvalidChild:
@@ -812,19 +834,19 @@ func expandScopeHorizons(
 	return nil
 }
 
-// externalKeyPath returns the relative external derivation path /0/0/index.
-func externalKeyPath(index uint32) waddrmgr.DerivationPath {
+// externalKeyPath returns the relative external derivation path /account/0/index.
+func externalKeyPath(account, index uint32) waddrmgr.DerivationPath {
 	return waddrmgr.DerivationPath{
-		Account: waddrmgr.DefaultAccountNum,
+		Account: account,
 		Branch:  waddrmgr.ExternalBranch,
 		Index:   index,
 	}
 }
 
-// internalKeyPath returns the relative internal derivation path /0/1/index.
-func internalKeyPath(index uint32) waddrmgr.DerivationPath {
+// internalKeyPath returns the relative internal derivation path /account/1/index.
+func internalKeyPath(account, index uint32) waddrmgr.DerivationPath {
 	return waddrmgr.DerivationPath{
-		Account: waddrmgr.DefaultAccountNum,
+		Account: account,
 		Branch:  waddrmgr.InternalBranch,
 		Index:   index,
 	}
@@ -871,6 +893,7 @@ func extendFoundAddresses(
 	ns walletdb.ReadWriteBucket,
 	filterResp *chain.FilterBlocksResponse,
 	scopedMgrs map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager,
+	account uint32,
 	recoveryState *RecoveryState,
 ) error {
 	// Mark all recovered external addresses as used. This will be done only for scopes that reported a non-zero number
@@ -891,7 +914,7 @@ func extendFoundAddresses(
 			exLastFound--
 		}
 		err := scopedMgr.ExtendExternalAddresses(
-			ns, waddrmgr.DefaultAccountNum, exLastFound,
+			ns, account, exLastFound,
 		)
 		if err != nil {
 			Error(err)
@@ -926,7 +949,7 @@ func extendFoundAddresses(
 			inLastFound--
 		}
 		err := scopedMgr.ExtendInternalAddresses(
-			ns, waddrmgr.DefaultAccountNum, inLastFound,
+			ns, account, inLastFound,
 		)
 		if err != nil {
 			Error(err)
@@ -985,11 +1008,12 @@ func logFilterBlocksResp(
 
 type (
 	createTxRequest struct {
-		account     uint32
-		outputs     []*wire.TxOut
-		minconf     int32
-		feeSatPerKB util.Amount
-		resp        chan createTxResponse
+		account      uint32
+		outputs      []*wire.TxOut
+		minconf      int32
+		feeSatPerKB  util.Amount
+		coinSelector CoinSelector
+		resp         chan createTxResponse
 	}
 	createTxResponse struct {
 		tx  *txauthor.AuthoredTx
@@ -1015,9 +1039,13 @@ out:
 				txr.resp <- createTxResponse{nil, err}
 				continue
 			}
+			coinSelector := txr.coinSelector
+			if coinSelector == nil {
+				coinSelector = w.defaultCoinSelector()
+			}
 			tx, err := w.txToOutputs(
 				txr.outputs, txr.account,
-				txr.minconf, txr.feeSatPerKB,
+				txr.minconf, txr.feeSatPerKB, coinSelector,
 			)
 			heldUnlock.release()
 			txr.resp <- createTxResponse{tx, err}
@@ -1028,20 +1056,47 @@ out:
 	w.wg.Done()
 }
 
+// defaultCoinSelector returns the coin selector of the strategy named in the wallet configuration, or of
+// DefaultCoinSelection if it names none. The wallet refuses to start with a name that is not known, but the
+// configuration can be changed while it runs, so such a name still falls back to DefaultCoinSelection.
+func (w *Wallet) defaultCoinSelector() CoinSelector {
+	var name string
+	if w.PodConfig != nil && w.PodConfig.WalletCoinSelection != nil {
+		name = *w.PodConfig.WalletCoinSelection
+	}
+	coinSelector, err := CoinSelectorByName(name)
+	if err != nil {
+		Warn(err, "- using", DefaultCoinSelection, "coin selection")
+		coinSelector, _ = CoinSelectorByName(DefaultCoinSelection)
+	}
+	return coinSelector
+}
+
+// InputSequence returns the sequence number of the inputs of the transactions the wallet creates, which signals that
+// they may be replaced (BIP125) unless replaceability is turned off in the wallet configuration.
+func (w *Wallet) InputSequence() uint32 {
+	if w.PodConfig != nil && w.PodConfig.WalletRBF != nil && !*w.PodConfig.WalletRBF {
+		return wire.MaxTxInSequenceNum
+	}
+	return txrules.MaxRBFSequence
+}
+
 // CreateSimpleTx creates a new signed transaction spending unspent P2PKH outputs with at least minconf confirmations
-// spending to any number of address/amount pairs. Change and an appropriate transaction fee are automatically included,
-// if necessary. All transaction creation through this function is serialized to prevent the creation of many
+// spending to any number of address/amount pairs. The outputs spent are chosen by coinSelector, or by the coin selector
+// of the wallet configuration if it is nil. Change and an appropriate transaction fee are automatically included, if
+// necessary. All transaction creation through this function is serialized to prevent the creation of many
 // transactions which spend the same outputs.
 func (w *Wallet) CreateSimpleTx(
 	account uint32, outputs []*wire.TxOut,
-	minconf int32, satPerKb util.Amount,
+	minconf int32, satPerKb util.Amount, coinSelector CoinSelector,
 ) (*txauthor.AuthoredTx, error) {
 	req := createTxRequest{
-		account:     account,
-		outputs:     outputs,
-		minconf:     minconf,
-		feeSatPerKB: satPerKb,
-		resp:        make(chan createTxResponse),
+		account:      account,
+		outputs:      outputs,
+		minconf:      minconf,
+		feeSatPerKB:  satPerKb,
+		coinSelector: coinSelector,
+		resp:         make(chan createTxResponse),
 	}
 	w.createTxRequests <- req
 	resp := <-req.resp
@@ -1541,6 +1596,20 @@ func (w *Wallet) AccountNumber(scope waddrmgr.KeyScope, accountName string) (uin
 	return account, err
 }
 
+// LookupAccount returns the key scope and number of the account with the passed name in any key scope, as accounts
+// imported from extended public keys and the default account of a watching-only wallet need not be in BIP0044.
+func (w *Wallet) LookupAccount(accountName string) (scope waddrmgr.KeyScope, account uint32, err error) {
+	err = walletdb.View(
+		w.db, func(tx walletdb.ReadTx) error {
+			addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+			var err error
+			scope, account, err = w.Manager.LookupAccount(addrmgrNs, accountName)
+			return err
+		},
+	)
+	return
+}
+
 // AccountName returns the name of an account.
 func (w *Wallet) AccountName(scope waddrmgr.KeyScope, accountNumber uint32) (string, error) {
 	manager, err := w.Manager.FetchScopedKeyManager(scope)
@@ -1606,6 +1675,55 @@ func (w *Wallet) RenameAccount(scope waddrmgr.KeyScope, account uint32, newName
 	return err
 }
 
+// SetTxComment records a memo for the transaction with the given hash, replacing any earlier one. An empty comment
+// removes it.
+func (w *Wallet) SetTxComment(txHash *chainhash.Hash, comment *wtxmgr.TxComment) error {
+	return walletdb.Update(
+		w.db, func(tx walletdb.ReadWriteTx) error {
+			txmgrNs := tx.ReadWriteBucket(wtxmgrNamespaceKey)
+			return w.TxStore.PutTxComment(txmgrNs, txHash, comment)
+		},
+	)
+}
+
+// TxComment returns the memo recorded for the transaction with the given hash, which is empty if there is none.
+func (w *Wallet) TxComment(txHash *chainhash.Hash) (wtxmgr.TxComment, error) {
+	var comment wtxmgr.TxComment
+	err := walletdb.View(
+		w.db, func(tx walletdb.ReadTx) error {
+			txmgrNs := tx.ReadBucket(wtxmgrNamespaceKey)
+			var err error
+			comment, err = w.TxStore.TxComment(txmgrNs, txHash)
+			return err
+		},
+	)
+	return comment, err
+}
+
+// SetAddressLabel sets the label of a wallet address. An empty label removes it.
+func (w *Wallet) SetAddressLabel(a util.Address, label string) error {
+	return walletdb.Update(
+		w.db, func(tx walletdb.ReadWriteTx) error {
+			addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			return w.Manager.SetAddrLabel(addrmgrNs, a, label)
+		},
+	)
+}
+
+// AddressLabel returns the label of a wallet address, which is empty if the address has none.
+func (w *Wallet) AddressLabel(a util.Address) (string, error) {
+	var label string
+	err := walletdb.View(
+		w.db, func(tx walletdb.ReadTx) error {
+			addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+			var err error
+			label, err = w.Manager.AddrLabel(addrmgrNs, a)
+			return err
+		},
+	)
+	return label, err
+}
+
 // const maxEmptyAccounts = 100
 
 // NextAccount creates the next account and returns its account number. The name must be unique to the account. In order
@@ -1645,6 +1763,210 @@ func (w *Wallet) NextAccount(scope waddrmgr.KeyScope, name string) (uint32, erro
 	return account, err
 }
 
+// ImportAccount adds an account to the wallet from the extended public key of a BIP0044-like account of the passed key
+// scope, which is the key at m/purpose'/cointype'/account'. The wallet can generate addresses and track the balance of
+// the account, but holds no private keys for it, so spends from it are created unsigned, for example as a PSBT, and
+// signed elsewhere. The account becomes the default account of the scope if it has none, as in a wallet created
+// watching-only.
+//
+// As the history of the account is unknown, the wallet watches a window of addresses past the last issued address on
+// both branches, and issues the addresses of a branch up to one a payment is seen to. If rescan is set the chain is also
+// scanned in the background from the passed block, or the genesis block if it is nil, for the used addresses of the
+// account as in a recovery, and then rescanned for them, so the returned properties do not reflect the addresses found.
+func (w *Wallet) ImportAccount(
+	scope waddrmgr.KeyScope, name string, accountPubKey *hdkeychain.ExtendedKey,
+	bs *waddrmgr.BlockStamp, rescan bool,
+) (*waddrmgr.AccountProperties, error) {
+	manager, err := w.Manager.FetchScopedKeyManager(scope)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if bs == nil {
+		bs = &waddrmgr.BlockStamp{
+			Hash:   *w.chainParams.GenesisHash,
+			Height: 0,
+		}
+	}
+	var chainClient chain.Interface
+	if rescan {
+		if chainClient, err = w.requireChainClient(); err != nil {
+			Error(err)
+			return nil, err
+		}
+	}
+	var account uint32
+	err = walletdb.Update(
+		w.db, func(tx walletdb.ReadWriteTx) error {
+			addrmgrNs := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+			var err error
+			account, err = manager.NewAccountWatchingOnly(addrmgrNs, name, accountPubKey)
+			return err
+		},
+	)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	if rescan {
+		// The scan makes two requests of the chain server for every block, so it is not waited for.
+		w.wg.Add(1)
+		go w.recoverImportedAccount(chainClient, manager, account, bs)
+	} else {
+		var addrs []util.Address
+		err = walletdb.View(
+			w.db, func(tx walletdb.ReadTx) error {
+				addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+				var err error
+				addrs, err = w.watchAccountLookahead(addrmgrNs, manager, account)
+				return err
+			},
+		)
+		if err != nil {
+			Error(err)
+			return nil, err
+		}
+		if chainClient := w.ChainClient(); chainClient != nil {
+			if err = chainClient.NotifyReceived(addrs); err != nil {
+				Error(err)
+				return nil, err
+			}
+		}
+	}
+	props, err := w.AccountProperties(scope, account)
+	if err != nil {
+		Error(err)
+		return nil, err
+	}
+	Infof("imported watching-only account %q in scope %s", name, scope.String())
+	w.NtfnServer.notifyAccountProperties(props)
+	return props, nil
+}
+
+// recoverImportedAccount scans the chain from the passed block for the used addresses of an imported account, then
+// rescans the chain for them and the lookahead window past them. It runs in the background and logs its outcome.
+func (w *Wallet) recoverImportedAccount(
+	chainClient chain.Interface, manager *waddrmgr.ScopedKeyManager, account uint32, bs *waddrmgr.BlockStamp,
+) {
+	defer w.wg.Done()
+	scope := manager.Scope()
+	Infof(
+		"scanning from height %d for the used addresses of imported account %d in scope %s",
+		bs.Height, account, scope.String(),
+	)
+	if err := w.recoverAccount(chainClient, manager, account, bs); err != nil {
+		Error(err)
+		Error("cannot recover the addresses of the imported account:", err)
+		return
+	}
+	var (
+		props *waddrmgr.AccountProperties
+		addrs []util.Address
+	)
+	err := walletdb.View(
+		w.db, func(tx walletdb.ReadTx) error {
+			addrmgrNs := tx.ReadBucket(waddrmgrNamespaceKey)
+			err := manager.ForEachAccountAddress(
+				addrmgrNs, account, func(maddr waddrmgr.ManagedAddress) error {
+					addrs = append(addrs, maddr.Address())
+					return nil
+				},
+			)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			lookaheadAddrs, err := w.watchAccountLookahead(addrmgrNs, manager, account)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			addrs = append(addrs, lookaheadAddrs...)
+			props, err = manager.AccountProperties(addrmgrNs, account)
+			return err
+		},
+	)
+	if err != nil {
+		Error(err)
+		return
+	}
+	w.NtfnServer.notifyAccountProperties(props)
+	// As with imported private keys the rescan is not waited for, its outcome is logged by the rescan handlers.
+	job := &RescanJob{
+		Addrs:      addrs,
+		OutPoints:  nil,
+		BlockStamp: *bs,
+		err:        make(chan error, 1),
+	}
+	select {
+	case w.rescanAddJob <- job:
+	case <-w.quitChan():
+	}
+}
+
+// recoverAccount scans the blocks from the passed one to the block the wallet is synced to for the used addresses of an
+// account, in batches as the recovery of the default accounts does, and extends the branches of the account to the
+// last of them found. It stops when the wallet shuts down.
+func (w *Wallet) recoverAccount(
+	chainClient chain.Interface, manager *waddrmgr.ScopedKeyManager, account uint32, bs *waddrmgr.BlockStamp,
+) error {
+	recoveryState := NewRecoveryState(w.importLookahead())
+	scopedMgrs := map[waddrmgr.KeyScope]*waddrmgr.ScopedKeyManager{manager.Scope(): manager}
+	syncedTo := w.Manager.SyncedTo()
+	for start := bs.Height; start <= syncedTo.Height; start += recoveryBatchSize {
+		if w.ShuttingDown() {
+			return errors.New("wallet is shutting down")
+		}
+		batch := make([]wtxmgr.BlockMeta, 0, recoveryBatchSize)
+		for height := start; height <= syncedTo.Height && height < start+recoveryBatchSize; height++ {
+			hash, err := chainClient.GetBlockHash(int64(height))
+			if err != nil {
+				Error(err)
+				return err
+			}
+			header, err := chainClient.GetBlockHeader(hash)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			batch = append(
+				batch, wtxmgr.BlockMeta{
+					Block: wtxmgr.Block{
+						Hash:   *hash,
+						Height: height,
+					},
+					Time: header.Timestamp,
+				},
+			)
+		}
+		err := walletdb.Update(
+			w.db, func(tx walletdb.ReadWriteTx) error {
+				ns := tx.ReadWriteBucket(waddrmgrNamespaceKey)
+				return w.recoverScopedAddresses(chainClient, tx, ns, batch, recoveryState, scopedMgrs, account)
+			},
+		)
+		if err != nil {
+			Error(err)
+			return err
+		}
+	}
+	return nil
+}
+
+// addressWatchOnly returns whether the wallet has no private key for an address of it, because the wallet is
+// watching-only or the address belongs to an account imported from an extended public key.
+func (w *Wallet) addressWatchOnly(addrmgrNs walletdb.ReadBucket, addr util.Address) bool {
+	if w.Manager.WatchOnly() {
+		return true
+	}
+	smgr, account, err := w.Manager.AddrAccount(addrmgrNs, addr)
+	if err != nil || account == waddrmgr.ImportedAddrAccount {
+		return false
+	}
+	props, err := smgr.AccountProperties(addrmgrNs, account)
+	return err == nil && props.IsWatchOnly
+}
+
 // CreditCategory describes the type of wallet transaction output. The category of "sent transactions" (debits) is
 // always "send", and is not expressed by this type.
 //
@@ -1747,6 +2069,7 @@ outputs:
 		}
 		var address string
 		var accountName string
+		var label string
 		_, addrs, _, _ := txscript.ExtractPkScriptAddrs(output.PkScript, net)
 		if len(addrs) == 1 {
 			addr := addrs[0]
@@ -1758,6 +2081,11 @@ outputs:
 					Error(err)
 					accountName = ""
 				}
+				label, err = mgr.AddrLabel(addrmgrNs, addrs[0])
+				if err != nil {
+					Error(err)
+					label = ""
+				}
 			}
 		}
 		amountF64 := util.Amount(output.Value).ToDUO()
@@ -1783,6 +2111,9 @@ outputs:
 			WalletConflicts: []string{},
 			Time:            received,
 			TimeReceived:    received,
+			Comment:         details.Comment.Comment,
+			To:              details.Comment.CommentTo,
+			Label:           label,
 		}
 		// Add a received/generated/immature result if this is a credit. If the output was spent, create a second result
 		// under the send category with the inverse of the output amount. It is therefore possible that a single output
@@ -2374,13 +2705,11 @@ func (w *Wallet) ListUnspent(
 					continue
 				}
 			include:
-				// At the moment watch-only addresses are not supported, so all recorded outputs that are not multisig are
-				// "spendable". Multisig outputs are only "spendable" if all keys are controlled by this wallet.
+				// Outputs paying to a single address are "spendable" unless the wallet holds no private key for the
+				// address. Multisig outputs are only "spendable" if all keys are controlled by this wallet.
 				//
-				// TODO: Each case will need updates when watch-only addrs is added. For P2PK, P2PKH, and P2SH, the address
-				//  must be looked up and not be watching-only. For multisig, all pubkeys must belong to the manager with the
-				//  associated private key (currently it only checks whether the pubkey exists, since the private key is
-				//  required at the moment).
+				// TODO: For multisig, all pubkeys must belong to the manager with the associated private key (currently it
+				//  only checks whether the pubkey exists, since the private key is required at the moment).
 				var spendable bool
 			scSwitch:
 				switch sc {
@@ -2405,6 +2734,9 @@ func (w *Wallet) ListUnspent(
 					}
 					spendable = true
 				}
+				if spendable && sc != txscript.MultiSigTy && len(addrs) > 0 && w.addressWatchOnly(addrmgrNs, addrs[0]) {
+					spendable = false
+				}
 				result := &btcjson.ListUnspentResult{
 					TxID:          output.OutPoint.Hash.String(),
 					Vout:          output.OutPoint.Index,
@@ -2982,10 +3314,11 @@ func (w *Wallet) TotalReceivedForAddr(addr util.Address, minConf int32) (util.Am
 	return amount, err
 }
 
-// SendOutputs creates and sends payment transactions. It returns the transaction hash upon success.
+// SendOutputs creates and sends payment transactions, spending the outputs chosen by coinSelector, or by the coin
+// selector of the wallet configuration if it is nil. It returns the transaction hash upon success.
 func (w *Wallet) SendOutputs(
 	outputs []*wire.TxOut, account uint32,
-	minconf int32, satPerKb util.Amount,
+	minconf int32, satPerKb util.Amount, coinSelector CoinSelector,
 ) (*chainhash.Hash, error) {
 	// Ensure the outputs to be created adhere to the network's consensus rules.
 	for _, output := range outputs {
@@ -2995,7 +3328,7 @@ func (w *Wallet) SendOutputs(
 	}
 	// Create the transaction and broadcast it to the network. The transaction will be added to the database in order to
 	// ensure that we continue to re-broadcast the transaction upon restarts until it has been confirmed.
-	createdTx, err := w.CreateSimpleTx(account, outputs, minconf, satPerKb)
+	createdTx, err := w.CreateSimpleTx(account, outputs, minconf, satPerKb, coinSelector)
 	if err != nil {
 		Error(err)
 		return nil, err
@@ -3286,6 +3619,57 @@ func Create(
 	)
 }
 
+// CreateWatchingOnly creates a new watching-only wallet, writing it to an empty database. Instead of a seed the wallet
+// is created from the extended public key of an account (xpub, ypub or zpub), which becomes the default account of the
+// key scope the key's version denotes. The wallet never holds private keys; transactions it creates have to be signed
+// elsewhere.
+func CreateWatchingOnly(
+	db walletdb.DB, pubPass []byte, accountPubKey string, params *netparams.Params,
+	birthday time.Time,
+) error {
+	acctKey, scope, err := waddrmgr.ParseAccountPubKey(accountPubKey, params)
+	if err != nil {
+		Error(err)
+		return err
+	}
+	return walletdb.Update(
+		db, func(tx walletdb.ReadWriteTx) error {
+			addrmgrNs, err := tx.CreateTopLevelBucket(waddrmgrNamespaceKey)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			txmgrNs, err := tx.CreateTopLevelBucket(wtxmgrNamespaceKey)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			err = waddrmgr.CreateWatchingOnly(addrmgrNs, pubPass, params, nil, birthday)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			mgr, err := waddrmgr.Open(addrmgrNs, pubPass, params)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			defer mgr.Close()
+			scopedMgr, err := mgr.FetchScopedKeyManager(scope)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			_, err = scopedMgr.NewAccountWatchingOnly(addrmgrNs, "default", acctKey)
+			if err != nil {
+				Error(err)
+				return err
+			}
+			return wtxmgr.Create(txmgrNs)
+		},
+	)
+}
+
 // Open loads an already-created wallet from the passed database and namespaces.
 func Open(
 	db walletdb.DB,
@@ -3378,6 +3762,8 @@ func Open(
 		quit:                quit,
 	}
 	w.NtfnServer = newNotificationServer(w)
+	w.lookahead.addrs = make(map[string]lookaheadAddr)
+	w.lookahead.horizons = make(map[lookaheadBranch]uint32)
 	w.TxStore.NotifyUnspent = func(hash *chainhash.Hash, index uint32) {
 		w.NtfnServer.notifyUnspentOutput(0, hash, index)
 	}